@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+func TestFreshnessAlerts(t *testing.T) {
+	ta := newTestApp(t)
+	ctx := context.Background()
+	user := ta.token(userEmail)
+
+	run := func() models.FreshnessAlertResult {
+		t.Helper()
+		rec := ta.do(http.MethodPost, "/v1/admin/jobs/freshness-alerts", ta.token(adminEmail), nil)
+		expect(t, rec, http.StatusOK, "")
+		var result models.FreshnessAlertResult
+		decode(t, rec, &result)
+		return result
+	}
+	stale := time.Now().UTC().AddDate(-2, 0, 0)
+	if err := ta.store.Locations().Update(ctx, ta.location.ID, repositories.Fields{"last_confirmed_at": stale}); err != nil {
+		t.Fatal(err)
+	}
+	// Tanpa berlangganan tidak ada email
+	if result := run(); result.Users != 0 || result.Emails != 0 {
+		t.Fatalf("tanpa langganan: %+v", result)
+	}
+
+	expect(t, ta.do(http.MethodPut, "/v1/me/preferences", user, map[string]interface{}{"alerts": map[string]float64{"freshness_below": 1.5}}),
+		http.StatusBadRequest, "VALIDATION_FAILED")
+	expect(t, ta.do(http.MethodPut, "/v1/me/preferences", user, map[string]interface{}{"alerts": map[string]float64{"freshness_below": 0.5}}),
+		http.StatusOK, "")
+	// Lokasi fakedata lain milik user bisa saja sudah basi
+	if result := run(); result.Users != 1 || result.Emails != 1 || result.Locations < 1 {
+		t.Fatalf("peringatan pertama: %+v", result)
+	}
+	// Sekali per lokasi sampai dikonfirmasi lagi
+	if result := run(); result.Emails != 0 {
+		t.Fatalf("peringatan berulang: %+v", result)
+	}
+	// Diperingatkan, dikonfirmasi sejam kemudian, lalu lama tidak disentuh
+	if err := ta.store.Locations().Update(ctx, ta.location.ID, repositories.Fields{"freshness_alerted_at": stale, "last_confirmed_at": stale.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if result := run(); result.Locations != 1 {
+		t.Fatalf("setelah dikonfirmasi lalu basi lagi: %+v", result)
+	}
+
+	// Berhenti berlangganan
+	expect(t, ta.do(http.MethodPut, "/v1/me/preferences", user, map[string]string{"units": "metric"}), http.StatusOK, "")
+	if result := run(); result.Users != 0 {
+		t.Fatalf("setelah berhenti: %+v", result)
+	}
+}
@@ -4,259 +4,470 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
-	"os"
 	"sync"
+	"time"
+
+	"InfoCuy-Backend/internal/auth"
+	"InfoCuy-Backend/internal/chaos"
+	"InfoCuy-Backend/internal/config"
+	"InfoCuy-Backend/internal/database"
+	"InfoCuy-Backend/internal/deprecation"
+	"InfoCuy-Backend/internal/doctor"
+	"InfoCuy-Backend/internal/elevation"
+	"InfoCuy-Backend/internal/fieldpolicy"
+	"InfoCuy-Backend/internal/geocode"
+	"InfoCuy-Backend/internal/handlers"
+	"InfoCuy-Backend/internal/jobs"
+	"InfoCuy-Backend/internal/logging"
+	"InfoCuy-Backend/internal/mailer"
+	"InfoCuy-Backend/internal/mapmatch"
+	"InfoCuy-Backend/internal/objectstore"
+	"InfoCuy-Backend/internal/osm"
+	"InfoCuy-Backend/internal/ratelimit"
+	"InfoCuy-Backend/internal/repositories"
+	"InfoCuy-Backend/internal/services"
+	"InfoCuy-Backend/internal/shadow"
+	"InfoCuy-Backend/internal/siem"
+	"InfoCuy-Backend/internal/weather"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
-// --- SEMUA STRUCT DATA ---
-type Coordinates struct {
-	Lat float64 `json:"lat" bson:"lat"`
-	Lng float64 `json:"lng" bson:"lng"`
-}
-type Location struct {
-	ID          primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
-	Name        string             `json:"name" bson:"name"`
-	Category    string             `json:"category" bson:"category"`
-	Coordinates Coordinates        `json:"coordinates" bson:"coordinates"`
-	Address     string             `json:"address" bson:"address"`
-	CreatedBy   string             `json:"created_by" bson:"created_by"`
-}
-type User struct {
-	ID       primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
-	Email    string             `json:"email" bson:"email"`
-	Password string             `json:"password" bson:"password"`
-	Role     string             `json:"role" bson:"role"`
-}
-type AuthInput struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
-}
-type RoleInput struct {
-	Role string `json:"role"`
+// Aplikasi untuk entry point Vercel, dirakit sekali per instance
+var (
+	vercelApp *App
+	once      sync.Once
+)
+
+// App adalah aplikasi yang sudah dirakit dari config.
+type App struct {
+	Router  *gin.Engine
+	db      *mongo.Database
+	streams *services.LocationStream
+	// Target reload config (lihat Reload)
+	handler  *handlers.Handler
+	auth     *services.AuthService
+	runtime  *runtimeState
+	reloadMu sync.Mutex
 }
 
-// Global Variables
-var (
-	app           *gin.Engine
-	geoCollection *mongo.Collection
-	userCollection *mongo.Collection
-	once          sync.Once // Agar init hanya jalan sekali
+// Batas waktu koneksi awal dan migrasi/index saat startup
+const (
+	connectTimeout = 15 * time.Second
+	startupTimeout = 60 * time.Second
 )
 
+// Repositories adalah semua akses data aplikasi. New memakai implementasi
+// MongoDB (MongoRepositories); test integrasi memasang implementasi
+// in-memory lewat Build.
+type Repositories struct {
+	Locations      repositories.LocationRepository
+	Users          repositories.UserRepository
+	PasswordResets repositories.PasswordResetRepository
+	Settings       repositories.SettingsRepository
+	Roles          repositories.RoleRepository
+	Categories     repositories.CategoryRepository
+	Audit          repositories.AuditRepository
+	Transit        repositories.TransitRepository
+	Regions        repositories.RegionRepository
+	Postcodes      repositories.PostcodeRepository
+	Reviews        repositories.ReviewRepository
+	// Konfirmasi "data masih akurat", kedaluwarsa otomatis (TTL)
+	Confirmations repositories.ConfirmationRepository
+	// Email campaign dan antrean penerimanya
+	Campaigns repositories.CampaignRepository
+	// Read model daftar lokasi publik, diproyeksikan dari geo_data
+	MapView repositories.MapViewRepository
+	// Lokasi yang disimpan user
+	Favorites repositories.FavoriteRepository
+	// Job pemindahan pemilik lokasi
+	Reassignments repositories.ReassignmentRepository
+	// Job geocoding baris import tanpa koordinat
+	ImportJobs repositories.ImportJobRepository
+	// Kehadiran di lokasi acara lewat QR
+	CheckIns repositories.CheckInRepository
+	// Impression lokasi pinned/sponsored per hari
+	Promotions repositories.PromotionRepository
+	// Jadwal export lokasi berkala
+	Exports repositories.ScheduledExportRepository
+	// Email transaksional yang gagal dikirim provider
+	DeadLetters repositories.DeadLetterRepository
+	// Usulan kategori baru dari user
+	CategorySuggestions repositories.CategorySuggestionRepository
+	// Jumlah request API per consumer, endpoint dan tanggal
+	Usage repositories.UsageRepository
+	// Log domain event ber-seq dan offset consumer-nya
+	Events repositories.EventRepository
+	// Riwayat login dan sidik jari perangkatnya
+	Sessions repositories.SessionRepository
+	// Redirect ID lokasi lama (mis. hasil merge) ke lokasi yang berlaku
+	Redirects repositories.RedirectRepository
+}
+
+// MongoRepositories memetakan setiap repository ke collection-nya di db.
+func MongoRepositories(db *mongo.Database) Repositories {
+	locations := db.Collection("geo_data")
+	reviews := db.Collection("reviews")
+	favorites := db.Collection("favorites")
+	checkins := db.Collection("checkins")
+	return Repositories{
+		// Lokasi lama dan tutup permanen dipindah ke geo_data_archive (cold storage)
+		Locations:           repositories.NewLocationRepository(locations, db.Collection("geo_data_archive"), reviews, favorites, checkins),
+		Users:               repositories.NewUserRepository(db.Collection("user")),
+		PasswordResets:      repositories.NewPasswordResetRepository(db.Collection("password_resets")),
+		Settings:            repositories.NewSettingsRepository(db.Collection("settings")),
+		Roles:               repositories.NewRoleRepository(db.Collection("roles")),
+		Categories:          repositories.NewCategoryRepository(db.Collection("categories")),
+		Audit:               repositories.NewAuditRepository(db.Collection("audit_logs"), db.Collection("audit_archives")),
+		Transit:             repositories.NewTransitRepository(db.Collection("transit_stops")),
+		Regions:             repositories.NewRegionRepository(db.Collection("regions")),
+		Postcodes:           repositories.NewPostcodeRepository(db.Collection("postcodes")),
+		Reviews:             repositories.NewReviewRepository(reviews),
+		Confirmations:       repositories.NewConfirmationRepository(db.Collection("location_confirmations")),
+		Campaigns:           repositories.NewCampaignRepository(db.Collection("campaigns"), db.Collection("campaign_recipients")),
+		MapView:             repositories.NewMapViewRepository(db.Collection("map_view"), locations, reviews),
+		Favorites:           repositories.NewFavoriteRepository(favorites, locations, reviews),
+		Reassignments:       repositories.NewReassignmentRepository(db.Collection("reassignments")),
+		ImportJobs:          repositories.NewImportJobRepository(db.Collection("import_jobs")),
+		CheckIns:            repositories.NewCheckInRepository(checkins),
+		Promotions:          repositories.NewPromotionRepository(db.Collection("promotion_impressions")),
+		Exports:             repositories.NewScheduledExportRepository(db.Collection("scheduled_exports")),
+		DeadLetters:         repositories.NewDeadLetterRepository(db.Collection("mail_dead_letters")),
+		Usage:               repositories.NewUsageRepository(db.Collection("api_usage")),
+		Events:              repositories.NewEventRepository(db.Collection("events"), db.Collection("event_offsets")),
+		Sessions:            repositories.NewSessionRepository(db.Collection("sessions")),
+		Redirects:           repositories.NewRedirectRepository(db.Collection("location_redirects")),
+		CategorySuggestions: repositories.NewCategorySuggestionRepository(db.Collection("category_suggestions")),
+	}
+}
+
 // --- KONEKSI DB ---
-func connectDB() {
-	mongoURI := os.Getenv("MONGO_URI")
-	if mongoURI == "" {
-		log.Println("Warning: MONGO_URI is missing")
-		return
-	}
-	clientOptions := options.Client().ApplyURI(mongoURI)
-	client, err := mongo.Connect(context.TODO(), clientOptions)
+func connectDB(cfg config.Mongo, state *database.State) (*mongo.Database, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+	db, err := database.Connect(ctx, database.Options{
+		URI:         cfg.URI,
+		Database:    cfg.Database,
+		MaxPoolSize: cfg.MaxPoolSize,
+		MinPoolSize: cfg.MinPoolSize,
+		MaxConnIdle: cfg.MaxConnIdle,
+		OpTimeout:   cfg.OpTimeout,
+		SlowOp:      cfg.SlowOp,
+		State:       state,
+	})
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	err = client.Ping(context.TODO(), nil)
+	slog.Info("connected to MongoDB", "database", db.Name())
+	return db, nil
+}
+
+// New menghubungkan MongoDB, menyiapkan index lalu merakit aplikasi.
+// Error hanya jika MongoDB tidak bisa dihubungi.
+func New(cfg *config.Config) (*App, error) {
+	// Log terstruktur; package log standar ikut diteruskan ke slog
+	logging.Setup(cfg.LogLevel, cfg.LogFormat)
+	// Gangguan buatan untuk uji ketahanan, hanya jika CHAOS_ENABLED=true
+	chaos.Setup()
+	state := database.NewState()
+	db, err := connectDB(cfg.Mongo, state)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("koneksi MongoDB: %w", err)
 	}
-	fmt.Println("✅ Connected to MongoDB!")
-	geoCollection = client.Database("geo_db").Collection("geo_data")
-	userCollection = client.Database("geo_db").Collection("user")
+	repos := MongoRepositories(db)
+	ensureIndexes(repos)
+	return build(cfg, repos, db, state), nil
 }
 
-// --- SETUP ROUTER (EXPORTED agar bisa dipanggil main.go) ---
-func SetupRouter() *gin.Engine {
-	// Gunakan sync.Once agar DB tidak connect berkali-kali saat di Vercel
-	once.Do(func() {
-		connectDB()
-		r := gin.New()
-		r.Use(gin.Logger())
-		r.Use(gin.Recovery())
-
-		config := cors.DefaultConfig()
-		config.AllowAllOrigins = true
-		config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "X-User-Email"}
-		r.Use(cors.New(config))
-
-		// === DEFINISI ROUTES ===
-		
-		// 1. REGISTER
-		r.POST("/register", func(c *gin.Context) {
-			var input AuthInput
-			if err := c.ShouldBindJSON(&input); err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-				return
-			}
-			var existingUser User
-			userCollection.FindOne(context.TODO(), bson.M{"email": input.Email}).Decode(&existingUser)
-			if existingUser.Email != "" {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Email sudah terdaftar!"})
-				return
-			}
-			newUser := User{ID: primitive.NewObjectID(), Email: input.Email, Password: input.Password, Role: "user"}
-			userCollection.InsertOne(context.TODO(), newUser)
-			c.JSON(http.StatusCreated, gin.H{"message": "Registrasi berhasil!", "data": newUser})
-		})
-
-		// 2. LOGIN
-		r.POST("/login", func(c *gin.Context) {
-			var input AuthInput
-			if err := c.ShouldBindJSON(&input); err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-				return
-			}
-			var user User
-			err := userCollection.FindOne(context.TODO(), bson.M{"email": input.Email, "password": input.Password}).Decode(&user)
-			if err != nil {
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Email atau Password salah"})
-				return
-			}
-			c.JSON(http.StatusOK, gin.H{"message": "Login sukses", "user": user})
-		})
-
-		// 3. GET LOCATIONS
-		r.GET("/locations", func(c *gin.Context) {
-			var locations []Location
-			cursor, _ := geoCollection.Find(context.TODO(), bson.M{})
-			defer cursor.Close(context.TODO())
-			for cursor.Next(context.TODO()) {
-				var loc Location
-				cursor.Decode(&loc)
-				locations = append(locations, loc)
-			}
-			if locations == nil { locations = []Location{} }
-			c.JSON(http.StatusOK, locations)
-		})
-
-		// 4. ADD LOCATION
-		r.POST("/locations", func(c *gin.Context) {
-			userEmail := c.GetHeader("X-User-Email")
-			if userEmail == "" {
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Anda harus login!"})
-				return
-			}
-			var newLocation Location
-			if err := c.ShouldBindJSON(&newLocation); err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-				return
-			}
-			newLocation.ID = primitive.NewObjectID()
-			newLocation.CreatedBy = userEmail
-			geoCollection.InsertOne(context.TODO(), newLocation)
-			c.JSON(http.StatusCreated, gin.H{"message": "Lokasi ditambahkan!", "data": newLocation})
-		})
-
-		// 5. EDIT LOCATION
-		r.PUT("/locations/:id", func(c *gin.Context) {
-			idParam := c.Param("id")
-			objID, _ := primitive.ObjectIDFromHex(idParam)
-			requestorEmail := c.GetHeader("X-User-Email")
-			
-			var requestor User
-			userCollection.FindOne(context.TODO(), bson.M{"email": requestorEmail}).Decode(&requestor)
-			var existingLoc Location
-			geoCollection.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&existingLoc)
+// ensureIndexes menjalankan migrasi dan membuat index. Kegagalan hanya
+// dicatat supaya aplikasi tetap bisa melayani request.
+func ensureIndexes(repos Repositories) {
+	ctx, cancel := context.WithTimeout(context.Background(), startupTimeout)
+	defer cancel()
+	// Ubah dokumen lama {lat, lng} menjadi GeoJSON Point dan buat index
+	migrated, err := repos.Locations.Migrate(ctx)
+	if err != nil {
+		log.Println("Warning: migrasi koordinat gagal:", err)
+	} else if migrated > 0 {
+		slog.Info("lokasi dimigrasi ke GeoJSON", "count", migrated)
+	}
+	// Token reset otomatis dihapus Mongo setelah kedaluwarsa
+	if err := repos.PasswordResets.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index password reset:", err)
+	}
+	if err := repos.Categories.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index kategori:", err)
+	}
+	if err := repos.Audit.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index audit log:", err)
+	}
+	if err := repos.Transit.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index transit:", err)
+	}
+	if err := repos.Regions.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index wilayah:", err)
+	}
+	if err := repos.Postcodes.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index kode pos:", err)
+	}
+	if err := repos.Reviews.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index ulasan:", err)
+	}
+	if err := repos.Confirmations.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index konfirmasi lokasi:", err)
+	}
+	if err := repos.Favorites.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index favorit:", err)
+	}
+	if err := repos.Campaigns.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index campaign:", err)
+	}
+	if err := repos.MapView.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index map_view:", err)
+	}
+	if err := repos.Reassignments.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index reassignment:", err)
+	}
+	if err := repos.ImportJobs.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index import job:", err)
+	}
+	if err := repos.CheckIns.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index check-in:", err)
+	}
+	if err := repos.Promotions.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index impression promosi:", err)
+	}
+	if err := repos.Exports.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index jadwal export:", err)
+	}
+	if err := repos.DeadLetters.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index dead letter email:", err)
+	}
+	if err := repos.CategorySuggestions.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index usulan kategori:", err)
+	}
+	if err := repos.Usage.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index pemakaian API:", err)
+	}
+	if err := repos.Events.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index log event:", err)
+	}
+	if err := repos.Sessions.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index sesi login:", err)
+	}
+	if err := repos.Redirects.EnsureIndexes(ctx); err != nil {
+		log.Println("Warning: gagal membuat index redirect lokasi:", err)
+	}
+}
 
-			if requestor.Role != "admin" && existingLoc.CreatedBy != requestor.Email {
-				c.JSON(http.StatusForbidden, gin.H{"error": "Akses ditolak"})
-				return
-			}
+// Build merakit repository -> service -> handler lalu membuat router. db
+// boleh nil (test): /readyz melaporkan MongoDB not_configured dan doctor
+// melewati pemeriksaan database.
+func Build(cfg *config.Config, repos Repositories, db *mongo.Database) *App {
+	return build(cfg, repos, db, database.NewState())
+}
 
-			var updateData Location
-			c.ShouldBindJSON(&updateData)
-			update := bson.M{
-				"$set": bson.M{
-					"name": updateData.Name, "category": updateData.Category,
-					"coordinates": updateData.Coordinates, "address": updateData.Address,
-				},
-			}
-			geoCollection.UpdateOne(context.TODO(), bson.M{"_id": objID}, update)
-			c.JSON(http.StatusOK, gin.H{"message": "Data diupdate"})
-		})
+func build(cfg *config.Config, repos Repositories, db *mongo.Database, dbState *database.State) *App {
+	photos := objectstore.NewFromEnv()
+	auditLog := services.NewAuditService(repos.Audit, services.AuditOptions{Retention: cfg.AuditRetention, Archive: photos})
+	// Selama MongoDB tanpa primary write idempoten diantrekan, sisanya 503
+	dbMode := services.NewDatabaseMode(dbState, auditLog)
+	health := services.NewHealthService(mongoPinger(db), dbMode)
+	roles := services.NewRoleService(repos.Roles, repos.Users, repos.Regions, repos.Categories, auditLog)
+	categories := services.NewCategoryService(repos.Categories, repos.Locations, auditLog)
+	regions := services.NewRegionService(repos.Regions, repos.Locations, auditLog)
+	postcodes := services.NewPostcodeService(repos.Postcodes, auditLog)
 
-		// 6. DELETE LOCATION
-		r.DELETE("/locations/:id", func(c *gin.Context) {
-			idParam := c.Param("id")
-			objID, _ := primitive.ObjectIDFromHex(idParam)
-			requestorEmail := c.GetHeader("X-User-Email")
-			
-			var requestor User
-			userCollection.FindOne(context.TODO(), bson.M{"email": requestorEmail}).Decode(&requestor)
-			var existingLoc Location
-			geoCollection.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&existingLoc)
+	ctx, cancel := context.WithTimeout(context.Background(), startupTimeout)
+	defer cancel()
+	if err := roles.EnsureDefaults(ctx); err != nil {
+		log.Println("Warning: gagal membuat role bawaan:", err)
+	}
 
-			if requestor.Role != "admin" && existingLoc.CreatedBy != requestor.Email {
-				c.JSON(http.StatusForbidden, gin.H{"error": "Akses ditolak"})
-				return
-			}
-			geoCollection.DeleteOne(context.TODO(), bson.M{"_id": objID})
-			c.JSON(http.StatusOK, gin.H{"message": "Data dihapus"})
-		})
+	settings := services.NewSettingsService(repos.Settings, auditLog)
+	// Perubahan lokasi & rating diteruskan ke read model map_view
+	locationEvents := services.NewLocationEvents()
+	// Setiap perubahan lokasi juga dicatat ke log event ber-seq (GET /events)
+	eventLog := services.NewEventLog(repos.Events, locationEvents)
+	promotions := services.NewPromotionService(repos.Locations, repos.Promotions, auditLog, locationEvents)
+	mapView := services.NewMapViewService(repos.MapView, repos.Locations, settings, categories, promotions, locationEvents, cfg.FreshnessHalfLife)
+	// Didaftarkan setelah map view supaya event dibaca dari map_view yang sudah diperbarui
+	streams := services.NewLocationStream(repos.MapView, categories, locationEvents, cfg.StreamMaxClients)
+	// Email user didahulukan dari webhook, lalu import/backfill & email massal
+	queue := jobs.New(jobs.Limits{Workers: cfg.Jobs.Workers, Caps: map[jobs.Class]int{
+		jobs.Email:    cfg.Jobs.Email,
+		jobs.Webhook:  cfg.Jobs.Webhook,
+		jobs.Backfill: cfg.Jobs.Backfill,
+	}})
+	// Email transaksional yang gagal disimpan sebagai dead letter untuk dikirim ulang
+	mail := services.NewMailOutbox(mailer.Queued(mailer.NewFromEnv(), queue), repos.DeadLetters, auditLog)
+	userCache := services.NewUserCache(0)
+	authService := services.NewAuthService(repos.Users, repos.PasswordResets, repos.Sessions, auth.NewManager(cfg.JWT.Secret, cfg.JWT.AccessTTL, cfg.JWT.RefreshTTL), mail, auditLog, services.AuthOptions{
+		AllowRegistration: cfg.AllowRegistration,
+		LegacyEmailAuth:   cfg.LegacyEmailAuth,
+		PasswordResetURL:  cfg.PasswordResetURL,
+		LoginAlertURL:     cfg.LoginAlertURL,
+		Lockout:           ratelimit.Lockout{MaxFailures: cfg.LoginMaxFailures, Duration: cfg.LoginLockout},
+		Cache:             userCache,
+		Database:          dbMode,
+	})
+	runtime := &runtimeState{cfg: cfg.Runtime()}
+	geocoder, matcher, elevations, forecasts := geocode.NewFromEnv(), mapmatch.NewFromEnv(), elevation.NewFromEnv(), weather.NewFromEnv()
+	pois := osm.NewFromEnv()
+	policyMode := fieldpolicy.ModeFromEnv()
+	h := &handlers.Handler{
+		Auth:        authService,
+		Users:       services.NewUserService(repos.Users, roles, auditLog, userCache, dbMode),
+		Roles:       roles,
+		Categories:  categories,
+		Suggestions: services.NewCategorySuggestionService(repos.CategorySuggestions, categories, mail, auditLog),
+		MissingPOIs: services.NewMissingPOIService(repos.Locations, categories, pois),
+		Regions:     regions,
+		Postcodes:   postcodes,
+		Geocode:     services.NewGeocodeService(geocoder),
+		Reviews:     services.NewReviewService(repos.Reviews, repos.Locations, roles, auditLog, locationEvents),
+		Favorites:   services.NewFavoriteService(repos.Favorites, repos.Locations, categories, roles, locationEvents, dbMode),
+		Locations: services.NewLocationService(repos.Locations, repos.Reviews, repos.Confirmations, settings, roles, categories, regions, postcodes, auditLog, services.LocationOptions{
+			PolicyMode:        policyMode,
+			QuotaUpgradeURL:   cfg.QuotaUpgradeURL,
+			MapMatcher:        matcher,
+			Elevation:         elevations,
+			Weather:           forecasts,
+			Photos:            photos,
+			FreshnessHalfLife: cfg.FreshnessHalfLife,
+			TrashRetention:    cfg.TrashRetention,
+			ArchiveAfter:      cfg.ArchiveAfter,
+			Events:            locationEvents,
+			Mail:              mail,
+			CheckIns:          repos.CheckIns,
+			Geocoder:          geocoder,
+			ImportJobs:        repos.ImportJobs,
+			Redirects:         repos.Redirects,
+		}),
+		MapView:    mapView,
+		Promotions: promotions,
+		Featured:   services.NewFeaturedService(repos.Settings, repos.Locations, categories, auditLog, locationEvents),
+		Stream:     streams,
+		Settings:   settings,
+		Transit:    services.NewTransitService(repos.Transit, repos.Locations, categories, repos.Settings, auditLog),
+		Security: services.NewSecurityService(repos.Users, repos.Locations, services.SecurityOptions{
+			AllowAllOrigins:      func() bool { return len(runtime.get().CORS.Origins) == 0 },
+			AdminAllowAllOrigins: func() bool { return len(runtime.get().AdminCORS.Origins) == 0 },
+			RegistrationOpen:     authService.RegistrationOpen,
+			LegacyEmailAuth:      authService.LegacyEmailAuth,
+			JWTSecretWeakness:    cfg.JWT.Weakness(),
+			MetricsTokenSet:      cfg.MetricsToken != "",
+		}),
+		Audit: auditLog,
+		Mail:  mail,
+		Campaigns: services.NewCampaignService(repos.Campaigns, repos.Users, mail, auditLog, services.CampaignOptions{
+			// Tanpa PUBLIC_API_URL open tidak dilacak
+			TrackingBaseURL: cfg.PublicAPIURL,
+			SendInterval:    cfg.CampaignSendInterval,
+		}),
+		Changelog: services.NewChangelogService(repos.Audit, repos.Locations, categories),
+		Reassign:  services.NewReassignService(repos.Reassignments, repos.Locations, repos.Users, repos.Regions, categories, roles, auditLog, locationEvents),
+		CheckIns:  services.NewCheckInService(repos.CheckIns, repos.Locations, categories, roles, locationEvents, cfg.JWT.Secret),
+		Stats:     services.NewStatsService(repos.Locations, categories),
+		Alerts:    services.NewAlertService(repos.Users, repos.Locations, mail, cfg.FreshnessHalfLife),
+		Health:    health,
+		Database:  dbMode,
+		Status:    services.NewStatusService(health, mail, photos, repos.Settings, auditLog),
+		Doctor: doctor.New(doctor.Options{
+			Config: cfg,
+			DB:     db,
+			Mailer: mail,
+			Store:  photos,
+		}),
+		Deprecations: deprecation.NewTracker(),
+		Events:       siem.NewFromEnv(),
+		Shadow:       shadow.NewFromEnv(),
+		// Batas request per IP; PerMin 0 = tanpa batas sampai diaktifkan lewat reload
+		RateLimit:     ratelimit.New(cfg.RateLimit.PerMin, cfg.RateLimit.Burst),
+		AuthRateLimit: ratelimit.New(cfg.AuthRateLimit.PerMin, cfg.AuthRateLimit.Burst),
+		MetricsToken:  cfg.MetricsToken,
+		// IP client untuk rate limit, audit log dan sesi login
+		TrustedProxies:  cfg.TrustedProxies,
+		TrustedPlatform: cfg.TrustedPlatform,
+		Jobs:            queue,
+		Usage:           services.NewUsageService(repos.Usage),
+		EventLog:        eventLog,
+	}
+	h.Integrity = services.NewIntegrityService(repos.Locations, repos.Users, repos.Reviews, repos.Favorites, repos.CheckIns,
+		repos.Settings, h.Reassign, auditLog, photos)
+	// Export berkala memakai filter dan penyamaran koordinat yang sama dengan GET /locations/export
+	h.Exports = services.NewScheduledExportService(repos.Exports, h.Locations, categories, mail, photos, auditLog, queue)
+	app := &App{Router: h.Router(cfg.CORS, cfg.AdminCORS), db: db, streams: streams, handler: h, auth: authService, runtime: runtime}
+	h.ReloadConfig = app.Reload
+	features := map[string]bool{
+		"email":             mail.Configured(),
+		"photo_upload":      photos != nil,
+		"geocode":           geocoder != nil,
+		"map_match":         matcher != nil,
+		"elevation":         elevations != nil,
+		"weather":           forecasts != nil,
+		"poi_suggestions":   pois != nil,
+		"siem_export":       h.Events.Enabled(),
+		"shadow_traffic":    h.Shadow.Enabled(),
+		"campaign_tracking": cfg.PublicAPIURL != "",
+		"login_lockout":     cfg.LoginMaxFailures > 0,
+	}
+	h.RuntimeInfo = func() handlers.RuntimeInfo {
+		return runtimeInfo(cfg, runtime.get(), string(policyMode), features, queue)
+	}
+	return app
+}
 
-		// 7. GET USERS (Admin)
-		r.GET("/users", func(c *gin.Context) {
-			requestorEmail := c.GetHeader("X-User-Email")
-			var u User
-			userCollection.FindOne(context.TODO(), bson.M{"email": requestorEmail}).Decode(&u)
-			if u.Role != "admin" {
-				c.JSON(http.StatusForbidden, gin.H{"error": "Khusus Admin"})
-				return
-			}
-			var users []User
-			cursor, _ := userCollection.Find(context.TODO(), bson.M{})
-			defer cursor.Close(context.TODO())
-			for cursor.Next(context.TODO()) {
-				var usr User
-				cursor.Decode(&usr)
-				users = append(users, usr)
-			}
-			if users == nil { users = []User{} }
-			c.JSON(http.StatusOK, users)
-		})
+// SetupRouter dipakai entry point Vercel: config dibaca dari environment
+// dan aplikasi dirakit sekali per instance. Config yang tidak valid
+// menghentikan proses supaya kesalahan deploy langsung terlihat.
+func SetupRouter() *gin.Engine {
+	once.Do(func() {
+		cfg, err := config.Load()
+		if err != nil {
+			log.Fatal(err)
+		}
+		vercelApp, err = New(cfg)
+		if err != nil {
+			log.Fatal(err)
+		}
+	})
+	return vercelApp.Router
+}
 
-		// 8. UPDATE USER ROLE
-		r.PUT("/users/:id/role", func(c *gin.Context) {
-			requestorEmail := c.GetHeader("X-User-Email")
-			var u User
-			userCollection.FindOne(context.TODO(), bson.M{"email": requestorEmail}).Decode(&u)
-			if u.Role != "admin" {
-				c.JSON(http.StatusForbidden, gin.H{"error": "Khusus Admin"})
-				return
-			}
-			idParam := c.Param("id")
-			objID, _ := primitive.ObjectIDFromHex(idParam)
-			var input RoleInput
-			c.ShouldBindJSON(&input)
-			userCollection.UpdateOne(context.TODO(), bson.M{"_id": objID}, bson.M{"$set": bson.M{"role": input.Role}})
-			c.JSON(http.StatusOK, gin.H{"message": "Role diubah"})
-		})
+// Pinger untuk /readyz; nil jika tanpa MongoDB. Secondary cukup: tanpa
+// primary instance tetap melayani read (mode baca saja).
+func mongoPinger(db *mongo.Database) services.Pinger {
+	if db == nil {
+		return nil
+	}
+	return func(ctx context.Context) error {
+		return db.Client().Ping(ctx, readpref.PrimaryPreferred())
+	}
+}
 
-		// 9. DELETE USER
-		r.DELETE("/users/:id", func(c *gin.Context) {
-			requestorEmail := c.GetHeader("X-User-Email")
-			var u User
-			userCollection.FindOne(context.TODO(), bson.M{"email": requestorEmail}).Decode(&u)
-			if u.Role != "admin" {
-				c.JSON(http.StatusForbidden, gin.H{"error": "Khusus Admin"})
-				return
-			}
-			idParam := c.Param("id")
-			objID, _ := primitive.ObjectIDFromHex(idParam)
-			userCollection.DeleteOne(context.TODO(), bson.M{"_id": objID})
-			c.JSON(http.StatusOK, gin.H{"message": "User dihapus"})
-		})
+// Shutdown menulis counter pemakaian API lalu menutup koneksi MongoDB. Dipanggil setelah server berhenti
+// menerima request (lihat main.go).
+func (a *App) Shutdown(ctx context.Context) error {
+	// Counter pemakaian API yang masih di memori
+	if err := a.handler.Usage.Close(ctx); err != nil {
+		log.Println("Warning: gagal menyimpan pemakaian API:", err)
+	}
+	if a.db == nil {
+		return nil
+	}
+	return a.db.Client().Disconnect(ctx)
+}
 
-		app = r
-	})
-	return app
+// StopStreams memutus semua koneksi GET /locations/stream supaya
+// http.Server.Shutdown tidak menunggu stream yang tidak pernah selesai
+// (lihat main.go).
+func (a *App) StopStreams() {
+	a.streams.Close()
 }
 
 // --- ENTRY POINT VERCEL ---
@@ -266,4 +477,4 @@ func Handler(w http.ResponseWriter, r *http.Request) {
 	router := SetupRouter()
 	// Jalankan request
 	router.ServeHTTP(w, r)
-}
\ No newline at end of file
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func verifyAudit(t *testing.T, ta *testApp) models.AuditVerifyResult {
+	t.Helper()
+	rec := ta.do(http.MethodGet, "/v1/admin/audit-logs/verify", ta.token(adminEmail), nil)
+	expect(t, rec, http.StatusOK, "")
+	var body struct {
+		Data models.AuditVerifyResult `json:"data"`
+	}
+	decode(t, rec, &body)
+	return body.Data
+}
+
+func TestAuditHashChain(t *testing.T) {
+	ta := newTestApp(t)
+	ctx := context.Background()
+	admin := ta.token(adminEmail)
+	expect(t, ta.do(http.MethodGet, "/v1/admin/audit-log/verify", admin, nil), http.StatusOK, "")
+
+	// Nilai bertingkat (labels) dan angka harus menghasilkan hash yang sama
+	// setelah disimpan
+	expect(t, ta.do(http.MethodPost, "/v1/locations", admin, newLocationPayload("Kopi Murah")), http.StatusCreated, "")
+	category := map[string]interface{}{"name": "Kafe", "labels": models.Labels{"id": "Kafe", "en": "Cafe"}}
+	expect(t, ta.do(http.MethodPut, "/v1/categories/kafe", admin, category), http.StatusOK, "")
+
+	result := verifyAudit(t, ta)
+	if !result.OK || result.Checked < 2 || result.HeadSeq != result.Checked || result.HeadHash == "" {
+		t.Fatalf("chain utuh: %+v", result)
+	}
+	expect(t, ta.do(http.MethodGet, "/v1/admin/audit-logs/verify", ta.token(userEmail), nil), http.StatusForbidden, "")
+
+	// Catatan palsu yang hash-nya tidak dihitung ulang
+	head, err := ta.store.Audit().Last(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := models.AuditLog{ID: primitive.NewObjectID(), Time: time.Now().UTC().Truncate(time.Millisecond),
+		Action: "location.delete", Seq: head.Seq + 1, PrevHash: head.Hash, Hash: head.Hash}
+	if err := ta.store.Audit().Insert(ctx, []models.AuditLog{forged}); err != nil {
+		t.Fatal(err)
+	}
+	result = verifyAudit(t, ta)
+	if result.OK || result.Broken == nil || result.Broken.Seq != forged.Seq || result.Broken.Reason != models.ChainHashMismatch {
+		t.Fatalf("catatan palsu: %+v", result)
+	}
+}
+
+func TestAuditChainGap(t *testing.T) {
+	ta := newTestApp(t)
+	ctx := context.Background()
+	expect(t, ta.do(http.MethodPost, "/v1/locations", ta.token(adminEmail), newLocationPayload("Kopi Murah")), http.StatusCreated, "")
+	head, err := ta.store.Audit().Last(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Seq yang dilompati berarti ada catatan yang dihapus
+	gap := models.AuditLog{ID: primitive.NewObjectID(), Time: time.Now().UTC().Truncate(time.Millisecond), Seq: head.Seq + 2, PrevHash: head.Hash}
+	gap.Hash = gap.ChainHash()
+	if err := ta.store.Audit().Insert(ctx, []models.AuditLog{gap}); err != nil {
+		t.Fatal(err)
+	}
+	result := verifyAudit(t, ta)
+	if result.OK || result.Broken == nil || result.Broken.Seq != head.Seq+1 || result.Broken.Reason != models.ChainMissing {
+		t.Fatalf("seq hilang: %+v", result)
+	}
+}
+
+func TestAuditSearchAndExport(t *testing.T) {
+	ta := newTestApp(t)
+	admin := ta.token(adminEmail)
+	expect(t, ta.do(http.MethodPost, "/v1/locations", admin, newLocationPayload("Kopi Braga")), http.StatusCreated, "")
+	category := map[string]interface{}{"name": "Kafe Kopi"}
+	expect(t, ta.do(http.MethodPut, "/v1/categories/kafe", admin, category), http.StatusOK, "")
+
+	search := func(query string) []models.AuditLog {
+		t.Helper()
+		rec := ta.do(http.MethodGet, "/v1/admin/audit-logs?"+query, admin, nil)
+		expect(t, rec, http.StatusOK, "")
+		var body struct {
+			Data []models.AuditLog `json:"data"`
+		}
+		decode(t, rec, &body)
+		return body.Data
+	}
+	// Nama resource masuk summary sehingga bisa dicari tanpa ID
+	logs := search("q=braga")
+	if len(logs) != 1 || logs[0].Action != "location.create" || !strings.Contains(logs[0].Summary, `"Kopi Braga"`) {
+		t.Fatalf("cari braga %+v", logs)
+	}
+	if logs := search("q=" + adminEmail[:5]); len(logs) < 2 {
+		t.Fatalf("cari actor %+v", logs)
+	}
+	today := time.Now().UTC().Format("2006-01-02")
+	if logs := search("q=braga&to=" + time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02")); len(logs) != 0 {
+		t.Fatalf("rentang kemarin %+v", logs)
+	}
+	if logs := search("q=braga&from=" + today + "&to=" + today); len(logs) != 1 {
+		t.Fatalf("rentang hari ini %+v", logs)
+	}
+
+	rec := ta.do(http.MethodGet, "/v1/admin/audit-logs/export?resource_type=category", admin, nil)
+	expect(t, rec, http.StatusOK, "")
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("content type %q", rec.Header().Get("Content-Type"))
+	}
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0][0] != "time" || rows[1][4] != "category.update" || !strings.Contains(rows[1][8], "name") {
+		t.Fatalf("csv %q", rows)
+	}
+	expect(t, ta.do(http.MethodGet, "/v1/admin/audit-logs/export?from=kemarin", admin, nil), http.StatusBadRequest, "")
+	expect(t, ta.do(http.MethodGet, "/v1/admin/audit-logs/export", ta.token(userEmail), nil), http.StatusForbidden, "")
+}
+
+func TestAuditArchive(t *testing.T) {
+	var mu sync.Mutex
+	objects := map[string][]byte{}
+	s3 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		objects[r.URL.Path] = body
+		mu.Unlock()
+	}))
+	defer s3.Close()
+	// Object store dibaca package objectstore langsung dari environment
+	for k, v := range map[string]string{"OBJECT_STORE": "s3", "S3_ENDPOINT": s3.URL, "S3_BUCKET": "arsip",
+		"S3_ACCESS_KEY_ID": "key", "S3_SECRET_ACCESS_KEY": "secret"} {
+		t.Setenv(k, v)
+	}
+
+	// Tanpa AUDIT_RETENTION job menolak berjalan
+	ta := newTestApp(t)
+	expect(t, ta.do(http.MethodPost, "/v1/admin/jobs/audit-archive", ta.token(adminEmail), nil), http.StatusBadRequest, "VALIDATION_FAILED")
+
+	ta = newTestAppWithEnv(t, map[string]string{"AUDIT_RETENTION": "200ms"})
+	admin := ta.token(adminEmail)
+	for _, name := range []string{"Kopi Satu", "Kopi Dua", "Kopi Tiga"} {
+		expect(t, ta.do(http.MethodPost, "/v1/locations", admin, newLocationPayload(name)), http.StatusCreated, "")
+	}
+	before := verifyAudit(t, ta)
+	// Catatan job arsip sendiri belum lewat retensi di batch berikutnya
+	time.Sleep(250 * time.Millisecond)
+	expect(t, ta.do(http.MethodPost, "/v1/admin/jobs/audit-archive", ta.token(userEmail), nil), http.StatusForbidden, "")
+
+	var result models.AuditArchiveResult
+	rec := ta.do(http.MethodPost, "/v1/admin/jobs/audit-archive?batch=2", admin, nil)
+	expect(t, rec, http.StatusOK, "")
+	decode(t, rec, &result)
+	if result.Archived != 2 || !result.More || result.Archive == nil || result.Archive.FromSeq != 1 || result.Archive.ToSeq != 2 {
+		t.Fatalf("batch pertama %+v", result)
+	}
+	rec = ta.do(http.MethodPost, "/v1/admin/jobs/audit-archive?batch=2", admin, nil)
+	expect(t, rec, http.StatusOK, "")
+	decode(t, rec, &result)
+	if result.Archived != before.HeadSeq-2 || result.More {
+		t.Fatalf("batch kedua %+v", result)
+	}
+
+	// File berisi satu catatan per baris
+	data, ok := objects["/arsip/"+result.Archive.Key]
+	if !ok {
+		t.Fatalf("object %s tidak tersimpan: %v", result.Archive.Key, objects)
+	}
+	zr, err := gzip.NewReader(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := 0
+	for sc := bufio.NewScanner(zr); sc.Scan(); {
+		lines++
+	}
+	if int64(lines) != result.Archive.Count {
+		t.Fatalf("%d baris, manifest %d", lines, result.Archive.Count)
+	}
+
+	// Chain tetap utuh: verifikasi mulai dari arsip, catatan baru menyambung
+	expect(t, ta.do(http.MethodPost, "/v1/locations", admin, newLocationPayload("Kopi Empat")), http.StatusCreated, "")
+	after := verifyAudit(t, ta)
+	if !after.OK || after.ArchivedSeq != before.HeadSeq || after.HeadSeq <= before.HeadSeq {
+		t.Fatalf("verifikasi setelah arsip %+v", after)
+	}
+	rec = ta.do(http.MethodGet, "/v1/admin/audit-logs/archives", admin, nil)
+	expect(t, rec, http.StatusOK, "")
+	var archives struct {
+		Data []models.AuditArchive `json:"data"`
+	}
+	decode(t, rec, &archives)
+	if len(archives.Data) != 2 || archives.Data[1].HeadHash != result.Archive.HeadHash {
+		t.Fatalf("manifest %+v", archives.Data)
+	}
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"InfoCuy-Backend/internal/apperr"
+	"InfoCuy-Backend/internal/fakedata"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/rbac"
+)
+
+func TestRegisterAndLogin(t *testing.T) {
+	ta := newTestApp(t)
+	input := models.AuthInput{Email: "baru@example.com", Password: "rahasia123"}
+
+	rec := ta.do(http.MethodPost, "/v1/register", "", input)
+	expect(t, rec, http.StatusCreated, "")
+	var registered struct {
+		Data models.User `json:"data"`
+	}
+	decode(t, rec, &registered)
+	if registered.Data.Role != rbac.DefaultRole {
+		t.Errorf("role %q, want %q", registered.Data.Role, rbac.DefaultRole)
+	}
+	if strings.Contains(rec.Body.String(), input.Password) {
+		t.Error("response registrasi tidak boleh memuat password")
+	}
+
+	rec = ta.do(http.MethodPost, "/v1/register", "", input)
+	expect(t, rec, http.StatusBadRequest, apperr.CodeEmailTaken)
+
+	rec = ta.do(http.MethodPost, "/v1/login", "", input)
+	expect(t, rec, http.StatusOK, "")
+}
+
+func TestLoginRejectsWrongPassword(t *testing.T) {
+	ta := newTestApp(t)
+	type loginError struct{ Code, Message string }
+	login := func(email, password string) (loginError, time.Duration) {
+		t.Helper()
+		start := time.Now()
+		rec := ta.do(http.MethodPost, "/v1/login", "", models.AuthInput{Email: email, Password: password})
+		took := time.Since(start)
+		expect(t, rec, http.StatusUnauthorized, apperr.CodeInvalidCredentials)
+		var body struct {
+			Error loginError `json:"error"`
+		}
+		decode(t, rec, &body)
+		return body.Error, took
+	}
+	wrong, wrongTook := login(userEmail, "salah")
+
+	// Email yang tidak terdaftar dijawab sama, dan tetap melewati bcrypt,
+	// supaya tidak bisa ditebak dari isi maupun lama respons
+	unknown, unknownTook := login("tidak.ada@example.com", fakedata.DefaultPassword)
+	if unknown != wrong {
+		t.Fatalf("error email tak terdaftar %+v, password salah %+v", unknown, wrong)
+	}
+	if unknownTook < wrongTook/4 {
+		t.Fatalf("email tak terdaftar dijawab %v, password salah %v", unknownTook, wrongTook)
+	}
+}
+
+func TestAuthRequired(t *testing.T) {
+	ta := newTestApp(t)
+	expect(t, ta.do(http.MethodGet, "/v1/me/quota", "", nil), http.StatusUnauthorized, apperr.CodeUnauthorized)
+	expect(t, ta.do(http.MethodGet, "/v1/me/quota", "bukan-token", nil), http.StatusUnauthorized, "")
+	expect(t, ta.do(http.MethodGet, "/v1/me/quota", ta.token(userEmail), nil), http.StatusOK, "")
+}
+
+func TestUserManagementNeedsPermission(t *testing.T) {
+	ta := newTestApp(t)
+	expect(t, ta.do(http.MethodGet, "/v1/users", ta.token(userEmail), nil), http.StatusForbidden, apperr.CodeForbidden)
+
+	rec := ta.do(http.MethodGet, "/v1/users", ta.token(adminEmail), nil)
+	expect(t, rec, http.StatusOK, "")
+	var users []models.User
+	decode(t, rec, &users)
+	if len(users) != 3 {
+		t.Errorf("got %d user, want 3", len(users))
+	}
+}
+
+// User di-cache untuk autentikasi, tapi perubahan role dan penghapusan harus
+// langsung berlaku di request berikutnya.
+func TestUserCacheInvalidation(t *testing.T) {
+	ta := newTestApp(t)
+	token := ta.token(userEmail)
+	expect(t, ta.do(http.MethodGet, "/v1/users", token, nil), http.StatusForbidden, apperr.CodeForbidden)
+
+	u, err := ta.store.Users().FindByEmail(context.Background(), userEmail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := "/v1/users/" + u.ID.Hex()
+	expect(t, ta.do(http.MethodPut, path+"/role", ta.token(adminEmail), map[string]string{"role": "admin"}), http.StatusOK, "")
+	expect(t, ta.do(http.MethodGet, "/v1/users", token, nil), http.StatusOK, "")
+
+	expect(t, ta.do(http.MethodDelete, path, ta.token(adminEmail), nil), http.StatusOK, "")
+	expect(t, ta.do(http.MethodGet, "/v1/me/quota", token, nil), http.StatusUnauthorized, "")
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+func changelogIn(t *testing.T, ta *testApp, acceptLanguage string) []models.ChangelogEntry {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/v1/changelog", nil)
+	req.Header.Set("Accept-Language", acceptLanguage)
+	rec := httptest.NewRecorder()
+	ta.app.Router.ServeHTTP(rec, req)
+	expect(t, rec, http.StatusOK, "")
+	if strings.Contains(rec.Body.String(), "@example.com") {
+		t.Fatalf("changelog memuat email actor: %s", rec.Body)
+	}
+	var body struct {
+		Data []models.ChangelogEntry `json:"data"`
+	}
+	decode(t, rec, &body)
+	return body.Data
+}
+
+func TestChangelog(t *testing.T) {
+	ta := newTestApp(t)
+	admin := ta.token(adminEmail)
+
+	added := createLocation(t, ta, admin, "Kopi Baru")
+	createLocation(t, ta, ta.token(userEmail), "Kopi Menunggu Review")
+	path := "/v1/locations/" + added.ID.Hex()
+	update := newLocationPayload("Kopi Baru Sekali")
+	update["address"] = "Jl. Asia Afrika No. 2, Bandung"
+	expect(t, ta.do(http.MethodPut, path, admin, update), http.StatusOK, "")
+	closed := newLocationPayload("Kopi Baru Sekali")
+	closed["address"] = update["address"]
+	closed["status"] = models.StatusPermanentlyClosed
+	expect(t, ta.do(http.MethodPut, path, admin, closed), http.StatusOK, "")
+	expect(t, ta.do(http.MethodDelete, "/v1/locations/"+ta.location.ID.Hex(), admin, nil), http.StatusOK, "")
+
+	entries := changelogIn(t, ta, "id")
+	var types []string
+	for _, e := range entries {
+		types = append(types, e.Type)
+	}
+	want := []string{models.ChangeAdded, models.ChangeUpdated, models.ChangeClosed, models.ChangeRemoved}
+	if strings.Join(types, ",") != strings.Join(want, ",") {
+		t.Fatalf("types %v, want %v", types, want)
+	}
+	if entries[0].Summary != "Lokasi baru: Kopi Baru (Kafe)" {
+		t.Errorf("summary %q", entries[0].Summary)
+	}
+	if got := strings.Join(entries[1].Fields, ","); got != "name,address" {
+		t.Errorf("fields %q", got)
+	}
+	if got := changelogIn(t, ta, "en")[1].Summary; got != "Kopi Baru Sekali updated: name, address" {
+		t.Errorf("summary en %q", got)
+	}
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"InfoCuy-Backend/internal/apperr"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+func TestEventCheckIn(t *testing.T) {
+	ta := newTestApp(t)
+	ctx := context.Background()
+	owner, visitor := ta.token(userEmail), ta.token(otherEmail)
+	base := "/v1/locations/" + ta.location.ID.Hex() + "/checkin"
+
+	// Lokasi biasa tidak punya check-in
+	expect(t, ta.do(http.MethodGet, base+"/qr", owner, nil), http.StatusBadRequest, apperr.CodeValidation)
+
+	if err := ta.store.Categories().Create(ctx, &models.Category{Name: "Acara", Slug: "acara", Event: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ta.store.Locations().Update(ctx, ta.location.ID, repositories.Fields{"category": "acara"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Kode dan kehadiran hanya untuk panitia
+	expect(t, ta.do(http.MethodGet, base+"/qr", visitor, nil), http.StatusForbidden, apperr.CodeForbidden)
+	expect(t, ta.do(http.MethodGet, base+"/attendance", visitor, nil), http.StatusForbidden, apperr.CodeForbidden)
+	rec := ta.do(http.MethodGet, base+"/qr", owner, nil)
+	expect(t, rec, http.StatusOK, "")
+	var qr struct {
+		Data models.CheckInCode `json:"data"`
+	}
+	decode(t, rec, &qr)
+	if qr.Data.Code == "" || rec.Header().Get("Cache-Control") != "no-store" {
+		t.Fatalf("qr %+v, headers %v", qr.Data, rec.Header())
+	}
+
+	expect(t, ta.do(http.MethodPost, base+"/verify", visitor, models.CheckInInput{Code: qr.Data.Code + "x"}), http.StatusBadRequest, "INVALID_CHECKIN_CODE")
+	expect(t, ta.do(http.MethodPost, base+"/verify", visitor, models.CheckInInput{Code: "1.AAAA"}), http.StatusBadRequest, "INVALID_CHECKIN_CODE")
+	expect(t, ta.do(http.MethodPost, base+"/verify", visitor, models.CheckInInput{Code: qr.Data.Code}), http.StatusCreated, "")
+	expect(t, ta.do(http.MethodPost, base+"/verify", visitor, models.CheckInInput{Code: qr.Data.Code}), http.StatusConflict, "ALREADY_CHECKED_IN")
+	expect(t, ta.do(http.MethodPost, base+"/verify", owner, models.CheckInInput{Code: qr.Data.Code}), http.StatusCreated, "")
+
+	rec = ta.do(http.MethodGet, base+"/attendance", owner, nil)
+	expect(t, rec, http.StatusOK, "")
+	var attendance struct {
+		Data models.Attendance `json:"data"`
+	}
+	decode(t, rec, &attendance)
+	if attendance.Data.Total != 2 || len(attendance.Data.ByDay) != 1 || attendance.Data.ByDay[0].Count != 2 {
+		t.Fatalf("attendance %+v", attendance.Data)
+	}
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func preflight(ta *testApp, path, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, path, nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	rec := httptest.NewRecorder()
+	ta.app.Router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCORSPolicyPerRouteGroup(t *testing.T) {
+	ta := newTestAppWithEnv(t, map[string]string{
+		"CORS_ORIGINS":                 "*",
+		"ADMIN_CORS_ORIGINS":           "https://admin.infocuy.id",
+		"ADMIN_CORS_MAX_AGE":           "5m",
+		"ADMIN_CORS_ALLOW_CREDENTIALS": "true",
+	})
+
+	tests := []struct {
+		name, path, origin                string
+		wantOrigin, wantMaxAge, wantCreds string
+	}{
+		{"publik dari origin mana pun", "/v1/locations", "https://app.example.com", "*", "43200", ""},
+		{"admin dari origin panel", "/v1/admin/audit-logs", "https://admin.infocuy.id", "https://admin.infocuy.id", "300", "true"},
+		{"admin dari origin lain", "/v1/admin/audit-logs", "https://app.example.com", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := preflight(ta, tt.path, tt.origin).Header()
+			if got := h.Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Allow-Origin %q, want %q", got, tt.wantOrigin)
+			}
+			if got := h.Get("Access-Control-Max-Age"); got != tt.wantMaxAge {
+				t.Errorf("Max-Age %q, want %q", got, tt.wantMaxAge)
+			}
+			if got := h.Get("Access-Control-Allow-Credentials"); got != tt.wantCreds {
+				t.Errorf("Allow-Credentials %q, want %q", got, tt.wantCreds)
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/categories", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rec := httptest.NewRecorder()
+	ta.app.Router.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Access-Control-Expose-Headers"); got == "" || !containsHeader(got, "Etag") || !containsHeader(got, "X-Request-Id") {
+		t.Errorf("Expose-Headers %q harus memuat ETag dan X-Request-ID", got)
+	}
+}
+
+func containsHeader(list, name string) bool {
+	for _, h := range strings.Split(list, ",") {
+		if strings.EqualFold(strings.TrimSpace(h), name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+func TestLocationCounters(t *testing.T) {
+	ta := newTestApp(t)
+	ctx := context.Background()
+	admin, user, other := ta.token(adminEmail), ta.token(userEmail), ta.token(otherEmail)
+	favorite := "/v1/locations/" + ta.location.ID.Hex() + "/favorite"
+
+	expect(t, ta.do(http.MethodPost, favorite, user, nil), http.StatusCreated, "")
+	expect(t, ta.do(http.MethodPost, favorite, other, nil), http.StatusCreated, "")
+	// Favorit ulang tidak menambah counter
+	expect(t, ta.do(http.MethodPost, favorite, other, nil), http.StatusOK, "")
+	expect(t, ta.do(http.MethodDelete, favorite, user, nil), http.StatusOK, "")
+	expect(t, ta.do(http.MethodDelete, favorite, user, nil), http.StatusOK, "")
+	expect(t, ta.do(http.MethodPost, favorite, admin, nil), http.StatusCreated, "")
+
+	count := func() int64 {
+		t.Helper()
+		loc, err := ta.store.Locations().FindByID(ctx, ta.location.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return loc.FavoritesCount
+	}
+	if n := count(); n != 2 {
+		t.Fatalf("favorites_count %d, want 2", n)
+	}
+
+	// Daftar diurutkan menurut counter tanpa menghitung ulang favorit
+	var list struct {
+		Data []models.MapViewItem `json:"data"`
+	}
+	rec := ta.do(http.MethodGet, "/v1/locations?sort=-favorites", "", nil)
+	expect(t, rec, http.StatusOK, "")
+	decode(t, rec, &list)
+	if len(list.Data) == 0 || list.Data[0].ID != ta.location.ID || list.Data[0].FavoritesCount != 2 {
+		t.Fatalf("sort favorites %+v", list.Data)
+	}
+
+	// Counter yang meleset diperbaiki job rekonsiliasi
+	drift := models.LocationCounters{ID: ta.location.ID, Favorites: 7, CheckIns: 3}
+	if err := ta.store.Locations().SetCounters(ctx, drift); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, ta.do(http.MethodPost, "/v1/admin/jobs/counter-reconcile", user, nil), http.StatusForbidden, "FORBIDDEN")
+	var result models.CounterReconcileResult
+	rec = ta.do(http.MethodPost, "/v1/admin/jobs/counter-reconcile?batch=2", admin, nil)
+	expect(t, rec, http.StatusOK, "")
+	decode(t, rec, &result)
+	for result.Next != "" {
+		rec = ta.do(http.MethodPost, "/v1/admin/jobs/counter-reconcile?batch=2&after="+result.Next, admin, nil)
+		expect(t, rec, http.StatusOK, "")
+		var next models.CounterReconcileResult
+		decode(t, rec, &next)
+		next.Fixed += result.Fixed
+		result = next
+	}
+	if result.Fixed != 1 || count() != 2 {
+		t.Fatalf("rekonsiliasi %+v, favorites_count %d", result, count())
+	}
+	loc, _ := ta.store.Locations().FindByID(ctx, ta.location.ID)
+	if loc.CheckInsCount != 0 {
+		t.Fatalf("checkins_count %d", loc.CheckInsCount)
+	}
+	expect(t, ta.do(http.MethodPost, "/v1/admin/jobs/counter-reconcile?after=bukan-id", admin, nil), http.StatusBadRequest, "")
+}
+
+func TestFavoriteUnlistedLocation(t *testing.T) {
+	ta := newTestApp(t)
+	user := ta.token(userEmail)
+	loc := createLocation(t, ta, user, "Warung Belum Tayang")
+	favorite := "/v1/locations/" + loc.ID.Hex() + "/favorite"
+
+	// Lokasi pending tidak ada bagi user lain, sama seperti di detail
+	expect(t, ta.do(http.MethodPost, favorite, ta.token(otherEmail), nil), http.StatusNotFound, "LOCATION_NOT_FOUND")
+	expect(t, ta.do(http.MethodPost, favorite, user, nil), http.StatusCreated, "")
+	expect(t, ta.do(http.MethodPost, favorite, ta.token(adminEmail), nil), http.StatusCreated, "")
+}
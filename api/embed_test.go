@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"InfoCuy-Backend/internal/apperr"
+	"InfoCuy-Backend/internal/models"
+)
+
+func TestLocationEmbed(t *testing.T) {
+	// Origin publik dibatasi, tetapi embed tetap boleh dibaca dari blog mana pun
+	ta := newTestAppWithEnv(t, map[string]string{"CORS_ORIGINS": "https://infocuy.id"})
+	path := "/v1/public/locations/" + ta.location.ID.Hex() + ".json"
+
+	get := func(header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path+"?lang=en", nil)
+		req.Header.Set("Origin", "https://blog.example.com")
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		ta.app.Router.ServeHTTP(rec, req)
+		return rec
+	}
+	rec := get("", "")
+	expect(t, rec, http.StatusOK, "")
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Allow-Origin %q", got)
+	}
+	if cc := rec.Header().Get("Cache-Control"); !strings.Contains(cc, "public") || !strings.Contains(cc, "max-age=") {
+		t.Errorf("Cache-Control %q", cc)
+	}
+	var embed models.LocationEmbed
+	decode(t, rec, &embed)
+	if embed.Version != models.LocationEmbedVersion || embed.ID != ta.location.ID || embed.Name != ta.location.Name || embed.Category.Slug != ta.location.Category {
+		t.Fatalf("embed %+v", embed)
+	}
+	// Hanya field publik, tidak dibungkus data
+	for _, field := range []string{"created_by", "moderation_status", `"data"`, "deleted_at"} {
+		if strings.Contains(rec.Body.String(), field) {
+			t.Errorf("embed memuat %s: %s", field, rec.Body)
+		}
+	}
+
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("ETag kosong")
+	}
+	if rec := get("If-None-Match", etag); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("revalidasi: %d %s", rec.Code, rec.Body)
+	}
+	if h := preflight(ta, path, "https://blog.example.com"); h.Code != http.StatusNoContent || h.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("preflight %d %v", h.Code, h.Header())
+	}
+
+	expect(t, ta.do(http.MethodGet, "/v1/public/locations/"+ta.location.ID.Hex(), "", nil), http.StatusNotFound, apperr.CodeRouteNotFound)
+	expect(t, ta.do(http.MethodGet, "/v1/public/locations/bukan-id.json", "", nil), http.StatusBadRequest, apperr.CodeInvalidObjectID)
+	expect(t, ta.do(http.MethodGet, path+"?lang=fr", "", nil), http.StatusBadRequest, "")
+	expect(t, ta.do(http.MethodDelete, "/v1/locations/"+ta.location.ID.Hex(), ta.token(userEmail), nil), http.StatusOK, "")
+	expect(t, ta.do(http.MethodGet, path, "", nil), http.StatusNotFound, "LOCATION_NOT_FOUND")
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+func TestEventLog(t *testing.T) {
+	ta := newTestApp(t)
+	admin, user := ta.token(adminEmail), ta.token(userEmail)
+
+	loc := createLocation(t, ta, admin, "Kopi Seq")
+	path := "/v1/locations/" + loc.ID.Hex()
+	expect(t, ta.do(http.MethodPut, path, admin, newLocationPayload("Kopi Seq Baru")), http.StatusOK, "")
+	expect(t, ta.do(http.MethodDelete, path, admin, nil), http.StatusOK, "")
+
+	type page struct {
+		Data    []models.Event `json:"data"`
+		NextSeq int64          `json:"next_seq"`
+		More    bool           `json:"more"`
+	}
+	list := func(query string) page {
+		t.Helper()
+		rec := ta.do(http.MethodGet, "/v1/events"+query, admin, nil)
+		expect(t, rec, http.StatusOK, "")
+		var p page
+		decode(t, rec, &p)
+		return p
+	}
+	expect(t, ta.do(http.MethodGet, "/v1/events", user, nil), http.StatusForbidden, "FORBIDDEN")
+	expect(t, ta.do(http.MethodGet, "/v1/events?after_seq=-1", admin, nil), http.StatusBadRequest, "VALIDATION_FAILED")
+
+	first := list("?limit=2")
+	if len(first.Data) != 2 || !first.More || first.NextSeq != 2 || first.Data[0].Seq != 1 || first.Data[0].Type != "location.create" {
+		t.Fatalf("halaman pertama %+v", first)
+	}
+	if first.Data[0].Actor != adminEmail || first.Data[0].LocationIDs[0] != loc.ID {
+		t.Fatalf("event create %+v", first.Data[0])
+	}
+	rest := list("?after_seq=2")
+	if len(rest.Data) != 1 || rest.More || rest.Data[0].Seq != 3 || rest.Data[0].Type != "location.delete" {
+		t.Fatalf("halaman berikutnya %+v", rest)
+	}
+
+	// Consumer menyimpan offset lalu melanjutkan dari sana
+	expect(t, ta.do(http.MethodGet, "/v1/events/consumers/proyeksi", admin, nil), http.StatusNotFound, "EVENT_CONSUMER_NOT_FOUND")
+	expect(t, ta.do(http.MethodPut, "/v1/events/consumers/proyeksi", admin, map[string]int{"seq": 9}), http.StatusBadRequest, "VALIDATION_FAILED")
+	expect(t, ta.do(http.MethodPut, "/v1/events/consumers/proyeksi", admin, map[string]int{"seq": 2}), http.StatusOK, "")
+	if resumed := list("?consumer=proyeksi"); len(resumed.Data) != 1 || resumed.Data[0].Seq != 3 {
+		t.Fatalf("lanjut dari offset %+v", resumed)
+	}
+	if replay := list("?consumer=proyeksi&after_seq=0"); len(replay.Data) != 3 {
+		t.Fatalf("replay dari awal %+v", replay)
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+func TestScheduledExportWebhook(t *testing.T) {
+	ta := newTestApp(t)
+	admin := ta.token(adminEmail)
+
+	var received struct {
+		Type     string            `json:"type"`
+		Features []json.RawMessage `json:"features"`
+	}
+	var signature, wantSignature string
+	status := http.StatusOK
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &received)
+		mac := hmac.New(sha256.New, []byte("rahasia"))
+		mac.Write(body)
+		signature, wantSignature = r.Header.Get("X-InfoCuy-Signature"), "sha256="+hex.EncodeToString(mac.Sum(nil))
+		w.WriteHeader(status)
+	}))
+	defer webhook.Close()
+
+	input := map[string]interface{}{
+		"name":      "Mingguan mitra",
+		"delivery":  map[string]string{"type": models.DeliverWebhook, "url": webhook.URL, "secret": "rahasia"},
+		"starts_at": time.Now().Add(-time.Minute),
+	}
+	expect(t, ta.do(http.MethodPost, "/v1/admin/scheduled-exports", ta.token(userEmail), input), http.StatusForbidden, "")
+	expect(t, ta.do(http.MethodPost, "/v1/admin/scheduled-exports", admin, map[string]interface{}{
+		"name": "S3", "delivery": map[string]string{"type": models.DeliverS3},
+	}), http.StatusBadRequest, "VALIDATION_FAILED")
+	rec := ta.do(http.MethodPost, "/v1/admin/scheduled-exports", admin, input)
+	expect(t, rec, http.StatusCreated, "")
+	var created struct {
+		Data models.ScheduledExport `json:"data"`
+	}
+	decode(t, rec, &created)
+	if created.Data.Format != "geojson" || created.Data.Interval != models.ExportWeekly || !created.Data.Enabled ||
+		created.Data.Delivery.Secret != "" || !created.Data.Delivery.HasSecret {
+		t.Fatalf("jadwal %+v", created.Data)
+	}
+
+	run := func() models.ScheduledExportResult {
+		t.Helper()
+		rec := ta.do(http.MethodPost, "/v1/admin/jobs/scheduled-exports", admin, nil)
+		expect(t, rec, http.StatusOK, "")
+		var result models.ScheduledExportResult
+		decode(t, rec, &result)
+		return result
+	}
+	if result := run(); result.Ran != 1 || result.Failed != 0 || result.Remaining != 0 {
+		t.Fatalf("job %+v", result)
+	}
+	_, listed, err := ta.store.Locations().List(context.Background(), repositories.LocationQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received.Type != "FeatureCollection" || int64(len(received.Features)) != listed {
+		t.Fatalf("webhook menerima %s dengan %d feature, lokasi tayang %d", received.Type, len(received.Features), listed)
+	}
+	if signature != wantSignature {
+		t.Fatalf("signature %q, want %q", signature, wantSignature)
+	}
+	// Sudah dijalankan: tidak jatuh tempo lagi sampai minggu depan
+	if result := run(); result.Ran != 0 {
+		t.Fatalf("job kedua %+v", result)
+	}
+
+	path := "/v1/admin/scheduled-exports/" + created.Data.ID.Hex()
+	rec = ta.do(http.MethodGet, path, admin, nil)
+	expect(t, rec, http.StatusOK, "")
+	var got struct {
+		Data models.ScheduledExport `json:"data"`
+	}
+	decode(t, rec, &got)
+	if got.Data.LastRun == nil || got.Data.LastRun.Status != models.ExportRunOK || got.Data.LastRun.Locations != listed ||
+		got.Data.NextRunAt.Before(time.Now().Add(6*24*time.Hour)) {
+		t.Fatalf("setelah dijalankan %+v (last_run %+v)", got.Data, got.Data.LastRun)
+	}
+
+	// Tujuan yang menolak dicatat sebagai failed tanpa menggagalkan request
+	status = http.StatusInternalServerError
+	rec = ta.do(http.MethodPost, path+"/run", admin, nil)
+	expect(t, rec, http.StatusOK, "")
+	var failed struct {
+		Data models.ExportRun `json:"data"`
+	}
+	decode(t, rec, &failed)
+	if failed.Data.Status != models.ExportRunFailed || failed.Data.Error == "" {
+		t.Fatalf("run gagal %+v", failed.Data)
+	}
+
+	expect(t, ta.do(http.MethodDelete, path, admin, nil), http.StatusOK, "")
+	expect(t, ta.do(http.MethodGet, path, admin, nil), http.StatusNotFound, "EXPORT_NOT_FOUND")
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+func TestFeaturedCarousel(t *testing.T) {
+	ta := newTestApp(t)
+	admin, user := ta.token(adminEmail), ta.token(userEmail)
+	locations, _, err := ta.store.Locations().List(context.Background(), repositories.LocationQuery{ListPage: repositories.ListPage{Field: "name", Limit: 100}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, second, later := locations[0], locations[1], locations[2]
+	pending := createLocation(t, ta, user, "Kopi Belum Tayang")
+	tomorrow := time.Now().Add(24 * time.Hour)
+
+	slot := func(id string, extra map[string]interface{}) map[string]interface{} {
+		s := map[string]interface{}{"location_id": id}
+		for k, v := range extra {
+			s[k] = v
+		}
+		return s
+	}
+	save := func(token string, slots ...map[string]interface{}) *httptest.ResponseRecorder {
+		t.Helper()
+		return ta.do(http.MethodPut, "/v1/admin/featured", token, map[string]interface{}{"slots": slots})
+	}
+	expect(t, save(user, slot(first.ID.Hex(), nil)), http.StatusForbidden, "FORBIDDEN")
+	expect(t, save(admin, slot(pending.ID.Hex(), nil)), http.StatusBadRequest, "VALIDATION_FAILED")
+	expect(t, save(admin, slot(first.ID.Hex(), nil), slot(first.ID.Hex(), nil)), http.StatusBadRequest, "VALIDATION_FAILED")
+
+	featured := func() []models.FeaturedItem {
+		t.Helper()
+		rec := ta.do(http.MethodGet, "/v1/locations/featured", "", nil)
+		expect(t, rec, http.StatusOK, "")
+		var body struct {
+			Data []models.FeaturedItem `json:"data"`
+		}
+		decode(t, rec, &body)
+		return body.Data
+	}
+	if items := featured(); len(items) != 0 {
+		t.Fatalf("carousel kosong %+v", items)
+	}
+
+	// Urutan body adalah urutan tampil; slot terjadwal belum tayang
+	expect(t, save(admin,
+		slot(second.ID.Hex(), map[string]interface{}{"caption": "Baru buka"}),
+		slot(first.ID.Hex(), nil),
+		slot(later.ID.Hex(), map[string]interface{}{"starts_at": tomorrow}),
+	), http.StatusOK, "")
+	items := featured()
+	if len(items) != 2 || items[0].Location.ID != second.ID || items[0].Caption != "Baru buka" || items[1].Location.ID != first.ID {
+		t.Fatalf("carousel %+v", items)
+	}
+
+	// Drag-order: kirim ulang dengan urutan baru, cache langsung dibuang
+	expect(t, save(admin, slot(first.ID.Hex(), nil), slot(second.ID.Hex(), nil)), http.StatusOK, "")
+	if items := featured(); len(items) != 2 || items[0].Location.ID != first.ID {
+		t.Fatalf("urutan baru %+v", items)
+	}
+
+	// Lokasi yang dihapus hilang dari carousel tapi tetap terlihat di admin
+	expect(t, ta.do(http.MethodDelete, "/v1/locations/"+first.ID.Hex(), admin, nil), http.StatusOK, "")
+	if items := featured(); len(items) != 1 || items[0].Location.ID != second.ID {
+		t.Fatalf("setelah hapus %+v", items)
+	}
+	rec := ta.do(http.MethodGet, "/v1/admin/featured", admin, nil)
+	expect(t, rec, http.StatusOK, "")
+	var entries struct {
+		Data []models.FeaturedEntry `json:"data"`
+	}
+	decode(t, rec, &entries)
+	if len(entries.Data) != 2 || entries.Data[0].Location != nil || entries.Data[0].Active || !entries.Data[1].Active || entries.Data[1].Position != 2 {
+		t.Fatalf("admin %+v", entries.Data)
+	}
+
+	rec = ta.do(http.MethodGet, "/v1/config", "", nil)
+	expect(t, rec, http.StatusOK, "")
+	var config struct {
+		Data models.PublicConfig `json:"data"`
+	}
+	decode(t, rec, &config)
+	if !config.Data.RegistrationOpen || len(config.Data.Featured) != 1 {
+		t.Fatalf("config %+v", config.Data)
+	}
+}
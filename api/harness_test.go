@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"InfoCuy-Backend/internal/auth"
+	"InfoCuy-Backend/internal/config"
+	"InfoCuy-Backend/internal/fakedata"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/rbac"
+	"InfoCuy-Backend/internal/repositories/memory"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Akun fixture; semua memakai fakedata.DefaultPassword
+const (
+	adminEmail = "admin@example.com"
+	userEmail  = "user@example.com"
+	otherEmail = "other@example.com"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	log.SetOutput(io.Discard)
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	os.Exit(m.Run())
+}
+
+// testApp adalah aplikasi lengkap (router, middleware, service) di atas
+// repository memori yang sudah berisi fixture.
+type testApp struct {
+	t      *testing.T
+	app    *App
+	store  *memory.Store
+	tokens map[string]string
+	// Lokasi fixture yang sudah tayang, dibuat oleh userEmail
+	location models.Location
+}
+
+// testConfig membaca config dari env minimal ditambah extra.
+func testConfig(t *testing.T, extra map[string]string) *config.Config {
+	t.Helper()
+	env := map[string]string{
+		"MONGO_URI":  "mongodb://localhost:27017",
+		"JWT_SECRET": "integration-test-secret",
+		// Rate limit dimatikan supaya test tidak saling memengaruhi
+		"RATE_LIMIT_PER_MIN":      "0",
+		"AUTH_RATE_LIMIT_PER_MIN": "0",
+	}
+	for k, v := range extra {
+		env[k] = v
+	}
+	cfg, err := config.FromLookup(func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func newTestApp(t *testing.T) *testApp {
+	t.Helper()
+	return newTestAppWithEnv(t, nil)
+}
+
+// newTestAppWithEnv sama seperti newTestApp dengan variabel config
+// tambahan, mis. CORS_ORIGINS.
+func newTestAppWithEnv(t *testing.T, env map[string]string) *testApp {
+	t.Helper()
+	return newTestAppWithRepos(t, env, nil)
+}
+
+// newTestAppWithRepos sama seperti newTestAppWithEnv, tetapi wrap boleh
+// mengganti repository (mis. dengan versi yang gagal) sebelum app dibuat.
+func newTestAppWithRepos(t *testing.T, env map[string]string, wrap func(*Repositories)) *testApp {
+	t.Helper()
+	store := memory.New()
+	repos := Repositories{
+		Locations:           store.Locations(),
+		Users:               store.Users(),
+		PasswordResets:      store.PasswordResets(),
+		Settings:            store.Settings(),
+		Roles:               store.Roles(),
+		Categories:          store.Categories(),
+		Audit:               store.Audit(),
+		Transit:             store.Transit(),
+		Regions:             store.Regions(),
+		Postcodes:           store.Postcodes(),
+		Reviews:             store.Reviews(),
+		Confirmations:       store.Confirmations(),
+		Campaigns:           store.Campaigns(),
+		MapView:             store.MapView(),
+		Favorites:           store.Favorites(),
+		Reassignments:       store.Reassignments(),
+		ImportJobs:          store.ImportJobs(),
+		CheckIns:            store.CheckIns(),
+		Promotions:          store.Promotions(),
+		Exports:             store.Exports(),
+		DeadLetters:         store.DeadLetters(),
+		CategorySuggestions: store.CategorySuggestions(),
+		Usage:               store.Usage(),
+		Events:              store.Events(),
+		Sessions:            store.Sessions(),
+		Redirects:           store.Redirects(),
+	}
+	if wrap != nil {
+		wrap(&repos)
+	}
+	ta := &testApp{t: t, app: Build(testConfig(t, env), repos, nil), store: store, tokens: map[string]string{}}
+	ta.seed(repos)
+	return ta
+}
+
+// seed mengisi kategori, tiga akun dengan role berbeda dan lokasi dari
+// fakedata (seed tetap supaya hasilnya bisa direproduksi).
+func (ta *testApp) seed(repos Repositories) {
+	ta.t.Helper()
+	ctx := context.Background()
+	for _, c := range fakedata.Categories() {
+		if err := repos.Categories.Create(ctx, &c); err != nil {
+			ta.t.Fatal(err)
+		}
+	}
+	hash, err := auth.HashPassword(fakedata.DefaultPassword)
+	if err != nil {
+		ta.t.Fatal(err)
+	}
+	users := []models.User{
+		{Email: adminEmail, Role: rbac.AdminRole},
+		{Email: userEmail, Role: rbac.DefaultRole},
+		{Email: otherEmail, Role: rbac.DefaultRole},
+	}
+	for i := range users {
+		users[i].Password = hash
+		if err := repos.Users.Create(ctx, &users[i]); err != nil {
+			ta.t.Fatal(err)
+		}
+	}
+	locs := fakedata.New(1).Locations(5, users[1:2])
+	if err := repos.Locations.CreateMany(ctx, locs); err != nil {
+		ta.t.Fatal(err)
+	}
+	ta.location = locs[0]
+}
+
+// token login sebagai email dan menyimpan access token-nya.
+func (ta *testApp) token(email string) string {
+	ta.t.Helper()
+	if tok, ok := ta.tokens[email]; ok {
+		return tok
+	}
+	rec := ta.do(http.MethodPost, "/v1/login", "", models.AuthInput{Email: email, Password: fakedata.DefaultPassword})
+	if rec.Code != http.StatusOK {
+		ta.t.Fatalf("login %s: %d %s", email, rec.Code, rec.Body)
+	}
+	var body struct {
+		Token auth.TokenPair `json:"token"`
+	}
+	decode(ta.t, rec, &body)
+	ta.tokens[email] = body.Token.AccessToken
+	return body.Token.AccessToken
+}
+
+// do mengirim request ke router. body di-encode sebagai JSON jika bukan
+// nil; token kosong berarti tanpa header Authorization.
+func (ta *testApp) do(method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	ta.t.Helper()
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			ta.t.Fatal(err)
+		}
+		r = bytes.NewReader(b)
+	}
+	req := httptest.NewRequest(method, path, r)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	ta.app.Router.ServeHTTP(rec, req)
+	return rec
+}
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder, out interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+		t.Fatalf("response bukan JSON: %v: %s", err, rec.Body)
+	}
+}
+
+// expect memastikan status response dan, untuk error, kode error-nya.
+func expect(t *testing.T, rec *httptest.ResponseRecorder, status int, code string) {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("status %d, want %d: %s", rec.Code, status, rec.Body)
+	}
+	if code == "" {
+		return
+	}
+	var body struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	decode(t, rec, &body)
+	if body.Error.Code != code {
+		t.Fatalf("error code %q, want %q: %s", body.Error.Code, code, rec.Body)
+	}
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+// uploadImport mengirim file CSV ke POST /v1/locations/import.
+func uploadImport(t *testing.T, ta *testApp, token, csv string) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreateFormFile("file", "lokasi.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte(csv))
+	w.Close()
+	req := httptest.NewRequest(http.MethodPost, "/v1/locations/import", &buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	ta.app.Router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestImportGeocoding(t *testing.T) {
+	// Nominatim palsu: hanya alamat di Jl. Braga yang ditemukan
+	nominatim := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Query().Get("q"), "Braga") {
+			w.Write([]byte(`[{"lat":"-6.9175","lon":"107.6091","display_name":"Jl. Braga, Bandung","address":{}}]`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer nominatim.Close()
+	t.Setenv("GEOCODE_PROVIDER", "nominatim")
+	t.Setenv("GEOCODE_NOMINATIM_URL", nominatim.URL)
+	t.Setenv("GEOCODE_RATE_PER_MIN", "6000")
+
+	ta := newTestApp(t)
+	admin, user, other := ta.token(adminEmail), ta.token(userEmail), ta.token(otherEmail)
+
+	csv := "name,category,address\n" +
+		"Kopi Braga,kafe,\"Jl. Braga No. 10, Bandung\"\n" +
+		"Kopi Hilang,kafe,Jalan Yang Tidak Ada\n"
+	rec := uploadImport(t, ta, user, csv)
+	expect(t, rec, http.StatusCreated, "")
+	var imported struct {
+		Data models.ImportResult `json:"data"`
+	}
+	decode(t, rec, &imported)
+	if imported.Data.Inserted != 0 || imported.Data.Geocoding != 2 || imported.Data.JobID == nil {
+		t.Fatalf("hasil import %+v", imported.Data)
+	}
+	jobPath := "/v1/locations/import-jobs/" + imported.Data.JobID.Hex()
+
+	job := func() models.ImportJob {
+		t.Helper()
+		rec := ta.do(http.MethodGet, jobPath, user, nil)
+		expect(t, rec, http.StatusOK, "")
+		var body struct {
+			Data models.ImportJob `json:"data"`
+		}
+		decode(t, rec, &body)
+		return body.Data
+	}
+	if j := job(); j.Status != models.ImportJobQueued || j.Pending != 2 {
+		t.Fatalf("job awal %+v", j)
+	}
+	expect(t, ta.do(http.MethodGet, jobPath, other, nil), http.StatusNotFound, "IMPORT_JOB_NOT_FOUND")
+	expect(t, ta.do(http.MethodPost, "/v1/admin/jobs/import-geocode", user, nil), http.StatusForbidden, "FORBIDDEN")
+
+	rec = ta.do(http.MethodPost, "/v1/admin/jobs/import-geocode", admin, nil)
+	expect(t, rec, http.StatusOK, "")
+	var batch models.GeocodeBatchResult
+	decode(t, rec, &batch)
+	if batch.Processed != 2 || batch.Geocoded != 1 || batch.Unresolved != 1 || batch.Queued != 0 {
+		t.Fatalf("batch %+v", batch)
+	}
+	j := job()
+	if j.Status != models.ImportJobDone || j.Geocoded != 1 || j.Unresolved != 1 || j.Pending != 0 {
+		t.Fatalf("job selesai %+v", j)
+	}
+	if j.Rows[0].Status != models.ImportRowGeocoded || j.Rows[1].Status != models.ImportRowUnresolved {
+		t.Fatalf("status baris %+v", j.Rows)
+	}
+	if _, err := ta.store.Locations().FindByID(t.Context(), j.Rows[0].LocationID); err != nil {
+		t.Fatalf("lokasi hasil geocoding: %v", err)
+	}
+
+	// Batch berikutnya tidak memproses apa pun
+	rec = ta.do(http.MethodPost, "/v1/admin/jobs/import-geocode", admin, nil)
+	var empty models.GeocodeBatchResult
+	decode(t, rec, &empty)
+	if empty.Processed != 0 || empty.JobID != nil {
+		t.Fatalf("antrean kosong %+v", empty)
+	}
+
+	unresolved := j.Rows[1]
+	place := jobPath + "/rows/" + strconv.Itoa(unresolved.Row) + "/place"
+	coords := map[string]interface{}{"coordinates": map[string]float64{"lat": -6.9, "lng": 107.6}}
+	expect(t, ta.do(http.MethodPost, jobPath+"/rows/"+strconv.Itoa(j.Rows[0].Row)+"/place", user, coords), http.StatusBadRequest, "VALIDATION_FAILED")
+	expect(t, ta.do(http.MethodPost, place, user, map[string]interface{}{"coordinates": map[string]float64{"lat": 91}}), http.StatusBadRequest, "VALIDATION_FAILED")
+	expect(t, ta.do(http.MethodPost, place, user, coords), http.StatusOK, "")
+	if j := job(); j.Unresolved != 0 || j.Placed != 1 || j.Rows[1].Status != models.ImportRowPlaced {
+		t.Fatalf("setelah ditempatkan %+v", j)
+	}
+	if _, err := ta.store.Locations().FindByID(t.Context(), unresolved.LocationID); err != nil {
+		t.Fatalf("lokasi ditempatkan: %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestIntegrityCheck(t *testing.T) {
+	s3 := &fakeS3{objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(s3)
+	defer server.Close()
+	for k, v := range map[string]string{"OBJECT_STORE": "s3", "S3_ENDPOINT": server.URL, "S3_BUCKET": "foto",
+		"S3_ACCESS_KEY_ID": "key", "S3_SECRET_ACCESS_KEY": "secret"} {
+		t.Setenv(k, v)
+	}
+	ta := newTestApp(t)
+	admin, user := ta.token(adminEmail), ta.token(userEmail)
+	ctx := t.Context()
+
+	// Lokasi milik akun yang sudah dihapus, dengan satu foto yang object-nya
+	// hilang dan satu yang masih ada
+	orphaned := models.Location{Name: "Warung Tanpa Pemilik", Category: ta.location.Category, Coordinates: ta.location.Coordinates,
+		CreatedBy: "hilang@example.com", ModerationStatus: models.ModerationApproved}
+	kept := models.Photo{ID: primitive.NewObjectID(), Store: "s3", Key: "locations/ada.png"}
+	lost := models.Photo{ID: primitive.NewObjectID(), Store: "s3", Key: "locations/hilang.png"}
+	orphaned.Photos = []models.Photo{kept, lost}
+	if err := ta.store.Locations().Create(ctx, &orphaned); err != nil {
+		t.Fatal(err)
+	}
+	s3.objects["/foto/"+kept.Key], s3.types["/foto/"+kept.Key] = []byte("png"), "image/png"
+	// Ulasan dan favorit untuk lokasi yang sudah di-purge
+	purged := primitive.NewObjectID()
+	review := models.Review{LocationID: purged, UserID: primitive.NewObjectID(), Rating: 4}
+	if err := ta.store.Reviews().Create(ctx, &review); err != nil {
+		t.Fatal(err)
+	}
+	if err := ta.store.Favorites().Add(ctx, &models.Favorite{LocationID: purged, UserID: primitive.NewObjectID()}); err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, ta.do(http.MethodGet, "/v1/admin/integrity-check", admin, nil), http.StatusNotFound, "INTEGRITY_REPORT_NOT_FOUND")
+	expect(t, ta.do(http.MethodPost, "/v1/admin/integrity-check", user, nil), http.StatusForbidden, "FORBIDDEN")
+
+	check := func() models.IntegrityReport {
+		t.Helper()
+		rec := ta.do(http.MethodPost, "/v1/admin/integrity-check", admin, nil)
+		expect(t, rec, http.StatusOK, "")
+		var body struct {
+			Data models.IntegrityReport `json:"data"`
+		}
+		decode(t, rec, &body)
+		return body.Data
+	}
+	report := check()
+	kinds := map[string]models.IntegrityIssue{}
+	for _, issue := range report.Issues {
+		kinds[issue.Kind] = issue
+	}
+	if len(report.Issues) != 4 || report.PhotosChecked != 2 {
+		t.Fatalf("laporan %+v", report)
+	}
+	if c := kinds[models.IntegrityCreatorMissing]; c.Email != "hilang@example.com" || c.Count != 1 || c.Repair != models.RepairReassign {
+		t.Fatalf("pembuat hilang %+v", c)
+	}
+	if p := kinds[models.IntegrityPhotoMissing]; p.PhotoID == nil || *p.PhotoID != lost.ID {
+		t.Fatalf("foto hilang %+v", p)
+	}
+	if r := kinds[models.IntegrityReviewOrphan]; r.LocationID == nil || *r.LocationID != purged || r.Count != 1 {
+		t.Fatalf("ulasan yatim %+v", r)
+	}
+
+	repair := func(body map[string]interface{}, status int, code string) models.IntegrityIssue {
+		t.Helper()
+		rec := ta.do(http.MethodPost, "/v1/admin/integrity-check/repair", admin, body)
+		expect(t, rec, status, code)
+		var res struct {
+			Data models.IntegrityIssue `json:"data"`
+		}
+		decode(t, rec, &res)
+		return res.Data
+	}
+	repair(map[string]interface{}{"issue": "tidak_ada:1"}, http.StatusNotFound, "INTEGRITY_ISSUE_NOT_FOUND")
+	photo := kinds[models.IntegrityPhotoMissing].ID
+	if fixed := repair(map[string]interface{}{"issue": photo}, http.StatusOK, ""); fixed.RepairedAt == nil || fixed.RepairedBy != adminEmail {
+		t.Fatalf("perbaikan foto %+v", fixed)
+	}
+	repair(map[string]interface{}{"issue": photo}, http.StatusConflict, "ALREADY_REPAIRED")
+	loc, err := ta.store.Locations().FindByID(ctx, orphaned.ID)
+	if err != nil || len(loc.Photos) != 1 || loc.Photos[0].ID != kept.ID {
+		t.Fatalf("foto setelah perbaikan %+v %v", loc, err)
+	}
+
+	repair(map[string]interface{}{"issue": kinds[models.IntegrityReviewOrphan].ID}, http.StatusOK, "")
+	if _, err := ta.store.Reviews().FindByID(ctx, review.ID); err == nil {
+		t.Fatal("ulasan yatim masih ada")
+	}
+	fixed := repair(map[string]interface{}{"issue": kinds[models.IntegrityCreatorMissing].ID, "target": userEmail}, http.StatusOK, "")
+	if !strings.Contains(fixed.Result, userEmail) {
+		t.Fatalf("perbaikan pemilik %+v", fixed)
+	}
+	expect(t, ta.do(http.MethodPost, "/v1/admin/jobs/location-reassign", admin, nil), http.StatusOK, "")
+	if loc, _ := ta.store.Locations().FindByID(ctx, orphaned.ID); loc.CreatedBy != userEmail {
+		t.Fatalf("pemilik setelah reassign %s", loc.CreatedBy)
+	}
+
+	// Hanya favorit yatim yang belum diperbaiki
+	report = check()
+	if len(report.Issues) != 1 || report.Issues[0].Kind != models.IntegrityFavoriteOrphan {
+		t.Fatalf("laporan ulang %+v", report.Issues)
+	}
+	rec := ta.do(http.MethodGet, "/v1/admin/integrity-check", admin, nil)
+	expect(t, rec, http.StatusOK, "")
+	var stored struct {
+		Data models.IntegrityReport `json:"data"`
+	}
+	decode(t, rec, &stored)
+	if !slices.EqualFunc(stored.Data.Issues, report.Issues, func(a, b models.IntegrityIssue) bool { return a.ID == b.ID }) {
+		t.Fatalf("laporan tersimpan %+v", stored.Data.Issues)
+	}
+}
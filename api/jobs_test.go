@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"InfoCuy-Backend/internal/jobs"
+)
+
+func TestJobQueuePriority(t *testing.T) {
+	q := jobs.New(jobs.Limits{Workers: 2, Caps: map[jobs.Class]int{jobs.Backfill: 1}})
+	ctx := context.Background()
+
+	backfill, err := q.Acquire(ctx, jobs.Backfill)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Backfill kedua tertahan batas kelasnya walaupun masih ada worker kosong
+	short, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	_, err = q.Acquire(short, jobs.Backfill)
+	cancel()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("backfill kedua: %v", err)
+	}
+	email, err := q.Acquire(ctx, jobs.Email)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Semua worker terpakai: webhook mengantre lebih dulu, tetapi email
+	// yang datang belakangan mendapat slot pertama
+	order := make(chan jobs.Class, 2)
+	wait := func(class jobs.Class) {
+		release, err := q.Acquire(ctx, class)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		order <- class
+		release()
+	}
+	go wait(jobs.Webhook)
+	waitFor(t, func() bool { return q.Stats()[jobs.Webhook].Waiting == 1 })
+	go wait(jobs.Email)
+	waitFor(t, func() bool { return q.Stats()[jobs.Email].Waiting == 1 })
+
+	email()
+	email() // release kedua tidak berefek
+	if first := <-order; first != jobs.Email {
+		t.Fatalf("slot pertama untuk %s", first)
+	}
+	if second := <-order; second != jobs.Webhook {
+		t.Fatalf("slot kedua untuk %s", second)
+	}
+	backfill()
+	for class, s := range q.Stats() {
+		if s.Running != 0 || s.Waiting != 0 {
+			t.Errorf("%s masih %+v", class, s)
+		}
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("kondisi tidak tercapai")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"InfoCuy-Backend/internal/apperr"
+	"InfoCuy-Backend/internal/models"
+)
+
+// facetsIn mengambil facet lokasi kafe dengan header Accept-Language.
+func facetsIn(t *testing.T, ta *testApp, acceptLanguage string) models.LocationFacets {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/v1/locations/facets?category=kafe", nil)
+	req.Header.Set("Accept-Language", acceptLanguage)
+	rec := httptest.NewRecorder()
+	ta.app.Router.ServeHTTP(rec, req)
+	expect(t, rec, http.StatusOK, "")
+	var body struct {
+		Data models.LocationFacets `json:"data"`
+	}
+	decode(t, rec, &body)
+	return body.Data
+}
+
+func TestLocalizedLabels(t *testing.T) {
+	ta := newTestApp(t)
+	admin := ta.token(adminEmail)
+
+	payload := newLocationPayload("Kopi Murah")
+	payload["price_range"] = "budget"
+	expect(t, ta.do(http.MethodPost, "/v1/locations", admin, payload), http.StatusCreated, "")
+	category := map[string]interface{}{"name": "Kafe", "labels": models.Labels{"id": "Kafe", "en": "Cafe"}}
+	expect(t, ta.do(http.MethodPut, "/v1/categories/kafe", admin, category), http.StatusOK, "")
+
+	facets := facetsIn(t, ta, "en-US,en;q=0.9,id;q=0.8")
+	if facets.Category[0].Label != "Cafe" || facets.PriceRange[0].Label != "Budget" {
+		t.Fatalf("en: %+v", facets)
+	}
+	facets = facetsIn(t, ta, "fr")
+	if facets.Category[0].Label != "Kafe" || facets.PriceRange[0].Label != "Murah" {
+		t.Fatalf("default id: %+v", facets)
+	}
+
+	labels := models.AttributeLabels{Attributes: map[string]map[string]models.Labels{"price_range": {"budget": {"en": "Cheap"}}}}
+	expect(t, ta.do(http.MethodPut, "/v1/admin/settings/attribute-labels", admin, labels), http.StatusOK, "")
+	if got := facetsIn(t, ta, "en").PriceRange[0].Label; got != "Cheap" {
+		t.Errorf("label setelah diubah %q", got)
+	}
+
+	labels.Attributes["price_range"]["gratis"] = models.Labels{"en": "Free"}
+	expect(t, ta.do(http.MethodPut, "/v1/admin/settings/attribute-labels", admin, labels), http.StatusBadRequest, apperr.CodeValidation)
+	category["labels"] = models.Labels{"fr": "Café"}
+	expect(t, ta.do(http.MethodPut, "/v1/categories/kafe", admin, category), http.StatusBadRequest, apperr.CodeValidation)
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"InfoCuy-Backend/internal/deprecation"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+func TestLegacyEmailAuth(t *testing.T) {
+	legacy := func(ta *testApp, email, token string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/v1/me/preferences", nil)
+		req.Header.Set("X-User-Email", email)
+		req.Header.Set("User-Agent", "infocuy-web/1.4")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		ta.app.Router.ServeHTTP(rec, req)
+		if rec.Header().Get("Deprecation") != "true" || !strings.HasPrefix(rec.Header().Get("Warning"), "299 ") {
+			t.Fatalf("header deprecation %v", rec.Header())
+		}
+		return rec
+	}
+
+	// Tanpa masa transisi header lama ditolak, tapi client tetap tercatat
+	ta := newTestApp(t)
+	expect(t, legacy(ta, userEmail, ""), http.StatusUnauthorized, "UNAUTHORIZED")
+
+	// Tanggal sunset dibaca package deprecation langsung dari environment
+	t.Setenv("LEGACY_SUNSET_ROUTES", "header:X-User-Email=2026-12-31")
+	ta = newTestAppWithEnv(t, map[string]string{"LEGACY_EMAIL_AUTH": "true"})
+	rec := legacy(ta, userEmail, "")
+	expect(t, rec, http.StatusOK, "")
+	if rec.Header().Get("Sunset") == "" {
+		t.Fatalf("sunset %v", rec.Header())
+	}
+	legacy(ta, userEmail, "")
+	expect(t, legacy(ta, "tidak-ada@example.com", ""), http.StatusUnauthorized, "")
+	// Client yang sudah memakai bearer token tidak masuk daftar
+	expect(t, legacy(ta, otherEmail, ta.token(otherEmail)), http.StatusOK, "")
+
+	var report struct {
+		Clients         []deprecation.Client `json:"clients"`
+		LegacyEmailAuth bool                 `json:"legacy_email_auth"`
+	}
+	rec = ta.do(http.MethodGet, "/v1/admin/deprecations", ta.token(adminEmail), nil)
+	expect(t, rec, http.StatusOK, "")
+	decode(t, rec, &report)
+	if !report.LegacyEmailAuth || len(report.Clients) != 1 {
+		t.Fatalf("laporan %+v", report)
+	}
+	if c := report.Clients[0]; c.UserAgent != "infocuy-web/1.4" || c.Hits != 3 || c.IP == "" {
+		t.Fatalf("client %+v", c)
+	}
+
+	// Header lama tunduk pada kunci akun, wajib reset dan pencabutan sesi
+	ctx := context.Background()
+	set := func(email string, fields repositories.Fields) {
+		t.Helper()
+		u, err := ta.store.Users().FindByEmail(ctx, email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ta.store.Users().Update(ctx, u.ID, fields); err != nil {
+			t.Fatal(err)
+		}
+	}
+	set(userEmail, repositories.Fields{"locked_until": time.Now().Add(time.Hour)})
+	expect(t, legacy(ta, userEmail, ""), http.StatusTooManyRequests, "ACCOUNT_LOCKED")
+	set(userEmail, repositories.Fields{"locked_until": nil, "password_reset_required": true})
+	expect(t, legacy(ta, userEmail, ""), http.StatusForbidden, "PASSWORD_RESET_REQUIRED")
+	set(otherEmail, repositories.Fields{"sessions_valid_after": time.Now().Add(-time.Hour)})
+	expect(t, legacy(ta, otherEmail, ""), http.StatusUnauthorized, "")
+	// Bearer token baru tetap berlaku
+	expect(t, ta.do(http.MethodGet, "/v1/me/preferences", ta.token(otherEmail), nil), http.StatusOK, "")
+}
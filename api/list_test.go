@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestListCursor(t *testing.T) {
+	ta := newTestApp(t)
+	admin := ta.token(adminEmail)
+	for _, name := range []string{"Kopi A", "Kopi B", "Kopi C", "Kopi D"} {
+		createLocation(t, ta, admin, name)
+	}
+	type listBody struct {
+		Data []models.MapViewItem `json:"data"`
+		Meta models.PageMeta      `json:"meta"`
+	}
+	list := func(path string) listBody {
+		t.Helper()
+		rec := ta.do(http.MethodGet, path, "", nil)
+		expect(t, rec, http.StatusOK, "")
+		var body listBody
+		decode(t, rec, &body)
+		return body
+	}
+
+	all := list("/v1/locations?sort=-name&limit=100")
+	var want []primitive.ObjectID
+	for _, item := range all.Data {
+		want = append(want, item.ID)
+	}
+
+	// Menelusuri dengan cursor menghasilkan urutan yang sama dengan page
+	var got []primitive.ObjectID
+	path := "/v1/locations?sort=-name&limit=2"
+	for i := 0; ; i++ {
+		page := list(path)
+		if i > 0 && page.Meta.Page != 0 {
+			t.Fatalf("meta.page lewat cursor %d", page.Meta.Page)
+		}
+		for _, item := range page.Data {
+			got = append(got, item.ID)
+		}
+		if page.Meta.NextCursor == "" || i > len(want) {
+			break
+		}
+		path = "/v1/locations?sort=-name&limit=2&cursor=" + page.Meta.NextCursor
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("cursor %v, want %v", got, want)
+	}
+
+	first := list("/v1/locations?sort=-name&limit=2")
+	invalid := func(path, field, rule string) {
+		t.Helper()
+		rec := ta.do(http.MethodGet, path, admin, nil)
+		expect(t, rec, http.StatusBadRequest, "VALIDATION_FAILED")
+		var failed struct {
+			Error struct {
+				Fields []struct{ Field, Rule string } `json:"fields"`
+			} `json:"error"`
+		}
+		decode(t, rec, &failed)
+		if len(failed.Error.Fields) != 1 || failed.Error.Fields[0].Field != field || failed.Error.Fields[0].Rule != rule {
+			t.Fatalf("validasi %+v", failed.Error.Fields)
+		}
+	}
+	invalid("/v1/locations?sort=address", "sort", "one_of")
+	invalid("/v1/locations?sort=name&cursor="+first.Meta.NextCursor, "cursor", "cursor")
+	invalid("/v1/locations?cursor=bukan-cursor", "cursor", "cursor")
+	invalid("/v1/users/me/favorites?cursor="+first.Meta.NextCursor, "cursor", "cursor")
+
+	if page := list("/v1/locations?limit=100000"); page.Meta.Limit >= 100000 || len(page.Data) > page.Meta.Limit {
+		t.Fatalf("limit tidak dibatasi: %+v", page.Meta)
+	}
+}
@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"InfoCuy-Backend/internal/apperr"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/services"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newLocationPayload(name string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        name,
+		"category":    "kafe",
+		"address":     "Jl. Braga No. 1, Bandung",
+		"coordinates": map[string]float64{"lat": -6.9175, "lng": 107.6091},
+	}
+}
+
+func createLocation(t *testing.T, ta *testApp, token, name string) models.Location {
+	t.Helper()
+	rec := ta.do(http.MethodPost, "/v1/locations", token, newLocationPayload(name))
+	expect(t, rec, http.StatusCreated, "")
+	var body struct {
+		Data models.Location `json:"data"`
+	}
+	decode(t, rec, &body)
+	return body.Data
+}
+
+func listLocations(t *testing.T, ta *testApp, path string) []models.Location {
+	t.Helper()
+	rec := ta.do(http.MethodGet, path, "", nil)
+	expect(t, rec, http.StatusOK, "")
+	var body struct {
+		Data []models.Location `json:"data"`
+	}
+	decode(t, rec, &body)
+	return body.Data
+}
+
+func TestLocationCRUD(t *testing.T) {
+	ta := newTestApp(t)
+	admin := ta.token(adminEmail)
+
+	loc := createLocation(t, ta, admin, "Kopi Braga")
+	if loc.CreatedBy != adminEmail || loc.ModerationStatus != models.ModerationApproved {
+		t.Fatalf("created_by %q, moderation_status %q", loc.CreatedBy, loc.ModerationStatus)
+	}
+	path := "/v1/locations/" + loc.ID.Hex()
+
+	rec := ta.do(http.MethodGet, path, "", nil)
+	expect(t, rec, http.StatusOK, "")
+	var got struct {
+		Data models.Location `json:"data"`
+	}
+	decode(t, rec, &got)
+	if got.Data.Name != "Kopi Braga" {
+		t.Errorf("name %q", got.Data.Name)
+	}
+
+	update := newLocationPayload("Kopi Braga Baru")
+	expect(t, ta.do(http.MethodPut, path, admin, update), http.StatusOK, "")
+	found := false
+	for _, l := range listLocations(t, ta, "/v1/locations?q=braga") {
+		found = found || (l.ID == loc.ID && l.Name == "Kopi Braga Baru")
+	}
+	if !found {
+		t.Error("lokasi yang diubah tidak muncul di daftar")
+	}
+
+	expect(t, ta.do(http.MethodDelete, path, admin, nil), http.StatusOK, "")
+	expect(t, ta.do(http.MethodGet, path, "", nil), http.StatusNotFound, "LOCATION_NOT_FOUND")
+	expect(t, ta.do(http.MethodDelete, path, admin, nil), http.StatusNotFound, "LOCATION_NOT_FOUND")
+}
+
+func TestLocationValidation(t *testing.T) {
+	ta := newTestApp(t)
+	token := ta.token(userEmail)
+
+	payload := newLocationPayload("")
+	delete(payload, "coordinates")
+	expect(t, ta.do(http.MethodPost, "/v1/locations", token, payload), http.StatusBadRequest, apperr.CodeValidation)
+
+	payload = newLocationPayload("Kategori Asing")
+	payload["category"] = "tidak-ada"
+	expect(t, ta.do(http.MethodPost, "/v1/locations", token, payload), http.StatusBadRequest, apperr.CodeValidation)
+
+	expect(t, ta.do(http.MethodGet, "/v1/locations/bukan-id", "", nil), http.StatusBadRequest, apperr.CodeInvalidObjectID)
+	expect(t, ta.do(http.MethodPost, "/v1/locations", "", newLocationPayload("Tanpa Login")), http.StatusUnauthorized, apperr.CodeUnauthorized)
+}
+
+func TestValidationMessagesFollowLanguage(t *testing.T) {
+	ta := newTestApp(t)
+	payload := newLocationPayload("")
+	payload["coordinates"] = map[string]float64{"lat": -100, "lng": 107.6}
+	body, _ := json.Marshal(payload)
+	req := httptest.NewRequest(http.MethodPost, "/v1/locations", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+ta.token(userEmail))
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	rec := httptest.NewRecorder()
+	ta.app.Router.ServeHTTP(rec, req)
+	expect(t, rec, http.StatusBadRequest, apperr.CodeValidation)
+
+	var resp struct {
+		Error struct {
+			Fields []services.FieldError `json:"fields"`
+		} `json:"error"`
+	}
+	decode(t, rec, &resp)
+	got := map[string]services.FieldError{}
+	for _, f := range resp.Error.Fields {
+		got[f.Field] = f
+	}
+	if f := got["name"]; f.Rule != services.RuleRequired || f.Message != "is required" {
+		t.Fatalf("name: %+v", f)
+	}
+	lat := got["coordinates.lat"]
+	if lat.Rule != services.RuleRange || lat.Params["min"] != -90.0 || lat.Params["max"] != 90.0 || lat.Message != "must be between -90 and 90" {
+		t.Fatalf("coordinates.lat: %+v", lat)
+	}
+}
+
+func TestLocationOwnership(t *testing.T) {
+	ta := newTestApp(t)
+	// Lokasi fixture dibuat oleh userEmail
+	path := "/v1/locations/" + ta.location.ID.Hex()
+
+	other := ta.token(otherEmail)
+	expect(t, ta.do(http.MethodPut, path, other, newLocationPayload("Diambil Alih")), http.StatusForbidden, apperr.CodeForbidden)
+	expect(t, ta.do(http.MethodDelete, path, other, nil), http.StatusForbidden, apperr.CodeForbidden)
+
+	expect(t, ta.do(http.MethodPut, path, ta.token(userEmail), newLocationPayload("Milik Sendiri")), http.StatusOK, "")
+	// Admin punya locations:delete_any
+	expect(t, ta.do(http.MethodDelete, path, ta.token(adminEmail), nil), http.StatusOK, "")
+}
+
+func TestPendingLocationIsNotListed(t *testing.T) {
+	ta := newTestApp(t)
+	user := ta.token(userEmail)
+
+	// User biasa tidak punya locations:moderate, lokasinya menunggu review
+	loc := createLocation(t, ta, user, "Warung Menunggu Review")
+	if loc.ModerationStatus != models.ModerationPending {
+		t.Fatalf("moderation_status %q, want pending", loc.ModerationStatus)
+	}
+	for _, l := range listLocations(t, ta, "/v1/locations?q=menunggu") {
+		if l.ID == loc.ID {
+			t.Fatal("lokasi pending muncul di daftar publik")
+		}
+	}
+	path := "/v1/locations/" + loc.ID.Hex()
+	expect(t, ta.do(http.MethodGet, path, "", nil), http.StatusNotFound, "LOCATION_NOT_FOUND")
+	expect(t, ta.do(http.MethodGet, path, ta.token(otherEmail), nil), http.StatusNotFound, "LOCATION_NOT_FOUND")
+	// Pembuatnya tetap bisa melihat
+	expect(t, ta.do(http.MethodGet, path, user, nil), http.StatusOK, "")
+}
+
+func TestEditedLocationNeedsReview(t *testing.T) {
+	ta := newTestApp(t)
+	user, admin := ta.token(userEmail), ta.token(adminEmail)
+
+	loc := createLocation(t, ta, user, "Warung Disunting")
+	path := "/v1/locations/" + loc.ID.Hex()
+	expect(t, ta.do(http.MethodPost, "/v1/admin/locations/"+loc.ID.Hex()+"/approve", admin, nil), http.StatusOK, "")
+	expect(t, ta.do(http.MethodGet, path, "", nil), http.StatusOK, "")
+
+	// Suntingan pemilik tanpa locations:moderate kembali ke antrean review
+	expect(t, ta.do(http.MethodPut, path, user, newLocationPayload("Warung Disunting Ulang")), http.StatusOK, "")
+	expect(t, ta.do(http.MethodGet, path, "", nil), http.StatusNotFound, "LOCATION_NOT_FOUND")
+	rec := ta.do(http.MethodGet, "/v1/admin/locations/pending", admin, nil)
+	expect(t, rec, http.StatusOK, "")
+	var pending struct {
+		Data []models.Location `json:"data"`
+	}
+	decode(t, rec, &pending)
+	if len(pending.Data) != 1 || pending.Data[0].ID != loc.ID || pending.Data[0].Name != "Warung Disunting Ulang" {
+		t.Fatalf("antrean pending %+v", pending.Data)
+	}
+
+	// Suntingan moderator tidak menahan lokasi
+	expect(t, ta.do(http.MethodPost, "/v1/admin/locations/"+loc.ID.Hex()+"/approve", admin, nil), http.StatusOK, "")
+	expect(t, ta.do(http.MethodPut, path, admin, newLocationPayload("Warung Dirapikan")), http.StatusOK, "")
+	expect(t, ta.do(http.MethodGet, path, "", nil), http.StatusOK, "")
+}
+
+func TestDistanceMatrix(t *testing.T) {
+	ta := newTestApp(t)
+	origin := ta.location.Coordinates
+	point := models.Coordinates{Lat: origin.Lat + 0.01, Lng: origin.Lng}
+	body := map[string]interface{}{
+		"origins":      []interface{}{map[string]interface{}{"location_id": ta.location.ID}},
+		"destinations": []interface{}{map[string]interface{}{"coordinates": point}, map[string]interface{}{"location_id": ta.location.ID}},
+	}
+	rec := ta.do(http.MethodPost, "/v1/distance/matrix?units=metric", "", body)
+	expect(t, rec, http.StatusOK, "")
+	var res struct {
+		Data struct {
+			Origins   []models.MatrixPoint `json:"origins"`
+			Distances [][]struct {
+				Meters float64 `json:"meters"`
+				Unit   string  `json:"unit"`
+			} `json:"distances"`
+		} `json:"data"`
+	}
+	decode(t, rec, &res)
+	d := res.Data.Distances
+	// 0,01° lintang kira-kira 1,1 km
+	if len(d) != 1 || len(d[0]) != 2 || d[0][0].Meters < 1100 || d[0][0].Meters > 1125 || d[0][0].Unit != "km" || d[0][1].Meters != 0 {
+		t.Fatalf("matriks %+v", d)
+	}
+	if res.Data.Origins[0].Name != ta.location.Name {
+		t.Fatalf("origin tidak dilengkapi: %+v", res.Data.Origins[0])
+	}
+
+	invalidPoint := func(origin map[string]interface{}, field, rule string) {
+		t.Helper()
+		body["origins"] = []interface{}{origin}
+		rec := ta.do(http.MethodPost, "/v1/distance/matrix", "", body)
+		expect(t, rec, http.StatusBadRequest, "VALIDATION_FAILED")
+		var failed struct {
+			Error struct {
+				Fields []struct{ Field, Rule string } `json:"fields"`
+			} `json:"error"`
+		}
+		decode(t, rec, &failed)
+		if len(failed.Error.Fields) != 1 || failed.Error.Fields[0].Field != field || failed.Error.Fields[0].Rule != rule {
+			t.Fatalf("validasi %+v", failed.Error.Fields)
+		}
+	}
+	invalidPoint(map[string]interface{}{}, "origins.0", "require_one")
+	invalidPoint(map[string]interface{}{"location_id": primitive.NewObjectID()}, "origins.0.location_id", "not_found")
+}
+
+func TestCompareLocations(t *testing.T) {
+	ta := newTestApp(t)
+	user := ta.token(userEmail)
+	other := createLocation(t, ta, user, "Kopi Pembanding")
+	ref := ta.location.Coordinates
+	path := "/v1/locations/compare?units=metric&ids=" + ta.location.ID.Hex() + "," + other.ID.Hex() +
+		fmt.Sprintf("&lat=%v&lng=%v", ref.Lat, ref.Lng)
+	rec := ta.do(http.MethodGet, path, user, nil)
+	expect(t, rec, http.StatusOK, "")
+	var res struct {
+		Data models.LocationComparison `json:"data"`
+	}
+	decode(t, rec, &res)
+	locs := res.Data.Locations
+	if len(locs) != 2 || locs[0].ID != ta.location.ID || locs[1].ID != other.ID {
+		t.Fatalf("urutan lokasi %+v", locs)
+	}
+	if locs[0].Distance == nil || locs[0].Distance.Meters != 0 || locs[1].Distance == nil || locs[1].PaymentMethods == nil {
+		t.Fatalf("kolom perbandingan %+v", locs)
+	}
+	if !slices.Contains(res.Data.Differences, "distance") {
+		t.Fatalf("differences %v", res.Data.Differences)
+	}
+
+	expect(t, ta.do(http.MethodGet, "/v1/locations/compare?ids="+other.ID.Hex(), "", nil), http.StatusBadRequest, "VALIDATION_FAILED")
+	expect(t, ta.do(http.MethodGet, "/v1/locations/compare?ids="+other.ID.Hex()+","+other.ID.Hex(), "", nil), http.StatusBadRequest, "VALIDATION_FAILED")
+	expect(t, ta.do(http.MethodGet, "/v1/locations/compare?ids="+other.ID.Hex()+",bukan-id", "", nil), http.StatusBadRequest, "INVALID_OBJECT_ID")
+	expect(t, ta.do(http.MethodGet, "/v1/locations/compare?ids="+other.ID.Hex()+","+primitive.NewObjectID().Hex(), "", nil), http.StatusBadRequest, "VALIDATION_FAILED")
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMailDeadLetterResend(t *testing.T) {
+	// SendGrid palsu: gangguan dulu, lalu pulih
+	var down atomic.Bool
+	down.Store(true)
+	var sent []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer sg-test" {
+			t.Errorf("authorization: %q", r.Header.Get("Authorization"))
+		}
+		if down.Load() {
+			http.Error(w, "maintenance", http.StatusServiceUnavailable)
+			return
+		}
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		sent = append(sent, payload)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+	t.Setenv("MAIL_PROVIDER", "sendgrid")
+	t.Setenv("SENDGRID_API_KEY", "sg-test")
+	t.Setenv("SENDGRID_BASE_URL", srv.URL)
+	ta := newTestApp(t)
+	admin := ta.token(adminEmail)
+
+	// Permintaan user tetap 200; email yang gagal masuk dead letter
+	rec := ta.do(http.MethodPost, "/v1/password-reset", "", map[string]string{"email": userEmail})
+	expect(t, rec, http.StatusOK, "")
+	var list struct {
+		Data []struct {
+			ID       string `json:"id"`
+			To       string `json:"to"`
+			Attempts int    `json:"attempts"`
+			Error    string `json:"error"`
+			Rejected bool   `json:"rejected"`
+		} `json:"data"`
+		Provider string `json:"provider"`
+	}
+	// Email reset dikirim di belakang request
+	waitFor(t, func() bool {
+		rec = ta.do(http.MethodGet, "/v1/admin/mail/dead-letters", admin, nil)
+		expect(t, rec, http.StatusOK, "")
+		decode(t, rec, &list)
+		return len(list.Data) > 0
+	})
+	if list.Provider != "sendgrid" || len(list.Data) != 1 || list.Data[0].To != userEmail || list.Data[0].Rejected {
+		t.Fatalf("dead letters: %+v", list)
+	}
+	if strings.Contains(rec.Body.String(), "mengatur ulang password") {
+		t.Fatalf("isi email bocor di daftar: %s", rec.Body)
+	}
+	id := list.Data[0].ID
+
+	expect(t, ta.do(http.MethodGet, "/v1/admin/mail/dead-letters", ta.token(userEmail), nil), http.StatusForbidden, "FORBIDDEN")
+
+	// Provider masih gangguan: 502 dan percobaan dicatat
+	expect(t, ta.do(http.MethodPost, "/v1/admin/mail/dead-letters/"+id+"/resend", admin, nil), http.StatusBadGateway, "UPSTREAM_FAILED")
+	rec = ta.do(http.MethodGet, "/v1/admin/mail/dead-letters", admin, nil)
+	decode(t, rec, &list)
+	if len(list.Data) != 1 || list.Data[0].Attempts != 2 {
+		t.Fatalf("setelah resend gagal: %+v", list.Data)
+	}
+
+	down.Store(false)
+	expect(t, ta.do(http.MethodPost, "/v1/admin/mail/dead-letters/"+id+"/resend", admin, nil), http.StatusOK, "")
+	if len(sent) != 1 || !strings.Contains(sent[0]["content"].([]interface{})[0].(map[string]interface{})["value"].(string), "mengatur ulang password") {
+		t.Fatalf("email terkirim: %+v", sent)
+	}
+	rec = ta.do(http.MethodGet, "/v1/admin/mail/dead-letters", admin, nil)
+	decode(t, rec, &list)
+	if len(list.Data) != 0 {
+		t.Fatalf("dead letter tidak dihapus: %+v", list.Data)
+	}
+	expect(t, ta.do(http.MethodDelete, "/v1/admin/mail/dead-letters/"+id, admin, nil), http.StatusNotFound, "DEAD_LETTER_NOT_FOUND")
+}
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMetricsToken(t *testing.T) {
+	// Tanpa METRICS_TOKEN endpoint terbuka seperti sebelumnya
+	open := newTestApp(t)
+	expect(t, open.do(http.MethodGet, "/metrics", "", nil), http.StatusOK, "")
+
+	ta := newTestAppWithEnv(t, map[string]string{"METRICS_TOKEN": "scrape-rahasia"})
+	expect(t, ta.do(http.MethodGet, "/metrics", "", nil), http.StatusUnauthorized, "UNAUTHORIZED")
+	expect(t, ta.do(http.MethodGet, "/metrics", "salah", nil), http.StatusUnauthorized, "UNAUTHORIZED")
+	rec := ta.do(http.MethodGet, "/metrics", "scrape-rahasia", nil)
+	expect(t, rec, http.StatusOK, "")
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("content type %q", rec.Header().Get("Content-Type"))
+	}
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+func TestMissingPOISuggestions(t *testing.T) {
+	// Tanpa POI_PROVIDER fitur nonaktif
+	disabled := newTestApp(t)
+	expect(t, disabled.do(http.MethodGet, "/v1/suggestions/missing?lat=-3.6954&lng=128.1814", disabled.token(userEmail), nil),
+		http.StatusBadRequest, "VALIDATION_FAILED")
+
+	// Overpass palsu di sekitar Ambon, jauh dari lokasi fixture
+	var query string
+	overpass := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.FormValue("data")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"elements":[
+			{"type":"node","id":1,"lat":-3.6950,"lon":128.1814,"tags":{"name":"Kopi Tifa!","amenity":"cafe"}},
+			{"type":"node","id":2,"lat":-3.6955,"lon":128.1814,"tags":{"name":"Kedai Sebelah","amenity":"cafe"}},
+			{"type":"node","id":3,"lat":-3.6974,"lon":128.1814,"tags":{"name":"RM Sari Laut","amenity":"restaurant",
+				"addr:street":"Jl. Pattimura","addr:housenumber":"5","addr:city":"Ambon","addr:postcode":"97124"}},
+			{"type":"way","id":4,"center":{"lat":-3.6964,"lon":128.1814},"tags":{"name":"Toko Maju","shop":"convenience"}},
+			{"type":"node","id":5,"lat":-3.6960,"lon":128.1814,"tags":{"name":"Bengkel","shop":"car_repair"}}
+		]}`))
+	}))
+	defer overpass.Close()
+	t.Setenv("POI_PROVIDER", "overpass")
+	t.Setenv("OVERPASS_URL", overpass.URL)
+
+	ta := newTestApp(t)
+	user := ta.token(userEmail)
+	payload := newLocationPayload("Kopi Tifa")
+	payload["coordinates"] = map[string]float64{"lat": -3.6954, "lng": 128.1814}
+	// Admin supaya lokasi langsung tayang tanpa moderasi
+	expect(t, ta.do(http.MethodPost, "/v1/locations", ta.token(adminEmail), payload), http.StatusCreated, "")
+
+	expect(t, ta.do(http.MethodGet, "/v1/suggestions/missing?lat=-3.6954&lng=128.1814", "", nil), http.StatusUnauthorized, "")
+	expect(t, ta.do(http.MethodGet, "/v1/suggestions/missing?lat=-3.6954&lng=128.1814&radius=5000", user, nil), http.StatusBadRequest, "VALIDATION_FAILED")
+	expect(t, ta.do(http.MethodGet, "/v1/suggestions/missing?lat=-3.6954&lng=128.1814&category=tidak-ada", user, nil), http.StatusNotFound, "CATEGORY_NOT_FOUND")
+
+	rec := ta.do(http.MethodGet, "/v1/suggestions/missing?lat=-3.6954&lng=128.1814&radius=500", user, nil)
+	expect(t, rec, http.StatusOK, "")
+	var body struct {
+		Data []models.MissingPOI `json:"data"`
+	}
+	decode(t, rec, &body)
+	if !strings.Contains(query, `node["amenity"="cafe"]["name"](around:500,-3.6954,128.1814)`) {
+		t.Fatalf("query overpass %s", query)
+	}
+	// Kopi Tifa cocok nama, Kedai Sebelah berimpit dengan kafe kita, bengkel
+	// tidak punya kategori
+	if len(body.Data) != 2 || body.Data[0].OSMID != "way/4" || body.Data[1].OSMID != "node/3" {
+		t.Fatalf("saran %+v", body.Data)
+	}
+	p := body.Data[1].Payload
+	if p.Category != "restoran" || p.Address != "Jl. Pattimura 5, Ambon" || p.PostalCode != "97124" || p.Coordinates.Lat != -3.6974 {
+		t.Fatalf("payload %+v", p)
+	}
+}
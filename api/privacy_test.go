@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+func TestApproximateCoordinates(t *testing.T) {
+	ta := newTestApp(t)
+	ctx := context.Background()
+	shelter := models.Category{Name: "Rumah Aman", Slug: "rumah_aman", ApproximateCoordinates: true}
+	if err := ta.store.Categories().Create(ctx, &shelter); err != nil {
+		t.Fatal(err)
+	}
+	if err := ta.store.Locations().Update(ctx, ta.location.ID, repositories.Fields{"category": shelter.Slug, "address": "Jl. Rahasia 1"}); err != nil {
+		t.Fatal(err)
+	}
+	exact := ta.location.Coordinates
+	path := "/v1/locations/" + ta.location.ID.Hex()
+
+	get := func(token string) models.Location {
+		t.Helper()
+		rec := ta.do(http.MethodGet, path, token, nil)
+		expect(t, rec, http.StatusOK, "")
+		var body struct {
+			Data models.Location `json:"data"`
+		}
+		decode(t, rec, &body)
+		return body.Data
+	}
+	// Publik dan user lain hanya melihat perkiraan tanpa alamat
+	for _, token := range []string{"", ta.token(otherEmail)} {
+		loc := get(token)
+		if loc.Coordinates != exact.Approximate() || loc.Coordinates == exact || loc.Address != "" || !loc.ApproximateCoordinates {
+			t.Fatalf("publik melihat %+v, asli %+v", loc, exact)
+		}
+	}
+	// Pembuat dan moderator melihat koordinat asli
+	for _, email := range []string{userEmail, adminEmail} {
+		loc := get(ta.token(email))
+		if loc.Coordinates != exact || loc.Address == "" || !loc.ApproximateCoordinates {
+			t.Fatalf("%s melihat %+v, asli %+v", email, loc, exact)
+		}
+	}
+
+	// Daftar publik dari map_view selalu perkiraan
+	rec := ta.do(http.MethodGet, "/v1/locations?category="+shelter.Slug, ta.token(userEmail), nil)
+	expect(t, rec, http.StatusOK, "")
+	var list struct {
+		Data []models.MapViewItem `json:"data"`
+	}
+	decode(t, rec, &list)
+	if len(list.Data) != 1 || list.Data[0].Coordinates != exact.Approximate() || !list.Data[0].ApproximateCoordinates {
+		t.Fatalf("daftar %+v", list.Data)
+	}
+
+	// Tanpa kebijakan kategori, pilihan per lokasi tetap berlaku
+	shelter.ApproximateCoordinates = false
+	expect(t, ta.do(http.MethodPut, "/v1/categories/"+shelter.Slug, ta.token(adminEmail), shelter), http.StatusOK, "")
+	if loc := get(""); loc.Coordinates != exact {
+		t.Fatalf("tanpa kebijakan publik melihat %+v", loc.Coordinates)
+	}
+	if err := ta.store.Locations().Update(ctx, ta.location.ID, repositories.Fields{"approximate_coordinates": true}); err != nil {
+		t.Fatal(err)
+	}
+	if loc := get(""); loc.Coordinates != exact.Approximate() {
+		t.Fatalf("pilihan per lokasi diabaikan: %+v", loc.Coordinates)
+	}
+}
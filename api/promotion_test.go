@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+func TestPromotedLocations(t *testing.T) {
+	ta := newTestApp(t)
+	admin := ta.token(adminEmail)
+	locations, _, err := ta.store.Locations().List(context.Background(), repositories.LocationQuery{ListPage: repositories.ListPage{Field: "name", Limit: 100}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pinned, sponsored := locations[len(locations)-1], locations[len(locations)-2]
+	promotion := func(id string) string { return "/v1/admin/locations/" + id + "/promotion" }
+
+	expect(t, ta.do(http.MethodPut, promotion(sponsored.ID.Hex()), admin, map[string]interface{}{
+		"type": models.PromotionSponsored, "ends_at": time.Now().Add(24 * time.Hour),
+	}), http.StatusBadRequest, "VALIDATION_FAILED")
+	expect(t, ta.do(http.MethodPut, promotion(sponsored.ID.Hex()), ta.token(userEmail), map[string]interface{}{
+		"type": models.PromotionPinned, "ends_at": time.Now().Add(24 * time.Hour),
+	}), http.StatusForbidden, "")
+	expect(t, ta.do(http.MethodPut, promotion(sponsored.ID.Hex()), admin, map[string]interface{}{
+		"type": models.PromotionSponsored, "sponsor": "Kopi Kenangan", "ends_at": time.Now().Add(24 * time.Hour),
+	}), http.StatusOK, "")
+	expect(t, ta.do(http.MethodPut, promotion(pinned.ID.Hex()), admin, map[string]interface{}{
+		"type": models.PromotionPinned, "ends_at": time.Now().Add(24 * time.Hour),
+	}), http.StatusOK, "")
+
+	list := func(path string) []models.MapViewItem {
+		t.Helper()
+		rec := ta.do(http.MethodGet, path, "", nil)
+		expect(t, rec, http.StatusOK, "")
+		var body struct {
+			Data []models.MapViewItem `json:"data"`
+		}
+		decode(t, rec, &body)
+		return body.Data
+	}
+	// Pinned di atas sponsored, lalu hasil organik tanpa duplikat
+	items := list("/v1/locations?limit=100")
+	if len(items) != len(locations) || items[0].ID != pinned.ID || items[1].ID != sponsored.ID ||
+		items[0].Promotion == nil || items[1].Promotion.Sponsor != "Kopi Kenangan" || items[2].Promotion != nil {
+		t.Fatalf("urutan promosi salah: %+v", items[:3])
+	}
+	// Halaman berikutnya tidak dipromosikan lagi
+	if items := list("/v1/locations?page=2&limit=2"); len(items) > 0 && items[0].Promotion != nil {
+		t.Fatalf("promosi muncul di halaman 2: %+v", items[0])
+	}
+	// Pencarian hanya menaikkan lokasi yang cocok
+	if items := list("/v1/locations?q=" + url.QueryEscape(pinned.Name)); items[0].ID != pinned.ID {
+		t.Fatalf("pencarian: %+v", items)
+	}
+	// Request shadow traffic tidak menambah impression
+	req := httptest.NewRequest(http.MethodGet, "/v1/locations?limit=100", nil)
+	req.Header.Set("X-Shadow-Request", "1")
+	shadowRec := httptest.NewRecorder()
+	ta.app.Router.ServeHTTP(shadowRec, req)
+	expect(t, shadowRec, http.StatusOK, "")
+
+	rec := ta.do(http.MethodGet, promotion(sponsored.ID.Hex())+"/impressions", admin, nil)
+	expect(t, rec, http.StatusOK, "")
+	var report struct {
+		Data models.PromotionReport `json:"data"`
+	}
+	decode(t, rec, &report)
+	if report.Data.Impressions != 1 || len(report.Data.ByDay) != 1 || report.Data.Promotion == nil {
+		t.Fatalf("laporan impression: %+v", report.Data)
+	}
+
+	expect(t, ta.do(http.MethodDelete, promotion(pinned.ID.Hex()), admin, nil), http.StatusOK, "")
+	if items := list("/v1/locations?limit=100"); items[0].ID != sponsored.ID {
+		t.Fatalf("promosi dihentikan masih tampil: %+v", items[0])
+	}
+}
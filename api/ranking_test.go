@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+func TestSearchRanking(t *testing.T) {
+	ta := newTestApp(t)
+	admin, user := ta.token(adminEmail), ta.token(userEmail)
+	create := func(name string, lat float64) models.Location {
+		t.Helper()
+		payload := newLocationPayload(name)
+		payload["coordinates"] = map[string]float64{"lat": lat, "lng": 107.6}
+		rec := ta.do(http.MethodPost, "/v1/locations", admin, payload)
+		expect(t, rec, http.StatusCreated, "")
+		var body struct {
+			Data models.Location `json:"data"`
+		}
+		decode(t, rec, &body)
+		return body.Data
+	}
+	exact, contains, prefix := create("Kopitiam", -6.95), create("Rumah Kopitiam", -6.90), create("Kopitiam Lama", -6.80)
+	search := func(path string) []models.MapViewItem {
+		t.Helper()
+		rec := ta.do(http.MethodGet, path, "", nil)
+		expect(t, rec, http.StatusOK, "")
+		var body struct {
+			Data []models.MapViewItem `json:"data"`
+		}
+		decode(t, rec, &body)
+		return body.Data
+	}
+	order := func(items []models.MapViewItem) []string {
+		names := []string{}
+		for _, item := range items {
+			names = append(names, item.Name)
+		}
+		return names
+	}
+
+	items := search("/v1/locations?q=kopitiam")
+	if len(items) != 3 || items[0].ID != exact.ID || items[1].ID != prefix.ID || items[2].ID != contains.ID {
+		t.Fatalf("urutan relevansi %v", order(items))
+	}
+	if items[0].Ranking != nil {
+		t.Fatalf("ranking tanpa explain: %+v", items[0].Ranking)
+	}
+	// sort eksplisit tetap dihormati
+	if items := search("/v1/locations?q=kopitiam&sort=name"); items[0].ID != exact.ID || items[1].ID != prefix.ID {
+		t.Fatalf("sort name %v", order(items))
+	}
+
+	weights := models.RankingWeights{Distance: 1}
+	expect(t, ta.do(http.MethodPut, "/v1/admin/settings/ranking", user, weights), http.StatusForbidden, "FORBIDDEN")
+	expect(t, ta.do(http.MethodPut, "/v1/admin/settings/ranking", admin, models.RankingWeights{}), http.StatusBadRequest, "VALIDATION_FAILED")
+	expect(t, ta.do(http.MethodPut, "/v1/admin/settings/ranking", admin, models.RankingWeights{Text: -1, Distance: 1}), http.StatusBadRequest, "VALIDATION_FAILED")
+	expect(t, ta.do(http.MethodPut, "/v1/admin/settings/ranking", admin, weights), http.StatusOK, "")
+
+	// Hanya jarak yang dihitung: yang paling dekat ke titik referensi di depan
+	items = search("/v1/locations?q=kopitiam&lat=-6.80&lng=107.6&explain=true")
+	if len(items) != 3 || items[0].ID != prefix.ID || items[2].ID != exact.ID {
+		t.Fatalf("urutan jarak %v", order(items))
+	}
+	explain := items[0].Ranking
+	if explain == nil || len(explain.Factors) != 1 || explain.Factors[0].Name != "distance" || explain.Factors[0].Value != 1 || explain.Score != 1 {
+		t.Fatalf("explain %+v", explain)
+	}
+
+	expect(t, ta.do(http.MethodGet, "/v1/locations?sort=relevance", "", nil), http.StatusBadRequest, "VALIDATION_FAILED")
+	expect(t, ta.do(http.MethodGet, "/v1/locations?q=kopitiam&lat=-6.8", "", nil), http.StatusBadRequest, "")
+}
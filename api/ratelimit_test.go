@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// getWithForwardedFor mengirim GET dengan X-Forwarded-For seperti client
+// yang memalsukan IP-nya.
+func getWithForwardedFor(ta *testApp, path, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("X-Forwarded-For", ip)
+	rec := httptest.NewRecorder()
+	ta.app.Router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimitIgnoresSpoofedForwardedFor(t *testing.T) {
+	ta := newTestAppWithEnv(t, map[string]string{"RATE_LIMIT_PER_MIN": "1", "RATE_LIMIT_BURST": "1"})
+
+	expect(t, getWithForwardedFor(ta, "/v1/categories", "198.51.100.1"), http.StatusOK, "")
+	// IP lain di header tidak memberi bucket baru: koneksinya tetap sama
+	rec := getWithForwardedFor(ta, "/v1/categories", "198.51.100.2")
+	expect(t, rec, http.StatusTooManyRequests, "RATE_LIMITED")
+	if rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("Retry-After kosong: %v", rec.Header())
+	}
+}
+
+func TestRateLimitTrustedProxy(t *testing.T) {
+	// httptest memakai RemoteAddr 192.0.2.1
+	ta := newTestAppWithEnv(t, map[string]string{
+		"RATE_LIMIT_PER_MIN": "1",
+		"RATE_LIMIT_BURST":   "1",
+		"TRUSTED_PROXIES":    "192.0.2.0/24",
+	})
+
+	expect(t, getWithForwardedFor(ta, "/v1/categories", "198.51.100.1"), http.StatusOK, "")
+	expect(t, getWithForwardedFor(ta, "/v1/categories", "198.51.100.2"), http.StatusOK, "")
+	expect(t, getWithForwardedFor(ta, "/v1/categories", "198.51.100.1"), http.StatusTooManyRequests, "RATE_LIMITED")
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+func TestDatabaseReadOnlyMode(t *testing.T) {
+	ta := newTestApp(t)
+	ctx := context.Background()
+	admin, user := ta.token(adminEmail), ta.token(userEmail)
+	favorite := "/v1/locations/" + ta.location.ID.Hex() + "/favorite"
+	mode := func(readOnly bool) {
+		t.Helper()
+		expect(t, ta.do(http.MethodPut, "/v1/admin/database/mode", admin, map[string]bool{"read_only": readOnly}), http.StatusOK, "")
+	}
+	status := func() models.DatabaseStatus {
+		t.Helper()
+		rec := ta.do(http.MethodGet, "/v1/admin/database", admin, nil)
+		expect(t, rec, http.StatusOK, "")
+		var body struct {
+			Data models.DatabaseStatus `json:"data"`
+		}
+		decode(t, rec, &body)
+		return body.Data
+	}
+
+	expect(t, ta.do(http.MethodPut, "/v1/admin/database/mode", user, map[string]bool{"read_only": true}), http.StatusForbidden, "")
+	mode(true)
+
+	// Write biasa langsung ditolak dengan petunjuk retry, read tetap jalan
+	rec := ta.do(http.MethodPost, "/v1/locations", admin, newLocationPayload("Kopi Failover"))
+	expect(t, rec, http.StatusServiceUnavailable, "DATABASE_READ_ONLY")
+	if rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("Retry-After kosong: %v", rec.Header())
+	}
+	expect(t, ta.do(http.MethodGet, "/v1/locations", "", nil), http.StatusOK, "")
+
+	// Favorit dan preferensi diantrekan; tambah-hapus-tambah digabung
+	expect(t, ta.do(http.MethodPost, favorite, user, nil), http.StatusAccepted, "")
+	expect(t, ta.do(http.MethodDelete, favorite, user, nil), http.StatusAccepted, "")
+	expect(t, ta.do(http.MethodPost, favorite, user, nil), http.StatusAccepted, "")
+	expect(t, ta.do(http.MethodPut, "/v1/me/preferences", user, map[string]string{"units": "imperial"}), http.StatusAccepted, "")
+	if st := status(); !st.ReadOnly || !st.Forced || st.Outbox.Pending != 2 {
+		t.Fatalf("status read-only %+v", st)
+	}
+	if n, _ := ta.store.Favorites().CountByLocation(ctx, ta.location.ID); n != 0 {
+		t.Fatalf("favorit tersimpan saat read-only: %d", n)
+	}
+
+	// Setelah mode dilepas outbox diterapkan di latar belakang
+	mode(false)
+	deadline := time.Now().Add(2 * time.Second)
+	for status().Outbox.Pending > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("outbox tidak diterapkan %+v", status())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if st := status(); st.ReadOnly || st.Outbox.Applied != 2 || st.Outbox.Failed != 0 {
+		t.Fatalf("status setelah pulih %+v", st)
+	}
+	if n, _ := ta.store.Favorites().CountByLocation(ctx, ta.location.ID); n != 1 {
+		t.Fatalf("favorit setelah replay: %d", n)
+	}
+	u, err := ta.store.Users().FindByEmail(ctx, userEmail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Preferences.Units != "imperial" {
+		t.Fatalf("preferensi setelah replay %+v", u.Preferences)
+	}
+	expect(t, ta.do(http.MethodPost, "/v1/locations", admin, newLocationPayload("Kopi Pulih")), http.StatusCreated, "")
+}
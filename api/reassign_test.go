@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"InfoCuy-Backend/internal/apperr"
+	"InfoCuy-Backend/internal/models"
+)
+
+func TestReassignLocations(t *testing.T) {
+	ta := newTestApp(t)
+	admin := ta.token(adminEmail)
+	input := models.ReassignInput{Filter: models.ReassignFilter{CreatedBy: userEmail}, Target: otherEmail}
+
+	// Butuh locations:moderate
+	expect(t, ta.do(http.MethodPost, "/v1/admin/locations/reassign", ta.token(userEmail), input), http.StatusForbidden, apperr.CodeForbidden)
+	expect(t, ta.do(http.MethodPost, "/v1/admin/locations/reassign", admin,
+		models.ReassignInput{Filter: input.Filter, Target: "tidak.ada@example.com"}), http.StatusBadRequest, apperr.CodeValidation)
+
+	rec := ta.do(http.MethodPost, "/v1/admin/locations/reassign", admin, input)
+	expect(t, rec, http.StatusAccepted, "")
+	var started struct {
+		Data models.Reassignment `json:"data"`
+	}
+	decode(t, rec, &started)
+	if started.Data.Matched != 5 || started.Data.Status != models.ReassignQueued {
+		t.Fatalf("job %+v", started.Data)
+	}
+
+	// Batch 3 lalu sisa 2
+	for _, want := range []int{3, 2} {
+		rec = ta.do(http.MethodPost, "/v1/admin/jobs/location-reassign?batch=3", admin, nil)
+		expect(t, rec, http.StatusOK, "")
+		var result models.ReassignResult
+		decode(t, rec, &result)
+		if result.Reassigned != want {
+			t.Fatalf("reassigned %d, want %d", result.Reassigned, want)
+		}
+	}
+
+	rec = ta.do(http.MethodGet, "/v1/admin/locations/reassign/"+started.Data.ID.Hex(), admin, nil)
+	expect(t, rec, http.StatusOK, "")
+	var job struct {
+		Data models.Reassignment `json:"data"`
+	}
+	decode(t, rec, &job)
+	if job.Data.Status != models.ReassignDone || job.Data.Reassigned != 5 {
+		t.Fatalf("job %+v", job.Data)
+	}
+	if left := listLocations(t, ta, "/v1/locations?created_by="+userEmail); len(left) != 0 {
+		t.Errorf("%d lokasi masih milik %s", len(left), userEmail)
+	}
+	// Pemilik baru boleh mengubah lokasinya
+	path := "/v1/locations/" + ta.location.ID.Hex()
+	expect(t, ta.do(http.MethodPut, path, ta.token(otherEmail), newLocationPayload("Dikelola Dinas Baru")), http.StatusOK, "")
+
+	rec = ta.do(http.MethodGet, "/v1/admin/audit-logs?action=location.reassign", admin, nil)
+	expect(t, rec, http.StatusOK, "")
+	var logs struct {
+		Meta models.PageMeta `json:"meta"`
+	}
+	decode(t, rec, &logs)
+	if logs.Meta.Total != 5 {
+		t.Errorf("audit location.reassign %d, want 5", logs.Meta.Total)
+	}
+
+	// Antrean kosong
+	rec = ta.do(http.MethodPost, "/v1/admin/jobs/location-reassign", admin, nil)
+	expect(t, rec, http.StatusOK, "")
+	expect(t, ta.do(http.MethodPost, "/v1/admin/locations/reassign", admin, input), http.StatusBadRequest, apperr.CodeValidation)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+func TestMergeRedirectsOldLinks(t *testing.T) {
+	ta := newTestApp(t)
+	admin := ta.token(adminEmail)
+	a := createLocation(t, ta, admin, "Kopi Braga")
+	b := createLocation(t, ta, admin, "Kopi Braga (duplikat)")
+	c := createLocation(t, ta, admin, "Kopi Braga Asli")
+	pending := createLocation(t, ta, ta.token(userEmail), "Kopi Braga Baru")
+
+	merge := func(from, into string) *models.LocationRedirect {
+		t.Helper()
+		rec := ta.do(http.MethodPost, "/v1/admin/locations/"+from+"/merge", admin, models.MergeInput{Into: into})
+		expect(t, rec, http.StatusOK, "")
+		var body struct {
+			Data models.LocationRedirect `json:"data"`
+		}
+		decode(t, rec, &body)
+		return &body.Data
+	}
+	expect(t, ta.do(http.MethodPost, "/v1/admin/locations/"+a.ID.Hex()+"/merge", ta.token(userEmail), models.MergeInput{Into: b.ID.Hex()}),
+		http.StatusForbidden, "")
+	expect(t, ta.do(http.MethodPost, "/v1/admin/locations/"+a.ID.Hex()+"/merge", admin, models.MergeInput{Into: a.ID.Hex()}),
+		http.StatusBadRequest, "VALIDATION_FAILED")
+	expect(t, ta.do(http.MethodPost, "/v1/admin/locations/"+a.ID.Hex()+"/merge", admin, models.MergeInput{Into: pending.ID.Hex()}),
+		http.StatusBadRequest, "VALIDATION_FAILED")
+
+	if red := merge(a.ID.Hex(), b.ID.Hex()); red.To != b.ID || red.Reason != models.RedirectMerged {
+		t.Fatalf("redirect %+v", red)
+	}
+	// Tujuan ikut digabung: link ke a langsung menuju c, tanpa rantai
+	merge(b.ID.Hex(), c.ID.Hex())
+
+	for _, old := range []models.Location{a, b} {
+		rec := ta.do(http.MethodGet, "/v1/locations/"+old.ID.Hex(), "", nil)
+		expect(t, rec, http.StatusOK, "")
+		var body struct {
+			Data     models.Location     `json:"data"`
+			Redirect models.RedirectInfo `json:"redirect"`
+		}
+		decode(t, rec, &body)
+		if body.Data.ID != c.ID || body.Redirect.From != old.ID || body.Redirect.To != c.ID || body.Redirect.Status != http.StatusMovedPermanently {
+			t.Fatalf("detail %s: %+v", old.Name, body)
+		}
+		if loc := rec.Header().Get("Location"); loc != "/v1/locations/"+c.ID.Hex() {
+			t.Fatalf("header Location %q", loc)
+		}
+	}
+	// Lokasi yang masih berlaku tidak membawa redirect
+	rec := ta.do(http.MethodGet, "/v1/locations/"+c.ID.Hex(), "", nil)
+	expect(t, rec, http.StatusOK, "")
+	var body map[string]interface{}
+	decode(t, rec, &body)
+	if _, ok := body["redirect"]; ok {
+		t.Fatalf("lokasi tujuan membawa redirect: %v", body)
+	}
+
+	// Lokasi hasil merge tidak bisa dipulihkan dari trash
+	expect(t, ta.do(http.MethodPost, "/v1/locations/"+a.ID.Hex()+"/restore", admin, nil), http.StatusConflict, "LOCATION_MERGED")
+	expect(t, ta.do(http.MethodGet, "/v1/locations/"+a.ID.Hex(), "", nil), http.StatusOK, "")
+
+	// Redirect tetap berlaku setelah lokasi lama dihapus permanen
+	expect(t, ta.do(http.MethodDelete, "/v1/admin/locations/"+a.ID.Hex(), admin, nil), http.StatusOK, "")
+	expect(t, ta.do(http.MethodGet, "/v1/locations/"+a.ID.Hex(), "", nil), http.StatusOK, "")
+}
+
+func TestMergeOutsideScope(t *testing.T) {
+	ta := newTestApp(t)
+	admin := ta.token(adminEmail)
+	token, area := scopedModerator(t, ta, "locations:moderate")
+	inside := createLocation(t, ta, admin, "Kopi Braga")
+	duplicate := createLocation(t, ta, admin, "Kopi Braga (duplikat)")
+	outside := createLocation(t, ta, admin, "Kopi Luar Kota")
+	for _, loc := range []models.Location{inside, duplicate} {
+		if err := ta.store.Locations().Update(context.Background(), loc.ID, repositories.Fields{"admin_area": area}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path := func(from models.Location) string { return "/v1/admin/locations/" + from.ID.Hex() + "/merge" }
+	expect(t, ta.do(http.MethodPost, path(outside), token, models.MergeInput{Into: inside.ID.Hex()}), http.StatusForbidden, "FORBIDDEN")
+	expect(t, ta.do(http.MethodPost, path(duplicate), token, models.MergeInput{Into: outside.ID.Hex()}), http.StatusForbidden, "FORBIDDEN")
+	expect(t, ta.do(http.MethodGet, "/v1/locations/"+outside.ID.Hex(), "", nil), http.StatusOK, "")
+	expect(t, ta.do(http.MethodPost, path(duplicate), token, models.MergeInput{Into: inside.ID.Hex()}), http.StatusOK, "")
+}
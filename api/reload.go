@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"sync"
+
+	"InfoCuy-Backend/internal/chaos"
+	"InfoCuy-Backend/internal/config"
+	"InfoCuy-Backend/internal/handlers"
+	"InfoCuy-Backend/internal/jobs"
+	"InfoCuy-Backend/internal/logging"
+)
+
+// runtimeState adalah config runtime yang sedang berlaku.
+type runtimeState struct {
+	mu  sync.RWMutex
+	cfg config.Runtime
+}
+
+func (s *runtimeState) get() config.Runtime {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg
+}
+
+func (s *runtimeState) set(cfg config.Runtime) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfg = cfg
+}
+
+// Reload membaca ulang config (config.Reload) dan menerapkan bagian
+// runtime-nya tanpa memutus koneksi: level log, kebijakan CORS, rate limit,
+// ALLOW_REGISTRATION dan LEGACY_EMAIL_AUTH. Jika config baru tidak valid
+// tidak ada yang diubah. Dipanggil saat SIGHUP (main.go) dan POST /admin/config/reload.
+func (a *App) Reload() (config.Runtime, []string, error) {
+	a.reloadMu.Lock()
+	defer a.reloadMu.Unlock()
+	cfg, err := config.Reload()
+	if err != nil {
+		return config.Runtime{}, nil, err
+	}
+	prev, next := a.runtime.get(), cfg.Runtime()
+	changed := prev.Changed(next)
+	if len(changed) == 0 {
+		return next, nil, nil
+	}
+	// CORS divalidasi lebih dulu supaya perubahan lain tidak diterapkan
+	// setengah jalan
+	if err := a.handler.SetCORS(next.CORS, next.AdminCORS); err != nil {
+		return config.Runtime{}, nil, fmt.Errorf("CORS: %w", err)
+	}
+	logging.SetLevel(next.LogLevel)
+	if prev.RateLimit != next.RateLimit {
+		a.handler.RateLimit.SetRate(next.RateLimit.PerMin, next.RateLimit.Burst)
+	}
+	if prev.AuthRateLimit != next.AuthRateLimit {
+		a.handler.AuthRateLimit.SetRate(next.AuthRateLimit.PerMin, next.AuthRateLimit.Burst)
+	}
+	a.auth.SetRegistrationOpen(next.AllowRegistration)
+	a.auth.SetLegacyEmailAuth(next.LegacyEmailAuth)
+	a.runtime.set(next)
+	slog.Info("config dimuat ulang", "changed", changed)
+	return next, changed, nil
+}
+
+// runtimeInfo merangkum fitur opsional dan batas yang berlaku untuk
+// GET /admin/runtime-info. Bagian runtime diambil dari config yang sedang
+// berlaku (setelah reload), sisanya dari config saat startup.
+func runtimeInfo(cfg *config.Config, runtime config.Runtime, policyMode string, features map[string]bool, queue *jobs.Queue) handlers.RuntimeInfo {
+	info := handlers.RuntimeInfo{
+		Features: maps.Clone(features),
+		Limits: handlers.RuntimeLimits{
+			Runtime:              runtime,
+			LoginMaxFailures:     cfg.LoginMaxFailures,
+			LoginLockout:         cfg.LoginLockout.String(),
+			AccessTokenTTL:       cfg.JWT.AccessTTL.String(),
+			RefreshTokenTTL:      cfg.JWT.RefreshTTL.String(),
+			StreamMaxClients:     cfg.StreamMaxClients,
+			MongoMaxPoolSize:     cfg.Mongo.MaxPoolSize,
+			MongoOpTimeout:       cfg.Mongo.OpTimeout.String(),
+			TrashRetention:       cfg.TrashRetention.String(),
+			ArchiveAfter:         cfg.ArchiveAfter.String(),
+			FreshnessHalfLife:    cfg.FreshnessHalfLife.String(),
+			CampaignSendInterval: cfg.CampaignSendInterval.String(),
+			FieldPolicyMode:      policyMode,
+			Jobs:                 queue.Limits(),
+		},
+		Jobs: queue.Stats(),
+	}
+	// Chaos bisa dinyalakan/dimatikan saat berjalan
+	info.Features["chaos"] = chaos.Current() != nil
+	return info
+}
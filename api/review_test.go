@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+func TestReviewSpamHold(t *testing.T) {
+	ta := newTestApp(t)
+	admin, user, other := ta.token(adminEmail), ta.token(userEmail), ta.token(otherEmail)
+	reviewsPath := "/v1/locations/" + ta.location.ID.Hex() + "/reviews"
+
+	create := func(path, token, comment string) models.Review {
+		t.Helper()
+		rec := ta.do(http.MethodPost, path, token, models.ReviewInput{Rating: 5, Comment: comment})
+		expect(t, rec, http.StatusCreated, "")
+		var body struct{ Data models.Review }
+		decode(t, rec, &body)
+		return body.Data
+	}
+	first := create(reviewsPath, other, "Kopinya enak banget, tempatnya nyaman buat kerja seharian.")
+	if !first.Listed() || len(first.SpamFlags) > 0 {
+		t.Fatalf("ulasan biasa ditahan: %+v", first)
+	}
+
+	// Teks yang sama dengan kapital & tanda baca berbeda di lokasi lain
+	rec := ta.do(http.MethodPost, "/v1/locations", admin, newLocationPayload("Kopi Kedua"))
+	expect(t, rec, http.StatusCreated, "")
+	var created struct{ Data models.Location }
+	decode(t, rec, &created)
+	dup := create("/v1/locations/"+created.Data.ID.Hex()+"/reviews", other, "KOPINYA enak banget!! Tempatnya nyaman buat kerja seharian")
+	if dup.ModerationStatus != models.ModerationPending || len(dup.SpamFlags) != 1 ||
+		dup.SpamFlags[0].Code != models.ReviewFlagDuplicate || *dup.SpamFlags[0].SimilarTo != first.ID {
+		t.Fatalf("duplikat: %+v", dup)
+	}
+	// ta.location dibuat oleh userEmail
+	self := create(reviewsPath, user, "Tempat terbaik di kota!")
+	if self.ModerationStatus != models.ModerationPending || len(self.SpamFlags) != 1 || self.SpamFlags[0].Code != models.ReviewFlagSelfReview {
+		t.Fatalf("self review: %+v", self)
+	}
+
+	listed := func() (int, models.RatingSummary) {
+		t.Helper()
+		rec := ta.do(http.MethodGet, reviewsPath, "", nil)
+		expect(t, rec, http.StatusOK, "")
+		var body struct {
+			Data   []models.Review
+			Rating models.RatingSummary
+		}
+		decode(t, rec, &body)
+		return len(body.Data), body.Rating
+	}
+	if n, rating := listed(); n != 1 || rating.Count != 1 {
+		t.Fatalf("ulasan tayang %d, rating %+v", n, rating)
+	}
+
+	expect(t, ta.do(http.MethodGet, "/v1/admin/reviews/pending", user, nil), http.StatusForbidden, "FORBIDDEN")
+	rec = ta.do(http.MethodGet, "/v1/admin/reviews/pending", admin, nil)
+	expect(t, rec, http.StatusOK, "")
+	var queue struct{ Data []models.Review }
+	decode(t, rec, &queue)
+	if len(queue.Data) != 2 || queue.Data[0].ID != dup.ID || queue.Data[1].ID != self.ID {
+		t.Fatalf("antrean %+v", queue.Data)
+	}
+
+	expect(t, ta.do(http.MethodPost, "/v1/admin/reviews/"+self.ID.Hex()+"/approve", admin, nil), http.StatusOK, "")
+	if n, rating := listed(); n != 2 || rating.Count != 2 {
+		t.Fatalf("setelah approve: %d, %+v", n, rating)
+	}
+	expect(t, ta.do(http.MethodPost, "/v1/admin/reviews/"+dup.ID.Hex()+"/reject", admin, nil), http.StatusOK, "")
+	expect(t, ta.do(http.MethodPost, "/v1/admin/reviews/"+dup.ID.Hex()+"/reject", admin, nil), http.StatusConflict, "ALREADY_MODERATED")
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRuntimeInfo(t *testing.T) {
+	ta := newTestApp(t)
+	expect(t, ta.do(http.MethodGet, "/v1/admin/runtime-info", ta.token(userEmail), nil), http.StatusForbidden, "")
+
+	rec := ta.do(http.MethodGet, "/v1/admin/runtime-info", ta.token(adminEmail), nil)
+	expect(t, rec, http.StatusOK, "")
+	var body struct {
+		Data struct {
+			Build struct {
+				Version   string `json:"version"`
+				GoVersion string `json:"go_version"`
+			} `json:"build"`
+			Ready        bool                      `json:"ready"`
+			Dependencies []struct{ Status string } `json:"dependencies"`
+			Features     map[string]bool           `json:"features"`
+			Limits       struct {
+				RateLimit struct {
+					PerMin int `json:"per_min"`
+				} `json:"rate_limit"`
+				AllowRegistration bool   `json:"allow_registration"`
+				AccessTokenTTL    string `json:"access_token_ttl"`
+				FieldPolicyMode   string `json:"field_policy_mode"`
+			} `json:"limits"`
+		} `json:"data"`
+	}
+	decode(t, rec, &body)
+	data := body.Data
+	if data.Build.Version == "" || data.Build.GoVersion == "" {
+		t.Fatalf("build kosong: %+v", data.Build)
+	}
+	// Test tidak memakai MongoDB
+	if data.Ready || len(data.Dependencies) != 1 || data.Dependencies[0].Status != "not_configured" {
+		t.Fatalf("dependency: ready=%v %+v", data.Ready, data.Dependencies)
+	}
+	if _, ok := data.Features["email"]; !ok || data.Features["chaos"] {
+		t.Fatalf("fitur: %+v", data.Features)
+	}
+	if data.Limits.AccessTokenTTL == "" || data.Limits.FieldPolicyMode != "strip" || !data.Limits.AllowRegistration {
+		t.Fatalf("batas: %+v", data.Limits)
+	}
+}
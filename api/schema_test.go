@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"InfoCuy-Backend/internal/apperr"
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestLocationSchemaUpgradeOnRead(t *testing.T) {
+	// Dokumen sebelum GeoJSON dan sebelum ada schema_version
+	old, err := bson.Marshal(bson.M{
+		"_id":         primitive.NewObjectID(),
+		"name":        "Warung Lama",
+		"coordinates": bson.M{"lat": -6.2, "lng": 106.8},
+		"distance_m":  42.5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loc models.Location
+	if err := bson.Unmarshal(old, &loc); err != nil {
+		t.Fatal(err)
+	}
+	if loc.Coordinates.Lat != -6.2 || loc.Coordinates.Lng != 106.8 {
+		t.Fatalf("coordinates %+v", loc.Coordinates)
+	}
+	if loc.SchemaVersion != models.LocationSchema.Current() {
+		t.Fatalf("schema_version %d, want %d", loc.SchemaVersion, models.LocationSchema.Current())
+	}
+	var nearby models.NearbyLocation
+	if err := bson.Unmarshal(old, &nearby); err != nil {
+		t.Fatal(err)
+	}
+	if nearby.Name != "Warung Lama" || nearby.DistanceM != 42.5 {
+		t.Fatalf("nearby %+v", nearby)
+	}
+}
+
+func TestSchemaUpgradeJob(t *testing.T) {
+	ta := newTestApp(t)
+	expect(t, ta.do(http.MethodPost, "/v1/admin/jobs/schema-upgrade", ta.token(userEmail), nil), http.StatusForbidden, apperr.CodeForbidden)
+
+	// Lokasi fixture ditulis lewat repository, jadi sudah versi terbaru
+	rec := ta.do(http.MethodPost, "/v1/admin/jobs/schema-upgrade?batch=10", ta.token(adminEmail), nil)
+	expect(t, rec, http.StatusOK, "")
+	var result models.BackfillResult
+	decode(t, rec, &result)
+	if result.Processed != 0 || result.Remaining != 0 {
+		t.Fatalf("result %+v", result)
+	}
+}
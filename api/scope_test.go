@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+func TestScopedModerator(t *testing.T) {
+	ta := newTestApp(t)
+	ctx := context.Background()
+	admin, user := ta.token(adminEmail), ta.token(userEmail)
+
+	unknown := map[string]interface{}{
+		"name":        "moderator-bandung",
+		"permissions": []string{"locations:moderate", "reviews:moderate"},
+		"scope":       map[string][]string{"regions": {"99.99"}, "categories": {"kafe"}},
+	}
+	expect(t, ta.do(http.MethodPost, "/v1/admin/roles", admin, unknown), http.StatusBadRequest, "")
+	token, area := scopedModerator(t, ta, "locations:moderate", "reviews:moderate")
+
+	// Dua lokasi pending; hanya satu yang berada di Kota Bandung
+	inside := createLocation(t, ta, user, "Kopi Braga")
+	outside := createLocation(t, ta, user, "Kopi Luar Kota")
+	if err := ta.store.Locations().Update(ctx, inside.ID, repositories.Fields{"admin_area": area}); err != nil {
+		t.Fatal(err)
+	}
+
+	var queue struct {
+		Data []models.Location `json:"data"`
+		Meta models.PageMeta   `json:"meta"`
+	}
+	rec := ta.do(http.MethodGet, "/v1/admin/locations/pending", token, nil)
+	expect(t, rec, http.StatusOK, "")
+	decode(t, rec, &queue)
+	if queue.Meta.Total != 1 || queue.Data[0].ID != inside.ID {
+		t.Fatalf("antrean moderator ber-scope %+v", queue)
+	}
+	rec = ta.do(http.MethodGet, "/v1/admin/locations/pending", admin, nil)
+	decode(t, rec, &queue)
+	if queue.Meta.Total != 2 {
+		t.Fatalf("antrean admin %+v", queue.Meta)
+	}
+
+	expect(t, ta.do(http.MethodPost, "/v1/admin/locations/"+outside.ID.Hex()+"/approve", token, nil), http.StatusForbidden, "FORBIDDEN")
+	expect(t, ta.do(http.MethodGet, "/v1/locations/"+outside.ID.Hex(), token, nil), http.StatusNotFound, "")
+	expect(t, ta.do(http.MethodPost, "/v1/admin/locations/"+inside.ID.Hex()+"/approve", token, nil), http.StatusOK, "")
+	expect(t, ta.do(http.MethodPost, "/v1/admin/locations/"+outside.ID.Hex()+"/approve", admin, nil), http.StatusOK, "")
+}
+
+// scopedModerator membuat role moderator-bandung (kafe di Kota Bandung)
+// dengan permission perms, memberikannya ke otherEmail dan mengembalikan
+// token-nya beserta admin_area di dalam scope.
+func scopedModerator(t *testing.T, ta *testApp, perms ...string) (string, models.AdminArea) {
+	t.Helper()
+	ctx := context.Background()
+	admin := ta.token(adminEmail)
+	bandung := models.Region{Code: "32.73", Name: "Kota Bandung", Level: models.RegionRegency, ParentCode: "32"}
+	if err := ta.store.Regions().Save(ctx, bandung); err != nil {
+		t.Fatal(err)
+	}
+	role := map[string]interface{}{
+		"name":        "moderator-bandung",
+		"permissions": perms,
+		"scope":       map[string][]string{"regions": {bandung.Code}, "categories": {"kafe"}},
+	}
+	expect(t, ta.do(http.MethodPost, "/v1/admin/roles", admin, role), http.StatusCreated, "")
+	moderator, err := ta.store.Users().FindByEmail(ctx, otherEmail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, ta.do(http.MethodPut, "/v1/users/"+moderator.ID.Hex()+"/role", admin, map[string]string{"role": "moderator-bandung"}), http.StatusOK, "")
+	return ta.token(otherEmail), models.AdminArea{Regency: &models.RegionRef{Code: bandung.Code, Name: bandung.Name}}
+}
+
+func TestScopedModeratorLocationPaths(t *testing.T) {
+	ta := newTestApp(t)
+	ctx := context.Background()
+	admin, user := ta.token(adminEmail), ta.token(userEmail)
+	token, area := scopedModerator(t, ta, "locations:create", "locations:moderate", "locations:update_any", "locations:delete_any", "locations:purge")
+
+	// Dua lokasi lama yang belum dikonfirmasi; hanya satu di Kota Bandung
+	old := time.Now().UTC().AddDate(-1, 0, 0)
+	inside := createLocation(t, ta, user, "Kopi Braga")
+	outside := createLocation(t, ta, user, "Kopi Luar Kota")
+	for _, loc := range []models.Location{inside, outside} {
+		set := repositories.Fields{"last_confirmed_at": old}
+		if loc.ID == inside.ID {
+			set["admin_area"] = area
+		}
+		if err := ta.store.Locations().Update(ctx, loc.ID, set); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var stale struct {
+		Data []models.Location `json:"data"`
+	}
+	rec := ta.do(http.MethodGet, "/v1/admin/locations/stale", token, nil)
+	expect(t, rec, http.StatusOK, "")
+	decode(t, rec, &stale)
+	if len(stale.Data) != 1 || stale.Data[0].ID != inside.ID {
+		t.Fatalf("lokasi basi untuk moderator ber-scope %+v", stale.Data)
+	}
+
+	// Ubah & field moderator
+	path := "/v1/locations/" + outside.ID.Hex()
+	expect(t, ta.do(http.MethodPut, path, token, newLocationPayload("Diubah Moderator")), http.StatusForbidden, "FORBIDDEN")
+	expect(t, ta.do(http.MethodPut, "/v1/locations/"+inside.ID.Hex(), token, newLocationPayload("Kopi Braga Baru")), http.StatusOK, "")
+	moved := newLocationPayload("Kopi Braga Pindah")
+	moved["coordinates"] = map[string]float64{"lat": -6.2, "lng": 106.8}
+	expect(t, ta.do(http.MethodPut, "/v1/locations/"+inside.ID.Hex(), token, moved), http.StatusForbidden, "FORBIDDEN")
+	own := newLocationPayload("Kopi Milik Moderator")
+	own["verified"] = true
+	rec = ta.do(http.MethodPost, "/v1/locations", token, own)
+	expect(t, rec, http.StatusCreated, "")
+	var created struct {
+		Data    models.Location `json:"data"`
+		Ignored []string        `json:"ignored_fields"`
+	}
+	decode(t, rec, &created)
+	if created.Data.Verified || !slices.Contains(created.Ignored, "verified") {
+		t.Fatalf("field verified di luar scope ditulis: %+v", created)
+	}
+
+	// Hapus, trash & purge
+	expect(t, ta.do(http.MethodDelete, path, token, nil), http.StatusForbidden, "FORBIDDEN")
+	expect(t, ta.do(http.MethodDelete, "/v1/admin/locations/"+outside.ID.Hex(), token, nil), http.StatusForbidden, "FORBIDDEN")
+	expect(t, ta.do(http.MethodDelete, path, admin, nil), http.StatusOK, "")
+	var trash struct {
+		Data []models.Location `json:"data"`
+	}
+	rec = ta.do(http.MethodGet, "/v1/locations/trash", token, nil)
+	expect(t, rec, http.StatusOK, "")
+	decode(t, rec, &trash)
+	if len(trash.Data) != 0 {
+		t.Fatalf("trash moderator ber-scope %+v", trash.Data)
+	}
+	expect(t, ta.do(http.MethodPost, path+"/restore", token, nil), http.StatusForbidden, "FORBIDDEN")
+	expect(t, ta.do(http.MethodPost, path+"/restore", admin, nil), http.StatusOK, "")
+
+	// Arsip
+	if err := ta.store.Locations().Archive(ctx, []models.Location{outside}, time.Now().UTC()); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, ta.do(http.MethodPost, "/v1/admin/locations/"+outside.ID.Hex()+"/unarchive", token, nil), http.StatusForbidden, "FORBIDDEN")
+	expect(t, ta.do(http.MethodPost, "/v1/admin/locations/"+outside.ID.Hex()+"/unarchive", admin, nil), http.StatusOK, "")
+
+	// Reassign hanya memindahkan lokasi di dalam scope
+	rec = ta.do(http.MethodPost, "/v1/admin/locations/reassign", token,
+		models.ReassignInput{Filter: models.ReassignFilter{CreatedBy: userEmail}, Target: adminEmail})
+	expect(t, rec, http.StatusAccepted, "")
+	var job struct {
+		Data models.Reassignment `json:"data"`
+	}
+	decode(t, rec, &job)
+	if job.Data.Matched != 1 || job.Data.Filter.Scope == nil {
+		t.Fatalf("job reassign moderator ber-scope %+v", job.Data)
+	}
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+func TestSecurityCheckWeakJWTSecret(t *testing.T) {
+	jwtCheck := func(ta *testApp) models.SecurityCheck {
+		t.Helper()
+		rec := ta.do(http.MethodGet, "/v1/admin/security-check", ta.token(adminEmail), nil)
+		expect(t, rec, http.StatusOK, "")
+		var body struct {
+			Checks []models.SecurityCheck `json:"checks"`
+		}
+		decode(t, rec, &body)
+		for _, chk := range body.Checks {
+			if chk.ID == "jwt_secret_weak" {
+				return chk
+			}
+		}
+		t.Fatalf("cek jwt_secret_weak tidak ada: %+v", body.Checks)
+		return models.SecurityCheck{}
+	}
+
+	// Secret test harness pendek dan mengandung kata "secret"
+	if chk := jwtCheck(newTestApp(t)); chk.Status != "warn" || !strings.Contains(chk.Message, "minimal 32") {
+		t.Fatalf("secret pendek: %+v", chk)
+	}
+	placeholder := newTestAppWithEnv(t, map[string]string{"JWT_SECRET": "changeme-0123456789abcdefghijklmnop"})
+	if chk := jwtCheck(placeholder); chk.Status != "warn" || !strings.Contains(chk.Message, "changeme") {
+		t.Fatalf("secret placeholder: %+v", chk)
+	}
+	strong := newTestAppWithEnv(t, map[string]string{"JWT_SECRET": "q7Vx2LmP9sK4tR8wZ1nB6cY3hJ5fD0gA"})
+	if chk := jwtCheck(strong); chk.Status != "ok" {
+		t.Fatalf("secret acak: %+v", chk)
+	}
+}
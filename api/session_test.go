@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"InfoCuy-Backend/internal/auth"
+	"InfoCuy-Backend/internal/fakedata"
+	"InfoCuy-Backend/internal/models"
+)
+
+func TestLoginDeviceAlerts(t *testing.T) {
+	// SendGrid palsu: simpan isi setiap email ke user
+	var mu sync.Mutex
+	var sent []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Personalizations []struct {
+				To []struct{ Email string } `json:"to"`
+			} `json:"personalizations"`
+			Content []struct{ Value string } `json:"content"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		mu.Lock()
+		if payload.Personalizations[0].To[0].Email == userEmail {
+			sent = append(sent, payload.Content[0].Value)
+		}
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+	t.Setenv("MAIL_PROVIDER", "sendgrid")
+	t.Setenv("SENDGRID_API_KEY", "sg-test")
+	t.Setenv("SENDGRID_BASE_URL", srv.URL)
+	// Header negara hanya dipercaya dari platform yang dikonfigurasi
+	ta := newTestAppWithEnv(t, map[string]string{"TRUSTED_PLATFORM": "vercel"})
+
+	login := func(userAgent, country string) *httptest.ResponseRecorder {
+		t.Helper()
+		body, _ := json.Marshal(models.AuthInput{Email: userEmail, Password: fakedata.DefaultPassword})
+		req := httptest.NewRequest(http.MethodPost, "/v1/login", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", userAgent)
+		if country != "" {
+			req.Header.Set("X-Vercel-IP-Country", country)
+		}
+		rec := httptest.NewRecorder()
+		ta.app.Router.ServeHTTP(rec, req)
+		return rec
+	}
+	accessToken := func(rec *httptest.ResponseRecorder) string {
+		t.Helper()
+		expect(t, rec, http.StatusOK, "")
+		var body struct {
+			Token auth.TokenPair `json:"token"`
+		}
+		decode(t, rec, &body)
+		return body.Token.AccessToken
+	}
+	emails := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), sent...)
+	}
+	// lastToken mengambil token dari email terakhir ("token: ...")
+	lastToken := func() string {
+		t.Helper()
+		all := emails()
+		if len(all) == 0 {
+			t.Fatal("tidak ada email")
+		}
+		_, token, ok := strings.Cut(all[len(all)-1], "token: ")
+		if !ok {
+			t.Fatalf("email tanpa token: %s", all[len(all)-1])
+		}
+		return strings.TrimSpace(token)
+	}
+
+	chrome := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0 Safari/537.36"
+	token := accessToken(login(chrome, "ID"))
+	// Perangkat yang sama dengan versi browser lain tidak dianggap baru
+	accessToken(login(strings.Replace(chrome, "126.0", "127.0", 1), "ID"))
+	if all := emails(); len(all) != 0 {
+		t.Fatalf("login dari perangkat dikenal tidak boleh diperingatkan: %v", all)
+	}
+	accessToken(login("Mozilla/5.0 (Linux; Android 14) Gecko/20100101 Firefox/128.0", "SG"))
+	if all := emails(); len(all) != 1 || !strings.Contains(all[0], "Firefox di Android") || !strings.Contains(all[0], "Lokasi: SG") {
+		t.Fatalf("email peringatan %v", all)
+	}
+
+	rec := ta.do(http.MethodGet, "/v1/me/sessions", token, nil)
+	expect(t, rec, http.StatusOK, "")
+	var sessions struct {
+		Data []models.Session `json:"data"`
+	}
+	decode(t, rec, &sessions)
+	if len(sessions.Data) != 3 || !sessions.Data[0].NewDevice || !sessions.Data[0].NewLocation || sessions.Data[1].NewDevice {
+		t.Fatalf("riwayat sesi %+v", sessions.Data)
+	}
+
+	// Sesi dicabut per detik (presisi sid)
+	time.Sleep(time.Second)
+	report := lastToken()
+	expect(t, ta.do(http.MethodPost, "/v1/sessions/report", "", map[string]string{"token": "salah"}), http.StatusBadRequest, "INVALID_TOKEN")
+	expect(t, ta.do(http.MethodPost, "/v1/sessions/report", "", map[string]string{"token": report}), http.StatusOK, "")
+	expect(t, ta.do(http.MethodPost, "/v1/sessions/report", "", map[string]string{"token": report}), http.StatusBadRequest, "INVALID_TOKEN")
+	expect(t, ta.do(http.MethodGet, "/v1/me/quota", token, nil), http.StatusUnauthorized, "")
+	expect(t, login(chrome, "ID"), http.StatusForbidden, "PASSWORD_RESET_REQUIRED")
+
+	expect(t, ta.do(http.MethodPost, "/v1/password-reset/confirm", "", models.PasswordResetConfirm{Token: lastToken(), NewPassword: fakedata.DefaultPassword}),
+		http.StatusOK, "")
+	expect(t, ta.do(http.MethodGet, "/v1/me/quota", accessToken(login(chrome, "ID")), nil), http.StatusOK, "")
+}
+
+func TestLoginIgnoresSpoofedGeoHeaders(t *testing.T) {
+	ta := newTestApp(t)
+	body, _ := json.Marshal(models.AuthInput{Email: userEmail, Password: fakedata.DefaultPassword})
+	req := httptest.NewRequest(http.MethodPost, "/v1/login", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vercel-IP-Country", "ID")
+	req.Header.Set("X-Vercel-IP-City", "Bandung")
+	req.Header.Set("CF-IPCountry", "ID")
+	rec := httptest.NewRecorder()
+	ta.app.Router.ServeHTTP(rec, req)
+	expect(t, rec, http.StatusOK, "")
+
+	rec = ta.do(http.MethodGet, "/v1/me/sessions", ta.token(userEmail), nil)
+	expect(t, rec, http.StatusOK, "")
+	var sessions struct {
+		Data []models.Session `json:"data"`
+	}
+	decode(t, rec, &sessions)
+	// Tanpa TRUSTED_PLATFORM lokasi diturunkan dari IP koneksi (192.0.2.1)
+	if len(sessions.Data) == 0 || sessions.Data[len(sessions.Data)-1].Location != "Jaringan 192.0.0.0/16" {
+		t.Fatalf("riwayat sesi %+v", sessions.Data)
+	}
+}
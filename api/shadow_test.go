@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+func TestShadowTrafficSkipsSideEffects(t *testing.T) {
+	// Service bayangan palsu: catat path yang dicerminkan
+	mirrored := make(chan string, 16)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Shadow-Request") != "1" {
+			t.Errorf("request bayangan tanpa penanda: %s", r.URL)
+		}
+		mirrored <- r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	t.Setenv("SHADOW_TARGET", srv.URL)
+	t.Setenv("SHADOW_SAMPLE_PERCENT", "100")
+	ta := newTestApp(t)
+	admin, user := ta.token(adminEmail), ta.token(userEmail)
+
+	expect(t, ta.do(http.MethodGet, "/v1/locations/export", "", nil), http.StatusOK, "")
+	expect(t, ta.do(http.MethodGet, "/v1/me/quota", user, nil), http.StatusOK, "")
+	select {
+	case path := <-mirrored:
+		if path != "/v1/me/quota" {
+			t.Fatalf("route dengan efek samping ikut dicerminkan: %s", path)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("GET /v1/me/quota tidak dicerminkan")
+	}
+	// Dua worker Mirror bisa mengirim tidak berurutan
+	select {
+	case path := <-mirrored:
+		t.Fatalf("route dengan efek samping ikut dicerminkan: %s", path)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// Request dari Mirror tidak dihitung lagi: open campaign (repository
+	// memori tidak mendukungnya, jadi 500 jika tetap dicatat) dan statistik
+	// pemakaian
+	fromMirror := func(path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		req.Header.Set("X-Shadow-Request", "1")
+		rec := httptest.NewRecorder()
+		ta.app.Router.ServeHTTP(rec, req)
+		return rec
+	}
+	expect(t, fromMirror("/v1/campaigns/open/token-salah", ""), http.StatusOK, "")
+	expect(t, fromMirror("/v1/me/quota", user), http.StatusOK, "")
+
+	rec := ta.do(http.MethodGet, "/v1/admin/usage?consumer="+userEmail, admin, nil)
+	expect(t, rec, http.StatusOK, "")
+	var body struct {
+		Data models.UsageReport `json:"data"`
+	}
+	decode(t, rec, &body)
+	if len(body.Data.Consumers) != 1 || body.Data.Consumers[0].Requests != 1 {
+		t.Fatalf("pemakaian user %+v", body.Data.Consumers)
+	}
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"InfoCuy-Backend/internal/fakedata"
+	"InfoCuy-Backend/internal/models"
+)
+
+func TestPublicStats(t *testing.T) {
+	ta := newTestApp(t)
+	get := func() models.PublicStats {
+		t.Helper()
+		rec := ta.do(http.MethodGet, "/v1/stats", "", nil)
+		expect(t, rec, http.StatusOK, "")
+		if cc := rec.Header().Get("Cache-Control"); cc != "public, max-age=300" {
+			t.Fatalf("Cache-Control %q", cc)
+		}
+		var body struct {
+			Data models.PublicStats `json:"data"`
+		}
+		decode(t, rec, &body)
+		return body.Data
+	}
+	stats := get()
+	if stats.Locations != 5 || stats.Contributors != 1 || stats.Categories != len(fakedata.Categories()) || stats.LastUpdatedAt == nil {
+		t.Fatalf("stats %+v", stats)
+	}
+
+	// Angka di-cache, lokasi baru belum terhitung
+	loc := models.Location{Name: "Baru", Category: ta.location.Category, CreatedBy: otherEmail}
+	if err := ta.store.Locations().Create(context.Background(), &loc); err != nil {
+		t.Fatal(err)
+	}
+	if again := get(); again.Locations != 5 || !again.GeneratedAt.Equal(stats.GeneratedAt) {
+		t.Fatalf("cache tidak dipakai: %+v", again)
+	}
+}
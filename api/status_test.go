@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+func TestStatusPage(t *testing.T) {
+	ta := newTestApp(t)
+	admin, user := ta.token(adminEmail), ta.token(userEmail)
+	page := func() models.StatusPage {
+		t.Helper()
+		rec := ta.do(http.MethodGet, "/status.json", "", nil)
+		expect(t, rec, http.StatusOK, "")
+		if rec.Header().Get("Access-Control-Allow-Origin") != "*" || rec.Header().Get("Cache-Control") == "" {
+			t.Fatalf("header status.json %v", rec.Header())
+		}
+		var body models.StatusPage
+		decode(t, rec, &body)
+		return body
+	}
+
+	p := page()
+	names := map[string]models.StatusComponent{}
+	for _, comp := range p.Components {
+		names[comp.Name] = comp
+	}
+	for _, name := range []string{"database", "cache", "mail", "storage"} {
+		if _, ok := names[name]; !ok {
+			t.Fatalf("komponen %s tidak ada: %+v", name, p.Components)
+		}
+	}
+	if names["cache"].Status != models.StatusOperational || names["cache"].Uptime != 1 || len(p.Incidents) != 0 {
+		t.Fatalf("status awal %+v", p)
+	}
+
+	incident := map[string]interface{}{"title": "Email terlambat", "severity": "major", "components": []string{"mail"}}
+	expect(t, ta.do(http.MethodPost, "/v1/admin/status/incidents", user, incident), http.StatusForbidden, "FORBIDDEN")
+	expect(t, ta.do(http.MethodPost, "/v1/admin/status/incidents", admin, map[string]string{"title": "x", "severity": "fatal"}), http.StatusBadRequest, "VALIDATION_FAILED")
+	rec := ta.do(http.MethodPost, "/v1/admin/status/incidents", admin, incident)
+	expect(t, rec, http.StatusCreated, "")
+	var created struct {
+		Data models.StatusIncident `json:"data"`
+	}
+	decode(t, rec, &created)
+
+	// Incident baru langsung tampil karena cache dibuang saat disimpan
+	if p := page(); p.Status != models.StatusOutage || len(p.Incidents) != 1 || p.Incidents[0].ID != created.Data.ID {
+		t.Fatalf("incident berlangsung %+v", p)
+	}
+
+	incident["starts_at"] = time.Now().Add(-time.Hour)
+	incident["resolved_at"] = time.Now()
+	expect(t, ta.do(http.MethodPut, "/v1/admin/status/incidents/"+created.Data.ID, admin, incident), http.StatusOK, "")
+	expect(t, ta.do(http.MethodPut, "/v1/admin/status/incidents/tidak-ada", admin, incident), http.StatusNotFound, "STATUS_INCIDENT_NOT_FOUND")
+	if p := page(); p.Status == models.StatusOutage || len(p.Incidents) != 1 || p.Incidents[0].ResolvedAt == nil {
+		t.Fatalf("incident selesai %+v", p)
+	}
+
+	expect(t, ta.do(http.MethodDelete, "/v1/admin/status/incidents/"+created.Data.ID, admin, nil), http.StatusOK, "")
+	if p := page(); len(p.Incidents) != 0 {
+		t.Fatalf("setelah hapus %+v", p.Incidents)
+	}
+}
+
+// statusSettingsDown gagal membaca incident halaman status, seperti saat
+// MongoDB tidak bisa dijangkau; setting lain tetap dibaca dari store.
+type statusSettingsDown struct {
+	repositories.SettingsRepository
+	reads atomic.Int32
+}
+
+func (r *statusSettingsDown) Get(ctx context.Context, key string, out interface{}) error {
+	if key == "status_incidents" {
+		r.reads.Add(1)
+		return errors.New("server selection timeout")
+	}
+	return r.SettingsRepository.Get(ctx, key, out)
+}
+
+func TestStatusPageWithoutIncidents(t *testing.T) {
+	down := &statusSettingsDown{}
+	ta := newTestAppWithRepos(t, nil, func(r *Repositories) {
+		down.SettingsRepository = r.Settings
+		r.Settings = down
+	})
+	for range 3 {
+		rec := ta.do(http.MethodGet, "/status.json", "", nil)
+		expect(t, rec, http.StatusOK, "")
+		var p models.StatusPage
+		decode(t, rec, &p)
+		if p.Note == "" || len(p.Incidents) != 0 || len(p.Components) < 4 {
+			t.Fatalf("halaman status %+v", p)
+		}
+	}
+	// Hasil gagal ikut di-cache
+	if n := down.reads.Load(); n != 1 {
+		t.Fatalf("incident dibaca %d kali", n)
+	}
+}
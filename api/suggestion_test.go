@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+func TestCategorySuggestions(t *testing.T) {
+	// SendGrid palsu untuk menangkap email ke pengusul
+	var mu sync.Mutex
+	sent := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Personalizations []struct {
+				To []struct{ Email string } `json:"to"`
+			} `json:"personalizations"`
+			Content []struct{ Value string } `json:"content"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		mu.Lock()
+		sent[payload.Personalizations[0].To[0].Email] = payload.Content[0].Value
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+	t.Setenv("MAIL_PROVIDER", "sendgrid")
+	t.Setenv("SENDGRID_API_KEY", "sg-test")
+	t.Setenv("SENDGRID_BASE_URL", srv.URL)
+	ta := newTestApp(t)
+	admin, user, other := ta.token(adminEmail), ta.token(userEmail), ta.token(otherEmail)
+
+	suggest := func(token, name string) models.CategorySuggestion {
+		t.Helper()
+		rec := ta.do(http.MethodPost, "/v1/categories/suggest", token, map[string]string{
+			"name": name, "justification": "Tim lapangan sering menemukan lokasi jenis ini",
+		})
+		expect(t, rec, http.StatusCreated, "")
+		var body struct {
+			Data models.CategorySuggestion `json:"data"`
+		}
+		decode(t, rec, &body)
+		return body.Data
+	}
+	bengkel := suggest(user, "Bengkel Sepeda")
+	if bengkel.Slug != "bengkel-sepeda" || bengkel.Status != models.SuggestionPending {
+		t.Fatalf("usulan %+v", bengkel)
+	}
+	duplicate := suggest(other, "Reparasi Sepeda")
+	spam := suggest(other, "Tukang Parkir")
+
+	expect(t, ta.do(http.MethodPost, "/v1/categories/suggest", user, map[string]string{"name": "Pom Bensin"}), http.StatusBadRequest, "VALIDATION_FAILED")
+	existing := ta.location.Category
+	expect(t, ta.do(http.MethodPost, "/v1/categories/suggest", user, map[string]string{
+		"name": existing, "justification": "Tim lapangan sering menemukan lokasi jenis ini",
+	}), http.StatusBadRequest, "VALIDATION_FAILED")
+	expect(t, ta.do(http.MethodGet, "/v1/admin/category-suggestions", user, nil), http.StatusForbidden, "FORBIDDEN")
+
+	var queue struct {
+		Data []models.CategorySuggestion `json:"data"`
+		Meta models.PageMeta             `json:"meta"`
+	}
+	rec := ta.do(http.MethodGet, "/v1/admin/category-suggestions", admin, nil)
+	expect(t, rec, http.StatusOK, "")
+	decode(t, rec, &queue)
+	if queue.Meta.Total != 3 || queue.Data[0].ID != bengkel.ID {
+		t.Fatalf("antrean %+v", queue)
+	}
+
+	// Approve membuat kategori yang langsung bisa dipakai
+	expect(t, ta.do(http.MethodPost, "/v1/admin/category-suggestions/"+bengkel.ID.Hex()+"/approve", admin, map[string]string{"color": "#22AA55"}), http.StatusOK, "")
+	rec = ta.do(http.MethodGet, "/v1/categories/bengkel-sepeda", "", nil)
+	expect(t, rec, http.StatusOK, "")
+	var category models.Category
+	decode(t, rec, &category)
+	if category.Name != "Bengkel Sepeda" || category.Color != "#22AA55" {
+		t.Fatalf("kategori %+v", category)
+	}
+	expect(t, ta.do(http.MethodPost, "/v1/admin/category-suggestions/"+bengkel.ID.Hex()+"/reject", admin, nil), http.StatusConflict, "ALREADY_MODERATED")
+
+	merge := "/v1/admin/category-suggestions/" + duplicate.ID.Hex() + "/merge"
+	expect(t, ta.do(http.MethodPost, merge, admin, map[string]string{"category": "tidak-ada"}), http.StatusBadRequest, "VALIDATION_FAILED")
+	expect(t, ta.do(http.MethodPost, merge, admin, map[string]string{"category": "bengkel-sepeda"}), http.StatusOK, "")
+	expect(t, ta.do(http.MethodPost, "/v1/admin/category-suggestions/"+spam.ID.Hex()+"/reject", admin, map[string]string{
+		"reason": "Bukan jenis lokasi",
+	}), http.StatusOK, "")
+
+	rec = ta.do(http.MethodGet, "/v1/admin/category-suggestions?status=all", admin, nil)
+	decode(t, rec, &queue)
+	statuses := map[string]string{}
+	for _, s := range queue.Data {
+		statuses[s.Name] = s.Status + ":" + s.Category
+	}
+	if statuses["Bengkel Sepeda"] != "approved:bengkel-sepeda" || statuses["Reparasi Sepeda"] != "merged:bengkel-sepeda" || statuses["Tukang Parkir"] != "rejected:" {
+		t.Fatalf("status %v", statuses)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !strings.Contains(sent[userEmail], "disetujui") || !strings.Contains(sent[otherEmail], "Bukan jenis lokasi") {
+		t.Fatalf("email pengusul %v", sent)
+	}
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+// gtfsZip membuat zip GTFS minimal dengan satu halte yang dilayani satu rute.
+func gtfsZip(t *testing.T, stopName string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"routes.txt":     "route_id,route_short_name,route_type\nR1,K1,3\n",
+		"trips.txt":      "route_id,service_id,trip_id\nR1,S,T1\n",
+		"stop_times.txt": "trip_id,arrival_time,departure_time,stop_id,stop_sequence\nT1,08:00:00,08:00:00,H1,1\n",
+		"stops.txt":      "stop_id,stop_name,stop_lat,stop_lon\nH1," + stopName + ",-6.9175,107.6091\n",
+	} {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestTransitFeedSync(t *testing.T) {
+	// Server partner: ETag mengikuti versi feed; tanpa ETag setelah sendETag
+	// dimatikan, supaya pemeriksaan hash isi ikut teruji
+	feed, etag, sendETag, fetches := gtfsZip(t, "Halte Braga"), `"v1"`, true, 0
+	partner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		if sendETag {
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+		}
+		w.Write(feed)
+	}))
+	defer partner.Close()
+
+	ta := newTestApp(t)
+	admin, user := ta.token(adminEmail), ta.token(userEmail)
+	expect(t, ta.do(http.MethodPut, "/v1/admin/transit/sources/damri", user, map[string]interface{}{"url": partner.URL}), http.StatusForbidden, "FORBIDDEN")
+	expect(t, ta.do(http.MethodPut, "/v1/admin/transit/sources/damri", admin, map[string]interface{}{"url": "ftp://x"}), http.StatusBadRequest, "VALIDATION_FAILED")
+	expect(t, ta.do(http.MethodPut, "/v1/admin/transit/sources/damri", admin, map[string]interface{}{"url": partner.URL, "interval_minutes": 1}), http.StatusBadRequest, "VALIDATION_FAILED")
+	expect(t, ta.do(http.MethodPut, "/v1/admin/transit/sources/damri", admin, map[string]interface{}{"url": partner.URL}), http.StatusOK, "")
+
+	sync := func(query string) models.TransitSyncResult {
+		t.Helper()
+		rec := ta.do(http.MethodPost, "/v1/admin/jobs/transit-sync"+query, admin, nil)
+		expect(t, rec, http.StatusOK, "")
+		var result models.TransitSyncResult
+		decode(t, rec, &result)
+		return result
+	}
+	first := sync("")
+	if first.Imported != 1 || first.Sources[0].Status != models.FeedImported || first.Sources[0].Result.Stops != 1 {
+		t.Fatalf("sync pertama %+v", first)
+	}
+	// Belum jatuh tempo: server tidak dihubungi
+	if idle := sync(""); idle.Checked != 0 || fetches != 1 {
+		t.Fatalf("sync belum jatuh tempo %+v, fetch %d", idle, fetches)
+	}
+	if r := sync("?force=true"); r.Unchanged != 1 || r.Sources[0].Status != models.FeedNotModified {
+		t.Fatalf("sync 304 %+v", r)
+	}
+	sendETag = false
+	if r := sync("?force=true"); r.Unchanged != 1 || r.Sources[0].Status != models.FeedUnchanged {
+		t.Fatalf("sync isi sama %+v", r)
+	}
+	feed = gtfsZip(t, "Halte Braga Baru")
+	if r := sync("?force=true"); r.Imported != 1 || r.Sources[0].ImportedAt == nil {
+		t.Fatalf("sync feed baru %+v", r)
+	}
+
+	// Feed rusak dicatat gagal tanpa menggagalkan job
+	feed = []byte("bukan zip")
+	if r := sync("?force=true"); r.Failed != 1 || r.Sources[0].Error == "" {
+		t.Fatalf("sync feed rusak %+v", r)
+	}
+
+	expect(t, ta.do(http.MethodDelete, "/v1/admin/transit/sources/damri", admin, nil), http.StatusOK, "")
+	expect(t, ta.do(http.MethodDelete, "/v1/admin/transit/sources/damri", admin, nil), http.StatusNotFound, "TRANSIT_SOURCE_NOT_FOUND")
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+// fakeS3 menyimpan object di memori dan menjawab PUT, HEAD, GET (dengan
+// Range) dan DELETE seperti S3.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+	// Query request PUT terakhir, untuk memeriksa tanda tangan presigned
+	lastPut string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, ok := f.objects[r.URL.Path]
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path], f.types[r.URL.Path], f.lastPut = body, r.Header.Get("Content-Type"), r.URL.RawQuery
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+	case http.MethodHead, http.MethodGet:
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", f.types[r.URL.Path])
+		var end int
+		if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=0-%d", &end); err == nil && end+1 < len(data) {
+			data = data[:end+1]
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+	}
+}
+
+func TestPresignedPhotoUpload(t *testing.T) {
+	s3 := &fakeS3{objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(s3)
+	defer server.Close()
+	for k, v := range map[string]string{"OBJECT_STORE": "s3", "S3_ENDPOINT": server.URL, "S3_BUCKET": "foto",
+		"S3_ACCESS_KEY_ID": "key", "S3_SECRET_ACCESS_KEY": "secret"} {
+		t.Setenv(k, v)
+	}
+	ta := newTestApp(t)
+	user := ta.token(userEmail)
+	loc := createLocation(t, ta, user, "Kopi Foto Besar")
+
+	presign := func(body map[string]interface{}) models.PresignedUpload {
+		t.Helper()
+		rec := ta.do(http.MethodPost, "/v1/uploads/presign", user, body)
+		expect(t, rec, http.StatusOK, "")
+		var res struct {
+			Data models.PresignedUpload `json:"data"`
+		}
+		decode(t, rec, &res)
+		return res.Data
+	}
+	put := func(u models.PresignedUpload, data []byte) {
+		t.Helper()
+		req, _ := http.NewRequest(u.Method, u.URL, bytes.NewReader(data))
+		for k, v := range u.Headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	expect(t, ta.do(http.MethodPost, "/v1/uploads/presign", user, map[string]interface{}{"location_id": loc.ID, "content_type": "application/pdf", "size_bytes": 100}), http.StatusBadRequest, "VALIDATION_FAILED")
+	expect(t, ta.do(http.MethodPost, "/v1/uploads/presign", ta.token(otherEmail), map[string]interface{}{"location_id": loc.ID, "content_type": "image/png", "size_bytes": 100}), http.StatusForbidden, "")
+
+	var img bytes.Buffer
+	png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 40, 30)))
+	upload := presign(map[string]interface{}{"location_id": loc.ID, "content_type": "image/png", "size_bytes": img.Len()})
+	if !strings.Contains(upload.URL, "X-Amz-Signature=") || !strings.HasSuffix(upload.UploadID, ".png") {
+		t.Fatalf("presign %+v", upload)
+	}
+	confirm := map[string]interface{}{"location_id": loc.ID, "upload_id": upload.UploadID}
+	// Belum diupload
+	expect(t, ta.do(http.MethodPost, "/v1/uploads/confirm", user, confirm), http.StatusBadRequest, "VALIDATION_FAILED")
+
+	put(upload, img.Bytes())
+	if !strings.Contains(s3.lastPut, "X-Amz-SignedHeaders=content-length%3Bcontent-type%3Bhost") || upload.Headers["Content-Length"] != fmt.Sprint(img.Len()) {
+		t.Fatalf("query PUT %s", s3.lastPut)
+	}
+	rec := ta.do(http.MethodPost, "/v1/uploads/confirm", user, confirm)
+	expect(t, rec, http.StatusCreated, "")
+	var res struct {
+		Data models.Photo `json:"data"`
+	}
+	decode(t, rec, &res)
+	if res.Data.Width != 40 || res.Data.Height != 30 || res.Data.SizeBytes != int64(img.Len()) || res.Data.ContentType != "image/png" {
+		t.Fatalf("foto %+v", res.Data)
+	}
+	// Konfirmasi ulang tidak menambah foto kedua
+	expect(t, ta.do(http.MethodPost, "/v1/uploads/confirm", user, confirm), http.StatusCreated, "")
+	rec = ta.do(http.MethodGet, "/v1/locations/"+loc.ID.Hex(), user, nil)
+	var got struct {
+		Data models.Location `json:"data"`
+	}
+	decode(t, rec, &got)
+	if len(got.Data.Photos) != 1 {
+		t.Fatalf("foto lokasi %+v", got.Data.Photos)
+	}
+
+	// Isi yang bukan gambar ditolak dan dihapus dari storage
+	notImage := []byte("bukan gambar sama sekali")
+	fake := presign(map[string]interface{}{"location_id": loc.ID, "content_type": "image/jpeg", "size_bytes": len(notImage)})
+	put(fake, notImage)
+	expect(t, ta.do(http.MethodPost, "/v1/uploads/confirm", user, map[string]interface{}{"location_id": loc.ID, "upload_id": fake.UploadID}), http.StatusBadRequest, "VALIDATION_FAILED")
+	if _, ok := s3.objects["/foto/locations/"+loc.ID.Hex()+"/"+fake.UploadID]; ok {
+		t.Fatal("upload tidak valid masih tersimpan")
+	}
+}
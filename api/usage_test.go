@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strings"
+	"testing"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+func TestAPIUsage(t *testing.T) {
+	ta := newTestApp(t)
+	admin, user := ta.token(adminEmail), ta.token(userEmail)
+
+	for range 3 {
+		expect(t, ta.do(http.MethodGet, "/v1/me/quota", user, nil), http.StatusOK, "")
+	}
+	expect(t, ta.do(http.MethodGet, "/v1/tidak-ada", "", nil), http.StatusNotFound, "ROUTE_NOT_FOUND")
+	expect(t, ta.do(http.MethodGet, "/v1/admin/usage", user, nil), http.StatusForbidden, "FORBIDDEN")
+	expect(t, ta.do(http.MethodGet, "/v1/admin/usage?from=2026-01-10&to=2026-01-01", admin, nil), http.StatusBadRequest, "VALIDATION_FAILED")
+
+	rec := ta.do(http.MethodGet, "/v1/admin/usage?consumer="+userEmail, admin, nil)
+	expect(t, rec, http.StatusOK, "")
+	var body struct {
+		Data models.UsageReport `json:"data"`
+	}
+	decode(t, rec, &body)
+	if len(body.Data.Consumers) != 1 {
+		t.Fatalf("consumer %+v", body.Data.Consumers)
+	}
+	c := body.Data.Consumers[0]
+	// 3x quota dan 1x /admin/usage yang ditolak
+	if c.ConsumerType != models.ConsumerUser || c.Requests != 4 || c.ClientErrors != 1 || c.ErrorRate != 0.25 || c.BytesOut == 0 {
+		t.Fatalf("pemakaian user %+v", c)
+	}
+	if e := c.Endpoints[0]; e.Route != "/v1/me/quota" || e.Requests != 3 {
+		t.Fatalf("endpoint teratas %+v", e)
+	}
+
+	// Request anonim (login, route tidak ada) dikelompokkan per IP
+	rec = ta.do(http.MethodGet, "/v1/admin/usage?format=csv", admin, nil)
+	expect(t, rec, http.StatusOK, "")
+	rows, err := csv.NewReader(strings.NewReader(rec.Body.String())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var anonymous, unmatched bool
+	for _, row := range rows[1:] {
+		anonymous = anonymous || (row[1] == models.ConsumerIP && row[3] == "/v1/login")
+		unmatched = unmatched || row[3] == "*"
+	}
+	if rows[0][0] != "consumer" || !anonymous || !unmatched {
+		t.Fatalf("csv %v", rows)
+	}
+}
@@ -0,0 +1,63 @@
+// Doctor memeriksa konfigurasi dan dependency deployment lalu mencetak
+// hasil per pemeriksaan. Exit code 1 jika ada yang gagal.
+//
+//	go run ./cmd/doctor
+//	go run ./cmd/doctor -json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"InfoCuy-Backend/internal/config"
+	"InfoCuy-Backend/internal/database"
+	"InfoCuy-Backend/internal/doctor"
+	"InfoCuy-Backend/internal/mailer"
+	"InfoCuy-Backend/internal/objectstore"
+)
+
+// Batas waktu koneksi awal ke MongoDB
+const connectTimeout = 10 * time.Second
+
+func main() {
+	asJSON := flag.Bool("json", false, "cetak hasil sebagai JSON")
+	flag.Parse()
+
+	ctx := context.Background()
+	opts := doctor.Options{Mailer: mailer.NewFromEnv(), Store: objectstore.NewFromEnv()}
+	opts.Config, opts.ConfigErr = config.Load()
+	if opts.Config != nil {
+		connectCtx, cancel := context.WithTimeout(ctx, connectTimeout)
+		opts.DB, opts.ConnectErr = database.Connect(connectCtx, database.Options{URI: opts.Config.Mongo.URI, Database: opts.Config.Mongo.Database})
+		cancel()
+		if opts.DB != nil {
+			defer opts.DB.Client().Disconnect(context.Background())
+		}
+	}
+	report := doctor.New(opts).Run(ctx)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(report)
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, c := range report.Checks {
+			latency := ""
+			if c.LatencyMS > 0 {
+				latency = fmt.Sprintf("%.0fms", c.LatencyMS)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", strings.ToUpper(c.Status), c.Name, latency, c.Message)
+		}
+		w.Flush()
+	}
+	if !report.OK {
+		os.Exit(1)
+	}
+}
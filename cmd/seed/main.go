@@ -0,0 +1,78 @@
+// Seeder mengisi database dengan data palsu dari internal/fakedata.
+//
+//	go run ./cmd/seed -seed 42 -users 50 -locations 1000
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+
+	"InfoCuy-Backend/internal/auth"
+	"InfoCuy-Backend/internal/config"
+	"InfoCuy-Backend/internal/database"
+	"InfoCuy-Backend/internal/fakedata"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func main() {
+	seed := flag.Int64("seed", 1, "seed generator (data sama untuk seed sama)")
+	nUsers := flag.Int("users", 20, "jumlah user")
+	nLocations := flag.Int("locations", 200, "jumlah lokasi")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx := context.Background()
+	db, err := database.Connect(ctx, database.Options{URI: cfg.Mongo.URI, Database: cfg.Mongo.Database})
+	if err != nil {
+		log.Fatal(err)
+	}
+	userRepo := repositories.NewUserRepository(db.Collection("user"))
+	locationRepo := repositories.NewLocationRepository(db.Collection("geo_data"), db.Collection("geo_data_archive"), db.Collection("reviews"), db.Collection("favorites"), db.Collection("checkins"))
+	categoryRepo := repositories.NewCategoryRepository(db.Collection("categories"))
+	mapViewRepo := repositories.NewMapViewRepository(db.Collection("map_view"), db.Collection("geo_data"), db.Collection("reviews"))
+
+	gen := fakedata.New(*seed)
+	users := gen.Users(*nUsers)
+	locations := gen.Locations(*nLocations, users)
+
+	// Hash sekali saja, semua user memakai password yang sama
+	hash, err := auth.HashPassword(fakedata.DefaultPassword)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for i := range users {
+		users[i].Password = hash
+		if err := userRepo.Create(ctx, &users[i]); err != nil {
+			log.Fatalf("user %s: %v", users[i].Email, err)
+		}
+	}
+	// Kategori dibuat dulu supaya lokasi seed lolos validasi kategori
+	for _, c := range fakedata.Categories() {
+		if _, err := categoryRepo.FindBySlug(ctx, c.Slug); errors.Is(err, repositories.ErrNotFound) {
+			if err := categoryRepo.Create(ctx, &c); err != nil {
+				log.Fatalf("kategori %s: %v", c.Slug, err)
+			}
+		}
+	}
+	if err := locationRepo.CreateMany(ctx, locations); err != nil {
+		log.Fatal(err)
+	}
+	// Seeder menulis langsung ke geo_data, jadi read model diisi manual
+	ids := make([]primitive.ObjectID, len(locations))
+	for i, loc := range locations {
+		ids[i] = loc.ID
+	}
+	if err := mapViewRepo.Project(ctx, ids); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("🌱 %d user dan %d lokasi dibuat (seed %d, password %q)\n",
+		len(users), len(locations), *seed, fakedata.DefaultPassword)
+}
@@ -0,0 +1,36 @@
+// Package adminui berisi UI admin minimal (HTML, CSS dan JS tanpa build
+// step) yang di-embed ke binary dan dilayani di /admin. Halaman ini tidak
+// membawa data apa pun; semua data diambil dari API /v1 memakai token
+// login admin, jadi izin tetap diperiksa per endpoint oleh RBAC.
+package adminui
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed static
+var files embed.FS
+
+// AssetPrefix adalah path tempat Assets dipasang.
+const AssetPrefix = "/admin/assets/"
+
+// IndexHTML mengembalikan halaman utama UI admin.
+func IndexHTML() []byte {
+	b, err := files.ReadFile("static/index.html")
+	if err != nil {
+		// File di-embed saat build, tidak mungkin hilang saat runtime
+		panic("adminui: " + err.Error())
+	}
+	return b
+}
+
+// Assets melayani CSS dan JS UI admin di bawah AssetPrefix.
+func Assets() http.Handler {
+	static, err := fs.Sub(files, "static")
+	if err != nil {
+		panic("adminui: " + err.Error())
+	}
+	return http.StripPrefix(AssetPrefix, http.FileServer(http.FS(static)))
+}
@@ -0,0 +1,308 @@
+// UI admin InfoCuy. Semua data diambil dari API /v1 dengan token yang
+// disimpan di sessionStorage (hilang saat tab ditutup).
+(function () {
+  "use strict";
+
+  var session = JSON.parse(sessionStorage.getItem("infocuy-admin") || "null");
+  var pendingPage = 1;
+
+  function $(sel) { return document.querySelector(sel); }
+
+  function el(tag, text, cls) {
+    var e = document.createElement(tag);
+    if (text !== undefined && text !== null) e.textContent = text;
+    if (cls) e.className = cls;
+    return e;
+  }
+
+  // Body error API: {"error": {"code": "...", "message": "..."}}
+  function errorMessage(data, res) {
+    return (data.error && data.error.message) || ("HTTP " + res.status);
+  }
+
+  function fmtTime(v) { return v ? new Date(v).toLocaleString("id-ID") : "-"; }
+
+  function flash(msg, isError) {
+    var f = $("#flash");
+    f.textContent = msg;
+    f.className = isError ? "error" : "";
+    f.hidden = false;
+    clearTimeout(flash.timer);
+    flash.timer = setTimeout(function () { f.hidden = true; }, 5000);
+  }
+
+  function save(s) {
+    session = s;
+    if (s) sessionStorage.setItem("infocuy-admin", JSON.stringify(s));
+    else sessionStorage.removeItem("infocuy-admin");
+  }
+
+  // api memanggil endpoint JSON; access token yang kedaluwarsa diperbarui
+  // sekali dengan refresh token sebelum user diminta login ulang.
+  function api(method, path, body, retried) {
+    var opts = { method: method, headers: { "Accept": "application/json" } };
+    if (session) opts.headers["Authorization"] = "Bearer " + session.token.access_token;
+    if (body !== undefined) {
+      opts.headers["Content-Type"] = "application/json";
+      opts.body = JSON.stringify(body);
+    }
+    return fetch(path, opts).then(function (res) {
+      if (res.status === 401 && session && !retried) {
+        return refresh().then(function () { return api(method, path, body, true); });
+      }
+      return res.json().catch(function () { return {}; }).then(function (data) {
+        if (res.status === 401) logout();
+        if (!res.ok) {
+          var err = new Error(errorMessage(data, res));
+          err.status = res.status;
+          err.code = data.error && data.error.code;
+          throw err;
+        }
+        return data;
+      });
+    });
+  }
+
+  function refresh() {
+    return fetch("/v1/refresh", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ refresh_token: session.token.refresh_token })
+    }).then(function (res) {
+      if (!res.ok) throw new Error("Sesi berakhir, silakan masuk lagi");
+      return res.json();
+    }).then(function (data) {
+      save({ user: session.user, token: data.token });
+    }).catch(function (err) {
+      logout();
+      throw err;
+    });
+  }
+
+  function logout() {
+    save(null);
+    $("#app").hidden = true;
+    $("#login").hidden = false;
+  }
+
+  function showApp() {
+    $("#login").hidden = true;
+    $("#app").hidden = false;
+    $("#whoami").textContent = session.user.email + " (" + session.user.role + ")";
+    route();
+  }
+
+  // === Ringkasan ===
+  function total(path) {
+    return api("GET", path).then(function (d) { return d.meta.total; }, function () { return "-"; });
+  }
+
+  function loadStats() {
+    total("/v1/locations?limit=1").then(function (n) { $("#stat-locations").textContent = n; });
+    total("/v1/admin/locations/pending?limit=1").then(function (n) { $("#stat-pending").textContent = n; });
+    api("GET", "/v1/users").then(function (users) {
+      $("#stat-users").textContent = users.length;
+    }, function () { $("#stat-users").textContent = "-"; });
+
+    var rows = $("#doctor-rows");
+    rows.textContent = "";
+    api("GET", "/v1/admin/doctor").then(function (report) {
+      var stat = $("#stat-doctor");
+      stat.textContent = report.ok ? "OK" : "GAGAL";
+      stat.className = report.ok ? "pass" : "fail";
+      report.checks.forEach(function (chk) {
+        var tr = el("tr");
+        tr.appendChild(el("td", chk.name));
+        tr.appendChild(el("td", chk.status, chk.status));
+        tr.appendChild(el("td", chk.message));
+        tr.appendChild(el("td", chk.latency_ms !== undefined ? chk.latency_ms + " ms" : ""));
+        rows.appendChild(tr);
+      });
+    }, function (err) {
+      $("#stat-doctor").textContent = "-";
+      var tr = el("tr");
+      var td = el("td", err.message, "error");
+      td.colSpan = 4;
+      tr.appendChild(td);
+      rows.appendChild(tr);
+    });
+  }
+
+  // === Moderasi ===
+  function loadPending() {
+    var rows = $("#pending-rows");
+    api("GET", "/v1/admin/locations/pending?page=" + pendingPage).then(function (res) {
+      rows.textContent = "";
+      if (res.data.length === 0) {
+        var td = el("td", "Tidak ada lokasi yang menunggu review");
+        td.colSpan = 6;
+        rows.appendChild(el("tr")).appendChild(td);
+      }
+      res.data.forEach(function (loc) {
+        var tr = el("tr");
+        tr.appendChild(el("td", loc.name));
+        tr.appendChild(el("td", loc.category));
+        tr.appendChild(el("td", loc.address));
+        tr.appendChild(el("td", loc.created_by));
+        tr.appendChild(el("td", fmtTime(loc.created_at)));
+        var actions = el("td", null, "actions");
+        var approve = el("button", "Setujui");
+        approve.onclick = function () { moderate(loc, "approve"); };
+        var reject = el("button", "Tolak", "danger");
+        reject.onclick = function () { moderate(loc, "reject"); };
+        actions.appendChild(approve);
+        actions.appendChild(reject);
+        tr.appendChild(actions);
+        rows.appendChild(tr);
+      });
+      var meta = res.meta;
+      $("#pending-meta").textContent = "Halaman " + meta.page + " dari " + Math.max(meta.total_pages, 1) + " (" + meta.total + " lokasi)";
+      document.querySelectorAll(".pending-page").forEach(function (b) {
+        var next = meta.page + Number(b.dataset.page);
+        b.disabled = next < 1 || next > meta.total_pages;
+      });
+    }).catch(function (err) { flash(err.message, true); });
+  }
+
+  function moderate(loc, action) {
+    var body;
+    if (action === "reject") {
+      var reason = prompt("Alasan penolakan \"" + loc.name + "\" (opsional, dikirim ke pengirim):", "");
+      if (reason === null) return;
+      body = { reason: reason };
+    }
+    api("POST", "/v1/admin/locations/" + loc._id + "/" + action, body).then(function (res) {
+      flash(res.message);
+      loadPending();
+    }).catch(function (err) { flash(err.message, true); });
+  }
+
+  // === User ===
+  function loadUsers() {
+    var rows = $("#user-rows");
+    var roles = api("GET", "/v1/admin/roles").then(function (list) {
+      return list.map(function (r) { return r.name; });
+    }, function () { return null; });
+    Promise.all([api("GET", "/v1/users"), roles]).then(function (res) {
+      var users = res[0], roleNames = res[1];
+      rows.textContent = "";
+      users.forEach(function (u) {
+        var tr = el("tr");
+        tr.appendChild(el("td", u.email));
+        var roleCell = el("td");
+        if (roleNames) {
+          var select = el("select");
+          roleNames.forEach(function (name) {
+            var opt = el("option", name);
+            opt.value = name;
+            opt.selected = name === u.role;
+            select.appendChild(opt);
+          });
+          select.onchange = function () {
+            api("PUT", "/v1/users/" + u.id + "/role", { role: select.value }).then(function (r) {
+              flash(r.message + ": " + u.email);
+            }).catch(function (err) {
+              select.value = u.role;
+              flash(err.message, true);
+            });
+          };
+          roleCell.appendChild(select);
+        } else {
+          roleCell.textContent = u.role;
+        }
+        tr.appendChild(roleCell);
+        tr.appendChild(el("td", fmtTime(u.last_login_at)));
+        var actions = el("td", null, "actions");
+        var del = el("button", "Hapus", "danger");
+        del.disabled = u.email === session.user.email;
+        del.onclick = function () {
+          if (!confirm("Hapus user " + u.email + "?")) return;
+          api("DELETE", "/v1/users/" + u.id).then(function (r) {
+            flash(r.message);
+            loadUsers();
+          }).catch(function (err) { flash(err.message, true); });
+        };
+        actions.appendChild(del);
+        tr.appendChild(actions);
+        rows.appendChild(tr);
+      });
+    }).catch(function (err) { flash(err.message, true); });
+  }
+
+  // === Pengaturan ===
+  function loadSettings() {
+    document.querySelectorAll("form.settings").forEach(function (form) {
+      api("GET", form.dataset.path).then(function (data) {
+        form.json.value = JSON.stringify(data, null, 2);
+      }).catch(function (err) { form.json.value = ""; flash(err.message, true); });
+    });
+  }
+
+  document.querySelectorAll("form.settings").forEach(function (form) {
+    form.onsubmit = function (e) {
+      e.preventDefault();
+      var body;
+      try {
+        body = JSON.parse(form.json.value);
+      } catch (err) {
+        flash("JSON tidak valid: " + err.message, true);
+        return;
+      }
+      api("PUT", form.dataset.path, body).then(function (r) {
+        flash(r.message);
+        loadSettings();
+      }).catch(function (err) { flash(err.message, true); });
+    };
+  });
+
+  // === Navigasi ===
+  var pages = { stats: loadStats, moderation: loadPending, users: loadUsers, settings: loadSettings };
+
+  function route() {
+    var name = location.hash.slice(1);
+    if (!pages[name]) name = "stats";
+    document.querySelectorAll(".page").forEach(function (p) {
+      p.classList.toggle("active", p.id === "page-" + name);
+    });
+    document.querySelectorAll("header nav a").forEach(function (a) {
+      a.classList.toggle("active", a.getAttribute("href") === "#" + name);
+    });
+    pages[name]();
+  }
+
+  window.addEventListener("hashchange", function () { if (session) route(); });
+
+  document.querySelectorAll(".pending-page").forEach(function (b) {
+    b.onclick = function () {
+      pendingPage += Number(b.dataset.page);
+      loadPending();
+    };
+  });
+
+  $("#login-form").onsubmit = function (e) {
+    e.preventDefault();
+    var form = e.target;
+    $("#login-error").textContent = "";
+    fetch("/v1/login", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ email: form.email.value, password: form.password.value })
+    }).then(function (res) {
+      return res.json().then(function (data) {
+        if (!res.ok) throw new Error(errorMessage(data, res));
+        return data;
+      });
+    }).then(function (data) {
+      form.password.value = "";
+      save({ user: data.user, token: data.token });
+      showApp();
+    }).catch(function (err) {
+      $("#login-error").textContent = err.message;
+    });
+  };
+
+  $("#logout").onclick = logout;
+
+  if (session) showApp();
+  else logout();
+})();
@@ -0,0 +1,118 @@
+// Package apperr mendefinisikan error aplikasi yang dikirim ke client.
+// Setiap error punya kode stabil (mis. LOCATION_NOT_FOUND) yang boleh
+// dipakai client untuk percabangan, status HTTP dan pesan untuk manusia.
+// Format response-nya:
+//
+//	{"error": {"code": "...", "message": "...", ...}, "request_id": "..."}
+//
+// Cause hanya untuk log server dan tidak pernah dikirim ke client.
+package apperr
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kode error yang dipakai di lebih dari satu tempat. Kode NOT_FOUND per
+// resource dibentuk dengan NotFound.
+const (
+	CodeValidation         = "VALIDATION_FAILED"
+	CodeInvalidObjectID    = "INVALID_OBJECT_ID"
+	CodeUnauthorized       = "UNAUTHORIZED"
+	CodeInvalidCredentials = "INVALID_CREDENTIALS"
+	CodeInvalidToken       = "INVALID_TOKEN"
+	CodeForbidden          = "FORBIDDEN"
+	CodeFieldForbidden     = "FIELD_FORBIDDEN"
+	CodeQuotaExceeded      = "QUOTA_EXCEEDED"
+	CodeRegistrationClosed = "REGISTRATION_CLOSED"
+	CodeNotFound           = "NOT_FOUND"
+	CodeRouteNotFound      = "ROUTE_NOT_FOUND"
+	CodeConflict           = "CONFLICT"
+	CodeEmailTaken         = "EMAIL_TAKEN"
+	CodeRateLimited        = "RATE_LIMITED"
+	CodeAccountLocked      = "ACCOUNT_LOCKED"
+	CodeUpstreamFailed     = "UPSTREAM_FAILED"
+	CodeUnavailable        = "SERVICE_UNAVAILABLE"
+	CodeDatabaseReadOnly   = "DATABASE_READ_ONLY"
+	CodeInternal           = "INTERNAL_ERROR"
+)
+
+// Error adalah error aplikasi beserta status HTTP-nya.
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	// Field tambahan di objek error (mis. fields, quota, retry_after)
+	Details map[string]interface{}
+	Cause   error
+}
+
+// New membuat Error tanpa detail.
+func New(status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return e.Code + ": " + e.Message + ": " + e.Cause.Error()
+	}
+	return e.Code + ": " + e.Message
+}
+
+func (e *Error) Unwrap() error { return e.Cause }
+
+// With mengembalikan salinan e dengan field tambahan key.
+func (e *Error) With(key string, value interface{}) *Error {
+	cp := *e
+	cp.Details = make(map[string]interface{}, len(e.Details)+1)
+	for k, v := range e.Details {
+		cp.Details[k] = v
+	}
+	cp.Details[key] = value
+	return &cp
+}
+
+// Wrap mengembalikan salinan e dengan cause untuk log.
+func (e *Error) Wrap(cause error) *Error {
+	cp := *e
+	cp.Cause = cause
+	return &cp
+}
+
+// Body adalah isi objek "error" pada response.
+func (e *Error) Body() map[string]interface{} {
+	body := make(map[string]interface{}, len(e.Details)+2)
+	for k, v := range e.Details {
+		body[k] = v
+	}
+	body["code"] = e.Code
+	body["message"] = e.Message
+	return body
+}
+
+// As mengambil *Error dari rantai err.
+func As(err error) (*Error, bool) {
+	var e *Error
+	ok := errors.As(err, &e)
+	return e, ok
+}
+
+// BadRequest adalah error input umum (400 VALIDATION_FAILED).
+func BadRequest(message string) *Error {
+	return New(http.StatusBadRequest, CodeValidation, message)
+}
+
+// InvalidObjectID dipakai jika parameter path bukan ObjectID yang valid.
+func InvalidObjectID(param string) *Error {
+	return New(http.StatusBadRequest, CodeInvalidObjectID, param+" harus berupa ID yang valid").With("param", param)
+}
+
+// NotFound membuat error 404 dengan kode <RESOURCE>_NOT_FOUND.
+func NotFound(resource, message string) *Error {
+	return New(http.StatusNotFound, resource+"_NOT_FOUND", message)
+}
+
+// Internal menyembunyikan cause dari client.
+func Internal(cause error) *Error {
+	return New(http.StatusInternalServerError, CodeInternal, "Terjadi kesalahan pada server").Wrap(cause)
+}
@@ -0,0 +1,121 @@
+// Package audit membawa identitas pelaku (email & IP) lewat context dan
+// menghitung diff before/after untuk catatan audit. Penyimpanan ada di
+// services.AuditService.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Actor adalah pelaku sebuah request.
+type Actor struct {
+	Email string
+	IP    string
+}
+
+type actorKey struct{}
+
+// WithActor menyimpan actor di context.
+func WithActor(ctx context.Context, a Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, a)
+}
+
+// ActorFrom mengambil actor dari context (zero value jika tidak ada,
+// mis. job sistem).
+func ActorFrom(ctx context.Context) Actor {
+	a, _ := ctx.Value(actorKey{}).(Actor)
+	return a
+}
+
+// Change adalah nilai satu field sebelum dan sesudah.
+type Change struct {
+	Before interface{} `json:"before,omitempty" bson:"before,omitempty"`
+	After  interface{} `json:"after,omitempty" bson:"after,omitempty"`
+}
+
+// Diff membandingkan representasi JSON before dan after per field level
+// atas. nil berarti resource belum ada (create) atau sudah tidak ada
+// (delete). Field yang tidak ter-serialize ke JSON (mis. password) tidak
+// pernah masuk diff.
+func Diff(before, after interface{}) map[string]Change {
+	b, a := toMap(before), toMap(after)
+	changes := map[string]Change{}
+	for k, bv := range b {
+		if av, ok := a[k]; !ok || !reflect.DeepEqual(bv, av) {
+			changes[k] = Change{Before: bv, After: av}
+		}
+	}
+	for k, av := range a {
+		if _, ok := b[k]; !ok {
+			changes[k] = Change{After: av}
+		}
+	}
+	return changes
+}
+
+// Label mengembalikan nama resource yang mudah dikenali dari v (field
+// name, email, title atau slug), kosong jika tidak ada.
+func Label(v interface{}) string {
+	m := toMap(v)
+	for _, k := range []string{"name", "email", "title", "slug"} {
+		if s, ok := m[k].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func toMap(v interface{}) map[string]interface{} {
+	m := map[string]interface{}{}
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil() {
+		return m
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return m
+	}
+	json.Unmarshal(raw, &m)
+	return m
+}
+
+// Canonical mengubah nilai changes hasil decode BSON (bson.M, primitive.D,
+// primitive.A, angka integer) ke bentuk yang sama seperti hasil Diff,
+// sehingga representasi JSON-nya sama sebelum dan sesudah disimpan.
+func Canonical(v interface{}) interface{} {
+	switch v := v.(type) {
+	case primitive.D:
+		m := make(map[string]interface{}, len(v))
+		for _, e := range v {
+			m[e.Key] = Canonical(e.Value)
+		}
+		return m
+	case bson.M:
+		return Canonical(map[string]interface{}(v))
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = Canonical(e)
+		}
+		return m
+	case primitive.A:
+		return Canonical([]interface{}(v))
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, e := range v {
+			out[i] = Canonical(e)
+		}
+		return out
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case int:
+		return float64(v)
+	}
+	return v
+}
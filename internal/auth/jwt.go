@@ -0,0 +1,101 @@
+// Package auth menerbitkan dan memvalidasi JWT (access + refresh token)
+// yang menggantikan autentikasi lewat header X-User-Email.
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	TypeAccess  = "access"
+	TypeRefresh = "refresh"
+)
+
+var ErrInvalidToken = errors.New("token tidak valid")
+
+// Claims adalah isi JWT yang kita terbitkan.
+type Claims struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+	Type  string `json:"typ"`
+	// ID sesi login (collection sessions); kosong untuk token lama
+	Session string `json:"sid,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// TokenPair dikirim ke client setelah login/refresh.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// Manager menandatangani dan memverifikasi token dengan HMAC-SHA256.
+type Manager struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewManager membuat Manager dengan secret dan umur token dari config.
+func NewManager(secret string, accessTTL, refreshTTL time.Duration) *Manager {
+	return &Manager{
+		secret:     []byte(secret),
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}
+}
+
+// IssuePair menerbitkan access token dan refresh token untuk user dalam
+// sesi sessionID (boleh kosong).
+func (m *Manager) IssuePair(userID, email, role, sessionID string) (TokenPair, error) {
+	access, err := m.sign(userID, email, role, sessionID, TypeAccess, m.accessTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	refresh, err := m.sign(userID, email, role, sessionID, TypeRefresh, m.refreshTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(m.accessTTL.Seconds()),
+	}, nil
+}
+
+func (m *Manager) sign(userID, email, role, sessionID, typ string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		Email:   email,
+		Role:    role,
+		Type:    typ,
+		Session: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+}
+
+// Parse memverifikasi signature, masa berlaku, dan tipe token.
+func (m *Manager) Parse(tokenString, expectedType string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return m.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}))
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if claims.Type != expectedType || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
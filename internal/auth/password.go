@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MinPasswordLength adalah panjang minimal password baru.
+const MinPasswordLength = 8
+
+// HashPassword membuat hash bcrypt dari password.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// IsHashed mengecek apakah nilai tersimpan sudah berupa hash bcrypt.
+func IsHashed(stored string) bool {
+	return strings.HasPrefix(stored, "$2a$") || strings.HasPrefix(stored, "$2b$") || strings.HasPrefix(stored, "$2y$")
+}
+
+// CheckPassword membandingkan password dengan nilai tersimpan. Nilai lama
+// yang masih plaintext tetap diterima (dibandingkan constant-time) dan
+// needsRehash=true supaya pemanggil bisa langsung meng-hash ulang.
+func CheckPassword(stored, password string) (ok bool, needsRehash bool) {
+	if IsHashed(stored) {
+		return bcrypt.CompareHashAndPassword([]byte(stored), []byte(password)) == nil, false
+	}
+	if stored == "" {
+		return false, false
+	}
+	match := subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+	return match, match
+}
+
+// Hash bcrypt untuk FakeCheckPassword, dibuat sekali saat pertama dipakai
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("infocuy-dummy-password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// FakeCheckPassword menjalankan bcrypt terhadap hash dummy. Dipakai saat
+// akun tidak ditemukan supaya lama respons login sama dengan password
+// salah dan email terdaftar tidak bisa ditebak dari waktunya.
+func FakeCheckPassword(password string) {
+	bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+}
+
+// NewResetToken membuat token acak untuk reset password. Yang disimpan di
+// DB hanya hash-nya, token mentah dikirim ke user lewat email.
+func NewResetToken() (token, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token = hex.EncodeToString(buf)
+	return token, HashResetToken(token), nil
+}
+
+// HashResetToken menghitung SHA-256 dari token reset.
+func HashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
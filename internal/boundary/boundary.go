@@ -0,0 +1,152 @@
+// Package boundary membaca batas wilayah administratif Indonesia dari
+// GeoJSON FeatureCollection (Polygon/MultiPolygon). Kolom kode & nama
+// dikenali dari beberapa sumber umum: shapefile BPS/Kemendagri (KDPPUM,
+// WADMPR, ...), GADM (GID_1, NAME_1, ...) atau properti generik
+// code/name/parent_code.
+//
+// File dibaca secara streaming per feature karena batas kecamatan bisa
+// berukuran ratusan MB.
+package boundary
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+// ErrInvalidFile berarti file bukan FeatureCollection yang bisa dibaca.
+var ErrInvalidFile = errors.New("file batas wilayah tidak valid")
+
+// Kandidat nama properti per level, dicoba berurutan
+type columns struct {
+	code, name, parent []string
+}
+
+var levelColumns = map[string]columns{
+	models.RegionProvince: {
+		code: []string{"code", "kode", "KDPPUM", "GID_1", "kode_prov"},
+		name: []string{"name", "nama", "WADMPR", "NAME_1", "PROVINSI"},
+	},
+	models.RegionRegency: {
+		code:   []string{"code", "kode", "KDPKAB", "GID_2", "kode_kab"},
+		name:   []string{"name", "nama", "WADMKK", "NAME_2", "KABKOT"},
+		parent: []string{"parent_code", "KDPPUM", "GID_1", "kode_prov"},
+	},
+	models.RegionDistrict: {
+		code:   []string{"code", "kode", "KDCPUM", "GID_3", "kode_kec"},
+		name:   []string{"name", "nama", "WADMKC", "NAME_3", "KECAMATAN"},
+		parent: []string{"parent_code", "KDPKAB", "GID_2", "kode_kab"},
+	},
+}
+
+// ValidLevel bernilai true untuk provinsi, kabupaten dan kecamatan.
+func ValidLevel(level string) bool {
+	_, ok := levelColumns[level]
+	return ok
+}
+
+// Row adalah satu feature. Err terisi jika feature tidak bisa dipakai.
+type Row struct {
+	Row    int
+	Region models.Region
+	Err    error
+}
+
+type feature struct {
+	Geometry   *models.Geometry       `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Read memanggil fn untuk setiap feature secara berurutan. Error dari fn
+// menghentikan pembacaan.
+func Read(r io.Reader, level string, fn func(Row) error) error {
+	cols, ok := levelColumns[level]
+	if !ok {
+		return fmt.Errorf("%w: level tidak dikenal: %s", ErrInvalidFile, level)
+	}
+	dec := json.NewDecoder(r)
+	if err := seekFeatures(dec); err != nil {
+		return err
+	}
+	for n := 1; dec.More(); n++ {
+		row := Row{Row: n}
+		var f feature
+		if err := dec.Decode(&f); err != nil {
+			return fmt.Errorf("%w: feature %d: %v", ErrInvalidFile, n, err)
+		}
+		row.Region, row.Err = toRegion(f, level, cols)
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seekFeatures maju sampai awal array "features". Field lain di level atas
+// (type, crs, name) dilewati.
+func seekFeatures(dec *json.Decoder) error {
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return fmt.Errorf("%w: harus berupa FeatureCollection", ErrInvalidFile)
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		if key, _ := tok.(string); key == "features" {
+			if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+				return fmt.Errorf("%w: features harus array", ErrInvalidFile)
+			}
+			return nil
+		}
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+	}
+	return fmt.Errorf("%w: features tidak ditemukan", ErrInvalidFile)
+}
+
+func toRegion(f feature, level string, cols columns) (models.Region, error) {
+	region := models.Region{
+		Level:      level,
+		Code:       prop(f.Properties, cols.code),
+		Name:       prop(f.Properties, cols.name),
+		ParentCode: prop(f.Properties, cols.parent),
+		Geometry:   f.Geometry,
+	}
+	if region.Code == "" {
+		return region, fmt.Errorf("kode wilayah tidak ditemukan (%s)", strings.Join(cols.code, "/"))
+	}
+	if region.Name == "" {
+		return region, fmt.Errorf("nama wilayah tidak ditemukan (%s)", strings.Join(cols.name, "/"))
+	}
+	if f.Geometry == nil || (f.Geometry.Type != "Polygon" && f.Geometry.Type != "MultiPolygon") {
+		return region, fmt.Errorf("geometry harus Polygon atau MultiPolygon")
+	}
+	return region, nil
+}
+
+// prop mengambil properti pertama yang terisi (nama properti case-insensitive)
+func prop(props map[string]interface{}, keys []string) string {
+	for _, key := range keys {
+		for k, v := range props {
+			if !strings.EqualFold(k, key) || v == nil {
+				continue
+			}
+			// Kode numerik jangan sampai jadi notasi eksponen (1.10101e+06)
+			if f, ok := v.(float64); ok {
+				return strconv.FormatFloat(f, 'f', -1, 64)
+			}
+			if s := strings.TrimSpace(fmt.Sprint(v)); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
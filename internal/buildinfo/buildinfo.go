@@ -0,0 +1,57 @@
+// Package buildinfo menyimpan commit & waktu build yang diisi lewat
+// ldflags saat build:
+//
+//	go build -ldflags "-X InfoCuy-Backend/internal/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X InfoCuy-Backend/internal/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Jika ldflags tidak dipakai, info VCS yang disisipkan Go toolchain dipakai
+// sebagai cadangan.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Diisi lewat -ldflags "-X ..."
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get mengembalikan info build; field yang kosong bernilai "unknown".
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = s.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = s.Value
+				}
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+	return info
+}
@@ -0,0 +1,212 @@
+// Package chaos menyuntikkan latensi dan error buatan untuk menguji
+// ketahanan di lingkungan development: timeout operasi MongoDB, retry dan
+// circuit breaker httpclient, serta retry di client frontend. Hanya untuk
+// local/staging; Setup menolak aktif di Render atau Vercel.
+//
+// Konfigurasi lewat environment (semua nonaktif secara default):
+//
+//	CHAOS_ENABLED               true untuk mengaktifkan
+//	CHAOS_MONGO_LATENCY         tambahan latensi per penulisan ke koneksi MongoDB, mis. 200ms atau 100ms-2s
+//	CHAOS_MONGO_ERROR_RATE      peluang koneksi MongoDB diputus saat menulis, 0-1
+//	CHAOS_PROVIDER_LATENCY      tambahan latensi per panggilan layanan eksternal
+//	CHAOS_PROVIDER_ERROR_RATE   peluang panggilan layanan eksternal gagal, 0-1
+//	CHAOS_PROVIDERS             nama provider yang terkena, dipisah koma (kosong = semua)
+//	CHAOS_HTTP_LATENCY          tambahan latensi per request /v1
+//	CHAOS_HTTP_ERROR_RATE       peluang request /v1 dijawab 503, 0-1
+//
+// Gangguan MongoDB disuntikkan di level koneksi jaringan (seperti jaringan
+// yang lambat atau putus), sehingga juga mengenai handshake dan heartbeat
+// driver. Latensi berbentuk rentang dipilih acak merata per kejadian.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ErrInjected adalah error buatan chaos; errors.Is bisa dipakai untuk
+// membedakannya dari kegagalan sungguhan di log.
+var ErrInjected = errors.New("chaos: gangguan buatan")
+
+// Fault adalah gangguan untuk satu target.
+type Fault struct {
+	// Latensi dipilih acak di [MinLatency, MaxLatency]
+	MinLatency time.Duration
+	MaxLatency time.Duration
+	// Peluang gagal, 0-1
+	ErrorRate float64
+}
+
+// Active bernilai true jika Fault menambah latensi atau error.
+func (f Fault) Active() bool {
+	return f.MaxLatency > 0 || f.ErrorRate > 0
+}
+
+func (f Fault) String() string {
+	if !f.Active() {
+		return "off"
+	}
+	s := fmt.Sprintf("error_rate=%g", f.ErrorRate)
+	if f.MaxLatency > 0 {
+		s += " latency=" + f.MinLatency.String()
+		if f.MaxLatency != f.MinLatency {
+			s += "-" + f.MaxLatency.String()
+		}
+	}
+	return s
+}
+
+func (f Fault) latency() time.Duration {
+	if f.MaxLatency <= f.MinLatency {
+		return f.MinLatency
+	}
+	return f.MinLatency + rand.N(f.MaxLatency-f.MinLatency+1)
+}
+
+func (f Fault) fail() bool {
+	return f.ErrorRate > 0 && rand.Float64() < f.ErrorRate
+}
+
+// inject menunggu latensi (berhenti lebih awal jika ctx selesai) lalu
+// mengembalikan ErrInjected sesuai ErrorRate.
+func (f Fault) inject(ctx context.Context) error {
+	if d := f.latency(); d > 0 {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	if f.fail() {
+		return ErrInjected
+	}
+	return nil
+}
+
+// Config adalah gangguan per target.
+type Config struct {
+	Mongo    Fault
+	Provider Fault
+	HTTP     Fault
+	// Nama provider httpclient yang terkena; kosong = semua
+	Providers []string
+}
+
+var active atomic.Pointer[Config]
+
+// Setup membaca konfigurasi dari environment. Tidak melakukan apa pun jika
+// CHAOS_ENABLED bukan true.
+func Setup() {
+	if v, _ := strconv.ParseBool(os.Getenv("CHAOS_ENABLED")); !v {
+		return
+	}
+	if os.Getenv("RENDER") != "" || os.Getenv("VERCEL") != "" {
+		log.Println("Warning: CHAOS_ENABLED diabaikan, chaos hanya untuk development")
+		return
+	}
+	var cfg Config
+	var errs []string
+	parse := func(target string) Fault {
+		f, err := parseFault(os.Getenv("CHAOS_"+target+"_LATENCY"), os.Getenv("CHAOS_"+target+"_ERROR_RATE"))
+		if err != nil {
+			errs = append(errs, "CHAOS_"+target+"_"+err.Error())
+		}
+		return f
+	}
+	cfg.Mongo = parse("MONGO")
+	cfg.Provider = parse("PROVIDER")
+	cfg.HTTP = parse("HTTP")
+	for _, p := range strings.Split(os.Getenv("CHAOS_PROVIDERS"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			cfg.Providers = append(cfg.Providers, p)
+		}
+	}
+	if len(errs) > 0 {
+		log.Println("Warning: chaos dinonaktifkan, konfigurasi tidak valid:", strings.Join(errs, "; "))
+		return
+	}
+	Set(&cfg)
+	log.Printf("Warning: CHAOS AKTIF, mongo: %s, provider: %s, http: %s", cfg.Mongo, cfg.Provider, cfg.HTTP)
+}
+
+// Set mengganti konfigurasi yang berlaku; nil menonaktifkan chaos.
+func Set(cfg *Config) {
+	active.Store(cfg)
+}
+
+// Current mengembalikan konfigurasi yang berlaku, nil jika nonaktif.
+func Current() *Config {
+	return active.Load()
+}
+
+// parseFault membaca latensi ("200ms" atau rentang "100ms-2s") dan error
+// rate ("0.05"). Error diawali nama field yang salah.
+func parseFault(latency, errorRate string) (Fault, error) {
+	var f Fault
+	if latency != "" {
+		lo, hi, isRange := strings.Cut(latency, "-")
+		lower, err := time.ParseDuration(strings.TrimSpace(lo))
+		if err != nil || lower < 0 {
+			return f, fmt.Errorf("LATENCY: %q bukan durasi", latency)
+		}
+		upper := lower
+		if isRange {
+			if upper, err = time.ParseDuration(strings.TrimSpace(hi)); err != nil || upper < lower {
+				return f, fmt.Errorf("LATENCY: rentang %q tidak valid", latency)
+			}
+		}
+		f.MinLatency, f.MaxLatency = lower, upper
+	}
+	if errorRate != "" {
+		rate, err := strconv.ParseFloat(errorRate, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return f, fmt.Errorf("ERROR_RATE: harus 0-1")
+		}
+		f.ErrorRate = rate
+	}
+	return f, nil
+}
+
+// HTTP menyuntikkan gangguan untuk satu request masuk. Error berarti
+// request harus dijawab 503.
+func HTTP(ctx context.Context) error {
+	cfg := active.Load()
+	if cfg == nil || !cfg.HTTP.Active() {
+		return nil
+	}
+	return cfg.HTTP.inject(ctx)
+}
+
+// Provider menyuntikkan gangguan untuk satu panggilan ke provider name.
+func Provider(ctx context.Context, name string) error {
+	cfg := active.Load()
+	if cfg == nil || !cfg.Provider.Active() {
+		return nil
+	}
+	if len(cfg.Providers) > 0 && !contains(cfg.Providers, name) {
+		return nil
+	}
+	if err := cfg.Provider.inject(ctx); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package chaos
+
+import (
+	"context"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// Dialer membuka koneksi MongoDB yang terkena gangguan CHAOS_MONGO_*:
+// setiap Write ditunda sebesar latensi dan, sesuai error rate, koneksi
+// diputus. Dipasang lewat options.Client().SetDialer.
+type Dialer struct {
+	net.Dialer
+}
+
+// MongoDialer mengembalikan nil jika tidak ada gangguan MongoDB yang
+// dikonfigurasi.
+func MongoDialer() *Dialer {
+	if cfg := active.Load(); cfg == nil || !cfg.Mongo.Active() {
+		return nil
+	}
+	return &Dialer{Dialer: net.Dialer{Timeout: 30 * time.Second, KeepAlive: 5 * time.Minute}}
+}
+
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, err := d.Dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	return &faultyConn{Conn: conn}, nil
+}
+
+type faultyConn struct {
+	net.Conn
+
+	mu       sync.Mutex
+	deadline time.Time
+}
+
+func (c *faultyConn) SetDeadline(t time.Time) error {
+	c.setWriteDeadline(t)
+	return c.Conn.SetDeadline(t)
+}
+
+func (c *faultyConn) SetWriteDeadline(t time.Time) error {
+	c.setWriteDeadline(t)
+	return c.Conn.SetWriteDeadline(t)
+}
+
+func (c *faultyConn) setWriteDeadline(t time.Time) {
+	c.mu.Lock()
+	c.deadline = t
+	c.mu.Unlock()
+}
+
+// Write menghormati write deadline dari driver: jika latensi melewatinya
+// Write gagal dengan os.ErrDeadlineExceeded seperti jaringan yang lambat.
+func (c *faultyConn) Write(b []byte) (int, error) {
+	cfg := active.Load()
+	if cfg == nil {
+		return c.Conn.Write(b)
+	}
+	if d := cfg.Mongo.latency(); d > 0 {
+		c.mu.Lock()
+		deadline := c.deadline
+		c.mu.Unlock()
+		if !deadline.IsZero() && time.Until(deadline) < d {
+			time.Sleep(max(0, time.Until(deadline)))
+			return 0, os.ErrDeadlineExceeded
+		}
+		time.Sleep(d)
+	}
+	if cfg.Mongo.fail() {
+		c.Conn.Close()
+		return 0, ErrInjected
+	}
+	return c.Conn.Write(b)
+}
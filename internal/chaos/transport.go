@@ -0,0 +1,29 @@
+package chaos
+
+import "net/http"
+
+// Transport membungkus base (nil = http.DefaultTransport) sehingga
+// panggilan ke provider name terkena gangguan CHAOS_PROVIDER_*. Latensi
+// dihitung dalam timeout http.Client, jadi timeout per percobaan ikut
+// teruji.
+func Transport(name string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{name: name, base: base}
+}
+
+type transport struct {
+	name string
+	base http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := Provider(req.Context(), t.name); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
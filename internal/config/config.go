@@ -0,0 +1,550 @@
+// Package config membaca dan memvalidasi seluruh pengaturan inti aplikasi
+// sekali saat startup, dari environment dan file .env (jika ada). Nilai di
+// environment menang atas isi .env.
+//
+// Wajib diisi:
+//
+//	MONGO_URI                      connection string MongoDB
+//	JWT_SECRET                     secret HMAC untuk access & refresh token
+//
+// Opsional (default di dalam kurung):
+//
+//	PORT                           port HTTP (8080)
+//	SHUTDOWN_TIMEOUT               tunggu request berjalan saat berhenti (25s)
+//	PUBLIC_API_URL                 URL publik API, dipakai pixel email campaign
+//	MONGO_DB                       nama database (geo_db)
+//	MONGO_MAX_POOL_SIZE            koneksi maksimum per instance (20)
+//	MONGO_MIN_POOL_SIZE            koneksi yang dijaga tetap terbuka (0)
+//	MONGO_MAX_CONN_IDLE            koneksi idle ditutup setelah durasi ini (5m)
+//	MONGO_OP_TIMEOUT               batas waktu tiap operasi (10s)
+//	MONGO_SLOW_OP                  operasi selama ini dicatat sebagai warning (500ms)
+//	JWT_ACCESS_TTL                 umur access token (15m)
+//	JWT_REFRESH_TTL                umur refresh token (168h)
+//	CORS_ORIGINS                   origin yang diizinkan, dipisah koma (kosong = semua)
+//	CORS_MAX_AGE                   cache preflight di browser (12h)
+//	CORS_ALLOW_CREDENTIALS         izinkan cookie/credential, butuh CORS_ORIGINS (false)
+//	ADMIN_CORS_ORIGINS             khusus /v1/admin (sama dengan CORS_ORIGINS)
+//	ADMIN_CORS_MAX_AGE             (10m)
+//	ADMIN_CORS_ALLOW_CREDENTIALS   butuh ADMIN_CORS_ORIGINS (false)
+//	RATE_LIMIT_PER_MIN             request per menit per IP, 0 = nonaktif (120)
+//	RATE_LIMIT_BURST               request beruntun (sama dengan PER_MIN)
+//	AUTH_RATE_LIMIT_PER_MIN        khusus login, register & reset password (10)
+//	AUTH_RATE_LIMIT_BURST          (5)
+//	TRUSTED_PROXIES                IP/CIDR proxy yang header X-Forwarded-For-nya dipercaya, dipisah koma (kosong = tidak ada)
+//	TRUSTED_PLATFORM               vercel | cloudflare: IP & negara/kota login dibaca dari header platform (kosong = tidak ada)
+//	LOGIN_MAX_FAILURES             login gagal sebelum akun dikunci, 0 = nonaktif (5)
+//	LOGIN_LOCKOUT_DURATION         lama akun dikunci (15m)
+//	LOG_LEVEL                      debug | info | warn | error (info)
+//	LOG_FORMAT                     json | text (json)
+//	ALLOW_REGISTRATION             false untuk menutup registrasi (true)
+//	LEGACY_EMAIL_AUTH              terima header X-User-Email tanpa bearer token selama masa transisi JWT (false)
+//	PASSWORD_RESET_URL             halaman frontend untuk link reset password
+//	LOGIN_ALERT_URL                halaman frontend "bukan saya" di email peringatan login dari perangkat baru
+//	QUOTA_UPGRADE_URL              link upgrade saat kuota lokasi habis
+//	METRICS_TOKEN                  bearer token wajib untuk GET /metrics (kosong = terbuka, batasi lewat firewall)
+//	LOCATION_FRESHNESS_HALF_LIFE   half-life skor kesegaran lokasi (4320h)
+//	LOCATION_TRASH_RETENTION       umur lokasi di trash sebelum di-purge (720h)
+//	LOCATION_ARCHIVE_AFTER         lokasi tidak disentuh selama ini dipindah ke arsip (17520h)
+//	CAMPAIGN_SEND_INTERVAL         jeda antar email campaign (0)
+//	AUDIT_RETENTION                umur audit log sebelum boleh diarsip ke object storage S3, 0 = arsip nonaktif (0)
+//	STREAM_MAX_CLIENTS             koneksi GET /locations/stream per instance (500)
+//	JOB_WORKERS                    pekerjaan latar belakang bersamaan per instance (8)
+//	JOB_EMAIL_CONCURRENCY          batas email ke user, mis. reset password (sama dengan JOB_WORKERS)
+//	JOB_WEBHOOK_CONCURRENCY        batas pengiriman webhook (4)
+//	JOB_BACKFILL_CONCURRENCY       batas import, backfill & email massal, harus < JOB_WORKERS (2)
+//
+// Integrasi pihak ketiga (SMTP, object storage, cuaca, OSRM, elevation,
+// geocoding, SIEM, SHADOW_*, CHAOS_*, OUTBOUND_*) tetap dibaca oleh package
+// masing-masing karena semuanya opsional dan nonaktif jika tidak
+// dikonfigurasi.
+//
+// LOG_LEVEL, CORS_*, ADMIN_CORS_*, RATE_LIMIT_*, AUTH_RATE_LIMIT_*,
+// ALLOW_REGISTRATION dan LEGACY_EMAIL_AUTH (lihat Runtime) bisa dimuat ulang tanpa restart
+// dengan SIGHUP atau POST /admin/config/reload; yang lain butuh restart.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// Config adalah pengaturan aplikasi yang sudah divalidasi.
+type Config struct {
+	Port            string
+	ShutdownTimeout time.Duration
+	PublicAPIURL    string
+
+	Mongo Mongo
+	JWT   JWT
+	// Kebijakan CORS route publik dan route /v1/admin
+	CORS      CORS
+	AdminCORS CORS
+
+	RateLimit     RateLimit
+	AuthRateLimit RateLimit
+	// Tanpa proxy atau platform tepercaya IP client diambil dari koneksi,
+	// bukan dari header yang bisa diisi client sendiri
+	TrustedProxies  []string
+	TrustedPlatform string
+	// LoginMaxFailures 0 berarti penguncian akun nonaktif
+	LoginMaxFailures int
+	LoginLockout     time.Duration
+
+	LogLevel  string
+	LogFormat string
+
+	AllowRegistration    bool
+	LegacyEmailAuth      bool
+	PasswordResetURL     string
+	LoginAlertURL        string
+	QuotaUpgradeURL      string
+	MetricsToken         string
+	FreshnessHalfLife    time.Duration
+	TrashRetention       time.Duration
+	ArchiveAfter         time.Duration
+	CampaignSendInterval time.Duration
+	AuditRetention       time.Duration
+	StreamMaxClients     int
+	Jobs                 Jobs
+}
+
+type Mongo struct {
+	URI         string
+	Database    string
+	MaxPoolSize uint64
+	MinPoolSize uint64
+	MaxConnIdle time.Duration
+	OpTimeout   time.Duration
+	SlowOp      time.Duration
+}
+
+type JWT struct {
+	Secret     string
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+// Panjang minimum JWT_SECRET (HS256 memakai kunci 256 bit)
+const minSecretLen = 32
+
+// Secret contoh yang sering tertinggal dari template .env
+var weakSecrets = []string{"secret", "changeme", "change-me", "jwt_secret", "jwtsecret", "password", "your-secret", "example"}
+
+// Weakness menjelaskan kenapa Secret mudah ditebak: terlalu pendek, terlalu
+// berulang atau mengandung kata placeholder. Kosong jika Secret cukup kuat.
+func (j JWT) Weakness() string {
+	distinct := map[rune]bool{}
+	for _, r := range j.Secret {
+		distinct[r] = true
+	}
+	switch {
+	case len(j.Secret) < minSecretLen:
+		return fmt.Sprintf("JWT_SECRET hanya %d karakter, minimal %d", len(j.Secret), minSecretLen)
+	case len(distinct) < 10:
+		return "JWT_SECRET terlalu berulang, isi dengan nilai acak (mis. openssl rand -base64 48)"
+	}
+	lower := strings.ToLower(j.Secret)
+	for _, w := range weakSecrets {
+		if strings.Contains(lower, w) {
+			return fmt.Sprintf("JWT_SECRET mengandung kata %q, isi dengan nilai acak", w)
+		}
+	}
+	return ""
+}
+
+// Jobs adalah kapasitas antrean pekerjaan latar belakang (package jobs).
+type Jobs struct {
+	Workers  int
+	Email    int
+	Webhook  int
+	Backfill int
+}
+
+// CORS adalah kebijakan CORS untuk satu kelompok route.
+type CORS struct {
+	// Kosong berarti semua origin diizinkan
+	Origins []string `json:"origins"`
+	// Lama browser boleh menyimpan hasil preflight
+	MaxAge           time.Duration `json:"-"`
+	AllowCredentials bool          `json:"allow_credentials"`
+}
+
+// MarshalJSON menulis max_age sebagai durasi yang bisa dibaca, mis. "10m0s".
+func (c CORS) MarshalJSON() ([]byte, error) {
+	type plain CORS
+	return json.Marshal(struct {
+		plain
+		MaxAge string `json:"max_age"`
+	}{plain(c), c.MaxAge.String()})
+}
+
+// Equal membandingkan dua kebijakan CORS.
+func (c CORS) Equal(other CORS) bool {
+	return strings.Join(c.Origins, ",") == strings.Join(other.Origins, ",") &&
+		c.MaxAge == other.MaxAge && c.AllowCredentials == other.AllowCredentials
+}
+
+// Nilai TRUSTED_PLATFORM
+const (
+	PlatformVercel     = "vercel"
+	PlatformCloudflare = "cloudflare"
+)
+
+// RateLimit adalah batas token bucket per IP; PerMin 0 berarti nonaktif.
+type RateLimit struct {
+	PerMin int `json:"per_min"`
+	Burst  int `json:"burst"`
+}
+
+// Error berisi semua masalah konfigurasi sekaligus supaya bisa diperbaiki
+// dalam satu kali deploy.
+type Error struct {
+	Missing []string
+	Invalid []string
+}
+
+func (e *Error) Error() string {
+	var parts []string
+	if len(e.Missing) > 0 {
+		parts = append(parts, "wajib diisi: "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Invalid) > 0 {
+		parts = append(parts, "tidak valid: "+strings.Join(e.Invalid, "; "))
+	}
+	return "konfigurasi tidak lengkap: " + strings.Join(parts, "; ")
+}
+
+// Environment proses sebelum .env dimuat, supaya Reload tetap memberi
+// environment prioritas di atas .env
+var (
+	baseEnvOnce sync.Once
+	baseEnv     map[string]string
+)
+
+func snapshotEnv() {
+	baseEnvOnce.Do(func() {
+		baseEnv = make(map[string]string)
+		for _, kv := range os.Environ() {
+			if k, v, ok := strings.Cut(kv, "="); ok {
+				baseEnv[k] = v
+			}
+		}
+	})
+}
+
+// Load membaca .env (jika ada) lalu environment. Hasilnya *Error jika ada
+// nilai wajib yang kosong atau nilai yang tidak bisa dibaca.
+func Load() (*Config, error) {
+	snapshotEnv()
+	// .env tidak wajib; di production nilai datang dari environment
+	godotenv.Load()
+	return FromLookup(os.LookupEnv)
+}
+
+// Reload membaca ulang konfigurasi: environment saat proses mulai, lalu isi
+// .env terbaru. Environment proses tidak bisa diubah dari luar, jadi
+// perubahan untuk reload dilakukan lewat .env.
+func Reload() (*Config, error) {
+	snapshotEnv()
+	file, err := godotenv.Read()
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("membaca .env: %w", err)
+	}
+	return FromLookup(func(key string) (string, bool) {
+		if v, ok := baseEnv[key]; ok {
+			return v, true
+		}
+		v, ok := file[key]
+		return v, ok
+	})
+}
+
+// FromLookup membaca konfigurasi dari fungsi lookup (mis. os.LookupEnv).
+func FromLookup(lookup func(string) (string, bool)) (*Config, error) {
+	l := &loader{lookup: lookup}
+	cfg := &Config{
+		Port:            l.str("PORT", "8080"),
+		ShutdownTimeout: l.duration("SHUTDOWN_TIMEOUT", 25*time.Second),
+		PublicAPIURL:    l.url("PUBLIC_API_URL"),
+		Mongo: Mongo{
+			URI:         l.required("MONGO_URI"),
+			Database:    l.str("MONGO_DB", "geo_db"),
+			MaxPoolSize: uint64(l.integer("MONGO_MAX_POOL_SIZE", 20, 1)),
+			MinPoolSize: uint64(l.integer("MONGO_MIN_POOL_SIZE", 0, 0)),
+			MaxConnIdle: l.duration("MONGO_MAX_CONN_IDLE", 5*time.Minute),
+			OpTimeout:   l.duration("MONGO_OP_TIMEOUT", 10*time.Second),
+			SlowOp:      l.duration("MONGO_SLOW_OP", 500*time.Millisecond),
+		},
+		JWT: JWT{
+			Secret:     l.required("JWT_SECRET"),
+			AccessTTL:  l.duration("JWT_ACCESS_TTL", 15*time.Minute),
+			RefreshTTL: l.duration("JWT_REFRESH_TTL", 7*24*time.Hour),
+		},
+		CORS: l.cors("CORS_", nil, 12*time.Hour),
+		RateLimit: RateLimit{
+			PerMin: l.integer("RATE_LIMIT_PER_MIN", 120, 0),
+			Burst:  l.integer("RATE_LIMIT_BURST", 0, 0),
+		},
+		AuthRateLimit: RateLimit{
+			PerMin: l.integer("AUTH_RATE_LIMIT_PER_MIN", 10, 0),
+			Burst:  l.integer("AUTH_RATE_LIMIT_BURST", 5, 0),
+		},
+		TrustedProxies:       l.proxies("TRUSTED_PROXIES"),
+		TrustedPlatform:      l.oneOf("TRUSTED_PLATFORM", "", PlatformVercel, PlatformCloudflare),
+		LoginMaxFailures:     l.integer("LOGIN_MAX_FAILURES", 5, 0),
+		LoginLockout:         l.duration("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+		LogLevel:             l.oneOf("LOG_LEVEL", "info", "debug", "info", "warn", "error"),
+		LogFormat:            l.oneOf("LOG_FORMAT", "json", "json", "text"),
+		AllowRegistration:    l.boolean("ALLOW_REGISTRATION", true),
+		LegacyEmailAuth:      l.boolean("LEGACY_EMAIL_AUTH", false),
+		PasswordResetURL:     l.url("PASSWORD_RESET_URL"),
+		LoginAlertURL:        l.url("LOGIN_ALERT_URL"),
+		QuotaUpgradeURL:      l.url("QUOTA_UPGRADE_URL"),
+		MetricsToken:         l.str("METRICS_TOKEN", ""),
+		FreshnessHalfLife:    l.duration("LOCATION_FRESHNESS_HALF_LIFE", 180*24*time.Hour),
+		TrashRetention:       l.duration("LOCATION_TRASH_RETENTION", 30*24*time.Hour),
+		ArchiveAfter:         l.duration("LOCATION_ARCHIVE_AFTER", 2*365*24*time.Hour),
+		CampaignSendInterval: l.durationOrZero("CAMPAIGN_SEND_INTERVAL"),
+		AuditRetention:       l.durationOrZero("AUDIT_RETENTION"),
+		StreamMaxClients:     l.integer("STREAM_MAX_CLIENTS", 500, 1),
+		Jobs: Jobs{
+			Workers:  l.integer("JOB_WORKERS", 8, 1),
+			Email:    l.integer("JOB_EMAIL_CONCURRENCY", 0, 0),
+			Webhook:  l.integer("JOB_WEBHOOK_CONCURRENCY", 4, 1),
+			Backfill: l.integer("JOB_BACKFILL_CONCURRENCY", 2, 1),
+		},
+	}
+	// Admin mengikuti origin publik kecuali diisi sendiri
+	cfg.AdminCORS = l.cors("ADMIN_CORS_", cfg.CORS.Origins, 10*time.Minute)
+	if cfg.Mongo.MinPoolSize > cfg.Mongo.MaxPoolSize {
+		l.invalid("MONGO_MIN_POOL_SIZE", "tidak boleh lebih dari MONGO_MAX_POOL_SIZE")
+	}
+	// Backfill yang boleh memakai semua worker bisa membuat email user menunggu
+	if cfg.Jobs.Backfill >= cfg.Jobs.Workers {
+		l.invalid("JOB_BACKFILL_CONCURRENCY", "harus lebih kecil dari JOB_WORKERS")
+	}
+	if len(l.err.Missing) > 0 || len(l.err.Invalid) > 0 {
+		return nil, &l.err
+	}
+	return cfg, nil
+}
+
+// Runtime adalah bagian config yang bisa diterapkan ulang saat aplikasi
+// berjalan tanpa memutus koneksi.
+type Runtime struct {
+	LogLevel          string    `json:"log_level"`
+	CORS              CORS      `json:"cors"`
+	AdminCORS         CORS      `json:"admin_cors"`
+	RateLimit         RateLimit `json:"rate_limit"`
+	AuthRateLimit     RateLimit `json:"auth_rate_limit"`
+	AllowRegistration bool      `json:"allow_registration"`
+	LegacyEmailAuth   bool      `json:"legacy_email_auth"`
+}
+
+// Runtime mengambil nilai yang bisa dimuat ulang dari c.
+func (c *Config) Runtime() Runtime {
+	return Runtime{
+		LogLevel:          c.LogLevel,
+		CORS:              c.CORS,
+		AdminCORS:         c.AdminCORS,
+		RateLimit:         c.RateLimit,
+		AuthRateLimit:     c.AuthRateLimit,
+		AllowRegistration: c.AllowRegistration,
+		LegacyEmailAuth:   c.LegacyEmailAuth,
+	}
+}
+
+// Changed mengembalikan nama variabel yang nilainya berbeda di next.
+func (r Runtime) Changed(next Runtime) []string {
+	var keys []string
+	if r.LogLevel != next.LogLevel {
+		keys = append(keys, "LOG_LEVEL")
+	}
+	if !r.CORS.Equal(next.CORS) {
+		keys = append(keys, "CORS_*")
+	}
+	if !r.AdminCORS.Equal(next.AdminCORS) {
+		keys = append(keys, "ADMIN_CORS_*")
+	}
+	if r.RateLimit != next.RateLimit {
+		keys = append(keys, "RATE_LIMIT_*")
+	}
+	if r.AuthRateLimit != next.AuthRateLimit {
+		keys = append(keys, "AUTH_RATE_LIMIT_*")
+	}
+	if r.AllowRegistration != next.AllowRegistration {
+		keys = append(keys, "ALLOW_REGISTRATION")
+	}
+	if r.LegacyEmailAuth != next.LegacyEmailAuth {
+		keys = append(keys, "LEGACY_EMAIL_AUTH")
+	}
+	return keys
+}
+
+// loader mencatat semua kesalahan sambil mengisi nilai default.
+type loader struct {
+	lookup func(string) (string, bool)
+	err    Error
+}
+
+func (l *loader) get(key string) string {
+	v, _ := l.lookup(key)
+	return strings.TrimSpace(v)
+}
+
+func (l *loader) invalid(key, reason string) {
+	l.err.Invalid = append(l.err.Invalid, key+" "+reason)
+}
+
+func (l *loader) required(key string) string {
+	v := l.get(key)
+	if v == "" {
+		l.err.Missing = append(l.err.Missing, key)
+	}
+	return v
+}
+
+func (l *loader) str(key, def string) string {
+	if v := l.get(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func (l *loader) integer(key string, def, min int) int {
+	v := l.get(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < min {
+		l.invalid(key, fmt.Sprintf("harus bilangan bulat >= %d", min))
+		return def
+	}
+	return n
+}
+
+func (l *loader) duration(key string, def time.Duration) time.Duration {
+	v := l.get(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		l.invalid(key, "harus durasi positif, mis. 30s atau 720h")
+		return def
+	}
+	return d
+}
+
+// durationOrZero sama seperti duration tetapi menerima 0 (nonaktif).
+func (l *loader) durationOrZero(key string) time.Duration {
+	v := l.get(key)
+	if v == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		l.invalid(key, "harus durasi, mis. 200ms")
+		return 0
+	}
+	return d
+}
+
+func (l *loader) boolean(key string, def bool) bool {
+	v := l.get(key)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		l.invalid(key, "harus true atau false")
+		return def
+	}
+	return b
+}
+
+func (l *loader) oneOf(key, def string, allowed ...string) string {
+	v := strings.ToLower(l.get(key))
+	if v == "" {
+		return def
+	}
+	for _, a := range allowed {
+		if v == a {
+			return v
+		}
+	}
+	l.invalid(key, "hanya boleh: "+strings.Join(allowed, ", "))
+	return def
+}
+
+func (l *loader) url(key string) string {
+	v := l.get(key)
+	if v == "" {
+		return ""
+	}
+	if u, err := url.Parse(v); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		l.invalid(key, "harus URL http(s) lengkap")
+		return ""
+	}
+	return v
+}
+
+// cors membaca <prefix>ORIGINS, <prefix>MAX_AGE dan
+// <prefix>ALLOW_CREDENTIALS. ORIGINS yang tidak diisi memakai origins.
+// Credential hanya boleh untuk origin eksplisit: browser menolak
+// Access-Control-Allow-Origin: * bersama credential.
+func (l *loader) cors(prefix string, origins []string, maxAge time.Duration) CORS {
+	c := CORS{
+		Origins:          origins,
+		MaxAge:           l.duration(prefix+"MAX_AGE", maxAge),
+		AllowCredentials: l.boolean(prefix+"ALLOW_CREDENTIALS", false),
+	}
+	if l.get(prefix+"ORIGINS") != "" {
+		c.Origins = l.list(prefix + "ORIGINS")
+	}
+	if c.AllowCredentials && len(c.Origins) == 0 {
+		l.invalid(prefix+"ALLOW_CREDENTIALS", "butuh "+prefix+"ORIGINS yang eksplisit (bukan *)")
+	}
+	return c
+}
+
+// list memecah nilai dipisah koma; "*" berarti tanpa batasan (nil).
+func (l *loader) list(key string) []string {
+	var out []string
+	for _, v := range strings.Split(l.get(key), ",") {
+		if v = strings.TrimSpace(v); v == "*" {
+			return nil
+		} else if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// proxies membaca daftar IP atau CIDR dipisah koma. Berbeda dengan list,
+// "*" tidak diterima: mempercayai semua proxy sama dengan membiarkan client
+// memilih IP-nya sendiri.
+func (l *loader) proxies(key string) []string {
+	var out []string
+	for _, v := range strings.Split(l.get(key), ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(v); err != nil && net.ParseIP(v) == nil {
+			l.invalid(key, "harus daftar IP atau CIDR, mis. 10.0.0.0/8")
+			return nil
+		}
+		out = append(out, v)
+	}
+	return out
+}
@@ -0,0 +1,121 @@
+// Package database membuka koneksi MongoDB.
+//
+// Operasi yang gagal atau lambat dicatat lewat slog dengan context pemanggil
+// sehingga request_id ikut tercatat; operasi lain hanya pada LOG_LEVEL=debug.
+//
+// Read memakai primaryPreferred: selama tidak ada primary (failover atau
+// stepdown) read dilayani secondary dan State melaporkan mode baca saja.
+package database
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"InfoCuy-Backend/internal/chaos"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// DatabaseName adalah nama database aplikasi jika Options.Database kosong.
+const DatabaseName = "geo_db"
+
+const (
+	defaultMaxPoolSize = 20
+	defaultMaxConnIdle = 5 * time.Minute
+	defaultOpTimeout   = 10 * time.Second
+	defaultSlowOp      = 500 * time.Millisecond
+)
+
+// Options adalah pengaturan koneksi (lihat package config). Field nol
+// memakai default.
+type Options struct {
+	URI         string
+	Database    string
+	MaxPoolSize uint64
+	MinPoolSize uint64
+	MaxConnIdle time.Duration
+	// Batas waktu tiap operasi, hanya berlaku jika context dari pemanggil
+	// tidak punya deadline yang lebih cepat
+	OpTimeout time.Duration
+	// Operasi selama ini atau lebih dicatat sebagai warning
+	SlowOp time.Duration
+	// Diperbarui setiap kali primary hilang atau kembali; nil = tidak dilacak
+	State *State
+}
+
+func clientOptions(o Options) *options.ClientOptions {
+	if o.MaxPoolSize == 0 {
+		o.MaxPoolSize = defaultMaxPoolSize
+	}
+	if o.MinPoolSize > o.MaxPoolSize {
+		o.MinPoolSize = o.MaxPoolSize
+	}
+	if o.MaxConnIdle <= 0 {
+		o.MaxConnIdle = defaultMaxConnIdle
+	}
+	if o.OpTimeout <= 0 {
+		o.OpTimeout = defaultOpTimeout
+	}
+	if o.SlowOp <= 0 {
+		o.SlowOp = defaultSlowOp
+	}
+	opts := options.Client().
+		ApplyURI(o.URI).
+		SetMonitor(commandMonitor(o.SlowOp)).
+		SetMaxPoolSize(o.MaxPoolSize).
+		SetMinPoolSize(o.MinPoolSize).
+		SetMaxConnIdleTime(o.MaxConnIdle).
+		SetConnectTimeout(10 * time.Second).
+		SetServerSelectionTimeout(10 * time.Second).
+		SetTimeout(o.OpTimeout).
+		SetReadPreference(readpref.PrimaryPreferred())
+	if o.State != nil {
+		opts.SetServerMonitor(o.State.serverMonitor())
+	}
+	// Hanya jika CHAOS_MONGO_* dikonfigurasi (development)
+	if d := chaos.MongoDialer(); d != nil {
+		opts.SetDialer(d)
+	}
+	return opts
+}
+
+func commandMonitor(slowOp time.Duration) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			level := slog.LevelDebug
+			if e.Duration >= slowOp {
+				level = slog.LevelWarn
+			}
+			if !slog.Default().Enabled(ctx, level) {
+				return
+			}
+			slog.Log(ctx, level, "mongo", "command", e.CommandName, "database", e.DatabaseName,
+				"duration_ms", float64(e.Duration.Microseconds())/1000)
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			slog.WarnContext(ctx, "mongo gagal", "command", e.CommandName, "database", e.DatabaseName,
+				"duration_ms", float64(e.Duration.Microseconds())/1000, "error", e.Failure)
+		},
+	}
+}
+
+// Connect membuka koneksi lalu ping untuk memastikan cluster bisa dijangkau.
+// Tutup dengan db.Client().Disconnect saat aplikasi berhenti.
+func Connect(ctx context.Context, o Options) (*mongo.Database, error) {
+	client, err := mongo.Connect(ctx, clientOptions(o))
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		client.Disconnect(context.Background())
+		return nil, err
+	}
+	if o.Database == "" {
+		o.Database = DatabaseName
+	}
+	return client.Database(o.Database), nil
+}
@@ -0,0 +1,178 @@
+package database
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/description"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+// ErrReadOnly dikembalikan untuk write yang ditolak selama mode baca saja.
+var ErrReadOnly = errors.New("database sedang read-only")
+
+// Kode error server yang berarti node bukan (lagi) primary atau sedang
+// berhenti, mis. saat stepdown dan pemilihan primary baru
+var notWritableCodes = []int{
+	91,    // ShutdownInProgress
+	189,   // PrimarySteppedDown
+	10107, // NotWritablePrimary
+	11600, // InterruptedAtShutdown
+	11602, // InterruptedDueToReplStateChange
+	13435, // NotPrimaryNoSecondaryOk
+	13436, // NotPrimaryOrSecondary
+}
+
+// IsUnavailable bernilai true jika err berarti MongoDB (atau primary-nya)
+// sedang tidak bisa dijangkau, bukan kesalahan pada request itu sendiri.
+func IsUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var selection topology.ServerSelectionError
+	if errors.Is(err, ErrReadOnly) || errors.As(err, &selection) || mongo.IsNetworkError(err) {
+		return true
+	}
+	var se mongo.ServerError
+	if errors.As(err, &se) {
+		for _, code := range notWritableCodes {
+			if se.HasErrorCode(code) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Status adalah mode database saat ini.
+type Status struct {
+	ReadOnly bool `json:"read_only"`
+	// Dipaksa admin (latihan failover atau maintenance), bukan terdeteksi
+	Forced bool       `json:"forced,omitempty"`
+	Reason string     `json:"reason,omitempty"`
+	Since  *time.Time `json:"since,omitempty"`
+}
+
+// State melacak apakah MongoDB bisa menerima write. Tanpa primary
+// (failover, stepdown, cluster dikunci read-only) aplikasi masuk mode baca
+// saja sampai driver menemukan primary lagi. Admin juga bisa memaksa mode
+// ini lewat Force.
+type State struct {
+	mu sync.Mutex
+	// Topology terakhir tanpa node yang bisa ditulis
+	detected bool
+	// Deteksi baru berlaku setelah primary pernah terlihat, supaya
+	// topology yang masih kosong saat startup tidak dianggap failover
+	seen   bool
+	forced bool
+	reason string
+	since  time.Time
+	subs   []func(readOnly bool)
+}
+
+func NewState() *State {
+	return &State{}
+}
+
+// ReadOnly bernilai true selama write harus ditahan. Aman dipanggil pada
+// receiver nil (selalu bisa ditulis).
+func (s *State) ReadOnly() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.detected || s.forced
+}
+
+func (s *State) Status() Status {
+	if s == nil {
+		return Status{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := Status{ReadOnly: s.detected || s.forced, Forced: s.forced}
+	if st.ReadOnly {
+		since := s.since
+		st.Reason, st.Since = s.reason, &since
+	}
+	return st
+}
+
+// Subscribe mendaftarkan fn yang dipanggil setiap kali mode berubah.
+// fn dipanggil sinkron dari monitor driver, jadi tidak boleh memblokir.
+func (s *State) Subscribe(fn func(readOnly bool)) {
+	s.mu.Lock()
+	s.subs = append(s.subs, fn)
+	s.mu.Unlock()
+}
+
+// Force memaksa (atau melepas) mode baca saja. Melepas paksaan tidak
+// menulis ulang hasil deteksi: tanpa primary database tetap read-only.
+func (s *State) Force(readOnly bool) {
+	s.update(func() string {
+		s.forced = readOnly
+		return "dipaksa admin"
+	})
+}
+
+func (s *State) detect(writable bool, reason string) {
+	s.update(func() string {
+		if writable {
+			s.seen = true
+		}
+		s.detected = s.seen && !writable
+		return reason
+	})
+}
+
+func (s *State) update(change func() (reason string)) {
+	s.mu.Lock()
+	before := s.detected || s.forced
+	reason := change()
+	after := s.detected || s.forced
+	if before == after {
+		s.mu.Unlock()
+		return
+	}
+	s.since = time.Now().UTC()
+	s.reason = ""
+	if after {
+		s.reason = reason
+	}
+	subs := append([]func(bool){}, s.subs...)
+	s.mu.Unlock()
+
+	if after {
+		slog.Warn("MongoDB tidak bisa ditulis, masuk mode baca saja", "reason", reason)
+	} else {
+		slog.Info("MongoDB bisa ditulis lagi, mode baca saja berakhir")
+	}
+	for _, fn := range subs {
+		fn(after)
+	}
+}
+
+// serverMonitor memperbarui s setiap kali driver melihat perubahan
+// topology, mis. primary hilang saat stepdown atau primary baru terpilih.
+func (s *State) serverMonitor() *event.ServerMonitor {
+	return &event.ServerMonitor{
+		TopologyDescriptionChanged: func(e *event.TopologyDescriptionChangedEvent) {
+			s.detect(writable(e.NewDescription), "primary tidak ditemukan (topology "+e.NewDescription.Kind.String()+")")
+		},
+	}
+}
+
+func writable(t description.Topology) bool {
+	for _, srv := range t.Servers {
+		switch srv.Kind {
+		case description.Standalone, description.RSPrimary, description.Mongos, description.LoadBalancer:
+			return true
+		}
+	}
+	return false
+}
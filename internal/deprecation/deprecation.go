@@ -0,0 +1,203 @@
+// Package deprecation mencatat pemakaian route/fitur lama (alias tanpa
+// versi, header X-User-Email) dan menyimpan tanggal sunset per route,
+// supaya kita tahu kapan perilaku lama aman dihapus.
+package deprecation
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"InfoCuy-Backend/internal/metrics"
+)
+
+var hitsTotal = metrics.NewCounterVec("deprecated_usage_total",
+	"Jumlah request yang memakai route atau fitur deprecated", "kind", "key")
+
+// Usage adalah ringkasan pemakaian satu route/fitur deprecated.
+type Usage struct {
+	Kind      string     `json:"kind"` // route | feature
+	Key       string     `json:"key"`
+	Successor string     `json:"successor,omitempty"`
+	Hits      int64      `json:"hits"`
+	LastSeen  *time.Time `json:"last_seen,omitempty"`
+	Sunset    *time.Time `json:"sunset,omitempty"`
+}
+
+// Client adalah satu client (user agent + IP) yang masih memakai fitur
+// deprecated, untuk mengoordinasikan cutover dengan pemilik client.
+type Client struct {
+	Feature   string    `json:"feature"`
+	UserAgent string    `json:"user_agent"`
+	IP        string    `json:"ip"`
+	Email     string    `json:"email,omitempty"` // email terakhir yang dikirim
+	Hits      int64     `json:"hits"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// maxClients membatasi jumlah client yang diingat supaya user agent acak
+// tidak membuat memori terus tumbuh; hit tetap dihitung di Usage.
+const maxClients = 1000
+
+// Tracker menyimpan hit count dan tanggal sunset di memori proses.
+type Tracker struct {
+	mu      sync.Mutex
+	usages  map[string]*Usage
+	clients map[string]*Client
+	sunsets map[string]time.Time
+	def     *time.Time
+}
+
+// NewTracker membaca konfigurasi sunset dari environment:
+//
+//	LEGACY_SUNSET        tanggal default (YYYY-MM-DD) untuk semua alias lama
+//	LEGACY_SUNSET_ROUTES daftar per route atau fitur, mis.
+//	                     "GET /locations=2027-01-31;header:X-User-Email=2026-12-31"
+func NewTracker() *Tracker {
+	t := &Tracker{usages: map[string]*Usage{}, clients: map[string]*Client{}, sunsets: map[string]time.Time{}}
+	if d, ok := parseDate(os.Getenv("LEGACY_SUNSET")); ok {
+		t.def = &d
+	}
+	for _, pair := range strings.Split(os.Getenv("LEGACY_SUNSET_ROUTES"), ";") {
+		key, date, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			continue
+		}
+		if d, ok := parseDate(date); ok {
+			t.sunsets[strings.TrimSpace(key)] = d
+		}
+	}
+	return t
+}
+
+func parseDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+	if d, err := time.Parse("2006-01-02", s); err == nil {
+		return d, true
+	}
+	if d, err := time.Parse(time.RFC3339, s); err == nil {
+		return d, true
+	}
+	return time.Time{}, false
+}
+
+// Register mendaftarkan route deprecated beserta penggantinya agar tetap
+// muncul di laporan walaupun belum pernah dipanggil.
+func (t *Tracker) Register(key, successor string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	u := t.usage("route", key)
+	u.Successor = successor
+}
+
+// Sunset mengembalikan tanggal sunset route atau fitur (jika dikonfigurasi).
+func (t *Tracker) Sunset(key string) (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if d, ok := t.sunsets[key]; ok {
+		return d, true
+	}
+	if t.def != nil {
+		return *t.def, true
+	}
+	return time.Time{}, false
+}
+
+// HitRoute mencatat satu pemakaian route deprecated.
+func (t *Tracker) HitRoute(key string) { t.hit("route", key) }
+
+// HitFeature mencatat satu pemakaian fitur deprecated (mis. header auth lama).
+func (t *Tracker) HitFeature(key string) { t.hit("feature", key) }
+
+func (t *Tracker) hit(kind, key string) {
+	hitsTotal.Inc(kind, key)
+	now := time.Now().UTC()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	u := t.usage(kind, key)
+	u.Hits++
+	u.LastSeen = &now
+}
+
+// HitClient mencatat pemakaian fitur oleh satu client. Hasilnya true jika
+// client baru pertama kali terlihat, supaya pemanggil cukup menulis log
+// sekali per client.
+func (t *Tracker) HitClient(feature, userAgent, ip, email string) bool {
+	t.hit("feature", feature)
+	now := time.Now().UTC()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	id := feature + "\x00" + userAgent + "\x00" + ip
+	c, ok := t.clients[id]
+	if !ok {
+		if len(t.clients) >= maxClients {
+			return false
+		}
+		c = &Client{Feature: feature, UserAgent: userAgent, IP: ip, FirstSeen: now}
+		t.clients[id] = c
+	}
+	c.Hits++
+	c.LastSeen = now
+	if email != "" {
+		c.Email = email
+	}
+	return !ok
+}
+
+// Clients mengembalikan salinan client yang masih memakai fitur deprecated,
+// yang paling banyak hit lebih dulu.
+func (t *Tracker) Clients() []Client {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]Client, 0, len(t.clients))
+	for _, c := range t.clients {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Hits != out[j].Hits {
+			return out[i].Hits > out[j].Hits
+		}
+		return out[i].LastSeen.After(out[j].LastSeen)
+	})
+	return out
+}
+
+func (t *Tracker) usage(kind, key string) *Usage {
+	id := kind + ":" + key
+	u, ok := t.usages[id]
+	if !ok {
+		u = &Usage{Kind: kind, Key: key}
+		t.usages[id] = u
+	}
+	return u
+}
+
+// Report mengembalikan salinan semua data pemakaian, diurutkan per key.
+func (t *Tracker) Report() []Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]Usage, 0, len(t.usages))
+	for _, u := range t.usages {
+		cp := *u
+		if d, ok := t.sunsets[u.Key]; ok {
+			cp.Sunset = &d
+		} else if t.def != nil {
+			d := *t.def
+			cp.Sunset = &d
+		}
+		out = append(out, cp)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind < out[j].Kind
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
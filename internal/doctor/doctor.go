@@ -0,0 +1,270 @@
+// Package doctor memeriksa apakah sebuah deployment siap jalan:
+// konfigurasi, koneksi MongoDB, SMTP dan object storage, index yang wajib
+// ada, serta kekuatan JWT_SECRET. Dipakai oleh cmd/doctor dan
+// GET /admin/doctor supaya deployment yang rusak cepat ketahuan sebabnya.
+//
+// Aplikasi tidak memakai Redis (rate limit dan cache disimpan di memori
+// proses), jadi tidak ada pemeriksaan Redis.
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/config"
+	"InfoCuy-Backend/internal/mailer"
+	"InfoCuy-Backend/internal/objectstore"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// Status hasil satu pemeriksaan. Skip berarti dependency opsional yang
+// memang tidak dikonfigurasi, atau pemeriksaan yang tidak bisa dijalankan
+// karena pemeriksaan sebelumnya gagal.
+const (
+	Pass = "pass"
+	Fail = "fail"
+	Skip = "skip"
+)
+
+// Batas waktu setiap pemeriksaan jaringan
+const checkTimeout = 5 * time.Second
+
+// Check adalah hasil satu pemeriksaan.
+type Check struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Message   string  `json:"message"`
+	LatencyMS float64 `json:"latency_ms,omitempty"`
+}
+
+// Report berisi semua pemeriksaan. OK false jika ada yang Fail.
+type Report struct {
+	OK     bool    `json:"ok"`
+	Checks []Check `json:"checks"`
+}
+
+// Options adalah dependency yang diperiksa. Field nil berarti dependency
+// tersebut tidak tersedia; alasannya diambil dari ConfigErr/ConnectErr.
+type Options struct {
+	Config    *config.Config
+	ConfigErr error
+	DB        *mongo.Database
+	// Error saat membuka koneksi ke DB (jika DB nil)
+	ConnectErr error
+	Mailer     mailer.Mailer
+	// nil = upload foto nonaktif (OBJECT_STORE kosong atau tidak valid)
+	Store objectstore.Store
+}
+
+type Doctor struct {
+	opts Options
+}
+
+func New(opts Options) *Doctor {
+	return &Doctor{opts: opts}
+}
+
+// Index yang wajib ada; tanpa index ini constraint unik, TTL atau query
+// geospasial tidak berjalan.
+type requiredIndex struct {
+	collection string
+	keys       bson.D
+	unique     bool
+	// Tidak dibuat otomatis saat server start
+	manual bool
+}
+
+func (i requiredIndex) name() string {
+	fields := make([]string, len(i.keys))
+	for n, k := range i.keys {
+		fields[n] = k.Key
+	}
+	return i.collection + "." + strings.Join(fields, "_")
+}
+
+var requiredIndexes = []requiredIndex{
+	{collection: "user", keys: bson.D{{Key: "email", Value: 1}}, unique: true, manual: true},
+	{collection: "geo_data", keys: bson.D{{Key: "coordinates", Value: "2dsphere"}}},
+	{collection: "categories", keys: bson.D{{Key: "slug", Value: 1}}, unique: true},
+	{collection: "reviews", keys: bson.D{{Key: "location_id", Value: 1}, {Key: "user_id", Value: 1}}, unique: true},
+	{collection: "location_confirmations", keys: bson.D{{Key: "location_id", Value: 1}, {Key: "user_id", Value: 1}}, unique: true},
+	{collection: "location_confirmations", keys: bson.D{{Key: "expires_at", Value: 1}}},
+	{collection: "favorites", keys: bson.D{{Key: "user_id", Value: 1}, {Key: "location_id", Value: 1}}, unique: true},
+	{collection: "password_resets", keys: bson.D{{Key: "expires_at", Value: 1}}},
+	{collection: "campaign_recipients", keys: bson.D{{Key: "token", Value: 1}}, unique: true},
+	{collection: "regions", keys: bson.D{{Key: "geometry", Value: "2dsphere"}}},
+	{collection: "transit_stops", keys: bson.D{{Key: "coordinates", Value: "2dsphere"}}},
+	{collection: "audit_logs", keys: bson.D{{Key: "seq", Value: 1}}, unique: true},
+	{collection: "promotion_impressions", keys: bson.D{{Key: "location_id", Value: 1}, {Key: "date", Value: 1}}, unique: true},
+	{collection: "scheduled_exports", keys: bson.D{{Key: "enabled", Value: 1}, {Key: "next_run_at", Value: 1}}},
+}
+
+// Run menjalankan semua pemeriksaan secara berurutan.
+func (d *Doctor) Run(ctx context.Context) Report {
+	checks := []Check{d.checkConfig(), d.checkJWTSecret()}
+	mongoCheck := d.checkMongo(ctx)
+	checks = append(checks, mongoCheck)
+	checks = append(checks, d.checkIndexes(ctx, mongoCheck.Status == Pass)...)
+	checks = append(checks, d.checkMail(ctx), d.checkStorage(ctx))
+	report := Report{OK: true, Checks: checks}
+	for _, c := range checks {
+		if c.Status == Fail {
+			report.OK = false
+		}
+	}
+	return report
+}
+
+func (d *Doctor) checkConfig() Check {
+	if d.opts.ConfigErr != nil {
+		return Check{Name: "config", Status: Fail, Message: d.opts.ConfigErr.Error()}
+	}
+	return Check{Name: "config", Status: Pass, Message: "Semua pengaturan wajib terisi dan valid"}
+}
+
+func (d *Doctor) checkJWTSecret() Check {
+	chk := Check{Name: "jwt_secret", Status: Fail}
+	if d.opts.Config == nil {
+		chk.Status, chk.Message = Skip, "Konfigurasi tidak valid"
+		return chk
+	}
+	if weakness := d.opts.Config.JWT.Weakness(); weakness != "" {
+		chk.Message = weakness
+		return chk
+	}
+	chk.Status, chk.Message = Pass, fmt.Sprintf("JWT_SECRET %d karakter", len(d.opts.Config.JWT.Secret))
+	return chk
+}
+
+func (d *Doctor) checkMongo(ctx context.Context) Check {
+	chk := Check{Name: "mongodb"}
+	if d.opts.DB == nil {
+		switch {
+		case d.opts.ConnectErr != nil:
+			chk.Status, chk.Message = Fail, "Gagal terhubung: "+d.opts.ConnectErr.Error()
+		default:
+			chk.Status, chk.Message = Skip, "Konfigurasi tidak valid"
+		}
+		return chk
+	}
+	err := timed(ctx, &chk, func(ctx context.Context) error {
+		return d.opts.DB.Client().Ping(ctx, readpref.Primary())
+	})
+	if err != nil {
+		chk.Status, chk.Message = Fail, "Ping gagal: "+err.Error()
+		return chk
+	}
+	chk.Status, chk.Message = Pass, "Terhubung ke database "+d.opts.DB.Name()
+	return chk
+}
+
+func (d *Doctor) checkIndexes(ctx context.Context, connected bool) []Check {
+	checks := make([]Check, 0, len(requiredIndexes))
+	// Index per collection cukup dibaca sekali
+	specs := map[string][]*mongo.IndexSpecification{}
+	for _, idx := range requiredIndexes {
+		chk := Check{Name: "index:" + idx.name()}
+		if !connected {
+			chk.Status, chk.Message = Skip, "MongoDB tidak terhubung"
+			checks = append(checks, chk)
+			continue
+		}
+		list, ok := specs[idx.collection]
+		if !ok {
+			listCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+			var err error
+			list, err = d.opts.DB.Collection(idx.collection).Indexes().ListSpecifications(listCtx)
+			cancel()
+			if err != nil && !isNamespaceNotFound(err) {
+				chk.Status, chk.Message = Fail, "Gagal membaca index: "+err.Error()
+				checks = append(checks, chk)
+				continue
+			}
+			specs[idx.collection] = list
+		}
+		switch found, unique := findIndex(list, idx.keys); {
+		case !found && idx.manual:
+			chk.Status, chk.Message = Fail, "Index belum dibuat dan tidak dibuat otomatis, buat manual di MongoDB"
+		case !found:
+			chk.Status, chk.Message = Fail, "Index belum dibuat; jalankan server sekali supaya index dibuat otomatis"
+		case idx.unique && !unique:
+			chk.Status, chk.Message = Fail, "Index ada tetapi tidak unique"
+		default:
+			chk.Status, chk.Message = Pass, "Index tersedia"
+		}
+		checks = append(checks, chk)
+	}
+	return checks
+}
+
+// Collection yang belum pernah ditulis belum punya index sama sekali
+func isNamespaceNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && cmdErr.Code == 26
+}
+
+// findIndex mencari index dengan key yang sama persis (urutan & arah).
+func findIndex(specs []*mongo.IndexSpecification, keys bson.D) (found, unique bool) {
+	for _, spec := range specs {
+		var got bson.D
+		if err := bson.Unmarshal(spec.KeysDocument, &got); err != nil || len(got) != len(keys) {
+			continue
+		}
+		match := true
+		for i := range keys {
+			// Angka bisa tersimpan sebagai int32/int64/double
+			if got[i].Key != keys[i].Key || fmt.Sprint(got[i].Value) != fmt.Sprint(keys[i].Value) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true, spec.Unique != nil && *spec.Unique
+		}
+	}
+	return false, false
+}
+
+func (d *Doctor) checkMail(ctx context.Context) Check {
+	chk := Check{Name: "mail"}
+	if d.opts.Mailer == nil || !d.opts.Mailer.Configured() {
+		chk.Status, chk.Message = Skip, "Provider email belum dikonfigurasi (MAIL_PROVIDER), email hanya ditulis ke log"
+		return chk
+	}
+	if err := timed(ctx, &chk, d.opts.Mailer.Ping); err != nil {
+		chk.Status, chk.Message = Fail, err.Error()
+		return chk
+	}
+	chk.Status, chk.Message = Pass, "Login ke "+d.opts.Mailer.Name()+" berhasil"
+	return chk
+}
+
+func (d *Doctor) checkStorage(ctx context.Context) Check {
+	chk := Check{Name: "storage"}
+	if d.opts.Store == nil {
+		chk.Status, chk.Message = Skip, "OBJECT_STORE kosong atau tidak valid, upload foto nonaktif"
+		return chk
+	}
+	if err := timed(ctx, &chk, d.opts.Store.Ping); err != nil {
+		chk.Status, chk.Message = Fail, err.Error()
+		return chk
+	}
+	chk.Status, chk.Message = Pass, "Object storage "+d.opts.Store.Name()+" bisa diakses"
+	return chk
+}
+
+// timed menjalankan fn dengan checkTimeout dan mencatat latensinya.
+func timed(ctx context.Context, chk *Check, fn func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	start := time.Now()
+	err := fn(ctx)
+	chk.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+	return err
+}
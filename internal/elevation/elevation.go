@@ -0,0 +1,106 @@
+// Package elevation mencari ketinggian (meter di atas permukaan laut) untuk
+// koordinat lokasi. Provider bawaan memakai API Open-Elevation.
+//
+// Konfigurasi lewat environment:
+//
+//	ELEVATION_PROVIDER  open-elevation (kosong = nonaktif)
+//	ELEVATION_URL       base URL, default https://api.open-elevation.com
+package elevation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/httpclient"
+	"InfoCuy-Backend/internal/models"
+)
+
+// Provider mengembalikan ketinggian untuk setiap titik, urutannya sama
+// dengan input.
+type Provider interface {
+	Lookup(ctx context.Context, points []models.Coordinates) ([]float64, error)
+}
+
+// NewFromEnv mengembalikan nil jika lookup ketinggian tidak dikonfigurasi.
+func NewFromEnv() Provider {
+	switch strings.ToLower(os.Getenv("ELEVATION_PROVIDER")) {
+	case "":
+		return nil
+	case "open-elevation":
+		return NewOpenElevation(os.Getenv("ELEVATION_URL"))
+	default:
+		log.Println("Warning: ELEVATION_PROVIDER tidak didukung:", os.Getenv("ELEVATION_PROVIDER"))
+		return nil
+	}
+}
+
+type openElevation struct {
+	baseURL string
+	client  *httpclient.Client
+}
+
+func NewOpenElevation(baseURL string) Provider {
+	if baseURL == "" {
+		baseURL = "https://api.open-elevation.com"
+	}
+	return &openElevation{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		// Lookup hanya membaca, aman diulang walau memakai POST
+		client: httpclient.New("open-elevation", httpclient.Options{Timeout: 10 * time.Second, Retries: 2, RetryUnsafe: true}),
+	}
+}
+
+type point struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// POST /api/v1/lookup menerima banyak titik sekaligus
+func (o *openElevation) Lookup(ctx context.Context, points []models.Coordinates) ([]float64, error) {
+	req := struct {
+		Locations []point `json:"locations"`
+	}{Locations: make([]point, len(points))}
+	for i, p := range points {
+		req.Locations[i] = point{Latitude: p.Lat, Longitude: p.Lng}
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/api/v1/lookup", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := o.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("open-elevation: status %d", resp.StatusCode)
+	}
+	var out struct {
+		Results []struct {
+			Elevation float64 `json:"elevation"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	if len(out.Results) != len(points) {
+		return nil, fmt.Errorf("open-elevation: %d hasil untuk %d titik", len(out.Results), len(points))
+	}
+	elevations := make([]float64, len(points))
+	for i, r := range out.Results {
+		elevations[i] = r.Elevation
+	}
+	return elevations, nil
+}
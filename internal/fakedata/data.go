@@ -0,0 +1,104 @@
+package fakedata
+
+var roleWeights = []weightedItem{
+	{"user", 90},
+	{"contributor", 8},
+	{"admin", 2},
+}
+
+var categoryWeights = []weightedItem{
+	{"restoran", 25},
+	{"kafe", 15},
+	{"minimarket", 15},
+	{"masjid", 10},
+	{"sekolah", 8},
+	{"atm", 8},
+	{"spbu", 5},
+	{"hotel", 5},
+	{"rumah_sakit", 4},
+	{"taman", 5},
+}
+
+// Awalan nama lokasi per kategori
+var categoryNames = map[string]string{
+	"restoran":    "Warung Makan",
+	"kafe":        "Kopi",
+	"minimarket":  "Toko",
+	"masjid":      "Masjid",
+	"sekolah":     "SD Negeri",
+	"atm":         "ATM",
+	"spbu":        "SPBU",
+	"hotel":       "Hotel",
+	"rumah_sakit": "RS",
+	"taman":       "Taman",
+}
+
+// Nama kategori untuk legenda peta
+var categoryLabels = map[string]string{
+	"restoran":    "Restoran",
+	"kafe":        "Kafe",
+	"minimarket":  "Minimarket",
+	"masjid":      "Masjid",
+	"sekolah":     "Sekolah",
+	"atm":         "ATM",
+	"spbu":        "SPBU",
+	"hotel":       "Hotel",
+	"rumah_sakit": "Rumah Sakit",
+	"taman":       "Taman",
+}
+
+// Tag OpenStreetMap yang setara dengan setiap kategori
+var categoryOSMTags = map[string][]string{
+	"restoran":    {"amenity=restaurant"},
+	"kafe":        {"amenity=cafe"},
+	"minimarket":  {"shop=convenience"},
+	"masjid":      {"amenity=place_of_worship"},
+	"sekolah":     {"amenity=school"},
+	"atm":         {"amenity=atm"},
+	"spbu":        {"amenity=fuel"},
+	"hotel":       {"tourism=hotel"},
+	"rumah_sakit": {"amenity=hospital"},
+	"taman":       {"leisure=park"},
+}
+
+var categoryColors = []string{
+	"#e6194b", "#3cb44b", "#ffe119", "#4363d8", "#f58231",
+	"#911eb4", "#46f0f0", "#f032e6", "#bcf60c", "#008080",
+}
+
+type city struct {
+	name      string
+	lat, lng  float64
+	spread    float64 // derajat, ~1 derajat = 111 km
+	districts []string
+}
+
+var cities = []city{
+	{"Jakarta", -6.2088, 106.8456, 0.06, []string{"Menteng", "Tebet", "Kemang", "Cempaka Putih", "Kelapa Gading", "Grogol"}},
+	{"Bandung", -6.9175, 107.6191, 0.04, []string{"Coblong", "Sukajadi", "Lengkong", "Buahbatu", "Cicendo"}},
+	{"Surabaya", -7.2575, 112.7521, 0.05, []string{"Gubeng", "Tegalsari", "Wonokromo", "Rungkut", "Sukolilo"}},
+	{"Yogyakarta", -7.7956, 110.3695, 0.03, []string{"Gondokusuman", "Umbulharjo", "Kotagede", "Jetis", "Mergangsan"}},
+	{"Medan", 3.5952, 98.6722, 0.05, []string{"Medan Baru", "Medan Petisah", "Medan Johor", "Medan Sunggal"}},
+	{"Makassar", -5.1477, 119.4327, 0.04, []string{"Panakkukang", "Tamalate", "Rappocini", "Ujung Pandang"}},
+	{"Denpasar", -8.6705, 115.2126, 0.03, []string{"Denpasar Barat", "Denpasar Timur", "Denpasar Selatan", "Denpasar Utara"}},
+}
+
+var firstNames = []string{
+	"Budi", "Siti", "Agus", "Dewi", "Rizky", "Putri", "Andi", "Nur", "Fajar", "Ayu",
+	"Dimas", "Rina", "Yusuf", "Indah", "Bayu", "Wulan", "Hendra", "Lestari", "Reza", "Maya",
+}
+
+var lastNames = []string{
+	"Santoso", "Wijaya", "Saputra", "Lestari", "Pratama", "Hidayat", "Kurniawan", "Siregar",
+	"Nasution", "Wibowo", "Setiawan", "Harahap", "Gunawan", "Susanto", "Purnomo",
+}
+
+var placeNames = []string{
+	"Sari Rasa", "Barokah", "Sejahtera", "Harapan", "Makmur", "Sentosa", "Mulia", "Jaya",
+	"Al-Ikhlas", "Nusantara", "Merdeka", "Cahaya", "Melati", "Kenanga", "Pelangi", "Bahagia",
+}
+
+var streets = []string{
+	"Sudirman", "Thamrin", "Gatot Subroto", "Diponegoro", "Ahmad Yani", "Pemuda", "Merdeka",
+	"Gajah Mada", "Hayam Wuruk", "Pahlawan", "Veteran", "Kartini", "Imam Bonjol", "Cendrawasih",
+}
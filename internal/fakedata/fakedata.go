@@ -0,0 +1,127 @@
+// Package fakedata membuat data user & lokasi palsu yang deterministik:
+// seed yang sama selalu menghasilkan data yang sama (termasuk ObjectID),
+// sehingga seeder, benchmark dan integration test memakai fixture yang
+// bisa direproduksi.
+package fakedata
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultPassword adalah password semua user hasil generator. Generator
+// tidak meng-hash password karena bcrypt memakai salt acak; hash di
+// pemanggil (mis. seeder) jika perlu.
+const DefaultPassword = "password123"
+
+// Generator tidak aman dipakai dari beberapa goroutine sekaligus.
+type Generator struct {
+	rng *rand.Rand
+}
+
+func New(seed int64) *Generator {
+	return &Generator{rng: rand.New(rand.NewSource(seed))}
+}
+
+// ObjectID deterministik dari RNG
+func (g *Generator) objectID() primitive.ObjectID {
+	var id primitive.ObjectID
+	g.rng.Read(id[:])
+	return id
+}
+
+func (g *Generator) pick(list []string) string {
+	return list[g.rng.Intn(len(list))]
+}
+
+// Pilih key berdasarkan bobot
+func (g *Generator) weighted(items []weightedItem) string {
+	total := 0
+	for _, it := range items {
+		total += it.weight
+	}
+	n := g.rng.Intn(total)
+	for _, it := range items {
+		if n < it.weight {
+			return it.value
+		}
+		n -= it.weight
+	}
+	return items[len(items)-1].value
+}
+
+type weightedItem struct {
+	value  string
+	weight int
+}
+
+// Users membuat n user. Email unik dalam satu generator.
+func (g *Generator) Users(n int) []models.User {
+	users := make([]models.User, n)
+	for i := range users {
+		first, last := g.pick(firstNames), g.pick(lastNames)
+		users[i] = models.User{
+			ID:       g.objectID(),
+			Email:    fmt.Sprintf("%s.%s%d@example.com", strings.ToLower(first), strings.ToLower(last), i+1),
+			Password: DefaultPassword,
+			Role:     g.weighted(roleWeights),
+		}
+	}
+	return users
+}
+
+// Categories mengembalikan kategori yang dipakai Locations, dengan nama
+// yang mudah dibaca untuk legenda peta.
+func Categories() []models.Category {
+	categories := make([]models.Category, len(categoryWeights))
+	for i, c := range categoryWeights {
+		categories[i] = models.Category{
+			Name:    categoryLabels[c.value],
+			Slug:    c.value,
+			Color:   categoryColors[i%len(categoryColors)],
+			OSMTags: categoryOSMTags[c.value],
+		}
+	}
+	return categories
+}
+
+// Locations membuat n lokasi yang mengumpul di sekitar kota besar. Pembuat
+// dipilih acak dari creators; jika kosong created_by dibiarkan kosong.
+func (g *Generator) Locations(n int, creators []models.User) []models.Location {
+	locs := make([]models.Location, n)
+	for i := range locs {
+		city := cities[g.rng.Intn(len(cities))]
+		category := g.weighted(categoryWeights)
+		loc := models.Location{
+			ID:          g.objectID(),
+			Name:        fmt.Sprintf("%s %s", categoryNames[category], g.pick(placeNames)),
+			Category:    category,
+			Coordinates: g.around(city),
+			Address: fmt.Sprintf("Jl. %s No. %d, %s, %s",
+				g.pick(streets), g.rng.Intn(200)+1, g.pick(city.districts), city.name),
+			Verified: g.rng.Intn(4) == 0,
+		}
+		if len(creators) > 0 {
+			loc.CreatedBy = creators[g.rng.Intn(len(creators))].Email
+		}
+		locs[i] = loc
+	}
+	return locs
+}
+
+// Titik acak (sebaran normal) di sekitar pusat kota
+func (g *Generator) around(c city) models.Coordinates {
+	return models.Coordinates{
+		Lat: round6(c.lat + g.rng.NormFloat64()*c.spread),
+		Lng: round6(c.lng + g.rng.NormFloat64()*c.spread),
+	}
+}
+
+func round6(v float64) float64 {
+	return float64(int64(v*1e6)) / 1e6
+}
@@ -0,0 +1,80 @@
+// Package fieldpolicy menentukan field payload mana yang hanya boleh ditulis
+// oleh pemilik permission tertentu. Handler cukup memanggil Apply sebelum bind ke struct,
+// sehingga aturan tidak tersebar di tiap handler.
+package fieldpolicy
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Rule memproteksi satu field. Jika Value diisi, hanya nilai itu yang
+// diproteksi (mis. visibility=pinned), nilai lain bebas ditulis.
+type Rule struct {
+	Field      string
+	Value      interface{}
+	Permission string
+}
+
+// Policy adalah kumpulan rule untuk satu resource.
+type Policy struct {
+	Resource string
+	Rules    []Rule
+}
+
+// Mode penanganan field terlarang: strip (buang diam-diam) atau reject.
+type Mode string
+
+const (
+	ModeStrip  Mode = "strip"
+	ModeReject Mode = "reject"
+)
+
+// ForbiddenError dikembalikan pada ModeReject.
+type ForbiddenError struct {
+	Resource string
+	Fields   []string
+}
+
+func (e *ForbiddenError) Error() string {
+	return fmt.Sprintf("field %s pada %s tidak boleh diubah oleh role Anda", strings.Join(e.Fields, ", "), e.Resource)
+}
+
+// ModeFromEnv membaca FIELD_POLICY_MODE (default strip).
+func ModeFromEnv() Mode {
+	if strings.EqualFold(os.Getenv("FIELD_POLICY_MODE"), string(ModeReject)) {
+		return ModeReject
+	}
+	return ModeStrip
+}
+
+// Apply menghapus field terlarang dari payload (in-place) dan mengembalikan
+// daftar field yang dibuang. can dipanggil untuk mengecek permission
+// pemanggil. Pada ModeReject payload tidak diubah dan error
+// *ForbiddenError dikembalikan.
+func (p Policy) Apply(can func(permission string) bool, payload map[string]interface{}, mode Mode) ([]string, error) {
+	var blocked []string
+	for _, rule := range p.Rules {
+		val, present := payload[rule.Field]
+		if !present || can(rule.Permission) {
+			continue
+		}
+		if rule.Value != nil && fmt.Sprint(val) != fmt.Sprint(rule.Value) {
+			continue
+		}
+		blocked = append(blocked, rule.Field)
+	}
+	if len(blocked) == 0 {
+		return nil, nil
+	}
+	sort.Strings(blocked)
+	if mode == ModeReject {
+		return nil, &ForbiddenError{Resource: p.Resource, Fields: blocked}
+	}
+	for _, f := range blocked {
+		delete(payload, f)
+	}
+	return blocked, nil
+}
@@ -0,0 +1,108 @@
+package geocode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+const (
+	// Batas jumlah entry di cache; entry kedaluwarsa dibuang saat batas tercapai
+	maxCacheEntries = 20000
+	// Reverse dibulatkan ke 4 desimal (~11 m) supaya klik yang berdekatan
+	// memakai satu panggilan
+	reversePrecision = 1e4
+	// Jika provider gagal, entry kedaluwarsa masih dipakai sampai umur ini
+	maxStaleAge = 7 * 24 * time.Hour
+)
+
+type cacheEntry struct {
+	places    []Place
+	storedAt  time.Time
+	expiresAt time.Time
+}
+
+type cached struct {
+	Provider
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// WithCache membungkus provider sehingga hasil yang sama dipakai ulang
+// selama ttl, termasuk hasil kosong. Saat provider gagal (termasuk rate
+// limit atau circuit terbuka), entry kedaluwarsa masih dipakai sampai
+// maxStaleAge.
+func WithCache(p Provider, ttl time.Duration) Provider {
+	return &cached{Provider: p, ttl: ttl, entries: map[string]cacheEntry{}}
+}
+
+func (c *cached) Reverse(ctx context.Context, coord models.Coordinates) (*Place, error) {
+	rounded := models.Coordinates{
+		Lat: math.Round(coord.Lat*reversePrecision) / reversePrecision,
+		Lng: math.Round(coord.Lng*reversePrecision) / reversePrecision,
+	}
+	key := fmt.Sprintf("r:%.4f,%.4f", rounded.Lat, rounded.Lng)
+	places, err := c.get(key, func() ([]Place, error) {
+		place, err := c.Provider.Reverse(ctx, rounded)
+		if err != nil {
+			return nil, err
+		}
+		return []Place{*place}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(places) == 0 {
+		return nil, ErrNoResult
+	}
+	place := places[0]
+	return &place, nil
+}
+
+func (c *cached) Search(ctx context.Context, query string, limit int) ([]Place, error) {
+	key := fmt.Sprintf("s:%d:%s", limit, strings.ToLower(strings.Join(strings.Fields(query), " ")))
+	return c.get(key, func() ([]Place, error) {
+		return c.Provider.Search(ctx, query, limit)
+	})
+}
+
+func (c *cached) get(key string, fetch func() ([]Place, error)) ([]Place, error) {
+	now := time.Now()
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.places, nil
+	}
+
+	places, err := fetch()
+	if err != nil && !errors.Is(err, ErrNoResult) {
+		// Fallback: alamat lama jauh lebih berguna daripada error
+		if ok && now.Sub(entry.storedAt) < maxStaleAge {
+			return entry.places, nil
+		}
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if len(c.entries) >= maxCacheEntries {
+		for k, e := range c.entries {
+			if now.After(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	if len(c.entries) < maxCacheEntries {
+		c.entries[key] = cacheEntry{places: places, storedAt: now, expiresAt: now.Add(c.ttl)}
+	}
+	c.mu.Unlock()
+	return places, nil
+}
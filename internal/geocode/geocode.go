@@ -0,0 +1,142 @@
+// Package geocode mengubah koordinat menjadi alamat (reverse) dan teks
+// pencarian menjadi daftar tempat (search/autocomplete) lewat provider
+// pihak ketiga, supaya frontend tidak perlu memegang API key atau
+// berurusan dengan CORS provider. Hasil di-cache dan panggilan ke provider
+// dibatasi sesuai kebijakan provider.
+//
+// Konfigurasi lewat environment:
+//
+//	GEOCODE_PROVIDER        nominatim | google (kosong = nonaktif)
+//	GEOCODE_NOMINATIM_URL   base URL Nominatim, default https://nominatim.openstreetmap.org
+//	GEOCODE_CONTACT         email kontak di User-Agent, diminta kebijakan Nominatim
+//	GOOGLE_MAPS_API_KEY     wajib untuk google
+//	GEOCODE_LANG            bahasa hasil, default id
+//	GEOCODE_COUNTRIES       kode negara untuk membatasi search, dipisah koma, default id
+//	GEOCODE_CACHE_TTL       lama cache, default 24h
+//	GEOCODE_RATE_PER_MIN    panggilan provider per menit, default 60 (nominatim) / 600 (google)
+package geocode
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+// Place adalah satu hasil geocoding dalam bentuk yang sama dengan field
+// lokasi (coordinates, address, postal_code) supaya bisa langsung dipakai
+// untuk mengisi form lokasi.
+type Place struct {
+	Provider    string             `json:"provider"`
+	Name        string             `json:"name,omitempty"`
+	Address     string             `json:"address"`
+	PostalCode  string             `json:"postal_code,omitempty"`
+	Coordinates models.Coordinates `json:"coordinates"`
+	Components  Components         `json:"components"`
+}
+
+// Components adalah bagian-bagian alamat dengan istilah wilayah Indonesia.
+type Components struct {
+	Road        string `json:"road,omitempty"`
+	HouseNumber string `json:"house_number,omitempty"`
+	Village     string `json:"village,omitempty"`  // kelurahan/desa
+	District    string `json:"district,omitempty"` // kecamatan
+	City        string `json:"city,omitempty"`     // kabupaten/kota
+	Province    string `json:"province,omitempty"`
+	Country     string `json:"country,omitempty"`
+	CountryCode string `json:"country_code,omitempty"` // ISO 3166-1 alpha-2, huruf kecil
+}
+
+// Provider adalah layanan geocoding.
+type Provider interface {
+	// Reverse mengembalikan alamat terdekat dari c, atau ErrNoResult.
+	Reverse(ctx context.Context, c models.Coordinates) (*Place, error)
+	// Search mengembalikan paling banyak limit tempat yang cocok dengan query.
+	Search(ctx context.Context, query string, limit int) ([]Place, error)
+}
+
+var (
+	// ErrNoResult dikembalikan Reverse jika tidak ada alamat di titik itu
+	// (mis. di tengah laut).
+	ErrNoResult = errors.New("geocode: alamat tidak ditemukan")
+	// ErrRateLimited dikembalikan jika batas panggilan ke provider sudah
+	// tercapai dan data belum ada di cache.
+	ErrRateLimited = errors.New("geocode: batas panggilan provider tercapai")
+)
+
+const (
+	defaultCacheTTL  = 24 * time.Hour
+	defaultLang      = "id"
+	defaultCountries = "id"
+)
+
+// NewFromEnv mengembalikan nil jika geocoding tidak dikonfigurasi.
+func NewFromEnv() Provider {
+	lang := os.Getenv("GEOCODE_LANG")
+	if lang == "" {
+		lang = defaultLang
+	}
+	countries, ok := os.LookupEnv("GEOCODE_COUNTRIES")
+	if !ok {
+		countries = defaultCountries
+	}
+	var p Provider
+	var rate int
+	switch strings.ToLower(os.Getenv("GEOCODE_PROVIDER")) {
+	case "":
+		return nil
+	case "nominatim":
+		p = newNominatim(os.Getenv("GEOCODE_NOMINATIM_URL"), os.Getenv("GEOCODE_CONTACT"), lang, countries)
+		// Kebijakan server publik Nominatim: maksimal 1 request per detik
+		rate = 60
+	case "google":
+		key := os.Getenv("GOOGLE_MAPS_API_KEY")
+		if key == "" {
+			log.Println("Warning: GOOGLE_MAPS_API_KEY kosong, geocoding dinonaktifkan")
+			return nil
+		}
+		p = newGoogle(key, lang, countries)
+		rate = 600
+	default:
+		log.Println("Warning: GEOCODE_PROVIDER tidak didukung:", os.Getenv("GEOCODE_PROVIDER"))
+		return nil
+	}
+
+	ttl := defaultCacheTTL
+	if v, err := time.ParseDuration(os.Getenv("GEOCODE_CACHE_TTL")); err == nil && v > 0 {
+		ttl = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("GEOCODE_RATE_PER_MIN")); err == nil && v > 0 {
+		rate = v
+	}
+	// Urutan penting: cache di luar supaya cache hit tidak memakai kuota
+	return WithCache(WithRateLimit(p, rate), ttl)
+}
+
+// join menggabungkan bagian alamat yang tidak kosong dengan ", ". Bagian
+// yang sama dengan sebelumnya dilewati (kelurahan dan kecamatan sering
+// bernama sama).
+func join(parts ...string) string {
+	out := parts[:0]
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" && (len(out) == 0 || out[len(out)-1] != p) {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
+// first mengembalikan nilai pertama yang tidak kosong
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,161 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/httpclient"
+	"InfoCuy-Backend/internal/models"
+)
+
+type google struct {
+	apiKey    string
+	lang      string
+	countries []string
+	client    *httpclient.Client
+}
+
+func newGoogle(apiKey, lang, countries string) *google {
+	g := &google{
+		apiKey: apiKey,
+		lang:   lang,
+		client: httpclient.New("google-geocode", httpclient.Options{Timeout: 5 * time.Second, Retries: 1}),
+	}
+	for _, c := range strings.Split(countries, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			g.countries = append(g.countries, "country:"+strings.ToUpper(c))
+		}
+	}
+	return g
+}
+
+type googleResult struct {
+	FormattedAddress  string `json:"formatted_address"`
+	AddressComponents []struct {
+		LongName  string   `json:"long_name"`
+		ShortName string   `json:"short_name"`
+		Types     []string `json:"types"`
+	} `json:"address_components"`
+	Geometry struct {
+		Location struct {
+			Lat float64 `json:"lat"`
+			Lng float64 `json:"lng"`
+		} `json:"location"`
+	} `json:"geometry"`
+}
+
+// GET /maps/api/geocode/json?latlng=..
+func (g *google) Reverse(ctx context.Context, c models.Coordinates) (*Place, error) {
+	q := url.Values{}
+	q.Set("latlng", strconv.FormatFloat(c.Lat, 'f', -1, 64)+","+strconv.FormatFloat(c.Lng, 'f', -1, 64))
+	results, err := g.get(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrNoResult
+	}
+	place := results[0].place()
+	return &place, nil
+}
+
+// GET /maps/api/geocode/json?address=..
+func (g *google) Search(ctx context.Context, query string, limit int) ([]Place, error) {
+	q := url.Values{}
+	q.Set("address", query)
+	// components hanya boleh satu country; lebih dari satu memakai region
+	if len(g.countries) == 1 {
+		q.Set("components", g.countries[0])
+	} else if len(g.countries) > 1 {
+		q.Set("region", strings.ToLower(strings.TrimPrefix(g.countries[0], "country:")))
+	}
+	results, err := g.get(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	places := make([]Place, 0, min(limit, len(results)))
+	for _, r := range results {
+		if len(places) == limit {
+			break
+		}
+		places = append(places, r.place())
+	}
+	return places, nil
+}
+
+func (g *google) get(ctx context.Context, q url.Values) ([]googleResult, error) {
+	q.Set("language", g.lang)
+	q.Set("key", g.apiKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://maps.googleapis.com/maps/api/geocode/json?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("google geocode: status %d", resp.StatusCode)
+	}
+	var out struct {
+		Status       string         `json:"status"`
+		ErrorMessage string         `json:"error_message"`
+		Results      []googleResult `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	switch out.Status {
+	case "OK", "ZERO_RESULTS":
+		return out.Results, nil
+	case "OVER_QUERY_LIMIT":
+		return nil, ErrRateLimited
+	default:
+		return nil, fmt.Errorf("google geocode: %s %s", out.Status, out.ErrorMessage)
+	}
+}
+
+func (r googleResult) place() Place {
+	var comp Components
+	var postalCode, name string
+	for _, c := range r.AddressComponents {
+		for _, t := range c.Types {
+			switch t {
+			case "route":
+				comp.Road = c.LongName
+			case "street_number":
+				comp.HouseNumber = c.LongName
+			case "administrative_area_level_4":
+				comp.Village = c.LongName
+			case "administrative_area_level_3":
+				comp.District = c.LongName
+			case "administrative_area_level_2":
+				comp.City = c.LongName
+			case "administrative_area_level_1":
+				comp.Province = c.LongName
+			case "country":
+				comp.Country = c.LongName
+				comp.CountryCode = strings.ToLower(c.ShortName)
+			case "postal_code":
+				postalCode = c.LongName
+			case "point_of_interest", "establishment", "premise":
+				name = first(name, c.LongName)
+			}
+		}
+	}
+	return Place{
+		Provider:    "google",
+		Name:        name,
+		Address:     r.FormattedAddress,
+		PostalCode:  postalCode,
+		Coordinates: models.Coordinates{Lat: r.Geometry.Location.Lat, Lng: r.Geometry.Location.Lng},
+		Components:  comp,
+	}
+}
@@ -0,0 +1,138 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/httpclient"
+	"InfoCuy-Backend/internal/models"
+)
+
+type nominatim struct {
+	baseURL   string
+	userAgent string
+	lang      string
+	countries string
+	client    *httpclient.Client
+}
+
+func newNominatim(baseURL, contact, lang, countries string) *nominatim {
+	if baseURL == "" {
+		baseURL = "https://nominatim.openstreetmap.org"
+	}
+	userAgent := "InfoCuy-Backend"
+	if contact != "" {
+		userAgent += " (" + contact + ")"
+	}
+	return &nominatim{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		userAgent: userAgent,
+		lang:      lang,
+		countries: countries,
+		client:    httpclient.New("nominatim", httpclient.Options{Timeout: 5 * time.Second, Retries: 1}),
+	}
+}
+
+// Satu hasil /reverse atau /search dengan format=jsonv2&addressdetails=1
+type nominatimPlace struct {
+	Lat         string            `json:"lat"`
+	Lon         string            `json:"lon"`
+	Name        string            `json:"name"`
+	DisplayName string            `json:"display_name"`
+	Address     map[string]string `json:"address"`
+	Error       string            `json:"error"`
+}
+
+// GET /reverse?lat=..&lon=..
+func (n *nominatim) Reverse(ctx context.Context, c models.Coordinates) (*Place, error) {
+	q := url.Values{}
+	q.Set("lat", strconv.FormatFloat(c.Lat, 'f', -1, 64))
+	q.Set("lon", strconv.FormatFloat(c.Lng, 'f', -1, 64))
+	var out nominatimPlace
+	if err := n.get(ctx, "/reverse", q, &out); err != nil {
+		return nil, err
+	}
+	// Nominatim menjawab 200 dengan {"error":"Unable to geocode"}
+	if out.Error != "" {
+		return nil, ErrNoResult
+	}
+	place := out.place()
+	return &place, nil
+}
+
+// GET /search?q=..
+func (n *nominatim) Search(ctx context.Context, query string, limit int) ([]Place, error) {
+	q := url.Values{}
+	q.Set("q", query)
+	q.Set("limit", strconv.Itoa(limit))
+	if n.countries != "" {
+		q.Set("countrycodes", n.countries)
+	}
+	var out []nominatimPlace
+	if err := n.get(ctx, "/search", q, &out); err != nil {
+		return nil, err
+	}
+	places := make([]Place, 0, len(out))
+	for _, p := range out {
+		places = append(places, p.place())
+	}
+	return places, nil
+}
+
+func (n *nominatim) get(ctx context.Context, path string, q url.Values, out any) error {
+	q.Set("format", "jsonv2")
+	q.Set("addressdetails", "1")
+	q.Set("accept-language", n.lang)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.baseURL+path+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", n.userAgent)
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return ErrRateLimited
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("nominatim: status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (p nominatimPlace) place() Place {
+	lat, _ := strconv.ParseFloat(p.Lat, 64)
+	lng, _ := strconv.ParseFloat(p.Lon, 64)
+	a := p.Address
+	comp := Components{
+		Road:        first(a["road"], a["pedestrian"], a["footway"]),
+		HouseNumber: a["house_number"],
+		Village:     first(a["village"], a["suburb"], a["neighbourhood"], a["hamlet"]),
+		District:    first(a["city_district"], a["district"], a["municipality"]),
+		City:        first(a["city"], a["town"], a["county"], a["regency"]),
+		Province:    first(a["state"], a["province"]),
+		Country:     a["country"],
+		CountryCode: a["country_code"],
+	}
+	street := strings.TrimSpace(comp.Road + " " + comp.HouseNumber)
+	address := join(street, comp.Village, comp.District, comp.City, comp.Province)
+	if address == "" {
+		address = p.DisplayName
+	}
+	return Place{
+		Provider:    "nominatim",
+		Name:        p.Name,
+		Address:     address,
+		PostalCode:  a["postcode"],
+		Coordinates: models.Coordinates{Lat: lat, Lng: lng},
+		Components:  comp,
+	}
+}
@@ -0,0 +1,57 @@
+package geocode
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+type rateLimited struct {
+	Provider
+	perMin int
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// WithRateLimit membungkus provider dengan token bucket: paling banyak
+// perMin panggilan per menit. Burst dibatasi sepersepuluh perMin (minimal
+// 1) karena provider seperti Nominatim menghitung batas per detik. Jika
+// token habis panggilan langsung gagal dengan ErrRateLimited.
+func WithRateLimit(p Provider, perMin int) Provider {
+	return &rateLimited{Provider: p, perMin: perMin, tokens: burst(perMin), last: time.Now()}
+}
+
+func burst(perMin int) float64 {
+	return max(1, float64(perMin/10))
+}
+
+func (r *rateLimited) take() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	r.tokens = min(burst(r.perMin), r.tokens+now.Sub(r.last).Minutes()*float64(r.perMin))
+	r.last = now
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+func (r *rateLimited) Reverse(ctx context.Context, c models.Coordinates) (*Place, error) {
+	if !r.take() {
+		return nil, ErrRateLimited
+	}
+	return r.Provider.Reverse(ctx, c)
+}
+
+func (r *rateLimited) Search(ctx context.Context, query string, limit int) ([]Place, error) {
+	if !r.take() {
+		return nil, ErrRateLimited
+	}
+	return r.Provider.Search(ctx, query, limit)
+}
@@ -0,0 +1,146 @@
+package geoio
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+// Nama kolom koordinat yang dikenali, termasuk X/Y dari export QGIS
+var (
+	latColumns = map[string]bool{"lat": true, "latitude": true, "y": true}
+	lngColumns = map[string]bool{"lng": true, "lon": true, "long": true, "longitude": true, "x": true}
+)
+
+// ReadCSV membaca CSV dengan baris header. Kolom lat/lng wajib ada kecuali
+// file punya kolom address; baris dengan lat dan lng kosong dikirim tanpa
+// koordinat supaya bisa di-geocode dari alamatnya. Sel kosong lainnya
+// dianggap tidak dikirim.
+func ReadCSV(r io.Reader) ([]Row, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	latCol, lngCol, hasAddress := -1, -1, false
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		header[i] = h
+		if latColumns[h] {
+			latCol = i
+		} else if lngColumns[h] {
+			lngCol = i
+		}
+		hasAddress = hasAddress || h == "address"
+	}
+	if (latCol < 0) != (lngCol < 0) || latCol < 0 && !hasAddress {
+		return nil, fmt.Errorf("%w: kolom lat dan lng (atau address) wajib ada", ErrInvalidFile)
+	}
+
+	var rows []Row
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		row := Row{Row: line}
+		if err != nil {
+			row.Err = err
+			rows = append(rows, row)
+			continue
+		}
+		row.Payload, row.Err = csvPayload(header, record, latCol, lngCol)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func csvPayload(header, record []string, latCol, lngCol int) (map[string]interface{}, error) {
+	if len(record) != len(header) {
+		return nil, fmt.Errorf("jumlah kolom %d, seharusnya %d", len(record), len(header))
+	}
+	payload := map[string]interface{}{}
+	if latCol >= 0 && (strings.TrimSpace(record[latCol]) != "" || strings.TrimSpace(record[lngCol]) != "") {
+		lat, errLat := strconv.ParseFloat(strings.TrimSpace(record[latCol]), 64)
+		lng, errLng := strconv.ParseFloat(strings.TrimSpace(record[lngCol]), 64)
+		if errLat != nil || errLng != nil {
+			return nil, fmt.Errorf("koordinat tidak valid")
+		}
+		payload["coordinates"] = coordinates(lat, lng)
+	}
+	for i, h := range header {
+		v := strings.TrimSpace(record[i])
+		if i == latCol || i == lngCol {
+			continue
+		}
+		if h == "verified" && v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("verified harus true/false")
+			}
+			setAttr(payload, h, b)
+			continue
+		}
+		if h == "payment_methods" && v != "" {
+			setAttr(payload, h, strings.Split(v, listSeparator))
+			continue
+		}
+		setAttr(payload, h, v)
+	}
+	return payload, nil
+}
+
+// Pemisah nilai dalam satu sel untuk kolom berisi daftar (payment_methods)
+const listSeparator = ";"
+
+type csvWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+// NewCSVWriter menulis CSV dengan kolom id, lat, lng lalu atribut lokasi.
+func NewCSVWriter(w io.Writer) Writer {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) writeHeader() error {
+	c.wroteHeader = true
+	return c.w.Write(append([]string{"id", "lat", "lng"}, exportFields...))
+}
+
+func (c *csvWriter) Write(loc models.Location) error {
+	if !c.wroteHeader {
+		if err := c.writeHeader(); err != nil {
+			return err
+		}
+	}
+	props := properties(loc)
+	record := []string{
+		loc.ID.Hex(),
+		strconv.FormatFloat(loc.Coordinates.Lat, 'f', -1, 64),
+		strconv.FormatFloat(loc.Coordinates.Lng, 'f', -1, 64),
+	}
+	for _, f := range exportFields {
+		if list, ok := props[f].([]string); ok {
+			record = append(record, strings.Join(list, listSeparator))
+			continue
+		}
+		record = append(record, fmt.Sprint(props[f]))
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	if !c.wroteHeader {
+		if err := c.writeHeader(); err != nil {
+			return err
+		}
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,135 @@
+// Package geoio membaca dan menulis lokasi dalam format GeoJSON dan CSV
+// supaya dataset bisa bolak-balik dengan QGIS. Reader menghasilkan payload
+// mentah per baris (divalidasi di service), writer menulis secara streaming.
+package geoio
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+// Format file yang didukung
+type Format string
+
+const (
+	GeoJSON Format = "geojson"
+	CSV     Format = "csv"
+)
+
+// ParseFormat menerima "geojson"/"json"/"csv" (case-insensitive).
+func ParseFormat(s string) (Format, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "geojson", "json":
+		return GeoJSON, true
+	case "csv":
+		return CSV, true
+	}
+	return "", false
+}
+
+// FormatFromFilename menebak format dari ekstensi file.
+func FormatFromFilename(name string) (Format, bool) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(name), "."))
+}
+
+// ContentType untuk response export
+func (f Format) ContentType() string {
+	if f == CSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/geo+json"
+}
+
+// Row adalah satu baris input. Untuk CSV, Row adalah nomor baris di file
+// (header = 1); untuk GeoJSON, urutan feature mulai dari 1. Err terisi
+// jika baris tidak bisa dibaca sama sekali.
+type Row struct {
+	Row     int
+	Payload map[string]interface{}
+	Err     error
+}
+
+// ErrInvalidFile dikembalikan jika file tidak bisa diparse secara keseluruhan.
+var ErrInvalidFile = errors.New("file tidak valid")
+
+// Writer menulis lokasi satu per satu. Close wajib dipanggil untuk
+// menutup dokumen (mis. kurung penutup FeatureCollection).
+type Writer interface {
+	Write(loc models.Location) error
+	Close() error
+}
+
+// Kolom yang ikut di-export (urutan kolom CSV / properties GeoJSON)
+var exportFields = []string{"name", "category", "address", "postal_code", "created_by", "verified", "status", "visibility",
+	"wheelchair", "accessible_toilet", "accessible_parking", "price_range", "payment_methods"}
+
+// Field aksesibilitas ditulis sebagai kolom datar supaya mudah dipakai di
+// spreadsheet/QGIS, lalu disusun ulang menjadi objek accessibility saat import
+var accessibilityColumns = map[string]bool{"wheelchair": true, "accessible_toilet": true, "accessible_parking": true}
+
+func properties(loc models.Location) map[string]interface{} {
+	var access models.Accessibility
+	if loc.Accessibility != nil {
+		access = *loc.Accessibility
+	}
+	return map[string]interface{}{
+		"name":               loc.Name,
+		"category":           loc.Category,
+		"address":            loc.Address,
+		"postal_code":        loc.PostalCode,
+		"created_by":         loc.CreatedBy,
+		"verified":           loc.Verified,
+		"status":             loc.Status,
+		"visibility":         loc.Visibility,
+		"wheelchair":         access.Wheelchair,
+		"accessible_toilet":  access.AccessibleToilet,
+		"accessible_parking": access.AccessibleParking,
+		"price_range":        loc.PriceRange,
+		"payment_methods":    loc.PaymentMethods,
+	}
+}
+
+// Atribut yang tidak ikut diimport: ID selalu dibuat baru
+var skipOnImport = map[string]bool{"_id": true, "id": true, "fid": true}
+
+// Nilai kosong/false tidak dimasukkan ke payload supaya file hasil export
+// bisa diimport ulang tanpa tersandung field policy (mis. verified=false).
+func setAttr(payload map[string]interface{}, key string, v interface{}) {
+	if skipOnImport[key] || v == nil || v == "" || v == false {
+		return
+	}
+	if accessibilityColumns[key] {
+		access, _ := payload["accessibility"].(map[string]interface{})
+		if access == nil {
+			access = map[string]interface{}{}
+			payload["accessibility"] = access
+		}
+		access[key] = v
+		return
+	}
+	payload[key] = v
+}
+
+func coordinates(lat, lng float64) map[string]interface{} {
+	return map[string]interface{}{"lat": lat, "lng": lng}
+}
+
+// Read membaca file sesuai format.
+func Read(f Format, r io.Reader) ([]Row, error) {
+	if f == CSV {
+		return ReadCSV(r)
+	}
+	return ReadGeoJSON(r)
+}
+
+// NewWriter membuat Writer sesuai format.
+func NewWriter(f Format, w io.Writer) Writer {
+	if f == CSV {
+		return NewCSVWriter(w)
+	}
+	return NewGeoJSONWriter(w)
+}
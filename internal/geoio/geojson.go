@@ -0,0 +1,102 @@
+package geoio
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+type feature struct {
+	Type     string                 `json:"type"`
+	ID       interface{}            `json:"id,omitempty"`
+	Geometry *geometry              `json:"geometry"`
+	Props    map[string]interface{} `json:"properties"`
+}
+
+type geometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// ReadGeoJSON membaca FeatureCollection berisi Point. Feature dengan
+// geometry selain Point dilaporkan sebagai error per baris; geometry null
+// berarti tanpa koordinat (di-geocode dari properti address).
+func ReadGeoJSON(r io.Reader) ([]Row, error) {
+	var fc struct {
+		Type     string            `json:"type"`
+		Features []json.RawMessage `json:"features"`
+	}
+	if err := json.NewDecoder(r).Decode(&fc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	if fc.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("%w: type harus FeatureCollection", ErrInvalidFile)
+	}
+	rows := make([]Row, 0, len(fc.Features))
+	for i, raw := range fc.Features {
+		row := Row{Row: i + 1}
+		var f feature
+		if err := json.Unmarshal(raw, &f); err != nil {
+			row.Err = err
+		} else if f.Geometry != nil && (f.Geometry.Type != "Point" || len(f.Geometry.Coordinates) < 2) {
+			row.Err = fmt.Errorf("geometry harus Point")
+		} else {
+			row.Payload = map[string]interface{}{}
+			for k, v := range f.Props {
+				setAttr(row.Payload, k, v)
+			}
+			if f.Geometry != nil {
+				row.Payload["coordinates"] = coordinates(f.Geometry.Coordinates[1], f.Geometry.Coordinates[0])
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+type geoJSONWriter struct {
+	w     *bufio.Writer
+	count int
+}
+
+// NewGeoJSONWriter menulis FeatureCollection secara streaming.
+func NewGeoJSONWriter(w io.Writer) Writer {
+	return &geoJSONWriter{w: bufio.NewWriter(w)}
+}
+
+func (g *geoJSONWriter) Write(loc models.Location) error {
+	prefix := ",\n"
+	if g.count == 0 {
+		prefix = `{"type":"FeatureCollection","features":[` + "\n"
+	}
+	f := feature{
+		Type:     "Feature",
+		ID:       loc.ID.Hex(),
+		Geometry: &geometry{Type: "Point", Coordinates: []float64{loc.Coordinates.Lng, loc.Coordinates.Lat}},
+		Props:    properties(loc),
+	}
+	b, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	g.count++
+	if _, err := g.w.WriteString(prefix); err != nil {
+		return err
+	}
+	_, err = g.w.Write(b)
+	return err
+}
+
+func (g *geoJSONWriter) Close() error {
+	end := "\n]}\n"
+	if g.count == 0 {
+		end = `{"type":"FeatureCollection","features":[]}` + "\n"
+	}
+	if _, err := g.w.WriteString(end); err != nil {
+		return err
+	}
+	return g.w.Flush()
+}
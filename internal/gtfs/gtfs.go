@@ -0,0 +1,265 @@
+// Package gtfs membaca dataset GTFS statis (file zip) menjadi daftar halte
+// beserta rute yang berhenti di sana. Hanya stops.txt, routes.txt,
+// trips.txt dan stop_times.txt yang dipakai; jadwal tidak disimpan.
+package gtfs
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+// ErrInvalidFeed berarti file bukan zip GTFS yang bisa dibaca.
+var ErrInvalidFeed = errors.New("file GTFS tidak valid")
+
+// Dataset adalah hasil Parse. Stops belum memiliki ID/Feed.
+type Dataset struct {
+	Stops []models.TransitStop
+	// Jumlah rute yang punya minimal satu halte
+	Routes int
+	// Halte yang dibuang (tanpa rute atau koordinat tidak valid)
+	Skipped int
+}
+
+type stopRow struct {
+	stop   models.TransitStop
+	parent string
+}
+
+// Parse membaca zip GTFS. stop_times.txt dibaca secara streaming karena
+// bisa berisi jutaan baris.
+func Parse(r io.ReaderAt, size int64) (*Dataset, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFeed, err)
+	}
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		// Sebagian feed menaruh file di dalam satu folder
+		files[path.Base(f.Name)] = f
+	}
+
+	routes := map[string]models.TransitRoute{}
+	err = eachRecord(files, "routes.txt", []string{"route_id", "route_type"}, func(rec record) error {
+		t, _ := strconv.Atoi(rec.get("route_type"))
+		color := rec.get("route_color")
+		if color != "" {
+			color = "#" + strings.ToUpper(color)
+		}
+		id := rec.get("route_id")
+		routes[id] = models.TransitRoute{
+			ID:        id,
+			ShortName: rec.get("route_short_name"),
+			LongName:  rec.get("route_long_name"),
+			Type:      t,
+			Mode:      Mode(t),
+			Color:     color,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tripRoute := map[string]string{}
+	err = eachRecord(files, "trips.txt", []string{"route_id", "trip_id"}, func(rec record) error {
+		tripRoute[rec.get("trip_id")] = rec.get("route_id")
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stopRoutes := map[string]map[string]bool{}
+	err = eachRecord(files, "stop_times.txt", []string{"trip_id", "stop_id"}, func(rec record) error {
+		routeID, ok := tripRoute[rec.get("trip_id")]
+		if !ok {
+			return nil
+		}
+		stopID := rec.get("stop_id")
+		if stopRoutes[stopID] == nil {
+			stopRoutes[stopID] = map[string]bool{}
+		}
+		stopRoutes[stopID][routeID] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var stops []stopRow
+	ds := &Dataset{}
+	err = eachRecord(files, "stops.txt", []string{"stop_id", "stop_name", "stop_lat", "stop_lon"}, func(rec record) error {
+		locType := rec.get("location_type")
+		// location_type 0/kosong = halte, 1 = stasiun; pintu masuk, node &
+		// area boarding tidak ditampilkan
+		if locType != "" && locType != "0" && locType != "1" {
+			return nil
+		}
+		lat, errLat := strconv.ParseFloat(rec.get("stop_lat"), 64)
+		lng, errLng := strconv.ParseFloat(rec.get("stop_lon"), 64)
+		if errLat != nil || errLng != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+			ds.Skipped++
+			return nil
+		}
+		stops = append(stops, stopRow{
+			stop: models.TransitStop{
+				StopID:      rec.get("stop_id"),
+				Code:        rec.get("stop_code"),
+				Name:        rec.get("stop_name"),
+				Coordinates: models.Coordinates{Lat: lat, Lng: lng},
+			},
+			parent: rec.get("parent_station"),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Stasiun dilayani semua rute yang berhenti di peron-peronnya; peron
+	// sendiri tidak disimpan supaya satu stasiun tidak muncul berkali-kali
+	present := make(map[string]bool, len(stops))
+	for _, s := range stops {
+		present[s.stop.StopID] = true
+	}
+	for _, s := range stops {
+		if s.parent == "" || !present[s.parent] {
+			continue
+		}
+		for routeID := range stopRoutes[s.stop.StopID] {
+			if stopRoutes[s.parent] == nil {
+				stopRoutes[s.parent] = map[string]bool{}
+			}
+			stopRoutes[s.parent][routeID] = true
+		}
+	}
+
+	usedRoutes := map[string]bool{}
+	for _, s := range stops {
+		if s.parent != "" && present[s.parent] {
+			continue
+		}
+		ids := make([]string, 0, len(stopRoutes[s.stop.StopID]))
+		for routeID := range stopRoutes[s.stop.StopID] {
+			if _, ok := routes[routeID]; ok {
+				ids = append(ids, routeID)
+			}
+		}
+		if len(ids) == 0 {
+			ds.Skipped++
+			continue
+		}
+		sort.Strings(ids)
+		s.stop.Routes = make([]models.TransitRoute, len(ids))
+		for i, id := range ids {
+			s.stop.Routes[i] = routes[id]
+			usedRoutes[id] = true
+		}
+		ds.Stops = append(ds.Stops, s.stop)
+	}
+	ds.Routes = len(usedRoutes)
+	return ds, nil
+}
+
+// Mode mengubah route_type GTFS (termasuk extended route type) menjadi
+// nama moda.
+func Mode(routeType int) string {
+	switch routeType {
+	case 0, 5:
+		return "tram"
+	case 1:
+		return "subway"
+	case 2:
+		return "rail"
+	case 3, 11:
+		return "bus"
+	case 4:
+		return "ferry"
+	case 6:
+		return "aerial_lift"
+	case 7:
+		return "funicular"
+	case 12:
+		return "monorail"
+	}
+	switch routeType / 100 {
+	case 1, 3:
+		return "rail"
+	case 2, 7, 8:
+		return "bus"
+	case 4:
+		return "subway"
+	case 9:
+		return "tram"
+	case 10, 12:
+		return "ferry"
+	case 13:
+		return "aerial_lift"
+	case 14:
+		return "funicular"
+	}
+	return "other"
+}
+
+type record struct {
+	cols   map[string]int
+	values []string
+}
+
+func (r record) get(col string) string {
+	if i, ok := r.cols[col]; ok && i < len(r.values) {
+		return strings.TrimSpace(r.values[i])
+	}
+	return ""
+}
+
+// eachRecord memanggil fn untuk setiap baris file CSV di dalam zip.
+func eachRecord(files map[string]*zip.File, name string, required []string, fn func(record) error) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("%w: %s tidak ada", ErrInvalidFeed, name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidFeed, name, err)
+	}
+	defer rc.Close()
+
+	cr := csv.NewReader(rc)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidFeed, name, err)
+	}
+	cols := make(map[string]int, len(header))
+	for i, h := range header {
+		cols[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+	}
+	for _, col := range required {
+		if _, ok := cols[col]; !ok {
+			return fmt.Errorf("%w: kolom %s wajib ada di %s", ErrInvalidFeed, col, name)
+		}
+	}
+	for {
+		values, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidFeed, name, err)
+		}
+		if err := fn(record{cols: cols, values: values}); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"InfoCuy-Backend/internal/adminui"
+	"InfoCuy-Backend/internal/apperr"
+	"InfoCuy-Backend/internal/config"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ADMIN UI, halaman statis yang memanggil API /v1
+func adminPage(c *gin.Context) {
+	adminHeaders(c)
+	c.Data(http.StatusOK, "text/html; charset=utf-8", adminui.IndexHTML())
+}
+
+// UI admin hanya memuat aset sendiri dan tidak boleh dibingkai situs lain
+func adminHeaders(c *gin.Context) {
+	c.Header("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Cache-Control", "no-cache")
+}
+
+// 10. SECURITY CHECK (Admin)
+func (h *Handler) securityCheck(c *gin.Context) {
+	checks := h.Security.RunChecks(c.Request.Context())
+	warnings := 0
+	for _, chk := range checks {
+		if chk.Status != "ok" {
+			warnings++
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"warnings": warnings, "checks": checks})
+}
+
+// DOCTOR (Admin), sama dengan go run ./cmd/doctor dari dalam instance yang berjalan
+func (h *Handler) doctorReport(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Doctor.Run(c.Request.Context()))
+}
+
+// RELOAD CONFIG (Admin), sama dengan SIGHUP: baca ulang .env dan terapkan
+// LOG_LEVEL, CORS_ORIGINS, RATE_LIMIT_*, AUTH_RATE_LIMIT_* dan ALLOW_REGISTRATION.
+// Hanya berlaku untuk instance yang menerima request ini.
+func (h *Handler) reloadConfig(c *gin.Context) {
+	if h.ReloadConfig == nil {
+		respondError(c, apperr.New(http.StatusNotImplemented, "RELOAD_UNSUPPORTED", "Reload config tidak didukung di deployment ini"))
+		return
+	}
+	runtime, changed, err := h.ReloadConfig()
+	var cfgErr *config.Error
+	if errors.As(err, &cfgErr) {
+		respondError(c, apperr.BadRequest(cfgErr.Error()))
+		return
+	} else if err != nil {
+		respondError(c, apperr.BadRequest("Config tidak bisa diterapkan: "+err.Error()))
+		return
+	}
+	if len(changed) > 0 {
+		h.Audit.Record(c.Request.Context(), services.AuditEvent{Action: "config.reload", ResourceType: services.AuditSettings,
+			ResourceID: "runtime", After: gin.H{"changed": changed, "config": runtime}})
+	}
+	if changed == nil {
+		changed = []string{}
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Config dimuat ulang", "changed": changed, "data": runtime})
+}
+
+// DEPRECATION REPORT (Admin): pemakaian route/fitur lama beserta client
+// yang masih auth dengan X-User-Email saja
+func (h *Handler) deprecationReport(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"deprecations":      h.Deprecations.Report(),
+		"clients":           h.Deprecations.Clients(),
+		"legacy_email_auth": h.Auth.LegacyEmailAuth(),
+	})
+}
+
+// GET NEAR LIMITS (Admin)
+func (h *Handler) getNearSettings(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Settings.NearSettings(c.Request.Context()))
+}
+
+// UPDATE NEAR LIMITS (Admin)
+func (h *Handler) updateNearSettings(c *gin.Context) {
+	var input models.NearSettings
+	if !bindJSON(c, &input) {
+		return
+	}
+	settings, err := h.Settings.UpdateNearSettings(c.Request.Context(), input, currentUser(c).Email)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Pengaturan disimpan", "data": settings})
+}
+
+// GET QUOTA SETTINGS (Admin)
+func (h *Handler) getQuotaSettings(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Settings.QuotaSettings(c.Request.Context()))
+}
+
+// UPDATE QUOTA SETTINGS (Admin)
+func (h *Handler) updateQuotaSettings(c *gin.Context) {
+	var input models.QuotaSettings
+	if !bindJSON(c, &input) {
+		return
+	}
+	if err := h.Settings.UpdateQuotaSettings(c.Request.Context(), input, currentUser(c).Email); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Kuota disimpan", "data": input})
+}
+
+// GET RANKING WEIGHTS (Admin), bobot skor relevansi pencarian
+func (h *Handler) getRankingWeights(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Settings.RankingWeights(c.Request.Context()))
+}
+
+// UPDATE RANKING WEIGHTS (Admin), semua bobot diganti sekaligus
+func (h *Handler) updateRankingWeights(c *gin.Context) {
+	var input models.RankingWeights
+	if !bindJSON(c, &input) {
+		return
+	}
+	weights, err := h.Settings.UpdateRankingWeights(c.Request.Context(), input, currentUser(c).Email)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Pengaturan disimpan", "data": weights})
+}
+
+// GET ATTRIBUTE LABELS (Admin), semua bahasa
+func (h *Handler) getAttributeLabels(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Settings.AttributeLabels(c.Request.Context()))
+}
+
+// UPDATE ATTRIBUTE LABELS (Admin)
+func (h *Handler) updateAttributeLabels(c *gin.Context) {
+	var input models.AttributeLabels
+	if !bindJSON(c, &input) {
+		return
+	}
+	labels, err := h.Settings.UpdateAttributeLabels(c.Request.Context(), input, currentUser(c).Email)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Label disimpan", "data": labels})
+}
+
+// BACKFILL ELEVATION (Admin), satu batch per request: ?batch=100
+func (h *Handler) backfillElevation(c *gin.Context) {
+	batch, _ := strconv.Atoi(c.Query("batch"))
+	result, err := h.Locations.BackfillElevation(c.Request.Context(), batch)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// UPGRADE SCHEMA (Admin), tulis ulang satu batch lokasi versi lama: ?batch=200
+func (h *Handler) upgradeSchema(c *gin.Context) {
+	batch, _ := strconv.Atoi(c.Query("batch"))
+	result, err := h.Locations.UpgradeSchema(c.Request.Context(), batch)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// REBUILD MAP VIEW (Admin), satu batch; panggil ulang dengan ?after=<next> sampai next kosong
+func (h *Handler) rebuildMapView(c *gin.Context) {
+	after := primitive.NilObjectID
+	if v := c.Query("after"); v != "" {
+		var err error
+		if after, err = primitive.ObjectIDFromHex(v); err != nil {
+			respondError(c, apperr.InvalidObjectID("after"))
+			return
+		}
+	}
+	batch, _ := strconv.Atoi(c.Query("batch"))
+	result, err := h.MapView.Rebuild(c.Request.Context(), after, batch)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// RECONCILE COUNTERS (Admin/cron), hitung ulang favorites_count dan
+// checkins_count satu batch lokasi. Panggil ulang dengan ?after=<next>
+// sampai next kosong.
+func (h *Handler) reconcileCounters(c *gin.Context) {
+	after := primitive.NilObjectID
+	if v := c.Query("after"); v != "" {
+		var err error
+		if after, err = primitive.ObjectIDFromHex(v); err != nil {
+			respondError(c, apperr.InvalidObjectID("after"))
+			return
+		}
+	}
+	batch, _ := strconv.Atoi(c.Query("batch"))
+	result, err := h.Locations.ReconcileCounters(c.Request.Context(), after, batch)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// FRESHNESS ALERTS JOB (Admin), kirim email ke pemilik lokasi yang skor
+// kesegarannya turun. Panggil ulang dengan ?after=<next> sampai next kosong.
+func (h *Handler) sendFreshnessAlerts(c *gin.Context) {
+	after := primitive.NilObjectID
+	if v := c.Query("after"); v != "" {
+		var err error
+		if after, err = primitive.ObjectIDFromHex(v); err != nil {
+			respondError(c, apperr.InvalidObjectID("after"))
+			return
+		}
+	}
+	batch, _ := strconv.Atoi(c.Query("batch"))
+	result, err := h.Alerts.FreshnessAlerts(c.Request.Context(), after, batch)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// AUDIT LOG (Admin)
+// Query: ?q (cari di actor, action, resource & summary), ?actor, ?action, ?resource_type, ?resource_id, ?from, ?to,
+// ?page, ?limit, ?cursor, ?sort (time atau -time)
+func (h *Handler) listAuditLogs(c *gin.Context) {
+	p := auditParams(c)
+	p.List = listOptions(c)
+	logs, meta, err := h.Audit.List(c.Request.Context(), p)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": logs, "meta": meta})
+}
+
+func auditParams(c *gin.Context) services.AuditParams {
+	return services.AuditParams{
+		Actor:        c.Query("actor"),
+		Action:       c.Query("action"),
+		ResourceType: c.Query("resource_type"),
+		ResourceID:   c.Query("resource_id"),
+		Q:            c.Query("q"),
+		From:         c.Query("from"),
+		To:           c.Query("to"),
+	}
+}
+
+// EXPORT AUDIT LOG (CSV)
+// Query: filter sama seperti GET /admin/audit-logs, tanpa halaman
+func (h *Handler) exportAuditLogs(c *gin.Context) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="audit-logs.csv"`)
+	c.Status(http.StatusOK)
+	err := h.Audit.Export(c.Request.Context(), auditParams(c), c.Writer)
+	if err == nil {
+		return
+	}
+	if !c.Writer.Written() {
+		// Filter tidak valid, belum ada baris yang terkirim
+		c.Writer.Header().Del("Content-Disposition")
+		respondError(c, err)
+		return
+	}
+	// Header sudah terkirim, tidak bisa lagi mengganti status
+	log.Println("export audit log:", err)
+}
+
+// AUDIT LOG ARCHIVES (Admin), manifest file arsip di object storage
+func (h *Handler) listAuditArchives(c *gin.Context) {
+	archives, err := h.Audit.Archives(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": archives})
+}
+
+// AUDIT ARCHIVE JOB (Admin), pindahkan catatan yang lewat AUDIT_RETENTION
+// ke object storage. Panggil ulang selama more bernilai true.
+// Query: ?batch (default 5000, maks 20000)
+func (h *Handler) archiveAuditLogs(c *gin.Context) {
+	batch, _ := strconv.Atoi(c.Query("batch"))
+	result, err := h.Audit.Archive(c.Request.Context(), batch, currentUser(c).Email)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// VERIFY AUDIT LOG (hash chain)
+func (h *Handler) verifyAuditLogs(c *gin.Context) {
+	result, err := h.Audit.Verify(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
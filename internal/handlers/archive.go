@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ARCHIVE LOCATIONS (Admin/cron)
+// Memindahkan satu batch lokasi tutup permanen atau yang tidak disentuh selama
+// LOCATION_ARCHIVE_AFTER ke arsip. Query: ?batch (default 200, maks 1000);
+// panggil ulang selama remaining > 0
+func (h *Handler) archiveLocations(c *gin.Context) {
+	batch, _ := strconv.Atoi(c.Query("batch"))
+	result, err := h.Locations.ArchiveStale(c.Request.Context(), batch)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// UNARCHIVE LOCATION (Admin), kembali muncul di query biasa
+func (h *Handler) unarchiveLocation(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	loc, err := h.Locations.Unarchive(c.Request.Context(), currentUser(c), objID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Data dikeluarkan dari arsip", "data": loc})
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"InfoCuy-Backend/internal/apperr"
+	"InfoCuy-Backend/internal/config"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 1. REGISTER
+func (h *Handler) register(c *gin.Context) {
+	var input models.AuthInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	newUser, err := h.Auth.Register(c.Request.Context(), input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Registrasi berhasil!", "data": newUser})
+}
+
+// 2. LOGIN
+func (h *Handler) login(c *gin.Context) {
+	var input models.AuthInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	user, pair, err := h.Auth.Login(c.Request.Context(), input, h.loginDevice(c))
+	var credErr *services.CredentialError
+	var lockedErr *services.AccountLockedError
+	if errors.As(err, &lockedErr) {
+		h.emitSecurityEvent(c, "auth.login.locked", 7, input.Email, "", "failure",
+			map[string]string{"locked_until": lockedErr.Until.UTC().Format(time.RFC3339)})
+	}
+	if errors.As(err, &credErr) {
+		h.emitSecurityEvent(c, "auth.login.failure", 5, input.Email, "", "failure", map[string]string{"reason": credErr.Reason})
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.emitSecurityEvent(c, "auth.login.success", 3, user.Email, "", "success", nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Login sukses", "user": user, "token": pair})
+}
+
+// loginDevice mengambil sidik jari perangkat dari request login. Header
+// geo hanya dibaca dari platform di TRUSTED_PLATFORM, yang menimpa nilai
+// dari client; di luar platform itu header tersebut bisa diisi siapa saja
+// (mis. untuk meniru negara korban), jadi lokasi diturunkan dari IP.
+func (h *Handler) loginDevice(c *gin.Context) models.LoginDevice {
+	dev := models.LoginDevice{UserAgent: c.Request.UserAgent(), IP: c.ClientIP()}
+	switch h.TrustedPlatform {
+	case config.PlatformVercel:
+		dev.Country = c.GetHeader("X-Vercel-IP-Country")
+		// Vercel meng-URL-encode nama kota
+		if city, err := url.QueryUnescape(c.GetHeader("X-Vercel-IP-City")); err == nil {
+			dev.City = city
+		}
+	case config.PlatformCloudflare:
+		dev.Country = c.GetHeader("CF-IPCountry")
+	}
+	return dev
+}
+
+// REPORT SESSION ("bukan saya"), body: {"token": ...} dari email peringatan
+// login. Semua sesi dicabut dan link reset password dikirim.
+func (h *Handler) reportSession(c *gin.Context) {
+	var input models.SessionReportInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	session, err := h.Auth.ReportSession(c.Request.Context(), input)
+	if errors.Is(err, services.ErrInvalidToken) {
+		respondError(c, apperr.New(http.StatusBadRequest, apperr.CodeInvalidToken, "Link tidak valid, sudah dipakai, atau kedaluwarsa"))
+		return
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.emitSecurityEvent(c, "auth.session.reported", 7, "", session.UserID.Hex(), "success",
+		map[string]string{"session_ip": session.IP, "session_device": session.Device, "session_location": session.Location})
+	c.JSON(http.StatusOK, gin.H{"message": "Semua sesi sudah dikeluarkan. Atur ulang password lewat link yang dikirim ke email Anda"})
+}
+
+// MY SESSIONS: riwayat login terbaru beserta perangkat dan lokasinya
+func (h *Handler) mySessions(c *gin.Context) {
+	sessions, err := h.Auth.Sessions(c.Request.Context(), currentUser(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": sessions})
+}
+
+// REFRESH TOKEN
+func (h *Handler) refresh(c *gin.Context) {
+	var input models.RefreshInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	pair, err := h.Auth.Refresh(c.Request.Context(), input.RefreshToken)
+	if errors.Is(err, services.ErrInvalidToken) {
+		respondError(c, apperr.New(http.StatusUnauthorized, apperr.CodeInvalidToken, "Refresh token tidak valid"))
+		return
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"token": pair})
+}
+
+// CHANGE PASSWORD
+func (h *Handler) changePassword(c *gin.Context) {
+	u := currentUser(c)
+	var input models.ChangePasswordInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	if err := h.Auth.ChangePassword(c.Request.Context(), u, input); err != nil {
+		respondError(c, err)
+		return
+	}
+	h.emitSecurityEvent(c, "auth.password.change", 5, u.Email, u.Email, "success", nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Password diubah"})
+}
+
+// REQUEST PASSWORD RESET
+func (h *Handler) requestPasswordReset(c *gin.Context) {
+	var input models.PasswordResetRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+	if err := h.Auth.RequestPasswordReset(c.Request.Context(), input.Email); err != nil {
+		respondError(c, err)
+		return
+	}
+	// Response selalu sama supaya tidak bisa dipakai menebak email terdaftar
+	c.JSON(http.StatusOK, gin.H{"message": "Jika email terdaftar, link reset password sudah dikirim"})
+}
+
+// CONFIRM PASSWORD RESET
+func (h *Handler) confirmPasswordReset(c *gin.Context) {
+	var input models.PasswordResetConfirm
+	if !bindJSON(c, &input) {
+		return
+	}
+	reset, err := h.Auth.ConfirmPasswordReset(c.Request.Context(), input)
+	if errors.Is(err, services.ErrInvalidToken) {
+		respondError(c, apperr.New(http.StatusBadRequest, apperr.CodeInvalidToken, "Token reset tidak valid atau kedaluwarsa"))
+		return
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.emitSecurityEvent(c, "auth.password.reset", 5, "", reset.UserID.Hex(), "success", nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Password berhasil direset"})
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/shadow"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GIF transparan 1x1 untuk pixel pelacak open
+var trackingPixel = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// LIST CAMPAIGNS (Admin), terbaru dulu. Query: ?page, ?limit
+func (h *Handler) listCampaigns(c *gin.Context) {
+	campaigns, meta, err := h.Campaigns.List(c.Request.Context(), listOptions(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": campaigns, "meta": meta})
+}
+
+// CREATE CAMPAIGN (Admin), disimpan sebagai draft
+func (h *Handler) createCampaign(c *gin.Context) {
+	var in models.CampaignInput
+	if !bindJSON(c, &in) {
+		return
+	}
+	campaign, err := h.Campaigns.Create(c.Request.Context(), currentUser(c), in)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Campaign dibuat", "data": campaign})
+}
+
+// GET CAMPAIGN (Admin), termasuk stats sent/bounced/opened
+func (h *Handler) getCampaign(c *gin.Context) {
+	id, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	campaign, err := h.Campaigns.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": campaign})
+}
+
+// PREVIEW CAMPAIGN (Admin): hasil render untuk satu user & jumlah penerima
+func (h *Handler) previewCampaign(c *gin.Context) {
+	id, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	preview, err := h.Campaigns.Preview(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": preview})
+}
+
+// TEST SEND CAMPAIGN (Admin). Body: {"email"}; kosong = email admin sendiri
+func (h *Handler) testSendCampaign(c *gin.Context) {
+	id, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var in models.TestSendInput
+	if c.Request.ContentLength != 0 && !bindJSON(c, &in) {
+		return
+	}
+	if err := h.Campaigns.TestSend(c.Request.Context(), currentUser(c), id, in.Email); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Email test dikirim"})
+}
+
+// SEND CAMPAIGN (Admin): kunci daftar penerima dan masukkan ke antrean.
+// Email dikirim bertahap oleh POST /admin/jobs/campaign-delivery.
+func (h *Handler) sendCampaign(c *gin.Context) {
+	id, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	campaign, err := h.Campaigns.Send(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Campaign masuk antrean", "data": campaign})
+}
+
+// DELIVER CAMPAIGNS (Admin/cron)
+// Mengirim satu batch email dari antrean campaign.
+// Query: ?batch (default 50, maks 500); panggil ulang selama remaining > 0
+func (h *Handler) deliverCampaigns(c *gin.Context) {
+	batch, _ := strconv.Atoi(c.Query("batch"))
+	result, err := h.Campaigns.Deliver(c.Request.Context(), batch)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// TRACK OPEN (publik): pixel di email campaign. Selalu mengembalikan GIF;
+// request shadow traffic tidak dihitung sebagai open.
+func (h *Handler) trackCampaignOpen(c *gin.Context) {
+	if !shadow.FromMirror(c.Request) {
+		h.Campaigns.TrackOpen(c.Request.Context(), c.Param("token"))
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "image/gif", trackingPixel)
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LIST CATEGORIES, label mengikuti Accept-Language
+func (h *Handler) listCategories(c *gin.Context) {
+	categories, err := h.Categories.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	services.LocalizeCategories(categories, language(c))
+	c.JSON(http.StatusOK, categories)
+}
+
+// GET CATEGORY
+func (h *Handler) getCategory(c *gin.Context) {
+	category, err := h.Categories.Get(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	localized := []models.Category{*category}
+	services.LocalizeCategories(localized, language(c))
+	c.JSON(http.StatusOK, localized[0])
+}
+
+// CREATE CATEGORY
+func (h *Handler) createCategory(c *gin.Context) {
+	var input models.Category
+	if !bindJSON(c, &input) {
+		return
+	}
+	category, err := h.Categories.Create(c.Request.Context(), input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Kategori ditambahkan", "data": category})
+}
+
+// UPDATE CATEGORY
+func (h *Handler) updateCategory(c *gin.Context) {
+	var input models.Category
+	if !bindJSON(c, &input) {
+		return
+	}
+	category, err := h.Categories.Update(c.Request.Context(), c.Param("slug"), input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Kategori diubah", "data": category})
+}
+
+// DELETE CATEGORY
+func (h *Handler) deleteCategory(c *gin.Context) {
+	if err := h.Categories.Delete(c.Request.Context(), c.Param("slug")); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Kategori dihapus"})
+}
+
+// LOCATIONS BY CATEGORY
+// Query sama seperti GET /locations (kecuali category)
+func (h *Handler) listLocationsByCategory(c *gin.Context) {
+	category, err := h.Categories.Get(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	locations, meta, err := h.Locations.List(c.Request.Context(), services.ListParams{
+		Category:        category.Slug,
+		CreatedBy:       c.Query("created_by"),
+		Q:               c.Query("q"),
+		List:            listOptions(c),
+		IncludeArchived: includeArchived(c),
+		Viewer:          h.optionalUser(c),
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"category": category, "data": locations, "meta": meta})
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"InfoCuy-Backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CHANGELOG (publik): lokasi yang ditambah, diubah, ditutup dan dihapus,
+// dari yang terlama. Query: ?since=YYYY-MM-DD (default 7 hari), ?after=<meta.next>, ?limit
+func (h *Handler) changelog(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	entries, meta, err := h.Changelog.List(c.Request.Context(), services.ChangelogParams{
+		Since: c.Query("since"),
+		After: c.Query("after"),
+		Limit: limit,
+		Lang:  language(c),
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": entries, "meta": meta})
+}
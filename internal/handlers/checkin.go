@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+
+	"InfoCuy-Backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CHECK-IN QR (panitia): kode yang sedang berlaku untuk dirender sebagai
+// QR di lokasi acara. Ambil ulang sebelum expires_at.
+func (h *Handler) checkInQR(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	code, err := h.CheckIns.Code(c.Request.Context(), currentUser(c), objID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{"data": code})
+}
+
+// VERIFY CHECK-IN: pengunjung mengirim kode hasil pindai QR
+func (h *Handler) verifyCheckIn(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var in models.CheckInInput
+	if !bindJSON(c, &in) {
+		return
+	}
+	checkin, err := h.CheckIns.Verify(c.Request.Context(), currentUser(c), objID, in)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Check-in berhasil", "data": checkin})
+}
+
+// ATTENDANCE (panitia): jumlah check-in total dan per hari
+func (h *Handler) checkInAttendance(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	attendance, err := h.CheckIns.Attendance(c.Request.Context(), currentUser(c), objID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": attendance})
+}
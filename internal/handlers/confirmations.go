@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CONFIRM LOCATION ("data ini masih akurat")
+// Satu user dihitung sekali per lokasi dalam 30 hari
+func (h *Handler) confirmLocation(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	loc, err := h.Locations.Confirm(c.Request.Context(), currentUser(c), objID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Terima kasih, konfirmasi tersimpan", "data": loc})
+}
+
+// STALE LOCATIONS (moderator)
+// Lokasi yang sudah lama tidak dikonfirmasi, paling basi lebih dulu. Query: ?page, ?limit
+func (h *Handler) staleLocations(c *gin.Context) {
+	locations, meta, err := h.Locations.Stale(c.Request.Context(), currentUser(c), listOptions(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": locations, "meta": meta})
+}
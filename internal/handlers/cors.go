@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"InfoCuy-Backend/internal/config"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// Header request yang boleh dikirim dan header response yang boleh dibaca
+// JavaScript frontend
+var (
+	corsAllowHeaders  = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-User-Email", "X-Request-ID"}
+	corsExposeHeaders = []string{"Deprecation", "Sunset", "Link", "Warning", "Retry-After", "X-Request-ID", "ETag"}
+)
+
+// isAdminPath menentukan kelompok CORS: /v1/admin (dan alias lama
+// /admin/security-check) memakai kebijakan admin, sisanya kebijakan publik.
+// Preflight juga dicocokkan lewat path-nya karena OPTIONS tidak punya route.
+func isAdminPath(path string) bool {
+	return strings.HasPrefix(path, "/v1/admin/") || strings.HasPrefix(path, "/admin/")
+}
+
+func corsConfig(p config.CORS) cors.Config {
+	cfg := cors.DefaultConfig()
+	cfg.AllowAllOrigins = len(p.Origins) == 0
+	cfg.AllowOrigins = p.Origins
+	cfg.AllowHeaders = corsAllowHeaders
+	cfg.ExposeHeaders = corsExposeHeaders
+	cfg.AllowCredentials = p.AllowCredentials
+	cfg.MaxAge = p.MaxAge
+	return cfg
+}
+
+type corsHandlers struct {
+	public, admin gin.HandlerFunc
+}
+
+// reloadableCORS membungkus middleware gin-contrib/cors per kelompok route
+// supaya kebijakannya bisa diganti saat config dimuat ulang tanpa membuat
+// ulang router.
+type reloadableCORS struct {
+	current atomic.Pointer[corsHandlers]
+}
+
+// newReloadableCORS panic jika kebijakan tidak valid, sama seperti cors.New.
+func newReloadableCORS(public, admin config.CORS) *reloadableCORS {
+	rc := &reloadableCORS{}
+	if err := rc.set(public, admin); err != nil {
+		panic(err)
+	}
+	return rc
+}
+
+func (rc *reloadableCORS) handle(c *gin.Context) {
+	if isEmbedPath(c.Request.URL.Path) {
+		embedCORS(c)
+		return
+	}
+	h := rc.current.Load()
+	if isAdminPath(c.Request.URL.Path) {
+		h.admin(c)
+		return
+	}
+	h.public(c)
+}
+
+// set mengganti kedua kebijakan sekaligus. Kebijakan lama tetap berlaku
+// jika salah satunya tidak valid.
+func (rc *reloadableCORS) set(public, admin config.CORS) error {
+	pub, adm := corsConfig(public), corsConfig(admin)
+	if err := pub.Validate(); err != nil {
+		return err
+	}
+	if err := adm.Validate(); err != nil {
+		return err
+	}
+	rc.current.Store(&corsHandlers{public: cors.New(pub), admin: cors.New(adm)})
+	return nil
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/apperr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Pemilihan primary baru biasanya selesai dalam 10-12 detik
+const readOnlyRetryAfter = 15 * time.Second
+
+// Write yang tetap diteruskan ke handler selama mode baca saja (path tanpa
+// /v1): service mengantrekannya di outbox, atau hanya butuh read (login,
+// refresh token, matriks jarak). Yang lain langsung dijawab 503.
+var readOnlyWrites = map[string]bool{
+	"POST /login":                    true,
+	"POST /refresh":                  true,
+	"POST /distance/matrix":          true,
+	"POST /locations/:id/favorite":   true,
+	"DELETE /locations/:id/favorite": true,
+	"PUT /me/preferences":            true,
+	"PUT /admin/database/mode":       true,
+}
+
+// Response 202 untuk write yang diantrekan di outbox
+const queuedMessage = "Database sedang dalam mode baca saja, perubahan diterima dan disimpan setelah database pulih"
+
+func databaseReadOnly(c *gin.Context) *apperr.Error {
+	retryAfter := setRetryAfter(c, readOnlyRetryAfter)
+	return apperr.New(http.StatusServiceUnavailable, apperr.CodeDatabaseReadOnly,
+		"Database sedang dalam mode baca saja, perubahan belum bisa disimpan. Coba lagi sebentar lagi").
+		With("retry_after", retryAfter)
+}
+
+// readOnlyGuard menolak write selama database tanpa primary tanpa menunggu
+// timeout driver. Read tetap dilayani dari secondary.
+func (h *Handler) readOnlyGuard(c *gin.Context) {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		c.Next()
+		return
+	}
+	if !h.Database.ReadOnly() || readOnlyWrites[c.Request.Method+" "+strings.TrimPrefix(c.FullPath(), "/v1")] {
+		c.Next()
+		return
+	}
+	respondError(c, databaseReadOnly(c))
+}
+
+// DATABASE MODE (Admin), mode baca saja dan isi outbox write di instance ini
+func (h *Handler) databaseStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.Database.Status()})
+}
+
+// SET DATABASE MODE (Admin), paksa mode baca saja untuk latihan failover
+// atau maintenance. Body: {"read_only": true|false}
+func (h *Handler) setDatabaseMode(c *gin.Context) {
+	var input struct {
+		ReadOnly *bool `json:"read_only"`
+	}
+	if !bindJSON(c, &input) {
+		return
+	}
+	if input.ReadOnly == nil {
+		respondError(c, apperr.BadRequest("read_only wajib diisi"))
+		return
+	}
+	status := h.Database.Force(c.Request.Context(), *input.ReadOnly)
+	msg := "Mode baca saja diaktifkan"
+	if !*input.ReadOnly {
+		msg = "Mode baca saja paksaan dilepas"
+	}
+	c.JSON(http.StatusOK, gin.H{"message": msg, "data": status})
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/apperr"
+	"InfoCuy-Backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kartu embed jarang berubah dan dibaca dari banyak halaman blog, jadi boleh
+// disimpan lama di browser dan CDN; ETag membuat revalidasi murah.
+const (
+	embedMaxAge = 5 * time.Minute
+	embedCDNAge = time.Hour
+	embedStale  = 24 * time.Hour
+)
+
+// isEmbedPath: data embed dan /status.json dibaca skrip di halaman pihak
+// ketiga mana pun, jadi tidak mengikuti daftar origin CORS publik.
+func isEmbedPath(path string) bool {
+	return strings.HasPrefix(path, "/v1/public/") || path == "/status.json"
+}
+
+// embedCORS mengizinkan semua origin tanpa kredensial. Preflight hanya
+// terjadi jika skrip mengirim If-None-Match sendiri.
+func embedCORS(c *gin.Context) {
+	h := c.Writer.Header()
+	h.Set("Access-Control-Allow-Origin", "*")
+	h.Set("Access-Control-Expose-Headers", "ETag, X-Request-ID")
+	h.Set("Cross-Origin-Resource-Policy", "cross-origin")
+	if c.Request.Method == http.MethodOptions {
+		h.Set("Access-Control-Allow-Methods", "GET, HEAD, OPTIONS")
+		h.Set("Access-Control-Allow-Headers", "If-None-Match")
+		h.Set("Access-Control-Max-Age", "86400")
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// EMBED LOCATION (publik): GET /v1/public/locations/:id.json
+// Hanya field publik dengan bentuk tetap (lihat models.LocationEmbed).
+// Query: ?lang=id|en untuk label kategori (bukan Accept-Language supaya
+// satu URL = satu isi di cache CDN).
+func (h *Handler) embedLocation(c *gin.Context) {
+	raw, ok := strings.CutSuffix(c.Param("file"), ".json")
+	if !ok {
+		respondError(c, apperr.New(http.StatusNotFound, apperr.CodeRouteNotFound, "Endpoint tidak ditemukan"))
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(raw)
+	if err != nil {
+		respondError(c, apperr.InvalidObjectID("id"))
+		return
+	}
+	lang := c.DefaultQuery("lang", models.DefaultLanguage)
+	if !slices.Contains(models.Languages, lang) {
+		respondError(c, apperr.BadRequest("lang harus salah satu dari: "+strings.Join(models.Languages, ", ")))
+		return
+	}
+	embed, err := h.Locations.Embed(c.Request.Context(), id, lang)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	body, err := json.Marshal(embed)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d, s-maxage=%d, stale-while-revalidate=%d",
+		int(embedMaxAge.Seconds()), int(embedCDNAge.Seconds()), int(embedStale.Seconds())))
+	c.Header("Content-Language", lang)
+	for _, match := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+		if match = strings.TrimPrefix(strings.TrimSpace(match), "W/"); match == etag || match == "*" {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/apperr"
+	"InfoCuy-Backend/internal/database"
+	"InfoCuy-Backend/internal/fieldpolicy"
+	"InfoCuy-Backend/internal/geocode"
+	"InfoCuy-Backend/internal/httpclient"
+	"InfoCuy-Backend/internal/objectstore"
+	"InfoCuy-Backend/internal/services"
+	"InfoCuy-Backend/internal/weather"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Pesan untuk services.NotFoundError per resource
+var notFoundMessages = map[string]string{
+	"location":     "Lokasi tidak ditemukan",
+	"review":       "Ulasan tidak ditemukan",
+	"photo":        "Foto tidak ditemukan",
+	"user":         "User tidak ditemukan",
+	"role":         "Role tidak ditemukan",
+	"category":     "Kategori tidak ditemukan",
+	"campaign":     "Campaign tidak ditemukan",
+	"region":       "Wilayah tidak ditemukan",
+	"postcode":     "Kode pos tidak ditemukan",
+	"address":      "Alamat tidak ditemukan di koordinat ini",
+	"reassignment": "Job pemindahan pemilik tidak ditemukan",
+	"export":       "Jadwal export tidak ditemukan",
+	"dead_letter":  "Dead letter email tidak ditemukan",
+	// Usulan kategori dari user
+	"category_suggestion": "Usulan kategori tidak ditemukan",
+	// Penanda incident di halaman status
+	"status_incident":  "Incident tidak ditemukan",
+	"import_job":       "Job import tidak ditemukan",
+	"integrity_report": "Pemeriksaan integritas belum pernah dijalankan",
+	"integrity_issue":  "Temuan integritas tidak ditemukan",
+	"transit_source":   "Sumber feed transit tidak ditemukan",
+	"event_consumer":   "Consumer event belum pernah menyimpan offset",
+}
+
+// Error service tanpa data tambahan -> error aplikasi
+var sentinelErrors = []struct {
+	err    error
+	appErr *apperr.Error
+}{
+	{services.ErrForbidden, apperr.New(http.StatusForbidden, apperr.CodeForbidden, "Akses ditolak")},
+	{services.ErrAlreadyReviewed, apperr.New(http.StatusConflict, "ALREADY_REVIEWED", "Anda sudah memberi ulasan untuk lokasi ini")},
+	{services.ErrAlreadyConfirmed, apperr.New(http.StatusConflict, "ALREADY_CONFIRMED", "Anda sudah mengonfirmasi lokasi ini dalam 30 hari terakhir")},
+	{services.ErrAlreadyCheckedIn, apperr.New(http.StatusConflict, "ALREADY_CHECKED_IN", "Anda sudah check-in di acara ini")},
+	{services.ErrInvalidCheckInCode, apperr.New(http.StatusBadRequest, "INVALID_CHECKIN_CODE", "Kode check-in tidak valid atau sudah kedaluwarsa, pindai ulang QR")},
+	{services.ErrAlreadyModerated, apperr.New(http.StatusConflict, "ALREADY_MODERATED", "Lokasi sudah dimoderasi")},
+	{services.ErrReviewModerated, apperr.New(http.StatusConflict, "ALREADY_MODERATED", "Ulasan sudah dimoderasi")},
+	{services.ErrSuggestionResolved, apperr.New(http.StatusConflict, "ALREADY_MODERATED", "Usulan kategori sudah diputuskan")},
+	{services.ErrIssueRepaired, apperr.New(http.StatusConflict, "ALREADY_REPAIRED", "Temuan sudah diperbaiki")},
+	{services.ErrLocationMerged, apperr.New(http.StatusConflict, "LOCATION_MERGED", "Lokasi sudah digabung ke lokasi lain dan tidak bisa dipulihkan")},
+	{services.ErrEmailTaken, apperr.New(http.StatusBadRequest, apperr.CodeEmailTaken, "Email sudah terdaftar!")},
+	{services.ErrRegistrationClosed, apperr.New(http.StatusForbidden, apperr.CodeRegistrationClosed, "Registrasi sedang ditutup")},
+	{services.ErrInvalidToken, apperr.New(http.StatusUnauthorized, apperr.CodeInvalidToken, "Token tidak valid atau kedaluwarsa")},
+	{services.ErrWrongPassword, apperr.New(http.StatusUnauthorized, "WRONG_PASSWORD", "Password lama salah")},
+	{services.ErrPasswordResetRequired, apperr.New(http.StatusForbidden, "PASSWORD_RESET_REQUIRED", "Password harus direset sebelum login, cek link reset di email Anda")},
+	{services.ErrNotFound, apperr.New(http.StatusNotFound, apperr.CodeNotFound, "Data tidak ditemukan")},
+}
+
+// renderErrors menulis error terakhir yang dicatat respondError sebagai
+// {"error": {"code", "message", ...}, "request_id"}. Response yang sudah
+// mulai ditulis (mis. export streaming) dibiarkan apa adanya.
+func renderErrors(c *gin.Context) {
+	c.Next()
+	if len(c.Errors) == 0 || c.Writer.Written() {
+		return
+	}
+	writeError(c, toAppError(c, c.Errors.Last().Err))
+}
+
+func writeError(c *gin.Context, e *apperr.Error) {
+	if e.Cause != nil {
+		level := slog.LevelWarn
+		if e.Status >= http.StatusInternalServerError && e.Status != http.StatusBadGateway && e.Status != http.StatusServiceUnavailable {
+			level = slog.LevelError
+		}
+		slog.Log(c.Request.Context(), level, "request gagal", "method", c.Request.Method, "path", c.FullPath(),
+			"code", e.Code, "error", e.Cause)
+	}
+	c.AbortWithStatusJSON(e.Status, gin.H{"error": e.Body(), "request_id": c.GetString("request_id")})
+}
+
+// toAppError memetakan error dari service ke error aplikasi. Error yang
+// tidak dikenal menjadi 500 tanpa membocorkan pesan aslinya.
+func toAppError(c *gin.Context, err error) *apperr.Error {
+	var appErr *apperr.Error
+	var validationErr *services.ValidationError
+	var notFoundErr *services.NotFoundError
+	var policyErr *fieldpolicy.ForbiddenError
+	var quotaErr *services.QuotaExceededError
+	var lockedErr *services.AccountLockedError
+	var credErr *services.CredentialError
+	switch {
+	case errors.As(err, &appErr):
+		return appErr
+	case errors.As(err, &validationErr):
+		e := apperr.BadRequest(validationErr.Message)
+		if len(validationErr.Fields) > 0 {
+			lang := language(c)
+			fields := make([]services.FieldError, len(validationErr.Fields))
+			for i, f := range validationErr.Fields {
+				fields[i] = f.Localize(lang)
+			}
+			e = e.With("fields", fields)
+		}
+		return e
+	case errors.As(err, &notFoundErr):
+		msg, ok := notFoundMessages[notFoundErr.Resource]
+		if !ok {
+			msg = "Data tidak ditemukan"
+		}
+		return apperr.NotFound(strings.ToUpper(notFoundErr.Resource), msg)
+	case errors.As(err, &policyErr):
+		return apperr.New(http.StatusForbidden, apperr.CodeFieldForbidden, policyErr.Error())
+	case errors.As(err, &quotaErr):
+		return apperr.New(http.StatusForbidden, apperr.CodeQuotaExceeded, quotaErr.Error()).With("quota", quotaErr.Usage)
+	case errors.As(err, &lockedErr):
+		retryAfter := setRetryAfter(c, time.Until(lockedErr.Until))
+		return apperr.New(http.StatusTooManyRequests, apperr.CodeAccountLocked,
+			"Terlalu banyak percobaan login gagal, akun dikunci sementara").With("retry_after", retryAfter)
+	case errors.As(err, &credErr):
+		return apperr.New(http.StatusUnauthorized, apperr.CodeInvalidCredentials, "Email atau Password salah")
+	case errors.Is(err, httpclient.ErrCircuitOpen):
+		c.Header("Retry-After", "30")
+		return apperr.New(http.StatusServiceUnavailable, apperr.CodeUpstreamFailed,
+			"Layanan eksternal sedang tidak tersedia, coba lagi nanti").Wrap(err)
+	case errors.Is(err, weather.ErrRateLimited):
+		c.Header("Retry-After", "60")
+		return apperr.New(http.StatusTooManyRequests, apperr.CodeRateLimited, "Terlalu banyak permintaan cuaca, coba lagi nanti").
+			With("retry_after", 60)
+	case errors.Is(err, geocode.ErrRateLimited):
+		c.Header("Retry-After", "60")
+		return apperr.New(http.StatusTooManyRequests, apperr.CodeRateLimited, "Terlalu banyak permintaan alamat, coba lagi nanti").
+			With("retry_after", 60)
+	case errors.Is(err, objectstore.ErrUpstream):
+		return apperr.New(http.StatusBadGateway, apperr.CodeUpstreamFailed, "Gagal menyimpan foto, coba lagi nanti").Wrap(err)
+	case errors.Is(err, services.ErrMailFailed):
+		return apperr.New(http.StatusBadGateway, apperr.CodeUpstreamFailed, "Provider email gagal mengirim, coba lagi nanti").Wrap(err)
+	case database.IsUnavailable(err):
+		return databaseReadOnly(c).Wrap(err)
+	case errors.Is(err, services.ErrStreamFull):
+		c.Header("Retry-After", "30")
+		return apperr.New(http.StatusServiceUnavailable, apperr.CodeUnavailable, "Server sedang penuh, coba lagi nanti")
+	}
+	for _, s := range sentinelErrors {
+		if errors.Is(err, s.err) {
+			return s.appErr
+		}
+	}
+	return apperr.Internal(err)
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"InfoCuy-Backend/internal/apperr"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LIST EVENTS, log domain event untuk consumer internal
+// Query: ?after_seq (default offset ?consumer, atau 0), ?consumer, ?limit (default 100, maks 1000)
+func (h *Handler) listEvents(c *gin.Context) {
+	p := services.EventParams{Consumer: c.Query("consumer")}
+	if v := c.Query("after_seq"); v != "" {
+		seq, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			respondError(c, apperr.BadRequest("after_seq harus berupa angka"))
+			return
+		}
+		p.AfterSeq = &seq
+	}
+	p.Limit, _ = strconv.Atoi(c.Query("limit"))
+	page, err := h.EventLog.List(c.Request.Context(), p)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": page.Events, "next_seq": page.NextSeq, "more": page.More})
+}
+
+// GET EVENT CONSUMER OFFSET
+func (h *Handler) getEventOffset(c *gin.Context) {
+	offset, err := h.EventLog.Offset(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": offset})
+}
+
+// PUT EVENT CONSUMER OFFSET, simpan seq terakhir yang sudah diproses
+func (h *Handler) putEventOffset(c *gin.Context) {
+	var in models.EventOffsetInput
+	if !bindJSON(c, &in) {
+		return
+	}
+	offset, err := h.EventLog.SetOffset(c.Request.Context(), currentUser(c), c.Param("name"), in)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Offset disimpan", "data": offset})
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"InfoCuy-Backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LIST SCHEDULED EXPORTS (Admin), terbaru dulu. Query: ?page, ?limit
+func (h *Handler) listScheduledExports(c *gin.Context) {
+	exports, meta, err := h.Exports.List(c.Request.Context(), listOptions(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": exports, "meta": meta})
+}
+
+// CREATE SCHEDULED EXPORT (Admin)
+// Body: {name, format, filter, interval, delivery: {type, email|url,secret|prefix}, enabled, starts_at}
+func (h *Handler) createScheduledExport(c *gin.Context) {
+	var in models.ScheduledExportInput
+	if !bindJSON(c, &in) {
+		return
+	}
+	export, err := h.Exports.Create(c.Request.Context(), currentUser(c), in)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Jadwal export dibuat", "data": export})
+}
+
+// GET SCHEDULED EXPORT (Admin), termasuk hasil terakhir
+func (h *Handler) getScheduledExport(c *gin.Context) {
+	id, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	export, err := h.Exports.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": export})
+}
+
+// UPDATE SCHEDULED EXPORT (Admin). Body sama dengan create; secret webhook
+// yang kosong mempertahankan secret lama.
+func (h *Handler) updateScheduledExport(c *gin.Context) {
+	id, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var in models.ScheduledExportInput
+	if !bindJSON(c, &in) {
+		return
+	}
+	export, err := h.Exports.Update(c.Request.Context(), id, in)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Jadwal export diperbarui", "data": export})
+}
+
+// DELETE SCHEDULED EXPORT (Admin)
+func (h *Handler) deleteScheduledExport(c *gin.Context) {
+	id, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.Exports.Delete(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Jadwal export dihapus"})
+}
+
+// RUN SCHEDULED EXPORT (Admin): jalankan sekarang tanpa menggeser jadwal.
+// Pengiriman yang gagal tetap 200 dengan status failed di data.
+func (h *Handler) runScheduledExport(c *gin.Context) {
+	id, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	run, err := h.Exports.RunNow(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": run})
+}
+
+// RUN DUE EXPORTS (Admin/cron)
+// Menjalankan satu batch jadwal export yang jatuh tempo.
+// Query: ?batch (default 5, maks 20); panggil ulang selama remaining > 0
+func (h *Handler) runScheduledExports(c *gin.Context) {
+	batch, _ := strconv.Atoi(c.Query("batch"))
+	result, err := h.Exports.RunDue(c.Request.Context(), batch)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
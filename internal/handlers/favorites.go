@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ADD FAVORITE, 201 jika baru disimpan, 200 jika sudah ada di favorit
+func (h *Handler) addFavorite(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	status, created, err := h.Favorites.Add(c.Request.Context(), currentUser(c), objID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if status.Queued {
+		c.JSON(http.StatusAccepted, gin.H{"message": queuedMessage, "data": status})
+		return
+	}
+	code := http.StatusOK
+	if created {
+		code = http.StatusCreated
+	}
+	c.JSON(code, gin.H{"message": "Lokasi disimpan ke favorit", "data": status})
+}
+
+// REMOVE FAVORITE, tetap 200 jika lokasi memang tidak ada di favorit
+func (h *Handler) removeFavorite(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	status, err := h.Favorites.Remove(c.Request.Context(), currentUser(c), objID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if status.Queued {
+		c.JSON(http.StatusAccepted, gin.H{"message": queuedMessage, "data": status})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Lokasi dihapus dari favorit", "data": status})
+}
+
+// MY FAVORITES, lokasi lengkap yang terakhir disimpan lebih dulu. Query: ?page, ?limit
+func (h *Handler) listFavorites(c *gin.Context) {
+	locations, meta, err := h.Favorites.List(c.Request.Context(), currentUser(c), listOptions(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": locations, "meta": meta})
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FEATURED LOCATIONS: carousel beranda sesuai urutan admin, hanya slot yang
+// sedang tayang. Di-cache services.FeaturedTTL
+func (h *Handler) featuredLocations(c *gin.Context) {
+	items, err := h.Featured.Public(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(services.FeaturedTTL.Seconds())))
+	c.JSON(http.StatusOK, gin.H{"data": items})
+}
+
+// PUBLIC CONFIG: pengaturan yang dibaca frontend saat dimuat, termasuk
+// carousel beranda
+func (h *Handler) publicConfig(c *gin.Context) {
+	featured, err := h.Featured.Public(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(services.FeaturedTTL.Seconds())))
+	c.JSON(http.StatusOK, gin.H{"data": models.PublicConfig{
+		RegistrationOpen: h.Auth.RegistrationOpen(),
+		Featured:         featured,
+	}})
+}
+
+// GET FEATURED (Admin): semua slot carousel termasuk yang terjadwal atau
+// sudah lewat
+func (h *Handler) getFeatured(c *gin.Context) {
+	entries, err := h.Featured.Admin(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": entries})
+}
+
+// UPDATE FEATURED (Admin): mengganti seluruh isi carousel; urutan slots
+// adalah urutan tampil. Body: {"slots": [{"location_id", "caption",
+// "starts_at", "ends_at"}]}
+func (h *Handler) updateFeatured(c *gin.Context) {
+	var input models.FeaturedSettings
+	if !bindJSON(c, &input) {
+		return
+	}
+	entries, err := h.Featured.Update(c.Request.Context(), input.Slots, currentUser(c).Email)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Carousel beranda disimpan", "data": entries})
+}
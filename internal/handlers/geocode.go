@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"InfoCuy-Backend/internal/apperr"
+	"InfoCuy-Backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// REVERSE GEOCODE, ?lat=..&lng=.. -> alamat terdekat
+func (h *Handler) reverseGeocode(c *gin.Context) {
+	lat, errLat := strconv.ParseFloat(c.Query("lat"), 64)
+	lng, errLng := strconv.ParseFloat(c.Query("lng"), 64)
+	if errLat != nil || errLng != nil {
+		respondError(c, apperr.BadRequest("lat dan lng wajib diisi dengan koordinat yang valid"))
+		return
+	}
+	place, err := h.Geocode.Reverse(c.Request.Context(), models.Coordinates{Lat: lat, Lng: lng})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": place})
+}
+
+// SEARCH GEOCODE, ?q=..&limit=.. untuk autocomplete alamat
+func (h *Handler) searchGeocode(c *gin.Context) {
+	var limit int
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			respondError(c, apperr.BadRequest("limit harus berupa angka"))
+			return
+		}
+		limit = n
+	}
+	places, err := h.Geocode.Search(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": places})
+}
@@ -0,0 +1,203 @@
+// Package handlers berisi HTTP handler Gin. Handler hanya mengurus
+// binding request & format response; logika ada di package services.
+package handlers
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/apperr"
+	"InfoCuy-Backend/internal/config"
+	"InfoCuy-Backend/internal/deprecation"
+	"InfoCuy-Backend/internal/doctor"
+	"InfoCuy-Backend/internal/jobs"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/ratelimit"
+	"InfoCuy-Backend/internal/services"
+	"InfoCuy-Backend/internal/shadow"
+	"InfoCuy-Backend/internal/siem"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Handler menampung semua dependency yang dibutuhkan route.
+type Handler struct {
+	Auth        *services.AuthService
+	Users       *services.UserService
+	Roles       *services.RoleService
+	Locations   *services.LocationService
+	MapView     *services.MapViewService
+	Promotions  *services.PromotionService
+	Featured    *services.FeaturedService
+	Stream      *services.LocationStream
+	Reviews     *services.ReviewService
+	Favorites   *services.FavoriteService
+	Categories  *services.CategoryService
+	Suggestions *services.CategorySuggestionService
+	// POI OSM yang belum ada di data (GET /suggestions/missing)
+	MissingPOIs *services.MissingPOIService
+	Regions     *services.RegionService
+	Postcodes   *services.PostcodeService
+	Geocode     *services.GeocodeService
+	Settings    *services.SettingsService
+	Transit     *services.TransitService
+	Security    *services.SecurityService
+	Audit       *services.AuditService
+	Campaigns   *services.CampaignService
+	Exports     *services.ScheduledExportService
+	Mail        *services.MailOutbox
+	Reassign    *services.ReassignService
+	Integrity   *services.IntegrityService
+	Changelog   *services.ChangelogService
+	CheckIns    *services.CheckInService
+	Stats       *services.StatsService
+	Alerts      *services.AlertService
+	Health      *services.HealthService
+	// Mode baca saja selama MongoDB tanpa primary
+	Database     *services.DatabaseMode
+	Status       *services.StatusService
+	Doctor       *doctor.Doctor
+	Deprecations *deprecation.Tracker
+	Events       *siem.Exporter
+	// Mencerminkan sampel traffic GET ke service bayangan (nil = nonaktif)
+	Shadow *shadow.Mirror
+	// Batas request per IP (nil = tanpa batas). AuthRateLimit khusus
+	// endpoint login, register dan reset password.
+	RateLimit     *ratelimit.Limiter
+	AuthRateLimit *ratelimit.Limiter
+	// Prioritas & batas pekerjaan latar belakang (nil = tanpa batas)
+	Jobs *jobs.Queue
+	// Statistik pemakaian API per consumer (GET /admin/usage)
+	Usage *services.UsageService
+	// Log domain event untuk consumer internal (GET /events)
+	EventLog *services.EventLog
+	// Memuat ulang config runtime (POST /admin/config/reload); nil = tidak didukung
+	ReloadConfig func() (config.Runtime, []string, error)
+	// Fitur dan batas yang berlaku untuk GET /admin/runtime-info; nil = tidak tersedia
+	RuntimeInfo func() RuntimeInfo
+	// Bearer token untuk GET /metrics; kosong berarti terbuka untuk siapa
+	// saja (security check memperingatkan)
+	MetricsToken string
+	// Proxy dan platform (config.Platform*) yang header IP-nya dipercaya
+	// untuk c.ClientIP; kosong berarti IP diambil dari koneksi langsung
+	TrustedProxies  []string
+	TrustedPlatform string
+
+	// Diisi Router
+	cors *reloadableCORS
+}
+
+// SetCORS mengganti kebijakan CORS route publik dan /v1/admin. Hanya
+// berlaku setelah Router dipanggil.
+func (h *Handler) SetCORS(public, admin config.CORS) error {
+	return h.cors.set(public, admin)
+}
+
+// User yang sedang login (zero value jika tidak ada)
+func currentUser(c *gin.Context) models.User {
+	if v, ok := c.Get("user"); ok {
+		return v.(models.User)
+	}
+	return models.User{}
+}
+
+// optionalUser mengembalikan user dari bearer token untuk route publik
+// (zero value jika tidak ada token atau token tidak valid)
+func (h *Handler) optionalUser(c *gin.Context) models.User {
+	if bearerToken(c) != "" || c.GetHeader(legacyEmailHeader) != "" {
+		if u, err := h.authenticate(c); err == nil {
+			return *u
+		}
+	}
+	return models.User{}
+}
+
+// authenticate memakai bearer token. Selama masa transisi JWT
+// (LEGACY_EMAIL_AUTH) request tanpa bearer token masih boleh memakai
+// header X-User-Email.
+func (h *Handler) authenticate(c *gin.Context) (*models.User, error) {
+	token := bearerToken(c)
+	if token == "" && h.Auth.LegacyEmailAuth() {
+		return h.Auth.AuthenticateEmail(c.Request.Context(), c.GetHeader(legacyEmailHeader))
+	}
+	return h.Auth.Authenticate(c.Request.Context(), token)
+}
+
+// Ambil bearer token dari header Authorization
+func bearerToken(c *gin.Context) string {
+	h := c.GetHeader("Authorization")
+	if len(h) > 7 && strings.EqualFold(h[:7], "Bearer ") {
+		return strings.TrimSpace(h[7:])
+	}
+	return ""
+}
+
+// bindJSON membaca body JSON ke obj. Jika gagal, response 400 dengan format
+// yang sama seperti error validasi sudah dikirim dan hasilnya false.
+func bindJSON(c *gin.Context, obj interface{}) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		respondError(c, services.DecodeError(err))
+		return false
+	}
+	return true
+}
+
+// respondError menyerahkan err ke middleware renderErrors, yang memetakannya
+// ke status HTTP dan kode error. Handler langsung return setelahnya.
+func respondError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// objectIDParam membaca parameter path name sebagai ObjectID. Jika tidak
+// valid, response 400 INVALID_OBJECT_ID sudah disiapkan dan hasilnya false.
+func objectIDParam(c *gin.Context, name string) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param(name))
+	if err != nil {
+		respondError(c, apperr.InvalidObjectID(name))
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+// listOptions membaca ?page, ?limit, ?sort dan ?cursor endpoint daftar.
+// Default, batas dan whitelist sort diterapkan service per resource.
+func listOptions(c *gin.Context) models.ListOptions {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	return models.ListOptions{Page: page, Limit: limit, Sort: c.Query("sort"), Cursor: c.Query("cursor")}
+}
+
+// setRetryAfter mengisi header Retry-After (dalam detik, dibulatkan ke atas,
+// minimal 1) dan mengembalikan nilainya.
+func setRetryAfter(c *gin.Context, wait time.Duration) int {
+	secs := int(math.Ceil(wait.Seconds()))
+	if secs < 1 {
+		secs = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(secs))
+	return secs
+}
+
+// Tambahkan ignored_fields ke response jika ada field yang dibuang
+func withIgnored(resp gin.H, ignored []string) gin.H {
+	if len(ignored) > 0 {
+		resp["ignored_fields"] = ignored
+	}
+	return resp
+}
+
+func (h *Handler) emitSecurityEvent(c *gin.Context, eventType string, severity int, actor, target, outcome string, details map[string]string) {
+	h.Events.Emit(siem.Event{
+		Type:     eventType,
+		Severity: severity,
+		Actor:    actor,
+		Target:   target,
+		SourceIP: c.ClientIP(),
+		Outcome:  outcome,
+		Details:  details,
+	})
+}
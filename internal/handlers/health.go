@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"InfoCuy-Backend/internal/apperr"
+	"InfoCuy-Backend/internal/buildinfo"
+	"InfoCuy-Backend/internal/config"
+	"InfoCuy-Backend/internal/jobs"
+	"InfoCuy-Backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LIVENESS, proses hidup dan bisa melayani HTTP (tanpa cek dependency)
+func (h *Handler) healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":   "ok",
+		"uptime_s": int64(h.Health.Uptime().Seconds()),
+		"time":     time.Now().UTC(),
+	})
+}
+
+// READINESS, 503 jika MongoDB tidak bisa dijangkau
+func (h *Handler) readyz(c *gin.Context) {
+	ready, checks := h.Health.Ready(c.Request.Context())
+	status, code := "ready", http.StatusOK
+	if !ready {
+		status, code = "not_ready", http.StatusServiceUnavailable
+	}
+	c.JSON(code, gin.H{"status": status, "checks": checks, "time": time.Now().UTC()})
+}
+
+// VERSION, commit & waktu build
+func (h *Handler) version(c *gin.Context) {
+	c.JSON(http.StatusOK, buildinfo.Get())
+}
+
+// RuntimeInfo adalah fitur opsional yang aktif dan batas yang berlaku di
+// instance ini.
+type RuntimeInfo struct {
+	Features map[string]bool `json:"features"`
+	Limits   RuntimeLimits   `json:"limits"`
+	// Pekerjaan latar belakang yang sedang berjalan dan mengantre per jenis
+	Jobs map[jobs.Class]jobs.Stats `json:"jobs"`
+}
+
+// RuntimeLimits berisi config runtime yang sedang berlaku ditambah batas
+// yang hanya berubah setelah restart. Durasi ditulis seperti "15m0s".
+type RuntimeLimits struct {
+	config.Runtime
+	LoginMaxFailures     int    `json:"login_max_failures"`
+	LoginLockout         string `json:"login_lockout"`
+	AccessTokenTTL       string `json:"access_token_ttl"`
+	RefreshTokenTTL      string `json:"refresh_token_ttl"`
+	StreamMaxClients     int    `json:"stream_max_clients"`
+	MongoMaxPoolSize     uint64 `json:"mongo_max_pool_size"`
+	MongoOpTimeout       string `json:"mongo_op_timeout"`
+	TrashRetention       string `json:"trash_retention"`
+	ArchiveAfter         string `json:"archive_after"`
+	FreshnessHalfLife    string `json:"freshness_half_life"`
+	CampaignSendInterval string `json:"campaign_send_interval"`
+	FieldPolicyMode      string `json:"field_policy_mode"`
+	// Kapasitas antrean setelah default diterapkan
+	Jobs jobs.Limits `json:"jobs"`
+}
+
+// RUNTIME INFO (Admin), gambaran lengkap deployment untuk support: build,
+// fitur, batas dan kesehatan dependency
+func (h *Handler) runtimeInfo(c *gin.Context) {
+	if h.RuntimeInfo == nil {
+		respondError(c, apperr.New(http.StatusNotImplemented, "RUNTIME_INFO_UNSUPPORTED", "Runtime info tidak tersedia di deployment ini"))
+		return
+	}
+	info := h.RuntimeInfo()
+	ready, checks := h.Health.Ready(c.Request.Context())
+	c.JSON(http.StatusOK, gin.H{"data": struct {
+		Build        buildinfo.Info             `json:"build"`
+		StartedAt    time.Time                  `json:"started_at"`
+		UptimeS      int64                      `json:"uptime_s"`
+		Ready        bool                       `json:"ready"`
+		Dependencies []services.DependencyCheck `json:"dependencies"`
+		RuntimeInfo
+	}{
+		Build:        buildinfo.Get(),
+		StartedAt:    h.Health.StartedAt(),
+		UptimeS:      int64(h.Health.Uptime().Seconds()),
+		Ready:        ready,
+		Dependencies: checks,
+		RuntimeInfo:  info,
+	}})
+}
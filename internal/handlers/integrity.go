@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+
+	"InfoCuy-Backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET INTEGRITY REPORT (Admin), hasil pemeriksaan integritas terakhir
+func (h *Handler) getIntegrityReport(c *gin.Context) {
+	report, err := h.Integrity.Report(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// RUN INTEGRITY CHECK (Admin), cari referensi yatim dan simpan laporannya.
+// Dipanggil berkala oleh cron; foto diperiksa bergiliran per panggilan.
+func (h *Handler) runIntegrityCheck(c *gin.Context) {
+	report, err := h.Integrity.Check(c.Request.Context(), currentUser(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// REPAIR INTEGRITY ISSUE (Admin), jalankan aksi perbaikan satu temuan
+func (h *Handler) repairIntegrityIssue(c *gin.Context) {
+	var in models.IntegrityRepairInput
+	if !bindJSON(c, &in) {
+		return
+	}
+	issue, err := h.Integrity.Repair(c.Request.Context(), currentUser(c), in)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Temuan diperbaiki", "data": issue})
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"InfoCuy-Backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// negotiateLanguage memilih bahasa yang didukung dengan q tertinggi dari
+// header Accept-Language. Subtag wilayah diabaikan, jadi "en-US" = "en".
+func negotiateLanguage(header string) string {
+	best, bestQ := models.DefaultLanguage, 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if q > bestQ && slices.Contains(models.Languages, base) {
+			best, bestQ = base, q
+		}
+	}
+	return best
+}
+
+// language membaca Accept-Language dan menandai response supaya cache
+// menyimpan versi per bahasa.
+func language(c *gin.Context) string {
+	lang := negotiateLanguage(c.GetHeader("Accept-Language"))
+	c.Header("Content-Language", lang)
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	return lang
+}
@@ -0,0 +1,481 @@
+package handlers
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"InfoCuy-Backend/internal/apperr"
+	"InfoCuy-Backend/internal/geoio"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/services"
+	"InfoCuy-Backend/internal/shadow"
+	"InfoCuy-Backend/internal/units"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// 3. GET LOCATIONS, dari read model map_view (id, nama, kategori, koordinat, rating, status)
+// Query: ?page, ?limit, ?cursor, ?category, ?created_by, ?q (nama/alamat), ?sort (mis. -created_at),
+// ?accessible (mis. wheelchair atau wheelchair,toilet), ?price_range (mis. budget,moderate),
+// ?payment (mis. qris; semua metode wajib diterima), ?include_archived=true.
+// Dengan ?q, default sort relevance: ?lat=&lng= untuk faktor jarak dan
+// ?explain=true untuk rincian skor per lokasi
+func (h *Handler) listLocations(c *gin.Context) {
+	near, ok := referencePoint(c)
+	if !ok {
+		return
+	}
+	locations, meta, err := h.MapView.List(c.Request.Context(), services.ListParams{
+		Category:        c.Query("category"),
+		CreatedBy:       c.Query("created_by"),
+		Q:               c.Query("q"),
+		Attributes:      attributeParams(c),
+		List:            listOptions(c),
+		IncludeArchived: includeArchived(c),
+		Near:            near,
+		Explain:         c.Query("explain") == "true",
+		// Request shadow traffic tidak menambah impression promosi
+		SkipImpressions: shadow.FromMirror(c.Request),
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": locations, "meta": meta})
+}
+
+// attributeParams membaca filter atribut yang dipakai list, nearby, export dan facets
+func attributeParams(c *gin.Context) services.AttributeParams {
+	return services.AttributeParams{
+		Accessible: c.Query("accessible"),
+		PriceRange: c.Query("price_range"),
+		Payment:    c.Query("payment"),
+	}
+}
+
+// referencePoint membaca ?lat=&lng= opsional sebagai titik referensi jarak;
+// nil jika keduanya kosong. Jika tidak valid, response 400 sudah disiapkan.
+func referencePoint(c *gin.Context) (*models.Coordinates, bool) {
+	if c.Query("lat") == "" && c.Query("lng") == "" {
+		return nil, true
+	}
+	lat, errLat := strconv.ParseFloat(c.Query("lat"), 64)
+	lng, errLng := strconv.ParseFloat(c.Query("lng"), 64)
+	if errLat != nil || errLng != nil {
+		respondError(c, apperr.BadRequest("lat dan lng harus diisi bersama dengan koordinat yang valid"))
+		return nil, false
+	}
+	return &models.Coordinates{Lat: lat, Lng: lng}, true
+}
+
+// ?include_archived=true ikut menampilkan lokasi di arsip
+func includeArchived(c *gin.Context) bool {
+	return c.Query("include_archived") == "true"
+}
+
+// LOCATION FACETS
+// Jumlah lokasi per kategori, price_range dan payment_methods; filter sama seperti export.
+// Label tiap nilai mengikuti Accept-Language.
+func (h *Handler) locationFacets(c *gin.Context) {
+	ctx := c.Request.Context()
+	facets, err := h.Locations.Facets(ctx, services.ExportParams{
+		Category:   c.Query("category"),
+		CreatedBy:  c.Query("created_by"),
+		Q:          c.Query("q"),
+		Attributes: attributeParams(c),
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	categories, err := h.Categories.List(ctx)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	services.LocalizeFacets(&facets, categories, h.Settings.AttributeLabels(ctx), language(c))
+	c.JSON(http.StatusOK, gin.H{"data": facets})
+}
+
+// LOCATION DETAIL, termasuk ringkasan rating; lokasi di arsip butuh ?include_archived=true.
+// Lokasi yang belum disetujui hanya terlihat oleh pembuatnya dan moderator.
+// ID lama lokasi yang sudah digabung tetap 200 dengan data lokasi tujuan,
+// field "redirect" (status 301) dan header Location ke ID barunya.
+func (h *Handler) getLocation(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	loc, redirect, err := h.Locations.Resolve(c.Request.Context(), h.optionalUser(c), objID, includeArchived(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if redirect != nil {
+		c.Header("Location", "/v1/locations/"+redirect.To.Hex())
+		c.JSON(http.StatusOK, gin.H{"data": loc, "redirect": redirect})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": loc})
+}
+
+// 4. ADD LOCATION
+// Query: ?snap=true untuk menggeser koordinat ke jalan/bangunan terdekat
+func (h *Handler) createLocation(c *gin.Context) {
+	var payload map[string]interface{}
+	if !bindJSON(c, &payload) {
+		return
+	}
+	newLocation, ignored, err := h.Locations.Create(c.Request.Context(), currentUser(c), payload, c.Query("snap") == "true")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	message := "Lokasi ditambahkan!"
+	if newLocation.ModerationStatus == models.ModerationPending {
+		message = "Lokasi dikirim dan menunggu review moderator"
+	}
+	c.JSON(http.StatusCreated, withIgnored(gin.H{"message": message, "data": newLocation}, ignored))
+}
+
+// 5. EDIT LOCATION
+func (h *Handler) updateLocation(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var payload map[string]interface{}
+	if !bindJSON(c, &payload) {
+		return
+	}
+	ignored, err := h.Locations.Update(c.Request.Context(), currentUser(c), objID, payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, withIgnored(gin.H{"message": "Data diupdate"}, ignored))
+}
+
+// 6. DELETE LOCATION
+func (h *Handler) deleteLocation(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	requestor := currentUser(c)
+	deleted, err := h.Locations.Delete(c.Request.Context(), requestor, objID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.emitSecurityEvent(c, "location.delete", 4, requestor.Email, "", "success",
+		map[string]string{"location_id": objID.Hex(), "name": deleted.Name})
+	c.JSON(http.StatusOK, gin.H{"message": "Data dipindahkan ke trash"})
+}
+
+// UPLOAD PHOTO, multipart: file=gambar (JPEG/PNG/WebP/GIF, maks 5MB)
+func (h *Handler) uploadPhoto(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	// Sisakan ruang untuk header multipart
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxPhotoBytes+1<<20)
+	fh, err := c.FormFile("file")
+	if err != nil || fh.Size > services.MaxPhotoBytes {
+		respondError(c, apperr.BadRequest("Foto wajib diupload (field: file, maks 5MB)"))
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	photo, err := h.Locations.AddPhoto(c.Request.Context(), currentUser(c), objID, data)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Foto ditambahkan!", "data": photo})
+}
+
+// DELETE PHOTO, pembuat lokasi atau role dengan locations:update_any
+func (h *Handler) deletePhoto(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	photoID, ok := objectIDParam(c, "photoId")
+	if !ok {
+		return
+	}
+	if err := h.Locations.DeletePhoto(c.Request.Context(), currentUser(c), objID, photoID); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Foto dihapus"})
+}
+
+// LOCATION WEATHER, cuaca terkini untuk halaman detail
+func (h *Handler) locationWeather(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	conditions, err := h.Locations.Weather(c.Request.Context(), objID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": conditions})
+}
+
+// NEARBY LOCATIONS
+func (h *Handler) nearbyLocations(c *gin.Context) {
+	lat, errLat := strconv.ParseFloat(c.Query("lat"), 64)
+	lng, errLng := strconv.ParseFloat(c.Query("lng"), 64)
+	if errLat != nil || errLng != nil {
+		respondError(c, apperr.BadRequest("lat dan lng wajib diisi dengan koordinat yang valid"))
+		return
+	}
+	var radius float64
+	if r := c.Query("radius"); r != "" {
+		v, err := strconv.ParseFloat(r, 64)
+		if err != nil || v <= 0 {
+			respondError(c, apperr.BadRequest("radius harus angka positif (meter)"))
+			return
+		}
+		radius = v
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	results, radius, err := h.Locations.Nearby(c.Request.Context(), services.NearbyParams{
+		Lat:        lat,
+		Lng:        lng,
+		RadiusM:    radius,
+		Category:   c.Query("category"),
+		Attributes: attributeParams(c),
+		Limit:      limit,
+		Sort:       c.Query("sort"),
+		Viewer:     h.optionalUser(c),
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	sys := h.resolveUnits(c)
+	for i := range results {
+		results[i].Distance = units.FromMeters(results[i].DistanceM, sys)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":   results,
+		"count":  len(results),
+		"radius": units.FromMeters(radius, sys),
+		"units":  sys,
+	})
+}
+
+// DISTANCE MATRIX, body: {"origins": [...], "destinations": [...]}; setiap
+// titik berisi location_id atau coordinates
+func (h *Handler) distanceMatrix(c *gin.Context) {
+	var input models.DistanceMatrixInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	matrix, err := h.Locations.DistanceMatrix(c.Request.Context(), h.optionalUser(c), input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	sys := h.resolveUnits(c)
+	matrix.Distances = make([][]units.Distance, len(matrix.DistancesM))
+	for i, row := range matrix.DistancesM {
+		matrix.Distances[i] = make([]units.Distance, len(row))
+		for j, m := range row {
+			matrix.Distances[i][j] = units.FromMeters(m, sys)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": matrix, "units": sys})
+}
+
+// COMPARE LOCATIONS, 2-4 lokasi berdampingan untuk tampilan perbandingan
+// Query: ?ids=a,b,c, ?lat=&lng= opsional sebagai titik referensi jarak, ?units=
+func (h *Handler) compareLocations(c *gin.Context) {
+	var ids []primitive.ObjectID
+	for _, raw := range strings.Split(c.Query("ids"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		id, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			respondError(c, apperr.InvalidObjectID("ids"))
+			return
+		}
+		ids = append(ids, id)
+	}
+	ref, ok := referencePoint(c)
+	if !ok {
+		return
+	}
+	comparison, err := h.Locations.Compare(c.Request.Context(), h.optionalUser(c), ids, ref)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	sys := h.resolveUnits(c)
+	for i, l := range comparison.Locations {
+		if l.DistanceM != nil {
+			d := units.FromMeters(*l.DistanceM, sys)
+			comparison.Locations[i].Distance = &d
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": comparison, "units": sys})
+}
+
+// Satuan jarak untuk response: ?units= > preferensi user > metric
+func (h *Handler) resolveUnits(c *gin.Context) units.System {
+	if sys, ok := units.Parse(c.Query("units")); ok {
+		return sys
+	}
+	if sys, ok := units.Parse(h.optionalUser(c).Preferences.Units); ok {
+		return sys
+	}
+	return units.Metric
+}
+
+// Batas ukuran file import
+const maxImportBytes = 10 << 20
+
+// IMPORT LOCATIONS (multipart: file=GeoJSON FeatureCollection / CSV, format opsional)
+func (h *Handler) importLocations(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+	fh, err := c.FormFile("file")
+	if err != nil {
+		respondError(c, apperr.BadRequest("File wajib diupload (field: file, maks 10MB)"))
+		return
+	}
+	format, ok := geoio.ParseFormat(c.PostForm("format"))
+	if !ok {
+		format, ok = geoio.FormatFromFilename(fh.Filename)
+	}
+	if !ok {
+		respondError(c, apperr.BadRequest("format harus geojson atau csv"))
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer f.Close()
+	rows, err := geoio.Read(format, f)
+	if err != nil {
+		respondError(c, apperr.BadRequest(err.Error()))
+		return
+	}
+	result, err := h.Locations.Import(c.Request.Context(), currentUser(c), rows)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	status, msg := http.StatusCreated, "Import selesai"
+	if result.Inserted == 0 && result.Geocoding == 0 {
+		status = http.StatusBadRequest
+	}
+	if result.Geocoding > 0 {
+		msg = "Import selesai, baris tanpa koordinat sedang dicari lewat alamatnya"
+	}
+	c.JSON(status, gin.H{"message": msg, "data": result})
+}
+
+// GET IMPORT JOB: progres geocoding baris import tanpa koordinat, termasuk
+// baris unresolved yang menunggu koordinat manual
+func (h *Handler) getImportJob(c *gin.Context) {
+	id, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	job, err := h.Locations.ImportJob(c.Request.Context(), currentUser(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": job})
+}
+
+// PLACE IMPORT ROW: buat lokasi untuk baris unresolved di koordinat yang
+// dipilih user. Body: {"coordinates": {"lat", "lng"}}
+func (h *Handler) placeImportRow(c *gin.Context) {
+	id, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	row, err := strconv.Atoi(c.Param("row"))
+	if err != nil {
+		respondError(c, apperr.BadRequest("row harus nomor baris"))
+		return
+	}
+	var input struct {
+		Coordinates *models.Coordinates `json:"coordinates"`
+	}
+	if !bindJSON(c, &input) {
+		return
+	}
+	if input.Coordinates == nil {
+		respondError(c, apperr.BadRequest("coordinates wajib diisi"))
+		return
+	}
+	job, err := h.Locations.PlaceImportRow(c.Request.Context(), currentUser(c), id, row, *input.Coordinates)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Lokasi ditempatkan", "data": job})
+}
+
+// RUN IMPORT GEOCODING (Admin), satu batch per request: ?batch=20. Panggil
+// ulang sampai queued = 0; rate_limited berarti batas provider tercapai
+func (h *Handler) runImportGeocoding(c *gin.Context) {
+	batch, _ := strconv.Atoi(c.Query("batch"))
+	result, err := h.Locations.GeocodeImports(c.Request.Context(), batch)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// EXPORT LOCATIONS
+// Query: ?format=geojson|csv, filter sama seperti GET /locations (category, created_by, q, accessible, price_range, payment,
+// include_archived)
+func (h *Handler) exportLocations(c *gin.Context) {
+	format, ok := geoio.ParseFormat(c.DefaultQuery("format", "geojson"))
+	if !ok {
+		respondError(c, apperr.BadRequest("format harus geojson atau csv"))
+		return
+	}
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", `attachment; filename="locations.`+string(format)+`"`)
+	c.Status(http.StatusOK)
+	err := h.Locations.Export(c.Request.Context(), services.ExportParams{
+		Category:        c.Query("category"),
+		CreatedBy:       c.Query("created_by"),
+		Q:               c.Query("q"),
+		Attributes:      attributeParams(c),
+		IncludeArchived: includeArchived(c),
+		Viewer:          h.optionalUser(c),
+	}, geoio.NewWriter(format, c.Writer))
+	if err != nil {
+		// Header sudah terkirim, tidak bisa lagi mengganti status
+		log.Println("export lokasi:", err)
+	}
+}
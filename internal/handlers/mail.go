@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LIST MAIL DEAD LETTERS (Admin): email transaksional yang gagal dikirim,
+// terbaru dulu. Query: ?page, ?limit
+func (h *Handler) listDeadLetters(c *gin.Context) {
+	letters, meta, err := h.Mail.DeadLetters(c.Request.Context(), listOptions(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": letters, "meta": meta, "provider": h.Mail.Name()})
+}
+
+// RESEND MAIL DEAD LETTER (Admin) lewat provider yang sedang aktif.
+// Berhasil = dead letter dihapus; gagal lagi = 502 dan percobaan dicatat.
+func (h *Handler) resendDeadLetter(c *gin.Context) {
+	id, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	letter, err := h.Mail.Resend(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Email terkirim ulang ke " + letter.To, "data": letter})
+}
+
+// DISCARD MAIL DEAD LETTER (Admin) tanpa mengirimnya
+func (h *Handler) discardDeadLetter(c *gin.Context) {
+	id, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.Mail.Discard(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Dead letter email dihapus"})
+}
@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"InfoCuy-Backend/internal/apperr"
+	"InfoCuy-Backend/internal/audit"
+	"InfoCuy-Backend/internal/chaos"
+	"InfoCuy-Backend/internal/jobs"
+	"InfoCuy-Backend/internal/logging"
+	"InfoCuy-Backend/internal/ratelimit"
+	"InfoCuy-Backend/internal/services"
+	"InfoCuy-Backend/internal/shadow"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestLogger memberi setiap request ID (dari header X-Request-ID jika
+// valid, selain itu dibuat baru), mengembalikannya di header response, lalu
+// mencatat satu baris log JSON per request.
+func requestLogger(c *gin.Context) {
+	start := time.Now()
+	id := c.GetHeader("X-Request-ID")
+	if !logging.ValidRequestID(id) {
+		id = logging.NewRequestID()
+	}
+	c.Header("X-Request-ID", id)
+	c.Set("request_id", id)
+	c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+
+	c.Next()
+
+	status := c.Writer.Status()
+	level := slog.LevelInfo
+	if status >= 500 {
+		level = slog.LevelError
+	} else if status >= 400 {
+		level = slog.LevelWarn
+	}
+	path := c.FullPath()
+	if path == "" {
+		path = c.Request.URL.Path
+	}
+	slog.LogAttrs(c.Request.Context(), level, "request",
+		slog.String("method", c.Request.Method),
+		slog.String("path", path),
+		slog.Int("status", status),
+		slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+		slog.String("ip", c.ClientIP()),
+		slog.String("user", audit.ActorFrom(c.Request.Context()).Email),
+		slog.Int("bytes", c.Writer.Size()),
+	)
+}
+
+// trackUsage mencatat setiap request ke statistik pemakaian API per
+// consumer (GET /admin/usage). User diambil setelah c.Next karena
+// authRequired baru mengisinya di dalam chain. Request shadow traffic
+// tidak dicatat karena sudah dihitung oleh service utama.
+func (h *Handler) trackUsage(c *gin.Context) {
+	if shadow.FromMirror(c.Request) {
+		c.Next()
+		return
+	}
+	c.Next()
+
+	route := c.FullPath()
+	if route == "" {
+		route = "*"
+	}
+	h.Usage.Record(services.UsageHit{
+		Email:    audit.ActorFrom(c.Request.Context()).Email,
+		IP:       c.ClientIP(),
+		Method:   c.Request.Method,
+		Route:    route,
+		Status:   c.Writer.Status(),
+		BytesIn:  c.Request.ContentLength,
+		BytesOut: int64(c.Writer.Size()),
+	})
+}
+
+// recoverPanic mengubah panic menjadi 500 dan mencatatnya beserta stack trace.
+func recoverPanic(c *gin.Context, recovered any) {
+	slog.ErrorContext(c.Request.Context(), "panic", "error", recovered, "stack", string(debug.Stack()))
+	writeError(c, apperr.Internal(nil))
+}
+
+// Wajib login: user disimpan di context dengan key "user"
+func (h *Handler) authRequired(c *gin.Context) {
+	u, err := h.authenticate(c)
+	// Akun terkunci atau wajib reset (header X-User-Email) dijelaskan apa adanya
+	var locked *services.AccountLockedError
+	if errors.As(err, &locked) || errors.Is(err, services.ErrPasswordResetRequired) {
+		respondError(c, err)
+		return
+	}
+	if err != nil {
+		msg := "Anda harus login!"
+		if bearerToken(c) == "" && c.GetHeader(legacyEmailHeader) != "" {
+			msg = "Header X-User-Email tidak lagi diterima, login untuk mendapatkan bearer token"
+		}
+		respondError(c, apperr.New(http.StatusUnauthorized, apperr.CodeUnauthorized, msg))
+		return
+	}
+	c.Set("user", *u)
+	actor := audit.ActorFrom(c.Request.Context())
+	actor.Email = u.Email
+	c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), actor))
+	c.Next()
+}
+
+// metricsAuth mewajibkan Authorization: Bearer <METRICS_TOKEN> untuk
+// /metrics jika token diset. Metrik berisi hitungan per route dan per
+// consumer, jadi tanpa token endpoint ini harus dibatasi lewat firewall.
+func (h *Handler) metricsAuth(c *gin.Context) {
+	if h.MetricsToken == "" {
+		c.Next()
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(bearerToken(c)), []byte(h.MetricsToken)) != 1 {
+		respondError(c, apperr.New(http.StatusUnauthorized, apperr.CodeUnauthorized, "Token metrics tidak valid"))
+		return
+	}
+	c.Next()
+}
+
+// Simpan IP client di context supaya service bisa mencatat audit log
+func withAuditActor(c *gin.Context) {
+	c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), audit.Actor{IP: c.ClientIP()}))
+	c.Next()
+}
+
+// limitByIP menolak request dengan 429 jika IP client melewati batas l.
+// Tanpa limiter (nil) semua request diteruskan.
+func limitByIP(l *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l == nil {
+			c.Next()
+			return
+		}
+		if ok, wait := l.Allow(c.ClientIP()); !ok {
+			retryAfter := setRetryAfter(c, wait)
+			respondError(c, apperr.New(http.StatusTooManyRequests, apperr.CodeRateLimited,
+				"Terlalu banyak permintaan, coba lagi nanti").With("retry_after", retryAfter))
+			return
+		}
+		c.Next()
+	}
+}
+
+// injectFaults menambah latensi atau menjawab 503 sesuai CHAOS_HTTP_*
+// (lihat package chaos) untuk menguji retry di client. Tanpa chaos aktif
+// request langsung diteruskan.
+func injectFaults(c *gin.Context) {
+	if err := chaos.HTTP(c.Request.Context()); err != nil {
+		c.Header("Retry-After", "1")
+		respondError(c, apperr.New(http.StatusServiceUnavailable, apperr.CodeUnavailable,
+			"Server sedang tidak tersedia (gangguan buatan chaos)").With("retry_after", 1).Wrap(err))
+		return
+	}
+	c.Next()
+}
+
+// RequirePermission menolak request jika role user tidak memiliki
+// permission perm. Dipasang setelah authRequired.
+func (h *Handler) RequirePermission(perm string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !h.Roles.Can(c.Request.Context(), currentUser(c).Role, perm) {
+			respondError(c, apperr.New(http.StatusForbidden, apperr.CodeForbidden, "Akses ditolak").With("permission", perm))
+			return
+		}
+		c.Next()
+	}
+}
+
+// backfillWait adalah lama request import/backfill boleh menunggu slot
+// sebelum diminta mencoba lagi.
+const backfillWait = 30 * time.Second
+
+// backfillSlot menjalankan import dan job batch di kelas jobs.Backfill
+// supaya pekerjaan besar tidak merebut slot email dan webhook. Jika slot
+// tidak didapat dalam backfillWait, request dijawab 503 dengan Retry-After
+// sehingga cron cukup mengulang di tick berikutnya.
+func (h *Handler) backfillSlot(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), backfillWait)
+	release, err := h.Jobs.Acquire(ctx, jobs.Backfill)
+	cancel()
+	if err != nil {
+		retryAfter := setRetryAfter(c, backfillWait)
+		respondError(c, apperr.New(http.StatusServiceUnavailable, "JOBS_BUSY",
+			"Antrean import dan backfill sedang penuh, coba lagi nanti").With("retry_after", retryAfter).Wrap(err))
+		return
+	}
+	defer release()
+	c.Next()
+}
+
+// Middleware untuk alias lama: catat hit dan kirim header Deprecation/Sunset/Link
+func (h *Handler) legacyAlias(rt route) gin.HandlerFunc {
+	key := rt.method + " " + rt.path
+	successor := "/v1" + rt.path
+	h.Deprecations.Register(key, successor)
+	return func(c *gin.Context) {
+		h.Deprecations.HitRoute(key)
+		c.Header("Deprecation", "true")
+		c.Header("Link", "<"+successor+">; rel=\"successor-version\"")
+		if sunset, ok := h.Deprecations.Sunset(key); ok {
+			c.Header("Sunset", sunset.UTC().Format(http.TimeFormat))
+		}
+		c.Next()
+	}
+}
+
+const (
+	// legacyEmailHeader adalah header auth lama sebelum JWT
+	legacyEmailHeader = "X-User-Email"
+	// legacyEmailFeature adalah key header itu di laporan deprecation dan
+	// di LEGACY_SUNSET_ROUTES
+	legacyEmailFeature = "header:" + legacyEmailHeader
+)
+
+// trackLegacyHeaders memberi header Deprecation, Warning dan Sunset (jika
+// dikonfigurasi) ke request yang masih mengirim X-User-Email. Client yang
+// hanya mengirim X-User-Email tanpa bearer token dicatat per user agent dan
+// IP di GET /admin/deprecations, dan ditulis ke log saat pertama terlihat,
+// supaya cutover frontend bisa dikoordinasikan sebelum masa transisi
+// (LEGACY_EMAIL_AUTH) dimatikan.
+func (h *Handler) trackLegacyHeaders(c *gin.Context) {
+	email := c.GetHeader(legacyEmailHeader)
+	if email == "" {
+		c.Next()
+		return
+	}
+	c.Header("Deprecation", "true")
+	c.Header("Link", "</v1/login>; rel=\"successor-version\"")
+	if sunset, ok := h.Deprecations.Sunset(legacyEmailFeature); ok {
+		c.Header("Sunset", sunset.UTC().Format(http.TimeFormat))
+	}
+	if bearerToken(c) != "" {
+		h.Deprecations.HitFeature(legacyEmailFeature)
+		c.Header("Warning", `299 - "X-User-Email diabaikan karena ada bearer token, hapus header ini"`)
+		c.Next()
+		return
+	}
+	legacy := h.Auth.LegacyEmailAuth()
+	if legacy {
+		c.Header("Warning", `299 - "X-User-Email deprecated, gunakan Authorization: Bearer dari POST /v1/login"`)
+	} else {
+		c.Header("Warning", `299 - "X-User-Email tidak lagi diterima, gunakan Authorization: Bearer dari POST /v1/login"`)
+	}
+	userAgent, ip := c.Request.UserAgent(), c.ClientIP()
+	if h.Deprecations.HitClient(legacyEmailFeature, userAgent, ip, email) {
+		slog.WarnContext(c.Request.Context(), "client masih memakai X-User-Email tanpa bearer token",
+			"user_agent", userAgent, "ip", ip, "email", email, "legacy_email_auth", legacy)
+	}
+	c.Next()
+}
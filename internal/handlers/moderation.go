@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+
+	"InfoCuy-Backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PENDING LOCATIONS (Moderator), yang paling lama menunggu lebih dulu
+// Query: ?page, ?limit (default 20, maks 100), ?cursor
+func (h *Handler) listPendingLocations(c *gin.Context) {
+	locations, meta, err := h.Locations.Pending(c.Request.Context(), currentUser(c), listOptions(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": locations, "meta": meta})
+}
+
+// APPROVE LOCATION (Moderator), lokasi mulai tampil di listing publik
+func (h *Handler) approveLocation(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	loc, err := h.Locations.Approve(c.Request.Context(), currentUser(c), objID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Lokasi disetujui", "data": loc})
+}
+
+// REJECT LOCATION (Moderator), body opsional: {"reason": "..."} dikirim ke pembuat lokasi
+func (h *Handler) rejectLocation(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var input struct {
+		Reason string `json:"reason"`
+	}
+	if c.Request.ContentLength != 0 && !bindJSON(c, &input) {
+		return
+	}
+	loc, err := h.Locations.Reject(c.Request.Context(), currentUser(c), objID, input.Reason)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Lokasi ditolak", "data": loc})
+}
+
+// MERGE LOCATION (Moderator), body: {"into": "<id lokasi yang dipertahankan>"}.
+// Lokasi duplikat masuk trash dan ID-nya dialihkan permanen ke lokasi tujuan.
+func (h *Handler) mergeLocation(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var in models.MergeInput
+	if !bindJSON(c, &in) {
+		return
+	}
+	redirect, err := h.Locations.Merge(c.Request.Context(), currentUser(c), objID, in)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Lokasi digabung", "data": redirect})
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+
+	"InfoCuy-Backend/internal/apperr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Batas ukuran CSV kode pos
+const maxPostcodeImportBytes = 20 << 20
+
+// GET POSTCODE, daftar kelurahan/kecamatan yang memakai kode pos
+func (h *Handler) getPostcode(c *gin.Context) {
+	pc, err := h.Postcodes.Get(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pc)
+}
+
+// IMPORT POSTCODES (Admin), multipart: file=CSV. Dataset lama diganti.
+func (h *Handler) importPostcodes(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxPostcodeImportBytes)
+	fh, err := c.FormFile("file")
+	if err != nil {
+		respondError(c, apperr.BadRequest("File CSV wajib diupload (field: file, maks 20MB)"))
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer f.Close()
+	result, err := h.Postcodes.Import(c.Request.Context(), f)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Dataset kode pos di-import", "data": result})
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+
+	"InfoCuy-Backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SET PROMOTION (Admin), body: {"type": "pinned|sponsored", "sponsor", "starts_at", "ends_at"}
+func (h *Handler) setPromotion(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var input models.PromotionInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	promotion, err := h.Promotions.Set(c.Request.Context(), objID, input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Promosi lokasi disimpan", "data": promotion})
+}
+
+// CLEAR PROMOTION (Admin)
+func (h *Handler) clearPromotion(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.Promotions.Clear(c.Request.Context(), objID); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Promosi lokasi dihentikan"})
+}
+
+// PROMOTION IMPRESSIONS (Admin), query opsional from & to (YYYY-MM-DD, WIB)
+func (h *Handler) promotionImpressions(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	report, err := h.Promotions.Report(c.Request.Context(), objID, c.Query("from"), c.Query("to"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"InfoCuy-Backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// START REASSIGN (Admin): pindahkan pemilik semua lokasi yang cocok filter.
+// Lokasi dipindah bertahap oleh POST /admin/jobs/location-reassign.
+func (h *Handler) startReassign(c *gin.Context) {
+	var in models.ReassignInput
+	if !bindJSON(c, &in) {
+		return
+	}
+	job, err := h.Reassign.Start(c.Request.Context(), currentUser(c), in)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Pemindahan pemilik dijadwalkan", "data": job})
+}
+
+// LIST REASSIGN JOBS (Admin), terbaru dulu. Query: ?page, ?limit
+func (h *Handler) listReassigns(c *gin.Context) {
+	jobs, meta, err := h.Reassign.List(c.Request.Context(), listOptions(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": jobs, "meta": meta})
+}
+
+// GET REASSIGN JOB (Admin), termasuk progres matched/reassigned
+func (h *Handler) getReassign(c *gin.Context) {
+	id, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	job, err := h.Reassign.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": job})
+}
+
+// RUN REASSIGN (Admin), satu batch per request: ?batch=200. Panggil ulang
+// sampai queued = 0.
+func (h *Handler) runReassign(c *gin.Context) {
+	batch, _ := strconv.Atoi(c.Query("batch"))
+	result, err := h.Reassign.Process(c.Request.Context(), batch)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
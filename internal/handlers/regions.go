@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+
+	"InfoCuy-Backend/internal/apperr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Batas ukuran file batas wilayah (GeoJSON kecamatan bisa ratusan MB)
+const maxRegionImportBytes = 300 << 20
+
+// LIST REGIONS, tanpa geometry. Query: ?level, ?parent (kode induk)
+func (h *Handler) listRegions(c *gin.Context) {
+	regions, err := h.Regions.List(c.Request.Context(), c.Query("level"), c.Query("parent"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": regions, "count": len(regions)})
+}
+
+// REGION STATS, jumlah lokasi per kategori dan per sub-wilayah
+func (h *Handler) regionStats(c *gin.Context) {
+	stats, err := h.Regions.Stats(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// IMPORT REGIONS (Admin), multipart: file=GeoJSON FeatureCollection, level=provinsi|kabupaten|kecamatan
+func (h *Handler) importRegions(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxRegionImportBytes)
+	fh, err := c.FormFile("file")
+	if err != nil {
+		respondError(c, apperr.BadRequest("File GeoJSON wajib diupload (field: file, maks 300MB)"))
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer f.Close()
+	result, err := h.Regions.Import(c.Request.Context(), c.PostForm("level"), f)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Import wilayah selesai", "data": result})
+}
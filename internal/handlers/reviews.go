@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+
+	"InfoCuy-Backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LIST REVIEWS, terbaru dulu
+// Query: ?page, ?limit (default 20, maks 100), ?cursor
+func (h *Handler) listReviews(c *gin.Context) {
+	locationID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	reviews, meta, summary, err := h.Reviews.List(c.Request.Context(), locationID, listOptions(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": reviews, "meta": meta, "rating": summary})
+}
+
+// ADD REVIEW, satu ulasan per user per lokasi. Ulasan yang tertahan aturan
+// spam tetap 201 dengan moderation_status pending.
+func (h *Handler) createReview(c *gin.Context) {
+	locationID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var input models.ReviewInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	review, err := h.Reviews.Create(c.Request.Context(), currentUser(c), locationID, input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	message := "Ulasan ditambahkan!"
+	if !review.Listed() {
+		message = "Ulasan disimpan dan akan tampil setelah diperiksa moderator"
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": message, "data": review})
+}
+
+// DELETE REVIEW, pemilik ulasan atau role dengan reviews:moderate
+func (h *Handler) deleteReview(c *gin.Context) {
+	locationID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	reviewID, ok := objectIDParam(c, "reviewId")
+	if !ok {
+		return
+	}
+	if _, err := h.Reviews.Delete(c.Request.Context(), currentUser(c), locationID, reviewID); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Ulasan dihapus"})
+}
+
+// PENDING REVIEWS (Moderator), ulasan yang ditahan aturan spam beserta
+// spam_flags, yang paling lama lebih dulu
+// Query: ?page, ?limit (default 20, maks 100), ?cursor
+func (h *Handler) listPendingReviews(c *gin.Context) {
+	reviews, meta, err := h.Reviews.Pending(c.Request.Context(), currentUser(c), listOptions(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": reviews, "meta": meta})
+}
+
+// APPROVE REVIEW (Moderator), ulasan tayang dan ikut rata-rata rating
+func (h *Handler) approveReview(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	review, err := h.Reviews.Approve(c.Request.Context(), currentUser(c), objID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Ulasan disetujui", "data": review})
+}
+
+// REJECT REVIEW (Moderator), ulasan tidak pernah tayang
+func (h *Handler) rejectReview(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	review, err := h.Reviews.Reject(c.Request.Context(), currentUser(c), objID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Ulasan ditolak", "data": review})
+}
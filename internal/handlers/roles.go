@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/rbac"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LIST PERMISSIONS
+func (h *Handler) listPermissions(c *gin.Context) {
+	c.JSON(http.StatusOK, rbac.Permissions)
+}
+
+// LIST ROLES
+func (h *Handler) listRoles(c *gin.Context) {
+	roles, err := h.Roles.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, roles)
+}
+
+// CREATE ROLE
+func (h *Handler) createRole(c *gin.Context) {
+	var input models.Role
+	if !bindJSON(c, &input) {
+		return
+	}
+	role, err := h.Roles.Create(c.Request.Context(), input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.emitSecurityEvent(c, "role.create", 6, currentUser(c).Email, role.Name, "success", nil)
+	c.JSON(http.StatusCreated, gin.H{"message": "Role dibuat", "data": role})
+}
+
+// UPDATE ROLE
+func (h *Handler) updateRole(c *gin.Context) {
+	var input models.Role
+	if !bindJSON(c, &input) {
+		return
+	}
+	role, err := h.Roles.Update(c.Request.Context(), c.Param("name"), input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.emitSecurityEvent(c, "role.update", 6, currentUser(c).Email, role.Name, "success", nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Role diubah", "data": role})
+}
+
+// DELETE ROLE
+func (h *Handler) deleteRole(c *gin.Context) {
+	name := c.Param("name")
+	if err := h.Roles.Delete(c.Request.Context(), name); err != nil {
+		respondError(c, err)
+		return
+	}
+	h.emitSecurityEvent(c, "role.delete", 6, currentUser(c).Email, name, "success", nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Role dihapus"})
+}
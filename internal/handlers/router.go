@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+
+	"InfoCuy-Backend/internal/adminui"
+	"InfoCuy-Backend/internal/apperr"
+	"InfoCuy-Backend/internal/config"
+	"InfoCuy-Backend/internal/metrics"
+	"InfoCuy-Backend/internal/openapi"
+	"InfoCuy-Backend/internal/rbac"
+
+	"github.com/gin-gonic/gin"
+)
+
+type route struct {
+	method   string
+	path     string
+	handlers []gin.HandlerFunc
+}
+
+// Route lama yang sudah ada sebelum /v1. Didaftarkan di bawah /v1, dan
+// path tanpa versi tetap dilayani sebagai alias deprecated.
+func (h *Handler) legacyRoutes() []route {
+	authLimit := limitByIP(h.AuthRateLimit)
+	return []route{
+		{"POST", "/register", []gin.HandlerFunc{authLimit, h.register}},
+		{"POST", "/login", []gin.HandlerFunc{authLimit, h.login}},
+		{"GET", "/locations", []gin.HandlerFunc{h.listLocations}},
+		{"POST", "/locations", []gin.HandlerFunc{h.authRequired, h.RequirePermission(rbac.LocationsCreate), h.createLocation}},
+		{"PUT", "/locations/:id", []gin.HandlerFunc{h.authRequired, h.updateLocation}},
+		{"DELETE", "/locations/:id", []gin.HandlerFunc{h.authRequired, h.deleteLocation}},
+		{"GET", "/users", []gin.HandlerFunc{h.authRequired, h.RequirePermission(rbac.UsersManage), h.listUsers}},
+		{"PUT", "/users/:id/role", []gin.HandlerFunc{h.authRequired, h.RequirePermission(rbac.UsersManage), h.updateUserRole}},
+		{"DELETE", "/users/:id", []gin.HandlerFunc{h.authRequired, h.RequirePermission(rbac.UsersManage), h.deleteUser}},
+		{"GET", "/admin/security-check", []gin.HandlerFunc{h.authRequired, h.RequirePermission(rbac.SystemAudit), h.securityCheck}},
+	}
+}
+
+// platformIPHeaders adalah header IP client yang diisi ulang oleh platform
+// (client tidak bisa memalsukannya karena platform menimpanya).
+var platformIPHeaders = map[string]string{
+	config.PlatformVercel:     "X-Real-IP",
+	config.PlatformCloudflare: gin.PlatformCloudflare,
+}
+
+// trustProxies mengatur dari mana c.ClientIP dibaca. Tanpa konfigurasi
+// header X-Forwarded-For diabaikan; kalau tidak, setiap client bisa
+// menghindari rate limit dan mengisi IP palsu di audit log dengan
+// mengganti header itu.
+func (h *Handler) trustProxies(r *gin.Engine) {
+	if err := r.SetTrustedProxies(h.TrustedProxies); err != nil {
+		// Sudah divalidasi config; jangan sampai gagal terbuka
+		slog.Error("TRUSTED_PROXIES tidak valid, semua proxy diabaikan", "error", err)
+		r.SetTrustedProxies(nil)
+	}
+	r.TrustedPlatform = platformIPHeaders[h.TrustedPlatform]
+}
+
+// Router membuat gin.Engine lengkap dengan middleware dan semua route.
+func (h *Handler) Router(publicCORS, adminCORS config.CORS) *gin.Engine {
+	r := gin.New()
+	h.trustProxies(r)
+	r.Use(requestLogger)
+	// Di luar renderErrors supaya status error sudah tertulis saat dicatat
+	r.Use(h.trackUsage)
+	r.Use(gin.CustomRecoveryWithWriter(io.Discard, recoverPanic))
+	r.Use(renderErrors)
+	h.cors = newReloadableCORS(publicCORS, adminCORS)
+	r.Use(h.cors.handle)
+	r.Use(h.trackLegacyHeaders)
+	r.Use(withAuditActor)
+	r.Use(h.shadowTraffic)
+
+	r.NoRoute(func(c *gin.Context) {
+		respondError(c, apperr.New(http.StatusNotFound, apperr.CodeRouteNotFound, "Endpoint tidak ditemukan"))
+	})
+
+	r.GET("/metrics", h.metricsAuth, func(c *gin.Context) {
+		c.Header("Content-Type", "text/plain; version=0.0.4")
+		metrics.WriteText(c.Writer)
+	})
+	r.GET("/healthz", h.healthz)
+	r.GET("/readyz", h.readyz)
+	r.GET("/version", h.version)
+	r.GET("/status.json", h.statusPage)
+	r.GET("/openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", openapi.Spec())
+	})
+	r.GET("/docs", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", openapi.DocsHTML())
+	})
+	// UI admin tidak butuh login untuk dimuat; datanya diambil lewat /v1
+	// dengan token dan permission masing-masing endpoint
+	r.GET("/admin", adminPage)
+	r.GET(adminui.AssetPrefix+"*filepath", adminHeaders, gin.WrapH(adminui.Assets()))
+
+	// === DEFINISI ROUTES ===
+	// Batas per IP berlaku untuk semua route API; /metrics dan health check
+	// tidak ikut dibatasi
+	limit := limitByIP(h.RateLimit)
+	authLimit := limitByIP(h.AuthRateLimit)
+	v1 := r.Group("/v1", limit, injectFaults, h.readOnlyGuard)
+	for _, rt := range h.legacyRoutes() {
+		v1.Handle(rt.method, rt.path, rt.handlers...)
+		r.Handle(rt.method, rt.path, append([]gin.HandlerFunc{limit, h.legacyAlias(rt), h.readOnlyGuard}, rt.handlers...)...)
+	}
+	v1.POST("/refresh", h.refresh)
+	v1.POST("/users/me/password", authLimit, h.authRequired, h.changePassword)
+	v1.GET("/users/me/favorites", h.authRequired, h.listFavorites)
+	v1.POST("/password-reset", authLimit, h.requestPasswordReset)
+	v1.POST("/password-reset/confirm", authLimit, h.confirmPasswordReset)
+	v1.POST("/sessions/report", authLimit, h.reportSession)
+	v1.GET("/locations/nearby", h.nearbyLocations)
+	v1.POST("/distance/matrix", h.distanceMatrix)
+	v1.GET("/locations/compare", h.compareLocations)
+	v1.GET("/locations/stream", h.streamLocations)
+	v1.GET("/locations/export", h.exportLocations)
+	v1.GET("/locations/facets", h.locationFacets)
+	v1.GET("/locations/featured", h.featuredLocations)
+	v1.GET("/locations/trash", h.authRequired, h.listTrash)
+	v1.GET("/locations/categories/:slug", h.listLocationsByCategory)
+	v1.GET("/locations/:id", h.getLocation)
+	v1.GET("/locations/:id/weather", h.locationWeather)
+	v1.GET("/locations/:id/transit", h.locationTransit)
+	v1.GET("/locations/:id/reviews", h.listReviews)
+	v1.POST("/locations/:id/reviews", h.authRequired, h.createReview)
+	v1.DELETE("/locations/:id/reviews/:reviewId", h.authRequired, h.deleteReview)
+	v1.POST("/locations/:id/photos", h.authRequired, h.uploadPhoto)
+	v1.DELETE("/locations/:id/photos/:photoId", h.authRequired, h.deletePhoto)
+	v1.POST("/uploads/presign", h.authRequired, h.presignUpload)
+	v1.POST("/uploads/confirm", h.authRequired, h.confirmUpload)
+	v1.POST("/locations/:id/confirm", h.authRequired, h.confirmLocation)
+	v1.GET("/locations/:id/checkin/qr", h.authRequired, h.checkInQR)
+	v1.POST("/locations/:id/checkin/verify", h.authRequired, h.verifyCheckIn)
+	v1.GET("/locations/:id/checkin/attendance", h.authRequired, h.checkInAttendance)
+	v1.POST("/locations/:id/favorite", h.authRequired, h.addFavorite)
+	v1.DELETE("/locations/:id/favorite", h.authRequired, h.removeFavorite)
+	v1.POST("/locations/:id/restore", h.authRequired, h.restoreLocation)
+	v1.POST("/locations/import", h.authRequired, h.RequirePermission(rbac.LocationsCreate), h.backfillSlot, h.importLocations)
+	v1.GET("/locations/import-jobs/:id", h.authRequired, h.getImportJob)
+	v1.POST("/locations/import-jobs/:id/rows/:row/place", h.authRequired, h.RequirePermission(rbac.LocationsCreate), h.placeImportRow)
+	v1.GET("/suggestions/missing", h.authRequired, h.RequirePermission(rbac.LocationsCreate), h.missingPOIs)
+	v1.GET("/me/sessions", h.authRequired, h.mySessions)
+	v1.GET("/me/preferences", h.authRequired, h.getPreferences)
+	v1.PUT("/me/preferences", h.authRequired, h.updatePreferences)
+	v1.GET("/me/quota", h.authRequired, h.myQuota)
+
+	// Log domain event untuk service internal (role dengan events:read)
+	v1.GET("/events", h.authRequired, h.RequirePermission(rbac.EventsRead), h.listEvents)
+	v1.GET("/events/consumers/:name", h.authRequired, h.RequirePermission(rbac.EventsRead), h.getEventOffset)
+	v1.PUT("/events/consumers/:name", h.authRequired, h.RequirePermission(rbac.EventsRead), h.putEventOffset)
+
+	v1.GET("/stats", h.publicStats)
+	v1.GET("/config", h.publicConfig)
+	// Kartu embed untuk blog: :file = <id>.json, CORS terbuka untuk semua origin
+	v1.GET("/public/locations/:file", h.embedLocation)
+	v1.GET("/categories", h.listCategories)
+	v1.POST("/categories/suggest", h.authRequired, h.suggestCategory)
+	v1.GET("/categories/:slug", h.getCategory)
+	v1.POST("/categories", h.authRequired, h.RequirePermission(rbac.CategoriesManage), h.createCategory)
+	v1.PUT("/categories/:slug", h.authRequired, h.RequirePermission(rbac.CategoriesManage), h.updateCategory)
+	v1.DELETE("/categories/:slug", h.authRequired, h.RequirePermission(rbac.CategoriesManage), h.deleteCategory)
+
+	v1.GET("/changelog", h.changelog)
+
+	v1.GET("/regions", h.listRegions)
+	v1.GET("/regions/:code/stats", h.regionStats)
+	v1.GET("/postcodes/:code", h.getPostcode)
+	v1.GET("/geocode/reverse", h.reverseGeocode)
+	v1.GET("/geocode/search", h.searchGeocode)
+	v1.GET("/campaigns/open/:token", h.trackCampaignOpen)
+
+	admin := v1.Group("/admin", h.authRequired)
+	admin.GET("/deprecations", h.RequirePermission(rbac.SystemAudit), h.deprecationReport)
+	admin.GET("/doctor", h.RequirePermission(rbac.SystemAudit), h.doctorReport)
+	admin.GET("/runtime-info", h.RequirePermission(rbac.SystemAudit), h.runtimeInfo)
+	admin.POST("/config/reload", h.RequirePermission(rbac.SettingsManage), h.reloadConfig)
+	admin.GET("/audit-logs", h.RequirePermission(rbac.SystemAudit), h.listAuditLogs)
+	admin.GET("/audit-logs/verify", h.RequirePermission(rbac.SystemAudit), h.verifyAuditLogs)
+	// Path tunggal seperti di permintaan awal fitur; client lama memakainya
+	admin.GET("/audit-log/verify", h.RequirePermission(rbac.SystemAudit), h.verifyAuditLogs)
+	admin.GET("/database", h.RequirePermission(rbac.SystemAudit), h.databaseStatus)
+	admin.PUT("/database/mode", h.RequirePermission(rbac.SettingsManage), h.setDatabaseMode)
+	admin.GET("/status/incidents", h.RequirePermission(rbac.SettingsManage), h.listIncidents)
+	admin.POST("/status/incidents", h.RequirePermission(rbac.SettingsManage), h.createIncident)
+	admin.PUT("/status/incidents/:id", h.RequirePermission(rbac.SettingsManage), h.updateIncident)
+	admin.DELETE("/status/incidents/:id", h.RequirePermission(rbac.SettingsManage), h.deleteIncident)
+	admin.GET("/audit-logs/export", h.RequirePermission(rbac.SystemAudit), h.exportAuditLogs)
+	admin.GET("/audit-logs/archives", h.RequirePermission(rbac.SystemAudit), h.listAuditArchives)
+	admin.GET("/usage", h.RequirePermission(rbac.SystemAudit), h.usageReport)
+	admin.GET("/locations/stale", h.RequirePermission(rbac.LocationsModerate), h.staleLocations)
+	admin.GET("/locations/pending", h.RequirePermission(rbac.LocationsModerate), h.listPendingLocations)
+	admin.POST("/locations/:id/approve", h.RequirePermission(rbac.LocationsModerate), h.approveLocation)
+	admin.POST("/locations/:id/reject", h.RequirePermission(rbac.LocationsModerate), h.rejectLocation)
+	admin.POST("/locations/:id/merge", h.RequirePermission(rbac.LocationsModerate), h.mergeLocation)
+	admin.DELETE("/locations/:id", h.RequirePermission(rbac.LocationsPurge), h.hardDeleteLocation)
+	admin.POST("/locations/:id/unarchive", h.RequirePermission(rbac.LocationsModerate), h.unarchiveLocation)
+	admin.GET("/reviews/pending", h.RequirePermission(rbac.ReviewsModerate), h.listPendingReviews)
+	admin.POST("/reviews/:id/approve", h.RequirePermission(rbac.ReviewsModerate), h.approveReview)
+	admin.POST("/reviews/:id/reject", h.RequirePermission(rbac.ReviewsModerate), h.rejectReview)
+	admin.PUT("/locations/:id/promotion", h.RequirePermission(rbac.LocationsPromote), h.setPromotion)
+	admin.DELETE("/locations/:id/promotion", h.RequirePermission(rbac.LocationsPromote), h.clearPromotion)
+	admin.GET("/locations/:id/promotion/impressions", h.RequirePermission(rbac.LocationsPromote), h.promotionImpressions)
+	admin.GET("/featured", h.RequirePermission(rbac.LocationsPromote), h.getFeatured)
+	admin.PUT("/featured", h.RequirePermission(rbac.LocationsPromote), h.updateFeatured)
+	admin.POST("/locations/reassign", h.RequirePermission(rbac.LocationsModerate), h.startReassign)
+	admin.GET("/locations/reassign", h.RequirePermission(rbac.LocationsModerate), h.listReassigns)
+	admin.GET("/locations/reassign/:id", h.RequirePermission(rbac.LocationsModerate), h.getReassign)
+	admin.GET("/integrity-check", h.RequirePermission(rbac.SystemAudit), h.getIntegrityReport)
+	admin.POST("/integrity-check", h.RequirePermission(rbac.SettingsManage), h.backfillSlot, h.runIntegrityCheck)
+	admin.POST("/integrity-check/repair", h.RequirePermission(rbac.SettingsManage), h.repairIntegrityIssue)
+	admin.GET("/settings/near-limits", h.RequirePermission(rbac.SettingsManage), h.getNearSettings)
+	admin.PUT("/settings/near-limits", h.RequirePermission(rbac.SettingsManage), h.updateNearSettings)
+	admin.GET("/settings/quotas", h.RequirePermission(rbac.SettingsManage), h.getQuotaSettings)
+	admin.PUT("/settings/quotas", h.RequirePermission(rbac.SettingsManage), h.updateQuotaSettings)
+	admin.GET("/settings/ranking", h.RequirePermission(rbac.SettingsManage), h.getRankingWeights)
+	admin.PUT("/settings/ranking", h.RequirePermission(rbac.SettingsManage), h.updateRankingWeights)
+	admin.GET("/settings/attribute-labels", h.RequirePermission(rbac.SettingsManage), h.getAttributeLabels)
+	admin.PUT("/settings/attribute-labels", h.RequirePermission(rbac.SettingsManage), h.updateAttributeLabels)
+	admin.PUT("/users/:id/quota", h.RequirePermission(rbac.UsersManage), h.updateUserQuota)
+	// Job yang mengirim email/webhook (campaign, alert, export) mengantre per
+	// pengiriman di mailer dan service, bukan di sini
+	admin.POST("/jobs/elevation-backfill", h.RequirePermission(rbac.SettingsManage), h.backfillSlot, h.backfillElevation)
+	admin.POST("/jobs/schema-upgrade", h.RequirePermission(rbac.SettingsManage), h.backfillSlot, h.upgradeSchema)
+	admin.POST("/jobs/trash-purge", h.RequirePermission(rbac.LocationsPurge), h.backfillSlot, h.purgeTrash)
+	admin.POST("/jobs/location-archive", h.RequirePermission(rbac.SettingsManage), h.backfillSlot, h.archiveLocations)
+	admin.POST("/jobs/map-view-rebuild", h.RequirePermission(rbac.SettingsManage), h.backfillSlot, h.rebuildMapView)
+	admin.POST("/jobs/counter-reconcile", h.RequirePermission(rbac.SettingsManage), h.backfillSlot, h.reconcileCounters)
+	admin.POST("/jobs/audit-archive", h.RequirePermission(rbac.SettingsManage), h.backfillSlot, h.archiveAuditLogs)
+	admin.POST("/jobs/transit-sync", h.RequirePermission(rbac.SettingsManage), h.backfillSlot, h.syncTransitFeeds)
+	admin.POST("/jobs/campaign-delivery", h.RequirePermission(rbac.CampaignsManage), h.deliverCampaigns)
+	admin.POST("/jobs/location-reassign", h.RequirePermission(rbac.LocationsModerate), h.backfillSlot, h.runReassign)
+	admin.POST("/jobs/import-geocode", h.RequirePermission(rbac.SettingsManage), h.backfillSlot, h.runImportGeocoding)
+	admin.POST("/jobs/freshness-alerts", h.RequirePermission(rbac.SettingsManage), h.sendFreshnessAlerts)
+	admin.POST("/jobs/scheduled-exports", h.RequirePermission(rbac.ExportsSchedule), h.runScheduledExports)
+	admin.GET("/scheduled-exports", h.RequirePermission(rbac.ExportsSchedule), h.listScheduledExports)
+	admin.POST("/scheduled-exports", h.RequirePermission(rbac.ExportsSchedule), h.createScheduledExport)
+	admin.GET("/scheduled-exports/:id", h.RequirePermission(rbac.ExportsSchedule), h.getScheduledExport)
+	admin.PUT("/scheduled-exports/:id", h.RequirePermission(rbac.ExportsSchedule), h.updateScheduledExport)
+	admin.DELETE("/scheduled-exports/:id", h.RequirePermission(rbac.ExportsSchedule), h.deleteScheduledExport)
+	admin.POST("/scheduled-exports/:id/run", h.RequirePermission(rbac.ExportsSchedule), h.runScheduledExport)
+	admin.GET("/mail/dead-letters", h.RequirePermission(rbac.MailManage), h.listDeadLetters)
+	admin.POST("/mail/dead-letters/:id/resend", h.RequirePermission(rbac.MailManage), h.resendDeadLetter)
+	admin.DELETE("/mail/dead-letters/:id", h.RequirePermission(rbac.MailManage), h.discardDeadLetter)
+	admin.GET("/category-suggestions", h.RequirePermission(rbac.CategoriesManage), h.listCategorySuggestions)
+	admin.POST("/category-suggestions/:id/approve", h.RequirePermission(rbac.CategoriesManage), h.approveCategorySuggestion)
+	admin.POST("/category-suggestions/:id/merge", h.RequirePermission(rbac.CategoriesManage), h.mergeCategorySuggestion)
+	admin.POST("/category-suggestions/:id/reject", h.RequirePermission(rbac.CategoriesManage), h.rejectCategorySuggestion)
+	admin.GET("/campaigns", h.RequirePermission(rbac.CampaignsManage), h.listCampaigns)
+	admin.POST("/campaigns", h.RequirePermission(rbac.CampaignsManage), h.createCampaign)
+	admin.GET("/campaigns/:id", h.RequirePermission(rbac.CampaignsManage), h.getCampaign)
+	admin.GET("/campaigns/:id/preview", h.RequirePermission(rbac.CampaignsManage), h.previewCampaign)
+	admin.POST("/campaigns/:id/test", h.RequirePermission(rbac.CampaignsManage), h.testSendCampaign)
+	admin.POST("/campaigns/:id/send", h.RequirePermission(rbac.CampaignsManage), h.sendCampaign)
+	admin.POST("/transit/import", h.RequirePermission(rbac.SettingsManage), h.backfillSlot, h.importTransit)
+	admin.GET("/transit/sources", h.RequirePermission(rbac.SettingsManage), h.listTransitSources)
+	admin.PUT("/transit/sources/:feed", h.RequirePermission(rbac.SettingsManage), h.putTransitSource)
+	admin.DELETE("/transit/sources/:feed", h.RequirePermission(rbac.SettingsManage), h.deleteTransitSource)
+	admin.POST("/regions/import", h.RequirePermission(rbac.SettingsManage), h.backfillSlot, h.importRegions)
+	admin.POST("/postcodes/import", h.RequirePermission(rbac.SettingsManage), h.backfillSlot, h.importPostcodes)
+	admin.GET("/permissions", h.RequirePermission(rbac.RolesManage), h.listPermissions)
+	admin.GET("/roles", h.RequirePermission(rbac.RolesManage), h.listRoles)
+	admin.POST("/roles", h.RequirePermission(rbac.RolesManage), h.createRole)
+	admin.PUT("/roles/:name", h.RequirePermission(rbac.RolesManage), h.updateRole)
+	admin.DELETE("/roles/:name", h.RequirePermission(rbac.RolesManage), h.deleteRole)
+
+	if missing := openapi.Undocumented(r.Routes()); len(missing) > 0 {
+		slog.Warn("route belum ada di openapi.json", "routes", missing)
+	}
+	return r
+}
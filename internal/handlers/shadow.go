@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"InfoCuy-Backend/internal/shadow"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Route GET yang tidak dicerminkan: punya efek samping (open campaign),
+// memakai kuota provider pihak ketiga, atau berupa stream/unduhan besar
+var shadowSkip = map[string]bool{
+	"/v1/campaigns/open/:token":   true,
+	"/v1/locations/stream":        true,
+	"/v1/locations/export":        true,
+	"/v1/locations/:id/weather":   true,
+	"/v1/geocode/reverse":         true,
+	"/v1/geocode/search":          true,
+	"/v1/suggestions/missing":     true,
+	"/v1/admin/audit-logs/export": true,
+	"/v1/admin/doctor":            true,
+}
+
+// shadowTraffic mencerminkan sampel request GET /v1 ke service bayangan
+// (lihat package shadow). Response asli disalin sambil ditulis ke client,
+// lalu diserahkan ke Mirror setelah handler selesai. Request yang sudah
+// berasal dari Mirror tidak dicerminkan lagi.
+func (h *Handler) shadowTraffic(c *gin.Context) {
+	path := c.FullPath()
+	if c.Request.Method != http.MethodGet || !strings.HasPrefix(path, "/v1/") ||
+		shadowSkip[path] || shadow.FromMirror(c.Request) || !h.Shadow.Sample() {
+		c.Next()
+		return
+	}
+	w := &teeWriter{ResponseWriter: c.Writer}
+	c.Writer = w
+	c.Next()
+
+	header := c.Request.Header.Clone()
+	header.Set("X-Request-ID", c.GetString("request_id"))
+	h.Shadow.Submit(shadow.Request{
+		Method:    c.Request.Method,
+		Path:      c.Request.URL.RequestURI(),
+		Header:    header,
+		Status:    w.Status(),
+		Body:      w.body.Bytes(),
+		Truncated: w.truncated,
+	})
+}
+
+// teeWriter menyalin body response sampai shadow.MaxBody
+type teeWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (w *teeWriter) Write(b []byte) (int, error) {
+	w.copy(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *teeWriter) WriteString(s string) (int, error) {
+	w.copy([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *teeWriter) copy(b []byte) {
+	if w.truncated {
+		return
+	}
+	if w.body.Len()+len(b) > shadow.MaxBody {
+		w.truncated = true
+		return
+	}
+	w.body.Write(b)
+}
@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"InfoCuy-Backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PUBLIC STATS: angka ringkas untuk halaman depan, di-cache 5 menit
+func (h *Handler) publicStats(c *gin.Context) {
+	stats, err := h.Stats.Public(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(services.PublicStatsTTL.Seconds())))
+	c.JSON(http.StatusOK, gin.H{"data": stats})
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// STATUS PAGE (publik): kesehatan komponen, incident dan uptime untuk
+// halaman status. Di-cache services.StatusTTL, CORS terbuka untuk semua
+// origin
+func (h *Handler) statusPage(c *gin.Context) {
+	page := h.Status.Page(c.Request.Context())
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(services.StatusTTL.Seconds())))
+	c.JSON(http.StatusOK, page)
+}
+
+// LIST INCIDENTS (Admin): semua penanda incident termasuk yang sudah lama
+// selesai
+func (h *Handler) listIncidents(c *gin.Context) {
+	incidents, err := h.Status.Incidents(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": incidents})
+}
+
+// CREATE INCIDENT (Admin). Body: {"title", "message", "severity":
+// "minor"|"major"|"maintenance", "components", "starts_at", "resolved_at"}
+func (h *Handler) createIncident(c *gin.Context) {
+	var input models.StatusIncident
+	if !bindJSON(c, &input) {
+		return
+	}
+	incident, err := h.Status.CreateIncident(c.Request.Context(), input, currentUser(c).Email)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Incident ditambahkan", "data": incident})
+}
+
+// UPDATE INCIDENT (Admin): mengganti isi incident; isi resolved_at untuk
+// menandainya selesai
+func (h *Handler) updateIncident(c *gin.Context) {
+	var input models.StatusIncident
+	if !bindJSON(c, &input) {
+		return
+	}
+	incident, err := h.Status.UpdateIncident(c.Request.Context(), c.Param("id"), input, currentUser(c).Email)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Incident diperbarui", "data": incident})
+}
+
+// DELETE INCIDENT (Admin)
+func (h *Handler) deleteIncident(c *gin.Context) {
+	if err := h.Status.DeleteIncident(c.Request.Context(), c.Param("id"), currentUser(c).Email); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Incident dihapus"})
+}
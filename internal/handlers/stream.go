@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Komentar kosong dikirim berkala supaya proxy tidak menutup koneksi diam
+const streamHeartbeat = 25 * time.Second
+
+// LOCATION STREAM (Server-Sent Events)
+// Event created/updated berisi ringkasan lokasi (sama seperti item GET /locations),
+// deleted hanya berisi id. Query: ?category (slug). Setelah reconnect, ambil
+// ulang GET /locations karena event yang terlewat tidak diputar ulang.
+func (h *Handler) streamLocations(c *gin.Context) {
+	client, err := h.Stream.Subscribe(c.Query("category"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer h.Stream.Unsubscribe(client)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Matikan buffering nginx
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	fmt.Fprint(c.Writer, "retry: 5000\n\n")
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	done := c.Request.Context().Done()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-done:
+			return false
+		case ev, ok := <-client.Events:
+			if !ok {
+				return false
+			}
+			data, _ := json.Marshal(ev)
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.Seq, ev.Type, data)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		return true
+	})
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"InfoCuy-Backend/internal/apperr"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/services"
+	"InfoCuy-Backend/internal/units"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SUGGEST CATEGORY (User): usulan kategori baru beserta alasannya, menunggu
+// keputusan admin
+func (h *Handler) suggestCategory(c *gin.Context) {
+	var input models.CategorySuggestionInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	suggestion, err := h.Suggestions.Suggest(c.Request.Context(), currentUser(c), input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Usulan kategori dikirim, Anda akan diberi tahu lewat email setelah diputuskan", "data": suggestion})
+}
+
+// LIST CATEGORY SUGGESTIONS (Admin), yang paling lama diusulkan dulu
+// Query: ?status (default pending; all = semua), ?page, ?limit, ?cursor
+func (h *Handler) listCategorySuggestions(c *gin.Context) {
+	status := c.DefaultQuery("status", models.SuggestionPending)
+	if status == "all" {
+		status = ""
+	}
+	suggestions, meta, err := h.Suggestions.List(c.Request.Context(), status, listOptions(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": suggestions, "meta": meta})
+}
+
+// APPROVE CATEGORY SUGGESTION (Admin), membuat kategori baru. Body opsional
+// seperti POST /categories; name dan slug diambil dari usulan jika kosong
+func (h *Handler) approveCategorySuggestion(c *gin.Context) {
+	id, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var input models.Category
+	if c.Request.ContentLength != 0 && !bindJSON(c, &input) {
+		return
+	}
+	suggestion, err := h.Suggestions.Approve(c.Request.Context(), currentUser(c), id, input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Usulan disetujui, kategori " + suggestion.Category + " ditambahkan", "data": suggestion})
+}
+
+// MERGE CATEGORY SUGGESTION (Admin) ke kategori yang sudah ada.
+// Body: {"category": "<slug>", "note": "..."}
+func (h *Handler) mergeCategorySuggestion(c *gin.Context) {
+	id, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var input struct {
+		Category string `json:"category"`
+		Note     string `json:"note"`
+	}
+	if !bindJSON(c, &input) {
+		return
+	}
+	suggestion, err := h.Suggestions.Merge(c.Request.Context(), currentUser(c), id, input.Category, input.Note)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Usulan digabungkan ke kategori " + suggestion.Category, "data": suggestion})
+}
+
+// REJECT CATEGORY SUGGESTION (Admin), body opsional: {"reason": "..."} dikirim ke pengusul
+func (h *Handler) rejectCategorySuggestion(c *gin.Context) {
+	id, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var input struct {
+		Reason string `json:"reason"`
+	}
+	if c.Request.ContentLength != 0 && !bindJSON(c, &input) {
+		return
+	}
+	suggestion, err := h.Suggestions.Reject(c.Request.Context(), currentUser(c), id, input.Reason)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Usulan kategori ditolak", "data": suggestion})
+}
+
+// MISSING POIs (Kontributor): POI OpenStreetMap di sekitar titik yang
+// belum ada di data kita, beserta payload POST /locations yang sudah diisi
+// Query: ?lat, ?lng (wajib), ?radius (meter, default 1000), ?category, ?limit
+func (h *Handler) missingPOIs(c *gin.Context) {
+	lat, errLat := strconv.ParseFloat(c.Query("lat"), 64)
+	lng, errLng := strconv.ParseFloat(c.Query("lng"), 64)
+	if errLat != nil || errLng != nil {
+		respondError(c, apperr.BadRequest("lat dan lng wajib diisi dengan koordinat yang valid"))
+		return
+	}
+	var radius float64
+	if r := c.Query("radius"); r != "" {
+		v, err := strconv.ParseFloat(r, 64)
+		if err != nil || v <= 0 {
+			respondError(c, apperr.BadRequest("radius harus angka positif (meter)"))
+			return
+		}
+		radius = v
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	missing, radius, err := h.MissingPOIs.Missing(c.Request.Context(), services.MissingParams{
+		Lat:      lat,
+		Lng:      lng,
+		RadiusM:  radius,
+		Category: c.Query("category"),
+		Limit:    limit,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	sys := h.resolveUnits(c)
+	for i := range missing {
+		missing[i].Distance = units.FromMeters(missing[i].DistanceM, sys)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":   missing,
+		"count":  len(missing),
+		"radius": units.FromMeters(radius, sys),
+		"units":  sys,
+	})
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"InfoCuy-Backend/internal/apperr"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/services"
+	"InfoCuy-Backend/internal/units"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Batas ukuran zip GTFS
+const maxTransitImportBytes = 100 << 20
+
+// LOCATION TRANSIT, halte & rute terdekat untuk panel "cara ke sini"
+// Query: ?radius (meter, default 500), ?limit, ?units
+func (h *Handler) locationTransit(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var radius float64
+	if r := c.Query("radius"); r != "" {
+		v, err := strconv.ParseFloat(r, 64)
+		if err != nil || v <= 0 {
+			respondError(c, apperr.BadRequest("radius harus angka positif (meter)"))
+			return
+		}
+		radius = v
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	stops, radius, err := h.Transit.NearLocation(c.Request.Context(), objID, services.TransitParams{RadiusM: radius, Limit: limit})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	sys := h.resolveUnits(c)
+	for i := range stops {
+		stops[i].Distance = units.FromMeters(stops[i].DistanceM, sys)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":   stops,
+		"count":  len(stops),
+		"radius": units.FromMeters(radius, sys),
+		"units":  sys,
+	})
+}
+
+// IMPORT GTFS (Admin), multipart: file=zip GTFS, feed=nama feed (default "default")
+func (h *Handler) importTransit(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxTransitImportBytes)
+	fh, err := c.FormFile("file")
+	if err != nil {
+		respondError(c, apperr.BadRequest("File zip GTFS wajib diupload (field: file, maks 100MB)"))
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer f.Close()
+	result, err := h.Transit.Import(c.Request.Context(), c.DefaultPostForm("feed", "default"), f, fh.Size)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Data transit di-import", "data": result})
+}
+
+// LIST TRANSIT SOURCES (Admin), feed GTFS partner yang diambil berkala
+func (h *Handler) listTransitSources(c *gin.Context) {
+	sources, err := h.Transit.Sources(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": sources})
+}
+
+// PUT TRANSIT SOURCE (Admin), daftarkan/ubah URL feed GTFS partner
+func (h *Handler) putTransitSource(c *gin.Context) {
+	var in models.TransitSourceInput
+	if !bindJSON(c, &in) {
+		return
+	}
+	source, err := h.Transit.PutSource(c.Request.Context(), currentUser(c), c.Param("feed"), in)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Sumber feed disimpan", "data": source})
+}
+
+// DELETE TRANSIT SOURCE (Admin), halte yang sudah di-import tidak dihapus
+func (h *Handler) deleteTransitSource(c *gin.Context) {
+	if err := h.Transit.DeleteSource(c.Request.Context(), currentUser(c), c.Param("feed")); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Sumber feed dihapus"})
+}
+
+// TRANSIT SYNC JOB (Admin), ambil feed yang jatuh tempo dengan conditional
+// GET. ?force=true mengambil semua feed; panggil ulang sampai remaining 0.
+func (h *Handler) syncTransitFeeds(c *gin.Context) {
+	batch, _ := strconv.Atoi(c.Query("batch"))
+	result, err := h.Transit.Sync(c.Request.Context(), currentUser(c), batch, c.Query("force") == "true")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
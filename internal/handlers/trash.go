@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TRASH LOCATIONS
+// Lokasi yang dihapus, terbaru dulu. Admin melihat semua, user lain hanya miliknya.
+// Query: ?page, ?limit, ?cursor
+func (h *Handler) listTrash(c *gin.Context) {
+	locations, meta, err := h.Locations.Trash(c.Request.Context(), currentUser(c), listOptions(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": locations, "meta": meta})
+}
+
+// RESTORE LOCATION dari trash (pemilik atau admin)
+func (h *Handler) restoreLocation(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	loc, err := h.Locations.Restore(c.Request.Context(), currentUser(c), objID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Data dipulihkan", "data": loc})
+}
+
+// HARD DELETE LOCATION (Admin), tidak bisa dipulihkan
+func (h *Handler) hardDeleteLocation(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	deleted, err := h.Locations.HardDelete(c.Request.Context(), currentUser(c), objID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.emitSecurityEvent(c, "location.purge", 6, currentUser(c).Email, "", "success",
+		map[string]string{"location_id": objID.Hex(), "name": deleted.Name})
+	c.JSON(http.StatusOK, gin.H{"message": "Data dihapus permanen"})
+}
+
+// PURGE TRASH (Admin/cron)
+// Menghapus permanen satu batch lokasi yang lebih lama dari LOCATION_TRASH_RETENTION.
+// Query: ?batch (default 100, maks 500); panggil ulang selama remaining > 0
+func (h *Handler) purgeTrash(c *gin.Context) {
+	batch, _ := strconv.Atoi(c.Query("batch"))
+	result, err := h.Locations.PurgeTrash(c.Request.Context(), batch)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"InfoCuy-Backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PRESIGN UPLOAD: URL untuk upload foto besar langsung ke object storage.
+// Body: {"location_id", "content_type", "size_bytes"}
+func (h *Handler) presignUpload(c *gin.Context) {
+	var input models.PresignInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	upload, err := h.Locations.PresignPhoto(c.Request.Context(), currentUser(c), input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": upload})
+}
+
+// CONFIRM UPLOAD: periksa file yang sudah diupload lewat URL presigned lalu
+// tambahkan sebagai foto lokasi. Body: {"location_id", "upload_id"}
+func (h *Handler) confirmUpload(c *gin.Context) {
+	var input models.ConfirmUploadInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	photo, err := h.Locations.ConfirmPhoto(c.Request.Context(), currentUser(c), input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Foto ditambahkan!", "data": photo})
+}
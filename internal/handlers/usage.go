@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"InfoCuy-Backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API USAGE (Admin), pemakaian API per consumer (user login atau IP anonim)
+// Query: ?from, ?to (YYYY-MM-DD WIB, default 7 hari terakhir), ?consumer, ?limit, ?format=csv
+func (h *Handler) usageReport(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	p := services.UsageParams{From: c.Query("from"), To: c.Query("to"), Consumer: c.Query("consumer"), Limit: limit}
+	if c.Query("format") == "csv" {
+		h.exportUsage(c, p)
+		return
+	}
+	report, err := h.Usage.Report(c.Request.Context(), p)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+func (h *Handler) exportUsage(c *gin.Context, p services.UsageParams) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="api-usage.csv"`)
+	c.Status(http.StatusOK)
+	err := h.Usage.ExportCSV(c.Request.Context(), p, c.Writer)
+	if err == nil {
+		return
+	}
+	if !c.Writer.Written() {
+		c.Writer.Header().Del("Content-Disposition")
+		respondError(c, err)
+		return
+	}
+	log.Println("export pemakaian API:", err)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/units"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 7. GET USERS (Admin)
+func (h *Handler) listUsers(c *gin.Context) {
+	users, err := h.Users.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, users)
+}
+
+// 8. UPDATE USER ROLE (Admin)
+func (h *Handler) updateUserRole(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var input models.RoleInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	before, err := h.Users.SetRole(c.Request.Context(), objID, input.Role)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.emitSecurityEvent(c, "user.role_change", 7, currentUser(c).Email, before.Email, "success",
+		map[string]string{"old_role": before.Role, "new_role": input.Role})
+	c.JSON(http.StatusOK, gin.H{"message": "Role diubah"})
+}
+
+// 9. DELETE USER (Admin)
+func (h *Handler) deleteUser(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	deleted, err := h.Users.Delete(c.Request.Context(), objID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.emitSecurityEvent(c, "user.delete", 7, currentUser(c).Email, deleted.Email, "success",
+		map[string]string{"user_id": objID.Hex()})
+	c.JSON(http.StatusOK, gin.H{"message": "User dihapus"})
+}
+
+// GET PREFERENCES
+func (h *Handler) getPreferences(c *gin.Context) {
+	prefs := currentUser(c).Preferences
+	if prefs.Units == "" {
+		prefs.Units = string(units.Metric)
+	}
+	c.JSON(http.StatusOK, prefs)
+}
+
+// UPDATE PREFERENCES
+func (h *Handler) updatePreferences(c *gin.Context) {
+	var input models.Preferences
+	if !bindJSON(c, &input) {
+		return
+	}
+	prefs, queued, err := h.Users.UpdatePreferences(c.Request.Context(), currentUser(c), input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if queued {
+		c.JSON(http.StatusAccepted, gin.H{"message": queuedMessage, "data": prefs, "queued": true})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Preferensi disimpan", "data": prefs})
+}
+
+// MY QUOTA
+func (h *Handler) myQuota(c *gin.Context) {
+	usage, err := h.Locations.QuotaUsage(c.Request.Context(), currentUser(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, usage)
+}
+
+// UPDATE USER QUOTA OVERRIDE (Admin), location_quota=null menghapus override
+func (h *Handler) updateUserQuota(c *gin.Context) {
+	objID, ok := objectIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var input models.QuotaOverrideInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	if err := h.Users.SetLocationQuota(c.Request.Context(), objID, input.LocationQuota); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Kuota user diubah"})
+}
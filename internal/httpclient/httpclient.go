@@ -0,0 +1,365 @@
+// Package httpclient adalah lapisan bersama untuk panggilan ke layanan
+// pihak ketiga (map-matching, elevation, cuaca, object storage, SIEM, SMTP)
+// supaya satu provider yang lambat tidak ikut menahan request user. Setiap
+// provider mendapat Client sendiri dengan:
+//
+//   - timeout per percobaan
+//   - retry dengan backoff untuk error jaringan dan status 5xx, dibatasi
+//     retry budget (ulangan maksimal ~20% dari jumlah request) supaya
+//     provider yang sedang bermasalah tidak dibanjiri ulangan
+//   - circuit breaker: setelah beberapa kegagalan beruntun panggilan
+//     langsung ditolak dengan ErrCircuitOpen selama cooldown
+//   - metrik di /metrics: outbound_requests_total{provider,outcome} dan
+//     outbound_request_seconds_total{provider}
+//
+// Nilai default tiap provider bisa diubah lewat environment, dengan NAME
+// adalah nama provider dalam huruf besar (mis. OSRM, OPEN_ELEVATION):
+//
+//	OUTBOUND_<NAME>_TIMEOUT            mis. 3s
+//	OUTBOUND_<NAME>_RETRIES            ulangan maksimal, 0 = tanpa retry
+//	OUTBOUND_<NAME>_BREAKER_FAILURES   kegagalan beruntun sebelum circuit terbuka, 0 = nonaktif
+//	OUTBOUND_<NAME>_BREAKER_COOLDOWN   lama circuit terbuka, mis. 30s
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"InfoCuy-Backend/internal/chaos"
+	"InfoCuy-Backend/internal/metrics"
+)
+
+// ErrCircuitOpen dikembalikan tanpa memanggil provider selama circuit
+// breaker terbuka.
+var ErrCircuitOpen = errors.New("provider sedang tidak tersedia")
+
+var (
+	requestsTotal = metrics.NewCounterVec("outbound_requests_total",
+		"Panggilan ke layanan eksternal per provider dan hasil (ok, error, retry, circuit_open, canceled)",
+		"provider", "outcome")
+	secondsTotal = metrics.NewCounterVec("outbound_request_seconds_total",
+		"Total durasi panggilan ke layanan eksternal dalam detik", "provider")
+)
+
+// Options adalah perilaku Client untuk satu provider.
+type Options struct {
+	// Batas waktu satu percobaan (default 10s)
+	Timeout time.Duration
+	// Ulangan maksimal setelah percobaan pertama gagal
+	Retries int
+	// Jeda sebelum ulangan pertama, berlipat dua setiap ulangan (default 200ms)
+	Backoff time.Duration
+	// Kegagalan beruntun sebelum circuit terbuka (default 5, negatif = nonaktif)
+	BreakerFailures int
+	// Lama circuit terbuka (default 30s)
+	BreakerCooldown time.Duration
+	// RetryUnsafe mengizinkan retry untuk POST/PATCH yang aman diulang
+	// (mis. lookup yang tidak mengubah apa pun di provider)
+	RetryUnsafe bool
+}
+
+const (
+	defaultTimeout         = 10 * time.Second
+	defaultBackoff         = 200 * time.Millisecond
+	defaultBreakerFailures = 5
+	defaultBreakerCooldown = 30 * time.Second
+)
+
+// Client membungkus http.Client untuk satu provider.
+type Client struct {
+	name    string
+	opts    Options
+	client  *http.Client
+	breaker *breaker
+	budget  *budget
+}
+
+// New membuat Client dengan default opts yang bisa ditimpa environment.
+func New(name string, opts Options) *Client {
+	opts = withEnv(name, opts)
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = defaultBackoff
+	}
+	if opts.BreakerFailures == 0 {
+		opts.BreakerFailures = defaultBreakerFailures
+	}
+	if opts.BreakerCooldown <= 0 {
+		opts.BreakerCooldown = defaultBreakerCooldown
+	}
+	c := &Client{
+		name:    name,
+		opts:    opts,
+		client:  &http.Client{Timeout: opts.Timeout, Transport: chaos.Transport(name, nil)},
+		breaker: &breaker{name: name, threshold: opts.BreakerFailures, cooldown: opts.BreakerCooldown},
+		budget:  newBudget(),
+	}
+	registry.Lock()
+	registry.clients[name] = c
+	registry.Unlock()
+	return c
+}
+
+// registry menyimpan Client terakhir per provider untuk Providers.
+var registry = struct {
+	sync.Mutex
+	clients map[string]*Client
+}{clients: map[string]*Client{}}
+
+// ProviderState adalah keadaan circuit breaker satu provider.
+type ProviderState struct {
+	Name string `json:"name"`
+	// Kegagalan beruntun sejak panggilan terakhir yang berhasil
+	Failures int `json:"failures"`
+	// Circuit terbuka: panggilan sedang ditolak tanpa menghubungi provider
+	Open bool `json:"open"`
+}
+
+// Providers mengembalikan keadaan breaker semua provider yang dipakai
+// proses ini, urut nama, tanpa memanggil provider.
+func Providers() []ProviderState {
+	registry.Lock()
+	clients := make([]*Client, 0, len(registry.clients))
+	for _, c := range registry.clients {
+		clients = append(clients, c)
+	}
+	registry.Unlock()
+	sort.Slice(clients, func(i, j int) bool { return clients[i].name < clients[j].name })
+	now := time.Now()
+	states := make([]ProviderState, len(clients))
+	for i, c := range clients {
+		failures, open := c.breaker.state(now)
+		states[i] = ProviderState{Name: c.name, Failures: failures, Open: open}
+	}
+	return states
+}
+
+func withEnv(name string, opts Options) Options {
+	prefix := "OUTBOUND_" + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name)) + "_"
+	if v, err := time.ParseDuration(os.Getenv(prefix + "TIMEOUT")); err == nil && v > 0 {
+		opts.Timeout = v
+	}
+	if v, err := strconv.Atoi(os.Getenv(prefix + "RETRIES")); err == nil && v >= 0 {
+		opts.Retries = v
+	}
+	if v, err := strconv.Atoi(os.Getenv(prefix + "BREAKER_FAILURES")); err == nil {
+		// 0 lewat env berarti breaker dimatikan
+		opts.BreakerFailures = v
+		if v == 0 {
+			opts.BreakerFailures = -1
+		}
+	}
+	if v, err := time.ParseDuration(os.Getenv(prefix + "BREAKER_COOLDOWN")); err == nil && v > 0 {
+		opts.BreakerCooldown = v
+	}
+	return opts
+}
+
+// Name adalah nama provider (label metrik).
+func (c *Client) Name() string { return c.name }
+
+// Timeout adalah batas waktu satu percobaan.
+func (c *Client) Timeout() time.Duration { return c.opts.Timeout }
+
+// Do mengirim request dengan retry dan circuit breaker. Response 5xx yang
+// tetap gagal setelah semua ulangan dikembalikan apa adanya (err nil)
+// supaya pemanggil bisa membaca status/isinya.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if !c.breaker.allow(time.Now()) {
+		requestsTotal.Inc(c.name, "circuit_open")
+		return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, c.name)
+	}
+	c.budget.deposit()
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if err := c.rewind(req); err != nil {
+				return nil, err
+			}
+		}
+		start := time.Now()
+		resp, err := c.client.Do(req)
+		secondsTotal.Add(time.Since(start).Seconds(), c.name)
+		if ctx.Err() != nil {
+			// Dibatalkan pemanggil, bukan kesalahan provider
+			requestsTotal.Inc(c.name, "canceled")
+			return resp, err
+		}
+		if err == nil && resp.StatusCode < 500 {
+			c.breaker.success()
+			requestsTotal.Inc(c.name, "ok")
+			return resp, nil
+		}
+		c.breaker.failure(time.Now())
+		if attempt >= c.opts.Retries || !c.retryable(req) || !c.budget.withdraw() {
+			requestsTotal.Inc(c.name, "error")
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+		requestsTotal.Inc(c.name, "retry")
+		if err := sleep(ctx, c.opts.Backoff<<attempt); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Run menjalankan panggilan non-HTTP (mis. SMTP) dengan timeout, circuit
+// breaker dan metrik yang sama, tanpa retry. fn wajib menghormati ctx.
+func (c *Client) Run(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !c.breaker.allow(time.Now()) {
+		requestsTotal.Inc(c.name, "circuit_open")
+		return fmt.Errorf("%w: %s", ErrCircuitOpen, c.name)
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+	defer cancel()
+	start := time.Now()
+	err := chaos.Provider(ctx, c.name)
+	if err == nil {
+		err = fn(ctx)
+	}
+	secondsTotal.Add(time.Since(start).Seconds(), c.name)
+	if err != nil {
+		c.breaker.failure(time.Now())
+		requestsTotal.Inc(c.name, "error")
+		return err
+	}
+	c.breaker.success()
+	requestsTotal.Inc(c.name, "ok")
+	return nil
+}
+
+func (c *Client) retryable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+	default:
+		if !c.opts.RetryUnsafe {
+			return false
+		}
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind menyiapkan body untuk percobaan berikutnya.
+func (c *Client) rewind(req *http.Request) error {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+	return nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// breaker terbuka setelah threshold kegagalan beruntun. Setelah cooldown
+// request kembali dilewatkan; satu kegagalan lagi langsung membukanya
+// kembali, satu keberhasilan menutupnya.
+type breaker struct {
+	name      string
+	threshold int // <0 = nonaktif
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+func (b *breaker) allow(now time.Time) bool {
+	if b.threshold < 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !now.Before(b.openUntil)
+}
+
+func (b *breaker) state(now time.Time) (failures int, open bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures, b.threshold >= 0 && now.Before(b.openUntil)
+}
+
+func (b *breaker) failure(now time.Time) {
+	if b.threshold < 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.failures >= b.threshold {
+		if !now.Before(b.openUntil) {
+			log.Printf("Warning: %s gagal %d kali beruntun, circuit dibuka selama %s", b.name, b.failures, b.cooldown)
+		}
+		b.openUntil = now.Add(b.cooldown)
+	}
+}
+
+func (b *breaker) success() {
+	if b.threshold < 0 {
+		return
+	}
+	b.mu.Lock()
+	b.failures = 0
+	b.openUntil = time.Time{}
+	b.mu.Unlock()
+}
+
+// budget membatasi retry: setiap request menabung retryRatio token, setiap
+// retry memakai satu token.
+type budget struct {
+	mu     sync.Mutex
+	tokens float64
+}
+
+const (
+	retryRatio     = 0.2
+	maxRetryTokens = 10
+)
+
+func newBudget() *budget { return &budget{tokens: maxRetryTokens} }
+
+func (b *budget) deposit() {
+	b.mu.Lock()
+	b.tokens += retryRatio
+	if b.tokens > maxRetryTokens {
+		b.tokens = maxRetryTokens
+	}
+	b.mu.Unlock()
+}
+
+func (b *budget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
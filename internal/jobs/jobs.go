@@ -0,0 +1,190 @@
+// Package jobs membagi kapasitas kerja latar belakang satu instance
+// (pengiriman email, webhook, import dan backfill) antar jenis pekerjaan.
+//
+// Setiap jenis (Class) punya prioritas dan batas konkurensi sendiri. Slot
+// yang kosong selalu diberikan ke antrean berprioritas tertinggi yang
+// belum mencapai batasnya, dengan urutan FIFO di dalam satu jenis. Karena
+// batas backfill lebih kecil dari jumlah worker, import 100 ribu baris
+// tidak pernah menghabiskan slot yang dibutuhkan email reset password.
+//
+// Antrean disimpan per instance. Batas diatur lewat package config
+// (JOB_WORKERS, JOB_*_CONCURRENCY).
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"InfoCuy-Backend/internal/metrics"
+)
+
+// Class adalah jenis pekerjaan.
+type Class string
+
+const (
+	// Email ke satu user yang sedang menunggu, mis. reset password
+	Email Class = "email"
+	// POST webhook ke sistem lain, termasuk ulangannya
+	Webhook Class = "webhook"
+	// Import, backfill, job batch dan email massal (campaign, alert, export)
+	Backfill Class = "backfill"
+)
+
+// Classes diurutkan dari prioritas tertinggi.
+var Classes = []Class{Email, Webhook, Backfill}
+
+var (
+	startedTotal = metrics.NewCounterVec("jobs_started_total",
+		"Pekerjaan latar belakang yang mendapat slot, per jenis", "class")
+	waitSecondsTotal = metrics.NewCounterVec("jobs_wait_seconds_total",
+		"Total waktu menunggu slot dalam detik, per jenis", "class")
+)
+
+// Limits adalah kapasitas antrean. Caps yang tidak diisi atau melebihi
+// Workers dianggap sama dengan Workers.
+type Limits struct {
+	// Pekerjaan yang boleh berjalan bersamaan, semua jenis digabung
+	Workers int `json:"workers"`
+	// Batas per jenis
+	Caps map[Class]int `json:"caps"`
+}
+
+// Nilai default: backfill paling banyak memakai 2 dari 8 slot
+const (
+	DefaultWorkers     = 8
+	DefaultWebhookCap  = 4
+	DefaultBackfillCap = 2
+)
+
+// Stats adalah keadaan satu jenis pekerjaan saat ini.
+type Stats struct {
+	Running int `json:"running"`
+	Waiting int `json:"waiting"`
+	Cap     int `json:"cap"`
+}
+
+// Queue membatasi dan mengurutkan pekerjaan. Nilai nil berarti tanpa
+// batas.
+type Queue struct {
+	limits Limits
+
+	mu      sync.Mutex
+	total   int
+	running map[Class]int
+	waiting map[Class][]chan struct{}
+}
+
+// New membuat antrean; workers <= 0 memakai DefaultWorkers.
+func New(limits Limits) *Queue {
+	if limits.Workers <= 0 {
+		limits.Workers = DefaultWorkers
+	}
+	caps := make(map[Class]int, len(Classes))
+	for _, class := range Classes {
+		n := limits.Caps[class]
+		if n <= 0 || n > limits.Workers {
+			n = limits.Workers
+		}
+		caps[class] = n
+	}
+	limits.Caps = caps
+	return &Queue{limits: limits, running: map[Class]int{}, waiting: map[Class][]chan struct{}{}}
+}
+
+// Limits mengembalikan kapasitas yang berlaku setelah default diterapkan.
+func (q *Queue) Limits() Limits {
+	if q == nil {
+		return Limits{}
+	}
+	return q.limits
+}
+
+// Acquire menunggu slot untuk class sampai ctx selesai. release wajib
+// dipanggil setelah pekerjaan selesai; memanggilnya lebih dari sekali
+// aman.
+func (q *Queue) Acquire(ctx context.Context, class Class) (release func(), err error) {
+	if q == nil {
+		return func() {}, nil
+	}
+	start := time.Now()
+	ready := make(chan struct{})
+	q.mu.Lock()
+	q.waiting[class] = append(q.waiting[class], ready)
+	q.dispatch()
+	q.mu.Unlock()
+
+	select {
+	case <-ready:
+	case <-ctx.Done():
+		q.mu.Lock()
+		if q.remove(class, ready) {
+			q.mu.Unlock()
+			return nil, ctx.Err()
+		}
+		q.mu.Unlock()
+		// Slot sudah diberikan bersamaan dengan ctx selesai; kembalikan
+		q.release(class)
+		return nil, ctx.Err()
+	}
+	startedTotal.Inc(string(class))
+	waitSecondsTotal.Add(time.Since(start).Seconds(), string(class))
+	var once sync.Once
+	return func() { once.Do(func() { q.release(class) }) }, nil
+}
+
+// Stats mengembalikan keadaan setiap jenis pekerjaan.
+func (q *Queue) Stats() map[Class]Stats {
+	stats := make(map[Class]Stats, len(Classes))
+	if q == nil {
+		return stats
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, class := range Classes {
+		stats[class] = Stats{Running: q.running[class], Waiting: len(q.waiting[class]), Cap: q.limits.Caps[class]}
+	}
+	return stats
+}
+
+func (q *Queue) release(class Class) {
+	q.mu.Lock()
+	q.total--
+	q.running[class]--
+	q.dispatch()
+	q.mu.Unlock()
+}
+
+// dispatch membagikan slot kosong ke antrean berprioritas tertinggi yang
+// masih di bawah batasnya. Dipanggil dengan mu terkunci.
+func (q *Queue) dispatch() {
+	for q.total < q.limits.Workers {
+		granted := false
+		for _, class := range Classes {
+			if len(q.waiting[class]) == 0 || q.running[class] >= q.limits.Caps[class] {
+				continue
+			}
+			ready := q.waiting[class][0]
+			q.waiting[class] = q.waiting[class][1:]
+			q.total++
+			q.running[class]++
+			close(ready)
+			granted = true
+			break
+		}
+		if !granted {
+			return
+		}
+	}
+}
+
+// remove mengeluarkan ready dari antrean; false jika sudah mendapat slot.
+func (q *Queue) remove(class Class, ready chan struct{}) bool {
+	for i, w := range q.waiting[class] {
+		if w == ready {
+			q.waiting[class] = append(q.waiting[class][:i:i], q.waiting[class][i+1:]...)
+			return true
+		}
+	}
+	return false
+}
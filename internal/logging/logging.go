@@ -0,0 +1,110 @@
+// Package logging menyiapkan log terstruktur (log/slog) untuk seluruh
+// aplikasi. Setelah Setup, output package log standar ikut diteruskan ke
+// slog sehingga semua baris log berformat sama.
+//
+// Level dan format diatur lewat LOG_LEVEL dan LOG_FORMAT (package config).
+// Level logger default bisa diganti saat berjalan dengan SetLevel.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+type ctxKey struct{}
+
+// Level logger default (Setup); diubah oleh SetLevel
+var level slog.LevelVar
+
+// WithRequestID menyimpan request ID di context. Semua log yang memakai
+// context ini (termasuk operasi Mongo) otomatis menyertakan request_id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// RequestID mengembalikan request ID dari context, atau "" jika tidak ada.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// NewRequestID membuat ID acak 16 karakter hex.
+func NewRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ValidRequestID menerima ID dari header X-Request-ID hanya jika pendek dan
+// berisi karakter aman, supaya tidak bisa dipakai menyisipkan isi log.
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// Setup memasang logger default ke stdout.
+func Setup(lvl, format string) {
+	SetLevel(lvl)
+	slog.SetDefault(newLogger(os.Stdout, &level, format))
+}
+
+// SetLevel mengganti level logger default tanpa membuat ulang logger.
+func SetLevel(lvl string) {
+	level.Set(parseLevel(lvl))
+}
+
+// New membuat logger; level dan format kosong/tidak dikenal memakai default.
+func New(w io.Writer, lvl, format string) *slog.Logger {
+	return newLogger(w, parseLevel(lvl), format)
+}
+
+func parseLevel(s string) slog.Level {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(s)); err != nil {
+		return slog.LevelInfo
+	}
+	return lvl
+}
+
+func newLogger(w io.Writer, lvl slog.Leveler, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: lvl}
+	var h slog.Handler
+	if strings.EqualFold(format, "text") {
+		h = slog.NewTextHandler(w, opts)
+	} else {
+		h = slog.NewJSONHandler(w, opts)
+	}
+	return slog.New(contextHandler{h})
+}
+
+// contextHandler menambahkan request_id dari context ke setiap record.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,211 @@
+// Package mailer mengirim email transaksional lewat salah satu provider:
+// SMTP, SendGrid atau Mailgun (API HTTP, tanpa SDK). Semua provider
+// memenuhi interface Mailer sehingga service tidak perlu tahu provider
+// mana yang dipakai. Jika provider belum dikonfigurasi, isi email hanya
+// ditulis ke log (mode development).
+//
+// Konfigurasi lewat environment:
+//
+//	MAIL_PROVIDER       smtp | sendgrid | mailgun (default smtp)
+//	MAIL_FROM           alamat pengirim, default no-reply@infocuy.local
+//
+//	SMTP_HOST           kosong = email hanya di-log
+//	SMTP_PORT           default 587
+//	SMTP_USER, SMTP_PASS
+//
+//	SENDGRID_API_KEY    wajib untuk sendgrid
+//	SENDGRID_BASE_URL   default https://api.sendgrid.com
+//
+//	MAILGUN_API_KEY     wajib untuk mailgun
+//	MAILGUN_DOMAIN      domain pengirim, wajib untuk mailgun
+//	MAILGUN_BASE_URL    default https://api.mailgun.net (region EU: https://api.eu.mailgun.net)
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/textproto"
+	"os"
+	"strings"
+
+	"InfoCuy-Backend/internal/jobs"
+)
+
+// Message adalah email plain text. Jika HTML diisi, email dikirim sebagai
+// multipart/alternative dengan Body sebagai versi teks. Attachments
+// membungkus isi tersebut dalam multipart/mixed.
+type Message struct {
+	To          string
+	Subject     string
+	Body        string
+	HTML        string
+	Attachments []Attachment
+	// Bulk menandai email massal (campaign, alert, export) yang mengantre
+	// di belakang email yang sedang ditunggu user.
+	Bulk bool
+}
+
+// Attachment adalah file lampiran, dikirim dalam base64.
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Mailer mengirim email lewat satu provider.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+	// Configured bernilai false jika email hanya ditulis ke log.
+	Configured() bool
+	// Ping memeriksa koneksi dan kredensial provider tanpa mengirim email.
+	Ping(ctx context.Context) error
+	Name() string
+}
+
+const defaultFrom = "no-reply@infocuy.local"
+
+// NewFromEnv memilih provider sesuai MAIL_PROVIDER. Provider yang tidak
+// dikenal atau belum lengkap konfigurasinya diganti mode log.
+func NewFromEnv() Mailer {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = defaultFrom
+	}
+	switch provider := strings.ToLower(os.Getenv("MAIL_PROVIDER")); provider {
+	case "", "smtp":
+		if os.Getenv("SMTP_HOST") == "" {
+			return newLogMailer("SMTP_HOST kosong")
+		}
+		return NewSMTP(os.Getenv("SMTP_HOST"), os.Getenv("SMTP_PORT"), os.Getenv("SMTP_USER"), os.Getenv("SMTP_PASS"), from)
+	case "sendgrid":
+		key := os.Getenv("SENDGRID_API_KEY")
+		if key == "" {
+			log.Println("Warning: SENDGRID_API_KEY kosong, email hanya ditulis ke log")
+			return newLogMailer("SENDGRID_API_KEY kosong")
+		}
+		return NewSendGrid(os.Getenv("SENDGRID_BASE_URL"), key, from)
+	case "mailgun":
+		key, domain := os.Getenv("MAILGUN_API_KEY"), os.Getenv("MAILGUN_DOMAIN")
+		if key == "" || domain == "" {
+			log.Println("Warning: MAILGUN_API_KEY dan MAILGUN_DOMAIN wajib diisi, email hanya ditulis ke log")
+			return newLogMailer("MAILGUN_API_KEY/MAILGUN_DOMAIN kosong")
+		}
+		return NewMailgun(os.Getenv("MAILGUN_BASE_URL"), domain, key, from)
+	default:
+		log.Println("Warning: MAIL_PROVIDER tidak didukung:", provider)
+		return newLogMailer("MAIL_PROVIDER " + provider + " tidak didukung")
+	}
+}
+
+// logMailer menulis email ke log tanpa mengirimnya. Isi email (berisi
+// token reset password dan token laporan sesi) hanya ditulis jika
+// MAIL_LOG_BODY=true, untuk development lokal; selain itu cukup penerima
+// dan subjek supaya token tidak masuk agregasi log.
+type logMailer struct {
+	reason string
+	body   bool
+}
+
+// NewLog membuat Mailer yang hanya menulis email ke log.
+func NewLog(reason string) Mailer { return newLogMailer(reason) }
+
+func newLogMailer(reason string) logMailer {
+	return logMailer{reason: reason, body: os.Getenv("MAIL_LOG_BODY") == "true"}
+}
+
+func (m logMailer) Name() string { return "log" }
+
+func (m logMailer) Configured() bool { return false }
+
+func (m logMailer) Ping(ctx context.Context) error {
+	return errors.New("mailer: " + m.reason)
+}
+
+func (m logMailer) Send(ctx context.Context, msg Message) error {
+	if !m.body {
+		log.Printf("mailer: %s, email ke %s tidak dikirim (Subject: %s, %d byte; MAIL_LOG_BODY=true untuk menampilkan isi)",
+			m.reason, msg.To, msg.Subject, len(msg.Body))
+	} else {
+		log.Printf("mailer: %s, email ke %s tidak dikirim\nSubject: %s\n%s", m.reason, msg.To, msg.Subject, msg.Body)
+	}
+	for _, a := range msg.Attachments {
+		log.Printf("mailer: lampiran %s (%d byte) tidak dikirim", a.Name, len(a.Data))
+	}
+	return nil
+}
+
+// queuedMailer menunggu slot antrean jobs sebelum memanggil provider.
+type queuedMailer struct {
+	Mailer
+	queue *jobs.Queue
+}
+
+// Queued membuat setiap pengiriman m menunggu slot di q: jobs.Email untuk
+// email biasa, jobs.Backfill untuk Message.Bulk. Mode log tidak mengantre.
+func Queued(m Mailer, q *jobs.Queue) Mailer {
+	return queuedMailer{Mailer: m, queue: q}
+}
+
+func (m queuedMailer) Send(ctx context.Context, msg Message) error {
+	if !m.Configured() {
+		return m.Mailer.Send(ctx, msg)
+	}
+	class := jobs.Email
+	if msg.Bulk {
+		class = jobs.Backfill
+	}
+	release, err := m.queue.Acquire(ctx, class)
+	if err != nil {
+		return fmt.Errorf("mailer: %w", err)
+	}
+	defer release()
+	return m.Mailer.Send(ctx, msg)
+}
+
+// StatusError adalah penolakan dari API provider (SendGrid, Mailgun).
+// Status 4xx selain 429 berarti email tidak akan berhasil jika diulang
+// tanpa perubahan.
+type StatusError struct {
+	Provider string
+	Status   int
+	Body     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s membalas %d: %s", e.Provider, e.Status, e.Body)
+}
+
+// Rejected bernilai true jika provider menolak email ini secara permanen
+// (SMTP 5xx, atau 4xx dari API selain masalah kredensial dan rate limit),
+// sehingga mengulang tanpa perubahan tidak ada gunanya.
+func Rejected(err error) bool {
+	var smtpErr *textproto.Error
+	if errors.As(err, &smtpErr) {
+		return smtpErr.Code >= 500
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.Status {
+		case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+			return false
+		}
+		return statusErr.Status >= 400 && statusErr.Status < 500
+	}
+	return false
+}
+
+// checkResponse menutup resp dan mengubah status di luar 2xx menjadi
+// *StatusError.
+func checkResponse(provider string, resp *http.Response) error {
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return &StatusError{Provider: provider, Status: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+}
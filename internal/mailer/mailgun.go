@@ -0,0 +1,95 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/httpclient"
+)
+
+type mailgunMailer struct {
+	baseURL string
+	domain  string
+	apiKey  string
+	from    string
+	client  *httpclient.Client
+}
+
+// NewMailgun membuat Mailer lewat Mailgun Messages API. baseURL kosong =
+// https://api.mailgun.net (region US).
+func NewMailgun(baseURL, domain, apiKey, from string) Mailer {
+	if baseURL == "" {
+		baseURL = "https://api.mailgun.net"
+	}
+	return &mailgunMailer{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		domain:  domain,
+		apiKey:  apiKey,
+		from:    from,
+		// POST tidak diulang otomatis supaya email tidak terkirim dua kali
+		client: httpclient.New("mailgun", httpclient.Options{Timeout: 15 * time.Second}),
+	}
+}
+
+func (m *mailgunMailer) Name() string { return "mailgun" }
+
+func (m *mailgunMailer) Configured() bool { return true }
+
+func (m *mailgunMailer) Send(ctx context.Context, msg Message) error {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("from", m.from)
+	mw.WriteField("to", msg.To)
+	mw.WriteField("subject", strings.ReplaceAll(msg.Subject, "\n", " "))
+	mw.WriteField("text", msg.Body)
+	if msg.HTML != "" {
+		mw.WriteField("html", msg.HTML)
+	}
+	for _, a := range msg.Attachments {
+		h := textproto.MIMEHeader{}
+		name := strings.NewReplacer(`"`, "", "\r", "", "\n", "").Replace(a.Name)
+		h.Set("Content-Disposition", `form-data; name="attachment"; filename="`+name+`"`)
+		h.Set("Content-Type", a.ContentType)
+		part, err := mw.CreatePart(h)
+		if err != nil {
+			return err
+		}
+		part.Write(a.Data)
+	}
+	if err := mw.Close(); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.baseURL+"/v3/"+m.domain+"/messages", &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return m.do(req)
+}
+
+// Ping membaca data domain pengirim; gagal jika key atau domain salah.
+func (m *mailgunMailer) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.baseURL+"/v3/domains/"+m.domain, nil)
+	if err != nil {
+		return err
+	}
+	return m.do(req)
+}
+
+func (m *mailgunMailer) do(req *http.Request) error {
+	req.SetBasicAuth("api", m.apiKey)
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("mailer: %w", err)
+	}
+	if err := checkResponse("mailgun", resp); err != nil {
+		return fmt.Errorf("mailer: %w", err)
+	}
+	return nil
+}
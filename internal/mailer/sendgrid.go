@@ -0,0 +1,117 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/httpclient"
+)
+
+type sendGridMailer struct {
+	baseURL string
+	apiKey  string
+	from    string
+	client  *httpclient.Client
+}
+
+// NewSendGrid membuat Mailer lewat SendGrid Web API v3. baseURL kosong =
+// https://api.sendgrid.com.
+func NewSendGrid(baseURL, apiKey, from string) Mailer {
+	if baseURL == "" {
+		baseURL = "https://api.sendgrid.com"
+	}
+	return &sendGridMailer{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		from:    from,
+		// POST tidak diulang otomatis supaya email tidak terkirim dua kali
+		client: httpclient.New("sendgrid", httpclient.Options{Timeout: 15 * time.Second}),
+	}
+}
+
+func (m *sendGridMailer) Name() string { return "sendgrid" }
+
+func (m *sendGridMailer) Configured() bool { return true }
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sendGridAttachment struct {
+	Content     string `json:"content"`
+	Type        string `json:"type"`
+	Filename    string `json:"filename"`
+	Disposition string `json:"disposition"`
+}
+
+func (m *sendGridMailer) Send(ctx context.Context, msg Message) error {
+	payload := struct {
+		Personalizations []struct {
+			To []sendGridAddress `json:"to"`
+		} `json:"personalizations"`
+		From        sendGridAddress      `json:"from"`
+		Subject     string               `json:"subject"`
+		Content     []sendGridContent    `json:"content"`
+		Attachments []sendGridAttachment `json:"attachments,omitempty"`
+	}{
+		From:    sendGridAddress{Email: m.from},
+		Subject: strings.ReplaceAll(msg.Subject, "\n", " "),
+		Content: []sendGridContent{{Type: "text/plain", Value: msg.Body}},
+	}
+	payload.Personalizations = append(payload.Personalizations, struct {
+		To []sendGridAddress `json:"to"`
+	}{To: []sendGridAddress{{Email: msg.To}}})
+	if msg.HTML != "" {
+		payload.Content = append(payload.Content, sendGridContent{Type: "text/html", Value: msg.HTML})
+	}
+	for _, a := range msg.Attachments {
+		payload.Attachments = append(payload.Attachments, sendGridAttachment{
+			Content:     base64.StdEncoding.EncodeToString(a.Data),
+			Type:        a.ContentType,
+			Filename:    a.Name,
+			Disposition: "attachment",
+		})
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.baseURL+"/v3/mail/send", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return m.do(req)
+}
+
+// Ping membaca scope API key; gagal jika key tidak valid.
+func (m *sendGridMailer) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.baseURL+"/v3/scopes", nil)
+	if err != nil {
+		return err
+	}
+	return m.do(req)
+}
+
+func (m *sendGridMailer) do(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("mailer: %w", err)
+	}
+	if err := checkResponse("sendgrid", resp); err != nil {
+		return fmt.Errorf("mailer: %w", err)
+	}
+	return nil
+}
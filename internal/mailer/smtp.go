@@ -0,0 +1,204 @@
+package mailer
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/httpclient"
+)
+
+// smtpMailer mengirim email lewat SMTP.
+type smtpMailer struct {
+	host string
+	port string
+	user string
+	pass string
+	from string
+	// Timeout & circuit breaker untuk koneksi SMTP
+	client *httpclient.Client
+}
+
+// NewSMTP membuat Mailer SMTP. port kosong = 587.
+func NewSMTP(host, port, user, pass, from string) Mailer {
+	if port == "" {
+		port = "587"
+	}
+	return &smtpMailer{
+		host:   host,
+		port:   port,
+		user:   user,
+		pass:   pass,
+		from:   from,
+		client: httpclient.New("smtp", httpclient.Options{Timeout: 15 * time.Second}),
+	}
+}
+
+func (m *smtpMailer) Name() string { return "smtp" }
+
+func (m *smtpMailer) Configured() bool { return m.host != "" }
+
+func (m *smtpMailer) Send(ctx context.Context, msg Message) error {
+	a := m.auth()
+	body := m.build(msg)
+	err := m.client.Run(ctx, func(ctx context.Context) error {
+		return m.sendMail(ctx, a, msg.To, []byte(body))
+	})
+	if err != nil {
+		return fmt.Errorf("mailer: %w", err)
+	}
+	return nil
+}
+
+// build menyusun header dan isi email. Subject di-encode supaya karakter
+// non-ASCII dan baris baru tidak merusak header.
+func (m *smtpMailer) build(msg Message) string {
+	headers := []string{
+		"From: " + m.from,
+		"To: " + msg.To,
+		"Subject: " + mime.QEncoding.Encode("UTF-8", strings.ReplaceAll(msg.Subject, "\n", " ")),
+		"MIME-Version: 1.0",
+	}
+	contentType, content := "text/plain; charset=UTF-8", msg.Body
+	if msg.HTML != "" {
+		boundary := newBoundary()
+		contentType = `multipart/alternative; boundary="` + boundary + `"`
+		content = strings.Join([]string{
+			"--" + boundary,
+			"Content-Type: text/plain; charset=UTF-8",
+			"",
+			msg.Body,
+			"--" + boundary,
+			"Content-Type: text/html; charset=UTF-8",
+			"",
+			msg.HTML,
+			"--" + boundary + "--",
+			"",
+		}, "\r\n")
+	}
+	if len(msg.Attachments) > 0 {
+		boundary := newBoundary()
+		parts := []string{"--" + boundary, "Content-Type: " + contentType, "", content}
+		for _, a := range msg.Attachments {
+			name := mime.QEncoding.Encode("UTF-8", strings.NewReplacer(`"`, "", "\r", "", "\n", "").Replace(a.Name))
+			parts = append(parts,
+				"--"+boundary,
+				"Content-Type: "+a.ContentType,
+				`Content-Disposition: attachment; filename="`+name+`"`,
+				"Content-Transfer-Encoding: base64",
+				"",
+				wrapBase64(a.Data),
+			)
+		}
+		parts = append(parts, "--"+boundary+"--", "")
+		contentType, content = `multipart/mixed; boundary="`+boundary+`"`, strings.Join(parts, "\r\n")
+	}
+	headers = append(headers, "Content-Type: "+contentType)
+	return strings.Join(headers, "\r\n") + "\r\n\r\n" + content
+}
+
+// Ping membuka koneksi SMTP dan login tanpa mengirim email, untuk
+// memeriksa host, port dan kredensial.
+func (m *smtpMailer) Ping(ctx context.Context) error {
+	if !m.Configured() {
+		return errors.New("mailer: SMTP_HOST belum diset")
+	}
+	c, err := m.dial(ctx, m.auth())
+	if err != nil {
+		return fmt.Errorf("mailer: %w", err)
+	}
+	defer c.Close()
+	return c.Quit()
+}
+
+func (m *smtpMailer) auth() smtp.Auth {
+	if m.user == "" {
+		return nil
+	}
+	return smtp.PlainAuth("", m.user, m.pass, m.host)
+}
+
+// dial membuka koneksi (STARTTLS jika didukung) dan login. Koneksi dibatasi
+// deadline dari ctx supaya server SMTP yang macet tidak menggantung selamanya.
+func (m *smtpMailer) dial(ctx context.Context, a smtp.Auth) (*smtp.Client, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(m.host, m.port))
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	if a != nil {
+		if err := c.Auth(a); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// sendMail sama seperti smtp.SendMail, tetapi lewat dial sehingga ikut
+// dibatasi deadline dari ctx.
+func (m *smtpMailer) sendMail(ctx context.Context, a smtp.Auth, to string, body []byte) error {
+	c, err := m.dial(ctx, a)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if err := c.Mail(m.from); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func newBoundary() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return "infocuy-" + hex.EncodeToString(b)
+}
+
+// wrapBase64 memecah base64 per 76 karakter sesuai batas baris MIME.
+func wrapBase64(data []byte) string {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	var b strings.Builder
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded)
+	return b.String()
+}
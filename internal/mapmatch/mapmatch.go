@@ -0,0 +1,81 @@
+// Package mapmatch menggeser (snap) koordinat yang dikirim user ke jalan
+// atau bangunan terdekat supaya pin lebih akurat untuk navigasi. Provider
+// bisa diganti; bawaan memakai layanan nearest dari OSRM (data OSM).
+//
+// Konfigurasi lewat environment:
+//
+//	MAPMATCH_PROVIDER        osrm (kosong = nonaktif)
+//	MAPMATCH_OSRM_URL        base URL OSRM, default https://router.project-osrm.org
+//	MAPMATCH_OSRM_PROFILE    driving (default) | walking | cycling
+//	MAPMATCH_MAX_DISTANCE_M  jarak geser maksimum, default 50
+package mapmatch
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+// Result adalah titik hasil snap beserta jarak geser dari titik asli.
+type Result struct {
+	Coordinates models.Coordinates
+	DistanceM   float64
+}
+
+// Provider mencari titik jalan/bangunan terdekat dari sebuah koordinat.
+type Provider interface {
+	Name() string
+	Snap(ctx context.Context, c models.Coordinates) (Result, error)
+}
+
+// ErrTooFar dikembalikan jika titik terdekat melebihi jarak maksimum;
+// koordinat asli sebaiknya dipakai apa adanya.
+var ErrTooFar = errors.New("mapmatch: titik terdekat terlalu jauh")
+
+const defaultMaxDistanceM = 50
+
+// NewFromEnv mengembalikan nil jika map-matching tidak dikonfigurasi.
+func NewFromEnv() Provider {
+	var p Provider
+	switch strings.ToLower(os.Getenv("MAPMATCH_PROVIDER")) {
+	case "":
+		return nil
+	case "osrm":
+		p = newOSRM(os.Getenv("MAPMATCH_OSRM_URL"), os.Getenv("MAPMATCH_OSRM_PROFILE"))
+	default:
+		log.Println("Warning: MAPMATCH_PROVIDER tidak didukung:", os.Getenv("MAPMATCH_PROVIDER"))
+		return nil
+	}
+	maxDistance := float64(defaultMaxDistanceM)
+	if v, err := strconv.ParseFloat(os.Getenv("MAPMATCH_MAX_DISTANCE_M"), 64); err == nil && v > 0 {
+		maxDistance = v
+	}
+	return WithMaxDistance(p, maxDistance)
+}
+
+// WithMaxDistance membungkus provider sehingga hasil yang lebih jauh dari
+// maxDistanceM ditolak dengan ErrTooFar.
+func WithMaxDistance(p Provider, maxDistanceM float64) Provider {
+	return &limited{Provider: p, maxDistanceM: maxDistanceM}
+}
+
+type limited struct {
+	Provider
+	maxDistanceM float64
+}
+
+func (l *limited) Snap(ctx context.Context, c models.Coordinates) (Result, error) {
+	res, err := l.Provider.Snap(ctx, c)
+	if err != nil {
+		return res, err
+	}
+	if res.DistanceM > l.maxDistanceM {
+		return res, ErrTooFar
+	}
+	return res, nil
+}
@@ -0,0 +1,72 @@
+package mapmatch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/httpclient"
+	"InfoCuy-Backend/internal/models"
+)
+
+type osrm struct {
+	baseURL string
+	profile string
+	client  *httpclient.Client
+}
+
+func newOSRM(baseURL, profile string) *osrm {
+	if baseURL == "" {
+		baseURL = "https://router.project-osrm.org"
+	}
+	if profile == "" {
+		profile = "driving"
+	}
+	return &osrm{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		profile: profile,
+		// Snap berjalan di dalam request create/update, jadi harus cepat
+		client: httpclient.New("osrm", httpclient.Options{Timeout: 3 * time.Second, Retries: 1}),
+	}
+}
+
+func (o *osrm) Name() string { return "osrm" }
+
+// Memakai service nearest: /nearest/v1/{profile}/{lng},{lat}
+func (o *osrm) Snap(ctx context.Context, c models.Coordinates) (Result, error) {
+	url := fmt.Sprintf("%s/nearest/v1/%s/%s,%s?number=1", o.baseURL, o.profile,
+		strconv.FormatFloat(c.Lng, 'f', -1, 64), strconv.FormatFloat(c.Lat, 'f', -1, 64))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Result{}, err
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Code      string `json:"code"`
+		Message   string `json:"message"`
+		Waypoints []struct {
+			Location []float64 `json:"location"` // [lng, lat]
+			Distance float64   `json:"distance"`
+		} `json:"waypoints"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Result{}, fmt.Errorf("osrm: status %d: %w", resp.StatusCode, err)
+	}
+	if body.Code != "Ok" || len(body.Waypoints) == 0 || len(body.Waypoints[0].Location) != 2 {
+		return Result{}, fmt.Errorf("osrm: %s %s", body.Code, body.Message)
+	}
+	wp := body.Waypoints[0]
+	return Result{
+		Coordinates: models.Coordinates{Lat: wp.Location[1], Lng: wp.Location[0]},
+		DistanceM:   wp.Distance,
+	}, nil
+}
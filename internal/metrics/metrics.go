@@ -0,0 +1,94 @@
+// Package metrics menyediakan counter in-process sederhana yang diekspos
+// dalam format teks Prometheus di endpoint /metrics.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CounterVec adalah counter dengan label (mis. route, method).
+type CounterVec struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []*CounterVec
+)
+
+// NewCounterVec membuat counter baru dan mendaftarkannya ke registry global.
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	v := &CounterVec{name: name, help: help, labels: labels, values: map[string]float64{}}
+	registryMu.Lock()
+	registry = append(registry, v)
+	registryMu.Unlock()
+	return v
+}
+
+// Inc menambah counter sebesar 1 untuk kombinasi label yang diberikan.
+func (v *CounterVec) Inc(labelValues ...string) {
+	v.Add(1, labelValues...)
+}
+
+// Add menambah counter sebesar delta.
+func (v *CounterVec) Add(delta float64, labelValues ...string) {
+	key := strings.Join(labelValues, "\xff")
+	v.mu.Lock()
+	v.values[key] += delta
+	v.mu.Unlock()
+}
+
+// Get mengembalikan nilai counter untuk kombinasi label tertentu.
+func (v *CounterVec) Get(labelValues ...string) float64 {
+	key := strings.Join(labelValues, "\xff")
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.values[key]
+}
+
+// WriteText menulis semua counter dalam format teks Prometheus.
+func WriteText(w io.Writer) {
+	registryMu.Lock()
+	vecs := append([]*CounterVec(nil), registry...)
+	registryMu.Unlock()
+
+	for _, v := range vecs {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", v.name, v.help, v.name)
+		v.mu.Lock()
+		keys := make([]string, 0, len(v.values))
+		for k := range v.values {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(w, "%s%s %g\n", v.name, formatLabels(v.labels, k), v.values[k])
+		}
+		v.mu.Unlock()
+	}
+}
+
+func formatLabels(names []string, key string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	values := strings.Split(key, "\xff")
+	parts := make([]string, 0, len(names))
+	for i, n := range names {
+		val := ""
+		if i < len(values) {
+			val = values[i]
+		}
+		val = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(val)
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, n, val))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"InfoCuy-Backend/internal/audit"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Catatan audit untuk setiap operasi yang mengubah data
+type AuditLog struct {
+	ID           primitive.ObjectID      `json:"_id,omitempty" bson:"_id,omitempty"`
+	Time         time.Time               `json:"time" bson:"time"`
+	Actor        string                  `json:"actor" bson:"actor"` // email, kosong untuk sistem/anonim
+	IP           string                  `json:"ip,omitempty" bson:"ip,omitempty"`
+	Action       string                  `json:"action" bson:"action"` // mis. location.update
+	ResourceType string                  `json:"resource_type" bson:"resource_type"`
+	ResourceID   string                  `json:"resource_id" bson:"resource_id"`
+	Changes      map[string]audit.Change `json:"changes,omitempty" bson:"changes,omitempty"`
+	// Ringkasan yang bisa dicari, mis. `location.update location/<id> "Kopi Braga": name, address`
+	Summary string `json:"summary,omitempty" bson:"summary,omitempty"`
+	// Hash chain: Seq berurutan mulai 1, PrevHash adalah Hash catatan
+	// sebelumnya. Kosong untuk catatan dari sebelum chain diaktifkan.
+	Seq      int64  `json:"seq,omitempty" bson:"seq,omitempty"`
+	PrevHash string `json:"prev_hash,omitempty" bson:"prev_hash,omitempty"`
+	Hash     string `json:"hash,omitempty" bson:"hash,omitempty"`
+}
+
+// ChainHash menghitung sha256 (hex) atas PrevHash dan seluruh isi catatan
+// selain Hash. Time harus sudah dibulatkan ke milidetik (presisi BSON)
+// supaya hasilnya sama sebelum dan sesudah disimpan.
+func (l *AuditLog) ChainHash() string {
+	changes := make(map[string]audit.Change, len(l.Changes))
+	for k, c := range l.Changes {
+		changes[k] = audit.Change{Before: audit.Canonical(c.Before), After: audit.Canonical(c.After)}
+	}
+	// Error tidak mungkin: semua nilai sudah berbentuk hasil decode JSON/BSON
+	payload, _ := json.Marshal(struct {
+		Seq          int64                   `json:"seq"`
+		ID           string                  `json:"id"`
+		Time         string                  `json:"time"`
+		Actor        string                  `json:"actor"`
+		IP           string                  `json:"ip"`
+		Action       string                  `json:"action"`
+		ResourceType string                  `json:"resource_type"`
+		ResourceID   string                  `json:"resource_id"`
+		Changes      map[string]audit.Change `json:"changes"`
+		// omitempty supaya hash catatan dari sebelum ada summary tidak berubah
+		Summary string `json:"summary,omitempty"`
+	}{l.Seq, l.ID.Hex(), l.Time.UTC().Format(time.RFC3339Nano), l.Actor, l.IP, l.Action, l.ResourceType, l.ResourceID, changes, l.Summary})
+	sum := sha256.Sum256(append([]byte(l.PrevHash+"\n"), payload...))
+	return hex.EncodeToString(sum[:])
+}
+
+// Alasan AuditChainBreak
+const (
+	ChainMissing      = "missing"       // ada Seq yang hilang (catatan dihapus)
+	ChainHashMismatch = "hash_mismatch" // isi catatan diubah
+	ChainPrevMismatch = "prev_mismatch" // catatan disisipkan atau diurutkan ulang
+)
+
+// AuditChainBreak adalah catatan pertama yang gagal diverifikasi.
+type AuditChainBreak struct {
+	Seq    int64               `json:"seq"`
+	ID     *primitive.ObjectID `json:"_id,omitempty"`
+	Reason string              `json:"reason"`
+}
+
+// AuditVerifyResult adalah hasil GET /admin/audit-logs/verify. Catatan
+// terakhir yang dihapus tidak terdeteksi dari chain saja, jadi simpan
+// HeadSeq dan HeadHash di luar sistem lalu bandingkan pada verifikasi berikutnya.
+type AuditVerifyResult struct {
+	OK      bool  `json:"ok"`
+	Checked int64 `json:"checked"`
+	// Seq terakhir yang sudah dipindah ke arsip; verifikasi dimulai dari
+	// hash arsip terakhir
+	ArchivedSeq int64            `json:"archived_seq,omitempty"`
+	HeadSeq     int64            `json:"head_seq"`
+	HeadHash    string           `json:"head_hash,omitempty"`
+	Broken      *AuditChainBreak `json:"broken,omitempty"`
+}
+
+// AuditArchive adalah satu file arsip audit log di object storage: JSON
+// Lines ter-gzip berisi catatan FromSeq..ToSeq urut seq, yang sudah
+// dihapus dari database setelah file tersimpan. HeadHash adalah hash
+// catatan ToSeq, tempat Verify melanjutkan chain.
+type AuditArchive struct {
+	ID        primitive.ObjectID `json:"_id" bson:"_id"`
+	Key       string             `json:"key" bson:"key"`
+	URL       string             `json:"url,omitempty" bson:"url,omitempty"`
+	FromSeq   int64              `json:"from_seq" bson:"from_seq"`
+	ToSeq     int64              `json:"to_seq" bson:"to_seq"`
+	From      time.Time          `json:"from" bson:"from"`
+	To        time.Time          `json:"to" bson:"to"`
+	Count     int64              `json:"count" bson:"count"`
+	HeadHash  string             `json:"head_hash" bson:"head_hash"`
+	SHA256    string             `json:"sha256" bson:"sha256"` // checksum file
+	CreatedBy string             `json:"created_by,omitempty" bson:"created_by,omitempty"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// AuditArchiveResult adalah hasil satu batch POST /admin/jobs/audit-archive.
+type AuditArchiveResult struct {
+	// Batas retensi: catatan sebelum waktu ini diarsip
+	Before   time.Time     `json:"before"`
+	Archived int64         `json:"archived"`
+	Archive  *AuditArchive `json:"archive,omitempty"`
+	// Masih ada catatan yang lewat retensi; jalankan lagi
+	More bool `json:"more"`
+}
+
+// Jenis entri changelog publik
+const (
+	ChangeAdded   = "added"
+	ChangeUpdated = "updated"
+	ChangeClosed  = "closed"
+	ChangeRemoved = "removed"
+)
+
+// ChangelogEntry adalah satu perubahan dataset di GET /changelog, disusun
+// dari audit log tanpa data actor.
+type ChangelogEntry struct {
+	Time       time.Time `json:"time"`
+	Type       string    `json:"type"`
+	LocationID string    `json:"location_id"`
+	Name       string    `json:"name"`
+	Category   string    `json:"category,omitempty"`
+	// Field penting yang berubah, hanya untuk type updated
+	Fields []string `json:"fields,omitempty"`
+	// Ringkasan sesuai Accept-Language
+	Summary string `json:"summary"`
+}
+
+type ChangelogMeta struct {
+	Since time.Time `json:"since"`
+	// Isi ke ?after= untuk halaman berikutnya; kosong jika sudah habis
+	Next string `json:"next,omitempty"`
+}
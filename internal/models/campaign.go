@@ -0,0 +1,100 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Status campaign email
+const (
+	CampaignDraft  = "draft"  // baru dibuat, bisa di-preview & test-send
+	CampaignQueued = "queued" // penerima sudah dibuat, menunggu job delivery
+	CampaignDone   = "done"   // semua penerima sudah diproses
+)
+
+// Status satu penerima campaign
+const (
+	RecipientPending = "pending"
+	RecipientSent    = "sent"
+	RecipientBounced = "bounced" // ditolak server SMTP saat dikirim
+)
+
+// CampaignSegment memilih user penerima. Field kosong tidak membatasi.
+type CampaignSegment struct {
+	Roles []string `json:"roles,omitempty" bson:"roles,omitempty"`
+	// Tanggal daftar diambil dari _id user
+	SignedUpAfter  *time.Time `json:"signed_up_after,omitempty" bson:"signed_up_after,omitempty"`
+	SignedUpBefore *time.Time `json:"signed_up_before,omitempty" bson:"signed_up_before,omitempty"`
+	// Login terakhir dalam N hari terakhir
+	ActiveWithinDays int `json:"active_within_days,omitempty" bson:"active_within_days,omitempty"`
+	// Tidak login selama N hari (termasuk yang belum pernah tercatat login)
+	InactiveForDays int `json:"inactive_for_days,omitempty" bson:"inactive_for_days,omitempty"`
+}
+
+type CampaignStats struct {
+	Recipients int64 `json:"recipients" bson:"recipients"`
+	Sent       int64 `json:"sent" bson:"sent"`
+	Bounced    int64 `json:"bounced" bson:"bounced"`
+	// Hanya terhitung jika PUBLIC_API_URL diset dan client email memuat gambar
+	Opened int64 `json:"opened" bson:"opened"`
+}
+
+// Campaign adalah email massal ke satu segmen user (collection campaigns).
+// Subject dan Body adalah text/template dengan data {{.Email}} dan {{.Role}}.
+type Campaign struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name        string             `json:"name" bson:"name"`
+	Subject     string             `json:"subject" bson:"subject"`
+	Body        string             `json:"body" bson:"body"`
+	Segment     CampaignSegment    `json:"segment" bson:"segment"`
+	Status      string             `json:"status" bson:"status"`
+	Stats       CampaignStats      `json:"stats" bson:"stats"`
+	CreatedBy   string             `json:"created_by" bson:"created_by"`
+	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+	QueuedAt    *time.Time         `json:"queued_at,omitempty" bson:"queued_at,omitempty"`
+	CompletedAt *time.Time         `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+}
+
+type CampaignInput struct {
+	Name    string          `json:"name"`
+	Subject string          `json:"subject"`
+	Body    string          `json:"body"`
+	Segment CampaignSegment `json:"segment"`
+}
+
+// CampaignRecipient adalah satu email dalam antrean delivery
+// (collection campaign_recipients). Email & Role disalin saat campaign
+// dikirim supaya isi email tidak berubah jika user diubah setelahnya.
+type CampaignRecipient struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	CampaignID primitive.ObjectID `bson:"campaign_id"`
+	UserID     primitive.ObjectID `bson:"user_id"`
+	Email      string             `bson:"email"`
+	Role       string             `bson:"role"`
+	Status     string             `bson:"status"`
+	// Token acak untuk pixel pelacak open
+	Token    string     `bson:"token"`
+	SentAt   *time.Time `bson:"sent_at,omitempty"`
+	OpenedAt *time.Time `bson:"opened_at,omitempty"`
+	Error    string     `bson:"error,omitempty"`
+}
+
+// CampaignPreview adalah hasil render untuk satu contoh penerima.
+type CampaignPreview struct {
+	Subject    string `json:"subject"`
+	Body       string `json:"body"`
+	SampleTo   string `json:"sample_to"`
+	Recipients int64  `json:"recipients"`
+}
+
+type TestSendInput struct {
+	Email string `json:"email"`
+}
+
+// DeliveryResult adalah hasil satu batch POST /admin/jobs/campaign-delivery.
+type DeliveryResult struct {
+	Sent      int   `json:"sent"`
+	Bounced   int   `json:"bounced"`
+	Remaining int64 `json:"remaining"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kategori lokasi. Location.Category menyimpan Slug.
+type Category struct {
+	ID    primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	Name  string             `json:"name" bson:"name"`
+	Slug  string             `json:"slug" bson:"slug"`
+	Icon  string             `json:"icon,omitempty" bson:"icon,omitempty"`
+	Color string             `json:"color,omitempty" bson:"color,omitempty"` // #RRGGBB
+	// Lokasi di kategori ini adalah acara: pengunjung bisa check-in dengan
+	// kode QR yang ditampilkan panitia
+	Event bool `json:"event,omitempty" bson:"event,omitempty"`
+	// Semua lokasi di kategori ini hanya tampil dengan koordinat perkiraan
+	// untuk publik, apa pun pilihan per lokasinya
+	ApproximateCoordinates bool `json:"approximate_coordinates,omitempty" bson:"approximate_coordinates,omitempty"`
+	// Nama tampilan per bahasa, diatur admin; Name dipakai jika kosong
+	Labels Labels `json:"labels,omitempty" bson:"labels,omitempty"`
+	// Tag OpenStreetMap yang setara (key=value, mis. amenity=cafe), untuk
+	// GET /suggestions/missing
+	OSMTags []string `json:"osm_tags,omitempty" bson:"osm_tags,omitempty"`
+	// Label hasil Accept-Language, hanya diisi di response
+	Label string `json:"label,omitempty" bson:"-"`
+}
+
+// Status usulan kategori
+const (
+	SuggestionPending  = "pending"
+	SuggestionApproved = "approved" // kategori baru dibuat dari usulan
+	SuggestionMerged   = "merged"   // diarahkan ke kategori yang sudah ada
+	SuggestionRejected = "rejected"
+)
+
+// CategorySuggestionInput adalah body POST /categories/suggest.
+type CategorySuggestionInput struct {
+	Name string `json:"name"`
+	// Kenapa kategori yang ada tidak cukup, mis. jenis lokasi yang
+	// ditemukan tim lapangan
+	Justification string `json:"justification"`
+}
+
+// CategorySuggestion adalah usulan kategori baru dari user (collection
+// category_suggestions). Admin menyetujui (kategori baru dibuat),
+// menggabungkan ke kategori yang sudah ada, atau menolak; pengusul diberi
+// tahu lewat email.
+type CategorySuggestion struct {
+	ID   primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name string             `json:"name" bson:"name"`
+	// Slug dari Name; dipakai jika usulan disetujui tanpa slug lain
+	Slug          string `json:"slug" bson:"slug"`
+	Justification string `json:"justification" bson:"justification"`
+	SuggestedBy   string `json:"suggested_by" bson:"suggested_by"`
+	Status        string `json:"status" bson:"status"`
+	// Slug kategori yang dibuat (approved) atau tujuan merge (merged)
+	Category string `json:"category,omitempty" bson:"category,omitempty"`
+	// Alasan penolakan atau catatan admin, ikut dikirim ke pengusul
+	Note       string     `json:"note,omitempty" bson:"note,omitempty"`
+	ResolvedBy string     `json:"resolved_by,omitempty" bson:"resolved_by,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty" bson:"resolved_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at" bson:"created_at"`
+}
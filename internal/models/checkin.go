@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CheckIn mencatat kehadiran user di lokasi acara (collection checkins),
+// dibuktikan dengan kode QR yang sedang tampil di lokasi. Satu user hanya
+// dihitung sekali per lokasi.
+type CheckIn struct {
+	ID         primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	LocationID primitive.ObjectID `json:"location_id" bson:"location_id"`
+	UserID     primitive.ObjectID `json:"-" bson:"user_id"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// CheckInInput adalah body POST /locations/:id/checkin/verify.
+type CheckInInput struct {
+	Code string `json:"code"`
+}
+
+// CheckInCode adalah isi QR di layar panitia. Kode berganti setiap
+// beberapa detik; layar sebaiknya mengambil kode baru sebelum ExpiresAt.
+type CheckInCode struct {
+	Code      string    `json:"code"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Attendance adalah ringkasan kehadiran untuk panitia acara.
+type Attendance struct {
+	LocationID    primitive.ObjectID `json:"location_id" bson:"-"`
+	Total         int64              `json:"total" bson:"total"`
+	LastCheckInAt *time.Time         `json:"last_check_in_at,omitempty" bson:"last_check_in_at"`
+	// Per tanggal WIB, untuk acara beberapa hari
+	ByDay []DayCount `json:"by_day" bson:"by_day"`
+}
+
+type DayCount struct {
+	Date  string `json:"date" bson:"_id"` // YYYY-MM-DD
+	Count int64  `json:"count" bson:"count"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"InfoCuy-Backend/internal/units"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ComparedLocation adalah satu kolom tabel perbandingan. Field yang kosong
+// di lokasi tetap dikirim (nilai nol) supaya baris tabel sejajar.
+type ComparedLocation struct {
+	ID                     primitive.ObjectID `json:"_id"`
+	Name                   string             `json:"name"`
+	Category               string             `json:"category"`
+	Address                string             `json:"address"`
+	Coordinates            Coordinates        `json:"coordinates"`
+	ApproximateCoordinates bool               `json:"approximate_coordinates,omitempty"`
+	Verified               bool               `json:"verified"`
+	Status                 string             `json:"status,omitempty"`
+	Rating                 RatingSummary      `json:"rating"`
+	PriceRange             string             `json:"price_range"`
+	PaymentMethods         []string           `json:"payment_methods"`
+	Accessibility          Accessibility      `json:"accessibility"`
+	ElevationM             *float64           `json:"elevation_m"`
+	FavoritesCount         int64              `json:"favorites_count"`
+	CheckInsCount          int64              `json:"checkins_count"`
+	Freshness              *float64           `json:"freshness"`
+	// Jarak dari titik referensi; hanya jika ?lat=&lng= diisi
+	DistanceM *float64        `json:"-"`
+	Distance  *units.Distance `json:"distance,omitempty"`
+}
+
+// LocationComparison adalah response GET /locations/compare, lokasi
+// berdampingan sesuai urutan ids.
+type LocationComparison struct {
+	Reference *Coordinates       `json:"reference,omitempty"`
+	Locations []ComparedLocation `json:"locations"`
+	// Field yang nilainya berbeda antar lokasi, untuk menyorot perbedaan
+	Differences []string `json:"differences"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Confirmation mencatat bahwa user menyatakan data lokasi masih akurat
+// (collection location_confirmations). Dokumen dihapus Mongo setelah
+// ExpiresAt, setelah itu user yang sama boleh mengonfirmasi lagi.
+type Confirmation struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	LocationID primitive.ObjectID `bson:"location_id"`
+	UserID     primitive.ObjectID `bson:"user_id"`
+	CreatedAt  time.Time          `bson:"created_at"`
+	ExpiresAt  time.Time          `bson:"expires_at"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// DatabaseStatus adalah mode MongoDB di instance ini (GET /admin/database).
+type DatabaseStatus struct {
+	// Tidak ada primary atau dipaksa admin: write ditolak 503 kecuali
+	// yang bisa diantrekan di outbox
+	ReadOnly bool        `json:"read_only"`
+	Forced   bool        `json:"forced,omitempty"`
+	Reason   string      `json:"reason,omitempty"`
+	Since    *time.Time  `json:"since,omitempty"`
+	Outbox   OutboxStats `json:"outbox"`
+}
+
+// OutboxStats adalah isi antrean write selama mode baca saja. Applied dan
+// Failed dihitung sejak instance berjalan.
+type OutboxStats struct {
+	Pending  int        `json:"pending"`
+	Capacity int        `json:"capacity"`
+	Oldest   *time.Time `json:"oldest,omitempty"`
+	Applied  int64      `json:"applied"`
+	Failed   int64      `json:"failed"`
+}
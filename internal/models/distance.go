@@ -0,0 +1,32 @@
+package models
+
+import (
+	"InfoCuy-Backend/internal/units"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MatrixPoint adalah satu titik matriks jarak: lokasi (location_id) atau
+// koordinat bebas. Pada response, titik lokasi dilengkapi nama dan
+// koordinatnya.
+type MatrixPoint struct {
+	LocationID             *primitive.ObjectID `json:"location_id,omitempty"`
+	Coordinates            *Coordinates        `json:"coordinates,omitempty"`
+	Name                   string              `json:"name,omitempty"`
+	ApproximateCoordinates bool                `json:"approximate_coordinates,omitempty"`
+}
+
+// DistanceMatrixInput adalah body POST /distance/matrix.
+type DistanceMatrixInput struct {
+	Origins      []MatrixPoint `json:"origins"`
+	Destinations []MatrixPoint `json:"destinations"`
+}
+
+// DistanceMatrix berisi jarak great-circle (haversine) setiap origin ke
+// setiap destination; Distances[i][j] adalah origins[i] ke destinations[j].
+type DistanceMatrix struct {
+	Origins      []MatrixPoint      `json:"origins"`
+	Destinations []MatrixPoint      `json:"destinations"`
+	DistancesM   [][]float64        `json:"-"`
+	Distances    [][]units.Distance `json:"distances"`
+}
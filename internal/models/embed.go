@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LocationEmbedVersion hanya naik jika bentuk LocationEmbed berubah secara
+// tidak kompatibel; field baru boleh ditambahkan tanpa menaikkannya.
+const LocationEmbedVersion = 1
+
+// EmbedCategory adalah kategori lokasi di kartu embed.
+type EmbedCategory struct {
+	Slug string `json:"slug"`
+	// Nama tampilan sesuai ?lang=
+	Label string `json:"label"`
+	Icon  string `json:"icon"`
+	Color string `json:"color"`
+}
+
+// LocationEmbed adalah data publik satu lokasi untuk widget pihak ketiga
+// (GET /v1/public/locations/:id.json). Semua field selalu ada; nilai yang
+// tidak tersedia berupa string kosong atau null, supaya skrip embed tidak
+// perlu memeriksa keberadaan field.
+type LocationEmbed struct {
+	Version  int                `json:"version"`
+	ID       primitive.ObjectID `json:"id"`
+	Name     string             `json:"name"`
+	Category EmbedCategory      `json:"category"`
+	// Kosong jika koordinat disamarkan
+	Address                string        `json:"address"`
+	Coordinates            Coordinates   `json:"coordinates"`
+	ApproximateCoordinates bool          `json:"approximate_coordinates"`
+	Verified               bool          `json:"verified"`
+	Status                 string        `json:"status"`
+	Rating                 RatingSummary `json:"rating"`
+	// Foto pertama, null jika belum ada
+	PhotoURL  *string    `json:"photo_url"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
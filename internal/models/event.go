@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Event adalah satu domain event di log event (collection events). Seq
+// naik tanpa celah, sehingga consumer cukup menyimpan seq terakhir yang
+// sudah diproses untuk melanjutkan.
+type Event struct {
+	ID   primitive.ObjectID `json:"id" bson:"_id"`
+	Seq  int64              `json:"seq" bson:"seq"`
+	Time time.Time          `json:"time" bson:"time"`
+	// Nama perubahan, sama dengan action di audit log (mis. location.update)
+	Type        string               `json:"type" bson:"type"`
+	Actor       string               `json:"actor,omitempty" bson:"actor,omitempty"`
+	LocationIDs []primitive.ObjectID `json:"location_ids" bson:"location_ids"`
+}
+
+// EventOffset adalah posisi terakhir yang sudah diproses satu consumer
+// log event.
+type EventOffset struct {
+	Consumer  string    `json:"consumer" bson:"_id"`
+	Seq       int64     `json:"seq" bson:"seq"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+	UpdatedBy string    `json:"updated_by" bson:"updated_by"`
+}
+
+// EventOffsetInput adalah body PUT /events/consumers/:name.
+type EventOffsetInput struct {
+	Seq *int64 `json:"seq"`
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Jadwal export berkala
+const (
+	ExportDaily  = "daily"
+	ExportWeekly = "weekly"
+)
+
+var ExportIntervals = []string{ExportDaily, ExportWeekly}
+
+// Tujuan pengiriman file export
+const (
+	DeliverEmail   = "email"   // lampiran email
+	DeliverWebhook = "webhook" // POST file ke URL
+	DeliverS3      = "s3"      // object di bucket OBJECT_STORE=s3
+)
+
+var ExportTargets = []string{DeliverEmail, DeliverWebhook, DeliverS3}
+
+// Hasil satu kali export dijalankan
+const (
+	ExportRunOK     = "ok"
+	ExportRunFailed = "failed"
+)
+
+// ExportFilter membatasi lokasi yang di-export. Hanya lokasi yang sudah
+// tayang (approved) yang ikut, dengan koordinat seperti yang dilihat publik.
+type ExportFilter struct {
+	Category string `json:"category,omitempty" bson:"category,omitempty"`
+}
+
+type ExportDelivery struct {
+	Type string `json:"type" bson:"type"`
+	// Penerima untuk type email
+	Email string `json:"email,omitempty" bson:"email,omitempty"`
+	// Tujuan POST untuk type webhook
+	URL string `json:"url,omitempty" bson:"url,omitempty"`
+	// Kunci HMAC-SHA256 untuk header X-InfoCuy-Signature (webhook). Hanya
+	// ditulis, tidak pernah dikembalikan; lihat HasSecret.
+	Secret    string `json:"secret,omitempty" bson:"secret,omitempty"`
+	HasSecret bool   `json:"has_secret,omitempty" bson:"-"`
+	// Awalan key object untuk type s3, mis. exports/mingguan
+	Prefix string `json:"prefix,omitempty" bson:"prefix,omitempty"`
+}
+
+// ExportRun adalah hasil terakhir sebuah jadwal export.
+type ExportRun struct {
+	At        time.Time `json:"at" bson:"at"`
+	Status    string    `json:"status" bson:"status"`
+	Locations int64     `json:"locations" bson:"locations"`
+	Bytes     int       `json:"bytes" bson:"bytes"`
+	// Email, URL atau key object tujuan
+	Target string `json:"target,omitempty" bson:"target,omitempty"`
+	Error  string `json:"error,omitempty" bson:"error,omitempty"`
+}
+
+// ScheduledExport adalah export lokasi berkala (collection
+// scheduled_exports) yang dijalankan POST /admin/jobs/scheduled-exports.
+type ScheduledExport struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name      string             `json:"name" bson:"name"`
+	Format    string             `json:"format" bson:"format"`
+	Filter    ExportFilter       `json:"filter" bson:"filter"`
+	Interval  string             `json:"interval" bson:"interval"`
+	Delivery  ExportDelivery     `json:"delivery" bson:"delivery"`
+	Enabled   bool               `json:"enabled" bson:"enabled"`
+	NextRunAt time.Time          `json:"next_run_at" bson:"next_run_at"`
+	LastRun   *ExportRun         `json:"last_run,omitempty" bson:"last_run,omitempty"`
+	CreatedBy string             `json:"created_by" bson:"created_by"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// ScheduledExportInput adalah body POST/PUT /admin/scheduled-exports.
+// Format kosong = geojson, interval kosong = weekly, enabled kosong = true.
+// StartsAt adalah jadwal pertama; kosong = secepatnya.
+type ScheduledExportInput struct {
+	Name     string         `json:"name"`
+	Format   string         `json:"format"`
+	Filter   ExportFilter   `json:"filter"`
+	Interval string         `json:"interval"`
+	Delivery ExportDelivery `json:"delivery"`
+	Enabled  *bool          `json:"enabled"`
+	StartsAt *time.Time     `json:"starts_at"`
+}
+
+// ScheduledExportResult adalah hasil satu batch
+// POST /admin/jobs/scheduled-exports.
+type ScheduledExportResult struct {
+	Ran    int `json:"ran"`
+	Failed int `json:"failed"`
+	// Jadwal yang sudah jatuh tempo tapi belum sempat dijalankan
+	Remaining int64 `json:"remaining"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Favorite adalah lokasi yang disimpan user (collection favorites). Satu
+// user hanya bisa menyimpan lokasi yang sama sekali.
+type Favorite struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `bson:"user_id"`
+	LocationID primitive.ObjectID `bson:"location_id"`
+	CreatedAt  time.Time          `bson:"created_at"`
+}
+
+// FavoriteStatus adalah response POST/DELETE /locations/:id/favorite.
+type FavoriteStatus struct {
+	Favorited      bool  `json:"favorited"`
+	FavoritesCount int64 `json:"favorites_count"`
+	// Database sedang read-only: perubahan diantrekan dan favorites_count
+	// belum termasuk perubahan ini
+	Queued bool `json:"queued,omitempty"`
+}
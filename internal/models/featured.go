@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FeaturedSlot adalah satu lokasi pilihan admin di carousel beranda.
+// Lokasi hanya tampil selama jendela StartsAt <= sekarang < EndsAt; yang
+// kosong berarti tanpa batas di sisi itu.
+type FeaturedSlot struct {
+	LocationID primitive.ObjectID `json:"location_id" bson:"location_id"`
+	// Teks pendek di kartu carousel, mis. "Baru buka"
+	Caption  string     `json:"caption,omitempty" bson:"caption,omitempty"`
+	StartsAt *time.Time `json:"starts_at,omitempty" bson:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty" bson:"ends_at,omitempty"`
+}
+
+// ActiveAt bernilai true jika slot tayang pada t.
+func (s FeaturedSlot) ActiveAt(t time.Time) bool {
+	return (s.StartsAt == nil || !t.Before(*s.StartsAt)) && (s.EndsAt == nil || t.Before(*s.EndsAt))
+}
+
+// FeaturedSettings adalah isi carousel yang disimpan di settings. Urutan
+// Slots adalah urutan tampil, diatur lewat PUT /admin/featured.
+type FeaturedSettings struct {
+	Slots []FeaturedSlot `json:"slots" bson:"slots"`
+}
+
+// FeaturedEntry adalah satu slot di GET /admin/featured beserta statusnya.
+// Location kosong jika lokasinya sudah dihapus atau belum tayang, sehingga
+// slot dilewati di carousel publik.
+type FeaturedEntry struct {
+	FeaturedSlot
+	Position int          `json:"position"`
+	Active   bool         `json:"active"`
+	Location *MapViewItem `json:"location,omitempty"`
+}
+
+// FeaturedItem adalah satu kartu carousel GET /locations/featured.
+type FeaturedItem struct {
+	Caption  string      `json:"caption,omitempty"`
+	Location MapViewItem `json:"location"`
+}
+
+// PublicConfig adalah pengaturan yang dibaca frontend saat dimuat
+// (GET /config), supaya beranda cukup satu request.
+type PublicConfig struct {
+	RegistrationOpen bool           `json:"registration_open"`
+	Featured         []FeaturedItem `json:"featured"`
+}
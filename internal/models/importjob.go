@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Status job geocoding import
+const (
+	ImportJobQueued = "queued" // masih ada baris yang menunggu geocoding
+	ImportJobDone   = "done"
+)
+
+// Status baris di job geocoding import
+const (
+	ImportRowPending = "pending"
+	// Koordinat ditemukan provider dan lokasinya sudah dibuat
+	ImportRowGeocoded = "geocoded"
+	// Alamat tidak ditemukan; menunggu koordinat diisi manual
+	ImportRowUnresolved = "unresolved"
+	// Koordinat diisi manual dan lokasinya sudah dibuat
+	ImportRowPlaced = "placed"
+	ImportRowFailed = "failed"
+)
+
+// ImportJobRow adalah satu baris import yang hanya berisi alamat.
+type ImportJobRow struct {
+	// Nomor baris di file import
+	Row     int    `json:"row" bson:"row"`
+	Name    string `json:"name" bson:"name"`
+	Address string `json:"address" bson:"address"`
+	Status  string `json:"status" bson:"status"`
+	// Ditentukan saat import supaya baris yang diproses dua kali tidak
+	// membuat lokasi ganda
+	LocationID  primitive.ObjectID `json:"location_id" bson:"location_id"`
+	Coordinates *Coordinates       `json:"coordinates,omitempty" bson:"coordinates,omitempty"`
+	Error       string             `json:"error,omitempty" bson:"error,omitempty"`
+	// Hasil validasi baris saat import, tanpa koordinat
+	Location Location `json:"-" bson:"location"`
+}
+
+// ImportJob adalah job geocoding untuk baris import tanpa koordinat
+// (collection import_jobs). Baris diproses urut; Cursor adalah jumlah
+// baris yang sudah diproses.
+type ImportJob struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Status    string             `json:"status" bson:"status"`
+	CreatedBy string             `json:"created_by" bson:"created_by"`
+	// Role pengimport saat import, untuk moderasi lokasi yang dibuat job
+	Role        string     `json:"-" bson:"role"`
+	CreatedAt   time.Time  `json:"created_at" bson:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+	Total       int        `json:"total" bson:"total"`
+	// Baris yang belum diproses, dihitung dari Cursor
+	Pending    int            `json:"pending" bson:"-"`
+	Geocoded   int            `json:"geocoded" bson:"geocoded"`
+	Unresolved int            `json:"unresolved" bson:"unresolved"`
+	Placed     int            `json:"placed" bson:"placed"`
+	Failed     int            `json:"failed" bson:"failed"`
+	Cursor     int            `json:"-" bson:"cursor"`
+	Rows       []ImportJobRow `json:"rows" bson:"rows"`
+}
+
+// GeocodeBatchResult adalah hasil satu batch POST /admin/jobs/import-geocode.
+type GeocodeBatchResult struct {
+	// Job yang diproses batch ini; kosong jika antrean kosong
+	JobID      *primitive.ObjectID `json:"job_id,omitempty"`
+	Processed  int                 `json:"processed"`
+	Geocoded   int                 `json:"geocoded"`
+	Unresolved int                 `json:"unresolved"`
+	// Batch berhenti karena batas panggilan provider geocoding tercapai
+	RateLimited bool `json:"rate_limited,omitempty"`
+	// Job yang masih antre, termasuk job ini jika belum selesai
+	Queued int64 `json:"queued"`
+}
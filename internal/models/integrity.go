@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Jenis masalah integritas data
+const (
+	// Pembuat lokasi (created_by) tidak terdaftar lagi, mis. akunnya dihapus
+	IntegrityCreatorMissing = "location_creator_missing"
+	// Ulasan, favorit atau check-in menunjuk lokasi yang sudah di-purge
+	IntegrityReviewOrphan   = "review_location_missing"
+	IntegrityFavoriteOrphan = "favorite_location_missing"
+	IntegrityCheckInOrphan  = "checkin_location_missing"
+	// Foto tercatat di lokasi tetapi object-nya tidak ada di storage
+	IntegrityPhotoMissing = "photo_object_missing"
+)
+
+// Aksi perbaikan masalah integritas
+const (
+	RepairReassign    = "reassign_locations" // job reassign ke pemilik baru
+	RepairDeleteRefs  = "delete_references"  // hapus dokumen yang yatim
+	RepairRemovePhoto = "remove_photo"       // hapus foto dari lokasi
+)
+
+// IntegrityIssue adalah satu temuan pemeriksaan integritas beserta aksi
+// perbaikannya. ID stabil antar pemeriksaan (jenis + resource).
+type IntegrityIssue struct {
+	ID     string `json:"id" bson:"id"`
+	Kind   string `json:"kind" bson:"kind"`
+	Repair string `json:"repair" bson:"repair"`
+	// Lokasi yang dirujuk (atau pemilik foto)
+	LocationID *primitive.ObjectID `json:"location_id,omitempty" bson:"location_id,omitempty"`
+	PhotoID    *primitive.ObjectID `json:"photo_id,omitempty" bson:"photo_id,omitempty"`
+	// Email pembuat yang tidak terdaftar
+	Email string `json:"email,omitempty" bson:"email,omitempty"`
+	// Jumlah dokumen yang terdampak
+	Count  int64  `json:"count" bson:"count"`
+	Detail string `json:"detail" bson:"detail"`
+	// Terisi setelah diperbaiki lewat POST /admin/integrity-check/repair
+	RepairedAt *time.Time `json:"repaired_at,omitempty" bson:"repaired_at,omitempty"`
+	RepairedBy string     `json:"repaired_by,omitempty" bson:"repaired_by,omitempty"`
+	// Hasil perbaikan, mis. ID job reassign atau jumlah dokumen dihapus
+	Result string `json:"result,omitempty" bson:"result,omitempty"`
+}
+
+// IntegrityReport adalah hasil pemeriksaan integritas terakhir.
+type IntegrityReport struct {
+	StartedAt   time.Time        `json:"started_at" bson:"started_at"`
+	CompletedAt time.Time        `json:"completed_at" bson:"completed_at"`
+	RunBy       string           `json:"run_by" bson:"run_by"`
+	Issues      []IntegrityIssue `json:"issues" bson:"issues"`
+	// Jenis masalah yang temuannya dipotong di batas per jenis; perbaiki
+	// lalu jalankan ulang untuk melihat sisanya
+	Truncated []string `json:"truncated,omitempty" bson:"truncated,omitempty"`
+	// Foto yang diperiksa ke storage pada pemeriksaan ini. Foto diperiksa
+	// bergiliran per batch; temuan foto dari pemeriksaan sebelumnya yang
+	// belum diperiksa ulang tetap dibawa
+	PhotosChecked int `json:"photos_checked" bson:"photos_checked"`
+	// Alasan foto tidak diperiksa, mis. object store tidak mendukung HEAD
+	PhotosSkipped string `json:"photos_skipped,omitempty" bson:"photos_skipped,omitempty"`
+	// Lokasi terakhir yang fotonya diperiksa; kosong = mulai dari awal
+	PhotoCursor primitive.ObjectID `json:"-" bson:"photo_cursor"`
+}
+
+// IntegrityRepairInput adalah body POST /admin/integrity-check/repair.
+type IntegrityRepairInput struct {
+	Issue string `json:"issue"`
+	// Pemilik baru untuk reassign_locations; kosong = admin yang memperbaiki
+	Target string `json:"target,omitempty"`
+}
+
+// DanglingReference adalah dokumen di collection turunan lokasi yang
+// location_id-nya tidak ada lagi, dikelompokkan per lokasi.
+type DanglingReference struct {
+	// Salah satu repositories.Ref*
+	Collection string             `bson:"collection"`
+	LocationID primitive.ObjectID `bson:"_id"`
+	Count      int64              `bson:"count"`
+}
+
+// CreatorCount adalah jumlah lokasi milik satu pembuat.
+type CreatorCount struct {
+	Email string `bson:"_id"`
+	Count int64  `bson:"count"`
+}
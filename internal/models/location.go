@@ -0,0 +1,295 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"InfoCuy-Backend/internal/units"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Coordinates struct {
+	Lat float64 `json:"lat" bson:"lat"`
+	Lng float64 `json:"lng" bson:"lng"`
+}
+
+// Di Mongo koordinat disimpan sebagai GeoJSON Point ([lng, lat]) agar bisa
+// memakai index 2dsphere. API tetap memakai bentuk {lat, lng}.
+type geoPoint struct {
+	Type        string    `bson:"type"`
+	Coordinates []float64 `bson:"coordinates"`
+}
+
+func (c Coordinates) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(geoPoint{Type: "Point", Coordinates: []float64{c.Lng, c.Lat}})
+}
+
+// Dokumen lama ({lat, lng}) tetap bisa dibaca
+func (c *Coordinates) UnmarshalBSON(data []byte) error {
+	var raw struct {
+		Type        string    `bson:"type"`
+		Coordinates []float64 `bson:"coordinates"`
+		Lat         float64   `bson:"lat"`
+		Lng         float64   `bson:"lng"`
+	}
+	if err := bson.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Type == "Point" && len(raw.Coordinates) == 2 {
+		c.Lng, c.Lat = raw.Coordinates[0], raw.Coordinates[1]
+		return nil
+	}
+	c.Lat, c.Lng = raw.Lat, raw.Lng
+	return nil
+}
+
+type Location struct {
+	ID          primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	Name        string             `json:"name" bson:"name"`
+	Category    string             `json:"category" bson:"category"`
+	Coordinates Coordinates        `json:"coordinates" bson:"coordinates"`
+	Address     string             `json:"address" bson:"address"`
+	PostalCode  string             `json:"postal_code,omitempty" bson:"postal_code,omitempty"`
+	CreatedBy   string             `json:"created_by" bson:"created_by"`
+	Verified    bool               `json:"verified" bson:"verified"`
+	Status      string             `json:"status,omitempty" bson:"status,omitempty"`
+	Visibility  string             `json:"visibility,omitempty" bson:"visibility,omitempty"`
+	// Publik hanya melihat koordinat perkiraan (lihat Conceal), mis. untuk
+	// rumah aman. Kategori juga bisa mewajibkannya lewat Category.ApproximateCoordinates
+	ApproximateCoordinates bool `json:"approximate_coordinates,omitempty" bson:"approximate_coordinates,omitempty"`
+	// Pinned/sponsored, diatur admin
+	Promotion *Promotion `json:"promotion,omitempty" bson:"promotion,omitempty"`
+	// Ketinggian (mdpl), diisi server dari provider elevation
+	ElevationM *float64 `json:"elevation_m,omitempty" bson:"elevation_m,omitempty"`
+	// Terisi jika Coordinates hasil snap ke jalan/bangunan
+	Snap *SnapInfo `json:"snap,omitempty" bson:"snap,omitempty"`
+	// Provinsi/kabupaten/kecamatan, diisi server dari batas wilayah
+	AdminArea *AdminArea `json:"admin_area,omitempty" bson:"admin_area,omitempty"`
+	// Fasilitas untuk penyandang disabilitas
+	Accessibility *Accessibility `json:"accessibility,omitempty" bson:"accessibility,omitempty"`
+	// Salah satu PriceRanges
+	PriceRange string `json:"price_range,omitempty" bson:"price_range,omitempty"`
+	// Subset PaymentMethods
+	PaymentMethods []string `json:"payment_methods,omitempty" bson:"payment_methods,omitempty"`
+	// Diisi lewat POST /locations/:id/photos
+	Photos []Photo `json:"photos,omitempty" bson:"photos,omitempty"`
+	// Dihitung dari collection reviews saat dibaca, tidak disimpan
+	Rating *RatingSummary `json:"rating,omitempty" bson:"rating,omitempty"`
+	// Counter yang dijaga lewat $inc saat favorit/check-in ditambah atau
+	// dihapus; selisih dengan collection sumbernya diperbaiki job
+	// rekonsiliasi (POST /admin/jobs/counter-reconcile)
+	FavoritesCount int64 `json:"favorites_count" bson:"favorites_count,omitempty"`
+	CheckInsCount  int64 `json:"checkins_count" bson:"checkins_count,omitempty"`
+	// Diisi lewat POST /locations/:id/confirm ("data ini masih akurat")
+	LastConfirmedAt   *time.Time `json:"last_confirmed_at,omitempty" bson:"last_confirmed_at,omitempty"`
+	ConfirmationCount int        `json:"confirmation_count" bson:"confirmation_count,omitempty"`
+	// 0-1, dihitung saat dibaca dengan FreshnessAt
+	Freshness *float64 `json:"freshness,omitempty" bson:"-"`
+	// Waktu pemilik terakhir diperingatkan bahwa skor kesegaran turun
+	FreshnessAlertedAt *time.Time `json:"-" bson:"freshness_alerted_at,omitempty"`
+	// Terisi jika lokasi ada di trash (soft delete)
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	DeletedBy string     `json:"deleted_by,omitempty" bson:"deleted_by,omitempty"`
+	// Waktu terakhir diubah lewat PUT (kosong untuk dokumen lama)
+	UpdatedAt *time.Time `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+	// Terisi jika lokasi ada di arsip (cold storage)
+	ArchivedAt *time.Time `json:"archived_at,omitempty" bson:"archived_at,omitempty"`
+	// Salah satu Moderation*; kosong untuk dokumen lama (dianggap approved)
+	ModerationStatus string `json:"moderation_status,omitempty" bson:"moderation_status,omitempty"`
+	// Diisi moderator saat menolak, dikirim juga ke pembuat lewat email
+	RejectionReason string     `json:"rejection_reason,omitempty" bson:"rejection_reason,omitempty"`
+	ModeratedBy     string     `json:"moderated_by,omitempty" bson:"moderated_by,omitempty"`
+	ModeratedAt     *time.Time `json:"moderated_at,omitempty" bson:"moderated_at,omitempty"`
+	// Versi bentuk dokumen (LocationSchema); diisi repository saat menulis
+	SchemaVersion int `json:"-" bson:"schema_version,omitempty"`
+}
+
+// Status moderasi lokasi. Lokasi dari user tanpa locations:moderate dibuat
+// pending dan baru tampil di daftar publik setelah approved.
+const (
+	ModerationPending  = "pending"
+	ModerationApproved = "approved"
+	ModerationRejected = "rejected"
+)
+
+// Listed bernilai true jika lokasi boleh tampil untuk publik.
+func (l *Location) Listed() bool {
+	return l.ModerationStatus != ModerationPending && l.ModerationStatus != ModerationRejected
+}
+
+// StatusPermanentlyClosed menandai lokasi yang sudah tutup permanen; lokasi
+// ini dipindah ke arsip oleh job archive.
+const StatusPermanentlyClosed = "permanently_closed"
+
+// FreshnessAt menghitung skor kesegaran data: 1 saat baru dikonfirmasi
+// (atau dibuat, jika belum pernah), lalu turun separuh setiap halfLife.
+func (l *Location) FreshnessAt(now time.Time, halfLife time.Duration) float64 {
+	ref := l.ID.Timestamp()
+	if l.LastConfirmedAt != nil {
+		ref = *l.LastConfirmedAt
+	}
+	age := now.Sub(ref)
+	if age < 0 {
+		age = 0
+	}
+	f := math.Pow(0.5, float64(age)/float64(halfLife))
+	return math.Round(f*1000) / 1000
+}
+
+// Nilai field aksesibilitas, mengikuti tag wheelchair=* OpenStreetMap.
+// Kosong berarti belum diketahui.
+const (
+	AccessYes     = "yes"
+	AccessLimited = "limited"
+	AccessNo      = "no"
+)
+
+// Accessibility berisi fasilitas aksesibilitas sebuah lokasi.
+type Accessibility struct {
+	// Pintu masuk & ruang utama bisa dilalui kursi roda
+	Wheelchair        string `json:"wheelchair,omitempty" bson:"wheelchair,omitempty"`
+	AccessibleToilet  string `json:"accessible_toilet,omitempty" bson:"accessible_toilet,omitempty"`
+	AccessibleParking string `json:"accessible_parking,omitempty" bson:"accessible_parking,omitempty"`
+}
+
+// AccessibilityFeatures memetakan nama fitur di ?accessible= ke field
+// Accessibility (nama JSON/BSON).
+var AccessibilityFeatures = map[string]string{
+	"wheelchair": "wheelchair",
+	"toilet":     "accessible_toilet",
+	"parking":    "accessible_parking",
+}
+
+// Kisaran harga, dari yang paling murah
+var PriceRanges = []string{"budget", "moderate", "pricey", "luxury"}
+
+// Metode pembayaran yang dikenal
+var PaymentMethods = []string{"cash", "qris", "debit_card", "credit_card", "ewallet", "bank_transfer"}
+
+// Jumlah lokasi per nilai atribut, untuk facet pencarian
+type FacetCount struct {
+	Value string `json:"value" bson:"_id"`
+	Count int64  `json:"count" bson:"count"`
+	// Label tampilan sesuai Accept-Language
+	Label string `json:"label,omitempty" bson:"-"`
+}
+
+// LocationFacets adalah ringkasan GET /locations/facets
+type LocationFacets struct {
+	Total          int64        `json:"total"`
+	Category       []FacetCount `json:"category"`
+	PriceRange     []FacetCount `json:"price_range"`
+	PaymentMethods []FacetCount `json:"payment_methods"`
+}
+
+// Koordinat asli yang dikirim user sebelum di-snap
+type SnapInfo struct {
+	RawCoordinates Coordinates `json:"raw_coordinates" bson:"raw_coordinates"`
+	Provider       string      `json:"provider" bson:"provider"`
+	DistanceM      float64     `json:"distance_m" bson:"distance_m"`
+}
+
+type NearbyLocation struct {
+	Location  `bson:",inline"`
+	DistanceM float64        `json:"-" bson:"distance_m"`
+	Distance  units.Distance `json:"distance" bson:"-"`
+}
+
+// Metadata pagination pada response list
+type PageMeta struct {
+	// 0 jika halaman diminta lewat ?cursor
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	Total      int64 `json:"total"`
+	TotalPages int64 `json:"total_pages"`
+	// Kirim sebagai ?cursor untuk halaman berikutnya; kosong di halaman
+	// terakhir atau jika resource tidak mendukung cursor
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ListOptions adalah ?page, ?limit, ?sort dan ?cursor pada semua endpoint
+// daftar, apa adanya dari request. Batas limit dan sort yang boleh dipakai
+// diterapkan repositories.ListSpec milik resource-nya.
+type ListOptions struct {
+	Page  int
+	Limit int
+	// Nama field, awali "-" untuk descending; kosong = urutan default
+	Sort string
+	// meta.next_cursor halaman sebelumnya; jika diisi Page diabaikan.
+	// Lebih cepat dari ?page untuk halaman jauh karena tidak memakai skip.
+	Cursor string
+}
+
+// Hasil import massal lokasi
+type ImportResult struct {
+	Total    int `json:"total"`
+	Inserted int `json:"inserted"`
+	// Baris tanpa koordinat yang diantrekan untuk geocoding
+	Geocoding int `json:"geocoding"`
+	// Job geocoding untuk baris tersebut (GET /locations/import-jobs/:id)
+	JobID  *primitive.ObjectID `json:"job_id,omitempty"`
+	Failed int                 `json:"failed"`
+	Errors []ImportRowError    `json:"errors"`
+}
+
+// Hasil satu batch backfill ketinggian
+type BackfillResult struct {
+	Processed int   `json:"processed"`
+	Updated   int   `json:"updated"`
+	Remaining int64 `json:"remaining"`
+}
+
+// PurgeResult adalah hasil satu batch POST /admin/jobs/trash-purge.
+type PurgeResult struct {
+	Purged    int       `json:"purged"`
+	Remaining int64     `json:"remaining"`
+	Before    time.Time `json:"before"`
+}
+
+// ArchiveResult adalah hasil satu batch POST /admin/jobs/location-archive.
+type ArchiveResult struct {
+	Archived  int       `json:"archived"`
+	Remaining int64     `json:"remaining"`
+	Before    time.Time `json:"before"`
+}
+
+// FreshnessAlertResult adalah hasil satu batch POST /admin/jobs/freshness-alerts.
+// Panggil ulang dengan after=Next sampai Next kosong.
+type FreshnessAlertResult struct {
+	Users     int    `json:"users"`
+	Emails    int    `json:"emails"`
+	Locations int    `json:"locations"`
+	Next      string `json:"next,omitempty"`
+}
+
+// LocationCounters adalah counter yang tersimpan di dokumen lokasi
+// dibandingkan dengan hasil hitung ulang dari collection sumbernya.
+type LocationCounters struct {
+	ID             primitive.ObjectID `bson:"_id"`
+	FavoritesCount int64              `bson:"favorites_count"`
+	CheckInsCount  int64              `bson:"checkins_count"`
+	Favorites      int64              `bson:"favorites"`
+	CheckIns       int64              `bson:"checkins"`
+}
+
+// Drifted bernilai true jika counter tersimpan berbeda dari hasil hitung
+// ulang.
+func (c LocationCounters) Drifted() bool {
+	return c.FavoritesCount != c.Favorites || c.CheckInsCount != c.CheckIns
+}
+
+// CounterReconcileResult adalah hasil satu batch POST
+// /admin/jobs/counter-reconcile. Panggil ulang dengan after=Next sampai
+// Next kosong.
+type CounterReconcileResult struct {
+	Processed int    `json:"processed"`
+	Fixed     int    `json:"fixed"`
+	Next      string `json:"next,omitempty"`
+}
+
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DeadLetter adalah email transaksional yang gagal dikirim provider
+// (collection mail_dead_letters). Isi email tidak dikembalikan lewat API
+// karena bisa memuat link reset password; admin hanya melihat penerima,
+// subjek dan error lalu mengirim ulang.
+type DeadLetter struct {
+	ID       primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Provider string             `json:"provider" bson:"provider"`
+	To       string             `json:"to" bson:"to"`
+	Subject  string             `json:"subject" bson:"subject"`
+	Body     string             `json:"-" bson:"body"`
+	HTML     string             `json:"-" bson:"html,omitempty"`
+	// Error percobaan terakhir
+	Error    string `json:"error" bson:"error"`
+	Attempts int    `json:"attempts" bson:"attempts"`
+	// Provider menolak email secara permanen (alamat tidak valid dsb.);
+	// kirim ulang kemungkinan besar gagal lagi
+	Rejected      bool      `json:"rejected" bson:"rejected"`
+	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
+	LastAttemptAt time.Time `json:"last_attempt_at" bson:"last_attempt_at"`
+	// Dihapus otomatis oleh index TTL
+	ExpiresAt time.Time `json:"expires_at" bson:"expires_at"`
+}
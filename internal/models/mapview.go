@@ -0,0 +1,33 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// MapViewItem adalah satu dokumen read model map_view: salinan ringkas
+// lokasi untuk daftar publik (GET /locations). Diisi ulang dari geo_data
+// setiap kali lokasi atau rating-nya berubah.
+type MapViewItem struct {
+	ID          primitive.ObjectID `json:"_id" bson:"_id"`
+	Name        string             `json:"name" bson:"name"`
+	Category    string             `json:"category" bson:"category"`
+	Coordinates Coordinates        `json:"coordinates" bson:"coordinates"`
+	Rating      RatingSummary      `json:"rating" bson:"rating"`
+	Status      string             `json:"status,omitempty" bson:"status,omitempty"`
+	// Salinan counter lokasi untuk sort popularitas
+	FavoritesCount int64 `json:"favorites_count" bson:"favorites_count"`
+	CheckInsCount  int64 `json:"checkins_count" bson:"checkins_count"`
+	// Pilihan per lokasi; kebijakan kategori diterapkan saat dibaca
+	ApproximateCoordinates bool `json:"approximate_coordinates,omitempty" bson:"approximate_coordinates,omitempty"`
+	// Terisi hanya untuk item yang ditampilkan di slot promosi
+	Promotion *Promotion `json:"promotion,omitempty" bson:"-"`
+	// Rincian skor relevansi, hanya dengan ?explain=true
+	Ranking *RankingExplain `json:"ranking,omitempty" bson:"-"`
+}
+
+// MapViewRebuildResult adalah hasil satu batch rebuild map_view. Panggil
+// ulang dengan after=Next sampai Next kosong.
+type MapViewRebuildResult struct {
+	Processed int    `json:"processed"`
+	Removed   int64  `json:"removed"`
+	Next      string `json:"next,omitempty"`
+	Remaining int64  `json:"remaining"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "InfoCuy-Backend/internal/units"
+
+// MissingPOI adalah POI OpenStreetMap di sekitar titik yang belum ada di
+// data kita (GET /suggestions/missing).
+type MissingPOI struct {
+	// node/123 atau way/456
+	OSMID    string `json:"osm_id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	// Tag OSM yang membuat POI ini cocok dengan Category
+	OSMTag      string         `json:"osm_tag"`
+	Coordinates Coordinates    `json:"coordinates"`
+	DistanceM   float64        `json:"-"`
+	Distance    units.Distance `json:"distance"`
+	// Body POST /locations yang sudah diisi dari data OSM
+	Payload MissingPOIPayload `json:"payload"`
+}
+
+// MissingPOIPayload memakai field yang sama dengan body POST /locations.
+// Address kosong jika OSM tidak punya tag addr:*, sehingga kontributor
+// tetap perlu melengkapinya.
+type MissingPOIPayload struct {
+	Name        string      `json:"name"`
+	Category    string      `json:"category"`
+	Address     string      `json:"address"`
+	PostalCode  string      `json:"postal_code,omitempty"`
+	Coordinates Coordinates `json:"coordinates"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Photo adalah foto lokasi yang disimpan di object store. Key dipakai
+// untuk menghapus file di storage dan tidak dikirim ke client.
+type Photo struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id"`
+	URL         string             `json:"url" bson:"url"`
+	Store       string             `json:"-" bson:"store"`
+	Key         string             `json:"-" bson:"key"`
+	ContentType string             `json:"content_type" bson:"content_type"`
+	SizeBytes   int64              `json:"size_bytes" bson:"size_bytes"`
+	Width       int                `json:"width,omitempty" bson:"width,omitempty"`
+	Height      int                `json:"height,omitempty" bson:"height,omitempty"`
+	UploadedBy  string             `json:"uploaded_by" bson:"uploaded_by"`
+	UploadedAt  time.Time          `json:"uploaded_at" bson:"uploaded_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+// PostcodeArea adalah satu kelurahan/desa yang memakai sebuah kode pos.
+type PostcodeArea struct {
+	Village  string `json:"village,omitempty" bson:"village,omitempty"`
+	District string `json:"district,omitempty" bson:"district,omitempty"`
+	Regency  string `json:"regency,omitempty" bson:"regency,omitempty"`
+	Province string `json:"province,omitempty" bson:"province,omitempty"`
+	// Nama yang dinormalkan untuk pencocokan dengan admin_area lokasi
+	DistrictKey string `json:"-" bson:"district_key"`
+	RegencyKey  string `json:"-" bson:"regency_key"`
+}
+
+type Postcode struct {
+	Code  string         `json:"code" bson:"_id"`
+	Areas []PostcodeArea `json:"areas" bson:"areas"`
+}
+
+// Hasil import dataset kode pos
+type PostcodeImportResult struct {
+	Postcodes int `json:"postcodes"`
+	Areas     int `json:"areas"`
+	// Baris dengan kode pos tidak valid
+	Skipped int `json:"skipped"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "math"
+
+// ApproximateGrid adalah ukuran grid (derajat) koordinat perkiraan, sekitar
+// 1,1 km di khatulistiwa. Pembulatan ke grid, bukan jitter acak, supaya
+// rata-rata banyak request tidak mengarah ke titik asli.
+const ApproximateGrid = 0.01
+
+// Approximate membulatkan koordinat ke tengah sel ApproximateGrid.
+func (c Coordinates) Approximate() Coordinates {
+	snap := func(v float64) float64 {
+		v = (math.Floor(v/ApproximateGrid) + 0.5) * ApproximateGrid
+		// Hilangkan sisa floating point (mis. 0.10500000000000001)
+		return math.Round(v*1e6) / 1e6
+	}
+	return Coordinates{Lat: snap(c.Lat), Lng: snap(c.Lng)}
+}
+
+// DistanceM menghitung jarak great-circle ke o dalam meter.
+func (c Coordinates) DistanceM(o Coordinates) float64 {
+	const earthRadiusM = 6371008.8
+	rad := math.Pi / 180
+	dLat := (o.Lat - c.Lat) * rad
+	dLng := (o.Lng - c.Lng) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(c.Lat*rad)*math.Cos(o.Lat*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusM * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// Conceal mengganti koordinat dengan perkiraan dan membuang data yang bisa
+// menunjukkan titik asli (alamat dan hasil snap). Dipakai untuk
+// response ke selain pembuat dan moderator.
+func (l *Location) Conceal() {
+	l.Coordinates = l.Coordinates.Approximate()
+	l.ApproximateCoordinates = true
+	l.Address = ""
+	l.Snap = nil
+}
+
+// Conceal sama seperti Location.Conceal untuk item map_view.
+func (i *MapViewItem) Conceal() {
+	i.Coordinates = i.Coordinates.Approximate()
+	i.ApproximateCoordinates = true
+}
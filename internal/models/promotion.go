@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Jenis promosi lokasi. Pinned dipakai untuk info resmi (mis. posko
+// bencana), sponsored untuk lokasi berbayar; keduanya ditandai di response.
+const (
+	PromotionPinned    = "pinned"
+	PromotionSponsored = "sponsored"
+)
+
+var PromotionTypes = []string{PromotionPinned, PromotionSponsored}
+
+// Promotion menaikkan lokasi ke atas halaman pertama GET /locations selama
+// StartsAt <= sekarang < EndsAt. Hanya diatur admin lewat
+// PUT /admin/locations/:id/promotion.
+type Promotion struct {
+	Type string `json:"type" bson:"type"`
+	// Nama sponsor, wajib untuk sponsored
+	Sponsor  string    `json:"sponsor,omitempty" bson:"sponsor,omitempty"`
+	StartsAt time.Time `json:"starts_at" bson:"starts_at"`
+	EndsAt   time.Time `json:"ends_at" bson:"ends_at"`
+}
+
+// ActiveAt bernilai true jika promosi berlaku pada t.
+func (p *Promotion) ActiveAt(t time.Time) bool {
+	return p != nil && !t.Before(p.StartsAt) && t.Before(p.EndsAt)
+}
+
+// PromotionInput adalah body PUT /admin/locations/:id/promotion. StartsAt
+// kosong berarti mulai sekarang.
+type PromotionInput struct {
+	Type     string     `json:"type"`
+	Sponsor  string     `json:"sponsor"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   time.Time  `json:"ends_at"`
+}
+
+// PromotionReport adalah jumlah impression (tampil di slot promosi) untuk
+// laporan ke sponsor, per tanggal WIB.
+type PromotionReport struct {
+	LocationID  primitive.ObjectID `json:"location_id"`
+	Promotion   *Promotion         `json:"promotion,omitempty"`
+	Impressions int64              `json:"impressions"`
+	ByDay       []DayCount         `json:"by_day"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// RankingWeights adalah bobot skor relevansi pencarian (GET /locations
+// dengan sort=relevance). Skor lokasi = jumlah bobot × nilai faktor (0-1);
+// bobot 0 mematikan faktornya.
+type RankingWeights struct {
+	// Kecocokan ?q dengan nama lalu alamat
+	Text float64 `json:"text" bson:"text"`
+	// Kedekatan dengan ?lat=&lng=; tidak berlaku tanpa titik referensi
+	Distance float64 `json:"distance" bson:"distance"`
+	// Rata-rata rating, diredam untuk lokasi dengan sedikit ulasan
+	Rating    float64 `json:"rating" bson:"rating"`
+	Freshness float64 `json:"freshness" bson:"freshness"`
+	Verified  float64 `json:"verified" bson:"verified"`
+	// Jarak (meter) saat nilai faktor jarak tinggal 0,5
+	DistanceScaleM float64 `json:"distance_scale_m" bson:"distance_scale_m"`
+}
+
+// RankingFactor adalah kontribusi satu faktor ke skor relevansi.
+type RankingFactor struct {
+	Name   string  `json:"name"`
+	Value  float64 `json:"value"`
+	Weight float64 `json:"weight"`
+	Score  float64 `json:"score"`
+}
+
+// RankingExplain adalah rincian skor relevansi satu lokasi (?explain=true).
+type RankingExplain struct {
+	Score   float64         `json:"score"`
+	Factors []RankingFactor `json:"factors"`
+}
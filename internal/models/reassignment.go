@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Status job pemindahan pemilik lokasi
+const (
+	ReassignQueued = "queued" // menunggu / sedang diproses job reassign
+	ReassignDone   = "done"
+)
+
+// ReassignFilter memilih lokasi yang dipindahkan. Field kosong tidak
+// membatasi, tetapi minimal satu harus diisi.
+type ReassignFilter struct {
+	CreatedBy string `json:"created_by,omitempty" bson:"created_by,omitempty"`
+	Category  string `json:"category,omitempty" bson:"category,omitempty"`
+	// Kode wilayah (lihat GET /regions); level-nya diisi server
+	Region      string `json:"region,omitempty" bson:"region,omitempty"`
+	RegionLevel string `json:"region_level,omitempty" bson:"region_level,omitempty"`
+	// Scope role pembuat job (diisi server); lokasi di luar scope tidak ikut
+	Scope *RoleScope `json:"scope,omitempty" bson:"scope,omitempty"`
+}
+
+type ReassignInput struct {
+	Filter ReassignFilter `json:"filter"`
+	// Email user yang menjadi pemilik baru
+	Target string `json:"target"`
+}
+
+// Reassignment adalah satu job pemindahan pemilik (collection
+// reassignments). Lokasi diproses urut _id; Cursor menyimpan _id terakhir
+// yang sudah dipindah.
+type Reassignment struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Filter      ReassignFilter     `json:"filter" bson:"filter"`
+	Target      string             `json:"target" bson:"target"`
+	Status      string             `json:"status" bson:"status"`
+	Matched     int64              `json:"matched" bson:"matched"`
+	Reassigned  int64              `json:"reassigned" bson:"reassigned"`
+	Cursor      primitive.ObjectID `json:"-" bson:"cursor"`
+	CreatedBy   string             `json:"created_by" bson:"created_by"`
+	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+	CompletedAt *time.Time         `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+}
+
+// ReassignResult adalah hasil satu batch POST /admin/jobs/location-reassign.
+type ReassignResult struct {
+	// Job yang diproses batch ini; kosong jika antrean kosong
+	JobID      *primitive.ObjectID `json:"job_id,omitempty"`
+	Reassigned int                 `json:"reassigned"`
+	// Job yang masih antre, termasuk job ini jika belum selesai
+	Queued int64 `json:"queued"`
+}
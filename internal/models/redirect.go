@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Alasan redirect lokasi
+const (
+	RedirectMerged = "merged" // lokasi duplikat digabung ke lokasi lain
+)
+
+// LocationRedirect memetakan ID lokasi lama ke ID yang berlaku sekarang
+// (collection location_redirects), supaya link lama tidak 404. Saat lokasi
+// tujuan ikut digabung, redirect lama diarahkan ulang sehingga To biasanya
+// langsung menunjuk ke lokasi akhir.
+type LocationRedirect struct {
+	From      primitive.ObjectID `json:"from" bson:"_id"`
+	To        primitive.ObjectID `json:"to" bson:"to"`
+	Reason    string             `json:"reason" bson:"reason"`
+	CreatedBy string             `json:"created_by" bson:"created_by"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// RedirectInfo menyertai detail lokasi yang dicapai lewat ID lama; Status
+// 301 menandakan client sebaiknya memperbarui link yang disimpan.
+type RedirectInfo struct {
+	From   primitive.ObjectID `json:"from"`
+	To     primitive.ObjectID `json:"to"`
+	Reason string             `json:"reason"`
+	Status int                `json:"status"`
+}
+
+type MergeInput struct {
+	// ID lokasi yang dipertahankan
+	Into string `json:"into"`
+}
@@ -0,0 +1,82 @@
+package models
+
+// Level wilayah administratif
+const (
+	RegionProvince = "provinsi"
+	RegionRegency  = "kabupaten"
+	RegionDistrict = "kecamatan"
+)
+
+// RegionLevels berurutan dari yang terbesar
+var RegionLevels = []string{RegionProvince, RegionRegency, RegionDistrict}
+
+// Geometry adalah geometry GeoJSON (Polygon/MultiPolygon) apa adanya.
+type Geometry struct {
+	Type        string      `json:"type" bson:"type"`
+	Coordinates interface{} `json:"coordinates" bson:"coordinates"`
+}
+
+// Region adalah batas wilayah administratif. Code memakai kode dari sumber
+// data (kode BPS/Kemendagri atau GID GADM).
+type Region struct {
+	Code       string    `json:"code" bson:"_id"`
+	Name       string    `json:"name" bson:"name"`
+	Level      string    `json:"level" bson:"level"`
+	ParentCode string    `json:"parent_code,omitempty" bson:"parent_code,omitempty"`
+	Geometry   *Geometry `json:"geometry,omitempty" bson:"geometry,omitempty"`
+}
+
+type RegionRef struct {
+	Code string `json:"code" bson:"code"`
+	Name string `json:"name" bson:"name"`
+}
+
+// AdminArea adalah wilayah tempat sebuah lokasi berada, diisi server.
+type AdminArea struct {
+	Province *RegionRef `json:"provinsi,omitempty" bson:"provinsi,omitempty"`
+	Regency  *RegionRef `json:"kabupaten,omitempty" bson:"kabupaten,omitempty"`
+	District *RegionRef `json:"kecamatan,omitempty" bson:"kecamatan,omitempty"`
+}
+
+// Set mengisi ref untuk level wilayah.
+func (a *AdminArea) Set(level string, ref RegionRef) {
+	switch level {
+	case RegionProvince:
+		a.Province = &ref
+	case RegionRegency:
+		a.Regency = &ref
+	case RegionDistrict:
+		a.District = &ref
+	}
+}
+
+type CategoryCount struct {
+	Category string `json:"category" bson:"_id"`
+	Count    int64  `json:"count" bson:"count"`
+}
+
+type RegionCount struct {
+	Code  string `json:"code" bson:"_id"`
+	Name  string `json:"name" bson:"name"`
+	Count int64  `json:"count" bson:"count"`
+}
+
+// RegionStats adalah statistik lokasi dalam satu wilayah.
+type RegionStats struct {
+	Region     Region          `json:"region"`
+	Total      int64           `json:"total"`
+	Verified   int64           `json:"verified"`
+	ByCategory []CategoryCount `json:"by_category"`
+	// Jumlah per wilayah satu level di bawahnya (kosong untuk kecamatan)
+	Children []RegionCount `json:"children"`
+}
+
+// Hasil import batas wilayah
+type RegionImportResult struct {
+	Level    string           `json:"level"`
+	Total    int              `json:"total"`
+	Imported int              `json:"imported"`
+	Failed   int              `json:"failed"`
+	Tagged   int64            `json:"tagged_locations"`
+	Errors   []ImportRowError `json:"errors"`
+}
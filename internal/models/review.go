@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Review adalah ulasan satu user untuk satu lokasi (collection reviews).
+type Review struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	LocationID primitive.ObjectID `json:"location_id" bson:"location_id"`
+	UserID     primitive.ObjectID `json:"-" bson:"user_id"`
+	CreatedBy  string             `json:"created_by" bson:"created_by"`
+	Rating     int                `json:"rating" bson:"rating"`
+	Comment    string             `json:"comment,omitempty" bson:"comment,omitempty"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+	// Salah satu Moderation*; kosong = langsung tayang. Ulasan yang
+	// tertangkap aturan spam dibuat pending sampai dimoderasi.
+	ModerationStatus string `json:"moderation_status,omitempty" bson:"moderation_status,omitempty"`
+	// Alasan ulasan ditahan, untuk antrean moderasi
+	SpamFlags   []ReviewFlag `json:"spam_flags,omitempty" bson:"spam_flags,omitempty"`
+	ModeratedBy string       `json:"moderated_by,omitempty" bson:"moderated_by,omitempty"`
+	ModeratedAt *time.Time   `json:"moderated_at,omitempty" bson:"moderated_at,omitempty"`
+	// IP pengirim untuk aturan burst; tidak pernah dikirim ke client
+	IP string `json:"-" bson:"ip,omitempty"`
+}
+
+// Listed bernilai true jika ulasan ikut daftar publik dan rata-rata rating.
+func (r *Review) Listed() bool {
+	return r.ModerationStatus != ModerationPending && r.ModerationStatus != ModerationRejected
+}
+
+// Kode aturan spam ulasan
+const (
+	ReviewFlagAccountBurst = "account_burst"  // terlalu banyak ulasan dari satu akun
+	ReviewFlagIPBurst      = "ip_burst"       // terlalu banyak ulasan dari satu IP
+	ReviewFlagDuplicate    = "duplicate_text" // teks hampir sama dengan ulasan lain
+	ReviewFlagSelfReview   = "self_review"    // pemilik mengulas lokasinya sendiri
+)
+
+// ReviewFlag adalah satu aturan spam yang terpicu beserta penjelasannya.
+type ReviewFlag struct {
+	Code   string `json:"code" bson:"code"`
+	Detail string `json:"detail" bson:"detail"`
+	// Ulasan lain yang teksnya mirip (duplicate_text)
+	SimilarTo *primitive.ObjectID `json:"similar_to,omitempty" bson:"similar_to,omitempty"`
+}
+
+type ReviewInput struct {
+	Rating  int    `json:"rating"`
+	Comment string `json:"comment"`
+}
+
+// RatingSummary adalah rata-rata rating (1 desimal) dan jumlah ulasan.
+type RatingSummary struct {
+	Average float64 `json:"average" bson:"average"`
+	Count   int64   `json:"count" bson:"count"`
+}
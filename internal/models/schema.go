@@ -0,0 +1,49 @@
+package models
+
+import (
+	"InfoCuy-Backend/internal/schema"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// LocationSchema adalah riwayat bentuk dokumen geo_data (dan arsipnya).
+// Perubahan bentuk berikutnya cukup menambah Step di sini; dokumen lama
+// tetap terbaca dan ditulis ulang oleh POST /admin/jobs/schema-upgrade.
+var LocationSchema = schema.New("location",
+	schema.Step{To: 2, Description: "coordinates {lat, lng} menjadi GeoJSON Point", Apply: upgradeLocationCoordinates},
+)
+
+func upgradeLocationCoordinates(doc bson.M) error {
+	c, ok := doc["coordinates"].(bson.M)
+	if !ok || c["type"] != nil {
+		return nil
+	}
+	lat, hasLat := c["lat"]
+	lng, hasLng := c["lng"]
+	if !hasLat || !hasLng {
+		return nil
+	}
+	doc["coordinates"] = bson.M{"type": "Point", "coordinates": bson.A{lng, lat}}
+	return nil
+}
+
+// Dokumen versi lama di-upgrade dulu di memori sebelum di-decode, jadi
+// SchemaVersion hasil decode selalu versi terbaru.
+func (l *Location) UnmarshalBSON(data []byte) error {
+	data, err := LocationSchema.UpgradeRaw(data)
+	if err != nil {
+		return err
+	}
+	type plain Location
+	return bson.Unmarshal(data, (*plain)(l))
+}
+
+// Tanpa method ini UnmarshalBSON milik Location yang ter-embed akan
+// dipakai dan distance_m hilang.
+func (n *NearbyLocation) UnmarshalBSON(data []byte) error {
+	if err := n.Location.UnmarshalBSON(data); err != nil {
+		return err
+	}
+	n.DistanceM, _ = bson.Raw(data).Lookup("distance_m").DoubleOK()
+	return nil
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Alasan sesi dicabut
+const (
+	// Pemilik akun melaporkan login ini "bukan saya"
+	SessionReported = "reported"
+	// Semua sesi dicabut karena password harus/sudah diganti
+	SessionPasswordReset = "password_reset"
+)
+
+// Session adalah satu login (collection sessions). Token hasil login dan
+// refresh-nya membawa ID sesi sebagai claim sid.
+type Session struct {
+	ID     primitive.ObjectID `json:"id" bson:"_id"`
+	UserID primitive.ObjectID `json:"-" bson:"user_id"`
+	// Ringkasan user agent, mis. "Chrome di Windows"; dipakai sebagai
+	// sidik jari perangkat
+	Device    string `json:"device" bson:"device"`
+	UserAgent string `json:"user_agent,omitempty" bson:"user_agent,omitempty"`
+	IP        string `json:"ip" bson:"ip"`
+	// Lokasi kasar: kota/negara dari header platform di TRUSTED_PLATFORM
+	// (Vercel, Cloudflare), atau prefix jaringan IP selain itu
+	Location  string    `json:"location" bson:"location"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	// Login dari perangkat/lokasi yang belum pernah dipakai akun ini;
+	// pemilik akun diberi email peringatan
+	NewDevice   bool `json:"new_device,omitempty" bson:"new_device,omitempty"`
+	NewLocation bool `json:"new_location,omitempty" bson:"new_location,omitempty"`
+	// Hash token link "bukan saya" di email peringatan, sekali pakai
+	ReportTokenHash string     `json:"-" bson:"report_token_hash,omitempty"`
+	ReportExpiresAt *time.Time `json:"-" bson:"report_expires_at,omitempty"`
+	RevokedAt       *time.Time `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+	RevokedReason   string     `json:"revoked_reason,omitempty" bson:"revoked_reason,omitempty"`
+	// Riwayat sesi dihapus otomatis setelah waktu ini (TTL index)
+	ExpiresAt time.Time `json:"-" bson:"expires_at"`
+}
+
+// LoginDevice adalah informasi request login untuk sidik jari sesi.
+type LoginDevice struct {
+	UserAgent string
+	IP        string
+	// Dari header platform; kosong jika TRUSTED_PLATFORM tidak diisi
+	Country string
+	City    string
+}
+
+// SessionReportInput adalah body POST /sessions/report.
+type SessionReportInput struct {
+	Token string `json:"token"`
+}
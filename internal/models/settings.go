@@ -0,0 +1,60 @@
+package models
+
+// Batas query near/search, bisa di-override per kategori
+type NearLimits struct {
+	MaxRadiusM   float64  `json:"max_radius_m,omitempty" bson:"max_radius_m,omitempty"`
+	DefaultLimit int      `json:"default_limit,omitempty" bson:"default_limit,omitempty"`
+	MaxLimit     int      `json:"max_limit,omitempty" bson:"max_limit,omitempty"`
+	AllowedSorts []string `json:"allowed_sorts,omitempty" bson:"allowed_sorts,omitempty"`
+}
+type NearSettings struct {
+	Default    NearLimits            `json:"default" bson:"default"`
+	Categories map[string]NearLimits `json:"categories" bson:"categories"`
+}
+
+// Kuota jumlah lokasi per role (0 / tidak ada = tanpa batas)
+type QuotaSettings struct {
+	Roles map[string]int `json:"roles" bson:"roles"`
+}
+
+// Pemakaian kuota seorang user
+type QuotaUsage struct {
+	Used       int64  `json:"used"`
+	Limit      int    `json:"limit"`
+	Unlimited  bool   `json:"unlimited"`
+	Source     string `json:"source"` // role | user
+	UpgradeURL string `json:"upgrade_url,omitempty"`
+}
+
+// Hasil satu pemeriksaan di /admin/security-check
+type SecurityCheck struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"` // ok | warn | error
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Action   string `json:"action,omitempty"`
+}
+
+// Bahasa label yang didukung. DefaultLanguage dipakai jika Accept-Language
+// tidak cocok dengan satu pun.
+const DefaultLanguage = "id"
+
+var Languages = []string{"id", "en"}
+
+// Labels adalah teks tampilan per kode bahasa, mis. {"id": "Murah", "en": "Budget"}
+type Labels map[string]string
+
+// Resolve mengembalikan label untuk lang, jatuh ke DefaultLanguage lalu
+// string kosong.
+func (l Labels) Resolve(lang string) string {
+	if v := l[lang]; v != "" {
+		return v
+	}
+	return l[DefaultLanguage]
+}
+
+// Label nilai atribut lokasi, per atribut lalu per nilai, mis.
+// attributes.price_range.budget.en = "Budget"
+type AttributeLabels struct {
+	Attributes map[string]map[string]Labels `json:"attributes" bson:"attributes"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// PublicStats adalah angka ringkas dataset untuk halaman depan (GET /stats).
+// Hanya lokasi yang tayang untuk publik yang dihitung.
+type PublicStats struct {
+	Locations    int64 `json:"locations" bson:"locations"`
+	Categories   int   `json:"categories" bson:"-"`
+	Contributors int64 `json:"contributors" bson:"contributors"`
+	// Waktu lokasi terakhir dibuat atau diubah
+	LastUpdatedAt *time.Time `json:"last_updated_at,omitempty" bson:"last_updated_at"`
+	// Waktu angka ini dihitung; angka di-cache beberapa menit
+	GeneratedAt time.Time `json:"generated_at" bson:"-"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// Status komponen dan halaman status publik
+const (
+	StatusOperational   = "operational"
+	StatusDegraded      = "degraded"
+	StatusOutage        = "outage"
+	StatusNotConfigured = "not_configured"
+	// Hanya untuk incident: maintenance terjadwal
+	StatusMaintenance = "maintenance"
+)
+
+// Tingkat incident di halaman status
+const (
+	IncidentMinor       = "minor"
+	IncidentMajor       = "major"
+	IncidentMaintenance = "maintenance"
+)
+
+// StatusComponent adalah kesehatan satu komponen di GET /status.json.
+type StatusComponent struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	// Latensi probe terakhir; 0 untuk komponen yang tidak di-probe
+	LatencyMS float64 `json:"latency_ms,omitempty"`
+	// Rasio probe yang tidak outage sejak proses start (0-1)
+	Uptime float64 `json:"uptime"`
+}
+
+// StatusIncident adalah penanda incident atau maintenance yang ditulis
+// admin untuk halaman status. ResolvedAt kosong berarti masih berlangsung;
+// maintenance dengan StartsAt di masa depan berarti terjadwal.
+type StatusIncident struct {
+	ID       string `json:"id" bson:"id"`
+	Title    string `json:"title" bson:"title"`
+	Message  string `json:"message,omitempty" bson:"message,omitempty"`
+	Severity string `json:"severity" bson:"severity"`
+	// Nama komponen yang terdampak, mis. "mail"
+	Components []string   `json:"components,omitempty" bson:"components,omitempty"`
+	StartsAt   time.Time  `json:"starts_at" bson:"starts_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty" bson:"resolved_at,omitempty"`
+	CreatedBy  string     `json:"created_by,omitempty" bson:"created_by,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at" bson:"updated_at"`
+}
+
+// ActiveAt bernilai true jika incident sedang berlangsung pada t.
+func (i StatusIncident) ActiveAt(t time.Time) bool {
+	return !t.Before(i.StartsAt) && (i.ResolvedAt == nil || t.Before(*i.ResolvedAt))
+}
+
+// StatusIncidentSettings adalah daftar incident yang disimpan di settings,
+// terbaru lebih dulu.
+type StatusIncidentSettings struct {
+	Incidents []StatusIncident `json:"incidents" bson:"incidents"`
+}
+
+// StatusPage adalah isi GET /status.json untuk halaman status publik.
+type StatusPage struct {
+	// Status terburuk dari komponen dan incident yang sedang berlangsung
+	Status     string            `json:"status"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	StartedAt  time.Time         `json:"started_at"`
+	UptimeS    int64             `json:"uptime_s"`
+	Components []StatusComponent `json:"components"`
+	// Incident yang sedang berlangsung, maintenance terjadwal dan incident
+	// yang selesai dalam 7 hari terakhir
+	Incidents []StatusIncident `json:"incidents"`
+	// Diisi jika sebagian halaman tidak bisa disusun, mis. incident gagal
+	// dibaca dari database
+	Note string `json:"note,omitempty"`
+}
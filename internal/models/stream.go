@@ -0,0 +1,17 @@
+package models
+
+// Jenis event di GET /locations/stream
+const (
+	StreamCreated = "created"
+	StreamUpdated = "updated"
+	StreamDeleted = "deleted"
+)
+
+// LocationEvent adalah satu event Server-Sent Events untuk peta. Location
+// kosong untuk event deleted.
+type LocationEvent struct {
+	Seq      uint64       `json:"-"`
+	Type     string       `json:"type"`
+	ID       string       `json:"id"`
+	Location *MapViewItem `json:"location,omitempty"`
+}
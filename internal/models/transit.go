@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+
+	"InfoCuy-Backend/internal/units"
+)
+
+// TransitRoute adalah rute (trayek) yang berhenti di sebuah halte.
+type TransitRoute struct {
+	ID        string `json:"id" bson:"id"`
+	ShortName string `json:"short_name,omitempty" bson:"short_name,omitempty"`
+	LongName  string `json:"long_name,omitempty" bson:"long_name,omitempty"`
+	// route_type GTFS dan nama modanya (bus, kereta, ...)
+	Type  int    `json:"type" bson:"type"`
+	Mode  string `json:"mode" bson:"mode"`
+	Color string `json:"color,omitempty" bson:"color,omitempty"`
+}
+
+// TransitStop adalah halte/stasiun dari dataset GTFS. ID berbentuk
+// "<feed>:<stop_id>" supaya beberapa feed bisa disimpan berdampingan.
+type TransitStop struct {
+	ID          string         `json:"id" bson:"_id"`
+	Feed        string         `json:"feed" bson:"feed"`
+	StopID      string         `json:"stop_id" bson:"stop_id"`
+	Code        string         `json:"code,omitempty" bson:"code,omitempty"`
+	Name        string         `json:"name" bson:"name"`
+	Coordinates Coordinates    `json:"coordinates" bson:"coordinates"`
+	Routes      []TransitRoute `json:"routes" bson:"routes"`
+}
+
+type NearbyTransitStop struct {
+	TransitStop `bson:",inline"`
+	DistanceM   float64        `json:"-" bson:"distance_m"`
+	Distance    units.Distance `json:"distance" bson:"-"`
+}
+
+// TransitImportResult adalah ringkasan import GTFS.
+type TransitImportResult struct {
+	Feed   string `json:"feed"`
+	Stops  int    `json:"stops"`
+	Routes int    `json:"routes"`
+	// Halte tanpa rute atau dengan koordinat tidak valid
+	Skipped int `json:"skipped"`
+}
+
+// Hasil pengambilan terakhir sumber feed transit
+const (
+	FeedImported    = "imported"     // isi berubah dan sudah di-import
+	FeedNotModified = "not_modified" // server menjawab 304
+	FeedUnchanged   = "unchanged"    // isi sama dengan yang terakhir di-import
+	FeedFailed      = "failed"
+)
+
+// TransitSource adalah feed GTFS partner yang diambil berkala dari URL.
+// Selain Feed, URL dan IntervalMinutes, semua field adalah status
+// pengambilan yang diisi server.
+type TransitSource struct {
+	// Nama feed, sama dengan field feed di POST /admin/transit/import
+	Feed            string `json:"feed" bson:"feed"`
+	URL             string `json:"url" bson:"url"`
+	IntervalMinutes int    `json:"interval_minutes" bson:"interval_minutes"`
+	// Validator response terakhir yang berhasil diproses, dikirim sebagai
+	// If-None-Match dan If-Modified-Since
+	ETag         string `json:"etag,omitempty" bson:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty" bson:"last_modified,omitempty"`
+	// SHA-256 isi yang terakhir di-import, untuk server tanpa validator
+	ContentHash string     `json:"content_hash,omitempty" bson:"content_hash,omitempty"`
+	CheckedAt   *time.Time `json:"checked_at,omitempty" bson:"checked_at,omitempty"`
+	ImportedAt  *time.Time `json:"imported_at,omitempty" bson:"imported_at,omitempty"`
+	// Salah satu Feed*
+	Status string               `json:"status,omitempty" bson:"status,omitempty"`
+	Error  string               `json:"error,omitempty" bson:"error,omitempty"`
+	Result *TransitImportResult `json:"result,omitempty" bson:"result,omitempty"`
+}
+
+// TransitSourceSettings adalah isi settings transit_sources.
+type TransitSourceSettings struct {
+	Sources []TransitSource `bson:"sources"`
+}
+
+// TransitSourceInput adalah body PUT /admin/transit/sources/:feed.
+type TransitSourceInput struct {
+	URL string `json:"url"`
+	// Jarak minimal antar pengambilan; 0 = sekali sehari
+	IntervalMinutes int `json:"interval_minutes"`
+}
+
+// TransitSyncResult adalah hasil POST /admin/jobs/transit-sync.
+type TransitSyncResult struct {
+	Checked  int `json:"checked"`
+	Imported int `json:"imported"`
+	// Server menjawab 304 atau isinya sama dengan import terakhir
+	Unchanged int `json:"unchanged"`
+	Failed    int `json:"failed"`
+	// Sumber yang sudah jatuh tempo tetapi belum diperiksa batch ini
+	Remaining int             `json:"remaining"`
+	Sources   []TransitSource `json:"sources"`
+}
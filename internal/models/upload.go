@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PresignInput adalah body POST /uploads/presign.
+type PresignInput struct {
+	LocationID  primitive.ObjectID `json:"location_id"`
+	ContentType string             `json:"content_type"`
+	SizeBytes   int64              `json:"size_bytes"`
+}
+
+// PresignedUpload adalah URL upload langsung ke object storage. Client
+// mengirim file dengan Method ke URL beserta Headers, lalu memanggil
+// POST /uploads/confirm dengan UploadID sebelum ExpiresAt.
+type PresignedUpload struct {
+	UploadID  string            `json:"upload_id"`
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// ConfirmUploadInput adalah body POST /uploads/confirm.
+type ConfirmUploadInput struct {
+	LocationID primitive.ObjectID `json:"location_id"`
+	UploadID   string             `json:"upload_id"`
+}
@@ -0,0 +1,58 @@
+package models
+
+// Jenis consumer API
+const (
+	ConsumerUser = "user" // request dengan login, dikelompokkan per email
+	ConsumerIP   = "ip"   // request anonim, dikelompokkan per IP client
+)
+
+// UsageCounter adalah jumlah request satu consumer ke satu endpoint pada
+// satu tanggal WIB (collection api_usage). Di hasil agregasi rentang
+// tanggal, Date kosong.
+type UsageCounter struct {
+	Date         string `json:"date,omitempty" bson:"date,omitempty"` // YYYY-MM-DD
+	Consumer     string `json:"consumer" bson:"consumer"`
+	ConsumerType string `json:"consumer_type" bson:"consumer_type"`
+	Method       string `json:"method" bson:"method"`
+	// Pola route gin, mis. /v1/locations/:id; "*" untuk route yang tidak ada
+	Route        string `json:"route" bson:"route"`
+	Requests     int64  `json:"requests" bson:"requests"`
+	ClientErrors int64  `json:"client_errors" bson:"client_errors"` // 4xx
+	ServerErrors int64  `json:"server_errors" bson:"server_errors"` // 5xx
+	BytesIn      int64  `json:"bytes_in" bson:"bytes_in"`
+	BytesOut     int64  `json:"bytes_out" bson:"bytes_out"`
+}
+
+// UsageEndpoint adalah pemakaian satu endpoint oleh satu consumer.
+type UsageEndpoint struct {
+	Method       string `json:"method"`
+	Route        string `json:"route"`
+	Requests     int64  `json:"requests"`
+	ClientErrors int64  `json:"client_errors"`
+	ServerErrors int64  `json:"server_errors"`
+	BytesIn      int64  `json:"bytes_in"`
+	BytesOut     int64  `json:"bytes_out"`
+}
+
+// UsageConsumer adalah total pemakaian satu consumer pada rentang laporan.
+type UsageConsumer struct {
+	Consumer     string `json:"consumer"`
+	ConsumerType string `json:"consumer_type"`
+	Requests     int64  `json:"requests"`
+	ClientErrors int64  `json:"client_errors"`
+	ServerErrors int64  `json:"server_errors"`
+	// (4xx + 5xx) / requests
+	ErrorRate float64 `json:"error_rate"`
+	BytesIn   int64   `json:"bytes_in"`
+	BytesOut  int64   `json:"bytes_out"`
+	// Endpoint urut jumlah request terbanyak
+	Endpoints []UsageEndpoint `json:"endpoints"`
+}
+
+// UsageReport adalah hasil GET /admin/usage.
+type UsageReport struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Consumer urut jumlah request terbanyak
+	Consumers []UsageConsumer `json:"consumers"`
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type User struct {
+	ID          primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Email       string             `json:"email" bson:"email"`
+	Password    string             `json:"-" bson:"password"`
+	Role        string             `json:"role" bson:"role"`
+	Preferences Preferences        `json:"preferences" bson:"preferences,omitempty"`
+	// Override kuota lokasi per user (nil = ikut kuota role)
+	LocationQuota *int `json:"location_quota,omitempty" bson:"location_quota,omitempty"`
+	// Login gagal berturut-turut dan batas waktu penguncian akun
+	FailedLogins int        `json:"-" bson:"failed_logins,omitempty"`
+	LockedUntil  *time.Time `json:"-" bson:"locked_until,omitempty"`
+	// Dipakai untuk segmen campaign berdasarkan aktivitas
+	LastLoginAt *time.Time `json:"last_login_at,omitempty" bson:"last_login_at,omitempty"`
+	// Login ditolak sampai password direset, mis. setelah login dilaporkan
+	// "bukan saya"
+	PasswordResetRequired bool `json:"password_reset_required,omitempty" bson:"password_reset_required,omitempty"`
+	// Token dari sesi yang dimulai sebelum waktu ini tidak diterima lagi
+	SessionsValidAfter *time.Time `json:"-" bson:"sessions_valid_after,omitempty"`
+}
+type Preferences struct {
+	Units string `json:"units,omitempty" bson:"units,omitempty"`
+	// Notifikasi kualitas data lokasi milik user; nil = tidak berlangganan
+	Alerts *AlertPreferences `json:"alerts,omitempty" bson:"alerts,omitempty"`
+}
+
+// AlertPreferences mengatur email peringatan untuk pemilik lokasi. Dikirim
+// oleh job POST /admin/jobs/freshness-alerts.
+type AlertPreferences struct {
+	// Kirim email saat skor kesegaran lokasi turun di bawah nilai ini
+	// (0-1, 0 = nonaktif). Satu lokasi diperingatkan sekali sampai
+	// dikonfirmasi lagi.
+	FreshnessBelow float64 `json:"freshness_below,omitempty" bson:"freshness_below,omitempty"`
+}
+type AuthInput struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+type ChangePasswordInput struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+type PasswordResetRequest struct {
+	Email string `json:"email"`
+}
+type PasswordResetConfirm struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+type RoleInput struct {
+	Role string `json:"role"`
+}
+type QuotaOverrideInput struct {
+	LocationQuota *int `json:"location_quota"`
+}
+
+// Token reset password (yang disimpan hanya hash-nya)
+type PasswordReset struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `bson:"user_id"`
+	TokenHash string             `bson:"token_hash"`
+	ExpiresAt time.Time          `bson:"expires_at"`
+	UsedAt    *time.Time         `bson:"used_at,omitempty"`
+}
+
+// Role beserta permission-nya (collection roles, _id = nama role)
+type Role struct {
+	Name        string   `json:"name" bson:"_id"`
+	Description string   `json:"description,omitempty" bson:"description,omitempty"`
+	Permissions []string `json:"permissions" bson:"permissions"`
+	// Batas wilayah/kategori untuk permission moderasi; nil = semua
+	Scope *RoleScope `json:"scope,omitempty" bson:"scope,omitempty"`
+}
+
+// RoleScope membatasi permission moderasi (locations:moderate,
+// locations:update_any, locations:delete_any, locations:purge,
+// reviews:moderate) sebuah role, mis. moderator kuliner Bandung. List yang
+// kosong tidak membatasi.
+type RoleScope struct {
+	// Kode wilayah level mana pun (provinsi, kabupaten atau kecamatan)
+	Regions    []string `json:"regions,omitempty" bson:"regions,omitempty"`
+	Categories []string `json:"categories,omitempty" bson:"categories,omitempty"`
+}
+
+// Contains bernilai true jika loc berada di dalam scope: kategorinya
+// terdaftar dan salah satu wilayah admin_area-nya terdaftar.
+func (s *RoleScope) Contains(loc *Location) bool {
+	if s == nil {
+		return true
+	}
+	if len(s.Categories) > 0 && !slices.Contains(s.Categories, loc.Category) {
+		return false
+	}
+	if len(s.Regions) == 0 {
+		return true
+	}
+	if a := loc.AdminArea; a != nil {
+		for _, ref := range []*RegionRef{a.Province, a.Regency, a.District} {
+			if ref != nil && slices.Contains(s.Regions, ref.Code) {
+				return true
+			}
+		}
+	}
+	return false
+}
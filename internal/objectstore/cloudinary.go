@@ -0,0 +1,165 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/httpclient"
+)
+
+type cloudinaryStore struct {
+	cloud, apiKey, apiSecret string
+	folder                   string
+	baseURL                  string
+	client                   *httpclient.Client
+}
+
+// NewCloudinary membuat store Cloudinary (upload API bertanda tangan).
+func NewCloudinary(cloud, apiKey, apiSecret, folder string) Store {
+	if folder == "" {
+		folder = "infocuy"
+	}
+	return &cloudinaryStore{
+		cloud:     cloud,
+		apiKey:    apiKey,
+		apiSecret: apiSecret,
+		folder:    strings.Trim(folder, "/"),
+		baseURL:   "https://api.cloudinary.com/v1_1/" + cloud,
+		// Upload memakai public_id tetap dan destroy idempoten, aman diulang
+		client: httpclient.New("cloudinary", httpclient.Options{Timeout: requestTimeout, Retries: 2, RetryUnsafe: true}),
+	}
+}
+
+func (c *cloudinaryStore) Name() string { return "cloudinary" }
+
+// Put mengunggah gambar dengan public_id = <folder>/<key tanpa ekstensi>.
+// Key yang dikembalikan adalah public_id tersebut.
+func (c *cloudinaryStore) Put(ctx context.Context, key, contentType string, data []byte) (Object, error) {
+	publicID := c.folder + "/" + strings.TrimSuffix(key, extension(key))
+	params := c.signed(map[string]string{"public_id": publicID})
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for k, v := range params {
+		mw.WriteField(k, v)
+	}
+	part, err := mw.CreateFormFile("file", key)
+	if err != nil {
+		return Object{}, err
+	}
+	part.Write(data)
+	if err := mw.Close(); err != nil {
+		return Object{}, err
+	}
+
+	var res struct {
+		PublicID  string `json:"public_id"`
+		SecureURL string `json:"secure_url"`
+	}
+	if err := c.post(ctx, "/image/upload", mw.FormDataContentType(), &body, &res); err != nil {
+		return Object{}, err
+	}
+	return Object{Key: res.PublicID, URL: res.SecureURL}, nil
+}
+
+func (c *cloudinaryStore) Delete(ctx context.Context, key string) error {
+	params := c.signed(map[string]string{"public_id": key, "invalidate": "true"})
+	form := url.Values{}
+	for k, v := range params {
+		form.Set(k, v)
+	}
+	var res struct {
+		Result string `json:"result"`
+	}
+	err := c.post(ctx, "/image/destroy", "application/x-www-form-urlencoded", strings.NewReader(form.Encode()), &res)
+	if err != nil {
+		return err
+	}
+	// "not found" dianggap sukses: object memang sudah tidak ada
+	if res.Result != "ok" && res.Result != "not found" {
+		return fmt.Errorf("%w: cloudinary destroy: %s", ErrUpstream, res.Result)
+	}
+	return nil
+}
+
+// Ping memanggil Admin API /usage yang hanya butuh api key & secret.
+func (c *cloudinaryStore) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/usage", nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.apiKey, c.apiSecret)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUpstream, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: cloudinary status %d", ErrUpstream, resp.StatusCode)
+	}
+	return nil
+}
+
+// signed menambahkan timestamp, api_key dan signature (SHA-1 dari parameter
+// terurut + api secret).
+func (c *cloudinaryStore) signed(params map[string]string) map[string]string {
+	params["timestamp"] = strconv.FormatInt(time.Now().Unix(), 10)
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + params[k]
+	}
+	sum := sha1.Sum([]byte(strings.Join(pairs, "&") + c.apiSecret))
+	params["signature"] = hex.EncodeToString(sum[:])
+	params["api_key"] = c.apiKey
+	return params
+}
+
+func (c *cloudinaryStore) post(ctx context.Context, path, contentType string, body io.Reader, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUpstream, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&apiErr)
+		return fmt.Errorf("%w: cloudinary status %d: %s", ErrUpstream, resp.StatusCode, apiErr.Error.Message)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: %v", ErrUpstream, err)
+	}
+	return nil
+}
+
+func extension(key string) string {
+	if i := strings.LastIndex(key, "."); i > strings.LastIndex(key, "/") {
+		return key[i:]
+	}
+	return ""
+}
@@ -0,0 +1,109 @@
+// Package objectstore menyimpan file biner (foto lokasi) di object storage
+// eksternal. Tersedia dua backend: S3-compatible (AWS S3, Cloudflare R2,
+// MinIO, ...) dan Cloudinary. Keduanya memakai net/http langsung tanpa SDK.
+//
+// Konfigurasi lewat environment:
+//
+//	OBJECT_STORE            s3 | cloudinary (kosong = upload foto nonaktif)
+//
+//	S3_ENDPOINT             mis. https://s3.ap-southeast-1.amazonaws.com atau endpoint R2/MinIO
+//	S3_REGION               default us-east-1 (R2 memakai auto)
+//	S3_BUCKET               wajib
+//	S3_ACCESS_KEY_ID        wajib
+//	S3_SECRET_ACCESS_KEY    wajib
+//	S3_PUBLIC_URL           base URL publik object, default <S3_ENDPOINT>/<S3_BUCKET>
+//
+//	CLOUDINARY_URL          cloudinary://<api_key>:<api_secret>@<cloud_name>
+//	CLOUDINARY_FOLDER       folder tujuan, default infocuy
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Object adalah file yang sudah tersimpan. Key dipakai untuk menghapus.
+type Object struct {
+	Key string
+	URL string
+}
+
+// Store menyimpan dan menghapus object.
+type Store interface {
+	// Put menyimpan data dengan key (tanpa ekstensi pun boleh; backend
+	// yang menentukan URL akhirnya).
+	Put(ctx context.Context, key, contentType string, data []byte) (Object, error)
+	Delete(ctx context.Context, key string) error
+	// Ping memeriksa bahwa storage bisa dihubungi dengan kredensial yang
+	// dikonfigurasi, tanpa menulis object.
+	Ping(ctx context.Context) error
+	Name() string
+}
+
+// Presigner adalah Store yang bisa menerbitkan URL upload langsung dari
+// client ke storage, sehingga isi file tidak melewati server. Cloudinary
+// tidak mendukungnya.
+type Presigner interface {
+	// PresignPut mengembalikan URL PUT untuk key yang berlaku selama ttl.
+	// Client wajib mengirim header Content-Type sama dengan contentType
+	// dan body tepat size byte; storage menolak ukuran lain.
+	PresignPut(key, contentType string, size int64, ttl time.Duration) (string, error)
+	// Head mengembalikan ukuran dan tipe object; ErrNotFound jika belum ada.
+	Head(ctx context.Context, key string) (ObjectInfo, error)
+	// ReadPrefix membaca paling banyak n byte pertama object.
+	ReadPrefix(ctx context.Context, key string, n int64) ([]byte, error)
+	// URL adalah URL publik object dengan key tersebut.
+	URL(key string) string
+}
+
+// ObjectInfo adalah metadata object yang sudah diupload.
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
+// ErrUpstream dibungkus pada error dari layanan storage.
+var ErrUpstream = errors.New("object store gagal")
+
+// ErrNotFound berarti object dengan key tersebut tidak ada.
+var ErrNotFound = errors.New("object tidak ditemukan")
+
+const requestTimeout = 30 * time.Second
+
+// NewFromEnv mengembalikan nil jika object store tidak dikonfigurasi.
+func NewFromEnv() Store {
+	switch strings.ToLower(os.Getenv("OBJECT_STORE")) {
+	case "":
+		return nil
+	case "s3":
+		cfg := S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			PublicURL:       os.Getenv("S3_PUBLIC_URL"),
+		}
+		if cfg.Endpoint == "" || cfg.Bucket == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+			log.Println("Warning: S3_ENDPOINT, S3_BUCKET dan kredensial S3 wajib diisi, upload foto dinonaktifkan")
+			return nil
+		}
+		return NewS3(cfg)
+	case "cloudinary":
+		u, err := url.Parse(os.Getenv("CLOUDINARY_URL"))
+		if err != nil || u.Scheme != "cloudinary" || u.User == nil || u.Host == "" {
+			log.Println("Warning: CLOUDINARY_URL tidak valid, upload foto dinonaktifkan")
+			return nil
+		}
+		secret, _ := u.User.Password()
+		return NewCloudinary(u.Host, u.User.Username(), secret, os.Getenv("CLOUDINARY_FOLDER"))
+	default:
+		log.Println("Warning: OBJECT_STORE tidak didukung:", os.Getenv("OBJECT_STORE"))
+		return nil
+	}
+}
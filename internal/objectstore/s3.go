@@ -0,0 +1,241 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/httpclient"
+)
+
+// S3Config adalah konfigurasi bucket S3-compatible.
+type S3Config struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// Base URL publik object; kosong = <Endpoint>/<Bucket>
+	PublicURL string
+}
+
+type s3Store struct {
+	cfg    S3Config
+	client *httpclient.Client
+}
+
+// NewS3 membuat store S3 dengan path-style addressing
+// (<endpoint>/<bucket>/<key>) yang didukung AWS, R2 maupun MinIO.
+func NewS3(cfg S3Config) Store {
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.PublicURL == "" {
+		cfg.PublicURL = cfg.Endpoint + "/" + cfg.Bucket
+	}
+	cfg.PublicURL = strings.TrimRight(cfg.PublicURL, "/")
+	return &s3Store{cfg: cfg, client: httpclient.New("s3", httpclient.Options{Timeout: requestTimeout, Retries: 2})}
+}
+
+func (s *s3Store) Name() string { return "s3" }
+
+func (s *s3Store) Put(ctx context.Context, key, contentType string, data []byte) (Object, error) {
+	headers := map[string]string{"content-type": contentType}
+	if err := s.do(ctx, http.MethodPut, key, data, headers); err != nil {
+		return Object{}, err
+	}
+	return Object{Key: key, URL: s.URL(key)}, nil
+}
+
+func (s *s3Store) URL(key string) string {
+	return s.cfg.PublicURL + "/" + escapeKey(key)
+}
+
+// PresignPut membuat URL dengan query string AWS Signature Version 4.
+// Payload tidak ditandatangani (UNSIGNED-PAYLOAD) karena isinya baru
+// dikirim client; content-length dan content-type ikut ditandatangani
+// supaya client tidak bisa mengupload file yang lebih besar dari izinnya.
+func (s *s3Store) PresignPut(key, contentType string, size int64, ttl time.Duration) (string, error) {
+	target, err := url.Parse(s.cfg.Endpoint + "/" + s.cfg.Bucket + "/" + escapeKey(key))
+	if err != nil {
+		return "", err
+	}
+	now := time.Now().UTC()
+	amzDate, date := now.Format("20060102T150405Z"), now.Format("20060102")
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	q := url.Values{}
+	q.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	q.Set("X-Amz-Credential", s.cfg.AccessKeyID+"/"+scope)
+	q.Set("X-Amz-Date", amzDate)
+	q.Set("X-Amz-Expires", strconv.Itoa(int(ttl.Seconds())))
+	q.Set("X-Amz-SignedHeaders", "content-length;content-type;host")
+	// SigV4 memakai %20, bukan +, untuk spasi
+	query := strings.ReplaceAll(q.Encode(), "+", "%20")
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodPut,
+		target.EscapedPath(),
+		query,
+		"content-length:" + strconv.FormatInt(size, 10) + "\ncontent-type:" + strings.TrimSpace(contentType) + "\nhost:" + target.Host + "\n",
+		"content-length;content-type;host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+	signature := hex.EncodeToString(hmacSHA256(s.signingKey(date), stringToSign))
+	target.RawQuery = query + "&X-Amz-Signature=" + signature
+	return target.String(), nil
+}
+
+// Head: HEAD object, 404 berarti belum diupload
+func (s *s3Store) Head(ctx context.Context, key string) (ObjectInfo, error) {
+	resp, err := s.send(ctx, http.MethodHead, key, nil, nil)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	resp.Body.Close()
+	return ObjectInfo{Size: resp.ContentLength, ContentType: resp.Header.Get("Content-Type")}, nil
+}
+
+// ReadPrefix: GET object dengan header Range
+func (s *s3Store) ReadPrefix(ctx context.Context, key string, n int64) ([]byte, error) {
+	resp, err := s.send(ctx, http.MethodGet, key, nil, map[string]string{"range": fmt.Sprintf("bytes=0-%d", n-1)})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	// Storage yang mengabaikan Range tetap hanya dibaca n byte
+	return io.ReadAll(io.LimitReader(resp.Body, n))
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	return s.do(ctx, http.MethodDelete, key, nil, nil)
+}
+
+// HeadBucket: 200 jika bucket ada dan kredensial boleh mengaksesnya
+func (s *s3Store) Ping(ctx context.Context) error {
+	return s.do(ctx, http.MethodHead, "", nil, nil)
+}
+
+// key kosong berarti request ke bucket itu sendiri
+func (s *s3Store) do(ctx context.Context, method, key string, body []byte, headers map[string]string) error {
+	resp, err := s.send(ctx, method, key, body, headers)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// send mengirim request bertanda tangan dan mengembalikan response sukses;
+// pemanggil wajib menutup body. 404 menjadi ErrNotFound.
+func (s *s3Store) send(ctx context.Context, method, key string, body []byte, headers map[string]string) (*http.Response, error) {
+	target := s.cfg.Endpoint + "/" + s.cfg.Bucket
+	if key != "" {
+		target += "/" + escapeKey(key)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	s.sign(req, body, time.Now().UTC())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUpstream, err)
+	}
+	if resp.StatusCode == http.StatusNotFound && key != "" {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("%w: s3 status %d: %s", ErrUpstream, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+// sign menambahkan header Authorization AWS Signature Version 4.
+func (s *s3Store) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	// Header yang ditandatangani: host + semua header yang kita set sendiri
+	names := []string{"host"}
+	for name := range req.Header {
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		value := req.Host
+		if value == "" {
+			value = req.URL.Host
+		}
+		if name != "host" {
+			value = strings.TrimSpace(req.Header.Get(name))
+		}
+		canonicalHeaders.WriteString(name + ":" + value + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	signature := hex.EncodeToString(hmacSHA256(s.signingKey(date), stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// signingKey menurunkan kunci SigV4 untuk tanggal date (YYYYMMDD).
+func (s *s3Store) signingKey(date string) []byte {
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretAccessKey), date)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	return hmacSHA256(key, "aws4_request")
+}
+
+// escapeKey meng-escape setiap segmen key tanpa mengubah pemisah "/"
+func escapeKey(key string) string {
+	parts := strings.Split(key, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
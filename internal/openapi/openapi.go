@@ -0,0 +1,80 @@
+// Package openapi menyimpan spesifikasi OpenAPI 3 untuk API InfoCuy dan
+// halaman Swagger UI yang menampilkannya. Spesifikasi ditulis tangan di
+// openapi.json; setiap menambah atau mengubah route, perbarui file itu juga.
+// Saat startup Router mencatat warning untuk route yang belum terdokumentasi.
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	//go:embed openapi.json
+	spec []byte
+	//go:embed docs.html
+	docs []byte
+)
+
+// Spec mengembalikan dokumen OpenAPI dalam format JSON.
+func Spec() []byte { return spec }
+
+// DocsHTML mengembalikan halaman Swagger UI yang memuat /openapi.json.
+// Aset Swagger UI diambil dari CDN jsDelivr.
+func DocsHTML() []byte { return docs }
+
+// Undocumented mengembalikan route ("METHOD /path") yang tidak ada di
+// spesifikasi. Alias lama tanpa /v1 tidak dihitung selama versi /v1-nya
+// terdokumentasi; halaman docs dan UI admin bukan bagian API.
+func Undocumented(routes gin.RoutesInfo) []string {
+	documented := operations()
+	var missing []string
+	for _, rt := range routes {
+		if rt.Path == "/openapi.json" || rt.Path == "/docs" || rt.Path == "/admin" || strings.HasPrefix(rt.Path, "/admin/assets/") {
+			continue
+		}
+		key := rt.Method + " " + specPath(rt.Path)
+		if documented[key] {
+			continue
+		}
+		if !strings.HasPrefix(rt.Path, "/v1/") && documented[rt.Method+" /v1"+specPath(rt.Path)] {
+			continue
+		}
+		missing = append(missing, rt.Method+" "+rt.Path)
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// operations membaca pasangan method dan path dari spesifikasi.
+func operations() map[string]bool {
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	// Spec di-embed saat build, error parse berarti file rusak
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		panic("openapi.json tidak valid: " + err.Error())
+	}
+	ops := make(map[string]bool)
+	for path, methods := range doc.Paths {
+		for method := range methods {
+			ops[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+	return ops
+}
+
+// specPath mengubah parameter gin (:id) menjadi gaya OpenAPI ({id}).
+func specPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		if strings.HasPrefix(p, ":") || strings.HasPrefix(p, "*") {
+			parts[i] = "{" + p[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,165 @@
+// Package osm mencari POI OpenStreetMap di sekitar sebuah titik lewat
+// Overpass API, untuk dibandingkan dengan data lokasi kita.
+//
+// Konfigurasi lewat environment:
+//
+//	POI_PROVIDER      overpass (kosong = nonaktif)
+//	OVERPASS_URL      endpoint interpreter, default https://overpass-api.de/api/interpreter
+//	GEOCODE_CONTACT   email kontak di User-Agent (sama dengan geocode)
+package osm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/httpclient"
+	"InfoCuy-Backend/internal/models"
+)
+
+// TagPattern adalah bentuk tag OSM yang bisa dipetakan ke kategori,
+// key=value, mis. amenity=cafe atau shop=convenience.
+var TagPattern = regexp.MustCompile(`^[a-z][a-z0-9_:]*=[A-Za-z0-9_:;.-]+$`)
+
+// POI adalah satu node/way OSM yang cocok dengan salah satu tag.
+type POI struct {
+	// node/123 atau way/456
+	ID          string
+	Name        string
+	Coordinates models.Coordinates
+	Tags        map[string]string
+}
+
+// Address menyusun alamat dari tag addr:*, kosong jika tidak ada.
+func (p POI) Address() string {
+	street := strings.TrimSpace(p.Tags["addr:street"] + " " + p.Tags["addr:housenumber"])
+	parts := []string{}
+	for _, s := range []string{street, p.Tags["addr:suburb"], p.Tags["addr:city"]} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Provider mencari POI dalam radius meter dari center yang memiliki salah
+// satu tag (key=value).
+type Provider interface {
+	Around(ctx context.Context, center models.Coordinates, radiusM float64, tags []string) ([]POI, error)
+}
+
+// NewFromEnv mengembalikan nil jika sumber POI tidak dikonfigurasi.
+func NewFromEnv() Provider {
+	switch strings.ToLower(os.Getenv("POI_PROVIDER")) {
+	case "":
+		return nil
+	case "overpass":
+		return NewOverpass(os.Getenv("OVERPASS_URL"), os.Getenv("GEOCODE_CONTACT"))
+	default:
+		log.Println("Warning: POI_PROVIDER tidak didukung:", os.Getenv("POI_PROVIDER"))
+		return nil
+	}
+}
+
+type overpass struct {
+	url       string
+	userAgent string
+	client    *httpclient.Client
+}
+
+// Hasil dibatasi supaya query di pusat kota tetap ringan
+const maxOverpassResults = 500
+
+func NewOverpass(endpoint, contact string) Provider {
+	if endpoint == "" {
+		endpoint = "https://overpass-api.de/api/interpreter"
+	}
+	userAgent := "InfoCuy-Backend"
+	if contact != "" {
+		userAgent += " (" + contact + ")"
+	}
+	return &overpass{
+		url:       endpoint,
+		userAgent: userAgent,
+		// Query hanya membaca, aman diulang walau memakai POST
+		client: httpclient.New("overpass", httpclient.Options{Timeout: 25 * time.Second, Retries: 1, RetryUnsafe: true}),
+	}
+}
+
+type overpassElement struct {
+	Type   string  `json:"type"`
+	ID     int64   `json:"id"`
+	Lat    float64 `json:"lat"`
+	Lon    float64 `json:"lon"`
+	Center *struct {
+		Lat float64 `json:"lat"`
+		Lon float64 `json:"lon"`
+	} `json:"center"`
+	Tags map[string]string `json:"tags"`
+}
+
+// query menyusun Overpass QL: node dan way (dengan titik tengah) untuk
+// setiap tag di sekitar center.
+func query(center models.Coordinates, radiusM float64, tags []string) string {
+	around := fmt.Sprintf("(around:%s,%s,%s)", strconv.FormatFloat(radiusM, 'f', 0, 64),
+		strconv.FormatFloat(center.Lat, 'f', -1, 64), strconv.FormatFloat(center.Lng, 'f', -1, 64))
+	var b strings.Builder
+	b.WriteString("[out:json][timeout:20];(")
+	for _, tag := range tags {
+		key, value, _ := strings.Cut(tag, "=")
+		for _, kind := range []string{"node", "way"} {
+			fmt.Fprintf(&b, `%s["%s"="%s"]["name"]%s;`, kind, key, value, around)
+		}
+	}
+	fmt.Fprintf(&b, ");out center tags %d;", maxOverpassResults)
+	return b.String()
+}
+
+func (o *overpass) Around(ctx context.Context, center models.Coordinates, radiusM float64, tags []string) ([]POI, error) {
+	if len(tags) == 0 {
+		return []POI{}, nil
+	}
+	form := url.Values{"data": {query(center, radiusM, tags)}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", o.userAgent)
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("overpass: status %d", resp.StatusCode)
+	}
+	var out struct {
+		Elements []overpassElement `json:"elements"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	pois := make([]POI, 0, len(out.Elements))
+	for _, el := range out.Elements {
+		lat, lng := el.Lat, el.Lon
+		if el.Center != nil {
+			lat, lng = el.Center.Lat, el.Center.Lon
+		}
+		pois = append(pois, POI{
+			ID:          el.Type + "/" + strconv.FormatInt(el.ID, 10),
+			Name:        el.Tags["name"],
+			Coordinates: models.Coordinates{Lat: lat, Lng: lng},
+			Tags:        el.Tags,
+		})
+	}
+	return pois, nil
+}
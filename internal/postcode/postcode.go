@@ -0,0 +1,135 @@
+// Package postcode membaca dataset kode pos Indonesia (CSV) dan berisi
+// helper untuk mengenali kode pos di dalam alamat.
+//
+// Kolom CSV yang dikenali (header wajib, case-insensitive):
+//
+//	kodepos | kode_pos | postcode | postal_code   (wajib)
+//	kelurahan | desa | village
+//	kecamatan | district
+//	kabupaten | kota | kabupaten_kota | regency | city
+//	provinsi | province
+package postcode
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+// ErrInvalidFile berarti CSV tidak bisa dibaca atau kolom kode pos tidak ada.
+var ErrInvalidFile = errors.New("file kode pos tidak valid")
+
+// Kode pos Indonesia: 5 digit, tidak diawali 0
+var (
+	codePattern    = regexp.MustCompile(`^[1-9][0-9]{4}$`)
+	addressPattern = regexp.MustCompile(`\b([1-9][0-9]{4})\b`)
+)
+
+var columnAliases = map[string]string{
+	"kodepos": "code", "kode_pos": "code", "postcode": "code", "postal_code": "code",
+	"kelurahan": "village", "desa": "village", "village": "village",
+	"kecamatan": "district", "district": "district",
+	"kabupaten": "regency", "kota": "regency", "kabupaten_kota": "regency", "regency": "regency", "city": "regency",
+	"provinsi": "province", "province": "province",
+}
+
+// Valid bernilai true jika code berformat kode pos Indonesia.
+func Valid(code string) bool {
+	return codePattern.MatchString(code)
+}
+
+// FromAddress mengambil kode pos terakhir yang muncul di alamat ("" jika tidak ada).
+func FromAddress(address string) string {
+	matches := addressPattern.FindAllString(address, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[len(matches)-1]
+}
+
+// Awalan nama wilayah yang diabaikan saat mencocokkan nama
+var placePrefixes = []string{"kecamatan ", "kec. ", "kec ", "kabupaten ", "kab. ", "kab ", "kota administrasi ", "kota ", "provinsi "}
+
+// Key menormalkan nama wilayah untuk pencocokan: huruf kecil, tanpa awalan
+// "Kecamatan"/"Kabupaten"/"Kota", spasi dirapikan.
+func Key(name string) string {
+	key := strings.Join(strings.Fields(strings.ToLower(name)), " ")
+	for _, p := range placePrefixes {
+		if strings.HasPrefix(key, p) {
+			return strings.TrimPrefix(key, p)
+		}
+	}
+	return key
+}
+
+// Read membaca CSV lalu mengelompokkan baris per kode pos (satu kode pos
+// bisa mencakup beberapa kelurahan). Baris dengan kode tidak valid
+// dihitung di skipped.
+func Read(r io.Reader) (postcodes []models.Postcode, skipped int, err error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	cols := map[string]int{}
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		if name, ok := columnAliases[h]; ok {
+			if _, dup := cols[name]; !dup {
+				cols[name] = i
+			}
+		}
+	}
+	if _, ok := cols["code"]; !ok {
+		return nil, 0, fmt.Errorf("%w: kolom kodepos wajib ada", ErrInvalidFile)
+	}
+	get := func(record []string, col string) string {
+		if i, ok := cols[col]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	byCode := map[string]*models.Postcode{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		code := get(record, "code")
+		if !Valid(code) {
+			skipped++
+			continue
+		}
+		pc, ok := byCode[code]
+		if !ok {
+			pc = &models.Postcode{Code: code}
+			byCode[code] = pc
+		}
+		area := models.PostcodeArea{
+			Village:  get(record, "village"),
+			District: get(record, "district"),
+			Regency:  get(record, "regency"),
+			Province: get(record, "province"),
+		}
+		area.DistrictKey, area.RegencyKey = Key(area.District), Key(area.Regency)
+		pc.Areas = append(pc.Areas, area)
+	}
+
+	postcodes = make([]models.Postcode, 0, len(byCode))
+	for _, pc := range byCode {
+		postcodes = append(postcodes, *pc)
+	}
+	sort.Slice(postcodes, func(i, j int) bool { return postcodes[i].Code < postcodes[j].Code })
+	return postcodes, skipped, nil
+}
@@ -0,0 +1,115 @@
+// Package ratelimit membatasi jumlah request per client dengan token bucket
+// in-memory, plus konfigurasi penguncian akun setelah login gagal
+// berturut-turut.
+//
+// Batas dan aturan penguncian diatur lewat package config (RATE_LIMIT_*,
+// AUTH_RATE_LIMIT_*, LOGIN_*).
+//
+// State token bucket disimpan per instance; jika aplikasi berjalan di
+// beberapa instance, batas efektifnya dikali jumlah instance. Penguncian
+// akun disimpan di MongoDB sehingga berlaku di semua instance.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// Bucket yang tidak dipakai selama ini pasti sudah penuh kembali dan
+	// boleh dibuang dari memori
+	sweepInterval = time.Minute
+)
+
+// Limiter adalah kumpulan token bucket, satu per key (mis. IP client).
+type Limiter struct {
+	perSec float64
+	burst  float64
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// New membuat limiter dengan perMin token per menit dan kapasitas burst.
+// burst <= 0 berarti sama dengan perMin; perMin 0 berarti semua request
+// diizinkan sampai SetRate mengaktifkannya.
+func New(perMin, burst int) *Limiter {
+	l := &Limiter{buckets: map[string]*bucket{}, lastSweep: time.Now()}
+	l.setRate(perMin, burst)
+	return l
+}
+
+// SetRate mengganti batas saat berjalan. Bucket yang sudah ada dibuang
+// sehingga setiap client mulai lagi dengan bucket penuh.
+func (l *Limiter) SetRate(perMin, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.setRate(perMin, burst)
+	l.buckets = map[string]*bucket{}
+}
+
+func (l *Limiter) setRate(perMin, burst int) {
+	if burst <= 0 {
+		burst = perMin
+	}
+	l.perSec = float64(perMin) / 60
+	l.burst = float64(burst)
+}
+
+// Allow mengambil satu token untuk key. Jika token habis, hasilnya false
+// beserta waktu tunggu sampai token berikutnya tersedia.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.perSec == 0 {
+		return true, 0
+	}
+	now := time.Now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.perSec)
+	b.last = now
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / l.perSec * float64(time.Second))
+		return false, wait
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep membuang bucket yang sudah penuh kembali supaya map tidak tumbuh
+// terus oleh IP yang hanya datang sekali.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	refill := time.Duration(l.burst / l.perSec * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= refill {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Lockout adalah aturan penguncian akun setelah login gagal berturut-turut.
+// MaxFailures 0 berarti penguncian nonaktif.
+type Lockout struct {
+	MaxFailures int
+	Duration    time.Duration
+}
+
+// Enabled bernilai true jika penguncian akun aktif.
+func (l Lockout) Enabled() bool { return l.MaxFailures > 0 && l.Duration > 0 }
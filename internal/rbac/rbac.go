@@ -0,0 +1,76 @@
+// Package rbac mendefinisikan permission yang dikenal aplikasi dan role
+// bawaan. Role sendiri disimpan di collection roles sehingga tier baru
+// (mis. moderator) cukup dibuat lewat API admin tanpa ubah kode.
+package rbac
+
+// Permission yang dicek di route dan service
+const (
+	LocationsCreate    = "locations:create"
+	LocationsUpdateAny = "locations:update_any" // ubah lokasi milik orang lain
+	LocationsDeleteAny = "locations:delete_any" // hapus lokasi milik orang lain
+	LocationsModerate  = "locations:moderate"   // tulis verified/status/created_by/pinned
+	LocationsPurge     = "locations:purge"      // hapus permanen & kosongkan trash
+	UsersManage        = "users:manage"
+	RolesManage        = "roles:manage"
+	SettingsManage     = "settings:manage"
+	CategoriesManage   = "categories:manage"
+	ReviewsModerate    = "reviews:moderate"  // hapus ulasan orang lain, moderasi ulasan yang ditahan
+	SystemAudit        = "system:audit"      // security check, laporan deprecation, audit log
+	CampaignsManage    = "campaigns:manage"  // email massal ke user
+	LocationsPromote   = "locations:promote" // lokasi pinned/sponsored dan carousel beranda
+	ExportsSchedule    = "exports:schedule"  // export berkala ke email/webhook/S3
+	MailManage         = "mail:manage"       // email transaksional yang gagal terkirim
+	EventsRead         = "events:read"       // baca log domain event (consumer internal)
+
+	// Wildcard: semua permission
+	All = "*"
+)
+
+// Permissions berisi semua permission beserta penjelasannya.
+var Permissions = map[string]string{
+	LocationsCreate:    "Menambah lokasi (dan import massal)",
+	LocationsUpdateAny: "Mengubah lokasi milik user lain",
+	LocationsDeleteAny: "Menghapus lokasi milik user lain",
+	LocationsModerate:  "Mengisi field moderasi lokasi: verified, status, created_by, visibility=pinned",
+	LocationsPurge:     "Menghapus lokasi secara permanen dan menjalankan purge trash",
+	UsersManage:        "Melihat, menghapus, mengubah role & kuota user",
+	RolesManage:        "Membuat dan mengubah role",
+	SettingsManage:     "Mengubah pengaturan aplikasi (batas pencarian, kuota)",
+	CategoriesManage:   "Membuat, mengubah dan menghapus kategori lokasi",
+	ReviewsModerate:    "Menghapus ulasan milik user lain dan memoderasi ulasan yang ditahan aturan spam",
+	SystemAudit:        "Melihat security check, laporan deprecation dan audit log",
+	CampaignsManage:    "Membuat, menguji dan mengirim email campaign ke segmen user",
+	LocationsPromote:   "Mengatur lokasi pinned/sponsored, carousel beranda dan melihat laporan impression",
+	ExportsSchedule:    "Mengatur dan menjalankan export lokasi berkala ke email, webhook atau S3",
+	MailManage:         "Melihat, mengirim ulang dan menghapus email yang gagal dikirim provider",
+	EventsRead:         "Membaca log domain event dan menyimpan offset consumer, untuk service internal",
+}
+
+// AdminRole tidak boleh diubah/dihapus supaya admin tidak terkunci.
+const AdminRole = "admin"
+
+// DefaultRole diberikan ke user baru saat registrasi.
+const DefaultRole = "user"
+
+// Role bawaan yang dibuat jika collection roles masih kosong
+var DefaultRoles = map[string][]string{
+	AdminRole:     {All},
+	"contributor": {LocationsCreate},
+	DefaultRole:   {LocationsCreate},
+}
+
+// Known bernilai true untuk permission yang terdaftar (termasuk wildcard).
+func Known(perm string) bool {
+	_, ok := Permissions[perm]
+	return ok || perm == All
+}
+
+// Allows mengecek apakah daftar permission mencakup perm.
+func Allows(perms []string, perm string) bool {
+	for _, p := range perms {
+		if p == perm || p == All {
+			return true
+		}
+	}
+	return false
+}
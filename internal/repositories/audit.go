@@ -0,0 +1,251 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AuditQuery adalah filter GET /admin/audit-logs dan GET /changelog.
+type AuditQuery struct {
+	Actor  string
+	Action string
+	// Cocok jika action salah satu dari ini
+	Actions      []string
+	ResourceType string
+	ResourceID   string
+	// Dicocokkan ke actor, action, resource dan summary (case-insensitive)
+	Text     string
+	From, To time.Time // zero = tanpa batas
+	ListPage
+}
+
+func (q AuditQuery) filter() bson.M {
+	filter := bson.M{}
+	if q.Actor != "" {
+		filter["actor"] = q.Actor
+	}
+	if q.Action != "" {
+		filter["action"] = q.Action
+	} else if len(q.Actions) > 0 {
+		filter["action"] = bson.M{"$in": q.Actions}
+	}
+	if q.ResourceType != "" {
+		filter["resource_type"] = q.ResourceType
+	}
+	if q.ResourceID != "" {
+		filter["resource_id"] = q.ResourceID
+	}
+	if q.Text != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(q.Text), Options: "i"}
+		or := bson.A{}
+		for _, f := range []string{"actor", "action", "resource_type", "resource_id", "summary"} {
+			or = append(or, bson.M{f: pattern})
+		}
+		filter["$or"] = or
+	}
+	timeRange := bson.M{}
+	if !q.From.IsZero() {
+		timeRange["$gte"] = q.From
+	}
+	if !q.To.IsZero() {
+		timeRange["$lt"] = q.To
+	}
+	if len(timeRange) > 0 {
+		filter["time"] = timeRange
+	}
+	return filter
+}
+
+// AuditList adalah sort dan batas GET /admin/audit-logs.
+var AuditList = ListSpec{
+	Sorts:        map[string]string{"time": "time"},
+	DefaultSort:  "-time",
+	DefaultLimit: 50,
+	MaxLimit:     200,
+}
+
+// AuditRepository hanya bisa menambah dan membaca; tidak ada update supaya
+// hash chain tidak perlu ditulis ulang. Catatan hanya dihapus oleh job
+// arsip setelah tersimpan di object storage (DeleteThrough).
+type AuditRepository interface {
+	// Insert mengembalikan ErrDuplicate jika seq sudah dipakai (instance
+	// lain menambah chain lebih dulu).
+	Insert(ctx context.Context, logs []models.AuditLog) error
+	Find(ctx context.Context, q AuditQuery) ([]models.AuditLog, int64, error)
+	// Each memanggil fn untuk setiap catatan yang cocok dengan q, urut
+	// time lalu _id naik, tanpa memuat semuanya ke memori (export).
+	Each(ctx context.Context, q AuditQuery, fn func(models.AuditLog) error) error
+	// Last mengembalikan catatan dengan seq terbesar; ErrNotFound jika
+	// chain masih kosong.
+	Last(ctx context.Context) (*models.AuditLog, error)
+	// Chain mengembalikan catatan ber-seq setelah afterSeq, urut seq naik.
+	Chain(ctx context.Context, afterSeq, limit int64) ([]models.AuditLog, error)
+	// DeleteThrough menghapus catatan ber-seq <= seq yang sudah diarsip.
+	DeleteThrough(ctx context.Context, seq int64) (int64, error)
+	// Arsip di collection audit_archives, urut from_seq naik.
+	AddArchive(ctx context.Context, a *models.AuditArchive) error
+	Archives(ctx context.Context) ([]models.AuditArchive, error)
+	// LastArchive mengembalikan arsip dengan to_seq terbesar; ErrNotFound
+	// jika belum pernah diarsip.
+	LastArchive(ctx context.Context) (*models.AuditArchive, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+type mongoAuditRepository struct {
+	coll     *mongo.Collection
+	archives *mongo.Collection
+}
+
+func NewAuditRepository(coll, archives *mongo.Collection) AuditRepository {
+	// Nilai before/after bertipe interface{}: decode sebagai map supaya
+	// response JSON tetap berbentuk object, bukan array key/value.
+	if coll != nil {
+		if clone, err := coll.Clone(options.Collection().SetBSONOptions(&options.BSONOptions{DefaultDocumentM: true})); err == nil {
+			coll = clone
+		}
+	}
+	return &mongoAuditRepository{coll: coll, archives: archives}
+}
+
+func (r *mongoAuditRepository) Insert(ctx context.Context, logs []models.AuditLog) error {
+	docs := make([]interface{}, len(logs))
+	for i := range logs {
+		docs[i] = logs[i]
+	}
+	_, err := r.coll.InsertMany(ctx, docs)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *mongoAuditRepository) Find(ctx context.Context, q AuditQuery) ([]models.AuditLog, int64, error) {
+	filter := q.filter()
+	total, err := r.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	cursor, err := r.coll.Find(ctx, q.Match(filter), q.FindOptions())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+	logs := []models.AuditLog{}
+	if err := cursor.All(ctx, &logs); err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+func (r *mongoAuditRepository) Each(ctx context.Context, q AuditQuery, fn func(models.AuditLog) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "time", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.coll.Find(ctx, q.filter(), opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var l models.AuditLog
+		if err := cursor.Decode(&l); err != nil {
+			return err
+		}
+		if err := fn(l); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func (r *mongoAuditRepository) Last(ctx context.Context) (*models.AuditLog, error) {
+	var l models.AuditLog
+	opts := options.FindOne().SetSort(bson.D{{Key: "seq", Value: -1}})
+	if err := r.coll.FindOne(ctx, bson.M{"seq": bson.M{"$exists": true}}, opts).Decode(&l); err != nil {
+		return nil, notFound(err)
+	}
+	return &l, nil
+}
+
+func (r *mongoAuditRepository) Chain(ctx context.Context, afterSeq, limit int64) ([]models.AuditLog, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "seq", Value: 1}}).SetLimit(limit)
+	cursor, err := r.coll.Find(ctx, bson.M{"seq": bson.M{"$gt": afterSeq}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	logs := []models.AuditLog{}
+	if err := cursor.All(ctx, &logs); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+func (r *mongoAuditRepository) DeleteThrough(ctx context.Context, seq int64) (int64, error) {
+	res, err := r.coll.DeleteMany(ctx, bson.M{"seq": bson.M{"$gt": 0, "$lte": seq}})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+func (r *mongoAuditRepository) AddArchive(ctx context.Context, a *models.AuditArchive) error {
+	if a.ID.IsZero() {
+		a.ID = primitive.NewObjectID()
+	}
+	_, err := r.archives.InsertOne(ctx, a)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *mongoAuditRepository) Archives(ctx context.Context) ([]models.AuditArchive, error) {
+	cursor, err := r.archives.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "from_seq", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	archives := []models.AuditArchive{}
+	if err := cursor.All(ctx, &archives); err != nil {
+		return nil, err
+	}
+	return archives, nil
+}
+
+func (r *mongoAuditRepository) LastArchive(ctx context.Context) (*models.AuditArchive, error) {
+	var a models.AuditArchive
+	opts := options.FindOne().SetSort(bson.D{{Key: "to_seq", Value: -1}})
+	if err := r.archives.FindOne(ctx, bson.M{}, opts).Decode(&a); err != nil {
+		return nil, notFound(err)
+	}
+	return &a, nil
+}
+
+func (r *mongoAuditRepository) EnsureIndexes(ctx context.Context) error {
+	// Dua instance yang mengarsip batch sama: hanya satu yang tercatat
+	if _, err := r.archives.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "from_seq", Value: 1}}, Options: options.Index().SetUnique(true),
+	}); err != nil {
+		return err
+	}
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		// Daftar audit log urut time lalu _id (ListPage)
+		{Keys: bson.D{{Key: "time", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "actor", Value: 1}, {Key: "time", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "resource_type", Value: 1}, {Key: "resource_id", Value: 1}, {Key: "time", Value: -1}, {Key: "_id", Value: -1}}},
+		// Changelog publik membaca per action urut _id
+		{Keys: bson.D{{Key: "action", Value: 1}, {Key: "_id", Value: 1}}},
+		// Satu seq hanya boleh dipakai sekali supaya chain tidak bercabang;
+		// catatan lama tanpa seq tidak ikut
+		{Keys: bson.D{{Key: "seq", Value: 1}}, Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.M{"seq": bson.M{"$exists": true}})},
+	})
+	return err
+}
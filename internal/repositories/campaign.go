@@ -0,0 +1,188 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CampaignRepository interface {
+	Create(ctx context.Context, c *models.Campaign) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Campaign, error)
+	List(ctx context.Context, p ListPage) ([]models.Campaign, int64, error)
+	// SetStatus hanya berhasil jika status saat ini from; jika tidak,
+	// hasilnya ErrNotFound. Dipakai supaya campaign tidak dikirim dua kali.
+	SetStatus(ctx context.Context, id primitive.ObjectID, from, to string, set Fields) error
+	// IncStats menambah counter di stats, mis. {"sent": 1}.
+	IncStats(ctx context.Context, id primitive.ObjectID, inc map[string]int64) error
+
+	InsertRecipients(ctx context.Context, recipients []models.CampaignRecipient) error
+	// PendingRecipients mengembalikan penerima yang belum dikirim, urut antrean.
+	PendingRecipients(ctx context.Context, limit int64) ([]models.CampaignRecipient, error)
+	// CountPending menghitung penerima yang belum dikirim; campaignID
+	// NilObjectID berarti semua campaign.
+	CountPending(ctx context.Context, campaignID primitive.ObjectID) (int64, error)
+	MarkRecipient(ctx context.Context, id primitive.ObjectID, status string, at time.Time, errMsg string) error
+	// MarkOpened mencatat open pertama untuk token. Open berikutnya atau
+	// token yang tidak dikenal menghasilkan ErrNotFound.
+	MarkOpened(ctx context.Context, token string, at time.Time) (*models.CampaignRecipient, error)
+	DeleteRecipients(ctx context.Context, campaignID primitive.ObjectID) error
+	EnsureIndexes(ctx context.Context) error
+}
+
+// CampaignList adalah sort dan batas GET /admin/campaigns.
+var CampaignList = ListSpec{
+	Sorts:        map[string]string{"created_at": "created_at"},
+	DefaultSort:  "-created_at",
+	DefaultLimit: 20,
+	MaxLimit:     100,
+}
+
+type mongoCampaignRepository struct {
+	coll       *mongo.Collection
+	recipients *mongo.Collection
+}
+
+func NewCampaignRepository(coll, recipients *mongo.Collection) CampaignRepository {
+	return &mongoCampaignRepository{coll: coll, recipients: recipients}
+}
+
+func (r *mongoCampaignRepository) Create(ctx context.Context, c *models.Campaign) error {
+	if c.ID.IsZero() {
+		c.ID = primitive.NewObjectID()
+	}
+	_, err := r.coll.InsertOne(ctx, c)
+	return err
+}
+
+func (r *mongoCampaignRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Campaign, error) {
+	var c models.Campaign
+	if err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&c); err != nil {
+		return nil, notFound(err)
+	}
+	return &c, nil
+}
+
+func (r *mongoCampaignRepository) List(ctx context.Context, p ListPage) ([]models.Campaign, int64, error) {
+	total, err := r.coll.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return nil, 0, err
+	}
+	cursor, err := r.coll.Find(ctx, p.Match(bson.M{}), p.FindOptions())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+	campaigns := []models.Campaign{}
+	if err := cursor.All(ctx, &campaigns); err != nil {
+		return nil, 0, err
+	}
+	return campaigns, total, nil
+}
+
+func (r *mongoCampaignRepository) SetStatus(ctx context.Context, id primitive.ObjectID, from, to string, set Fields) error {
+	update := bson.M{"status": to}
+	for k, v := range set {
+		update[k] = v
+	}
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": id, "status": from}, bson.M{"$set": update})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoCampaignRepository) IncStats(ctx context.Context, id primitive.ObjectID, inc map[string]int64) error {
+	fields := bson.M{}
+	for k, v := range inc {
+		fields["stats."+k] = v
+	}
+	_, err := r.coll.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$inc": fields})
+	return err
+}
+
+func (r *mongoCampaignRepository) InsertRecipients(ctx context.Context, recipients []models.CampaignRecipient) error {
+	if len(recipients) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, len(recipients))
+	for i := range recipients {
+		if recipients[i].ID.IsZero() {
+			recipients[i].ID = primitive.NewObjectID()
+		}
+		docs[i] = recipients[i]
+	}
+	_, err := r.recipients.InsertMany(ctx, docs)
+	return err
+}
+
+func (r *mongoCampaignRepository) PendingRecipients(ctx context.Context, limit int64) ([]models.CampaignRecipient, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit)
+	cursor, err := r.recipients.Find(ctx, bson.M{"status": models.RecipientPending}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	recipients := []models.CampaignRecipient{}
+	if err := cursor.All(ctx, &recipients); err != nil {
+		return nil, err
+	}
+	return recipients, nil
+}
+
+func (r *mongoCampaignRepository) CountPending(ctx context.Context, campaignID primitive.ObjectID) (int64, error) {
+	filter := bson.M{"status": models.RecipientPending}
+	if !campaignID.IsZero() {
+		filter["campaign_id"] = campaignID
+	}
+	return r.recipients.CountDocuments(ctx, filter)
+}
+
+func (r *mongoCampaignRepository) MarkRecipient(ctx context.Context, id primitive.ObjectID, status string, at time.Time, errMsg string) error {
+	set := bson.M{"status": status, "sent_at": at}
+	if errMsg != "" {
+		set["error"] = errMsg
+	}
+	_, err := r.recipients.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+	return err
+}
+
+func (r *mongoCampaignRepository) MarkOpened(ctx context.Context, token string, at time.Time) (*models.CampaignRecipient, error) {
+	var rec models.CampaignRecipient
+	filter := bson.M{"token": token, "status": models.RecipientSent, "opened_at": nil}
+	err := r.recipients.FindOneAndUpdate(ctx, filter, bson.M{"$set": bson.M{"opened_at": at}}).Decode(&rec)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &rec, nil
+}
+
+func (r *mongoCampaignRepository) DeleteRecipients(ctx context.Context, campaignID primitive.ObjectID) error {
+	_, err := r.recipients.DeleteMany(ctx, bson.M{"campaign_id": campaignID})
+	return err
+}
+
+// Antrean delivery dibaca per status urut _id; token pixel harus unik
+func (r *mongoCampaignRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.recipients.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "campaign_id", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "token", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+	})
+	return err
+}
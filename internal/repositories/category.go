@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CategoryRepository interface {
+	List(ctx context.Context) ([]models.Category, error)
+	FindBySlug(ctx context.Context, slug string) (*models.Category, error)
+	Create(ctx context.Context, c *models.Category) error
+	Update(ctx context.Context, slug string, set Fields) error
+	Delete(ctx context.Context, slug string) error
+	// EnsureIndexes membuat unique index slug.
+	EnsureIndexes(ctx context.Context) error
+}
+
+type mongoCategoryRepository struct {
+	coll *mongo.Collection
+}
+
+func NewCategoryRepository(coll *mongo.Collection) CategoryRepository {
+	return &mongoCategoryRepository{coll: coll}
+}
+
+func (r *mongoCategoryRepository) List(ctx context.Context) ([]models.Category, error) {
+	cursor, err := r.coll.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	categories := []models.Category{}
+	if err := cursor.All(ctx, &categories); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *mongoCategoryRepository) FindBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	var c models.Category
+	if err := r.coll.FindOne(ctx, bson.M{"slug": slug}).Decode(&c); err != nil {
+		return nil, notFound(err)
+	}
+	return &c, nil
+}
+
+func (r *mongoCategoryRepository) Create(ctx context.Context, c *models.Category) error {
+	if c.ID.IsZero() {
+		c.ID = primitive.NewObjectID()
+	}
+	_, err := r.coll.InsertOne(ctx, c)
+	return err
+}
+
+func (r *mongoCategoryRepository) Update(ctx context.Context, slug string, set Fields) error {
+	res, err := r.coll.UpdateOne(ctx, bson.M{"slug": slug}, bson.M{"$set": bson.M(set)})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoCategoryRepository) Delete(ctx context.Context, slug string) error {
+	res, err := r.coll.DeleteOne(ctx, bson.M{"slug": slug})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoCategoryRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "slug", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AttendanceTimezone dipakai untuk mengelompokkan check-in per tanggal (WIB).
+const AttendanceTimezone = "+07:00"
+
+type CheckInRepository interface {
+	// Create mengembalikan ErrDuplicate jika user sudah check-in di lokasi ini.
+	Create(ctx context.Context, c *models.CheckIn) error
+	Attendance(ctx context.Context, locationID primitive.ObjectID) (models.Attendance, error)
+	DeleteByLocation(ctx context.Context, locationID primitive.ObjectID) (int64, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+type mongoCheckInRepository struct {
+	coll *mongo.Collection
+}
+
+func NewCheckInRepository(coll *mongo.Collection) CheckInRepository {
+	return &mongoCheckInRepository{coll: coll}
+}
+
+func (r *mongoCheckInRepository) Create(ctx context.Context, c *models.CheckIn) error {
+	if c.ID.IsZero() {
+		c.ID = primitive.NewObjectID()
+	}
+	_, err := r.coll.InsertOne(ctx, c)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *mongoCheckInRepository) Attendance(ctx context.Context, locationID primitive.ObjectID) (models.Attendance, error) {
+	attendance := models.Attendance{LocationID: locationID, ByDay: []models.DayCount{}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"location_id": locationID}}},
+		{{Key: "$facet", Value: bson.M{
+			"summary": bson.A{bson.M{"$group": bson.M{"_id": nil, "total": bson.M{"$sum": 1}, "last_check_in_at": bson.M{"$max": "$created_at"}}}},
+			"by_day": bson.A{
+				bson.M{"$group": bson.M{
+					"_id":   bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$created_at", "timezone": AttendanceTimezone}},
+					"count": bson.M{"$sum": 1},
+				}},
+				bson.M{"$sort": bson.M{"_id": 1}},
+			},
+		}}},
+	}
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return attendance, err
+	}
+	defer cursor.Close(ctx)
+	var out []struct {
+		Summary []models.Attendance `bson:"summary"`
+		ByDay   []models.DayCount   `bson:"by_day"`
+	}
+	if err := cursor.All(ctx, &out); err != nil {
+		return attendance, err
+	}
+	if len(out) == 0 || len(out[0].Summary) == 0 {
+		return attendance, nil
+	}
+	attendance.Total = out[0].Summary[0].Total
+	attendance.LastCheckInAt = out[0].Summary[0].LastCheckInAt
+	attendance.ByDay = out[0].ByDay
+	return attendance, nil
+}
+
+func (r *mongoCheckInRepository) DeleteByLocation(ctx context.Context, locationID primitive.ObjectID) (int64, error) {
+	res, err := r.coll.DeleteMany(ctx, bson.M{"location_id": locationID})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+// Satu check-in per user per lokasi; index yang sama dipakai Attendance
+func (r *mongoCheckInRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "location_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
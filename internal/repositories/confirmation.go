@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ConfirmationRepository interface {
+	// Create mengembalikan ErrDuplicate jika user masih punya konfirmasi
+	// yang belum kedaluwarsa untuk lokasi ini.
+	Create(ctx context.Context, c *models.Confirmation) error
+	DeleteByLocation(ctx context.Context, locationID primitive.ObjectID) (int64, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+type mongoConfirmationRepository struct {
+	coll *mongo.Collection
+}
+
+func NewConfirmationRepository(coll *mongo.Collection) ConfirmationRepository {
+	return &mongoConfirmationRepository{coll: coll}
+}
+
+func (r *mongoConfirmationRepository) Create(ctx context.Context, c *models.Confirmation) error {
+	if c.ID.IsZero() {
+		c.ID = primitive.NewObjectID()
+	}
+	_, err := r.coll.InsertOne(ctx, c)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *mongoConfirmationRepository) DeleteByLocation(ctx context.Context, locationID primitive.ObjectID) (int64, error) {
+	res, err := r.coll.DeleteMany(ctx, bson.M{"location_id": locationID})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+// Satu konfirmasi aktif per user per lokasi; dihapus otomatis saat expires_at
+func (r *mongoConfirmationRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "location_id", Value: 1}, {Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
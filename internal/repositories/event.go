@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EventRepository menyimpan log domain event (collection events) dan
+// offset consumer-nya (collection event_offsets). Event hanya ditambah,
+// tidak pernah diubah.
+type EventRepository interface {
+	// Insert mengembalikan ErrDuplicate jika seq sudah dipakai (instance
+	// lain menambah log lebih dulu).
+	Insert(ctx context.Context, ev *models.Event) error
+	// Last mengembalikan event dengan seq terbesar; ErrNotFound jika log
+	// masih kosong.
+	Last(ctx context.Context) (*models.Event, error)
+	// After mengembalikan event ber-seq setelah afterSeq, urut seq naik.
+	After(ctx context.Context, afterSeq, limit int64) ([]models.Event, error)
+	// Offset mengembalikan ErrNotFound jika consumer belum pernah
+	// menyimpan offset.
+	Offset(ctx context.Context, consumer string) (*models.EventOffset, error)
+	SetOffset(ctx context.Context, offset models.EventOffset) error
+	// EnsureIndexes membuat index unik seq.
+	EnsureIndexes(ctx context.Context) error
+}
+
+type mongoEventRepository struct {
+	coll    *mongo.Collection
+	offsets *mongo.Collection
+}
+
+func NewEventRepository(coll, offsets *mongo.Collection) EventRepository {
+	return &mongoEventRepository{coll: coll, offsets: offsets}
+}
+
+func (r *mongoEventRepository) Insert(ctx context.Context, ev *models.Event) error {
+	_, err := r.coll.InsertOne(ctx, ev)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *mongoEventRepository) Last(ctx context.Context) (*models.Event, error) {
+	var ev models.Event
+	opts := options.FindOne().SetSort(bson.D{{Key: "seq", Value: -1}})
+	if err := r.coll.FindOne(ctx, bson.M{}, opts).Decode(&ev); err != nil {
+		return nil, notFound(err)
+	}
+	return &ev, nil
+}
+
+func (r *mongoEventRepository) After(ctx context.Context, afterSeq, limit int64) ([]models.Event, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "seq", Value: 1}}).SetLimit(limit)
+	cursor, err := r.coll.Find(ctx, bson.M{"seq": bson.M{"$gt": afterSeq}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	events := []models.Event{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *mongoEventRepository) Offset(ctx context.Context, consumer string) (*models.EventOffset, error) {
+	var o models.EventOffset
+	if err := r.offsets.FindOne(ctx, bson.M{"_id": consumer}).Decode(&o); err != nil {
+		return nil, notFound(err)
+	}
+	return &o, nil
+}
+
+func (r *mongoEventRepository) SetOffset(ctx context.Context, offset models.EventOffset) error {
+	_, err := r.offsets.ReplaceOne(ctx, bson.M{"_id": offset.Consumer}, offset, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *mongoEventRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "seq", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ScheduledExportRepository interface {
+	Create(ctx context.Context, e *models.ScheduledExport) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.ScheduledExport, error)
+	List(ctx context.Context, p ListPage) ([]models.ScheduledExport, int64, error)
+	// Replace mengganti seluruh dokumen; ErrNotFound jika tidak ada.
+	Replace(ctx context.Context, e *models.ScheduledExport) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	// Due mengembalikan jadwal aktif dengan next_run_at <= now, yang paling
+	// lama jatuh tempo lebih dulu.
+	Due(ctx context.Context, now time.Time, limit int64) ([]models.ScheduledExport, error)
+	CountDue(ctx context.Context, now time.Time) (int64, error)
+	// Claim memindahkan next_run_at dari from ke to. ErrNotFound jika
+	// jadwal sudah diklaim proses lain, supaya tidak terkirim dua kali.
+	Claim(ctx context.Context, id primitive.ObjectID, from, to time.Time) error
+	SetLastRun(ctx context.Context, id primitive.ObjectID, run models.ExportRun) error
+	EnsureIndexes(ctx context.Context) error
+}
+
+// ScheduledExportList adalah sort dan batas GET /admin/scheduled-exports.
+var ScheduledExportList = ListSpec{
+	Sorts:        map[string]string{"created_at": "_id"},
+	DefaultSort:  "-created_at",
+	DefaultLimit: 20,
+	MaxLimit:     100,
+}
+
+type mongoScheduledExportRepository struct {
+	coll *mongo.Collection
+}
+
+func NewScheduledExportRepository(coll *mongo.Collection) ScheduledExportRepository {
+	return &mongoScheduledExportRepository{coll: coll}
+}
+
+func (r *mongoScheduledExportRepository) Create(ctx context.Context, e *models.ScheduledExport) error {
+	if e.ID.IsZero() {
+		e.ID = primitive.NewObjectID()
+	}
+	_, err := r.coll.InsertOne(ctx, e)
+	return err
+}
+
+func (r *mongoScheduledExportRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.ScheduledExport, error) {
+	var e models.ScheduledExport
+	if err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&e); err != nil {
+		return nil, notFound(err)
+	}
+	return &e, nil
+}
+
+func (r *mongoScheduledExportRepository) List(ctx context.Context, p ListPage) ([]models.ScheduledExport, int64, error) {
+	total, err := r.coll.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return nil, 0, err
+	}
+	cursor, err := r.coll.Find(ctx, p.Match(bson.M{}), p.FindOptions())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+	exports := []models.ScheduledExport{}
+	if err := cursor.All(ctx, &exports); err != nil {
+		return nil, 0, err
+	}
+	return exports, total, nil
+}
+
+func (r *mongoScheduledExportRepository) Replace(ctx context.Context, e *models.ScheduledExport) error {
+	res, err := r.coll.ReplaceOne(ctx, bson.M{"_id": e.ID}, e)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoScheduledExportRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	res, err := r.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func dueFilter(now time.Time) bson.M {
+	return bson.M{"enabled": true, "next_run_at": bson.M{"$lte": now}}
+}
+
+func (r *mongoScheduledExportRepository) Due(ctx context.Context, now time.Time, limit int64) ([]models.ScheduledExport, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "next_run_at", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(limit)
+	cursor, err := r.coll.Find(ctx, dueFilter(now), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	exports := []models.ScheduledExport{}
+	if err := cursor.All(ctx, &exports); err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (r *mongoScheduledExportRepository) CountDue(ctx context.Context, now time.Time) (int64, error) {
+	return r.coll.CountDocuments(ctx, dueFilter(now))
+}
+
+func (r *mongoScheduledExportRepository) Claim(ctx context.Context, id primitive.ObjectID, from, to time.Time) error {
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": id, "next_run_at": from}, bson.M{"$set": bson.M{"next_run_at": to}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoScheduledExportRepository) SetLastRun(ctx context.Context, id primitive.ObjectID, run models.ExportRun) error {
+	_, err := r.coll.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"last_run": run}})
+	return err
+}
+
+// Job membaca jadwal jatuh tempo per enabled + next_run_at
+func (r *mongoScheduledExportRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "enabled", Value: 1}, {Key: "next_run_at", Value: 1}},
+	})
+	return err
+}
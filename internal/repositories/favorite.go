@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"context"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type FavoriteRepository interface {
+	// Add mengembalikan ErrDuplicate jika lokasi sudah ada di favorit user.
+	Add(ctx context.Context, f *models.Favorite) error
+	// Remove mengembalikan ErrNotFound jika lokasi tidak ada di favorit user.
+	Remove(ctx context.Context, userID, locationID primitive.ObjectID) error
+	// Locations mengembalikan lokasi favorit user (tanpa trash dan arsip)
+	// beserta rating, urut waktu disimpan.
+	Locations(ctx context.Context, userID primitive.ObjectID, p ListPage) ([]models.Location, int64, error)
+	CountByLocation(ctx context.Context, locationID primitive.ObjectID) (int64, error)
+	DeleteByLocation(ctx context.Context, locationID primitive.ObjectID) (int64, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+// FavoriteList adalah sort dan batas GET /me/favorites. created_at adalah
+// waktu lokasi disimpan, yang tidak ada di dokumen lokasi hasil, jadi tanpa
+// cursor.
+var FavoriteList = ListSpec{
+	Sorts:        map[string]string{"created_at": "created_at"},
+	DefaultSort:  "-created_at",
+	DefaultLimit: 20,
+	MaxLimit:     100,
+	NoCursor:     true,
+}
+
+type mongoFavoriteRepository struct {
+	coll      *mongo.Collection
+	locations *mongo.Collection
+	// Sumber rating pada Locations
+	reviews *mongo.Collection
+}
+
+func NewFavoriteRepository(coll, locations, reviews *mongo.Collection) FavoriteRepository {
+	return &mongoFavoriteRepository{coll: coll, locations: locations, reviews: reviews}
+}
+
+func (r *mongoFavoriteRepository) Add(ctx context.Context, f *models.Favorite) error {
+	if f.ID.IsZero() {
+		f.ID = primitive.NewObjectID()
+	}
+	_, err := r.coll.InsertOne(ctx, f)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *mongoFavoriteRepository) Remove(ctx context.Context, userID, locationID primitive.ObjectID) error {
+	res, err := r.coll.DeleteOne(ctx, bson.M{"user_id": userID, "location_id": locationID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Favorit yang lokasinya sedang di trash atau arsip tetap disimpan, hanya
+// tidak ikut tampil sampai lokasinya kembali.
+func (r *mongoFavoriteRepository) Locations(ctx context.Context, userID primitive.ObjectID, p ListPage) ([]models.Location, int64, error) {
+	page := bson.A{
+		bson.M{"$skip": p.Skip},
+		bson.M{"$limit": p.Limit},
+		bson.M{"$replaceRoot": bson.M{"newRoot": "$location"}},
+	}
+	for _, stage := range ratingStages(r.reviews) {
+		page = append(page, stage)
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": userID}}},
+		{{Key: "$sort", Value: p.SortDoc()}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": r.locations.Name(),
+			"let":  bson.M{"location_id": "$location_id"},
+			"pipeline": bson.A{bson.M{"$match": notDeleted(bson.M{
+				"$expr": bson.M{"$eq": bson.A{"$_id", "$$location_id"}},
+			})}},
+			"as": "location",
+		}}},
+		{{Key: "$unwind", Value: "$location"}},
+		{{Key: "$facet", Value: bson.M{
+			"total": bson.A{bson.M{"$count": "n"}},
+			"items": page,
+		}}},
+	}
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+	var out []struct {
+		Total []struct{ N int64 } `bson:"total"`
+		Items []models.Location   `bson:"items"`
+	}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, 0, err
+	}
+	locations := []models.Location{}
+	if len(out) == 0 || len(out[0].Total) == 0 {
+		return locations, 0, nil
+	}
+	if out[0].Items != nil {
+		locations = out[0].Items
+	}
+	return locations, out[0].Total[0].N, nil
+}
+
+func (r *mongoFavoriteRepository) CountByLocation(ctx context.Context, locationID primitive.ObjectID) (int64, error) {
+	return r.coll.CountDocuments(ctx, bson.M{"location_id": locationID})
+}
+
+func (r *mongoFavoriteRepository) DeleteByLocation(ctx context.Context, locationID primitive.ObjectID) (int64, error) {
+	res, err := r.coll.DeleteMany(ctx, bson.M{"location_id": locationID})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+// Satu favorit per user per lokasi; index location_id untuk rekonsiliasi favorites_count
+func (r *mongoFavoriteRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "location_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "location_id", Value: 1}}},
+	})
+	return err
+}
@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ImportJobRepository interface {
+	Create(ctx context.Context, job *models.ImportJob) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.ImportJob, error)
+	// NextQueued mengembalikan job queued yang paling lama; ErrNotFound
+	// jika antrean kosong.
+	NextQueued(ctx context.Context) (*models.ImportJob, error)
+	CountQueued(ctx context.Context) (int64, error)
+	// Advance menyimpan hasil baris from sampai from+len(rows)-1, memajukan
+	// cursor dan menambah counter sesuai status baris. ErrNotFound jika
+	// cursor sudah dipindah proses lain.
+	Advance(ctx context.Context, id primitive.ObjectID, from int, rows []models.ImportJobRow) error
+	// Place menyimpan baris unresolved yang sudah diberi koordinat manual.
+	// ErrNotFound jika baris tidak lagi unresolved.
+	Place(ctx context.Context, id primitive.ObjectID, index int, row models.ImportJobRow) error
+	// Complete menandai job queued selesai; ErrNotFound jika sudah selesai.
+	Complete(ctx context.Context, id primitive.ObjectID, at time.Time) error
+	EnsureIndexes(ctx context.Context) error
+}
+
+// importRowCounters adalah field counter job untuk setiap status akhir baris.
+var importRowCounters = map[string]string{
+	models.ImportRowGeocoded:   "geocoded",
+	models.ImportRowUnresolved: "unresolved",
+	models.ImportRowPlaced:     "placed",
+	models.ImportRowFailed:     "failed",
+}
+
+type mongoImportJobRepository struct {
+	coll *mongo.Collection
+}
+
+func NewImportJobRepository(coll *mongo.Collection) ImportJobRepository {
+	return &mongoImportJobRepository{coll: coll}
+}
+
+func (r *mongoImportJobRepository) Create(ctx context.Context, job *models.ImportJob) error {
+	if job.ID.IsZero() {
+		job.ID = primitive.NewObjectID()
+	}
+	_, err := r.coll.InsertOne(ctx, job)
+	return err
+}
+
+func (r *mongoImportJobRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.ImportJob, error) {
+	var job models.ImportJob
+	if err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&job); err != nil {
+		return nil, notFound(err)
+	}
+	return &job, nil
+}
+
+func (r *mongoImportJobRepository) NextQueued(ctx context.Context) (*models.ImportJob, error) {
+	var job models.ImportJob
+	opts := options.FindOne().SetSort(bson.D{{Key: "_id", Value: 1}})
+	if err := r.coll.FindOne(ctx, bson.M{"status": models.ImportJobQueued}, opts).Decode(&job); err != nil {
+		return nil, notFound(err)
+	}
+	return &job, nil
+}
+
+func (r *mongoImportJobRepository) CountQueued(ctx context.Context) (int64, error) {
+	return r.coll.CountDocuments(ctx, bson.M{"status": models.ImportJobQueued})
+}
+
+func (r *mongoImportJobRepository) Advance(ctx context.Context, id primitive.ObjectID, from int, rows []models.ImportJobRow) error {
+	set := bson.M{"cursor": from + len(rows)}
+	inc := bson.M{}
+	for i, row := range rows {
+		set[fmt.Sprintf("rows.%d", from+i)] = row
+		if field, ok := importRowCounters[row.Status]; ok {
+			inc[field] = incValue(inc[field]) + 1
+		}
+	}
+	update := bson.M{"$set": set}
+	if len(inc) > 0 {
+		update["$inc"] = inc
+	}
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": id, "cursor": from}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func incValue(v interface{}) int {
+	n, _ := v.(int)
+	return n
+}
+
+func (r *mongoImportJobRepository) Place(ctx context.Context, id primitive.ObjectID, index int, row models.ImportJobRow) error {
+	key := fmt.Sprintf("rows.%d", index)
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": id, key + ".status": models.ImportRowUnresolved},
+		bson.M{"$set": bson.M{key: row}, "$inc": bson.M{"unresolved": -1, importRowCounters[row.Status]: 1}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoImportJobRepository) Complete(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": id, "status": models.ImportJobQueued},
+		bson.M{"$set": bson.M{"status": models.ImportJobDone, "completed_at": at}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Antrean dibaca per status urut _id
+func (r *mongoImportJobRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "_id", Value: 1}},
+	})
+	return err
+}
@@ -0,0 +1,224 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ListSpec adalah aturan daftar satu resource: sort yang boleh dipakai
+// client dan batas limit. Semua ListOptions dari request wajib lewat
+// Resolve supaya ?limit besar atau ?sort tanpa index tidak bisa dipakai
+// untuk membebani MongoDB.
+type ListSpec struct {
+	// Nama sort publik -> field Mongo. Hanya daftarkan field yang punya
+	// index (bersama filter yang biasa dipakai) atau _id.
+	Sorts map[string]string
+	// Urutan jika ?sort kosong, mis. "-created_at"
+	DefaultSort  string
+	DefaultLimit int
+	MaxLimit     int
+	// true jika field sort tidak ada di dokumen hasil (mis. favorit yang
+	// diurutkan menurut waktu disimpan), sehingga cursor tidak bisa dibuat
+	NoCursor bool
+}
+
+// ListError berarti ?sort atau ?cursor tidak diterima ListSpec.
+type ListError struct {
+	// sort | cursor
+	Param string
+	// Nama sort yang diizinkan (Param sort)
+	Allowed []string
+}
+
+func (e *ListError) Error() string {
+	if e.Param == "sort" {
+		return "sort hanya boleh: " + strings.Join(e.Allowed, ", ") + " (awali - untuk descending)"
+	}
+	return e.Param + " tidak valid"
+}
+
+// ListPage adalah pagination dan urutan yang sudah diperiksa. Service
+// mendapatkannya dari ListSpec.Resolve; kode internal yang tidak membawa
+// input client boleh mengisinya langsung.
+type ListPage struct {
+	// Field Mongo; kosong = _id. _id selalu dipakai sebagai tiebreaker
+	// dengan arah yang sama.
+	Field string
+	Desc  bool
+	Skip  int64
+	// 0 = tanpa batas
+	Limit int64
+	// Nomor halaman untuk meta; 0 jika memakai cursor
+	Page int
+	// Posisi dokumen terakhir halaman sebelumnya; nil = pakai Skip
+	After *Cursor
+
+	// Sort publik (dengan "-") yang ditulis ke cursor berikutnya
+	sort     string
+	noCursor bool
+}
+
+// Cursor adalah nilai field sort dan _id dokumen terakhir yang sudah
+// dikirim ke client.
+type Cursor struct {
+	Sort  string             `bson:"s"`
+	Value bson.RawValue      `bson:"v"`
+	ID    primitive.ObjectID `bson:"id"`
+}
+
+// Resolve menerapkan default, batas limit dan whitelist sort ke o.
+func (s ListSpec) Resolve(o models.ListOptions) (ListPage, error) {
+	sort := o.Sort
+	if sort == "" {
+		sort = s.DefaultSort
+	}
+	field, ok := s.Sorts[strings.TrimPrefix(sort, "-")]
+	if !ok {
+		allowed := make([]string, 0, len(s.Sorts))
+		for name := range s.Sorts {
+			allowed = append(allowed, name)
+		}
+		slices.Sort(allowed)
+		return ListPage{}, &ListError{Param: "sort", Allowed: allowed}
+	}
+	limit := o.Limit
+	if limit <= 0 {
+		limit = s.DefaultLimit
+	}
+	if limit > s.MaxLimit {
+		limit = s.MaxLimit
+	}
+	p := ListPage{Field: field, Desc: strings.HasPrefix(sort, "-"), Limit: int64(limit), sort: sort, noCursor: s.NoCursor}
+	if o.Cursor != "" {
+		after, err := decodeCursor(o.Cursor)
+		// Cursor dari sort lain menunjuk posisi yang tidak berarti di urutan ini
+		if err != nil || s.NoCursor || after.Sort != sort {
+			return ListPage{}, &ListError{Param: "cursor"}
+		}
+		p.After = after
+		return p, nil
+	}
+	p.Page = max(o.Page, 1)
+	p.Skip = int64(p.Page-1) * p.Limit
+	return p, nil
+}
+
+// Meta membuat PageMeta untuk items, hasil List dengan p.
+func Meta[T any](p ListPage, total int64, items []T) models.PageMeta {
+	meta := models.PageMeta{Page: p.Page, Limit: int(p.Limit), Total: total}
+	if p.Limit > 0 {
+		meta.TotalPages = (total + p.Limit - 1) / p.Limit
+	}
+	if !p.noCursor && p.sort != "" && p.Limit > 0 && int64(len(items)) == p.Limit {
+		meta.NextCursor = nextCursor(p, items[len(items)-1])
+	}
+	return meta
+}
+
+func (p ListPage) field() string {
+	if p.Field == "" {
+		return "_id"
+	}
+	return p.Field
+}
+
+// SortDoc adalah dokumen $sort untuk p.
+func (p ListPage) SortDoc() bson.D {
+	dir := 1
+	if p.Desc {
+		dir = -1
+	}
+	sort := bson.D{{Key: p.field(), Value: dir}}
+	if p.field() != "_id" {
+		sort = append(sort, bson.E{Key: "_id", Value: dir})
+	}
+	return sort
+}
+
+// FindOptions adalah sort, skip dan limit untuk Find.
+func (p ListPage) FindOptions() *options.FindOptions {
+	opts := options.Find().SetSort(p.SortDoc()).SetSkip(p.Skip)
+	if p.Limit > 0 {
+		opts.SetLimit(p.Limit)
+	}
+	return opts
+}
+
+// Match menambahkan posisi cursor ke filter. filter tidak diubah.
+func (p ListPage) Match(filter bson.M) bson.M {
+	if p.After == nil {
+		return filter
+	}
+	return bson.M{"$and": bson.A{filter, p.after()}}
+}
+
+// after adalah kondisi "sesudah cursor" menurut p.SortDoc. Mongo
+// mengurutkan null (dan field yang tidak ada) sebelum nilai lain, dan $gt/$lt
+// hanya membandingkan nilai bertipe sama, jadi null ditangani terpisah.
+func (p ListPage) after() bson.M {
+	op := "$gt"
+	if p.Desc {
+		op = "$lt"
+	}
+	id := p.After.ID
+	if p.field() == "_id" {
+		return bson.M{"_id": bson.M{op: id}}
+	}
+	f, v := p.field(), p.After.Value
+	isNull := v.Type == 0 || v.Type == bson.TypeNull || v.Type == bson.TypeUndefined
+	switch {
+	case isNull && p.Desc:
+		return bson.M{f: nil, "_id": bson.M{op: id}}
+	case isNull:
+		return bson.M{"$or": bson.A{bson.M{f: bson.M{"$ne": nil}}, bson.M{f: nil, "_id": bson.M{op: id}}}}
+	case p.Desc:
+		return bson.M{"$or": bson.A{bson.M{f: bson.M{op: v}}, bson.M{f: v, "_id": bson.M{op: id}}, bson.M{f: nil}}}
+	}
+	return bson.M{"$or": bson.A{bson.M{f: bson.M{op: v}}, bson.M{f: v, "_id": bson.M{op: id}}}}
+}
+
+func nextCursor(p ListPage, last interface{}) string {
+	b, err := bson.Marshal(last)
+	if err != nil {
+		return ""
+	}
+	doc := bson.Raw(b)
+	id, ok := doc.Lookup("_id").ObjectIDOK()
+	if !ok {
+		return ""
+	}
+	c := Cursor{Sort: p.sort, ID: id}
+	if val, err := doc.LookupErr(strings.Split(p.field(), ".")...); err == nil {
+		c.Value = val
+	} else {
+		c.Value = bson.RawValue{Type: bson.TypeNull}
+	}
+	out, err := bson.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(out)
+}
+
+func decodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var c Cursor
+	if err := bson.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+	if c.ID.IsZero() {
+		return nil, fmt.Errorf("cursor tanpa _id")
+	}
+	return &c, nil
+}
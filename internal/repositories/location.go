@@ -0,0 +1,1162 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LocationQuery adalah filter + pagination untuk daftar lokasi.
+type LocationQuery struct {
+	Category   string
+	CreatedBy  string
+	Text       string // dicocokkan ke nama/alamat (case-insensitive)
+	Attributes AttributeFilter
+	// true = hanya lokasi di trash, false = hanya lokasi yang tidak dihapus
+	Trashed bool
+	// Ikut mencari di arsip (hanya List dan Each)
+	IncludeArchived bool
+	// Kosong = hanya lokasi yang sudah tayang; selain itu moderation_status
+	// harus sama dengan nilai ini
+	Moderation string
+	// Jika diisi, hanya lokasi dengan promosi yang aktif pada waktu ini
+	PromotedAt time.Time
+	// Jika diisi, hanya lokasi di dalam scope moderator
+	Scope *models.RoleScope
+	ListPage
+}
+
+// TrashList adalah sort dan batas GET /locations/trash, yang terakhir
+// dihapus dulu.
+var TrashList = ListSpec{
+	Sorts:        map[string]string{"deleted_at": "deleted_at"},
+	DefaultSort:  "-deleted_at",
+	DefaultLimit: 20,
+	MaxLimit:     100,
+}
+
+// PendingLocationList adalah sort dan batas antrean moderasi lokasi, yang
+// paling lama dikirim dulu.
+var PendingLocationList = ListSpec{
+	Sorts:        map[string]string{"created_at": "_id"},
+	DefaultSort:  "created_at",
+	DefaultLimit: 20,
+	MaxLimit:     100,
+}
+
+// StaleList adalah sort dan batas GET /admin/locations/stale, yang paling
+// lama tidak dikonfirmasi dulu.
+var StaleList = ListSpec{
+	Sorts:        map[string]string{"last_confirmed_at": "last_confirmed_at"},
+	DefaultSort:  "last_confirmed_at",
+	DefaultLimit: 20,
+	MaxLimit:     100,
+}
+
+// LocationList adalah sort dan batas GET /locations. Limit mengikuti
+// pengaturan pencarian (NearLimits), jadi diisi service. created_at memakai
+// _id (ObjectID berurutan waktu) supaya dokumen lama ikut terurut.
+var LocationList = ListSpec{
+	Sorts: map[string]string{"name": "name", "category": "category", "created_at": "_id",
+		"favorites": CounterFavorites, "checkins": CounterCheckIns},
+	DefaultSort: "-created_at",
+}
+
+// Nilai DanglingReference.Collection
+const (
+	RefReviews   = "reviews"
+	RefFavorites = "favorites"
+	RefCheckIns  = "checkins"
+)
+
+// Counter di dokumen lokasi yang dijaga lewat IncrementCounter
+const (
+	CounterFavorites = "favorites_count"
+	CounterCheckIns  = "checkins_count"
+)
+
+// Lokasi yang dihapus (soft delete) tetap ada sampai di-purge; semua query
+// biasa wajib menyertakan filter ini.
+func notDeleted(filter bson.M) bson.M {
+	filter["deleted_at"] = nil
+	return filter
+}
+
+// listed membatasi filter ke lokasi yang boleh tampil untuk publik: tidak
+// dihapus dan tidak sedang pending/rejected.
+func listed(filter bson.M) bson.M {
+	filter["moderation_status"] = bson.M{"$nin": bson.A{models.ModerationPending, models.ModerationRejected}}
+	return notDeleted(filter)
+}
+
+// AttributeFilter adalah filter atribut lokasi yang sama untuk list,
+// nearby, export dan facet.
+type AttributeFilter struct {
+	// Field Accessibility yang wajib bernilai yes (mis. wheelchair)
+	Accessible []string
+	// Cocok jika price_range salah satu dari ini
+	PriceRanges []string
+	// Cocok jika semua metode pembayaran ini diterima
+	PaymentMethods []string
+}
+
+func (f AttributeFilter) apply(filter bson.M) {
+	for _, field := range f.Accessible {
+		filter["accessibility."+field] = models.AccessYes
+	}
+	if len(f.PriceRanges) > 0 {
+		filter["price_range"] = bson.M{"$in": f.PriceRanges}
+	}
+	if len(f.PaymentMethods) > 0 {
+		filter["payment_methods"] = bson.M{"$all": f.PaymentMethods}
+	}
+}
+
+// NearbyQuery adalah parameter pencarian lokasi terdekat.
+type NearbyQuery struct {
+	Lat, Lng   float64
+	RadiusM    float64
+	Category   string
+	Attributes AttributeFilter
+	Limit      int
+	SortField  string // distance | name
+	SortDesc   bool
+}
+
+type LocationRepository interface {
+	List(ctx context.Context, q LocationQuery) ([]models.Location, int64, error)
+	Nearby(ctx context.Context, q NearbyQuery) ([]models.NearbyLocation, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Location, error)
+	// FindWithRating sama seperti FindByID ditambah ringkasan rating.
+	FindWithRating(ctx context.Context, id primitive.ObjectID) (*models.Location, error)
+	// Create memakai loc.ID jika sudah diisi; ErrDuplicate jika ID itu
+	// sudah dipakai.
+	Create(ctx context.Context, loc *models.Location) error
+	CreateMany(ctx context.Context, locs []models.Location) error
+	// Each memanggil fn untuk setiap lokasi yang cocok tanpa memuat
+	// semuanya ke memori. Pagination di q diabaikan.
+	Each(ctx context.Context, q LocationQuery, fn func(models.Location) error) error
+	// Facets menghitung lokasi per kategori, kisaran harga dan metode
+	// pembayaran untuk filter q (pagination & sort diabaikan).
+	Facets(ctx context.Context, q LocationQuery) (models.LocationFacets, error)
+	Update(ctx context.Context, id primitive.ObjectID, set Fields) error
+	// SoftDelete memindahkan lokasi ke trash; ErrNotFound jika tidak ada
+	// atau sudah di trash.
+	SoftDelete(ctx context.Context, id primitive.ObjectID, by string, at time.Time) error
+	// FindDeleted mencari lokasi di trash.
+	FindDeleted(ctx context.Context, id primitive.ObjectID) (*models.Location, error)
+	Restore(ctx context.Context, id primitive.ObjectID) error
+	// DeletedBefore mengembalikan lokasi yang masuk trash sebelum before,
+	// yang paling lama lebih dulu.
+	DeletedBefore(ctx context.Context, before time.Time, limit int64) ([]models.Location, error)
+	CountDeletedBefore(ctx context.Context, before time.Time) (int64, error)
+	// Delete menghapus dokumen secara permanen, termasuk yang di trash.
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	// FindArchivedWithRating mencari lokasi di arsip beserta ringkasan rating.
+	FindArchivedWithRating(ctx context.Context, id primitive.ObjectID) (*models.Location, error)
+	// ArchiveCandidates mengembalikan lokasi (bukan trash) yang tutup
+	// permanen atau tidak diubah maupun dikonfirmasi sejak before.
+	ArchiveCandidates(ctx context.Context, before time.Time, limit int64) ([]models.Location, error)
+	CountArchiveCandidates(ctx context.Context, before time.Time) (int64, error)
+	// Archive memindahkan lokasi ke arsip. Aman diulang jika gagal di tengah.
+	Archive(ctx context.Context, locs []models.Location, at time.Time) error
+	// Unarchive mengembalikan lokasi dari arsip; ErrNotFound jika tidak ada.
+	Unarchive(ctx context.Context, id primitive.ObjectID, at time.Time) (*models.Location, error)
+	// AddPhoto menambahkan foto selama jumlah foto masih di bawah max.
+	// Mengembalikan false jika lokasi tidak ada atau foto sudah penuh.
+	AddPhoto(ctx context.Context, id primitive.ObjectID, photo models.Photo, max int) (bool, error)
+	// RemovePhoto menghapus foto dari lokasi; ErrNotFound jika tidak ada.
+	RemovePhoto(ctx context.Context, id, photoID primitive.ObjectID) error
+	// IncrementCounter menambah counter (Counter*) lokasi sebanyak delta.
+	// Lokasi yang tidak ada (atau di arsip) diabaikan; selisihnya diperbaiki
+	// lewat Counters dan SetCounters.
+	IncrementCounter(ctx context.Context, id primitive.ObjectID, counter string, delta int64) error
+	// Counters menghitung ulang counter lokasi (termasuk trash) yang _id-nya
+	// setelah after dari collection favorites dan checkins, urut _id.
+	Counters(ctx context.Context, after primitive.ObjectID, limit int64) ([]models.LocationCounters, error)
+	// SetCounters menimpa counter lokasi dengan hasil hitung ulang c.
+	SetCounters(ctx context.Context, c models.LocationCounters) error
+	// DanglingReferences mengelompokkan ulasan, favorit dan check-in per
+	// location_id yang tidak ada lagi, baik di geo_data (termasuk trash)
+	// maupun di arsip. Paling banyak limit lokasi per collection, urut _id.
+	DanglingReferences(ctx context.Context, limit int64) ([]models.DanglingReference, error)
+	// Creators menghitung lokasi (bukan trash, tanpa arsip) per created_by.
+	Creators(ctx context.Context) ([]models.CreatorCount, error)
+	// WithPhotos mengembalikan lokasi (bukan trash) yang punya foto dan
+	// _id-nya setelah after, urut _id.
+	WithPhotos(ctx context.Context, after primitive.ObjectID, limit int64) ([]models.Location, error)
+	// Confirm mencatat satu konfirmasi "data masih akurat" dan
+	// mengembalikan lokasi setelah diubah.
+	Confirm(ctx context.Context, id primitive.ObjectID, at time.Time) (*models.Location, error)
+	// Moderate mengubah lokasi (bukan trash) yang moderation_status-nya
+	// salah satu dari from dan mengembalikan lokasi setelah diubah;
+	// ErrNotFound jika tidak ada yang cocok.
+	Moderate(ctx context.Context, id primitive.ObjectID, from []string, set Fields) (*models.Location, error)
+	// Stale mengembalikan lokasi yang terakhir dikonfirmasi (atau dibuat,
+	// jika belum pernah) sebelum before.
+	Stale(ctx context.Context, before time.Time, scope *models.RoleScope, p ListPage) ([]models.Location, int64, error)
+	// FreshnessAlertCandidates mengembalikan lokasi milik owner yang
+	// terakhir dikonfirmasi (atau dibuat) sebelum before dan belum
+	// diperingatkan sejak konfirmasi terakhir, paling basi dulu.
+	FreshnessAlertCandidates(ctx context.Context, owner string, before time.Time, limit int64) ([]models.Location, error)
+	MarkFreshnessAlerted(ctx context.Context, ids []primitive.ObjectID, at time.Time) error
+	CountByCreator(ctx context.Context, email string) (int64, error)
+	// CountByCategory ikut menghitung lokasi di arsip.
+	CountByCategory(ctx context.Context, category string) (int64, error)
+	// WithoutElevation mengembalikan lokasi yang elevation_m-nya kosong/null.
+	WithoutElevation(ctx context.Context, limit int64) ([]models.Location, error)
+	CountWithoutElevation(ctx context.Context) (int64, error)
+	// TagAdminArea mengisi admin_area.<level> untuk semua lokasi di dalam
+	// geometry. Mengembalikan jumlah lokasi yang berubah.
+	TagAdminArea(ctx context.Context, level string, ref models.RegionRef, geometry *models.Geometry) (int64, error)
+	// RegionStats menghitung lokasi dengan admin_area.<level>.code = code;
+	// Children dikelompokkan per childLevel (kosong = tidak dihitung).
+	RegionStats(ctx context.Context, level, code, childLevel string) (models.RegionStats, error)
+	// PublicStats menghitung lokasi tayang, jumlah kontributornya dan waktu
+	// perubahan terakhir. Categories dan GeneratedAt tidak diisi.
+	PublicStats(ctx context.Context) (models.PublicStats, error)
+	// ReassignCandidates mengembalikan lokasi (bukan trash, termasuk yang
+	// pending) yang cocok dengan f, belum dimiliki target dan _id-nya
+	// setelah after, urut _id.
+	ReassignCandidates(ctx context.Context, f models.ReassignFilter, target string, after primitive.ObjectID, limit int64) ([]models.Location, error)
+	CountReassignCandidates(ctx context.Context, f models.ReassignFilter, target string, after primitive.ObjectID) (int64, error)
+	// SchemaOutdated mengembalikan lokasi (termasuk trash dan arsip) yang
+	// tersimpan dengan schema_version di bawah version, dalam bentuk yang
+	// sudah di-upgrade. Lokasi dari arsip ditandai ArchivedAt.
+	SchemaOutdated(ctx context.Context, version int, limit int64) ([]models.Location, error)
+	CountSchemaOutdated(ctx context.Context, version int) (int64, error)
+	// SaveUpgraded menulis ulang lokasi hasil SchemaOutdated. Tidak
+	// melakukan apa-apa jika dokumennya sudah ditulis ulang proses lain.
+	SaveUpgraded(ctx context.Context, loc models.Location) error
+	// Migrate mengubah koordinat lama ke GeoJSON dan membuat index.
+	Migrate(ctx context.Context) (int64, error)
+	HasIndex(ctx context.Context, key string, unique bool) (bool, error)
+}
+
+type mongoLocationRepository struct {
+	coll *mongo.Collection
+	// Lokasi lama/tutup permanen, di luar query biasa supaya coll dan
+	// index-nya tetap kecil
+	archive *mongo.Collection
+	// Sumber rating pada List, Nearby dan FindWithRating
+	reviews *mongo.Collection
+	// Sumber rekonsiliasi counter
+	favorites *mongo.Collection
+	checkins  *mongo.Collection
+}
+
+func NewLocationRepository(coll, archive, reviews, favorites, checkins *mongo.Collection) LocationRepository {
+	return &mongoLocationRepository{coll: coll, archive: archive, reviews: reviews, favorites: favorites, checkins: checkins}
+}
+
+// Field yang dihitung saat dibaca: rating. Counter favorit dan check-in
+// tersimpan di dokumen.
+func (r *mongoLocationRepository) derivedStages() []bson.D {
+	return ratingStages(r.reviews)
+}
+
+func (q LocationQuery) filter() bson.M {
+	filter := bson.M{}
+	if q.Category != "" {
+		filter["category"] = q.Category
+	}
+	if q.CreatedBy != "" {
+		filter["created_by"] = q.CreatedBy
+	}
+	if q.Text != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(q.Text), Options: "i"}
+		filter["$or"] = bson.A{bson.M{"name": pattern}, bson.M{"address": pattern}}
+	}
+	q.Attributes.apply(filter)
+	if q.Scope != nil {
+		filter["$and"] = bson.A{scopeFilter(q.Scope)}
+	}
+	if !q.PromotedAt.IsZero() {
+		filter["promotion.starts_at"] = bson.M{"$lte": q.PromotedAt}
+		filter["promotion.ends_at"] = bson.M{"$gt": q.PromotedAt}
+	}
+	if q.Trashed {
+		filter["deleted_at"] = bson.M{"$ne": nil}
+		return filter
+	}
+	if q.Moderation != "" {
+		filter["moderation_status"] = q.Moderation
+		return notDeleted(filter)
+	}
+	return listed(filter)
+}
+
+// scopeFilter sama dengan RoleScope.Contains; wilayah dicocokkan ke kode
+// admin_area level mana pun.
+func scopeFilter(scope *models.RoleScope) bson.M {
+	filter := bson.M{}
+	if len(scope.Categories) > 0 {
+		filter["category"] = bson.M{"$in": scope.Categories}
+	}
+	if len(scope.Regions) > 0 {
+		var or bson.A
+		for _, level := range models.RegionLevels {
+			or = append(or, bson.M{"admin_area." + level + ".code": bson.M{"$in": scope.Regions}})
+		}
+		filter["$or"] = or
+	}
+	return filter
+}
+
+func (r *mongoLocationRepository) List(ctx context.Context, q LocationQuery) ([]models.Location, int64, error) {
+	filter := q.filter()
+	total, err := r.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if q.IncludeArchived {
+		archived, err := r.archive.CountDocuments(ctx, filter)
+		if err != nil {
+			return nil, 0, err
+		}
+		total += archived
+	}
+	match := q.Match(filter)
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: match}}}
+	if q.IncludeArchived {
+		pipeline = append(pipeline, bson.D{{Key: "$unionWith", Value: bson.M{
+			"coll":     r.archive.Name(),
+			"pipeline": bson.A{bson.M{"$match": match}},
+		}}})
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$sort", Value: q.SortDoc()}},
+		bson.D{{Key: "$skip", Value: q.Skip}},
+	)
+	if q.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: q.Limit}})
+	}
+	pipeline = append(pipeline, r.derivedStages()...)
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+	locations := []models.Location{}
+	if err := cursor.All(ctx, &locations); err != nil {
+		return nil, 0, err
+	}
+	return locations, total, nil
+}
+
+func (r *mongoLocationRepository) Nearby(ctx context.Context, q NearbyQuery) ([]models.NearbyLocation, error) {
+	query := listed(bson.M{})
+	if q.Category != "" {
+		query["category"] = q.Category
+	}
+	q.Attributes.apply(query)
+	pipeline := mongo.Pipeline{
+		{{Key: "$geoNear", Value: bson.M{
+			"near":          bson.M{"type": "Point", "coordinates": bson.A{q.Lng, q.Lat}},
+			"distanceField": "distance_m",
+			"maxDistance":   q.RadiusM,
+			"query":         query,
+			"spherical":     true,
+		}}},
+		{{Key: "$limit", Value: q.Limit}},
+	}
+	pipeline = append(pipeline, r.derivedStages()...)
+	if q.SortField == "name" {
+		dir := 1
+		if q.SortDesc {
+			dir = -1
+		}
+		pipeline = append(pipeline, bson.D{{Key: "$sort", Value: bson.D{{Key: "name", Value: dir}}}})
+	}
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	results := []models.NearbyLocation{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *mongoLocationRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Location, error) {
+	var loc models.Location
+	if err := r.coll.FindOne(ctx, notDeleted(bson.M{"_id": id})).Decode(&loc); err != nil {
+		return nil, notFound(err)
+	}
+	return &loc, nil
+}
+
+func (r *mongoLocationRepository) FindWithRating(ctx context.Context, id primitive.ObjectID) (*models.Location, error) {
+	return r.findWithRating(ctx, r.coll, notDeleted(bson.M{"_id": id}))
+}
+
+func (r *mongoLocationRepository) FindArchivedWithRating(ctx context.Context, id primitive.ObjectID) (*models.Location, error) {
+	return r.findWithRating(ctx, r.archive, bson.M{"_id": id})
+}
+
+func (r *mongoLocationRepository) findWithRating(ctx context.Context, coll *mongo.Collection, filter bson.M) (*models.Location, error) {
+	pipeline := append(mongo.Pipeline{{{Key: "$match", Value: filter}}}, r.derivedStages()...)
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	if !cursor.Next(ctx) {
+		if err := cursor.Err(); err != nil {
+			return nil, err
+		}
+		return nil, ErrNotFound
+	}
+	var loc models.Location
+	if err := cursor.Decode(&loc); err != nil {
+		return nil, err
+	}
+	return &loc, nil
+}
+
+func (r *mongoLocationRepository) Create(ctx context.Context, loc *models.Location) error {
+	if loc.ID.IsZero() {
+		loc.ID = primitive.NewObjectID()
+	}
+	loc.SchemaVersion = models.LocationSchema.Current()
+	_, err := r.coll.InsertOne(ctx, loc)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *mongoLocationRepository) CreateMany(ctx context.Context, locs []models.Location) error {
+	if len(locs) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, len(locs))
+	for i := range locs {
+		if locs[i].ID.IsZero() {
+			locs[i].ID = primitive.NewObjectID()
+		}
+		locs[i].SchemaVersion = models.LocationSchema.Current()
+		docs[i] = locs[i]
+	}
+	_, err := r.coll.InsertMany(ctx, docs)
+	return err
+}
+
+// Lokasi di arsip dikirim setelah semua lokasi di coll.
+func (r *mongoLocationRepository) Each(ctx context.Context, q LocationQuery, fn func(models.Location) error) error {
+	if err := each(ctx, r.coll, q.filter(), fn); err != nil {
+		return err
+	}
+	if q.IncludeArchived {
+		return each(ctx, r.archive, q.filter(), fn)
+	}
+	return nil
+}
+
+func each(ctx context.Context, coll *mongo.Collection, filter bson.M, fn func(models.Location) error) error {
+	cursor, err := coll.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var loc models.Location
+		if err := cursor.Decode(&loc); err != nil {
+			return err
+		}
+		if err := fn(loc); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func (r *mongoLocationRepository) Update(ctx context.Context, id primitive.ObjectID, set Fields) error {
+	_, err := r.coll.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M(set)})
+	return err
+}
+
+func (r *mongoLocationRepository) SoftDelete(ctx context.Context, id primitive.ObjectID, by string, at time.Time) error {
+	res, err := r.coll.UpdateOne(ctx, notDeleted(bson.M{"_id": id}),
+		bson.M{"$set": bson.M{"deleted_at": at, "deleted_by": by}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoLocationRepository) FindDeleted(ctx context.Context, id primitive.ObjectID) (*models.Location, error) {
+	var loc models.Location
+	if err := r.coll.FindOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}}).Decode(&loc); err != nil {
+		return nil, notFound(err)
+	}
+	return &loc, nil
+}
+
+func (r *mongoLocationRepository) Restore(ctx context.Context, id primitive.ObjectID) error {
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}},
+		bson.M{"$unset": bson.M{"deleted_at": "", "deleted_by": ""}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoLocationRepository) DeletedBefore(ctx context.Context, before time.Time, limit int64) ([]models.Location, error) {
+	cursor, err := r.coll.Find(ctx, bson.M{"deleted_at": bson.M{"$lt": before}},
+		options.Find().SetSort(bson.D{{Key: "deleted_at", Value: 1}}).SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	locations := []models.Location{}
+	if err := cursor.All(ctx, &locations); err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+func (r *mongoLocationRepository) CountDeletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.coll.CountDocuments(ctx, bson.M{"deleted_at": bson.M{"$lt": before}})
+}
+
+func (r *mongoLocationRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.coll.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// Dokumen lama tanpa updated_at memakai waktu pembuatan (_id). Job archive
+// jarang berjalan, jadi filter ini sengaja tidak diberi index.
+func archiveFilter(before time.Time) bson.M {
+	return notDeleted(bson.M{"$or": bson.A{
+		bson.M{"status": models.StatusPermanentlyClosed},
+		bson.M{"$and": bson.A{
+			bson.M{"$or": bson.A{
+				bson.M{"updated_at": bson.M{"$lt": before}},
+				bson.M{"updated_at": nil, "_id": bson.M{"$lt": primitive.NewObjectIDFromTimestamp(before)}},
+			}},
+			bson.M{"$or": bson.A{
+				bson.M{"last_confirmed_at": nil},
+				bson.M{"last_confirmed_at": bson.M{"$lt": before}},
+			}},
+		}},
+	}})
+}
+
+func (r *mongoLocationRepository) ArchiveCandidates(ctx context.Context, before time.Time, limit int64) ([]models.Location, error) {
+	cursor, err := r.coll.Find(ctx, archiveFilter(before), options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	locations := []models.Location{}
+	if err := cursor.All(ctx, &locations); err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+func (r *mongoLocationRepository) CountArchiveCandidates(ctx context.Context, before time.Time) (int64, error) {
+	return r.coll.CountDocuments(ctx, archiveFilter(before))
+}
+
+// Salin ke arsip dulu (upsert) baru hapus dari coll, supaya lokasi tidak
+// pernah hilang jika proses berhenti di tengah.
+func (r *mongoLocationRepository) Archive(ctx context.Context, locs []models.Location, at time.Time) error {
+	if len(locs) == 0 {
+		return nil
+	}
+	writes := make([]mongo.WriteModel, len(locs))
+	ids := make([]primitive.ObjectID, len(locs))
+	for i := range locs {
+		locs[i].ArchivedAt = &at
+		writes[i] = mongo.NewReplaceOneModel().SetFilter(bson.M{"_id": locs[i].ID}).SetReplacement(locs[i]).SetUpsert(true)
+		ids[i] = locs[i].ID
+	}
+	if _, err := r.archive.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return err
+	}
+	_, err := r.coll.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	return err
+}
+
+// updated_at diisi at supaya lokasi tidak langsung diarsipkan lagi.
+func (r *mongoLocationRepository) Unarchive(ctx context.Context, id primitive.ObjectID, at time.Time) (*models.Location, error) {
+	var loc models.Location
+	if err := r.archive.FindOne(ctx, bson.M{"_id": id}).Decode(&loc); err != nil {
+		return nil, notFound(err)
+	}
+	loc.ArchivedAt = nil
+	loc.UpdatedAt = &at
+	if _, err := r.coll.ReplaceOne(ctx, bson.M{"_id": id}, loc, options.Replace().SetUpsert(true)); err != nil {
+		return nil, err
+	}
+	if _, err := r.archive.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		return nil, err
+	}
+	return &loc, nil
+}
+
+// Batas jumlah foto dicek di filter supaya upload bersamaan tidak bisa
+// melewatinya
+func (r *mongoLocationRepository) AddPhoto(ctx context.Context, id primitive.ObjectID, photo models.Photo, max int) (bool, error) {
+	filter := notDeleted(bson.M{"_id": id, "photos." + strconv.Itoa(max-1): bson.M{"$exists": false}})
+	res, err := r.coll.UpdateOne(ctx, filter, bson.M{"$push": bson.M{"photos": photo}})
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+func (r *mongoLocationRepository) RemovePhoto(ctx context.Context, id, photoID primitive.ObjectID) error {
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$pull": bson.M{"photos": bson.M{"_id": photoID}}})
+	if err != nil {
+		return err
+	}
+	if res.ModifiedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoLocationRepository) IncrementCounter(ctx context.Context, id primitive.ObjectID, counter string, delta int64) error {
+	_, err := r.coll.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{counter: delta}})
+	return err
+}
+
+func (r *mongoLocationRepository) Counters(ctx context.Context, after primitive.ObjectID, limit int64) ([]models.LocationCounters, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"_id": bson.M{"$gt": after}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+		countLookup(r.favorites, "favorites"),
+		countLookup(r.checkins, "checkins"),
+		{{Key: "$project", Value: bson.M{
+			CounterFavorites: bson.M{"$ifNull": bson.A{"$" + CounterFavorites, 0}},
+			CounterCheckIns:  bson.M{"$ifNull": bson.A{"$" + CounterCheckIns, 0}},
+			"favorites":      bson.M{"$ifNull": bson.A{bson.M{"$arrayElemAt": bson.A{"$favorites.n", 0}}, 0}},
+			"checkins":       bson.M{"$ifNull": bson.A{bson.M{"$arrayElemAt": bson.A{"$checkins.n", 0}}, 0}},
+		}}},
+	}
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	counters := []models.LocationCounters{}
+	if err := cursor.All(ctx, &counters); err != nil {
+		return nil, err
+	}
+	return counters, nil
+}
+
+// countLookup menghitung dokumen from dengan location_id = _id lokasi ke
+// field as ([{n}], kosong jika tidak ada).
+func countLookup(from *mongo.Collection, as string) bson.D {
+	return bson.D{{Key: "$lookup", Value: bson.M{
+		"from": from.Name(),
+		"let":  bson.M{"location_id": "$_id"},
+		"pipeline": bson.A{
+			bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$location_id", "$$location_id"}}}},
+			bson.M{"$count": "n"},
+		},
+		"as": as,
+	}}}
+}
+
+func (r *mongoLocationRepository) DanglingReferences(ctx context.Context, limit int64) ([]models.DanglingReference, error) {
+	refs := []models.DanglingReference{}
+	sources := map[string]*mongo.Collection{RefReviews: r.reviews, RefFavorites: r.favorites, RefCheckIns: r.checkins}
+	for _, name := range []string{RefReviews, RefFavorites, RefCheckIns} {
+		from := sources[name]
+		pipeline := mongo.Pipeline{
+			{{Key: "$group", Value: bson.M{"_id": "$location_id", "count": bson.M{"$sum": 1}}}},
+			existsLookup(r.coll, "location"),
+			existsLookup(r.archive, "archived"),
+			{{Key: "$match", Value: bson.M{"location": bson.M{"$size": 0}, "archived": bson.M{"$size": 0}}}},
+			{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+			{{Key: "$limit", Value: limit}},
+			{{Key: "$project", Value: bson.M{"count": 1, "collection": bson.M{"$literal": name}}}},
+		}
+		cursor, err := from.Aggregate(ctx, pipeline)
+		if err != nil {
+			return nil, err
+		}
+		var batch []models.DanglingReference
+		err = cursor.All(ctx, &batch)
+		cursor.Close(ctx)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, batch...)
+	}
+	return refs, nil
+}
+
+// existsLookup mengisi field as dengan [{_id}] jika dokumen from dengan
+// _id yang sama ada, kosong jika tidak.
+func existsLookup(from *mongo.Collection, as string) bson.D {
+	return bson.D{{Key: "$lookup", Value: bson.M{
+		"from": from.Name(),
+		"let":  bson.M{"id": "$_id"},
+		"pipeline": bson.A{
+			bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$id"}}}},
+			bson.M{"$project": bson.M{"_id": 1}},
+		},
+		"as": as,
+	}}}
+}
+
+func (r *mongoLocationRepository) Creators(ctx context.Context) ([]models.CreatorCount, error) {
+	cursor, err := r.coll.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: notDeleted(bson.M{})}},
+		{{Key: "$group", Value: bson.M{"_id": "$created_by", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	creators := []models.CreatorCount{}
+	if err := cursor.All(ctx, &creators); err != nil {
+		return nil, err
+	}
+	return creators, nil
+}
+
+func (r *mongoLocationRepository) WithPhotos(ctx context.Context, after primitive.ObjectID, limit int64) ([]models.Location, error) {
+	filter := notDeleted(bson.M{"_id": bson.M{"$gt": after}, "photos.0": bson.M{"$exists": true}})
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit)
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	locations := []models.Location{}
+	if err := cursor.All(ctx, &locations); err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+func (r *mongoLocationRepository) SetCounters(ctx context.Context, c models.LocationCounters) error {
+	_, err := r.coll.UpdateOne(ctx, bson.M{"_id": c.ID}, bson.M{"$set": bson.M{CounterFavorites: c.Favorites, CounterCheckIns: c.CheckIns}})
+	return err
+}
+
+func (r *mongoLocationRepository) Confirm(ctx context.Context, id primitive.ObjectID, at time.Time) (*models.Location, error) {
+	var loc models.Location
+	err := r.coll.FindOneAndUpdate(ctx, notDeleted(bson.M{"_id": id}),
+		bson.M{"$set": bson.M{"last_confirmed_at": at}, "$inc": bson.M{"confirmation_count": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&loc)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &loc, nil
+}
+
+// Dokumen lama tanpa moderation_status dicocokkan sebagai approved
+func (r *mongoLocationRepository) Moderate(ctx context.Context, id primitive.ObjectID, from []string, set Fields) (*models.Location, error) {
+	states := bson.A{}
+	for _, st := range from {
+		states = append(states, st)
+		if st == models.ModerationApproved {
+			states = append(states, nil)
+		}
+	}
+	var loc models.Location
+	err := r.coll.FindOneAndUpdate(ctx, notDeleted(bson.M{"_id": id, "moderation_status": bson.M{"$in": states}}),
+		bson.M{"$set": bson.M(set)},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&loc)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &loc, nil
+}
+
+// Lokasi yang belum pernah dikonfirmasi memakai waktu pembuatan dari _id
+func (r *mongoLocationRepository) Stale(ctx context.Context, before time.Time, scope *models.RoleScope, p ListPage) ([]models.Location, int64, error) {
+	filter := notDeleted(bson.M{"$or": bson.A{
+		bson.M{"last_confirmed_at": bson.M{"$lt": before}},
+		bson.M{"last_confirmed_at": nil, "_id": bson.M{"$lt": primitive.NewObjectIDFromTimestamp(before)}},
+	}})
+	if scope != nil {
+		filter["$and"] = bson.A{scopeFilter(scope)}
+	}
+	total, err := r.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	cursor, err := r.coll.Find(ctx, p.Match(filter), p.FindOptions())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+	locations := []models.Location{}
+	if err := cursor.All(ctx, &locations); err != nil {
+		return nil, 0, err
+	}
+	return locations, total, nil
+}
+
+func (r *mongoLocationRepository) FreshnessAlertCandidates(ctx context.Context, owner string, before time.Time, limit int64) ([]models.Location, error) {
+	filter := notDeleted(bson.M{
+		"created_by": owner,
+		"$and": bson.A{
+			bson.M{"$or": bson.A{
+				bson.M{"last_confirmed_at": bson.M{"$lt": before}},
+				bson.M{"last_confirmed_at": nil, "_id": bson.M{"$lt": primitive.NewObjectIDFromTimestamp(before)}},
+			}},
+			// Peringatan lama tidak berlaku lagi setelah lokasi dikonfirmasi
+			bson.M{"$or": bson.A{
+				bson.M{"freshness_alerted_at": nil},
+				bson.M{"$expr": bson.M{"$lt": bson.A{"$freshness_alerted_at", "$last_confirmed_at"}}},
+			}},
+		},
+	})
+	opts := options.Find().SetSort(bson.D{{Key: "last_confirmed_at", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(limit)
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	locations := []models.Location{}
+	if err := cursor.All(ctx, &locations); err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+func (r *mongoLocationRepository) MarkFreshnessAlerted(ctx context.Context, ids []primitive.ObjectID, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := r.coll.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{"$set": bson.M{"freshness_alerted_at": at}})
+	return err
+}
+
+func (r *mongoLocationRepository) CountByCreator(ctx context.Context, email string) (int64, error) {
+	return r.coll.CountDocuments(ctx, notDeleted(bson.M{"created_by": email}))
+}
+
+// Lokasi di trash ikut dihitung supaya kategori yang masih dipakai tidak
+// terhapus sebelum lokasinya di-purge (restore tetap aman).
+func (r *mongoLocationRepository) CountByCategory(ctx context.Context, category string) (int64, error) {
+	n, err := r.coll.CountDocuments(ctx, bson.M{"category": category})
+	if err != nil {
+		return 0, err
+	}
+	archived, err := r.archive.CountDocuments(ctx, bson.M{"category": category})
+	return n + archived, err
+}
+
+func (r *mongoLocationRepository) Facets(ctx context.Context, q LocationQuery) (models.LocationFacets, error) {
+	countBy := func(field string) bson.A {
+		return bson.A{
+			bson.M{"$group": bson.M{"_id": field, "count": bson.M{"$sum": 1}}},
+			bson.M{"$match": bson.M{"_id": bson.M{"$nin": bson.A{nil, ""}}}},
+			bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: q.filter()}},
+		{{Key: "$facet", Value: bson.M{
+			"total":           bson.A{bson.M{"$count": "n"}},
+			"category":        countBy("$category"),
+			"price_range":     countBy("$price_range"),
+			"payment_methods": append(bson.A{bson.M{"$unwind": "$payment_methods"}}, countBy("$payment_methods")...),
+		}}},
+	}
+	facets := models.LocationFacets{Category: []models.FacetCount{}, PriceRange: []models.FacetCount{}, PaymentMethods: []models.FacetCount{}}
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return facets, err
+	}
+	defer cursor.Close(ctx)
+	var out []struct {
+		Total          []struct{ N int64 } `bson:"total"`
+		Category       []models.FacetCount `bson:"category"`
+		PriceRange     []models.FacetCount `bson:"price_range"`
+		PaymentMethods []models.FacetCount `bson:"payment_methods"`
+	}
+	if err := cursor.All(ctx, &out); err != nil || len(out) == 0 {
+		return facets, err
+	}
+	if len(out[0].Total) > 0 {
+		facets.Total = out[0].Total[0].N
+	}
+	if out[0].Category != nil {
+		facets.Category = out[0].Category
+	}
+	if out[0].PriceRange != nil {
+		facets.PriceRange = out[0].PriceRange
+	}
+	if out[0].PaymentMethods != nil {
+		facets.PaymentMethods = out[0].PaymentMethods
+	}
+	return facets, nil
+}
+
+// Dokumen lama tanpa updated_at memakai waktu pembuatan (_id)
+func (r *mongoLocationRepository) PublicStats(ctx context.Context) (models.PublicStats, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: listed(bson.M{})}},
+		{{Key: "$facet", Value: bson.M{
+			"summary": bson.A{bson.M{"$group": bson.M{
+				"_id":             nil,
+				"locations":       bson.M{"$sum": 1},
+				"last_updated_at": bson.M{"$max": bson.M{"$ifNull": bson.A{"$updated_at", bson.M{"$toDate": "$_id"}}}},
+			}}},
+			"contributors": bson.A{
+				bson.M{"$group": bson.M{"_id": "$created_by"}},
+				bson.M{"$count": "n"},
+			},
+		}}},
+	}
+	var stats models.PublicStats
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return stats, err
+	}
+	defer cursor.Close(ctx)
+	var out []struct {
+		Summary      []models.PublicStats `bson:"summary"`
+		Contributors []struct{ N int64 }  `bson:"contributors"`
+	}
+	if err := cursor.All(ctx, &out); err != nil || len(out) == 0 {
+		return stats, err
+	}
+	if len(out[0].Summary) > 0 {
+		stats = out[0].Summary[0]
+	}
+	if len(out[0].Contributors) > 0 {
+		stats.Contributors = out[0].Contributors[0].N
+	}
+	return stats, nil
+}
+
+func reassignFilter(f models.ReassignFilter, target string, after primitive.ObjectID) bson.M {
+	createdBy := bson.M{"$ne": target}
+	if f.CreatedBy != "" {
+		createdBy["$eq"] = f.CreatedBy
+	}
+	filter := bson.M{"_id": bson.M{"$gt": after}, "created_by": createdBy}
+	if f.Category != "" {
+		filter["category"] = f.Category
+	}
+	if f.Region != "" {
+		filter["admin_area."+f.RegionLevel+".code"] = f.Region
+	}
+	if f.Scope != nil {
+		filter["$and"] = bson.A{scopeFilter(f.Scope)}
+	}
+	return notDeleted(filter)
+}
+
+func (r *mongoLocationRepository) ReassignCandidates(ctx context.Context, f models.ReassignFilter, target string, after primitive.ObjectID, limit int64) ([]models.Location, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit)
+	cursor, err := r.coll.Find(ctx, reassignFilter(f, target, after), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	locations := []models.Location{}
+	if err := cursor.All(ctx, &locations); err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+func (r *mongoLocationRepository) CountReassignCandidates(ctx context.Context, f models.ReassignFilter, target string, after primitive.ObjectID) (int64, error) {
+	return r.coll.CountDocuments(ctx, reassignFilter(f, target, after))
+}
+
+func (r *mongoLocationRepository) TagAdminArea(ctx context.Context, level string, ref models.RegionRef, geometry *models.Geometry) (int64, error) {
+	filter := bson.M{"coordinates": bson.M{"$geoWithin": bson.M{"$geometry": geometry}}}
+	res, err := r.coll.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"admin_area." + level: ref}})
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+func (r *mongoLocationRepository) RegionStats(ctx context.Context, level, code, childLevel string) (models.RegionStats, error) {
+	facets := bson.M{
+		"total":       bson.A{bson.M{"$count": "n"}},
+		"verified":    bson.A{bson.M{"$match": bson.M{"verified": true}}, bson.M{"$count": "n"}},
+		"by_category": bson.A{bson.M{"$group": bson.M{"_id": "$category", "count": bson.M{"$sum": 1}}}, bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+	if childLevel != "" {
+		child := "$admin_area." + childLevel
+		facets["children"] = bson.A{
+			bson.M{"$match": bson.M{"admin_area." + childLevel: bson.M{"$ne": nil}}},
+			bson.M{"$group": bson.M{"_id": child + ".code", "name": bson.M{"$first": child + ".name"}, "count": bson.M{"$sum": 1}}},
+			bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: listed(bson.M{"admin_area." + level + ".code": code})}},
+		{{Key: "$facet", Value: facets}},
+	}
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return models.RegionStats{}, err
+	}
+	defer cursor.Close(ctx)
+	var out []struct {
+		Total      []struct{ N int64 }    `bson:"total"`
+		Verified   []struct{ N int64 }    `bson:"verified"`
+		ByCategory []models.CategoryCount `bson:"by_category"`
+		Children   []models.RegionCount   `bson:"children"`
+	}
+	if err := cursor.All(ctx, &out); err != nil {
+		return models.RegionStats{}, err
+	}
+	stats := models.RegionStats{ByCategory: []models.CategoryCount{}, Children: []models.RegionCount{}}
+	if len(out) == 0 {
+		return stats, nil
+	}
+	if len(out[0].Total) > 0 {
+		stats.Total = out[0].Total[0].N
+	}
+	if len(out[0].Verified) > 0 {
+		stats.Verified = out[0].Verified[0].N
+	}
+	if out[0].ByCategory != nil {
+		stats.ByCategory = out[0].ByCategory
+	}
+	if out[0].Children != nil {
+		stats.Children = out[0].Children
+	}
+	return stats, nil
+}
+
+func (r *mongoLocationRepository) WithoutElevation(ctx context.Context, limit int64) ([]models.Location, error) {
+	cursor, err := r.coll.Find(ctx, notDeleted(bson.M{"elevation_m": nil}),
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	locations := []models.Location{}
+	if err := cursor.All(ctx, &locations); err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+func (r *mongoLocationRepository) CountWithoutElevation(ctx context.Context) (int64, error) {
+	return r.coll.CountDocuments(ctx, notDeleted(bson.M{"elevation_m": nil}))
+}
+
+// Dokumen tanpa schema_version ikut cocok. Job backfill hanya berjalan
+// setelah ada versi baru, jadi filter ini sengaja tidak diberi index.
+func schemaOutdated(version int) bson.M {
+	return bson.M{"schema_version": bson.M{"$not": bson.M{"$gte": version}}}
+}
+
+// Lokasi di coll diproses lebih dulu, baru arsip.
+func (r *mongoLocationRepository) SchemaOutdated(ctx context.Context, version int, limit int64) ([]models.Location, error) {
+	locations := []models.Location{}
+	for _, coll := range []*mongo.Collection{r.coll, r.archive} {
+		if int64(len(locations)) >= limit {
+			break
+		}
+		opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit - int64(len(locations)))
+		cursor, err := coll.Find(ctx, schemaOutdated(version), opts)
+		if err != nil {
+			return nil, err
+		}
+		var batch []models.Location
+		err = cursor.All(ctx, &batch)
+		cursor.Close(ctx)
+		if err != nil {
+			return nil, err
+		}
+		locations = append(locations, batch...)
+	}
+	return locations, nil
+}
+
+func (r *mongoLocationRepository) CountSchemaOutdated(ctx context.Context, version int) (int64, error) {
+	var total int64
+	for _, coll := range []*mongo.Collection{r.coll, r.archive} {
+		n, err := coll.CountDocuments(ctx, schemaOutdated(version))
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// Filter versi mencegah dokumen yang sudah ditulis ulang (mis. oleh
+// panggilan job lain) ditimpa lagi. Perubahan lain di antara baca dan
+// tulis tetap bisa tertimpa, jadi jalankan job dengan batch kecil.
+func (r *mongoLocationRepository) SaveUpgraded(ctx context.Context, loc models.Location) error {
+	coll := r.coll
+	if loc.ArchivedAt != nil {
+		coll = r.archive
+	}
+	filter := schemaOutdated(loc.SchemaVersion)
+	filter["_id"] = loc.ID
+	_, err := coll.ReplaceOne(ctx, filter, loc)
+	return err
+}
+
+// Ubah dokumen lama {lat, lng} menjadi GeoJSON Point lalu buat index 2dsphere.
+// Aman dijalankan berulang kali: dokumen yang sudah GeoJSON tidak tersentuh.
+func (r *mongoLocationRepository) Migrate(ctx context.Context) (int64, error) {
+	res, err := r.coll.UpdateMany(ctx,
+		bson.M{"coordinates.lat": bson.M{"$exists": true}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"coordinates": bson.M{
+				"type":        "Point",
+				"coordinates": bson.A{"$coordinates.lng", "$coordinates.lat"},
+			},
+		}}}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	_, err = r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "coordinates", Value: "2dsphere"}}},
+		// Untuk filter ?category / ?created_by di GET /locations
+		{Keys: bson.D{{Key: "category", Value: 1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "created_by", Value: 1}, {Key: "_id", Value: -1}}},
+		// Untuk GET /locations/trash dan purge trash
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}, Options: options.Index().SetSparse(true)},
+		// Untuk daftar lokasi basi di GET /admin/locations/stale
+		{Keys: bson.D{{Key: "last_confirmed_at", Value: 1}, {Key: "_id", Value: 1}}},
+		// Slot promosi di GET /locations
+		{Keys: bson.D{{Key: "promotion.ends_at", Value: 1}}, Options: options.Index().SetSparse(true)},
+	})
+	if err != nil {
+		return res.ModifiedCount, err
+	}
+	// Arsip cukup diberi index untuk cek kategori yang masih dipakai
+	_, err = r.archive.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "category", Value: 1}}})
+	return res.ModifiedCount, err
+}
+
+func (r *mongoLocationRepository) HasIndex(ctx context.Context, key string, unique bool) (bool, error) {
+	return hasIndex(ctx, r.coll, key, unique)
+}
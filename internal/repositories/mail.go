@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DeadLetterRepository interface {
+	Create(ctx context.Context, d *models.DeadLetter) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.DeadLetter, error)
+	List(ctx context.Context, p ListPage) ([]models.DeadLetter, int64, error)
+	// RecordAttempt mencatat kirim ulang yang gagal lagi.
+	RecordAttempt(ctx context.Context, id primitive.ObjectID, at time.Time, errMsg string, rejected bool) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	EnsureIndexes(ctx context.Context) error
+}
+
+// DeadLetterList adalah sort dan batas GET /admin/mail/dead-letters.
+var DeadLetterList = ListSpec{
+	Sorts:        map[string]string{"last_attempt_at": "last_attempt_at"},
+	DefaultSort:  "-last_attempt_at",
+	DefaultLimit: 20,
+	MaxLimit:     100,
+}
+
+type mongoDeadLetterRepository struct {
+	coll *mongo.Collection
+}
+
+func NewDeadLetterRepository(coll *mongo.Collection) DeadLetterRepository {
+	return &mongoDeadLetterRepository{coll: coll}
+}
+
+func (r *mongoDeadLetterRepository) Create(ctx context.Context, d *models.DeadLetter) error {
+	if d.ID.IsZero() {
+		d.ID = primitive.NewObjectID()
+	}
+	_, err := r.coll.InsertOne(ctx, d)
+	return err
+}
+
+func (r *mongoDeadLetterRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.DeadLetter, error) {
+	var d models.DeadLetter
+	if err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&d); err != nil {
+		return nil, notFound(err)
+	}
+	return &d, nil
+}
+
+func (r *mongoDeadLetterRepository) List(ctx context.Context, p ListPage) ([]models.DeadLetter, int64, error) {
+	total, err := r.coll.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return nil, 0, err
+	}
+	cursor, err := r.coll.Find(ctx, p.Match(bson.M{}), p.FindOptions())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+	letters := []models.DeadLetter{}
+	if err := cursor.All(ctx, &letters); err != nil {
+		return nil, 0, err
+	}
+	return letters, total, nil
+}
+
+func (r *mongoDeadLetterRepository) RecordAttempt(ctx context.Context, id primitive.ObjectID, at time.Time, errMsg string, rejected bool) error {
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{"last_attempt_at": at, "error": errMsg, "rejected": rejected},
+		"$inc": bson.M{"attempts": 1},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoDeadLetterRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	res, err := r.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Daftar diurutkan per last_attempt_at; email lama dihapus Mongo lewat TTL
+func (r *mongoDeadLetterRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "last_attempt_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
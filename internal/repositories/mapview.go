@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"context"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MapViewQuery adalah filter + pagination untuk membaca map_view.
+type MapViewQuery struct {
+	Category string
+	// Sort dari LocationList
+	ListPage
+}
+
+// MapViewRepository mengelola read model map_view. Dokumen selalu
+// diproyeksikan dari geo_data (write model), tidak pernah ditulis langsung
+// dari input user.
+type MapViewRepository interface {
+	List(ctx context.Context, q MapViewQuery) ([]models.MapViewItem, int64, error)
+	FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.MapViewItem, error)
+	// Project menulis ulang dokumen map_view untuk ids dari geo_data,
+	// termasuk ringkasan rating. Lokasi yang sudah tidak ada, masuk trash
+	// atau belum disetujui moderator dihapus dari map_view.
+	Project(ctx context.Context, ids []primitive.ObjectID) error
+	// LocationIDsAfter mengembalikan ID lokasi publik (bukan trash, sudah
+	// disetujui) di geo_data
+	// yang lebih besar dari after, urut naik.
+	LocationIDsAfter(ctx context.Context, after primitive.ObjectID, limit int64) ([]primitive.ObjectID, error)
+	CountLocationsAfter(ctx context.Context, after primitive.ObjectID) (int64, error)
+	// Prune menghapus dokumen map_view dengan after < _id <= upTo yang tidak
+	// ada di keep. upTo NilObjectID berarti tanpa batas atas.
+	Prune(ctx context.Context, after, upTo primitive.ObjectID, keep []primitive.ObjectID) (int64, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+type mongoMapViewRepository struct {
+	coll *mongo.Collection
+	// Sumber proyeksi
+	locations *mongo.Collection
+	reviews   *mongo.Collection
+}
+
+func NewMapViewRepository(coll, locations, reviews *mongo.Collection) MapViewRepository {
+	return &mongoMapViewRepository{coll: coll, locations: locations, reviews: reviews}
+}
+
+func (r *mongoMapViewRepository) List(ctx context.Context, q MapViewQuery) ([]models.MapViewItem, int64, error) {
+	filter := bson.M{}
+	if q.Category != "" {
+		filter["category"] = q.Category
+	}
+	total, err := r.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	cursor, err := r.coll.Find(ctx, q.Match(filter), q.FindOptions())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+	items := []models.MapViewItem{}
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+func (r *mongoMapViewRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.MapViewItem, error) {
+	cursor, err := r.coll.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	items := []models.MapViewItem{}
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *mongoMapViewRepository) Project(ctx context.Context, ids []primitive.ObjectID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	live, err := r.locations.Distinct(ctx, "_id", listed(bson.M{"_id": bson.M{"$in": ids}}))
+	if err != nil {
+		return err
+	}
+	if live == nil {
+		live = bson.A{}
+	}
+	if len(live) > 0 {
+		pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{"_id": bson.M{"$in": live}}}}}
+		pipeline = append(pipeline, ratingStages(r.reviews)...)
+		pipeline = append(pipeline,
+			bson.D{{Key: "$project", Value: bson.M{"name": 1, "category": 1, "coordinates": 1, "rating": 1, "status": 1, "approximate_coordinates": 1,
+				CounterFavorites: bson.M{"$ifNull": bson.A{"$" + CounterFavorites, 0}},
+				CounterCheckIns:  bson.M{"$ifNull": bson.A{"$" + CounterCheckIns, 0}},
+			}}},
+			bson.D{{Key: "$merge", Value: bson.M{"into": r.coll.Name(), "on": "_id", "whenMatched": "replace", "whenNotMatched": "insert"}}},
+		)
+		cursor, err := r.locations.Aggregate(ctx, pipeline)
+		if err != nil {
+			return err
+		}
+		cursor.Close(ctx)
+	}
+	_, err = r.coll.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids, "$nin": live}})
+	return err
+}
+
+func (r *mongoMapViewRepository) LocationIDsAfter(ctx context.Context, after primitive.ObjectID, limit int64) ([]primitive.ObjectID, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(limit).
+		SetProjection(bson.M{"_id": 1})
+	cursor, err := r.locations.Find(ctx, listed(bson.M{"_id": bson.M{"$gt": after}}), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, len(docs))
+	for i, d := range docs {
+		ids[i] = d.ID
+	}
+	return ids, nil
+}
+
+func (r *mongoMapViewRepository) CountLocationsAfter(ctx context.Context, after primitive.ObjectID) (int64, error) {
+	return r.locations.CountDocuments(ctx, listed(bson.M{"_id": bson.M{"$gt": after}}))
+}
+
+func (r *mongoMapViewRepository) Prune(ctx context.Context, after, upTo primitive.ObjectID, keep []primitive.ObjectID) (int64, error) {
+	if keep == nil {
+		keep = []primitive.ObjectID{}
+	}
+	rng := bson.M{"$gt": after, "$nin": keep}
+	if !upTo.IsZero() {
+		rng["$lte"] = upTo
+	}
+	res, err := r.coll.DeleteMany(ctx, bson.M{"_id": rng})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+func (r *mongoMapViewRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "category", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "category", Value: 1}, {Key: "name", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}},
+		// Sort popularitas
+		{Keys: bson.D{{Key: CounterFavorites, Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "category", Value: 1}, {Key: CounterFavorites, Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: CounterCheckIns, Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "category", Value: 1}, {Key: CounterCheckIns, Value: 1}, {Key: "_id", Value: 1}}},
+	})
+	return err
+}
@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type scheduledExportRepository struct {
+	s *Store
+}
+
+func (s *Store) Exports() repositories.ScheduledExportRepository {
+	return &scheduledExportRepository{s: s}
+}
+
+// find mencari indeks jadwal; pemanggil wajib memegang s.mu.
+func (r *scheduledExportRepository) find(id primitive.ObjectID) int {
+	return slices.IndexFunc(r.s.exports, func(e models.ScheduledExport) bool { return e.ID == id })
+}
+
+func (r *scheduledExportRepository) Create(ctx context.Context, e *models.ScheduledExport) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if e.ID.IsZero() {
+		e.ID = primitive.NewObjectID()
+	}
+	r.s.exports = append(r.s.exports, clone(*e))
+	return nil
+}
+
+func (r *scheduledExportRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.ScheduledExport, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.find(id)
+	if i < 0 {
+		return nil, repositories.ErrNotFound
+	}
+	e := clone(r.s.exports[i])
+	return &e, nil
+}
+
+func (r *scheduledExportRepository) List(ctx context.Context, p repositories.ListPage) ([]models.ScheduledExport, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	exports := cloneAll(r.s.exports)
+	return listPage(exports, p), int64(len(exports)), nil
+}
+
+func (r *scheduledExportRepository) Replace(ctx context.Context, e *models.ScheduledExport) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.find(e.ID)
+	if i < 0 {
+		return repositories.ErrNotFound
+	}
+	r.s.exports[i] = clone(*e)
+	return nil
+}
+
+func (r *scheduledExportRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.find(id)
+	if i < 0 {
+		return repositories.ErrNotFound
+	}
+	r.s.exports = slices.Delete(r.s.exports, i, i+1)
+	return nil
+}
+
+func (r *scheduledExportRepository) due(now time.Time) []models.ScheduledExport {
+	exports := []models.ScheduledExport{}
+	for _, e := range r.s.exports {
+		if e.Enabled && !e.NextRunAt.After(now) {
+			exports = append(exports, e)
+		}
+	}
+	sortBy(exports, "next_run_at", false)
+	return exports
+}
+
+func (r *scheduledExportRepository) Due(ctx context.Context, now time.Time, limit int64) ([]models.ScheduledExport, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return cloneAll(page(r.due(now), 0, limit)), nil
+}
+
+func (r *scheduledExportRepository) CountDue(ctx context.Context, now time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return int64(len(r.due(now))), nil
+}
+
+func (r *scheduledExportRepository) Claim(ctx context.Context, id primitive.ObjectID, from, to time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.find(id)
+	if i < 0 || !r.s.exports[i].NextRunAt.Equal(from) {
+		return repositories.ErrNotFound
+	}
+	// Mongo menyimpan waktu dalam milidetik
+	r.s.exports[i].NextRunAt = to.UTC().Truncate(time.Millisecond)
+	return nil
+}
+
+func (r *scheduledExportRepository) SetLastRun(ctx context.Context, id primitive.ObjectID, run models.ExportRun) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if i := r.find(id); i >= 0 {
+		run := clone(run)
+		r.s.exports[i].LastRun = &run
+	}
+	return nil
+}
+
+func (r *scheduledExportRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
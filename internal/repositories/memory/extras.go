@@ -0,0 +1,814 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type reviewRepository struct {
+	s *Store
+}
+
+// Reviews mengembalikan ReviewRepository di atas Store. Satu ulasan per
+// user per lokasi seperti unique index di Mongo.
+func (s *Store) Reviews() repositories.ReviewRepository {
+	return &reviewRepository{s: s}
+}
+
+func (r *reviewRepository) Create(ctx context.Context, rv *models.Review) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if slices.ContainsFunc(r.s.reviews, func(e models.Review) bool {
+		return e.LocationID == rv.LocationID && e.UserID == rv.UserID
+	}) {
+		return repositories.ErrDuplicate
+	}
+	if rv.ID.IsZero() {
+		rv.ID = primitive.NewObjectID()
+	}
+	r.s.reviews = append(r.s.reviews, clone(*rv))
+	return nil
+}
+
+func (r *reviewRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Review, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := slices.IndexFunc(r.s.reviews, func(rv models.Review) bool { return rv.ID == id })
+	if i < 0 {
+		return nil, repositories.ErrNotFound
+	}
+	rv := clone(r.s.reviews[i])
+	return &rv, nil
+}
+
+func (r *reviewRepository) List(ctx context.Context, locationID primitive.ObjectID, p repositories.ListPage) ([]models.Review, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	reviews := []models.Review{}
+	for _, rv := range r.s.reviews {
+		if rv.LocationID == locationID && rv.Listed() {
+			reviews = append(reviews, clone(rv))
+		}
+	}
+	return listPage(reviews, p), int64(len(reviews)), nil
+}
+
+func (r *reviewRepository) Recent(ctx context.Context, since time.Time, limit int64) ([]models.Review, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	reviews := []models.Review{}
+	for _, rv := range r.s.reviews {
+		if rv.CreatedAt.After(since) {
+			reviews = append(reviews, clone(rv))
+		}
+	}
+	sortBy(reviews, "created_at", true)
+	return page(reviews, 0, limit), nil
+}
+
+func (r *reviewRepository) Pending(ctx context.Context, locationIDs []primitive.ObjectID, p repositories.ListPage) ([]models.Review, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	reviews := []models.Review{}
+	for _, rv := range r.s.reviews {
+		if rv.ModerationStatus == models.ModerationPending && (locationIDs == nil || slices.Contains(locationIDs, rv.LocationID)) {
+			reviews = append(reviews, clone(rv))
+		}
+	}
+	return listPage(reviews, p), int64(len(reviews)), nil
+}
+
+func (r *reviewRepository) Moderate(ctx context.Context, id primitive.ObjectID, from []string, set repositories.Fields) (*models.Review, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := slices.IndexFunc(r.s.reviews, func(rv models.Review) bool { return rv.ID == id })
+	if i < 0 || !slices.Contains(from, r.s.reviews[i].ModerationStatus) {
+		return nil, repositories.ErrNotFound
+	}
+	if err := apply(&r.s.reviews[i], set); err != nil {
+		return nil, err
+	}
+	rv := clone(r.s.reviews[i])
+	return &rv, nil
+}
+
+func (r *reviewRepository) Summary(ctx context.Context, locationID primitive.ObjectID) (models.RatingSummary, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.summary(locationID), nil
+}
+
+func (r *reviewRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	n := len(r.s.reviews)
+	r.s.reviews = slices.DeleteFunc(r.s.reviews, func(rv models.Review) bool { return rv.ID == id })
+	if len(r.s.reviews) == n {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+func (r *reviewRepository) DeleteByLocation(ctx context.Context, locationID primitive.ObjectID) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	n := len(r.s.reviews)
+	r.s.reviews = slices.DeleteFunc(r.s.reviews, func(rv models.Review) bool { return rv.LocationID == locationID })
+	return int64(n - len(r.s.reviews)), nil
+}
+
+func (r *reviewRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+type favoriteRepository struct {
+	s *Store
+}
+
+// Favorites mengembalikan FavoriteRepository di atas Store.
+func (s *Store) Favorites() repositories.FavoriteRepository {
+	return &favoriteRepository{s: s}
+}
+
+func (r *favoriteRepository) Add(ctx context.Context, f *models.Favorite) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if slices.ContainsFunc(r.s.favorites, func(e models.Favorite) bool {
+		return e.UserID == f.UserID && e.LocationID == f.LocationID
+	}) {
+		return repositories.ErrDuplicate
+	}
+	if f.ID.IsZero() {
+		f.ID = primitive.NewObjectID()
+	}
+	r.s.favorites = append(r.s.favorites, clone(*f))
+	return nil
+}
+
+func (r *favoriteRepository) Remove(ctx context.Context, userID, locationID primitive.ObjectID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	n := len(r.s.favorites)
+	r.s.favorites = slices.DeleteFunc(r.s.favorites, func(f models.Favorite) bool {
+		return f.UserID == userID && f.LocationID == locationID
+	})
+	if len(r.s.favorites) == n {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+func (r *favoriteRepository) Locations(ctx context.Context, userID primitive.ObjectID, p repositories.ListPage) ([]models.Location, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	favorites := []models.Favorite{}
+	for _, f := range r.s.favorites {
+		if f.UserID == userID {
+			favorites = append(favorites, f)
+		}
+	}
+	// Urutan favorit, bukan lokasi; FavoriteList tanpa cursor
+	sortBy(favorites, p.Field, p.Desc)
+	locs := &locationRepository{s: r.s}
+	locations := []models.Location{}
+	for _, f := range favorites {
+		if i := locs.index(f.LocationID, false); i >= 0 {
+			locations = append(locations, r.s.locations[i])
+		}
+	}
+	total := int64(len(locations))
+	locations = page(locations, p.Skip, p.Limit)
+	for i := range locations {
+		locations[i] = locs.withDerived(locations[i])
+	}
+	return locations, total, nil
+}
+
+func (r *favoriteRepository) CountByLocation(ctx context.Context, locationID primitive.ObjectID) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var n int64
+	for _, f := range r.s.favorites {
+		if f.LocationID == locationID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *favoriteRepository) DeleteByLocation(ctx context.Context, locationID primitive.ObjectID) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	n := len(r.s.favorites)
+	r.s.favorites = slices.DeleteFunc(r.s.favorites, func(f models.Favorite) bool { return f.LocationID == locationID })
+	return int64(n - len(r.s.favorites)), nil
+}
+
+func (r *favoriteRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+type confirmationRepository struct {
+	s *Store
+}
+
+// Confirmations mengembalikan ConfirmationRepository di atas Store.
+// Konfirmasi yang kedaluwarsa diabaikan seperti setelah TTL index Mongo
+// menghapusnya.
+func (s *Store) Confirmations() repositories.ConfirmationRepository {
+	return &confirmationRepository{s: s}
+}
+
+func (r *confirmationRepository) Create(ctx context.Context, c *models.Confirmation) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if slices.ContainsFunc(r.s.confirmations, func(e models.Confirmation) bool {
+		return e.LocationID == c.LocationID && e.UserID == c.UserID && e.ExpiresAt.After(c.CreatedAt)
+	}) {
+		return repositories.ErrDuplicate
+	}
+	if c.ID.IsZero() {
+		c.ID = primitive.NewObjectID()
+	}
+	r.s.confirmations = append(r.s.confirmations, clone(*c))
+	return nil
+}
+
+func (r *confirmationRepository) DeleteByLocation(ctx context.Context, locationID primitive.ObjectID) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	n := len(r.s.confirmations)
+	r.s.confirmations = slices.DeleteFunc(r.s.confirmations, func(c models.Confirmation) bool { return c.LocationID == locationID })
+	return int64(n - len(r.s.confirmations)), nil
+}
+
+func (r *confirmationRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+type checkInRepository struct {
+	s *Store
+}
+
+// CheckIns mengembalikan CheckInRepository di atas Store. Satu check-in per
+// user per lokasi seperti unique index di Mongo.
+func (s *Store) CheckIns() repositories.CheckInRepository {
+	return &checkInRepository{s: s}
+}
+
+func (r *checkInRepository) Create(ctx context.Context, c *models.CheckIn) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if slices.ContainsFunc(r.s.checkins, func(e models.CheckIn) bool {
+		return e.LocationID == c.LocationID && e.UserID == c.UserID
+	}) {
+		return repositories.ErrDuplicate
+	}
+	if c.ID.IsZero() {
+		c.ID = primitive.NewObjectID()
+	}
+	r.s.checkins = append(r.s.checkins, clone(*c))
+	return nil
+}
+
+// Tanggal dikelompokkan dengan zona yang sama dengan AttendanceTimezone
+func (r *checkInRepository) Attendance(ctx context.Context, locationID primitive.ObjectID) (models.Attendance, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	wib := time.FixedZone("WIB", 7*60*60)
+	attendance := models.Attendance{LocationID: locationID, ByDay: []models.DayCount{}}
+	days := map[string]int64{}
+	for _, c := range r.s.checkins {
+		if c.LocationID != locationID {
+			continue
+		}
+		attendance.Total++
+		if attendance.LastCheckInAt == nil || c.CreatedAt.After(*attendance.LastCheckInAt) {
+			at := c.CreatedAt
+			attendance.LastCheckInAt = &at
+		}
+		days[c.CreatedAt.In(wib).Format("2006-01-02")]++
+	}
+	for date, n := range days {
+		attendance.ByDay = append(attendance.ByDay, models.DayCount{Date: date, Count: n})
+	}
+	sortBy(attendance.ByDay, "_id", false)
+	return attendance, nil
+}
+
+func (r *checkInRepository) DeleteByLocation(ctx context.Context, locationID primitive.ObjectID) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	n := len(r.s.checkins)
+	r.s.checkins = slices.DeleteFunc(r.s.checkins, func(c models.CheckIn) bool { return c.LocationID == locationID })
+	return int64(n - len(r.s.checkins)), nil
+}
+
+func (r *checkInRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// impression adalah dokumen promotion_impressions
+type impression struct {
+	LocationID primitive.ObjectID `bson:"location_id"`
+	Date       string             `bson:"date"`
+	Count      int64              `bson:"count"`
+}
+
+type promotionRepository struct {
+	s *Store
+}
+
+// Promotions mengembalikan PromotionRepository di atas Store.
+func (s *Store) Promotions() repositories.PromotionRepository {
+	return &promotionRepository{s: s}
+}
+
+func (r *promotionRepository) AddImpressions(ctx context.Context, date string, ids []primitive.ObjectID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, id := range ids {
+		i := slices.IndexFunc(r.s.impressions, func(e impression) bool { return e.LocationID == id && e.Date == date })
+		if i < 0 {
+			r.s.impressions = append(r.s.impressions, impression{LocationID: id, Date: date})
+			i = len(r.s.impressions) - 1
+		}
+		r.s.impressions[i].Count++
+	}
+	return nil
+}
+
+func (r *promotionRepository) Impressions(ctx context.Context, locationID primitive.ObjectID, from, to string) ([]models.DayCount, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	days := []models.DayCount{}
+	for _, e := range r.s.impressions {
+		if e.LocationID != locationID || (from != "" && e.Date < from) || (to != "" && e.Date > to) {
+			continue
+		}
+		days = append(days, models.DayCount{Date: e.Date, Count: e.Count})
+	}
+	sortBy(days, "_id", false)
+	return days, nil
+}
+
+func (r *promotionRepository) DeleteByLocation(ctx context.Context, locationID primitive.ObjectID) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	n := len(r.s.impressions)
+	r.s.impressions = slices.DeleteFunc(r.s.impressions, func(e impression) bool { return e.LocationID == locationID })
+	return int64(n - len(r.s.impressions)), nil
+}
+
+func (r *promotionRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+type mapViewRepository struct {
+	repositories.MapViewRepository
+	s *Store
+}
+
+// MapView mengembalikan MapViewRepository di atas Store. Read model
+// dihitung langsung dari lokasi saat dibaca, sehingga selalu sinkron dan
+// Project tidak perlu melakukan apa pun.
+func (s *Store) MapView() repositories.MapViewRepository {
+	return &mapViewRepository{s: s}
+}
+
+func (r *mapViewRepository) items(keep func(*models.Location) bool) []models.MapViewItem {
+	items := []models.MapViewItem{}
+	for i := range r.s.locations {
+		loc := &r.s.locations[i]
+		if loc.DeletedAt != nil || !loc.Listed() || !keep(loc) {
+			continue
+		}
+		items = append(items, models.MapViewItem{
+			ID:                     loc.ID,
+			Name:                   loc.Name,
+			Category:               loc.Category,
+			Coordinates:            loc.Coordinates,
+			Rating:                 r.s.summary(loc.ID),
+			Status:                 loc.Status,
+			FavoritesCount:         loc.FavoritesCount,
+			CheckInsCount:          loc.CheckInsCount,
+			ApproximateCoordinates: loc.ApproximateCoordinates,
+		})
+	}
+	return items
+}
+
+func (r *mapViewRepository) List(ctx context.Context, q repositories.MapViewQuery) ([]models.MapViewItem, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	items := r.items(func(loc *models.Location) bool { return q.Category == "" || loc.Category == q.Category })
+	return listPage(items, q.ListPage), int64(len(items)), nil
+}
+
+func (r *mapViewRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.MapViewItem, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.items(func(loc *models.Location) bool { return slices.Contains(ids, loc.ID) }), nil
+}
+
+func (r *mapViewRepository) Project(ctx context.Context, ids []primitive.ObjectID) error {
+	return nil
+}
+
+func (r *mapViewRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+type regionRepository struct {
+	repositories.RegionRepository
+	s *Store
+}
+
+// Regions mengembalikan RegionRepository tanpa geometry: wilayah bisa
+// disimpan dan dibaca per kode, tetapi lokasi baru tidak mendapat
+// admin_area.
+func (s *Store) Regions() repositories.RegionRepository {
+	return regionRepository{s: s}
+}
+
+func (r regionRepository) Save(ctx context.Context, region models.Region) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	region.Geometry = nil
+	if i := slices.IndexFunc(r.s.regions, func(g models.Region) bool { return g.Code == region.Code }); i >= 0 {
+		r.s.regions[i] = clone(region)
+		return nil
+	}
+	r.s.regions = append(r.s.regions, clone(region))
+	return nil
+}
+
+func (r regionRepository) Get(ctx context.Context, code string) (*models.Region, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := slices.IndexFunc(r.s.regions, func(g models.Region) bool { return g.Code == code })
+	if i < 0 {
+		return nil, repositories.ErrNotFound
+	}
+	region := clone(r.s.regions[i])
+	return &region, nil
+}
+
+func (regionRepository) Containing(ctx context.Context, c models.Coordinates) ([]models.Region, error) {
+	return nil, nil
+}
+
+func (regionRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+type postcodeRepository struct {
+	repositories.PostcodeRepository
+}
+
+// Postcodes mengembalikan PostcodeRepository dengan dataset kosong.
+func (s *Store) Postcodes() repositories.PostcodeRepository {
+	return postcodeRepository{}
+}
+
+func (postcodeRepository) Get(ctx context.Context, code string) (*models.Postcode, error) {
+	return nil, repositories.ErrNotFound
+}
+
+func (postcodeRepository) ByDistrict(ctx context.Context, districtKey, regencyKey string) ([]models.Postcode, error) {
+	return nil, nil
+}
+
+func (postcodeRepository) Loaded(ctx context.Context) (bool, error) {
+	return false, nil
+}
+
+func (postcodeRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+type transitRepository struct {
+	repositories.TransitRepository
+	s *Store
+}
+
+// Transit mengembalikan TransitRepository yang menyimpan halte tetapi
+// Nearby-nya selalu kosong (tanpa query geo).
+func (s *Store) Transit() repositories.TransitRepository {
+	return transitRepository{s: s}
+}
+
+func (r transitRepository) ReplaceFeed(ctx context.Context, feed string, stops []models.TransitStop) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.transitStops = slices.DeleteFunc(r.s.transitStops, func(st models.TransitStop) bool { return st.Feed == feed })
+	for _, st := range stops {
+		r.s.transitStops = append(r.s.transitStops, clone(st))
+	}
+	return nil
+}
+
+func (transitRepository) Nearby(ctx context.Context, q repositories.TransitNearbyQuery) ([]models.NearbyTransitStop, error) {
+	return []models.NearbyTransitStop{}, nil
+}
+
+func (transitRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+type campaignRepository struct {
+	repositories.CampaignRepository
+}
+
+// Campaigns mengembalikan CampaignRepository yang hanya mendukung
+// PendingRecipients dan CountPending (selalu kosong), cukup untuk worker
+// dan health check.
+func (s *Store) Campaigns() repositories.CampaignRepository {
+	return campaignRepository{}
+}
+
+func (campaignRepository) PendingRecipients(ctx context.Context, limit int64) ([]models.CampaignRecipient, error) {
+	return nil, nil
+}
+
+func (campaignRepository) CountPending(ctx context.Context, campaignID primitive.ObjectID) (int64, error) {
+	return 0, nil
+}
+
+func (campaignRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+type usageRepository struct {
+	s *Store
+}
+
+// Usage mengembalikan UsageRepository di atas Store.
+func (s *Store) Usage() repositories.UsageRepository {
+	return &usageRepository{s: s}
+}
+
+func (r *usageRepository) Add(ctx context.Context, counters []models.UsageCounter) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, c := range counters {
+		i := slices.IndexFunc(r.s.usage, func(u models.UsageCounter) bool {
+			return u.Date == c.Date && u.Consumer == c.Consumer && u.Method == c.Method && u.Route == c.Route
+		})
+		if i < 0 {
+			r.s.usage = append(r.s.usage, models.UsageCounter{Date: c.Date, Consumer: c.Consumer, ConsumerType: c.ConsumerType,
+				Method: c.Method, Route: c.Route})
+			i = len(r.s.usage) - 1
+		}
+		u := &r.s.usage[i]
+		u.Requests += c.Requests
+		u.ClientErrors += c.ClientErrors
+		u.ServerErrors += c.ServerErrors
+		u.BytesIn += c.BytesIn
+		u.BytesOut += c.BytesOut
+	}
+	return nil
+}
+
+func (r *usageRepository) Totals(ctx context.Context, from, to, consumer string) ([]models.UsageCounter, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	totals := []models.UsageCounter{}
+	for _, u := range r.s.usage {
+		if u.Date < from || u.Date > to || (consumer != "" && u.Consumer != consumer) {
+			continue
+		}
+		i := slices.IndexFunc(totals, func(t models.UsageCounter) bool {
+			return t.Consumer == u.Consumer && t.Method == u.Method && t.Route == u.Route
+		})
+		if i < 0 {
+			u.Date = ""
+			totals = append(totals, u)
+			continue
+		}
+		t := &totals[i]
+		t.Requests += u.Requests
+		t.ClientErrors += u.ClientErrors
+		t.ServerErrors += u.ServerErrors
+		t.BytesIn += u.BytesIn
+		t.BytesOut += u.BytesOut
+	}
+	return totals, nil
+}
+
+func (r *usageRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+type eventRepository struct {
+	s *Store
+}
+
+// Events mengembalikan EventRepository di atas Store.
+func (s *Store) Events() repositories.EventRepository {
+	return &eventRepository{s: s}
+}
+
+func (r *eventRepository) Insert(ctx context.Context, ev *models.Event) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if slices.ContainsFunc(r.s.events, func(e models.Event) bool { return e.Seq == ev.Seq }) {
+		return repositories.ErrDuplicate
+	}
+	r.s.events = append(r.s.events, clone(*ev))
+	return nil
+}
+
+func (r *eventRepository) Last(ctx context.Context) (*models.Event, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if len(r.s.events) == 0 {
+		return nil, repositories.ErrNotFound
+	}
+	last := slices.MaxFunc(r.s.events, func(a, b models.Event) int { return cmp.Compare(a.Seq, b.Seq) })
+	return &last, nil
+}
+
+func (r *eventRepository) After(ctx context.Context, afterSeq, limit int64) ([]models.Event, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	events := []models.Event{}
+	for _, e := range r.s.events {
+		if e.Seq > afterSeq {
+			events = append(events, clone(e))
+		}
+	}
+	slices.SortFunc(events, func(a, b models.Event) int { return cmp.Compare(a.Seq, b.Seq) })
+	if int64(len(events)) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+func (r *eventRepository) Offset(ctx context.Context, consumer string) (*models.EventOffset, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := slices.IndexFunc(r.s.eventOffsets, func(o models.EventOffset) bool { return o.Consumer == consumer })
+	if i < 0 {
+		return nil, repositories.ErrNotFound
+	}
+	o := r.s.eventOffsets[i]
+	return &o, nil
+}
+
+func (r *eventRepository) SetOffset(ctx context.Context, offset models.EventOffset) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if i := slices.IndexFunc(r.s.eventOffsets, func(o models.EventOffset) bool { return o.Consumer == offset.Consumer }); i >= 0 {
+		r.s.eventOffsets[i] = offset
+		return nil
+	}
+	r.s.eventOffsets = append(r.s.eventOffsets, offset)
+	return nil
+}
+
+func (r *eventRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+type sessionRepository struct {
+	s *Store
+}
+
+// Sessions mengembalikan SessionRepository di atas Store.
+func (s *Store) Sessions() repositories.SessionRepository {
+	return &sessionRepository{s: s}
+}
+
+func (r *sessionRepository) Create(ctx context.Context, sess *models.Session) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if sess.ID.IsZero() {
+		sess.ID = primitive.NewObjectID()
+	}
+	r.s.sessions = append(r.s.sessions, clone(*sess))
+	return nil
+}
+
+func (r *sessionRepository) Seen(ctx context.Context, userID primitive.ObjectID, device, location string) (repositories.SessionsSeen, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var seen repositories.SessionsSeen
+	for _, sess := range r.s.sessions {
+		if sess.UserID != userID || sess.RevokedReason == models.SessionReported {
+			continue
+		}
+		seen.Any = true
+		seen.Device = seen.Device || sess.Device == device
+		seen.Location = seen.Location || sess.Location == location
+	}
+	return seen, nil
+}
+
+func (r *sessionRepository) ListByUser(ctx context.Context, userID primitive.ObjectID, limit int64) ([]models.Session, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	sessions := []models.Session{}
+	for _, sess := range r.s.sessions {
+		if sess.UserID == userID {
+			sessions = append(sessions, clone(sess))
+		}
+	}
+	slices.SortStableFunc(sessions, func(a, b models.Session) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	if int64(len(sessions)) > limit {
+		sessions = sessions[:limit]
+	}
+	return sessions, nil
+}
+
+func (r *sessionRepository) ConsumeReport(ctx context.Context, tokenHash string, now time.Time) (*models.Session, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for i := range r.s.sessions {
+		sess := &r.s.sessions[i]
+		if sess.ReportTokenHash == tokenHash && sess.ReportExpiresAt != nil && sess.ReportExpiresAt.After(now) {
+			revoked := now
+			sess.RevokedAt, sess.RevokedReason = &revoked, models.SessionReported
+			sess.ReportTokenHash, sess.ReportExpiresAt = "", nil
+			out := clone(*sess)
+			return &out, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *sessionRepository) RevokeUser(ctx context.Context, userID primitive.ObjectID, at time.Time, reason string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for i := range r.s.sessions {
+		sess := &r.s.sessions[i]
+		if sess.UserID == userID && sess.RevokedAt == nil {
+			revoked := at
+			sess.RevokedAt, sess.RevokedReason = &revoked, reason
+		}
+	}
+	return nil
+}
+
+func (r *sessionRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+type redirectRepository struct {
+	s *Store
+}
+
+// Redirects mengembalikan RedirectRepository di atas Store.
+func (s *Store) Redirects() repositories.RedirectRepository {
+	return &redirectRepository{s: s}
+}
+
+func (r *redirectRepository) Find(ctx context.Context, from primitive.ObjectID) (*models.LocationRedirect, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := slices.IndexFunc(r.s.redirects, func(red models.LocationRedirect) bool { return red.From == from })
+	if i < 0 {
+		return nil, repositories.ErrNotFound
+	}
+	out := r.s.redirects[i]
+	return &out, nil
+}
+
+func (r *redirectRepository) Put(ctx context.Context, red *models.LocationRedirect) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.redirects = slices.DeleteFunc(r.s.redirects, func(e models.LocationRedirect) bool { return e.From == red.From })
+	r.s.redirects = append(r.s.redirects, *red)
+	return nil
+}
+
+func (r *redirectRepository) Retarget(ctx context.Context, from, to primitive.ObjectID) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var n int64
+	for i := range r.s.redirects {
+		if r.s.redirects[i].To == from {
+			r.s.redirects[i].To = to
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *redirectRepository) Delete(ctx context.Context, from primitive.ObjectID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.redirects = slices.DeleteFunc(r.s.redirects, func(e models.LocationRedirect) bool { return e.From == from })
+	return nil
+}
+
+func (r *redirectRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
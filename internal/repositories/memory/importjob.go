@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type importJobRepository struct {
+	s *Store
+}
+
+func (s *Store) ImportJobs() repositories.ImportJobRepository {
+	return &importJobRepository{s: s}
+}
+
+// find mencari indeks job; pemanggil wajib memegang s.mu.
+func (r *importJobRepository) find(id primitive.ObjectID) int {
+	return slices.IndexFunc(r.s.importJobs, func(j models.ImportJob) bool { return j.ID == id })
+}
+
+func (r *importJobRepository) Create(ctx context.Context, job *models.ImportJob) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if job.ID.IsZero() {
+		job.ID = primitive.NewObjectID()
+	}
+	r.s.importJobs = append(r.s.importJobs, clone(*job))
+	return nil
+}
+
+func (r *importJobRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.ImportJob, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.find(id)
+	if i < 0 {
+		return nil, repositories.ErrNotFound
+	}
+	job := clone(r.s.importJobs[i])
+	return &job, nil
+}
+
+func (r *importJobRepository) queued() []models.ImportJob {
+	jobs := []models.ImportJob{}
+	for _, j := range r.s.importJobs {
+		if j.Status == models.ImportJobQueued {
+			jobs = append(jobs, j)
+		}
+	}
+	sortBy(jobs, "_id", false)
+	return jobs
+}
+
+func (r *importJobRepository) NextQueued(ctx context.Context) (*models.ImportJob, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	jobs := r.queued()
+	if len(jobs) == 0 {
+		return nil, repositories.ErrNotFound
+	}
+	job := clone(jobs[0])
+	return &job, nil
+}
+
+func (r *importJobRepository) CountQueued(ctx context.Context) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return int64(len(r.queued())), nil
+}
+
+func (r *importJobRepository) Advance(ctx context.Context, id primitive.ObjectID, from int, rows []models.ImportJobRow) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.find(id)
+	if i < 0 || r.s.importJobs[i].Cursor != from {
+		return repositories.ErrNotFound
+	}
+	job := &r.s.importJobs[i]
+	for n, row := range rows {
+		job.Rows[from+n] = clone(row)
+		countImportRow(job, row.Status, 1)
+	}
+	job.Cursor = from + len(rows)
+	return nil
+}
+
+func (r *importJobRepository) Place(ctx context.Context, id primitive.ObjectID, index int, row models.ImportJobRow) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.find(id)
+	if i < 0 || index >= len(r.s.importJobs[i].Rows) || r.s.importJobs[i].Rows[index].Status != models.ImportRowUnresolved {
+		return repositories.ErrNotFound
+	}
+	job := &r.s.importJobs[i]
+	job.Rows[index] = clone(row)
+	countImportRow(job, models.ImportRowUnresolved, -1)
+	countImportRow(job, row.Status, 1)
+	return nil
+}
+
+// countImportRow menambah counter job untuk status baris.
+func countImportRow(job *models.ImportJob, status string, n int) {
+	switch status {
+	case models.ImportRowGeocoded:
+		job.Geocoded += n
+	case models.ImportRowUnresolved:
+		job.Unresolved += n
+	case models.ImportRowPlaced:
+		job.Placed += n
+	case models.ImportRowFailed:
+		job.Failed += n
+	}
+}
+
+func (r *importJobRepository) Complete(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.find(id)
+	if i < 0 || r.s.importJobs[i].Status != models.ImportJobQueued {
+		return repositories.ErrNotFound
+	}
+	r.s.importJobs[i].Status = models.ImportJobDone
+	r.s.importJobs[i].CompletedAt = &at
+	return nil
+}
+
+func (r *importJobRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,702 @@
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type locationRepository struct {
+	repositories.LocationRepository
+	s *Store
+}
+
+// Locations mengembalikan LocationRepository di atas Store. Agregasi
+// wilayah tidak diimplementasikan; Nearby menghitung jarak satu per satu.
+func (s *Store) Locations() repositories.LocationRepository {
+	return &locationRepository{s: s}
+}
+
+// match menerjemahkan LocationQuery.filter() untuk field yang didukung
+func match(q repositories.LocationQuery, loc *models.Location) bool {
+	if q.Category != "" && loc.Category != q.Category {
+		return false
+	}
+	if q.CreatedBy != "" && loc.CreatedBy != q.CreatedBy {
+		return false
+	}
+	if q.Text != "" {
+		text := strings.ToLower(q.Text)
+		if !strings.Contains(strings.ToLower(loc.Name), text) && !strings.Contains(strings.ToLower(loc.Address), text) {
+			return false
+		}
+	}
+	if !q.PromotedAt.IsZero() && !loc.Promotion.ActiveAt(q.PromotedAt) {
+		return false
+	}
+	if q.Scope != nil && !q.Scope.Contains(loc) {
+		return false
+	}
+	if q.Trashed {
+		return loc.DeletedAt != nil
+	}
+	if q.Moderation != "" {
+		return loc.ModerationStatus == q.Moderation && loc.DeletedAt == nil
+	}
+	return loc.Listed() && loc.DeletedAt == nil
+}
+
+func (r *locationRepository) index(id primitive.ObjectID, deleted bool) int {
+	return slices.IndexFunc(r.s.locations, func(loc models.Location) bool {
+		return loc.ID == id && (loc.DeletedAt != nil) == deleted
+	})
+}
+
+// withDerived mengisi rating seperti derivedStages
+func (r *locationRepository) withDerived(loc models.Location) models.Location {
+	loc = clone(loc)
+	rating := r.s.summary(loc.ID)
+	loc.Rating = &rating
+	return loc
+}
+
+func (r *locationRepository) List(ctx context.Context, q repositories.LocationQuery) ([]models.Location, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	locations := []models.Location{}
+	for i := range r.s.locations {
+		if match(q, &r.s.locations[i]) {
+			locations = append(locations, r.s.locations[i])
+		}
+	}
+	total := int64(len(locations))
+	locations = listPage(locations, q.ListPage)
+	for i := range locations {
+		locations[i] = r.withDerived(locations[i])
+	}
+	return locations, total, nil
+}
+
+func (r *locationRepository) Each(ctx context.Context, q repositories.LocationQuery, fn func(models.Location) error) error {
+	q.Skip, q.Limit, q.After = 0, 0, nil
+	locations, _, err := r.List(ctx, q)
+	if err != nil {
+		return err
+	}
+	for _, loc := range locations {
+		if err := fn(loc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Nearby sama seperti $geoNear tanpa filter atribut.
+func (r *locationRepository) Nearby(ctx context.Context, q repositories.NearbyQuery) ([]models.NearbyLocation, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	center := models.Coordinates{Lat: q.Lat, Lng: q.Lng}
+	results := []models.NearbyLocation{}
+	for i := range r.s.locations {
+		loc := &r.s.locations[i]
+		if !match(repositories.LocationQuery{Category: q.Category}, loc) {
+			continue
+		}
+		if d := center.DistanceM(loc.Coordinates); d <= q.RadiusM {
+			results = append(results, models.NearbyLocation{Location: r.withDerived(*loc), DistanceM: d})
+		}
+	}
+	slices.SortFunc(results, func(a, b models.NearbyLocation) int {
+		c := cmp.Compare(a.DistanceM, b.DistanceM)
+		if q.SortField == "name" {
+			c = cmp.Compare(a.Name, b.Name)
+		}
+		if q.SortDesc {
+			c = -c
+		}
+		return c
+	})
+	if q.Limit > 0 && len(results) > q.Limit {
+		results = results[:q.Limit]
+	}
+	return results, nil
+}
+
+// Facets sama seperti $facet di repository Mongo: nilai kosong diabaikan,
+// urut count menurun lalu nilai.
+func (r *locationRepository) Facets(ctx context.Context, q repositories.LocationQuery) (models.LocationFacets, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	category, priceRange, payment := map[string]int64{}, map[string]int64{}, map[string]int64{}
+	var total int64
+	for i := range r.s.locations {
+		loc := &r.s.locations[i]
+		if !match(q, loc) {
+			continue
+		}
+		total++
+		category[loc.Category]++
+		priceRange[loc.PriceRange]++
+		for _, m := range loc.PaymentMethods {
+			payment[m]++
+		}
+	}
+	return models.LocationFacets{
+		Total:          total,
+		Category:       facetCounts(category),
+		PriceRange:     facetCounts(priceRange),
+		PaymentMethods: facetCounts(payment),
+	}, nil
+}
+
+func facetCounts(counts map[string]int64) []models.FacetCount {
+	out := []models.FacetCount{}
+	for value, n := range counts {
+		if value != "" {
+			out = append(out, models.FacetCount{Value: value, Count: n})
+		}
+	}
+	slices.SortFunc(out, func(a, b models.FacetCount) int {
+		if a.Count != b.Count {
+			return cmp.Compare(b.Count, a.Count)
+		}
+		return strings.Compare(a.Value, b.Value)
+	})
+	return out
+}
+
+func (r *locationRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Location, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.index(id, false)
+	if i < 0 {
+		return nil, repositories.ErrNotFound
+	}
+	loc := clone(r.s.locations[i])
+	return &loc, nil
+}
+
+func (r *locationRepository) FindWithRating(ctx context.Context, id primitive.ObjectID) (*models.Location, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.index(id, false)
+	if i < 0 {
+		return nil, repositories.ErrNotFound
+	}
+	loc := r.withDerived(r.s.locations[i])
+	return &loc, nil
+}
+
+func (r *locationRepository) FindArchivedWithRating(ctx context.Context, id primitive.ObjectID) (*models.Location, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := slices.IndexFunc(r.s.archive, func(loc models.Location) bool { return loc.ID == id })
+	if i < 0 {
+		return nil, repositories.ErrNotFound
+	}
+	loc := r.withDerived(r.s.archive[i])
+	return &loc, nil
+}
+
+func (r *locationRepository) Archive(ctx context.Context, locs []models.Location, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, loc := range locs {
+		if i := r.index(loc.ID, false); i >= 0 {
+			archived := clone(r.s.locations[i])
+			archived.ArchivedAt = &at
+			r.s.archive = append(r.s.archive, archived)
+			r.s.locations = slices.Delete(r.s.locations, i, i+1)
+		}
+	}
+	return nil
+}
+
+func (r *locationRepository) Unarchive(ctx context.Context, id primitive.ObjectID, at time.Time) (*models.Location, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := slices.IndexFunc(r.s.archive, func(loc models.Location) bool { return loc.ID == id })
+	if i < 0 {
+		return nil, repositories.ErrNotFound
+	}
+	loc := r.s.archive[i]
+	loc.ArchivedAt = nil
+	loc.UpdatedAt = &at
+	r.s.archive = slices.Delete(r.s.archive, i, i+1)
+	r.s.locations = append(r.s.locations, loc)
+	out := clone(loc)
+	return &out, nil
+}
+
+// Stale sama dengan repository Mongo: tanpa konfirmasi, umur dihitung dari _id
+func (r *locationRepository) Stale(ctx context.Context, before time.Time, scope *models.RoleScope, p repositories.ListPage) ([]models.Location, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	locations := []models.Location{}
+	for _, loc := range r.s.locations {
+		confirmed := loc.ID.Timestamp()
+		if loc.LastConfirmedAt != nil {
+			confirmed = *loc.LastConfirmedAt
+		}
+		if loc.DeletedAt == nil && confirmed.Before(before) && scope.Contains(&loc) {
+			locations = append(locations, clone(loc))
+		}
+	}
+	total := int64(len(locations))
+	return listPage(locations, p), total, nil
+}
+
+func (r *locationRepository) Create(ctx context.Context, loc *models.Location) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if loc.ID.IsZero() {
+		loc.ID = primitive.NewObjectID()
+	} else if slices.ContainsFunc(r.s.locations, func(l models.Location) bool { return l.ID == loc.ID }) {
+		return repositories.ErrDuplicate
+	}
+	loc.SchemaVersion = models.LocationSchema.Current()
+	r.s.locations = append(r.s.locations, clone(*loc))
+	return nil
+}
+
+func (r *locationRepository) CreateMany(ctx context.Context, locs []models.Location) error {
+	for i := range locs {
+		if err := r.Create(ctx, &locs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *locationRepository) Update(ctx context.Context, id primitive.ObjectID, set repositories.Fields) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := slices.IndexFunc(r.s.locations, func(loc models.Location) bool { return loc.ID == id })
+	if i < 0 {
+		return nil
+	}
+	return apply(&r.s.locations[i], set)
+}
+
+func (r *locationRepository) SoftDelete(ctx context.Context, id primitive.ObjectID, by string, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.index(id, false)
+	if i < 0 {
+		return repositories.ErrNotFound
+	}
+	return apply(&r.s.locations[i], repositories.Fields{"deleted_at": at, "deleted_by": by})
+}
+
+func (r *locationRepository) FindDeleted(ctx context.Context, id primitive.ObjectID) (*models.Location, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.index(id, true)
+	if i < 0 {
+		return nil, repositories.ErrNotFound
+	}
+	loc := clone(r.s.locations[i])
+	return &loc, nil
+}
+
+func (r *locationRepository) Restore(ctx context.Context, id primitive.ObjectID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.index(id, true)
+	if i < 0 {
+		return repositories.ErrNotFound
+	}
+	return unset(&r.s.locations[i], "deleted_at", "deleted_by")
+}
+
+func (r *locationRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.locations = slices.DeleteFunc(r.s.locations, func(loc models.Location) bool { return loc.ID == id })
+	return nil
+}
+
+func (r *locationRepository) Moderate(ctx context.Context, id primitive.ObjectID, from []string, set repositories.Fields) (*models.Location, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.index(id, false)
+	if i < 0 {
+		return nil, repositories.ErrNotFound
+	}
+	status := r.s.locations[i].ModerationStatus
+	// Dokumen lama tanpa moderation_status dicocokkan sebagai approved
+	if status == "" {
+		status = models.ModerationApproved
+	}
+	if !slices.Contains(from, status) {
+		return nil, repositories.ErrNotFound
+	}
+	if err := apply(&r.s.locations[i], set); err != nil {
+		return nil, err
+	}
+	loc := clone(r.s.locations[i])
+	return &loc, nil
+}
+
+func (r *locationRepository) AddPhoto(ctx context.Context, id primitive.ObjectID, photo models.Photo, max int) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.index(id, false)
+	if i < 0 || len(r.s.locations[i].Photos) >= max {
+		return false, nil
+	}
+	r.s.locations[i].Photos = append(r.s.locations[i].Photos, clone(photo))
+	return true, nil
+}
+
+func (r *locationRepository) RemovePhoto(ctx context.Context, id, photoID primitive.ObjectID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := slices.IndexFunc(r.s.locations, func(loc models.Location) bool { return loc.ID == id })
+	if i < 0 {
+		return repositories.ErrNotFound
+	}
+	photos := r.s.locations[i].Photos
+	j := slices.IndexFunc(photos, func(p models.Photo) bool { return p.ID == photoID })
+	if j < 0 {
+		return repositories.ErrNotFound
+	}
+	r.s.locations[i].Photos = slices.Delete(photos, j, j+1)
+	return nil
+}
+
+func (r *locationRepository) IncrementCounter(ctx context.Context, id primitive.ObjectID, counter string, delta int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := slices.IndexFunc(r.s.locations, func(loc models.Location) bool { return loc.ID == id })
+	if i < 0 {
+		return nil
+	}
+	switch counter {
+	case repositories.CounterFavorites:
+		r.s.locations[i].FavoritesCount += delta
+	case repositories.CounterCheckIns:
+		r.s.locations[i].CheckInsCount += delta
+	}
+	return nil
+}
+
+func (r *locationRepository) Counters(ctx context.Context, after primitive.ObjectID, limit int64) ([]models.LocationCounters, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	counters := []models.LocationCounters{}
+	for _, loc := range r.s.locations {
+		if bytes.Compare(loc.ID[:], after[:]) > 0 {
+			counters = append(counters, models.LocationCounters{ID: loc.ID, FavoritesCount: loc.FavoritesCount, CheckInsCount: loc.CheckInsCount})
+		}
+	}
+	sortBy(counters, "_id", false)
+	counters = page(counters, 0, limit)
+	for i := range counters {
+		for _, f := range r.s.favorites {
+			if f.LocationID == counters[i].ID {
+				counters[i].Favorites++
+			}
+		}
+		for _, c := range r.s.checkins {
+			if c.LocationID == counters[i].ID {
+				counters[i].CheckIns++
+			}
+		}
+	}
+	return counters, nil
+}
+
+// Arsip tidak disimpan di memori, jadi cukup dicek ke s.locations
+func (r *locationRepository) DanglingReferences(ctx context.Context, limit int64) ([]models.DanglingReference, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	exists := func(id primitive.ObjectID) bool {
+		return slices.ContainsFunc(r.s.locations, func(loc models.Location) bool { return loc.ID == id })
+	}
+	refs := []models.DanglingReference{}
+	group := func(collection string, ids []primitive.ObjectID) {
+		var dangling []models.DanglingReference
+		for _, id := range ids {
+			if exists(id) {
+				continue
+			}
+			if i := slices.IndexFunc(dangling, func(d models.DanglingReference) bool { return d.LocationID == id }); i >= 0 {
+				dangling[i].Count++
+			} else {
+				dangling = append(dangling, models.DanglingReference{Collection: collection, LocationID: id, Count: 1})
+			}
+		}
+		sortBy(dangling, "_id", false)
+		refs = append(refs, page(dangling, 0, limit)...)
+	}
+	var ids []primitive.ObjectID
+	for _, rv := range r.s.reviews {
+		ids = append(ids, rv.LocationID)
+	}
+	group(repositories.RefReviews, ids)
+	ids = nil
+	for _, f := range r.s.favorites {
+		ids = append(ids, f.LocationID)
+	}
+	group(repositories.RefFavorites, ids)
+	ids = nil
+	for _, c := range r.s.checkins {
+		ids = append(ids, c.LocationID)
+	}
+	group(repositories.RefCheckIns, ids)
+	return refs, nil
+}
+
+func (r *locationRepository) Creators(ctx context.Context) ([]models.CreatorCount, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	counts := map[string]int64{}
+	for _, loc := range r.s.locations {
+		if loc.DeletedAt == nil {
+			counts[loc.CreatedBy]++
+		}
+	}
+	creators := []models.CreatorCount{}
+	for email, n := range counts {
+		creators = append(creators, models.CreatorCount{Email: email, Count: n})
+	}
+	slices.SortFunc(creators, func(a, b models.CreatorCount) int { return strings.Compare(a.Email, b.Email) })
+	return creators, nil
+}
+
+func (r *locationRepository) WithPhotos(ctx context.Context, after primitive.ObjectID, limit int64) ([]models.Location, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	locations := []models.Location{}
+	for _, loc := range r.s.locations {
+		if loc.DeletedAt == nil && len(loc.Photos) > 0 && bytes.Compare(loc.ID[:], after[:]) > 0 {
+			locations = append(locations, loc)
+		}
+	}
+	sortBy(locations, "_id", false)
+	return cloneAll(page(locations, 0, limit)), nil
+}
+
+func (r *locationRepository) SetCounters(ctx context.Context, c models.LocationCounters) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if i := slices.IndexFunc(r.s.locations, func(loc models.Location) bool { return loc.ID == c.ID }); i >= 0 {
+		r.s.locations[i].FavoritesCount = c.Favorites
+		r.s.locations[i].CheckInsCount = c.CheckIns
+	}
+	return nil
+}
+
+func (r *locationRepository) FreshnessAlertCandidates(ctx context.Context, owner string, before time.Time, limit int64) ([]models.Location, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var locations []models.Location
+	for _, loc := range r.s.locations {
+		if loc.CreatedBy != owner || loc.DeletedAt != nil {
+			continue
+		}
+		ref := loc.ID.Timestamp()
+		if loc.LastConfirmedAt != nil {
+			ref = *loc.LastConfirmedAt
+		}
+		alerted := loc.FreshnessAlertedAt != nil && (loc.LastConfirmedAt == nil || !loc.FreshnessAlertedAt.Before(*loc.LastConfirmedAt))
+		if ref.Before(before) && !alerted {
+			locations = append(locations, clone(loc))
+		}
+	}
+	sortBy(locations, "last_confirmed_at", false)
+	return page(locations, 0, limit), nil
+}
+
+func (r *locationRepository) MarkFreshnessAlerted(ctx context.Context, ids []primitive.ObjectID, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for i := range r.s.locations {
+		if slices.Contains(ids, r.s.locations[i].ID) {
+			r.s.locations[i].FreshnessAlertedAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *locationRepository) CountByCreator(ctx context.Context, email string) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var n int64
+	for _, loc := range r.s.locations {
+		if loc.CreatedBy == email && loc.DeletedAt == nil {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *locationRepository) CountByCategory(ctx context.Context, category string) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var n int64
+	for _, loc := range r.s.locations {
+		if loc.Category == category {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// reassignable sama dengan reassignFilter di repository Mongo
+func reassignable(f models.ReassignFilter, target string, after primitive.ObjectID, loc *models.Location) bool {
+	if loc.DeletedAt != nil || loc.CreatedBy == target || bytes.Compare(loc.ID[:], after[:]) <= 0 {
+		return false
+	}
+	if f.CreatedBy != "" && loc.CreatedBy != f.CreatedBy {
+		return false
+	}
+	if f.Category != "" && loc.Category != f.Category {
+		return false
+	}
+	if !f.Scope.Contains(loc) {
+		return false
+	}
+	return f.Region == "" || regionCode(loc.AdminArea, f.RegionLevel) == f.Region
+}
+
+func regionCode(a *models.AdminArea, level string) string {
+	if a == nil {
+		return ""
+	}
+	var ref *models.RegionRef
+	switch level {
+	case models.RegionProvince:
+		ref = a.Province
+	case models.RegionRegency:
+		ref = a.Regency
+	case models.RegionDistrict:
+		ref = a.District
+	}
+	if ref == nil {
+		return ""
+	}
+	return ref.Code
+}
+
+func (r *locationRepository) ReassignCandidates(ctx context.Context, f models.ReassignFilter, target string, after primitive.ObjectID, limit int64) ([]models.Location, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	locations := []models.Location{}
+	for i := range r.s.locations {
+		if reassignable(f, target, after, &r.s.locations[i]) {
+			locations = append(locations, r.s.locations[i])
+		}
+	}
+	sortBy(locations, "_id", false)
+	return cloneAll(page(locations, 0, limit)), nil
+}
+
+func (r *locationRepository) CountReassignCandidates(ctx context.Context, f models.ReassignFilter, target string, after primitive.ObjectID) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var n int64
+	for i := range r.s.locations {
+		if reassignable(f, target, after, &r.s.locations[i]) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Lokasi di Store selalu hasil decode, jadi hanya versi yang disimpan
+// langsung di s.locations yang bisa tertinggal.
+func (r *locationRepository) SchemaOutdated(ctx context.Context, version int, limit int64) ([]models.Location, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var locations []models.Location
+	for _, loc := range r.s.locations {
+		if loc.SchemaVersion < version {
+			locations = append(locations, loc)
+		}
+	}
+	return cloneAll(page(locations, 0, limit)), nil
+}
+
+func (r *locationRepository) CountSchemaOutdated(ctx context.Context, version int) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var n int64
+	for _, loc := range r.s.locations {
+		if loc.SchemaVersion < version {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *locationRepository) SaveUpgraded(ctx context.Context, loc models.Location) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := slices.IndexFunc(r.s.locations, func(l models.Location) bool {
+		return l.ID == loc.ID && l.SchemaVersion < loc.SchemaVersion
+	})
+	if i >= 0 {
+		r.s.locations[i] = clone(loc)
+	}
+	return nil
+}
+
+func (r *locationRepository) PublicStats(ctx context.Context) (models.PublicStats, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var stats models.PublicStats
+	contributors := map[string]bool{}
+	for _, loc := range r.s.locations {
+		if !loc.Listed() || loc.DeletedAt != nil {
+			continue
+		}
+		stats.Locations++
+		contributors[loc.CreatedBy] = true
+		updated := loc.ID.Timestamp()
+		if loc.UpdatedAt != nil {
+			updated = *loc.UpdatedAt
+		}
+		if stats.LastUpdatedAt == nil || updated.After(*stats.LastUpdatedAt) {
+			stats.LastUpdatedAt = &updated
+		}
+	}
+	stats.Contributors = int64(len(contributors))
+	return stats, nil
+}
+
+func (r *locationRepository) Migrate(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+// summary menghitung ringkasan rating seperti summaryStages: rata-rata
+// dibulatkan satu desimal. Pemanggil wajib memegang s.mu.
+func (s *Store) summary(locationID primitive.ObjectID) models.RatingSummary {
+	var sum models.RatingSummary
+	total := 0
+	for _, rv := range s.reviews {
+		if rv.LocationID == locationID && rv.Listed() {
+			sum.Count++
+			total += rv.Rating
+		}
+	}
+	if sum.Count > 0 {
+		sum.Average = math.Round(float64(total)/float64(sum.Count)*10) / 10
+	}
+	return sum
+}
+
+// HasIndex selalu true: Store tidak butuh index untuk query geo
+func (r *locationRepository) HasIndex(ctx context.Context, key string, unique bool) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type deadLetterRepository struct {
+	s *Store
+}
+
+func (s *Store) DeadLetters() repositories.DeadLetterRepository {
+	return &deadLetterRepository{s: s}
+}
+
+// find mencari indeks email; pemanggil wajib memegang s.mu.
+func (r *deadLetterRepository) find(id primitive.ObjectID) int {
+	return slices.IndexFunc(r.s.deadLetters, func(d models.DeadLetter) bool { return d.ID == id })
+}
+
+func (r *deadLetterRepository) Create(ctx context.Context, d *models.DeadLetter) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if d.ID.IsZero() {
+		d.ID = primitive.NewObjectID()
+	}
+	r.s.deadLetters = append(r.s.deadLetters, clone(*d))
+	return nil
+}
+
+func (r *deadLetterRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.DeadLetter, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.find(id)
+	if i < 0 {
+		return nil, repositories.ErrNotFound
+	}
+	d := clone(r.s.deadLetters[i])
+	return &d, nil
+}
+
+func (r *deadLetterRepository) List(ctx context.Context, p repositories.ListPage) ([]models.DeadLetter, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	letters := cloneAll(r.s.deadLetters)
+	return listPage(letters, p), int64(len(letters)), nil
+}
+
+func (r *deadLetterRepository) RecordAttempt(ctx context.Context, id primitive.ObjectID, at time.Time, errMsg string, rejected bool) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.find(id)
+	if i < 0 {
+		return repositories.ErrNotFound
+	}
+	d := &r.s.deadLetters[i]
+	d.LastAttemptAt = at.UTC().Truncate(time.Millisecond)
+	d.Error = errMsg
+	d.Rejected = rejected
+	d.Attempts++
+	return nil
+}
+
+func (r *deadLetterRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.find(id)
+	if i < 0 {
+		return repositories.ErrNotFound
+	}
+	r.s.deadLetters = slices.Delete(r.s.deadLetters, i, i+1)
+	return nil
+}
+
+func (r *deadLetterRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,273 @@
+// Package memory berisi implementasi repository di memori untuk integration
+// test: seluruh stack HTTP (router, middleware, service) bisa dijalankan
+// tanpa MongoDB. Dokumen disimpan lewat roundtrip BSON sehingga tag bson,
+// omitempty dan update Fields (termasuk key bertitik seperti
+// "preferences.units") berperilaku seperti di MongoDB.
+//
+// Hanya method yang dipakai alur utama (auth, user, kategori, role, lokasi,
+// moderasi, audit) yang diimplementasikan. Method lain berasal dari
+// interface yang di-embed bernilai nil dan akan panic jika dipanggil, supaya
+// test yang butuh perilaku Mongo sungguhan (geo query, agregasi) langsung
+// ketahuan alih-alih lolos dengan hasil palsu.
+package memory
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Store menyimpan semua collection. Aman dipakai dari banyak goroutine.
+type Store struct {
+	mu            sync.Mutex
+	users         []models.User
+	locations     []models.Location
+	archive       []models.Location
+	categories    []models.Category
+	roles         []models.Role
+	audit         []models.AuditLog
+	auditArchives []models.AuditArchive
+	resets        []models.PasswordReset
+	favorites     []models.Favorite
+	reviews       []models.Review
+	confirmations []models.Confirmation
+	reassignments []models.Reassignment
+	importJobs    []models.ImportJob
+	checkins      []models.CheckIn
+	impressions   []impression
+	exports       []models.ScheduledExport
+	deadLetters   []models.DeadLetter
+	suggestions   []models.CategorySuggestion
+	regions       []models.Region
+	transitStops  []models.TransitStop
+	usage         []models.UsageCounter
+	events        []models.Event
+	eventOffsets  []models.EventOffset
+	sessions      []models.Session
+	redirects     []models.LocationRedirect
+	settings      map[string]bson.Raw
+}
+
+// New membuat Store kosong.
+func New() *Store {
+	return &Store{settings: map[string]bson.Raw{}}
+}
+
+// clone menyalin v lewat BSON supaya pemanggil tidak berbagi slice/pointer
+// dengan data di Store.
+func clone[T any](v T) T {
+	var out T
+	b, err := bson.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	if err := bson.Unmarshal(b, &out); err != nil {
+		panic(err)
+	}
+	return out
+}
+
+func cloneAll[T any](list []T) []T {
+	out := make([]T, len(list))
+	for i := range list {
+		out[i] = clone(list[i])
+	}
+	return out
+}
+
+// apply menjalankan $set (nilai nil/tanpa isi dihapus oleh omitempty saat
+// decode, sama seperti di Mongo setelah dibaca ulang).
+func apply[T any](v *T, set repositories.Fields) error {
+	return update(v, func(doc bson.M) {
+		for k, val := range set {
+			setPath(doc, k, val)
+		}
+	})
+}
+
+// unset menjalankan $unset.
+func unset[T any](v *T, keys ...string) error {
+	return update(v, func(doc bson.M) {
+		for _, k := range keys {
+			parent, last := walk(doc, k, false)
+			if parent != nil {
+				delete(parent, last)
+			}
+		}
+	})
+}
+
+func update[T any](v *T, fn func(bson.M)) error {
+	b, err := bson.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+	fn(doc)
+	if b, err = bson.Marshal(doc); err != nil {
+		return err
+	}
+	var out T
+	if err := bson.Unmarshal(b, &out); err != nil {
+		return err
+	}
+	*v = out
+	return nil
+}
+
+func setPath(doc bson.M, key string, val interface{}) {
+	parent, last := walk(doc, key, true)
+	parent[last] = val
+}
+
+// walk mengembalikan dokumen induk untuk key bertitik beserta nama field
+// terakhirnya. Jika create, dokumen perantara yang belum ada dibuat.
+func walk(doc bson.M, key string, create bool) (bson.M, string) {
+	parts := strings.Split(key, ".")
+	for _, p := range parts[:len(parts)-1] {
+		var next bson.M
+		switch v := doc[p].(type) {
+		case bson.M:
+			next = v
+		case primitive.D:
+			next = v.Map()
+		default:
+			if !create {
+				return nil, ""
+			}
+			next = bson.M{}
+		}
+		doc[p] = next
+		doc = next
+	}
+	return doc, parts[len(parts)-1]
+}
+
+// field membaca nilai field BSON dari v untuk pengurutan.
+func field(v interface{}, key string) interface{} {
+	b, err := bson.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	val, err := bson.Raw(b).LookupErr(strings.Split(key, ".")...)
+	if err != nil {
+		return nil
+	}
+	return rawValue(val)
+}
+
+// rawValue mengubah nilai BSON bertipe yang dipakai sort ke nilai Go; tipe
+// lain menjadi nil.
+func rawValue(val bson.RawValue) interface{} {
+	switch val.Type {
+	case bson.TypeString:
+		return val.StringValue()
+	case bson.TypeObjectID:
+		return val.ObjectID()
+	case bson.TypeDateTime:
+		return val.Time()
+	case bson.TypeInt32:
+		return float64(val.Int32())
+	case bson.TypeInt64:
+		return float64(val.Int64())
+	case bson.TypeDouble:
+		return val.Double()
+	}
+	return nil
+}
+
+// compare mengurutkan seperti Mongo untuk tipe yang dipakai sort: null di
+// depan, lalu angka, string, ObjectID dan waktu.
+func compare(a, b interface{}) int {
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		}
+		return 1
+	}
+	switch av := a.(type) {
+	case string:
+		if bv, ok := b.(string); ok {
+			return strings.Compare(av, bv)
+		}
+	case primitive.ObjectID:
+		if bv, ok := b.(primitive.ObjectID); ok {
+			return strings.Compare(av.Hex(), bv.Hex())
+		}
+	case time.Time:
+		if bv, ok := b.(time.Time); ok {
+			return av.Compare(bv)
+		}
+	case float64:
+		if bv, ok := b.(float64); ok {
+			return cmp.Compare(av, bv)
+		}
+	}
+	return 0
+}
+
+// sortBy mengurutkan list menurut field lalu _id, seperti sort dengan
+// tiebreaker _id di repository Mongo.
+func sortBy[T any](list []T, key string, desc bool) {
+	sort.SliceStable(list, func(i, j int) bool {
+		c := compare(field(list[i], key), field(list[j], key))
+		if c == 0 && key != "_id" {
+			c = compare(field(list[i], "_id"), field(list[j], "_id"))
+		}
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+// listPage mengurutkan list lalu menerapkan cursor, skip dan limit p,
+// seperti ListPage.FindOptions dan ListPage.Match di repository Mongo.
+func listPage[T any](list []T, p repositories.ListPage) []T {
+	key := p.Field
+	if key == "" {
+		key = "_id"
+	}
+	sortBy(list, key, p.Desc)
+	if p.After != nil {
+		after := rawValue(p.After.Value)
+		list = slices.DeleteFunc(list, func(v T) bool {
+			c := compare(field(v, key), after)
+			if c == 0 {
+				c = compare(field(v, "_id"), p.After.ID)
+			}
+			if p.Desc {
+				c = -c
+			}
+			return c <= 0
+		})
+	}
+	return page(list, p.Skip, p.Limit)
+}
+
+// page menerapkan skip/limit; limit 0 berarti tanpa batas.
+func page[T any](list []T, skip, limit int64) []T {
+	if skip >= int64(len(list)) {
+		return []T{}
+	}
+	list = list[skip:]
+	if limit > 0 && limit < int64(len(list)) {
+		list = list[:limit]
+	}
+	return list
+}
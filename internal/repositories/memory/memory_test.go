@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+func TestUserUpdateDottedField(t *testing.T) {
+	ctx := context.Background()
+	users := New().Users()
+	u := models.User{Email: "a@example.com", Role: "user"}
+	if err := users.Create(ctx, &u); err != nil {
+		t.Fatal(err)
+	}
+	if err := users.Create(ctx, &models.User{Email: "a@example.com"}); !errors.Is(err, repositories.ErrDuplicate) {
+		t.Fatalf("duplicate email: %v", err)
+	}
+	if err := users.Update(ctx, u.ID, repositories.Fields{"preferences.units": "imperial"}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := users.FindByID(ctx, u.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Preferences.Units != "imperial" || got.Role != "user" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestLocationListFilters(t *testing.T) {
+	ctx := context.Background()
+	locations := New().Locations()
+	seed := []models.Location{
+		{Name: "Kopi B", Category: "kafe", Address: "Bandung"},
+		{Name: "Kopi A", Category: "kafe", Address: "Bandung"},
+		{Name: "Masjid", Category: "masjid", Address: "Jakarta"},
+		{Name: "Kopi Pending", Category: "kafe", Address: "Bandung", ModerationStatus: models.ModerationPending},
+	}
+	if err := locations.CreateMany(ctx, seed); err != nil {
+		t.Fatal(err)
+	}
+	if err := locations.SoftDelete(ctx, seed[2].ID, "admin@example.com", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := locations.SoftDelete(ctx, seed[2].ID, "admin@example.com", time.Now()); !errors.Is(err, repositories.ErrNotFound) {
+		t.Fatalf("soft delete ulang: %v", err)
+	}
+
+	if err := locations.IncrementCounter(ctx, seed[1].ID, repositories.CounterFavorites, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	list, total, err := locations.List(ctx, repositories.LocationQuery{Text: "kopi", ListPage: repositories.ListPage{Field: "name"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 || len(list) != 2 || list[0].Name != "Kopi A" || list[1].Name != "Kopi B" {
+		t.Fatalf("total %d, list %+v", total, list)
+	}
+	if list[0].Rating == nil || list[0].FavoritesCount != 2 || list[1].FavoritesCount != 0 {
+		t.Errorf("rating harus diisi dan favorites_count dari counter tersimpan: %+v", list)
+	}
+
+	trash, _, err := locations.List(ctx, repositories.LocationQuery{Trashed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trash) != 1 || trash[0].DeletedBy != "admin@example.com" {
+		t.Fatalf("trash %+v", trash)
+	}
+	if err := locations.Restore(ctx, seed[2].ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := locations.FindByID(ctx, seed[2].ID); err != nil {
+		t.Fatalf("setelah restore: %v", err)
+	}
+}
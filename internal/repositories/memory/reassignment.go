@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type reassignmentRepository struct {
+	s *Store
+}
+
+func (s *Store) Reassignments() repositories.ReassignmentRepository {
+	return &reassignmentRepository{s: s}
+}
+
+// find mencari indeks job; pemanggil wajib memegang s.mu.
+func (r *reassignmentRepository) find(id primitive.ObjectID) int {
+	return slices.IndexFunc(r.s.reassignments, func(j models.Reassignment) bool { return j.ID == id })
+}
+
+func (r *reassignmentRepository) Create(ctx context.Context, job *models.Reassignment) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if job.ID.IsZero() {
+		job.ID = primitive.NewObjectID()
+	}
+	r.s.reassignments = append(r.s.reassignments, clone(*job))
+	return nil
+}
+
+func (r *reassignmentRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Reassignment, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.find(id)
+	if i < 0 {
+		return nil, repositories.ErrNotFound
+	}
+	job := clone(r.s.reassignments[i])
+	return &job, nil
+}
+
+func (r *reassignmentRepository) List(ctx context.Context, p repositories.ListPage) ([]models.Reassignment, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	jobs := cloneAll(r.s.reassignments)
+	return listPage(jobs, p), int64(len(jobs)), nil
+}
+
+func (r *reassignmentRepository) queued() []models.Reassignment {
+	jobs := []models.Reassignment{}
+	for _, j := range r.s.reassignments {
+		if j.Status == models.ReassignQueued {
+			jobs = append(jobs, j)
+		}
+	}
+	sortBy(jobs, "_id", false)
+	return jobs
+}
+
+func (r *reassignmentRepository) NextQueued(ctx context.Context) (*models.Reassignment, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	jobs := r.queued()
+	if len(jobs) == 0 {
+		return nil, repositories.ErrNotFound
+	}
+	job := clone(jobs[0])
+	return &job, nil
+}
+
+func (r *reassignmentRepository) CountQueued(ctx context.Context) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return int64(len(r.queued())), nil
+}
+
+func (r *reassignmentRepository) Advance(ctx context.Context, id, from, to primitive.ObjectID, n int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.find(id)
+	if i < 0 || r.s.reassignments[i].Cursor != from {
+		return repositories.ErrNotFound
+	}
+	r.s.reassignments[i].Cursor = to
+	r.s.reassignments[i].Reassigned += n
+	return nil
+}
+
+func (r *reassignmentRepository) Complete(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.find(id)
+	if i < 0 || r.s.reassignments[i].Status != models.ReassignQueued {
+		return repositories.ErrNotFound
+	}
+	r.s.reassignments[i].Status = models.ReassignDone
+	r.s.reassignments[i].CompletedAt = &at
+	return nil
+}
+
+func (r *reassignmentRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,334 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type roleRepository struct {
+	s *Store
+}
+
+// Roles mengembalikan RoleRepository di atas Store.
+func (s *Store) Roles() repositories.RoleRepository {
+	return &roleRepository{s: s}
+}
+
+func (r *roleRepository) index(name string) int {
+	return slices.IndexFunc(r.s.roles, func(role models.Role) bool { return role.Name == name })
+}
+
+func (r *roleRepository) List(ctx context.Context) ([]models.Role, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	roles := cloneAll(r.s.roles)
+	slices.SortFunc(roles, func(a, b models.Role) int { return strings.Compare(a.Name, b.Name) })
+	return roles, nil
+}
+
+func (r *roleRepository) Get(ctx context.Context, name string) (*models.Role, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.index(name)
+	if i < 0 {
+		return nil, repositories.ErrNotFound
+	}
+	role := clone(r.s.roles[i])
+	return &role, nil
+}
+
+func (r *roleRepository) Save(ctx context.Context, role models.Role) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if i := r.index(role.Name); i >= 0 {
+		r.s.roles[i] = clone(role)
+		return nil
+	}
+	r.s.roles = append(r.s.roles, clone(role))
+	return nil
+}
+
+func (r *roleRepository) Delete(ctx context.Context, name string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.index(name)
+	if i < 0 {
+		return repositories.ErrNotFound
+	}
+	r.s.roles = slices.Delete(r.s.roles, i, i+1)
+	return nil
+}
+
+func (r *roleRepository) EnsureDefaults(ctx context.Context, roles []models.Role) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, role := range roles {
+		if r.index(role.Name) < 0 {
+			r.s.roles = append(r.s.roles, clone(role))
+		}
+	}
+	return nil
+}
+
+type settingsRepository struct {
+	s *Store
+}
+
+// Settings mengembalikan SettingsRepository di atas Store.
+func (s *Store) Settings() repositories.SettingsRepository {
+	return &settingsRepository{s: s}
+}
+
+func (r *settingsRepository) Get(ctx context.Context, key string, out interface{}) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	raw, ok := r.s.settings[key]
+	if !ok {
+		return repositories.ErrNotFound
+	}
+	return raw.Lookup("value").Unmarshal(out)
+}
+
+func (r *settingsRepository) Put(ctx context.Context, key string, value interface{}, updatedBy string) error {
+	// Dibungkus dokumen karena value bisa berupa nilai skalar
+	raw, err := bson.Marshal(bson.M{"value": value})
+	if err != nil {
+		return err
+	}
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.settings[key] = raw
+	return nil
+}
+
+type categoryRepository struct {
+	s *Store
+}
+
+// Categories mengembalikan CategoryRepository di atas Store. Slug unik
+// seperti unique index di Mongo.
+func (s *Store) Categories() repositories.CategoryRepository {
+	return &categoryRepository{s: s}
+}
+
+func (r *categoryRepository) index(slug string) int {
+	return slices.IndexFunc(r.s.categories, func(c models.Category) bool { return c.Slug == slug })
+}
+
+func (r *categoryRepository) List(ctx context.Context) ([]models.Category, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	categories := cloneAll(r.s.categories)
+	sortBy(categories, "name", false)
+	return categories, nil
+}
+
+func (r *categoryRepository) FindBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.index(slug)
+	if i < 0 {
+		return nil, repositories.ErrNotFound
+	}
+	c := clone(r.s.categories[i])
+	return &c, nil
+}
+
+func (r *categoryRepository) Create(ctx context.Context, c *models.Category) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if r.index(c.Slug) >= 0 {
+		return repositories.ErrDuplicate
+	}
+	if c.ID.IsZero() {
+		c.ID = primitive.NewObjectID()
+	}
+	r.s.categories = append(r.s.categories, clone(*c))
+	return nil
+}
+
+func (r *categoryRepository) Update(ctx context.Context, slug string, set repositories.Fields) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.index(slug)
+	if i < 0 {
+		return repositories.ErrNotFound
+	}
+	return apply(&r.s.categories[i], set)
+}
+
+func (r *categoryRepository) Delete(ctx context.Context, slug string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.index(slug)
+	if i < 0 {
+		return repositories.ErrNotFound
+	}
+	r.s.categories = slices.Delete(r.s.categories, i, i+1)
+	return nil
+}
+
+func (r *categoryRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+type auditRepository struct {
+	s *Store
+}
+
+// Audit mengembalikan AuditRepository di atas Store.
+func (s *Store) Audit() repositories.AuditRepository {
+	return &auditRepository{s: s}
+}
+
+func (r *auditRepository) Insert(ctx context.Context, logs []models.AuditLog) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, l := range logs {
+		for _, existing := range r.s.audit {
+			if l.Seq != 0 && existing.Seq == l.Seq {
+				return repositories.ErrDuplicate
+			}
+		}
+	}
+	for _, l := range logs {
+		if l.ID.IsZero() {
+			l.ID = primitive.NewObjectID()
+		}
+		r.s.audit = append(r.s.audit, clone(l))
+	}
+	return nil
+}
+
+func (r *auditRepository) match(q repositories.AuditQuery, l models.AuditLog) bool {
+	if q.Text != "" {
+		text := strings.ToLower(q.Text)
+		found := false
+		for _, v := range []string{l.Actor, l.Action, l.ResourceType, l.ResourceID, l.Summary} {
+			if strings.Contains(strings.ToLower(v), text) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return (q.Actor == "" || l.Actor == q.Actor) &&
+		(q.Action == "" || l.Action == q.Action) &&
+		(q.Action != "" || len(q.Actions) == 0 || slices.Contains(q.Actions, l.Action)) &&
+		(q.ResourceType == "" || l.ResourceType == q.ResourceType) &&
+		(q.ResourceID == "" || l.ResourceID == q.ResourceID) &&
+		(q.From.IsZero() || !l.Time.Before(q.From)) &&
+		(q.To.IsZero() || l.Time.Before(q.To))
+}
+
+func (r *auditRepository) Find(ctx context.Context, q repositories.AuditQuery) ([]models.AuditLog, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	logs := []models.AuditLog{}
+	for _, l := range r.s.audit {
+		if r.match(q, l) {
+			logs = append(logs, clone(l))
+		}
+	}
+	total := int64(len(logs))
+	return listPage(logs, q.ListPage), total, nil
+}
+
+func (r *auditRepository) Each(ctx context.Context, q repositories.AuditQuery, fn func(models.AuditLog) error) error {
+	r.s.mu.Lock()
+	logs := []models.AuditLog{}
+	for _, l := range r.s.audit {
+		if r.match(q, l) {
+			logs = append(logs, clone(l))
+		}
+	}
+	r.s.mu.Unlock()
+	sortBy(logs, "time", false)
+	for _, l := range logs {
+		if err := fn(l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *auditRepository) Last(ctx context.Context) (*models.AuditLog, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var last *models.AuditLog
+	for i := range r.s.audit {
+		if l := &r.s.audit[i]; l.Seq > 0 && (last == nil || l.Seq > last.Seq) {
+			last = l
+		}
+	}
+	if last == nil {
+		return nil, repositories.ErrNotFound
+	}
+	l := clone(*last)
+	return &l, nil
+}
+
+func (r *auditRepository) Chain(ctx context.Context, afterSeq, limit int64) ([]models.AuditLog, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	logs := []models.AuditLog{}
+	for _, l := range r.s.audit {
+		if l.Seq > afterSeq {
+			logs = append(logs, clone(l))
+		}
+	}
+	sortBy(logs, "seq", false)
+	return page(logs, 0, limit), nil
+}
+
+func (r *auditRepository) DeleteThrough(ctx context.Context, seq int64) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	n := len(r.s.audit)
+	r.s.audit = slices.DeleteFunc(r.s.audit, func(l models.AuditLog) bool { return l.Seq > 0 && l.Seq <= seq })
+	return int64(n - len(r.s.audit)), nil
+}
+
+func (r *auditRepository) AddArchive(ctx context.Context, a *models.AuditArchive) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, existing := range r.s.auditArchives {
+		if existing.FromSeq == a.FromSeq {
+			return repositories.ErrDuplicate
+		}
+	}
+	if a.ID.IsZero() {
+		a.ID = primitive.NewObjectID()
+	}
+	r.s.auditArchives = append(r.s.auditArchives, clone(*a))
+	return nil
+}
+
+func (r *auditRepository) Archives(ctx context.Context) ([]models.AuditArchive, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	archives := cloneAll(r.s.auditArchives)
+	sortBy(archives, "from_seq", false)
+	return archives, nil
+}
+
+func (r *auditRepository) LastArchive(ctx context.Context) (*models.AuditArchive, error) {
+	archives, _ := r.Archives(ctx)
+	if len(archives) == 0 {
+		return nil, repositories.ErrNotFound
+	}
+	return &archives[len(archives)-1], nil
+}
+
+func (r *auditRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type suggestionRepository struct {
+	s *Store
+}
+
+func (s *Store) CategorySuggestions() repositories.CategorySuggestionRepository {
+	return &suggestionRepository{s: s}
+}
+
+// find mencari indeks usulan; pemanggil wajib memegang s.mu.
+func (r *suggestionRepository) find(id primitive.ObjectID) int {
+	return slices.IndexFunc(r.s.suggestions, func(s models.CategorySuggestion) bool { return s.ID == id })
+}
+
+func (r *suggestionRepository) Create(ctx context.Context, sug *models.CategorySuggestion) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if sug.ID.IsZero() {
+		sug.ID = primitive.NewObjectID()
+	}
+	r.s.suggestions = append(r.s.suggestions, clone(*sug))
+	return nil
+}
+
+func (r *suggestionRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.CategorySuggestion, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.find(id)
+	if i < 0 {
+		return nil, repositories.ErrNotFound
+	}
+	sug := clone(r.s.suggestions[i])
+	return &sug, nil
+}
+
+func (r *suggestionRepository) List(ctx context.Context, status string, p repositories.ListPage) ([]models.CategorySuggestion, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	suggestions := []models.CategorySuggestion{}
+	for _, sug := range r.s.suggestions {
+		if status == "" || sug.Status == status {
+			suggestions = append(suggestions, clone(sug))
+		}
+	}
+	return listPage(suggestions, p), int64(len(suggestions)), nil
+}
+
+func (r *suggestionRepository) CountPending(ctx context.Context, email string) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var n int64
+	for _, sug := range r.s.suggestions {
+		if sug.SuggestedBy == email && sug.Status == models.SuggestionPending {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *suggestionRepository) Resolve(ctx context.Context, id primitive.ObjectID, set repositories.Fields) (*models.CategorySuggestion, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.find(id)
+	if i < 0 || r.s.suggestions[i].Status != models.SuggestionPending {
+		return nil, repositories.ErrNotFound
+	}
+	if err := apply(&r.s.suggestions[i], set); err != nil {
+		return nil, err
+	}
+	sug := clone(r.s.suggestions[i])
+	return &sug, nil
+}
+
+func (r *suggestionRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,232 @@
+package memory
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Prefix hash bcrypt, sama dengan filter CountPlaintextPasswords di Mongo
+var bcryptHash = regexp.MustCompile(`^\$2[aby]\$`)
+
+type userRepository struct {
+	repositories.UserRepository
+	s *Store
+}
+
+// Users mengembalikan UserRepository di atas Store. Email unik seperti
+// unique index di Mongo.
+func (s *Store) Users() repositories.UserRepository {
+	return &userRepository{s: s}
+}
+
+func (r *userRepository) find(match func(*models.User) bool) int {
+	for i := range r.s.users {
+		if match(&r.s.users[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *userRepository) byID(id primitive.ObjectID) int {
+	return r.find(func(u *models.User) bool { return u.ID == id })
+}
+
+func (r *userRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.byID(id)
+	if i < 0 {
+		return nil, repositories.ErrNotFound
+	}
+	u := clone(r.s.users[i])
+	return &u, nil
+}
+
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.find(func(u *models.User) bool { return u.Email == email })
+	if i < 0 {
+		return nil, repositories.ErrNotFound
+	}
+	u := clone(r.s.users[i])
+	return &u, nil
+}
+
+func (r *userRepository) List(ctx context.Context) ([]models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return cloneAll(r.s.users), nil
+}
+
+func (r *userRepository) MissingEmails(ctx context.Context, emails []string) ([]string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	missing := []string{}
+	for _, email := range emails {
+		if r.find(func(u *models.User) bool { return u.Email == email }) < 0 {
+			missing = append(missing, email)
+		}
+	}
+	return missing, nil
+}
+
+func (r *userRepository) Create(ctx context.Context, u *models.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if r.find(func(existing *models.User) bool { return existing.Email == u.Email }) >= 0 {
+		return repositories.ErrDuplicate
+	}
+	if u.ID.IsZero() {
+		u.ID = primitive.NewObjectID()
+	}
+	r.s.users = append(r.s.users, clone(*u))
+	return nil
+}
+
+func (r *userRepository) Update(ctx context.Context, id primitive.ObjectID, set repositories.Fields) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.byID(id)
+	if i < 0 {
+		return repositories.ErrNotFound
+	}
+	return apply(&r.s.users[i], set)
+}
+
+func (r *userRepository) IncrementFailedLogins(ctx context.Context, id primitive.ObjectID) (int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.byID(id)
+	if i < 0 {
+		return 0, repositories.ErrNotFound
+	}
+	r.s.users[i].FailedLogins++
+	return r.s.users[i].FailedLogins, nil
+}
+
+func (r *userRepository) SetRole(ctx context.Context, id primitive.ObjectID, role string) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.byID(id)
+	if i < 0 {
+		return nil, repositories.ErrNotFound
+	}
+	before := clone(r.s.users[i])
+	r.s.users[i].Role = role
+	return &before, nil
+}
+
+func (r *userRepository) SetLocationQuota(ctx context.Context, id primitive.ObjectID, quota *int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.byID(id)
+	if i < 0 {
+		return repositories.ErrNotFound
+	}
+	if quota != nil {
+		q := *quota
+		quota = &q
+	}
+	r.s.users[i].LocationQuota = quota
+	return nil
+}
+
+func (r *userRepository) Delete(ctx context.Context, id primitive.ObjectID) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i := r.byID(id)
+	if i < 0 {
+		return nil, repositories.ErrNotFound
+	}
+	deleted := r.s.users[i]
+	r.s.users = append(r.s.users[:i], r.s.users[i+1:]...)
+	return &deleted, nil
+}
+
+func (r *userRepository) CountByRole(ctx context.Context, role string) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var n int64
+	for _, u := range r.s.users {
+		if u.Role == role {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *userRepository) CountPlaintextPasswords(ctx context.Context) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var n int64
+	for _, u := range r.s.users {
+		if !bcryptHash.MatchString(u.Password) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// HasIndex selalu true: Store menegakkan keunikan email di kode
+func (r *userRepository) HasIndex(ctx context.Context, key string, unique bool) (bool, error) {
+	return true, nil
+}
+
+func (r *userRepository) WithFreshnessAlerts(ctx context.Context, afterID primitive.ObjectID, limit int64) ([]models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var users []models.User
+	for _, u := range r.s.users {
+		if a := u.Preferences.Alerts; a != nil && a.FreshnessBelow > 0 && u.ID.Hex() > afterID.Hex() {
+			users = append(users, clone(u))
+		}
+	}
+	sortBy(users, "_id", false)
+	return page(users, 0, limit), nil
+}
+
+type passwordResetRepository struct {
+	s *Store
+}
+
+// PasswordResets mengembalikan PasswordResetRepository di atas Store.
+func (s *Store) PasswordResets() repositories.PasswordResetRepository {
+	return &passwordResetRepository{s: s}
+}
+
+func (r *passwordResetRepository) Create(ctx context.Context, reset *models.PasswordReset) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if reset.ID.IsZero() {
+		reset.ID = primitive.NewObjectID()
+	}
+	r.s.resets = append(r.s.resets, clone(*reset))
+	return nil
+}
+
+func (r *passwordResetRepository) Consume(ctx context.Context, tokenHash string, now time.Time) (*models.PasswordReset, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for i := range r.s.resets {
+		reset := &r.s.resets[i]
+		if reset.TokenHash == tokenHash && reset.UsedAt == nil && reset.ExpiresAt.After(now) {
+			used := now
+			reset.UsedAt = &used
+			out := clone(*reset)
+			return &out, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *passwordResetRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, reset *models.PasswordReset) error
+	// Consume menandai token terpakai secara atomik; ErrNotFound jika token
+	// tidak ada, sudah dipakai, atau kedaluwarsa.
+	Consume(ctx context.Context, tokenHash string, now time.Time) (*models.PasswordReset, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+type mongoPasswordResetRepository struct {
+	coll *mongo.Collection
+}
+
+func NewPasswordResetRepository(coll *mongo.Collection) PasswordResetRepository {
+	return &mongoPasswordResetRepository{coll: coll}
+}
+
+func (r *mongoPasswordResetRepository) Create(ctx context.Context, reset *models.PasswordReset) error {
+	if reset.ID.IsZero() {
+		reset.ID = primitive.NewObjectID()
+	}
+	_, err := r.coll.InsertOne(ctx, reset)
+	return err
+}
+
+func (r *mongoPasswordResetRepository) Consume(ctx context.Context, tokenHash string, now time.Time) (*models.PasswordReset, error) {
+	var reset models.PasswordReset
+	err := r.coll.FindOneAndUpdate(ctx,
+		bson.M{"token_hash": tokenHash, "used_at": nil, "expires_at": bson.M{"$gt": now}},
+		bson.M{"$set": bson.M{"used_at": now}},
+	).Decode(&reset)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &reset, nil
+}
+
+// Token reset otomatis dihapus Mongo setelah kedaluwarsa
+func (r *mongoPasswordResetRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	return err
+}
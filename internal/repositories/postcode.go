@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Jumlah dokumen per InsertMany saat import kode pos
+const postcodeInsertBatch = 1000
+
+type PostcodeRepository interface {
+	// ReplaceAll mengganti seluruh dataset kode pos.
+	ReplaceAll(ctx context.Context, postcodes []models.Postcode) error
+	Get(ctx context.Context, code string) (*models.Postcode, error)
+	// ByDistrict mencari kode pos yang mencakup kecamatan di kabupaten/kota
+	// (memakai nama yang sudah dinormalkan).
+	ByDistrict(ctx context.Context, districtKey, regencyKey string) ([]models.Postcode, error)
+	// Loaded bernilai true jika dataset sudah pernah di-import.
+	Loaded(ctx context.Context) (bool, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+type mongoPostcodeRepository struct {
+	coll *mongo.Collection
+}
+
+func NewPostcodeRepository(coll *mongo.Collection) PostcodeRepository {
+	return &mongoPostcodeRepository{coll: coll}
+}
+
+func (r *mongoPostcodeRepository) ReplaceAll(ctx context.Context, postcodes []models.Postcode) error {
+	if _, err := r.coll.DeleteMany(ctx, bson.M{}); err != nil {
+		return err
+	}
+	for start := 0; start < len(postcodes); start += postcodeInsertBatch {
+		end := min(start+postcodeInsertBatch, len(postcodes))
+		docs := make([]interface{}, 0, end-start)
+		for _, pc := range postcodes[start:end] {
+			docs = append(docs, pc)
+		}
+		if _, err := r.coll.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *mongoPostcodeRepository) Get(ctx context.Context, code string) (*models.Postcode, error) {
+	var pc models.Postcode
+	if err := r.coll.FindOne(ctx, bson.M{"_id": code}).Decode(&pc); err != nil {
+		return nil, notFound(err)
+	}
+	return &pc, nil
+}
+
+func (r *mongoPostcodeRepository) ByDistrict(ctx context.Context, districtKey, regencyKey string) ([]models.Postcode, error) {
+	filter := bson.M{"areas": bson.M{"$elemMatch": bson.M{"district_key": districtKey, "regency_key": regencyKey}}}
+	cursor, err := r.coll.Find(ctx, filter, options.Find().SetLimit(50))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	postcodes := []models.Postcode{}
+	if err := cursor.All(ctx, &postcodes); err != nil {
+		return nil, err
+	}
+	return postcodes, nil
+}
+
+func (r *mongoPostcodeRepository) Loaded(ctx context.Context) (bool, error) {
+	n, err := r.coll.EstimatedDocumentCount(ctx)
+	return n > 0, err
+}
+
+func (r *mongoPostcodeRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "areas.district_key", Value: 1}, {Key: "areas.regency_key", Value: 1}},
+	})
+	return err
+}
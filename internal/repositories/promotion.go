@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PromotionRepository menyimpan jumlah impression lokasi promosi per
+// tanggal (collection promotion_impressions, satu dokumen per lokasi per
+// hari).
+type PromotionRepository interface {
+	// AddImpressions menambah satu impression untuk setiap id pada date
+	// (YYYY-MM-DD).
+	AddImpressions(ctx context.Context, date string, ids []primitive.ObjectID) error
+	// Impressions mengembalikan jumlah per tanggal from..to (inklusif,
+	// kosong = tanpa batas), urut tanggal.
+	Impressions(ctx context.Context, locationID primitive.ObjectID, from, to string) ([]models.DayCount, error)
+	DeleteByLocation(ctx context.Context, locationID primitive.ObjectID) (int64, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+type mongoPromotionRepository struct {
+	coll *mongo.Collection
+}
+
+func NewPromotionRepository(coll *mongo.Collection) PromotionRepository {
+	return &mongoPromotionRepository{coll: coll}
+}
+
+func (r *mongoPromotionRepository) AddImpressions(ctx context.Context, date string, ids []primitive.ObjectID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	writes := make([]mongo.WriteModel, len(ids))
+	for i, id := range ids {
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"location_id": id, "date": date}).
+			SetUpdate(bson.M{"$inc": bson.M{"count": 1}}).
+			SetUpsert(true)
+	}
+	_, err := r.coll.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (r *mongoPromotionRepository) Impressions(ctx context.Context, locationID primitive.ObjectID, from, to string) ([]models.DayCount, error) {
+	filter := bson.M{"location_id": locationID}
+	dateRange := bson.M{}
+	if from != "" {
+		dateRange["$gte"] = from
+	}
+	if to != "" {
+		dateRange["$lte"] = to
+	}
+	if len(dateRange) > 0 {
+		filter["date"] = dateRange
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}}).
+		SetProjection(bson.M{"_id": "$date", "count": 1})
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	days := []models.DayCount{}
+	if err := cursor.All(ctx, &days); err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+func (r *mongoPromotionRepository) DeleteByLocation(ctx context.Context, locationID primitive.ObjectID) (int64, error) {
+	res, err := r.coll.DeleteMany(ctx, bson.M{"location_id": locationID})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+func (r *mongoPromotionRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "location_id", Value: 1}, {Key: "date", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
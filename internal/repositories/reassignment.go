@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReassignmentRepository interface {
+	Create(ctx context.Context, r *models.Reassignment) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Reassignment, error)
+	List(ctx context.Context, p ListPage) ([]models.Reassignment, int64, error)
+	// NextQueued mengembalikan job queued yang paling lama; ErrNotFound
+	// jika antrean kosong.
+	NextQueued(ctx context.Context) (*models.Reassignment, error)
+	CountQueued(ctx context.Context) (int64, error)
+	// Advance memindahkan cursor dari from ke to dan menambah reassigned
+	// sebanyak n. ErrNotFound jika cursor sudah dipindah proses lain.
+	Advance(ctx context.Context, id, from, to primitive.ObjectID, n int64) error
+	// Complete menandai job queued selesai; ErrNotFound jika sudah selesai.
+	Complete(ctx context.Context, id primitive.ObjectID, at time.Time) error
+	EnsureIndexes(ctx context.Context) error
+}
+
+// ReassignmentList adalah sort dan batas daftar job pemindahan pemilik.
+var ReassignmentList = ListSpec{
+	Sorts:        map[string]string{"created_at": "_id"},
+	DefaultSort:  "-created_at",
+	DefaultLimit: 20,
+	MaxLimit:     100,
+}
+
+type mongoReassignmentRepository struct {
+	coll *mongo.Collection
+}
+
+func NewReassignmentRepository(coll *mongo.Collection) ReassignmentRepository {
+	return &mongoReassignmentRepository{coll: coll}
+}
+
+func (r *mongoReassignmentRepository) Create(ctx context.Context, job *models.Reassignment) error {
+	if job.ID.IsZero() {
+		job.ID = primitive.NewObjectID()
+	}
+	_, err := r.coll.InsertOne(ctx, job)
+	return err
+}
+
+func (r *mongoReassignmentRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Reassignment, error) {
+	var job models.Reassignment
+	if err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&job); err != nil {
+		return nil, notFound(err)
+	}
+	return &job, nil
+}
+
+func (r *mongoReassignmentRepository) List(ctx context.Context, p ListPage) ([]models.Reassignment, int64, error) {
+	total, err := r.coll.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return nil, 0, err
+	}
+	cursor, err := r.coll.Find(ctx, p.Match(bson.M{}), p.FindOptions())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+	jobs := []models.Reassignment{}
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, 0, err
+	}
+	return jobs, total, nil
+}
+
+func (r *mongoReassignmentRepository) NextQueued(ctx context.Context) (*models.Reassignment, error) {
+	var job models.Reassignment
+	opts := options.FindOne().SetSort(bson.D{{Key: "_id", Value: 1}})
+	if err := r.coll.FindOne(ctx, bson.M{"status": models.ReassignQueued}, opts).Decode(&job); err != nil {
+		return nil, notFound(err)
+	}
+	return &job, nil
+}
+
+func (r *mongoReassignmentRepository) CountQueued(ctx context.Context) (int64, error) {
+	return r.coll.CountDocuments(ctx, bson.M{"status": models.ReassignQueued})
+}
+
+func (r *mongoReassignmentRepository) Advance(ctx context.Context, id, from, to primitive.ObjectID, n int64) error {
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": id, "cursor": from},
+		bson.M{"$set": bson.M{"cursor": to}, "$inc": bson.M{"reassigned": n}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoReassignmentRepository) Complete(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": id, "status": models.ReassignQueued},
+		bson.M{"$set": bson.M{"status": models.ReassignDone, "completed_at": at}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Antrean dibaca per status urut _id
+func (r *mongoReassignmentRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "_id", Value: 1}},
+	})
+	return err
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RedirectRepository menyimpan redirect ID lokasi lama (collection
+// location_redirects). Dokumen tidak pernah kedaluwarsa.
+type RedirectRepository interface {
+	// Find mengembalikan ErrNotFound jika from tidak pernah dialihkan.
+	Find(ctx context.Context, from primitive.ObjectID) (*models.LocationRedirect, error)
+	// Put membuat atau menimpa redirect dari r.From.
+	Put(ctx context.Context, r *models.LocationRedirect) error
+	// Retarget mengarahkan semua redirect yang menuju from ke to.
+	Retarget(ctx context.Context, from, to primitive.ObjectID) (int64, error)
+	Delete(ctx context.Context, from primitive.ObjectID) error
+	EnsureIndexes(ctx context.Context) error
+}
+
+type mongoRedirectRepository struct {
+	coll *mongo.Collection
+}
+
+func NewRedirectRepository(coll *mongo.Collection) RedirectRepository {
+	return &mongoRedirectRepository{coll: coll}
+}
+
+func (r *mongoRedirectRepository) Find(ctx context.Context, from primitive.ObjectID) (*models.LocationRedirect, error) {
+	var red models.LocationRedirect
+	if err := r.coll.FindOne(ctx, bson.M{"_id": from}).Decode(&red); err != nil {
+		return nil, notFound(err)
+	}
+	return &red, nil
+}
+
+func (r *mongoRedirectRepository) Put(ctx context.Context, red *models.LocationRedirect) error {
+	_, err := r.coll.ReplaceOne(ctx, bson.M{"_id": red.From}, red, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *mongoRedirectRepository) Retarget(ctx context.Context, from, to primitive.ObjectID) (int64, error) {
+	res, err := r.coll.UpdateMany(ctx, bson.M{"to": from}, bson.M{"$set": bson.M{"to": to}})
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+func (r *mongoRedirectRepository) Delete(ctx context.Context, from primitive.ObjectID) error {
+	_, err := r.coll.DeleteOne(ctx, bson.M{"_id": from})
+	return err
+}
+
+// Index tujuan untuk Retarget
+func (r *mongoRedirectRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "to", Value: 1}}})
+	return err
+}
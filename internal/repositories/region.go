@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Geometry wilayah besar; jangan ikut terbaca kecuali dibutuhkan
+var withoutGeometry = bson.M{"geometry": 0}
+
+type RegionRepository interface {
+	// Save membuat atau mengganti wilayah berdasarkan kode.
+	Save(ctx context.Context, region models.Region) error
+	// List tanpa geometry; filter kosong diabaikan.
+	List(ctx context.Context, level, parentCode string) ([]models.Region, error)
+	Get(ctx context.Context, code string) (*models.Region, error)
+	// Containing mengembalikan semua wilayah (tanpa geometry) yang memuat titik c.
+	Containing(ctx context.Context, c models.Coordinates) ([]models.Region, error)
+	// EnsureIndexes membuat index 2dsphere geometry dan index level/parent.
+	EnsureIndexes(ctx context.Context) error
+}
+
+type mongoRegionRepository struct {
+	coll *mongo.Collection
+}
+
+func NewRegionRepository(coll *mongo.Collection) RegionRepository {
+	return &mongoRegionRepository{coll: coll}
+}
+
+func (r *mongoRegionRepository) Save(ctx context.Context, region models.Region) error {
+	_, err := r.coll.ReplaceOne(ctx, bson.M{"_id": region.Code}, region, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *mongoRegionRepository) List(ctx context.Context, level, parentCode string) ([]models.Region, error) {
+	filter := bson.M{}
+	if level != "" {
+		filter["level"] = level
+	}
+	if parentCode != "" {
+		filter["parent_code"] = parentCode
+	}
+	opts := options.Find().SetProjection(withoutGeometry).SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	regions := []models.Region{}
+	if err := cursor.All(ctx, &regions); err != nil {
+		return nil, err
+	}
+	return regions, nil
+}
+
+func (r *mongoRegionRepository) Get(ctx context.Context, code string) (*models.Region, error) {
+	var region models.Region
+	opts := options.FindOne().SetProjection(withoutGeometry)
+	if err := r.coll.FindOne(ctx, bson.M{"_id": code}, opts).Decode(&region); err != nil {
+		return nil, notFound(err)
+	}
+	return &region, nil
+}
+
+func (r *mongoRegionRepository) Containing(ctx context.Context, c models.Coordinates) ([]models.Region, error) {
+	filter := bson.M{"geometry": bson.M{"$geoIntersects": bson.M{
+		"$geometry": bson.M{"type": "Point", "coordinates": bson.A{c.Lng, c.Lat}},
+	}}}
+	cursor, err := r.coll.Find(ctx, filter, options.Find().SetProjection(withoutGeometry))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	regions := []models.Region{}
+	if err := cursor.All(ctx, &regions); err != nil {
+		return nil, err
+	}
+	return regions, nil
+}
+
+func (r *mongoRegionRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "geometry", Value: "2dsphere"}}},
+		{Keys: bson.D{{Key: "level", Value: 1}, {Key: "parent_code", Value: 1}}},
+	})
+	return err
+}
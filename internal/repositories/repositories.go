@@ -0,0 +1,48 @@
+// Package repositories berisi akses data ke MongoDB. Setiap repository
+// didefinisikan sebagai interface supaya service bisa diuji dengan
+// implementasi palsu, dan menerima *mongo.Collection lewat konstruktor.
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrNotFound dikembalikan jika dokumen yang dicari tidak ada.
+var ErrNotFound = errors.New("dokumen tidak ditemukan")
+
+// ErrDuplicate dikembalikan jika insert melanggar unique index.
+var ErrDuplicate = errors.New("dokumen sudah ada")
+
+// Fields adalah kumpulan field (nama field BSON) untuk operasi $set.
+type Fields map[string]interface{}
+
+func notFound(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// hasIndex mengecek apakah collection punya index satu field dengan key
+// tersebut (dan unique jika diminta).
+func hasIndex(ctx context.Context, coll *mongo.Collection, key string, unique bool) (bool, error) {
+	specs, err := coll.Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, spec := range specs {
+		var keys bson.D
+		if err := bson.Unmarshal(spec.KeysDocument, &keys); err != nil || len(keys) != 1 || keys[0].Key != key {
+			continue
+		}
+		if unique && (spec.Unique == nil || !*spec.Unique) {
+			continue
+		}
+		return true, nil
+	}
+	return false, nil
+}
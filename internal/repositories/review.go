@@ -0,0 +1,230 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReviewRepository interface {
+	// Create mengembalikan ErrDuplicate jika user sudah mengulas lokasi ini.
+	Create(ctx context.Context, r *models.Review) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Review, error)
+	// List mengembalikan ulasan yang tayang (Listed).
+	List(ctx context.Context, locationID primitive.ObjectID, p ListPage) ([]models.Review, int64, error)
+	// Summary hanya menghitung ulasan yang tayang.
+	Summary(ctx context.Context, locationID primitive.ObjectID) (models.RatingSummary, error)
+	// Recent mengembalikan ulasan semua status yang dibuat setelah since,
+	// terbaru dulu, untuk aturan spam.
+	Recent(ctx context.Context, since time.Time, limit int64) ([]models.Review, error)
+	// Pending mengembalikan ulasan yang ditahan. Jika locationIDs tidak nil,
+	// hanya ulasan untuk lokasi tersebut.
+	Pending(ctx context.Context, locationIDs []primitive.ObjectID, p ListPage) ([]models.Review, int64, error)
+	// Moderate mengubah ulasan yang moderation_status-nya salah satu dari
+	// from dan mengembalikan ulasan setelah diubah; ErrNotFound jika tidak
+	// ada yang cocok.
+	Moderate(ctx context.Context, id primitive.ObjectID, from []string, set Fields) (*models.Review, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	DeleteByLocation(ctx context.Context, locationID primitive.ObjectID) (int64, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+// ReviewList adalah sort dan batas ulasan satu lokasi.
+var ReviewList = ListSpec{
+	Sorts:        map[string]string{"created_at": "created_at"},
+	DefaultSort:  "-created_at",
+	DefaultLimit: 20,
+	MaxLimit:     100,
+}
+
+// PendingReviewList adalah sort dan batas antrean moderasi ulasan, yang
+// paling lama ditahan dulu.
+var PendingReviewList = ListSpec{
+	Sorts:        map[string]string{"created_at": "created_at"},
+	DefaultSort:  "created_at",
+	DefaultLimit: 20,
+	MaxLimit:     100,
+}
+
+type mongoReviewRepository struct {
+	coll *mongo.Collection
+}
+
+func NewReviewRepository(coll *mongo.Collection) ReviewRepository {
+	return &mongoReviewRepository{coll: coll}
+}
+
+func (r *mongoReviewRepository) Create(ctx context.Context, rev *models.Review) error {
+	if rev.ID.IsZero() {
+		rev.ID = primitive.NewObjectID()
+	}
+	_, err := r.coll.InsertOne(ctx, rev)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *mongoReviewRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Review, error) {
+	var rev models.Review
+	if err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&rev); err != nil {
+		return nil, notFound(err)
+	}
+	return &rev, nil
+}
+
+// listedReview mencocokkan ulasan tanpa status atau yang sudah approved,
+// sama dengan Review.Listed.
+var listedReview = bson.M{"$nin": bson.A{models.ModerationPending, models.ModerationRejected}}
+
+func (r *mongoReviewRepository) List(ctx context.Context, locationID primitive.ObjectID, p ListPage) ([]models.Review, int64, error) {
+	filter := bson.M{"location_id": locationID, "moderation_status": listedReview}
+	total, err := r.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	cursor, err := r.coll.Find(ctx, p.Match(filter), p.FindOptions())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+	reviews := []models.Review{}
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
+
+func (r *mongoReviewRepository) Summary(ctx context.Context, locationID primitive.ObjectID) (models.RatingSummary, error) {
+	var summary models.RatingSummary
+	pipeline := append(mongo.Pipeline{{{Key: "$match", Value: bson.M{"location_id": locationID, "moderation_status": listedReview}}}}, summaryStages()...)
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return summary, err
+	}
+	defer cursor.Close(ctx)
+	if cursor.Next(ctx) {
+		err = cursor.Decode(&summary)
+	}
+	if err == nil {
+		err = cursor.Err()
+	}
+	return summary, err
+}
+
+func (r *mongoReviewRepository) Recent(ctx context.Context, since time.Time, limit int64) ([]models.Review, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := r.coll.Find(ctx, bson.M{"created_at": bson.M{"$gt": since}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	reviews := []models.Review{}
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+func (r *mongoReviewRepository) Pending(ctx context.Context, locationIDs []primitive.ObjectID, p ListPage) ([]models.Review, int64, error) {
+	filter := bson.M{"moderation_status": models.ModerationPending}
+	if locationIDs != nil {
+		filter["location_id"] = bson.M{"$in": locationIDs}
+	}
+	total, err := r.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	cursor, err := r.coll.Find(ctx, p.Match(filter), p.FindOptions())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+	reviews := []models.Review{}
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
+
+func (r *mongoReviewRepository) Moderate(ctx context.Context, id primitive.ObjectID, from []string, set Fields) (*models.Review, error) {
+	var rev models.Review
+	err := r.coll.FindOneAndUpdate(ctx, bson.M{"_id": id, "moderation_status": bson.M{"$in": from}},
+		bson.M{"$set": bson.M(set)},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&rev)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &rev, nil
+}
+
+func (r *mongoReviewRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	res, err := r.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoReviewRepository) DeleteByLocation(ctx context.Context, locationID primitive.ObjectID) (int64, error) {
+	res, err := r.coll.DeleteMany(ctx, bson.M{"location_id": locationID})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+// Satu ulasan per user per lokasi dijaga oleh unique index. created_at
+// untuk aturan spam, moderation_status untuk antrean moderasi.
+func (r *mongoReviewRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "location_id", Value: 1}, {Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "location_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "moderation_status", Value: 1}, {Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}},
+	})
+	return err
+}
+
+// summaryStages mengelompokkan ulasan menjadi satu dokumen {average, count}.
+func summaryStages() []bson.D {
+	return []bson.D{
+		{{Key: "$group", Value: bson.M{"_id": nil, "average": bson.M{"$avg": "$rating"}, "count": bson.M{"$sum": 1}}}},
+		{{Key: "$project", Value: bson.M{"_id": 0, "average": bson.M{"$round": bson.A{"$average", 1}}, "count": 1}}},
+	}
+}
+
+// ratingStages menambahkan field rating ({average, count}) ke setiap
+// lokasi di pipeline dengan $lookup ke collection reviews. Lokasi tanpa
+// ulasan mendapat {average: 0, count: 0}.
+func ratingStages(reviews *mongo.Collection) []bson.D {
+	lookup := bson.A{bson.M{"$match": bson.M{
+		"$expr":             bson.M{"$eq": bson.A{"$location_id", "$$location_id"}},
+		"moderation_status": listedReview,
+	}}}
+	for _, stage := range summaryStages() {
+		lookup = append(lookup, stage)
+	}
+	return []bson.D{
+		{{Key: "$lookup", Value: bson.M{
+			"from":     reviews.Name(),
+			"let":      bson.M{"location_id": "$_id"},
+			"pipeline": lookup,
+			"as":       "rating",
+		}}},
+		{{Key: "$set", Value: bson.M{"rating": bson.M{"$ifNull": bson.A{
+			bson.M{"$arrayElemAt": bson.A{"$rating", 0}},
+			bson.M{"average": 0, "count": 0},
+		}}}}},
+	}
+}
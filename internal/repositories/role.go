@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RoleRepository interface {
+	List(ctx context.Context) ([]models.Role, error)
+	Get(ctx context.Context, name string) (*models.Role, error)
+	// Save membuat atau mengganti role.
+	Save(ctx context.Context, role models.Role) error
+	Delete(ctx context.Context, name string) error
+	// EnsureDefaults membuat role yang belum ada tanpa mengubah yang sudah ada.
+	EnsureDefaults(ctx context.Context, roles []models.Role) error
+}
+
+type mongoRoleRepository struct {
+	coll *mongo.Collection
+}
+
+func NewRoleRepository(coll *mongo.Collection) RoleRepository {
+	return &mongoRoleRepository{coll: coll}
+}
+
+func (r *mongoRoleRepository) List(ctx context.Context) ([]models.Role, error) {
+	cursor, err := r.coll.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	roles := []models.Role{}
+	if err := cursor.All(ctx, &roles); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *mongoRoleRepository) Get(ctx context.Context, name string) (*models.Role, error) {
+	var role models.Role
+	if err := r.coll.FindOne(ctx, bson.M{"_id": name}).Decode(&role); err != nil {
+		return nil, notFound(err)
+	}
+	return &role, nil
+}
+
+func (r *mongoRoleRepository) Save(ctx context.Context, role models.Role) error {
+	_, err := r.coll.ReplaceOne(ctx, bson.M{"_id": role.Name}, role, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *mongoRoleRepository) Delete(ctx context.Context, name string) error {
+	res, err := r.coll.DeleteOne(ctx, bson.M{"_id": name})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoRoleRepository) EnsureDefaults(ctx context.Context, roles []models.Role) error {
+	for _, role := range roles {
+		_, err := r.coll.UpdateOne(ctx, bson.M{"_id": role.Name},
+			bson.M{"$setOnInsert": bson.M{"description": role.Description, "permissions": role.Permissions}},
+			options.Update().SetUpsert(true))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
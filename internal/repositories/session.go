@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SessionsSeen menandai apakah user pernah login, dan pernah login dari
+// perangkat dan lokasi tertentu.
+type SessionsSeen struct {
+	Any, Device, Location bool
+}
+
+// SessionRepository menyimpan riwayat login (collection sessions).
+type SessionRepository interface {
+	Create(ctx context.Context, s *models.Session) error
+	// Seen memeriksa sesi user sebelumnya. Sesi yang dilaporkan "bukan
+	// saya" tidak dihitung, supaya perangkat penyusup tidak dianggap dikenal.
+	Seen(ctx context.Context, userID primitive.ObjectID, device, location string) (SessionsSeen, error)
+	// ListByUser mengembalikan sesi terbaru lebih dulu.
+	ListByUser(ctx context.Context, userID primitive.ObjectID, limit int64) ([]models.Session, error)
+	// ConsumeReport mencabut sesi lewat token "bukan saya" secara atomik;
+	// ErrNotFound jika token tidak ada, sudah dipakai, atau kedaluwarsa.
+	ConsumeReport(ctx context.Context, tokenHash string, now time.Time) (*models.Session, error)
+	// RevokeUser menandai semua sesi user yang belum dicabut.
+	RevokeUser(ctx context.Context, userID primitive.ObjectID, at time.Time, reason string) error
+	EnsureIndexes(ctx context.Context) error
+}
+
+type mongoSessionRepository struct {
+	coll *mongo.Collection
+}
+
+func NewSessionRepository(coll *mongo.Collection) SessionRepository {
+	return &mongoSessionRepository{coll: coll}
+}
+
+func (r *mongoSessionRepository) Create(ctx context.Context, s *models.Session) error {
+	if s.ID.IsZero() {
+		s.ID = primitive.NewObjectID()
+	}
+	_, err := r.coll.InsertOne(ctx, s)
+	return err
+}
+
+func (r *mongoSessionRepository) Seen(ctx context.Context, userID primitive.ObjectID, device, location string) (SessionsSeen, error) {
+	exists := func(field, value string) (bool, error) {
+		filter := bson.M{"user_id": userID, "revoked_reason": bson.M{"$ne": models.SessionReported}}
+		if field != "" {
+			filter[field] = value
+		}
+		n, err := r.coll.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+		return n > 0, err
+	}
+	var seen SessionsSeen
+	var err error
+	if seen.Any, err = exists("", ""); err != nil || !seen.Any {
+		return seen, err
+	}
+	if seen.Device, err = exists("device", device); err != nil {
+		return seen, err
+	}
+	seen.Location, err = exists("location", location)
+	return seen, err
+}
+
+func (r *mongoSessionRepository) ListByUser(ctx context.Context, userID primitive.ObjectID, limit int64) ([]models.Session, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := r.coll.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	sessions := []models.Session{}
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *mongoSessionRepository) ConsumeReport(ctx context.Context, tokenHash string, now time.Time) (*models.Session, error) {
+	var s models.Session
+	err := r.coll.FindOneAndUpdate(ctx,
+		bson.M{"report_token_hash": tokenHash, "report_expires_at": bson.M{"$gt": now}},
+		bson.M{
+			"$set":   bson.M{"revoked_at": now, "revoked_reason": models.SessionReported},
+			"$unset": bson.M{"report_token_hash": "", "report_expires_at": ""},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&s)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &s, nil
+}
+
+func (r *mongoSessionRepository) RevokeUser(ctx context.Context, userID primitive.ObjectID, at time.Time, reason string) error {
+	_, err := r.coll.UpdateMany(ctx, bson.M{"user_id": userID, "revoked_at": nil},
+		bson.M{"$set": bson.M{"revoked_at": at, "revoked_reason": reason}})
+	return err
+}
+
+// Index riwayat per user, token laporan, dan TTL riwayat
+func (r *mongoSessionRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "device", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "location", Value: 1}}},
+		{Keys: bson.D{{Key: "report_token_hash", Value: 1}}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SettingsRepository menyimpan pengaturan aplikasi sebagai dokumen
+// {_id: key, value: ...} di collection settings.
+type SettingsRepository interface {
+	// Get men-decode field value ke out; ErrNotFound jika belum pernah diset.
+	Get(ctx context.Context, key string, out interface{}) error
+	Put(ctx context.Context, key string, value interface{}, updatedBy string) error
+}
+
+type mongoSettingsRepository struct {
+	coll *mongo.Collection
+}
+
+func NewSettingsRepository(coll *mongo.Collection) SettingsRepository {
+	return &mongoSettingsRepository{coll: coll}
+}
+
+func (r *mongoSettingsRepository) Get(ctx context.Context, key string, out interface{}) error {
+	var doc struct {
+		Value bson.Raw `bson:"value"`
+	}
+	if err := r.coll.FindOne(ctx, bson.M{"_id": key}).Decode(&doc); err != nil {
+		return notFound(err)
+	}
+	return bson.Unmarshal(doc.Value, out)
+}
+
+func (r *mongoSettingsRepository) Put(ctx context.Context, key string, value interface{}, updatedBy string) error {
+	_, err := r.coll.UpdateOne(ctx, bson.M{"_id": key},
+		bson.M{"$set": bson.M{"value": value, "updated_by": updatedBy, "updated_at": time.Now()}},
+		options.Update().SetUpsert(true))
+	return err
+}
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CategorySuggestionRepository interface {
+	Create(ctx context.Context, s *models.CategorySuggestion) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.CategorySuggestion, error)
+	// List mengembalikan usulan berstatus status; kosong = semua status.
+	List(ctx context.Context, status string, p ListPage) ([]models.CategorySuggestion, int64, error)
+	// CountPending menghitung usulan pending milik email.
+	CountPending(ctx context.Context, email string) (int64, error)
+	// Resolve mengubah usulan yang masih pending dan mengembalikan hasilnya;
+	// ErrNotFound jika tidak ada atau sudah diputuskan.
+	Resolve(ctx context.Context, id primitive.ObjectID, set Fields) (*models.CategorySuggestion, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+// CategorySuggestionList adalah sort dan batas antrean usulan kategori,
+// yang paling lama diusulkan dulu.
+var CategorySuggestionList = ListSpec{
+	Sorts:        map[string]string{"created_at": "_id"},
+	DefaultSort:  "created_at",
+	DefaultLimit: 20,
+	MaxLimit:     100,
+}
+
+type mongoCategorySuggestionRepository struct {
+	coll *mongo.Collection
+}
+
+func NewCategorySuggestionRepository(coll *mongo.Collection) CategorySuggestionRepository {
+	return &mongoCategorySuggestionRepository{coll: coll}
+}
+
+func (r *mongoCategorySuggestionRepository) Create(ctx context.Context, s *models.CategorySuggestion) error {
+	if s.ID.IsZero() {
+		s.ID = primitive.NewObjectID()
+	}
+	_, err := r.coll.InsertOne(ctx, s)
+	return err
+}
+
+func (r *mongoCategorySuggestionRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.CategorySuggestion, error) {
+	var s models.CategorySuggestion
+	if err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&s); err != nil {
+		return nil, notFound(err)
+	}
+	return &s, nil
+}
+
+func (r *mongoCategorySuggestionRepository) List(ctx context.Context, status string, p ListPage) ([]models.CategorySuggestion, int64, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	total, err := r.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	cursor, err := r.coll.Find(ctx, p.Match(filter), p.FindOptions())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+	suggestions := []models.CategorySuggestion{}
+	if err := cursor.All(ctx, &suggestions); err != nil {
+		return nil, 0, err
+	}
+	return suggestions, total, nil
+}
+
+func (r *mongoCategorySuggestionRepository) CountPending(ctx context.Context, email string) (int64, error) {
+	return r.coll.CountDocuments(ctx, bson.M{"suggested_by": email, "status": models.SuggestionPending})
+}
+
+func (r *mongoCategorySuggestionRepository) Resolve(ctx context.Context, id primitive.ObjectID, set Fields) (*models.CategorySuggestion, error) {
+	var s models.CategorySuggestion
+	err := r.coll.FindOneAndUpdate(ctx, bson.M{"_id": id, "status": models.SuggestionPending}, bson.M{"$set": bson.M(set)},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&s)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &s, nil
+}
+
+// Antrean dibaca per status urut _id; kuota pending per pengusul
+func (r *mongoCategorySuggestionRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "suggested_by", Value: 1}, {Key: "status", Value: 1}}},
+	})
+	return err
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Jumlah dokumen per InsertMany saat import GTFS
+const transitInsertBatch = 1000
+
+// TransitNearbyQuery adalah parameter pencarian halte terdekat.
+type TransitNearbyQuery struct {
+	Lat, Lng float64
+	RadiusM  float64
+	Limit    int
+}
+
+type TransitRepository interface {
+	// ReplaceFeed menghapus semua halte feed lalu menyimpan stops.
+	ReplaceFeed(ctx context.Context, feed string, stops []models.TransitStop) error
+	Nearby(ctx context.Context, q TransitNearbyQuery) ([]models.NearbyTransitStop, error)
+	// EnsureIndexes membuat index 2dsphere koordinat dan index feed.
+	EnsureIndexes(ctx context.Context) error
+}
+
+type mongoTransitRepository struct {
+	coll *mongo.Collection
+}
+
+func NewTransitRepository(coll *mongo.Collection) TransitRepository {
+	return &mongoTransitRepository{coll: coll}
+}
+
+func (r *mongoTransitRepository) ReplaceFeed(ctx context.Context, feed string, stops []models.TransitStop) error {
+	if _, err := r.coll.DeleteMany(ctx, bson.M{"feed": feed}); err != nil {
+		return err
+	}
+	for start := 0; start < len(stops); start += transitInsertBatch {
+		end := min(start+transitInsertBatch, len(stops))
+		docs := make([]interface{}, 0, end-start)
+		for _, s := range stops[start:end] {
+			docs = append(docs, s)
+		}
+		if _, err := r.coll.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *mongoTransitRepository) Nearby(ctx context.Context, q TransitNearbyQuery) ([]models.NearbyTransitStop, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$geoNear", Value: bson.M{
+			"near":          bson.M{"type": "Point", "coordinates": bson.A{q.Lng, q.Lat}},
+			"distanceField": "distance_m",
+			"maxDistance":   q.RadiusM,
+			"spherical":     true,
+		}}},
+		{{Key: "$limit", Value: q.Limit}},
+	}
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	results := []models.NearbyTransitStop{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *mongoTransitRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "coordinates", Value: "2dsphere"}}},
+		{Keys: bson.D{{Key: "feed", Value: 1}}},
+	})
+	return err
+}
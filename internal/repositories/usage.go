@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// UsageRepository menyimpan jumlah request API per consumer, endpoint dan
+// tanggal (collection api_usage, satu dokumen per kombinasi).
+type UsageRepository interface {
+	// Add menambahkan counter ke dokumen dengan date, consumer, method dan
+	// route yang sama (dibuat jika belum ada).
+	Add(ctx context.Context, counters []models.UsageCounter) error
+	// Totals menjumlahkan counter tanggal from..to (inklusif) per consumer
+	// dan endpoint. consumer kosong = semua consumer.
+	Totals(ctx context.Context, from, to, consumer string) ([]models.UsageCounter, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+type mongoUsageRepository struct {
+	coll *mongo.Collection
+}
+
+func NewUsageRepository(coll *mongo.Collection) UsageRepository {
+	return &mongoUsageRepository{coll: coll}
+}
+
+func (r *mongoUsageRepository) Add(ctx context.Context, counters []models.UsageCounter) error {
+	if len(counters) == 0 {
+		return nil
+	}
+	writes := make([]mongo.WriteModel, len(counters))
+	for i, c := range counters {
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"date": c.Date, "consumer": c.Consumer, "method": c.Method, "route": c.Route}).
+			SetUpdate(bson.M{
+				"$setOnInsert": bson.M{"consumer_type": c.ConsumerType},
+				"$inc": bson.M{"requests": c.Requests, "client_errors": c.ClientErrors, "server_errors": c.ServerErrors,
+					"bytes_in": c.BytesIn, "bytes_out": c.BytesOut},
+			}).
+			SetUpsert(true)
+	}
+	_, err := r.coll.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (r *mongoUsageRepository) Totals(ctx context.Context, from, to, consumer string) ([]models.UsageCounter, error) {
+	match := bson.M{"date": bson.M{"$gte": from, "$lte": to}}
+	if consumer != "" {
+		match["consumer"] = consumer
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":           bson.M{"consumer": "$consumer", "method": "$method", "route": "$route"},
+			"consumer_type": bson.M{"$first": "$consumer_type"},
+			"requests":      bson.M{"$sum": "$requests"},
+			"client_errors": bson.M{"$sum": "$client_errors"},
+			"server_errors": bson.M{"$sum": "$server_errors"},
+			"bytes_in":      bson.M{"$sum": "$bytes_in"},
+			"bytes_out":     bson.M{"$sum": "$bytes_out"},
+		}}},
+		{{Key: "$project", Value: bson.M{"_id": 0, "consumer": "$_id.consumer", "method": "$_id.method", "route": "$_id.route",
+			"consumer_type": 1, "requests": 1, "client_errors": 1, "server_errors": 1, "bytes_in": 1, "bytes_out": 1}}},
+	}
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	totals := []models.UsageCounter{}
+	if err := cursor.All(ctx, &totals); err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+func (r *mongoUsageRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "date", Value: 1}, {Key: "consumer", Value: 1}, {Key: "method", Value: 1}, {Key: "route", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
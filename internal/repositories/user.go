@@ -0,0 +1,250 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type UserRepository interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	List(ctx context.Context) ([]models.User, error)
+	Create(ctx context.Context, u *models.User) error
+	Update(ctx context.Context, id primitive.ObjectID, set Fields) error
+	// IncrementFailedLogins menambah hitungan login gagal dan mengembalikan
+	// nilai barunya.
+	IncrementFailedLogins(ctx context.Context, id primitive.ObjectID) (int, error)
+	// SetRole mengembalikan data user sebelum role diubah.
+	SetRole(ctx context.Context, id primitive.ObjectID, role string) (*models.User, error)
+	// SetLocationQuota menghapus override jika quota nil.
+	SetLocationQuota(ctx context.Context, id primitive.ObjectID, quota *int) error
+	// Delete mengembalikan data user yang dihapus.
+	Delete(ctx context.Context, id primitive.ObjectID) (*models.User, error)
+	CountPlaintextPasswords(ctx context.Context) (int64, error)
+	CountByRole(ctx context.Context, role string) (int64, error)
+	HasIndex(ctx context.Context, key string, unique bool) (bool, error)
+	// CountSegment menghitung user yang masuk segmen campaign per waktu now.
+	CountSegment(ctx context.Context, seg models.CampaignSegment, now time.Time) (int64, error)
+	// FindSegment membaca user dalam segmen urut _id, mulai setelah afterID
+	// (NilObjectID = dari awal), supaya segmen besar bisa dibaca bertahap.
+	FindSegment(ctx context.Context, seg models.CampaignSegment, now time.Time, afterID primitive.ObjectID, limit int64) ([]models.User, error)
+	// WithFreshnessAlerts membaca user yang berlangganan peringatan
+	// kesegaran, urut _id setelah afterID.
+	WithFreshnessAlerts(ctx context.Context, afterID primitive.ObjectID, limit int64) ([]models.User, error)
+	// MissingEmails mengembalikan email di emails yang tidak dimiliki user
+	// mana pun.
+	MissingEmails(ctx context.Context, emails []string) ([]string, error)
+}
+
+type mongoUserRepository struct {
+	coll *mongo.Collection
+}
+
+func NewUserRepository(coll *mongo.Collection) UserRepository {
+	return &mongoUserRepository{coll: coll}
+}
+
+func (r *mongoUserRepository) findOne(ctx context.Context, filter bson.M) (*models.User, error) {
+	var u models.User
+	if err := r.coll.FindOne(ctx, filter).Decode(&u); err != nil {
+		return nil, notFound(err)
+	}
+	return &u, nil
+}
+
+func (r *mongoUserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *mongoUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.findOne(ctx, bson.M{"email": email})
+}
+
+func (r *mongoUserRepository) List(ctx context.Context) ([]models.User, error) {
+	cursor, err := r.coll.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	users := []models.User{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *mongoUserRepository) Create(ctx context.Context, u *models.User) error {
+	if u.ID.IsZero() {
+		u.ID = primitive.NewObjectID()
+	}
+	_, err := r.coll.InsertOne(ctx, u)
+	return err
+}
+
+func (r *mongoUserRepository) Update(ctx context.Context, id primitive.ObjectID, set Fields) error {
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M(set)})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoUserRepository) IncrementFailedLogins(ctx context.Context, id primitive.ObjectID) (int, error) {
+	var after models.User
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := r.coll.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"failed_logins": 1}}, opts).Decode(&after)
+	if err != nil {
+		return 0, notFound(err)
+	}
+	return after.FailedLogins, nil
+}
+
+func (r *mongoUserRepository) SetRole(ctx context.Context, id primitive.ObjectID, role string) (*models.User, error) {
+	var before models.User
+	err := r.coll.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"role": role}}).Decode(&before)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &before, nil
+}
+
+func (r *mongoUserRepository) CountByRole(ctx context.Context, role string) (int64, error) {
+	return r.coll.CountDocuments(ctx, bson.M{"role": role})
+}
+
+func (r *mongoUserRepository) SetLocationQuota(ctx context.Context, id primitive.ObjectID, quota *int) error {
+	update := bson.M{"$unset": bson.M{"location_quota": ""}}
+	if quota != nil {
+		update = bson.M{"$set": bson.M{"location_quota": *quota}}
+	}
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoUserRepository) Delete(ctx context.Context, id primitive.ObjectID) (*models.User, error) {
+	var deleted models.User
+	if err := r.coll.FindOneAndDelete(ctx, bson.M{"_id": id}).Decode(&deleted); err != nil {
+		return nil, notFound(err)
+	}
+	return &deleted, nil
+}
+
+// Password yang belum berbentuk hash bcrypt ($2a$/$2b$/$2y$) dianggap plaintext
+func (r *mongoUserRepository) CountPlaintextPasswords(ctx context.Context) (int64, error) {
+	filter := bson.M{"password": bson.M{"$not": bson.M{"$regex": `^\$2[aby]\$`}}}
+	return r.coll.CountDocuments(ctx, filter)
+}
+
+func (r *mongoUserRepository) HasIndex(ctx context.Context, key string, unique bool) (bool, error) {
+	return hasIndex(ctx, r.coll, key, unique)
+}
+
+// segmentFilter menerjemahkan segmen campaign ke filter user. Tanggal daftar
+// dibaca dari timestamp _id, aktivitas dari last_login_at.
+func segmentFilter(seg models.CampaignSegment, now time.Time) bson.M {
+	filter := bson.M{}
+	if len(seg.Roles) > 0 {
+		filter["role"] = bson.M{"$in": seg.Roles}
+	}
+	id := bson.M{}
+	if seg.SignedUpAfter != nil {
+		id["$gte"] = primitive.NewObjectIDFromTimestamp(*seg.SignedUpAfter)
+	}
+	if seg.SignedUpBefore != nil {
+		id["$lt"] = primitive.NewObjectIDFromTimestamp(*seg.SignedUpBefore)
+	}
+	if len(id) > 0 {
+		filter["_id"] = id
+	}
+	login := bson.M{}
+	if seg.ActiveWithinDays > 0 {
+		login["$gte"] = now.AddDate(0, 0, -seg.ActiveWithinDays)
+	}
+	if seg.InactiveForDays > 0 {
+		// Field yang tidak ada tidak cocok dengan $lt, jadi user yang belum
+		// pernah tercatat login ditambahkan lewat $or
+		cutoff := now.AddDate(0, 0, -seg.InactiveForDays)
+		filter["$or"] = bson.A{
+			bson.M{"last_login_at": bson.M{"$lt": cutoff}},
+			bson.M{"last_login_at": nil},
+		}
+	}
+	if len(login) > 0 {
+		filter["last_login_at"] = login
+	}
+	return filter
+}
+
+func (r *mongoUserRepository) CountSegment(ctx context.Context, seg models.CampaignSegment, now time.Time) (int64, error) {
+	return r.coll.CountDocuments(ctx, segmentFilter(seg, now))
+}
+
+func (r *mongoUserRepository) FindSegment(ctx context.Context, seg models.CampaignSegment, now time.Time, afterID primitive.ObjectID, limit int64) ([]models.User, error) {
+	filter := segmentFilter(seg, now)
+	if !afterID.IsZero() {
+		// Gabungkan dengan batas tanggal daftar yang mungkin sudah ada di _id
+		filter = bson.M{"$and": bson.A{filter, bson.M{"_id": bson.M{"$gt": afterID}}}}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit)
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	users := []models.User{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *mongoUserRepository) WithFreshnessAlerts(ctx context.Context, afterID primitive.ObjectID, limit int64) ([]models.User, error) {
+	filter := bson.M{"preferences.alerts.freshness_below": bson.M{"$gt": 0}, "_id": bson.M{"$gt": afterID}}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit)
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	users := []models.User{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *mongoUserRepository) MissingEmails(ctx context.Context, emails []string) ([]string, error) {
+	found, err := r.coll.Distinct(ctx, "email", bson.M{"email": bson.M{"$in": emails}})
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(found))
+	for _, e := range found {
+		if email, ok := e.(string); ok {
+			known[email] = true
+		}
+	}
+	missing := []string{}
+	for _, email := range emails {
+		if !known[email] {
+			missing = append(missing, email)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,136 @@
+// Package schema mencatat versi bentuk dokumen Mongo (field schema_version)
+// beserta langkah upgrade dari versi lama. Dokumen lama di-upgrade saat
+// dibaca lalu ditulis ulang bertahap oleh job backfill, jadi perubahan
+// bentuk model tidak butuh migrasi besar sekaligus saat deploy.
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Field adalah nama field versi di setiap dokumen.
+const Field = "schema_version"
+
+// Step mengubah dokumen dari versi To-1 ke To. Dokumen tanpa
+// schema_version dianggap versi 1, padahal bisa saja sudah berbentuk
+// baru, jadi Apply wajib aman dijalankan pada dokumen yang sudah sesuai.
+type Step struct {
+	To          int
+	Description string
+	Apply       func(doc bson.M) error
+}
+
+// Schema adalah rangkaian upgrade untuk satu jenis dokumen.
+type Schema struct {
+	Name  string
+	steps []Step
+}
+
+var (
+	mu       sync.Mutex
+	registry = map[string]*Schema{}
+)
+
+// New mendaftarkan schema name. Step harus berurutan mulai dari To = 2;
+// salah urut atau nama ganda adalah bug program sehingga panic.
+func New(name string, steps ...Step) *Schema {
+	for i, step := range steps {
+		if step.To != i+2 || step.Apply == nil {
+			panic(fmt.Sprintf("schema %s: step ke-%d harus To=%d dengan Apply", name, i+1, i+2))
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := registry[name]; ok {
+		panic("schema " + name + " sudah terdaftar")
+	}
+	s := &Schema{Name: name, steps: steps}
+	registry[name] = s
+	return s
+}
+
+// All mengembalikan semua schema terdaftar, urut nama.
+func All() []*Schema {
+	mu.Lock()
+	defer mu.Unlock()
+	all := make([]*Schema, 0, len(registry))
+	for _, s := range registry {
+		all = append(all, s)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
+
+// Current adalah versi yang ditulis oleh kode saat ini.
+func (s *Schema) Current() int {
+	return len(s.steps) + 1
+}
+
+// Steps mengembalikan salinan daftar upgrade.
+func (s *Schema) Steps() []Step {
+	return append([]Step(nil), s.steps...)
+}
+
+// Version membaca schema_version dokumen; 1 jika tidak ada.
+func Version(raw bson.Raw) int {
+	if v, ok := raw.Lookup(Field).AsInt64OK(); ok && v > 0 {
+		return int(v)
+	}
+	return 1
+}
+
+// versionOf membaca schema_version hasil decode ke bson.M.
+func versionOf(v interface{}) int {
+	var n int64
+	switch v := v.(type) {
+	case int32:
+		n = int64(v)
+	case int64:
+		n = v
+	case int:
+		n = int64(v)
+	case float64:
+		n = int64(v)
+	}
+	if n > 0 {
+		return int(n)
+	}
+	return 1
+}
+
+// Upgrade menjalankan step dari versi doc sampai Current lalu mengisi
+// schema_version. Dokumen dari versi yang lebih baru (mis. saat rolling
+// deploy) dibiarkan. Mengembalikan false jika tidak ada yang diubah.
+func (s *Schema) Upgrade(doc bson.M) (bool, error) {
+	version := versionOf(doc[Field])
+	if version >= s.Current() {
+		return false, nil
+	}
+	for _, step := range s.steps[version-1:] {
+		if err := step.Apply(doc); err != nil {
+			return false, fmt.Errorf("schema %s v%d: %w", s.Name, step.To, err)
+		}
+	}
+	doc[Field] = s.Current()
+	return true, nil
+}
+
+// UpgradeRaw sama seperti Upgrade untuk dokumen BSON mentah. data
+// dikembalikan apa adanya jika sudah versi terbaru, tanpa decode.
+func (s *Schema) UpgradeRaw(data []byte) ([]byte, error) {
+	if Version(data) >= s.Current() {
+		return data, nil
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if _, err := s.Upgrade(doc); err != nil {
+		return nil, err
+	}
+	return bson.Marshal(doc)
+}
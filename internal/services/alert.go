@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/mailer"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultAlertBatch = 100
+	maxAlertBatch     = 500
+	// Lokasi maksimum dalam satu email; sisanya ikut di job berikutnya
+	maxAlertLocations = 50
+)
+
+// AlertService mengirim peringatan kualitas data ke pemilik lokasi yang
+// berlangganan lewat preferensi (alerts). Saat ini hanya skor kesegaran
+// yang dipantau.
+type AlertService struct {
+	users     repositories.UserRepository
+	locations repositories.LocationRepository
+	mail      mailer.Mailer
+	halfLife  time.Duration
+}
+
+// NewAlertService memakai half-life yang sama dengan skor kesegaran
+// lokasi (0 = DefaultFreshnessHalfLife).
+func NewAlertService(users repositories.UserRepository, locations repositories.LocationRepository, mail mailer.Mailer, halfLife time.Duration) *AlertService {
+	if halfLife <= 0 {
+		halfLife = DefaultFreshnessHalfLife
+	}
+	return &AlertService{users: users, locations: locations, mail: mail, halfLife: halfLife}
+}
+
+// freshnessCutoff mengembalikan batas waktu konfirmasi terakhir: lokasi
+// yang dikonfirmasi sebelum ini skornya di bawah threshold.
+func (s *AlertService) freshnessCutoff(now time.Time, threshold float64) time.Time {
+	age := float64(s.halfLife) * math.Log2(1/threshold)
+	return now.Add(-time.Duration(age))
+}
+
+// FreshnessAlerts memproses satu batch user setelah after (urut ID): setiap
+// user menerima satu email berisi lokasinya yang skor kesegarannya turun di
+// bawah threshold pilihannya. Lokasi yang sudah diperingatkan tidak
+// dikirim lagi sampai dikonfirmasi ulang. Kegagalan kirim hanya dicatat dan
+// lokasinya dicoba lagi di job berikutnya.
+func (s *AlertService) FreshnessAlerts(ctx context.Context, after primitive.ObjectID, batch int) (models.FreshnessAlertResult, error) {
+	var result models.FreshnessAlertResult
+	if batch <= 0 {
+		batch = defaultAlertBatch
+	}
+	if batch > maxAlertBatch {
+		batch = maxAlertBatch
+	}
+	users, err := s.users.WithFreshnessAlerts(ctx, after, int64(batch))
+	if err != nil {
+		return result, err
+	}
+	now := time.Now().UTC()
+	for _, u := range users {
+		threshold := u.Preferences.Alerts.FreshnessBelow
+		locations, err := s.locations.FreshnessAlertCandidates(ctx, u.Email, s.freshnessCutoff(now, threshold), maxAlertLocations)
+		if err != nil {
+			return result, err
+		}
+		result.Users++
+		if len(locations) == 0 {
+			continue
+		}
+		if err := s.mail.Send(ctx, s.freshnessMessage(u.Email, threshold, locations, now)); err != nil {
+			log.Println("email peringatan kesegaran ke", u.Email+":", err)
+			continue
+		}
+		ids := make([]primitive.ObjectID, len(locations))
+		for i, loc := range locations {
+			ids[i] = loc.ID
+		}
+		if err := s.locations.MarkFreshnessAlerted(ctx, ids, now); err != nil {
+			return result, err
+		}
+		result.Emails++
+		result.Locations += len(locations)
+	}
+	if len(users) == batch {
+		result.Next = users[len(users)-1].ID.Hex()
+	}
+	return result, nil
+}
+
+func (s *AlertService) freshnessMessage(to string, threshold float64, locations []models.Location, now time.Time) mailer.Message {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Skor kesegaran %d lokasi Anda turun di bawah %.0f%%:\n\n", len(locations), threshold*100)
+	for _, loc := range locations {
+		fmt.Fprintf(&b, "- %s (%.0f%%)\n", loc.Name, loc.FreshnessAt(now, s.halfLife)*100)
+	}
+	b.WriteString("\nBuka lokasi tersebut lalu konfirmasi bahwa datanya masih akurat atau perbarui datanya.\n")
+	b.WriteString("Peringatan ini bisa dimatikan di preferensi akun (alerts).")
+	return mailer.Message{To: to, Subject: "Data lokasi Anda perlu diperbarui", Body: b.String(), Bulk: true}
+}
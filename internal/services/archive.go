@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/rbac"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// DefaultArchiveAfter dipakai jika LocationOptions.ArchiveAfter kosong
+	DefaultArchiveAfter = 2 * 365 * 24 * time.Hour
+	defaultArchiveBatch = 200
+	maxArchiveBatch     = 1000
+)
+
+// ArchiveStale memindahkan satu batch lokasi yang tutup permanen atau tidak
+// diubah maupun dikonfirmasi selama ArchiveAfter ke arsip. Lokasi di arsip
+// tidak muncul di query biasa kecuali dengan ?include_archived=true.
+// Dipanggil berulang (mis. cron) sampai Remaining = 0.
+func (s *LocationService) ArchiveStale(ctx context.Context, batch int) (models.ArchiveResult, error) {
+	if batch <= 0 {
+		batch = defaultArchiveBatch
+	}
+	if batch > maxArchiveBatch {
+		batch = maxArchiveBatch
+	}
+	after := s.opts.ArchiveAfter
+	if after <= 0 {
+		after = DefaultArchiveAfter
+	}
+	now := time.Now().UTC()
+	result := models.ArchiveResult{Before: now.Add(-after)}
+	locations, err := s.locations.ArchiveCandidates(ctx, result.Before, int64(batch))
+	if err != nil {
+		return result, err
+	}
+	if err := s.locations.Archive(ctx, locations, now); err != nil {
+		return result, err
+	}
+	events := make([]AuditEvent, len(locations))
+	ids := make([]primitive.ObjectID, len(locations))
+	for i, loc := range locations {
+		events[i] = AuditEvent{Action: "location.archive", ResourceType: AuditLocation, ResourceID: loc.ID.Hex()}
+		ids[i] = loc.ID
+	}
+	s.audit.Record(ctx, events...)
+	s.opts.Events.Publish(ctx, "location.archive", ids...)
+	result.Archived = len(locations)
+	result.Remaining, err = s.locations.CountArchiveCandidates(ctx, result.Before)
+	return result, err
+}
+
+// Unarchive mengembalikan lokasi dari arsip ke data utama. Permission dicek
+// di route; scope role dicek di sini.
+func (s *LocationService) Unarchive(ctx context.Context, u models.User, id primitive.ObjectID) (*models.Location, error) {
+	archived, err := s.locations.FindArchivedWithRating(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrLocationNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if !s.roles.CanModerate(ctx, u.Role, rbac.LocationsModerate, archived) {
+		return nil, ErrForbidden
+	}
+	loc, err := s.locations.Unarchive(ctx, id, time.Now().UTC())
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrLocationNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "location.unarchive", ResourceType: AuditLocation, ResourceID: id.Hex(), After: loc})
+	s.opts.Events.Publish(ctx, "location.unarchive", id)
+	return loc, nil
+}
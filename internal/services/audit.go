@@ -0,0 +1,457 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"InfoCuy-Backend/internal/audit"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/objectstore"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Resource type pada audit log
+const (
+	AuditLocation     = "location"
+	AuditUser         = "user"
+	AuditRole         = "role"
+	AuditCategory     = "category"
+	AuditSettings     = "settings"
+	AuditTransit      = "transit"
+	AuditRegion       = "region"
+	AuditPostcode     = "postcode"
+	AuditReview       = "review"
+	AuditCampaign     = "campaign"
+	AuditReassignment = "reassignment"
+	AuditExport       = "export"
+	AuditMail         = "mail"
+	// Usulan kategori dari user
+	AuditCategorySuggestion = "category_suggestion"
+	// Arsip audit log di object storage
+	AuditArchive = "audit_archive"
+	// Job geocoding baris import tanpa koordinat
+	AuditImportJob = "import_job"
+	// Temuan pemeriksaan integritas data
+	AuditIntegrity = "integrity_issue"
+)
+
+// AuditEvent adalah satu perubahan yang akan dicatat. Before nil untuk
+// create, After nil untuk delete.
+type AuditEvent struct {
+	Action       string
+	ResourceType string
+	ResourceID   string
+	Before       interface{}
+	After        interface{}
+	// Kosong = disusun dari action, resource dan field yang berubah
+	Summary string
+}
+
+const (
+	// Percobaan menambah chain saat seq direbut instance lain
+	maxAuditAppendAttempts = 5
+	auditVerifyBatch       = 1000
+	// Field berubah yang ditulis di summary
+	maxSummaryFields = 10
+)
+
+// AuditOptions mengatur arsip audit log. Tanpa Retention atau tanpa
+// object store S3 job arsip menolak berjalan dan semua catatan tetap di
+// database.
+type AuditOptions struct {
+	// Umur catatan sebelum boleh dipindah ke arsip (AUDIT_RETENTION)
+	Retention time.Duration
+	Archive   objectstore.Store
+}
+
+// AuditService mencatat audit log sebagai hash chain: setiap catatan memuat
+// hash catatan sebelumnya, jadi mengubah, menghapus atau menyisipkan
+// catatan terdeteksi oleh Verify.
+type AuditService struct {
+	repo repositories.AuditRepository
+	opts AuditOptions
+	// Menyerialkan penambahan chain di proses ini; antar instance dijaga
+	// index unik seq
+	mu sync.Mutex
+}
+
+func NewAuditService(repo repositories.AuditRepository, opts AuditOptions) *AuditService {
+	return &AuditService{repo: repo, opts: opts}
+}
+
+// Record menyimpan event beserta actor dari context. Kegagalan hanya
+// dicatat ke log supaya operasi utama tidak ikut gagal. Aman dipanggil
+// pada receiver nil (audit nonaktif).
+func (s *AuditService) Record(ctx context.Context, events ...AuditEvent) {
+	if s == nil || len(events) == 0 {
+		return
+	}
+	actor := audit.ActorFrom(ctx)
+	// Presisi BSON, supaya hash sama setelah dibaca ulang
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	logs := make([]models.AuditLog, len(events))
+	for i, ev := range events {
+		changes := audit.Diff(ev.Before, ev.After)
+		if ev.Summary == "" {
+			ev.Summary = auditSummary(ev, changes)
+		}
+		logs[i] = models.AuditLog{
+			ID:           primitive.NewObjectID(),
+			Time:         now,
+			Actor:        actor.Email,
+			IP:           actor.IP,
+			Action:       ev.Action,
+			ResourceType: ev.ResourceType,
+			ResourceID:   ev.ResourceID,
+			Changes:      changes,
+			Summary:      ev.Summary,
+		}
+	}
+	// Operasi utama sudah berhasil; catatan tetap disimpan walau client
+	// memutus koneksi sebelum response terkirim.
+	ctx = context.WithoutCancel(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var err error
+	for attempt := 0; attempt < maxAuditAppendAttempts; attempt++ {
+		if err = s.append(ctx, logs); !errors.Is(err, repositories.ErrDuplicate) {
+			break
+		}
+	}
+	if err != nil {
+		log.Printf("audit: gagal menyimpan %d catatan: %v", len(logs), err)
+	}
+}
+
+// append menyambung logs ke ujung chain saat ini.
+func (s *AuditService) append(ctx context.Context, logs []models.AuditLog) error {
+	var seq int64
+	var prev string
+	head, err := s.repo.Last(ctx)
+	if err == nil {
+		seq, prev = head.Seq, head.Hash
+	} else if !errors.Is(err, repositories.ErrNotFound) {
+		return err
+	} else if archive, err := s.repo.LastArchive(ctx); err == nil {
+		// Semua catatan sudah diarsip: chain berlanjut dari arsip terakhir
+		seq, prev = archive.ToSeq, archive.HeadHash
+	} else if !errors.Is(err, repositories.ErrNotFound) {
+		return err
+	}
+	for i := range logs {
+		seq++
+		logs[i].Seq, logs[i].PrevHash = seq, prev
+		logs[i].Hash = logs[i].ChainHash()
+		prev = logs[i].Hash
+	}
+	return s.repo.Insert(ctx, logs)
+}
+
+// auditSummary menyusun ringkasan yang bisa dicari dari event: action,
+// resource, nama resource (jika ada) dan field yang berubah.
+func auditSummary(ev AuditEvent, changes map[string]audit.Change) string {
+	var b strings.Builder
+	b.WriteString(ev.Action)
+	if ev.ResourceType != "" {
+		b.WriteString(" " + ev.ResourceType)
+		if ev.ResourceID != "" {
+			b.WriteString("/" + ev.ResourceID)
+		}
+	}
+	label := audit.Label(ev.After)
+	if label == "" {
+		label = audit.Label(ev.Before)
+	}
+	if label != "" {
+		fmt.Fprintf(&b, " %q", label)
+	}
+	fields := make([]string, 0, len(changes))
+	for k := range changes {
+		fields = append(fields, k)
+	}
+	slices.Sort(fields)
+	if len(fields) > maxSummaryFields {
+		fields = append(fields[:maxSummaryFields], "...")
+	}
+	if len(fields) > 0 {
+		b.WriteString(": " + strings.Join(fields, ", "))
+	}
+	return b.String()
+}
+
+// Verify menelusuri chain dari seq 1 (atau dari arsip terakhir) dan berhenti
+// pada catatan pertama yang tidak cocok. Catatan dari sebelum chain
+// diaktifkan (tanpa seq) dilewati.
+func (s *AuditService) Verify(ctx context.Context) (models.AuditVerifyResult, error) {
+	var result models.AuditVerifyResult
+	if archive, err := s.repo.LastArchive(ctx); err == nil {
+		result.ArchivedSeq, result.HeadSeq, result.HeadHash = archive.ToSeq, archive.ToSeq, archive.HeadHash
+	} else if !errors.Is(err, repositories.ErrNotFound) {
+		return result, err
+	}
+	for {
+		logs, err := s.repo.Chain(ctx, result.HeadSeq, auditVerifyBatch)
+		if err != nil {
+			return result, err
+		}
+		for i := range logs {
+			l := &logs[i]
+			reason := ""
+			switch {
+			case l.Seq != result.HeadSeq+1:
+				result.Broken = &models.AuditChainBreak{Seq: result.HeadSeq + 1, Reason: models.ChainMissing}
+				return result, nil
+			case l.ChainHash() != l.Hash:
+				reason = models.ChainHashMismatch
+			case l.PrevHash != result.HeadHash:
+				reason = models.ChainPrevMismatch
+			}
+			if reason != "" {
+				result.Broken = &models.AuditChainBreak{Seq: l.Seq, ID: &l.ID, Reason: reason}
+				return result, nil
+			}
+			result.Checked++
+			result.HeadSeq, result.HeadHash = l.Seq, l.Hash
+		}
+		if len(logs) < auditVerifyBatch {
+			result.OK = true
+			return result, nil
+		}
+	}
+}
+
+// AuditParams adalah query GET /admin/audit-logs. From/To berformat
+// RFC3339 atau YYYY-MM-DD (To inklusif sampai akhir hari).
+type AuditParams struct {
+	Actor        string
+	Action       string
+	ResourceType string
+	ResourceID   string
+	// Pencarian bebas di actor, action, resource dan summary
+	Q        string
+	From, To string
+	List     models.ListOptions
+}
+
+// query memeriksa tanggal p dan menyusun filter repository tanpa halaman.
+func (p AuditParams) query() (repositories.AuditQuery, error) {
+	from, err := parseAuditTime(p.From, false)
+	if err != nil {
+		return repositories.AuditQuery{}, err
+	}
+	to, err := parseAuditTime(p.To, true)
+	if err != nil {
+		return repositories.AuditQuery{}, err
+	}
+	if !from.IsZero() && !to.IsZero() && !to.After(from) {
+		return repositories.AuditQuery{}, invalid("to harus setelah from")
+	}
+	return repositories.AuditQuery{
+		Actor:        p.Actor,
+		Action:       p.Action,
+		ResourceType: p.ResourceType,
+		ResourceID:   p.ResourceID,
+		Text:         strings.TrimSpace(p.Q),
+		From:         from,
+		To:           to,
+	}, nil
+}
+
+func parseAuditTime(s string, endOfDay bool) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return t, invalid("Format tanggal harus YYYY-MM-DD atau RFC3339: %s", s)
+	}
+	if endOfDay {
+		t = t.Add(24 * time.Hour)
+	}
+	return t, nil
+}
+
+func (s *AuditService) List(ctx context.Context, p AuditParams) ([]models.AuditLog, models.PageMeta, error) {
+	q, err := p.query()
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	page, err := listPage(repositories.AuditList, p.List)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	q.ListPage = page
+	logs, total, err := s.repo.Find(ctx, q)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	return logs, repositories.Meta(page, total, logs), nil
+}
+
+const (
+	defaultAuditArchiveBatch = 5000
+	maxAuditArchiveBatch     = 20000
+)
+
+var auditCSVHeader = []string{"time", "seq", "actor", "ip", "action", "resource_type", "resource_id", "summary", "changes"}
+
+// Export menulis semua catatan yang cocok dengan p sebagai CSV, urut waktu
+// naik. Halaman dan sort di p.List diabaikan.
+func (s *AuditService) Export(ctx context.Context, p AuditParams, w io.Writer) error {
+	q, err := p.query()
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(auditCSVHeader); err != nil {
+		return err
+	}
+	err = s.repo.Each(ctx, q, func(l models.AuditLog) error {
+		changes := ""
+		if len(l.Changes) > 0 {
+			b, err := json.Marshal(l.Changes)
+			if err != nil {
+				return err
+			}
+			changes = string(b)
+		}
+		record := []string{l.Time.UTC().Format(time.RFC3339Nano), strconv.FormatInt(l.Seq, 10), l.Actor, l.IP,
+			l.Action, l.ResourceType, l.ResourceID, l.Summary, changes}
+		for i := range record {
+			record[i] = csvCell(record[i])
+		}
+		return cw.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvCell mencegah sel dibaca sebagai formula saat CSV dibuka di
+// spreadsheet; actor dan summary berasal dari input user.
+func csvCell(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+// Archives mengembalikan manifest arsip audit log, urut seq naik.
+func (s *AuditService) Archives(ctx context.Context) ([]models.AuditArchive, error) {
+	return s.repo.Archives(ctx)
+}
+
+// Archive memindahkan satu batch catatan chain yang lebih tua dari
+// Retention ke object storage sebagai JSONL ter-gzip, lalu menghapusnya
+// dari database. Manifest menyimpan hash catatan terakhir supaya Verify
+// dan catatan baru tetap tersambung ke chain. Catatan dari sebelum chain
+// diaktifkan (tanpa seq) tidak diarsip. Dipanggil berulang selama More.
+func (s *AuditService) Archive(ctx context.Context, batch int, createdBy string) (models.AuditArchiveResult, error) {
+	store := s.opts.Archive
+	if s.opts.Retention <= 0 || store == nil || store.Name() != "s3" {
+		return models.AuditArchiveResult{}, invalid("arsip audit log butuh AUDIT_RETENTION dan OBJECT_STORE=s3")
+	}
+	if batch <= 0 {
+		batch = defaultAuditArchiveBatch
+	}
+	if batch > maxAuditArchiveBatch {
+		batch = maxAuditArchiveBatch
+	}
+	result := models.AuditArchiveResult{Before: time.Now().UTC().Add(-s.opts.Retention).Truncate(time.Millisecond)}
+
+	var seq int64
+	var prev string
+	if last, err := s.repo.LastArchive(ctx); err == nil {
+		seq, prev = last.ToSeq, last.HeadHash
+	} else if !errors.Is(err, repositories.ErrNotFound) {
+		return result, err
+	}
+	logs, err := s.repo.Chain(ctx, seq, int64(batch)+1)
+	if err != nil {
+		return result, err
+	}
+	n := 0
+	for n < len(logs) && n < batch && logs[n].Time.Before(result.Before) {
+		n++
+	}
+	if n == 0 {
+		return result, nil
+	}
+	result.More = n == batch && len(logs) > batch && logs[batch].Time.Before(result.Before)
+	logs = logs[:n]
+
+	// Chain diperiksa dulu: catatan yang rusak tidak boleh hilang dari
+	// database sebelum diselidiki
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(zw)
+	for i := range logs {
+		l := &logs[i]
+		if l.Seq != seq+1 || l.PrevHash != prev || l.ChainHash() != l.Hash {
+			return result, invalid("chain audit log rusak di seq %d, jalankan GET /admin/audit-logs/verify", seq+1)
+		}
+		seq, prev = l.Seq, l.Hash
+		if err := enc.Encode(l); err != nil {
+			return result, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return result, err
+	}
+	data := buf.Bytes()
+	sum := sha256.Sum256(data)
+	first, last := logs[0], logs[len(logs)-1]
+	archive := &models.AuditArchive{
+		ID:        primitive.NewObjectID(),
+		FromSeq:   first.Seq,
+		ToSeq:     last.Seq,
+		From:      first.Time,
+		To:        last.Time,
+		Count:     int64(len(logs)),
+		HeadHash:  last.Hash,
+		SHA256:    hex.EncodeToString(sum[:]),
+		CreatedBy: createdBy,
+		CreatedAt: time.Now().UTC().Truncate(time.Millisecond),
+	}
+	key := fmt.Sprintf("audit/%s/%d-%d.jsonl.gz", first.Time.UTC().Format("2006/01"), first.Seq, last.Seq)
+	obj, err := store.Put(ctx, key, "application/gzip", data)
+	if err != nil {
+		return result, err
+	}
+	archive.Key, archive.URL = obj.Key, obj.URL
+	if err := s.repo.AddArchive(ctx, archive); errors.Is(err, repositories.ErrDuplicate) {
+		// Instance lain sudah mengarsip batch yang sama
+		result.More = true
+		return result, nil
+	} else if err != nil {
+		return result, err
+	}
+	deleted, err := s.repo.DeleteThrough(ctx, archive.ToSeq)
+	if err != nil {
+		return result, err
+	}
+	result.Archived, result.Archive = deleted, archive
+	s.Record(ctx, AuditEvent{Action: "audit.archive", ResourceType: AuditArchive, ResourceID: archive.ID.Hex(), After: archive})
+	return result, nil
+}
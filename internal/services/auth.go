@@ -0,0 +1,356 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"InfoCuy-Backend/internal/auth"
+	"InfoCuy-Backend/internal/database"
+	"InfoCuy-Backend/internal/mailer"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/ratelimit"
+	"InfoCuy-Backend/internal/rbac"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Masa berlaku token reset password
+const resetTokenTTL = time.Hour
+
+type AuthOptions struct {
+	AllowRegistration bool
+	// Masa transisi JWT: user boleh diautentikasi dari header X-User-Email
+	LegacyEmailAuth bool
+	// URL halaman reset di frontend; token ditambahkan sebagai ?token=
+	PasswordResetURL string
+	// URL halaman "bukan saya" di frontend untuk email peringatan login
+	// dari perangkat/lokasi baru; token ditambahkan sebagai ?token=
+	LoginAlertURL string
+	// Penguncian akun setelah login gagal berturut-turut
+	Lockout ratelimit.Lockout
+	// Cache user untuk Authenticate, dipakai bersama UserService supaya
+	// perubahan role langsung berlaku; nil berarti selalu membaca DB
+	Cache *UserCache
+	// Selama database read-only login tidak mencatat last_login_at dan
+	// percobaan gagal (batas per IP tetap berlaku)
+	Database *DatabaseMode
+}
+
+type AuthService struct {
+	users    repositories.UserRepository
+	resets   repositories.PasswordResetRepository
+	sessions repositories.SessionRepository
+	tokens   *auth.Manager
+	mail     mailer.Mailer
+	audit    *AuditService
+	opts     AuthOptions
+	// Salinan opts.AllowRegistration yang bisa diubah saat reload config
+	registration atomic.Bool
+	legacyEmail  atomic.Bool
+}
+
+func NewAuthService(users repositories.UserRepository, resets repositories.PasswordResetRepository, sessions repositories.SessionRepository,
+	tokens *auth.Manager, mail mailer.Mailer, audit *AuditService, opts AuthOptions) *AuthService {
+	s := &AuthService{users: users, resets: resets, sessions: sessions, tokens: tokens, mail: mail, audit: audit, opts: opts}
+	s.registration.Store(opts.AllowRegistration)
+	s.legacyEmail.Store(opts.LegacyEmailAuth)
+	return s
+}
+
+// RegistrationOpen bernilai true jika pendaftaran publik diizinkan.
+func (s *AuthService) RegistrationOpen() bool { return s.registration.Load() }
+
+// SetRegistrationOpen membuka atau menutup pendaftaran publik saat berjalan.
+func (s *AuthService) SetRegistrationOpen(open bool) { s.registration.Store(open) }
+
+// LegacyEmailAuth bernilai true selama header X-User-Email masih diterima
+// sebagai pengganti bearer token.
+func (s *AuthService) LegacyEmailAuth() bool { return s.legacyEmail.Load() }
+
+// SetLegacyEmailAuth menyalakan atau mematikan masa transisi X-User-Email
+// saat berjalan, supaya cutover frontend tidak butuh restart.
+func (s *AuthService) SetLegacyEmailAuth(on bool) { s.legacyEmail.Store(on) }
+
+func (s *AuthService) Register(ctx context.Context, in models.AuthInput) (*models.User, error) {
+	if !s.RegistrationOpen() {
+		return nil, ErrRegistrationClosed
+	}
+	in.Email = strings.TrimSpace(in.Email)
+	var v validator
+	v.email("email", in.Email)
+	v.password("password", in.Password)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	if _, err := s.users.FindByEmail(ctx, in.Email); err == nil {
+		return nil, ErrEmailTaken
+	} else if !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+	hash, err := auth.HashPassword(in.Password)
+	if err != nil {
+		return nil, err
+	}
+	u := &models.User{ID: primitive.NewObjectID(), Email: in.Email, Password: hash, Role: rbac.DefaultRole}
+	if err := s.users.Create(ctx, u); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "user.register", ResourceType: AuditUser, ResourceID: u.ID.Hex(), After: u})
+	return u, nil
+}
+
+// Login memeriksa password lalu memulai sesi baru dari perangkat dev.
+func (s *AuthService) Login(ctx context.Context, in models.AuthInput, dev models.LoginDevice) (*models.User, auth.TokenPair, error) {
+	u, err := s.users.FindByEmail(ctx, in.Email)
+	if errors.Is(err, repositories.ErrNotFound) {
+		// bcrypt tetap dijalankan: tanpa itu email tak terdaftar ditolak jauh
+		// lebih cepat dan bisa dipakai menebak email terdaftar
+		auth.FakeCheckPassword(in.Password)
+		return nil, auth.TokenPair{}, &CredentialError{Reason: "unknown_email"}
+	}
+	if err != nil {
+		return nil, auth.TokenPair{}, err
+	}
+	// Akun yang terkunci ditolak tanpa memeriksa password
+	now := time.Now()
+	if u.LockedUntil != nil && u.LockedUntil.After(now) {
+		return nil, auth.TokenPair{}, &AccountLockedError{Until: *u.LockedUntil}
+	}
+	ok, needsRehash := auth.CheckPassword(u.Password, in.Password)
+	if !ok {
+		return nil, auth.TokenPair{}, s.loginFailed(ctx, u, now)
+	}
+	if u.PasswordResetRequired {
+		return nil, auth.TokenPair{}, ErrPasswordResetRequired
+	}
+	readOnly := s.opts.Database.ReadOnly()
+	if !readOnly {
+		s.users.Update(ctx, u.ID, repositories.Fields{"failed_logins": 0, "locked_until": nil, "last_login_at": now.UTC()})
+		s.opts.Cache.Invalidate(u.ID)
+	}
+	// Migrasi akun lama: password plaintext di-hash ulang saat login sukses
+	if needsRehash && !readOnly {
+		if hash, err := auth.HashPassword(in.Password); err == nil {
+			s.users.Update(ctx, u.ID, repositories.Fields{"password": hash})
+		}
+	}
+	// Selama read-only sesi tidak dicatat; token tanpa sid tetap berlaku
+	var sessionID string
+	if !readOnly {
+		sessionID = s.startSession(ctx, u, dev)
+	}
+	pair, err := s.tokens.IssuePair(u.ID.Hex(), u.Email, u.Role, sessionID)
+	if err != nil {
+		return nil, auth.TokenPair{}, err
+	}
+	return u, pair, nil
+}
+
+// loginFailed mencatat password salah dan mengunci akun jika batas
+// percobaan tercapai. Hitungan direset setiap kali akun dikunci.
+func (s *AuthService) loginFailed(ctx context.Context, u *models.User, now time.Time) error {
+	if !s.opts.Lockout.Enabled() || s.opts.Database.ReadOnly() {
+		return &CredentialError{Reason: "bad_password"}
+	}
+	failures, err := s.users.IncrementFailedLogins(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	if failures < s.opts.Lockout.MaxFailures {
+		return &CredentialError{Reason: "bad_password"}
+	}
+	until := now.Add(s.opts.Lockout.Duration)
+	err = s.users.Update(ctx, u.ID, repositories.Fields{"failed_logins": 0, "locked_until": until})
+	s.opts.Cache.Invalidate(u.ID)
+	if err != nil {
+		return err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "user.lock", ResourceType: AuditUser, ResourceID: u.ID.Hex(),
+		After: map[string]interface{}{"failed_logins": failures, "locked_until": until}})
+	return &AccountLockedError{Until: until}
+}
+
+// Refresh menerbitkan pasangan token baru dalam sesi yang sama. Role
+// diambil ulang dari DB supaya perubahan role langsung berlaku.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (auth.TokenPair, error) {
+	claims, err := s.tokens.Parse(refreshToken, auth.TypeRefresh)
+	if err != nil {
+		return auth.TokenPair{}, ErrInvalidToken
+	}
+	u, err := s.userBySubject(ctx, claims.Subject)
+	if err != nil {
+		return auth.TokenPair{}, err
+	}
+	if sessionRevoked(u, claims) {
+		return auth.TokenPair{}, ErrInvalidToken
+	}
+	return s.tokens.IssuePair(u.ID.Hex(), u.Email, u.Role, claims.Session)
+}
+
+// Authenticate memvalidasi access token lalu memuat user dari DB. Token
+// dari sesi yang sudah dicabut ditolak.
+func (s *AuthService) Authenticate(ctx context.Context, accessToken string) (*models.User, error) {
+	claims, err := s.tokens.Parse(accessToken, auth.TypeAccess)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	u, err := s.userBySubject(ctx, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if sessionRevoked(u, claims) {
+		return nil, ErrInvalidToken
+	}
+	return u, nil
+}
+
+// AuthenticateEmail memuat user dari header X-User-Email lama. Hanya
+// berhasil selama masa transisi (LegacyEmailAuth) aktif. Akun yang
+// terkunci atau wajib reset password ditolak seperti saat Login, dan akun
+// yang sesinya pernah dicabut tidak bisa lagi memakai header ini sama
+// sekali: header tanpa rahasia tidak bisa dibedakan dari sesi penyusup.
+func (s *AuthService) AuthenticateEmail(ctx context.Context, email string) (*models.User, error) {
+	if !s.LegacyEmailAuth() || email == "" {
+		return nil, ErrInvalidToken
+	}
+	u, err := s.users.FindByEmail(ctx, strings.TrimSpace(email))
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if u.LockedUntil != nil && u.LockedUntil.After(time.Now()) {
+		return nil, &AccountLockedError{Until: *u.LockedUntil}
+	}
+	if u.PasswordResetRequired {
+		return nil, ErrPasswordResetRequired
+	}
+	if u.SessionsValidAfter != nil {
+		return nil, ErrInvalidToken
+	}
+	return u, nil
+}
+
+func (s *AuthService) userBySubject(ctx context.Context, subject string) (*models.User, error) {
+	objID, err := primitive.ObjectIDFromHex(subject)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if u, ok := s.opts.Cache.get(objID); ok {
+		return u, nil
+	}
+	u, err := s.users.FindByID(ctx, objID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrInvalidToken
+	}
+	if database.IsUnavailable(err) {
+		if u, ok := s.opts.Cache.stale(objID); ok {
+			return u, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.opts.Cache.put(u)
+	return u, nil
+}
+
+func (s *AuthService) ChangePassword(ctx context.Context, u models.User, in models.ChangePasswordInput) error {
+	if ok, _ := auth.CheckPassword(u.Password, in.OldPassword); !ok {
+		return ErrWrongPassword
+	}
+	if err := s.setPassword(ctx, u.ID, in.NewPassword); err != nil {
+		return err
+	}
+	// Password tidak pernah masuk diff, cukup catat kejadiannya
+	s.audit.Record(ctx, AuditEvent{Action: "user.password_change", ResourceType: AuditUser, ResourceID: u.ID.Hex()})
+	return nil
+}
+
+func (s *AuthService) setPassword(ctx context.Context, userID primitive.ObjectID, password string) error {
+	var v validator
+	v.password("new_password", password)
+	if err := v.err(); err != nil {
+		return err
+	}
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	err = s.users.Update(ctx, userID, repositories.Fields{"password": hash})
+	s.opts.Cache.Invalidate(userID)
+	return err
+}
+
+// RequestPasswordReset mengirim link reset jika email terdaftar. Email yang
+// tidak terdaftar tidak dianggap error supaya tidak bisa ditebak dari luar.
+func (s *AuthService) RequestPasswordReset(ctx context.Context, email string) error {
+	u, err := s.users.FindByEmail(ctx, email)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	token, hash, err := auth.NewResetToken()
+	if err != nil {
+		return err
+	}
+	err = s.resets.Create(ctx, &models.PasswordReset{
+		UserID:    u.ID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(resetTokenTTL),
+	})
+	if err != nil {
+		return err
+	}
+	link := "token: " + token
+	if s.opts.PasswordResetURL != "" {
+		link = s.opts.PasswordResetURL + "?token=" + token
+	}
+	// Email dikirim di belakang: lama respons tidak boleh bergantung pada
+	// provider email, karena hanya email terdaftar yang dikirimi
+	go func() {
+		err := s.mail.Send(context.WithoutCancel(ctx), mailer.Message{
+			To:      u.Email,
+			Subject: "Reset password InfoCuy",
+			Body:    "Gunakan link berikut untuk mengatur ulang password (berlaku 1 jam):\n\n" + link,
+		})
+		if err != nil {
+			log.Println("password reset:", err)
+		}
+	}()
+	return nil
+}
+
+// ConfirmPasswordReset memakai token (sekali pakai) untuk mengganti
+// password. Semua sesi yang ada dicabut dan login dibuka lagi jika
+// sebelumnya diwajibkan reset.
+func (s *AuthService) ConfirmPasswordReset(ctx context.Context, in models.PasswordResetConfirm) (*models.PasswordReset, error) {
+	var v validator
+	v.password("new_password", in.NewPassword)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	reset, err := s.resets.Consume(ctx, auth.HashResetToken(in.Token), time.Now())
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setPassword(ctx, reset.UserID, in.NewPassword); err != nil {
+		return nil, err
+	}
+	if err := s.revokeSessions(ctx, reset.UserID, time.Now().UTC(), false); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "user.password_reset", ResourceType: AuditUser, ResourceID: reset.UserID.Hex()})
+	return reset, nil
+}
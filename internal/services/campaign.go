@@ -0,0 +1,386 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"html"
+	"log"
+	"strings"
+	"text/template"
+	"time"
+
+	"InfoCuy-Backend/internal/mailer"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxCampaignNameLen    = 200
+	maxCampaignSubjectLen = 200
+	maxCampaignBodyLen    = 20000
+	// Penerima dibuat per potongan ini saat campaign dikirim
+	recipientChunk       = 500
+	defaultDeliveryBatch = 50
+	maxDeliveryBatch     = 500
+)
+
+type CampaignOptions struct {
+	// URL publik API (mis. https://api.infocuy.id) untuk pixel pelacak
+	// open. Kosong = email dikirim plain text dan open tidak dihitung.
+	TrackingBaseURL string
+	// Jeda antar email dalam satu batch delivery, untuk provider SMTP yang
+	// membatasi laju kirim. Batch x jeda harus muat dalam timeout request.
+	SendInterval time.Duration
+}
+
+// CampaignService mengelola email massal dari admin. Tidak ada worker
+// terpisah: POST /admin/jobs/campaign-delivery mengirim satu batch dari
+// antrean campaign_recipients dan dipanggil berkala oleh cron sampai
+// remaining = 0.
+type CampaignService struct {
+	campaigns repositories.CampaignRepository
+	users     repositories.UserRepository
+	mail      mailer.Mailer
+	audit     *AuditService
+	opts      CampaignOptions
+}
+
+func NewCampaignService(campaigns repositories.CampaignRepository, users repositories.UserRepository, mail mailer.Mailer, audit *AuditService, opts CampaignOptions) *CampaignService {
+	return &CampaignService{campaigns: campaigns, users: users, mail: mail, audit: audit, opts: opts}
+}
+
+// campaignData adalah data yang tersedia di template subject & body.
+type campaignData struct {
+	Email string
+	Role  string
+}
+
+// render mengisi template campaign untuk satu penerima.
+func render(c models.Campaign, data campaignData) (subject, body string, err error) {
+	subject, err = execute("subject", c.Subject, data)
+	if err != nil {
+		return "", "", err
+	}
+	body, err = execute("body", c.Body, data)
+	return subject, body, err
+}
+
+func execute(name, text string, data campaignData) (string, error) {
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func validateCampaign(in models.CampaignInput) error {
+	var v validator
+	v.required(in.Name != "", "name")
+	v.maxLength("name", in.Name, maxCampaignNameLen)
+	v.required(in.Subject != "", "subject")
+	v.maxLength("subject", in.Subject, maxCampaignSubjectLen)
+	v.required(in.Body != "", "body")
+	v.maxLength("body", in.Body, maxCampaignBodyLen)
+	// Coba render dengan data contoh supaya field template yang salah
+	// (mis. {{.Nama}}) ketahuan sebelum dikirim ke ribuan user
+	sample := campaignData{Email: "contoh@example.com", Role: "user"}
+	if _, err := execute("subject", in.Subject, sample); err != nil {
+		v.check(false, "subject", RuleTemplate, Params{"error": err.Error()})
+	}
+	if _, err := execute("body", in.Body, sample); err != nil {
+		v.check(false, "body", RuleTemplate, Params{"error": err.Error()})
+	}
+	seg := in.Segment
+	v.check(seg.ActiveWithinDays >= 0, "segment.active_within_days", RuleNotNegative, nil)
+	v.check(seg.InactiveForDays >= 0, "segment.inactive_for_days", RuleNotNegative, nil)
+	if seg.SignedUpAfter != nil && seg.SignedUpBefore != nil {
+		v.check(seg.SignedUpAfter.Before(*seg.SignedUpBefore), "segment.signed_up_before", RuleAfter, Params{"field": "signed_up_after"})
+	}
+	return v.err()
+}
+
+// Create menyimpan campaign baru sebagai draft.
+func (s *CampaignService) Create(ctx context.Context, u models.User, in models.CampaignInput) (*models.Campaign, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	in.Subject = strings.TrimSpace(in.Subject)
+	in.Segment.Roles = normalizeList(in.Segment.Roles)
+	if err := validateCampaign(in); err != nil {
+		return nil, err
+	}
+	c := models.Campaign{
+		Name:      in.Name,
+		Subject:   in.Subject,
+		Body:      in.Body,
+		Segment:   in.Segment,
+		Status:    models.CampaignDraft,
+		CreatedBy: u.Email,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.campaigns.Create(ctx, &c); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "campaign.create", ResourceType: AuditCampaign, ResourceID: c.ID.Hex(), After: c})
+	return &c, nil
+}
+
+func (s *CampaignService) Get(ctx context.Context, id primitive.ObjectID) (*models.Campaign, error) {
+	c, err := s.campaigns.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrCampaignNotFound
+	}
+	return c, err
+}
+
+func (s *CampaignService) List(ctx context.Context, opts models.ListOptions) ([]models.Campaign, models.PageMeta, error) {
+	p, err := listPage(repositories.CampaignList, opts)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	campaigns, total, err := s.campaigns.List(ctx, p)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	meta := repositories.Meta(p, total, campaigns)
+	return campaigns, meta, nil
+}
+
+// Preview merender campaign untuk user pertama di segmen dan menghitung
+// jumlah penerima saat ini.
+func (s *CampaignService) Preview(ctx context.Context, id primitive.ObjectID) (*models.CampaignPreview, error) {
+	c, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	count, err := s.users.CountSegment(ctx, c.Segment, now)
+	if err != nil {
+		return nil, err
+	}
+	data := campaignData{Email: "contoh@example.com", Role: "user"}
+	sample, err := s.users.FindSegment(ctx, c.Segment, now, primitive.NilObjectID, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(sample) > 0 {
+		data = campaignData{Email: sample[0].Email, Role: sample[0].Role}
+	}
+	subject, body, err := render(*c, data)
+	if err != nil {
+		return nil, invalid("Template tidak bisa dirender: %v", err)
+	}
+	return &models.CampaignPreview{Subject: subject, Body: body, SampleTo: data.Email, Recipients: count}, nil
+}
+
+// TestSend mengirim satu email contoh ke alamat to, tanpa dihitung di stats.
+func (s *CampaignService) TestSend(ctx context.Context, u models.User, id primitive.ObjectID, to string) error {
+	to = strings.ToLower(strings.TrimSpace(to))
+	if to == "" {
+		to = u.Email
+	}
+	var v validator
+	v.email("email", to)
+	if err := v.err(); err != nil {
+		return err
+	}
+	c, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	subject, body, err := render(*c, campaignData{Email: to, Role: u.Role})
+	if err != nil {
+		return invalid("Template tidak bisa dirender: %v", err)
+	}
+	if err := s.mail.Send(ctx, mailer.Message{To: to, Subject: "[TEST] " + subject, Body: body}); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "campaign.test_send", ResourceType: AuditCampaign, ResourceID: id.Hex(),
+		After: map[string]string{"to": to}})
+	return nil
+}
+
+// Send mengunci daftar penerima campaign draft dan memasukkannya ke antrean
+// delivery. User yang masuk segmen setelah ini tidak ikut dikirimi.
+func (s *CampaignService) Send(ctx context.Context, id primitive.ObjectID) (*models.Campaign, error) {
+	c, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status != models.CampaignDraft {
+		return nil, invalid("Campaign sudah dikirim (status %s)", c.Status)
+	}
+	now := time.Now().UTC()
+	count, err := s.users.CountSegment(ctx, c.Segment, now)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, invalid("Segmen tidak berisi user")
+	}
+	// Klaim status lebih dulu supaya dua request send bersamaan tidak
+	// membuat penerima ganda
+	if err := s.campaigns.SetStatus(ctx, id, models.CampaignDraft, models.CampaignQueued, repositories.Fields{"queued_at": now}); errors.Is(err, repositories.ErrNotFound) {
+		return nil, invalid("Campaign sudah dikirim")
+	} else if err != nil {
+		return nil, err
+	}
+	total, err := s.enqueue(ctx, *c, now)
+	if err != nil {
+		// Kembalikan ke draft supaya bisa dicoba lagi
+		cleanup := context.WithoutCancel(ctx)
+		s.campaigns.DeleteRecipients(cleanup, id)
+		s.campaigns.SetStatus(cleanup, id, models.CampaignQueued, models.CampaignDraft, repositories.Fields{"queued_at": nil})
+		return nil, err
+	}
+	s.campaigns.IncStats(ctx, id, map[string]int64{"recipients": total})
+	if total == 0 {
+		s.campaigns.SetStatus(ctx, id, models.CampaignQueued, models.CampaignDone, repositories.Fields{"completed_at": now})
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "campaign.send", ResourceType: AuditCampaign, ResourceID: id.Hex(),
+		After: map[string]interface{}{"recipients": total}})
+	return s.Get(ctx, id)
+}
+
+// enqueue membuat dokumen penerima untuk seluruh segmen.
+func (s *CampaignService) enqueue(ctx context.Context, c models.Campaign, now time.Time) (int64, error) {
+	var total int64
+	after := primitive.NilObjectID
+	for {
+		users, err := s.users.FindSegment(ctx, c.Segment, now, after, recipientChunk)
+		if err != nil {
+			return 0, err
+		}
+		if len(users) == 0 {
+			return total, nil
+		}
+		recipients := make([]models.CampaignRecipient, len(users))
+		for i, u := range users {
+			recipients[i] = models.CampaignRecipient{
+				CampaignID: c.ID,
+				UserID:     u.ID,
+				Email:      u.Email,
+				Role:       u.Role,
+				Status:     models.RecipientPending,
+				Token:      newOpenToken(),
+			}
+		}
+		if err := s.campaigns.InsertRecipients(ctx, recipients); err != nil {
+			return 0, err
+		}
+		total += int64(len(users))
+		after = users[len(users)-1].ID
+	}
+}
+
+func newOpenToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Deliver mengirim satu batch dari antrean. Penolakan permanen dari server
+// SMTP (kode 5xx) dicatat sebagai bounce; error sementara (koneksi, 4xx,
+// circuit terbuka) menghentikan batch dan penerima tetap di antrean untuk
+// panggilan berikutnya.
+func (s *CampaignService) Deliver(ctx context.Context, batch int) (models.DeliveryResult, error) {
+	var result models.DeliveryResult
+	if batch <= 0 {
+		batch = defaultDeliveryBatch
+	}
+	if batch > maxDeliveryBatch {
+		batch = maxDeliveryBatch
+	}
+	recipients, err := s.campaigns.PendingRecipients(ctx, int64(batch))
+	if err != nil {
+		return result, err
+	}
+	campaigns := map[primitive.ObjectID]*models.Campaign{}
+	var sendErr error
+	for i, rec := range recipients {
+		c, ok := campaigns[rec.CampaignID]
+		if !ok {
+			if c, err = s.campaigns.FindByID(ctx, rec.CampaignID); err != nil {
+				return result, err
+			}
+			campaigns[rec.CampaignID] = c
+		}
+		if i > 0 && s.opts.SendInterval > 0 {
+			if sendErr = sleepCtx(ctx, s.opts.SendInterval); sendErr != nil {
+				break
+			}
+		}
+		now := time.Now().UTC()
+		err := s.mail.Send(ctx, s.message(*c, rec))
+		switch {
+		case err == nil:
+			s.campaigns.MarkRecipient(ctx, rec.ID, models.RecipientSent, now, "")
+			s.campaigns.IncStats(ctx, c.ID, map[string]int64{"sent": 1})
+			result.Sent++
+		case mailer.Rejected(err):
+			s.campaigns.MarkRecipient(ctx, rec.ID, models.RecipientBounced, now, err.Error())
+			s.campaigns.IncStats(ctx, c.ID, map[string]int64{"bounced": 1})
+			result.Bounced++
+		default:
+			sendErr = err
+		}
+		if sendErr != nil {
+			break
+		}
+	}
+	for id := range campaigns {
+		if n, err := s.campaigns.CountPending(ctx, id); err == nil && n == 0 {
+			s.campaigns.SetStatus(ctx, id, models.CampaignQueued, models.CampaignDone, repositories.Fields{"completed_at": time.Now().UTC()})
+		}
+	}
+	if sendErr != nil {
+		log.Println("campaign delivery berhenti:", sendErr)
+		return result, sendErr
+	}
+	result.Remaining, err = s.campaigns.CountPending(ctx, primitive.NilObjectID)
+	return result, err
+}
+
+// message merender email untuk satu penerima. Versi HTML dengan pixel
+// pelacak hanya dibuat jika TrackingBaseURL diset.
+func (s *CampaignService) message(c models.Campaign, rec models.CampaignRecipient) mailer.Message {
+	subject, body, err := render(c, campaignData{Email: rec.Email, Role: rec.Role})
+	if err != nil {
+		// Template sudah divalidasi saat dibuat; jatuh ke teks mentah
+		subject, body = c.Subject, c.Body
+	}
+	msg := mailer.Message{To: rec.Email, Subject: subject, Body: body, Bulk: true}
+	if base := strings.TrimRight(s.opts.TrackingBaseURL, "/"); base != "" {
+		msg.HTML = "<html><body><div style=\"white-space:pre-wrap\">" + html.EscapeString(body) + "</div>" +
+			"<img src=\"" + base + "/v1/campaigns/open/" + rec.Token + "\" width=\"1\" height=\"1\" alt=\"\"></body></html>"
+	}
+	return msg
+}
+
+// TrackOpen mencatat open pertama dari pixel email. Token tidak dikenal
+// diabaikan supaya pixel tidak bisa dipakai menebak token.
+func (s *CampaignService) TrackOpen(ctx context.Context, token string) {
+	rec, err := s.campaigns.MarkOpened(ctx, token, time.Now().UTC())
+	if err != nil {
+		return
+	}
+	s.campaigns.IncStats(ctx, rec.CampaignID, map[string]int64{"opened": 1})
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/osm"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Cukup untuk kategori yang setara dengan beberapa tag, mis. rumah sakit
+// dan klinik
+const maxCategoryOSMTags = 5
+
+var (
+	colorPattern   = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+	nonSlugPattern = regexp.MustCompile(`[^a-z0-9_]+`)
+)
+
+type CategoryService struct {
+	categories repositories.CategoryRepository
+	locations  repositories.LocationRepository
+	audit      *AuditService
+}
+
+func NewCategoryService(categories repositories.CategoryRepository, locations repositories.LocationRepository, audit *AuditService) *CategoryService {
+	return &CategoryService{categories: categories, locations: locations, audit: audit}
+}
+
+// Slugify mengubah nama menjadi slug, mis. "Rumah Sakit" -> "rumah-sakit".
+func Slugify(name string) string {
+	return strings.Trim(nonSlugPattern.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+func (s *CategoryService) List(ctx context.Context) ([]models.Category, error) {
+	return s.categories.List(ctx)
+}
+
+func (s *CategoryService) Get(ctx context.Context, slug string) (*models.Category, error) {
+	c, err := s.categories.FindBySlug(ctx, slug)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrCategoryNotFound
+	}
+	return c, err
+}
+
+// CategoryResolver memetakan input kategori dari client ke slug.
+type CategoryResolver func(input string) (string, error)
+
+// Resolver memuat semua kategori sekali lalu mencocokkan input dengan slug
+// atau nama (case-insensitive), sehingga "Kuliner" dan "kuliner" menjadi
+// slug yang sama. Input kosong dibiarkan kosong. Selama belum ada satu pun
+// kategori terdaftar, input diterima apa adanya supaya deploy baru tidak
+// langsung menolak semua lokasi.
+func (s *CategoryService) Resolver(ctx context.Context) (CategoryResolver, error) {
+	categories, err := s.categories.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lookup := make(map[string]string, len(categories)*2)
+	slugs := make([]string, 0, len(categories))
+	for _, c := range categories {
+		lookup[c.Slug] = c.Slug
+		lookup[strings.ToLower(c.Name)] = c.Slug
+		slugs = append(slugs, c.Slug)
+	}
+	return func(input string) (string, error) {
+		input = strings.TrimSpace(input)
+		if input == "" || len(lookup) == 0 {
+			return input, nil
+		}
+		if slug, ok := lookup[strings.ToLower(input)]; ok {
+			return slug, nil
+		}
+		return "", invalid("Kategori %q tidak terdaftar. Pilihan: %s", input, strings.Join(slugs, ", "))
+	}, nil
+}
+
+func validateCategory(c models.Category) error {
+	if strings.TrimSpace(c.Name) == "" {
+		return invalid("Nama kategori wajib diisi")
+	}
+	if c.Color != "" && !colorPattern.MatchString(c.Color) {
+		return invalid("color harus format #RRGGBB")
+	}
+	if len(c.OSMTags) > maxCategoryOSMTags {
+		return invalid("osm_tags maksimal %d tag", maxCategoryOSMTags)
+	}
+	for _, tag := range c.OSMTags {
+		if !osm.TagPattern.MatchString(tag) {
+			return invalid("osm_tags harus berformat key=value, mis. amenity=cafe: %s", tag)
+		}
+	}
+	return validateLabels(c.Labels)
+}
+
+func (s *CategoryService) Create(ctx context.Context, in models.Category) (*models.Category, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	if err := validateCategory(in); err != nil {
+		return nil, err
+	}
+	if in.Slug == "" {
+		in.Slug = in.Name
+	}
+	in.Slug = Slugify(in.Slug)
+	if in.Slug == "" {
+		return nil, invalid("Slug kategori tidak valid")
+	}
+	if _, err := s.categories.FindBySlug(ctx, in.Slug); err == nil {
+		return nil, invalid("Kategori %s sudah ada", in.Slug)
+	} else if !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+	in.ID = primitive.NilObjectID
+	if err := s.categories.Create(ctx, &in); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "category.create", ResourceType: AuditCategory, ResourceID: in.Slug, After: in})
+	return &in, nil
+}
+
+// Update mengubah nama, label, icon, warna, penanda acara dan tag OSM. Slug tidak bisa diubah karena
+// dipakai sebagai referensi di data lokasi.
+func (s *CategoryService) Update(ctx context.Context, slug string, in models.Category) (*models.Category, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	if err := validateCategory(in); err != nil {
+		return nil, err
+	}
+	before, err := s.Get(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	err = s.categories.Update(ctx, slug, repositories.Fields{"name": in.Name, "labels": in.Labels, "icon": in.Icon, "color": in.Color, "event": in.Event,
+		"approximate_coordinates": in.ApproximateCoordinates, "osm_tags": in.OSMTags})
+	if err != nil {
+		return nil, err
+	}
+	after, err := s.Get(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "category.update", ResourceType: AuditCategory, ResourceID: slug, Before: before, After: after})
+	return after, nil
+}
+
+// Delete menolak kategori yang masih dipakai lokasi.
+func (s *CategoryService) Delete(ctx context.Context, slug string) error {
+	n, err := s.locations.CountByCategory(ctx, slug)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return invalid("Kategori %s masih dipakai %d lokasi", slug, n)
+	}
+	before, err := s.Get(ctx, slug)
+	if err != nil {
+		return err
+	}
+	if err := s.categories.Delete(ctx, slug); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "category.delete", ResourceType: AuditCategory, ResourceID: slug, Before: before})
+	return nil
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/audit"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultChangelogWindow = 7 * 24 * time.Hour
+	defaultChangelogLimit  = 100
+	maxChangelogLimit      = 500
+)
+
+// Action audit yang dibaca changelog
+var changelogActions = []string{"location.create", "location.import", "location.approve", "location.update", "location.delete"}
+
+// Field yang perubahannya layak diumumkan, beserta namanya per bahasa.
+// Perubahan lain (mis. foto, ketinggian, verified) tidak masuk changelog.
+var significantFields = map[string]models.Labels{
+	"name":            {"id": "nama", "en": "name"},
+	"category":        {"id": "kategori", "en": "category"},
+	"address":         {"id": "alamat", "en": "address"},
+	"coordinates":     {"id": "koordinat", "en": "coordinates"},
+	"status":          {"id": "status", "en": "status"},
+	"accessibility":   {"id": "aksesibilitas", "en": "accessibility"},
+	"price_range":     {"id": "kisaran harga", "en": "price range"},
+	"payment_methods": {"id": "metode pembayaran", "en": "payment methods"},
+}
+
+// Urutan tetap supaya Fields dan Summary stabil
+var significantOrder = []string{"name", "category", "address", "coordinates", "status", "accessibility", "price_range", "payment_methods"}
+
+// Template ringkasan per jenis entri dan bahasa
+var changelogSummaries = map[string]models.Labels{
+	models.ChangeAdded:   {"id": "Lokasi baru: %s", "en": "New location: %s"},
+	models.ChangeUpdated: {"id": "%s diperbarui: %s", "en": "%s updated: %s"},
+	models.ChangeClosed:  {"id": "%s tutup permanen", "en": "%s permanently closed"},
+	models.ChangeRemoved: {"id": "%s dihapus dari dataset", "en": "%s removed from the dataset"},
+}
+
+// ChangelogService merangkum perubahan dataset lokasi dari audit log untuk
+// konsumen publik. Actor dan IP tidak pernah ikut keluar.
+type ChangelogService struct {
+	audit      repositories.AuditRepository
+	locations  repositories.LocationRepository
+	categories *CategoryService
+}
+
+func NewChangelogService(audit repositories.AuditRepository, locations repositories.LocationRepository, categories *CategoryService) *ChangelogService {
+	return &ChangelogService{audit: audit, locations: locations, categories: categories}
+}
+
+// ChangelogParams adalah query GET /changelog. Since berformat RFC3339 atau
+// YYYY-MM-DD (default 7 hari terakhir); After adalah meta.next dari
+// halaman sebelumnya.
+type ChangelogParams struct {
+	Since string
+	After string
+	Limit int
+	Lang  string
+}
+
+// List mengembalikan entri dari yang terlama. Limit membatasi jumlah
+// catatan audit yang dibaca, jadi satu halaman bisa berisi lebih sedikit
+// entri; lanjutkan selama meta.next tidak kosong.
+func (s *ChangelogService) List(ctx context.Context, p ChangelogParams) ([]models.ChangelogEntry, models.ChangelogMeta, error) {
+	var meta models.ChangelogMeta
+	since, err := parseAuditTime(p.Since, false)
+	if err != nil {
+		return nil, meta, err
+	}
+	if since.IsZero() {
+		since = time.Now().UTC().Add(-defaultChangelogWindow).Truncate(24 * time.Hour)
+	}
+	meta.Since = since
+	var after primitive.ObjectID
+	if p.After != "" {
+		if after, err = primitive.ObjectIDFromHex(p.After); err != nil {
+			return nil, meta, invalid("after tidak valid")
+		}
+	}
+	if p.Limit <= 0 {
+		p.Limit = defaultChangelogLimit
+	}
+	if p.Limit > maxChangelogLimit {
+		p.Limit = maxChangelogLimit
+	}
+	q := repositories.AuditQuery{
+		Actions:      changelogActions,
+		ResourceType: AuditLocation,
+		From:         since,
+		// Urut _id naik; ?after menunjuk _id entri terakhir halaman sebelumnya
+		ListPage: repositories.ListPage{Limit: int64(p.Limit)},
+	}
+	if !after.IsZero() {
+		q.After = &repositories.Cursor{ID: after}
+	}
+	logs, _, err := s.audit.Find(ctx, q)
+	if err != nil {
+		return nil, meta, err
+	}
+	if len(logs) == p.Limit {
+		meta.Next = logs[len(logs)-1].ID.Hex()
+	}
+	categories, err := s.categories.List(ctx)
+	if err != nil {
+		return nil, meta, err
+	}
+	LocalizeCategories(categories, p.Lang)
+	categoryLabels := map[string]string{}
+	for _, c := range categories {
+		categoryLabels[c.Slug] = c.Label
+	}
+
+	// Lokasi saat ini, untuk nama dan memastikan lokasinya publik
+	current := map[string]*models.Location{}
+	entries := []models.ChangelogEntry{}
+	for _, l := range logs {
+		entry, ok := changelogEntry(l)
+		if !ok {
+			continue
+		}
+		// Perubahan dan penutupan hanya diumumkan untuk lokasi yang masih
+		// tayang; entri lain cukup memakai data dari audit jika lengkap
+		if entry.Type == models.ChangeUpdated || entry.Type == models.ChangeClosed || entry.Name == "" {
+			loc, seen := current[l.ResourceID]
+			if !seen {
+				loc = s.find(ctx, l.ResourceID)
+				current[l.ResourceID] = loc
+			}
+			if loc == nil || !loc.Listed() {
+				continue
+			}
+			if entry.Name == "" {
+				entry.Name = loc.Name
+			}
+			if entry.Category == "" {
+				entry.Category = loc.Category
+			}
+		}
+		entry.Summary = changelogSummary(entry, categoryLabels, p.Lang)
+		entries = append(entries, entry)
+	}
+	return entries, meta, nil
+}
+
+func (s *ChangelogService) find(ctx context.Context, hex string) *models.Location {
+	id, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		return nil
+	}
+	loc, err := s.locations.FindByID(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return loc
+}
+
+// changelogEntry menerjemahkan satu catatan audit. false jika catatan tidak
+// perlu diumumkan.
+func changelogEntry(l models.AuditLog) (models.ChangelogEntry, bool) {
+	entry := models.ChangelogEntry{Time: l.Time, LocationID: l.ResourceID}
+	switch l.Action {
+	case "location.create", "location.import":
+		// Lokasi yang masih menunggu moderasi diumumkan saat di-approve
+		if !listedStatus(changeValue(l.Changes, "moderation_status", true)) {
+			return entry, false
+		}
+		entry.Type = models.ChangeAdded
+		entry.Name = changeValue(l.Changes, "name", true)
+		entry.Category = changeValue(l.Changes, "category", true)
+	case "location.approve":
+		entry.Type = models.ChangeAdded
+	case "location.update":
+		if changeValue(l.Changes, "status", true) == models.StatusPermanentlyClosed {
+			entry.Type = models.ChangeClosed
+			break
+		}
+		for _, f := range significantOrder {
+			if _, ok := l.Changes[f]; ok {
+				entry.Fields = append(entry.Fields, f)
+			}
+		}
+		if len(entry.Fields) == 0 {
+			return entry, false
+		}
+		entry.Type = models.ChangeUpdated
+		entry.Name = changeValue(l.Changes, "name", true)
+	case "location.delete":
+		if !listedStatus(changeValue(l.Changes, "moderation_status", false)) {
+			return entry, false
+		}
+		entry.Type = models.ChangeRemoved
+		entry.Name = changeValue(l.Changes, "name", false)
+		entry.Category = changeValue(l.Changes, "category", false)
+	default:
+		return entry, false
+	}
+	return entry, true
+}
+
+// changeValue membaca nilai string field sesudah (after) atau sebelum perubahan.
+func changeValue(changes map[string]audit.Change, field string, after bool) string {
+	c, ok := changes[field]
+	if !ok {
+		return ""
+	}
+	v := c.Before
+	if after {
+		v = c.After
+	}
+	s, _ := v.(string)
+	return s
+}
+
+func listedStatus(moderation string) bool {
+	return moderation != models.ModerationPending && moderation != models.ModerationRejected
+}
+
+func changelogSummary(e models.ChangelogEntry, categoryLabels map[string]string, lang string) string {
+	format := changelogSummaries[e.Type].Resolve(lang)
+	switch e.Type {
+	case models.ChangeAdded:
+		name := e.Name
+		if label := categoryLabels[e.Category]; label != "" {
+			name += " (" + label + ")"
+		}
+		return fmt.Sprintf(format, name)
+	case models.ChangeUpdated:
+		fields := make([]string, len(e.Fields))
+		for i, f := range e.Fields {
+			fields[i] = significantFields[f].Resolve(lang)
+		}
+		return fmt.Sprintf(format, e.Name, strings.Join(fields, ", "))
+	default:
+		return fmt.Sprintf(format, e.Name)
+	}
+}
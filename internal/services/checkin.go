@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/rbac"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kode check-in berganti setiap checkInCodePeriod. Kode periode sebelumnya
+// masih diterima supaya pengunjung yang memindai tepat saat pergantian
+// tidak ditolak; foto QR yang dibagikan keluar lokasi basi dalam 1 menit.
+const checkInCodePeriod = 30 * time.Second
+
+// CheckInService menangani check-in QR di lokasi acara (kategori dengan
+// event = true). Panitia (pembuat lokasi atau pemegang
+// locations:update_any) menampilkan kode yang terus berganti; pengunjung
+// memindainya untuk membuktikan hadir di tempat.
+type CheckInService struct {
+	checkins   repositories.CheckInRepository
+	locations  repositories.LocationRepository
+	categories *CategoryService
+	roles      *RoleService
+	// checkins_count ikut berubah di map_view
+	events *LocationEvents
+	// Kunci HMAC kode check-in, diturunkan dari JWT_SECRET
+	key []byte
+}
+
+func NewCheckInService(checkins repositories.CheckInRepository, locations repositories.LocationRepository,
+	categories *CategoryService, roles *RoleService, events *LocationEvents, secret string) *CheckInService {
+	key := sha256.Sum256([]byte("checkin:" + secret))
+	return &CheckInService{checkins: checkins, locations: locations, categories: categories, roles: roles, events: events, key: key[:]}
+}
+
+// event memastikan lokasi tayang dan kategorinya acara.
+func (s *CheckInService) event(ctx context.Context, id primitive.ObjectID) (*models.Location, error) {
+	loc, err := s.locations.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrLocationNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if !loc.Listed() {
+		return nil, ErrLocationNotFound
+	}
+	category, err := s.categories.Get(ctx, loc.Category)
+	if err != nil && !errors.Is(err, ErrCategoryNotFound) {
+		return nil, err
+	}
+	if category == nil || !category.Event {
+		return nil, invalid("Lokasi ini bukan acara, check-in tidak tersedia")
+	}
+	return loc, nil
+}
+
+// organizer memastikan u boleh melihat kode dan kehadiran acara.
+func (s *CheckInService) organizer(ctx context.Context, u models.User, id primitive.ObjectID) error {
+	loc, err := s.event(ctx, id)
+	if err != nil {
+		return err
+	}
+	if loc.CreatedBy != u.Email && !s.roles.CanModerate(ctx, u.Role, rbac.LocationsUpdateAny, loc) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// sign membuat kode untuk periode ke-window: "<window>.<hmac>".
+func (s *CheckInService) sign(id primitive.ObjectID, window int64) string {
+	w := strconv.FormatInt(window, 10)
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(id.Hex() + ":" + w))
+	return w + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:12])
+}
+
+func checkInWindow(t time.Time) int64 {
+	return t.Unix() / int64(checkInCodePeriod/time.Second)
+}
+
+// Code mengembalikan kode yang berlaku sekarang untuk ditampilkan sebagai QR.
+func (s *CheckInService) Code(ctx context.Context, u models.User, id primitive.ObjectID) (models.CheckInCode, error) {
+	if err := s.organizer(ctx, u, id); err != nil {
+		return models.CheckInCode{}, err
+	}
+	window := checkInWindow(time.Now())
+	return models.CheckInCode{
+		Code:      s.sign(id, window),
+		ExpiresAt: time.Unix((window+1)*int64(checkInCodePeriod/time.Second), 0).UTC(),
+	}, nil
+}
+
+// validCode menerima kode periode ini dan periode sebelumnya.
+func (s *CheckInService) validCode(id primitive.ObjectID, code string, now time.Time) bool {
+	w, _, ok := strings.Cut(strings.TrimSpace(code), ".")
+	if !ok {
+		return false
+	}
+	window, err := strconv.ParseInt(w, 10, 64)
+	current := checkInWindow(now)
+	if err != nil || window > current || window < current-1 {
+		return false
+	}
+	return hmac.Equal([]byte(s.sign(id, window)), []byte(strings.TrimSpace(code)))
+}
+
+// Verify mencatat kehadiran u jika code adalah kode yang sedang tampil.
+// Check-in tidak dicatat di audit log; collection checkins sudah menjadi
+// catatannya.
+func (s *CheckInService) Verify(ctx context.Context, u models.User, id primitive.ObjectID, in models.CheckInInput) (*models.CheckIn, error) {
+	if _, err := s.event(ctx, id); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if !s.validCode(id, in.Code, now) {
+		return nil, ErrInvalidCheckInCode
+	}
+	checkin := models.CheckIn{LocationID: id, UserID: u.ID, CreatedAt: now}
+	if err := s.checkins.Create(ctx, &checkin); errors.Is(err, repositories.ErrDuplicate) {
+		return nil, ErrAlreadyCheckedIn
+	} else if err != nil {
+		return nil, err
+	}
+	// Kegagalan counter hanya dicatat; diperbaiki job rekonsiliasi
+	if err := s.locations.IncrementCounter(ctx, id, repositories.CounterCheckIns, 1); err != nil {
+		log.Println("checkins_count lokasi", id.Hex()+":", err)
+	} else {
+		s.events.Publish(ctx, "checkin.create", id)
+	}
+	return &checkin, nil
+}
+
+// Attendance mengembalikan jumlah kehadiran untuk panitia.
+func (s *CheckInService) Attendance(ctx context.Context, u models.User, id primitive.ObjectID) (models.Attendance, error) {
+	if err := s.organizer(ctx, u, id); err != nil {
+		return models.Attendance{}, err
+	}
+	return s.checkins.Attendance(ctx, id)
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Lokasi maksimum yang bisa dibandingkan sekaligus
+const maxCompareLocations = 4
+
+// compareFields adalah field tabel perbandingan yang diperiksa untuk
+// Differences, urut sesuai tampilan di frontend.
+var compareFields = []struct {
+	name  string
+	value func(models.ComparedLocation) interface{}
+}{
+	{"category", func(l models.ComparedLocation) interface{} { return l.Category }},
+	{"verified", func(l models.ComparedLocation) interface{} { return l.Verified }},
+	{"status", func(l models.ComparedLocation) interface{} { return l.Status }},
+	{"rating", func(l models.ComparedLocation) interface{} { return l.Rating }},
+	{"price_range", func(l models.ComparedLocation) interface{} { return l.PriceRange }},
+	{"payment_methods", func(l models.ComparedLocation) interface{} { return l.PaymentMethods }},
+	{"accessibility", func(l models.ComparedLocation) interface{} { return l.Accessibility }},
+	{"distance", func(l models.ComparedLocation) interface{} { return l.DistanceM }},
+}
+
+// Compare mengembalikan beberapa lokasi berdampingan untuk tampilan
+// perbandingan di frontend, lengkap dengan rating, atribut dan jarak dari
+// ref (opsional). Sama seperti DistanceMatrix, jarak dihitung dari
+// koordinat yang dilihat viewer.
+func (s *LocationService) Compare(ctx context.Context, viewer models.User, ids []primitive.ObjectID, ref *models.Coordinates) (*models.LocationComparison, error) {
+	var v validator
+	v.check(len(ids) >= 2 && len(ids) <= maxCompareLocations, "ids", RuleRange, Params{"min": 2, "max": maxCompareLocations})
+	seen := map[primitive.ObjectID]bool{}
+	for i, id := range ids {
+		v.check(!seen[id], fmt.Sprintf("ids.%d", i), RuleNotEqual, Params{"field": "id lain"})
+		seen[id] = true
+	}
+	if ref != nil {
+		v.check(ref.Lat >= -90 && ref.Lat <= 90, "lat", RuleRange, Params{"min": -90, "max": 90})
+		v.check(ref.Lng >= -180 && ref.Lng <= 180, "lng", RuleRange, Params{"min": -180, "max": 180})
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	result := &models.LocationComparison{Reference: ref, Locations: make([]models.ComparedLocation, 0, len(ids)), Differences: []string{}}
+	for i, id := range ids {
+		loc, err := s.Get(ctx, viewer, id, false)
+		if errors.Is(err, ErrLocationNotFound) {
+			v.check(false, fmt.Sprintf("ids.%d", i), RuleNotFound, nil)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		result.Locations = append(result.Locations, compared(loc, ref))
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	for _, f := range compareFields {
+		first := f.value(result.Locations[0])
+		for _, l := range result.Locations[1:] {
+			if !reflect.DeepEqual(first, f.value(l)) {
+				result.Differences = append(result.Differences, f.name)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func compared(loc *models.Location, ref *models.Coordinates) models.ComparedLocation {
+	c := models.ComparedLocation{
+		ID:                     loc.ID,
+		Name:                   loc.Name,
+		Category:               loc.Category,
+		Address:                loc.Address,
+		Coordinates:            loc.Coordinates,
+		ApproximateCoordinates: loc.ApproximateCoordinates,
+		Verified:               loc.Verified,
+		Status:                 loc.Status,
+		PriceRange:             loc.PriceRange,
+		PaymentMethods:         loc.PaymentMethods,
+		ElevationM:             loc.ElevationM,
+		FavoritesCount:         loc.FavoritesCount,
+		CheckInsCount:          loc.CheckInsCount,
+		Freshness:              loc.Freshness,
+	}
+	if c.PaymentMethods == nil {
+		c.PaymentMethods = []string{}
+	}
+	if loc.Rating != nil {
+		c.Rating = *loc.Rating
+	}
+	if loc.Accessibility != nil {
+		c.Accessibility = *loc.Accessibility
+	}
+	if ref != nil {
+		d := ref.DistanceM(loc.Coordinates)
+		c.DistanceM = &d
+	}
+	return c
+}
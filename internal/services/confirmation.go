@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// DefaultFreshnessHalfLife dipakai jika LocationOptions.FreshnessHalfLife kosong
+	DefaultFreshnessHalfLife = 180 * 24 * time.Hour
+	// User yang sama baru boleh mengonfirmasi lokasi yang sama lagi setelah ini
+	confirmationCooldown = 30 * 24 * time.Hour
+)
+
+func (s *LocationService) freshnessHalfLife() time.Duration {
+	if s.opts.FreshnessHalfLife > 0 {
+		return s.opts.FreshnessHalfLife
+	}
+	return DefaultFreshnessHalfLife
+}
+
+// withFreshness mengisi skor kesegaran lokasi untuk response.
+func (s *LocationService) withFreshness(loc *models.Location, now time.Time) {
+	f := loc.FreshnessAt(now, s.freshnessHalfLife())
+	loc.Freshness = &f
+}
+
+// Confirm mencatat bahwa u menyatakan data lokasi masih akurat.
+// Setiap user hanya dihitung sekali per lokasi dalam confirmationCooldown.
+func (s *LocationService) Confirm(ctx context.Context, u models.User, id primitive.ObjectID) (*models.Location, error) {
+	if _, err := s.locations.FindByID(ctx, id); errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrLocationNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	err := s.confirmations.Create(ctx, &models.Confirmation{
+		LocationID: id,
+		UserID:     u.ID,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(confirmationCooldown),
+	})
+	if errors.Is(err, repositories.ErrDuplicate) {
+		return nil, ErrAlreadyConfirmed
+	} else if err != nil {
+		return nil, err
+	}
+	loc, err := s.locations.Confirm(ctx, id, now)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrLocationNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	conceal, err := s.concealer(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	conceal(loc)
+	s.withFreshness(loc, now)
+	s.audit.Record(ctx, AuditEvent{Action: "location.confirm", ResourceType: AuditLocation, ResourceID: id.Hex(),
+		After: map[string]interface{}{"last_confirmed_at": now, "confirmation_count": loc.ConfirmationCount}})
+	return loc, nil
+}
+
+// Stale mengembalikan lokasi di dalam scope u yang skor kesegarannya sudah
+// di bawah 0.5 (belum dikonfirmasi selama satu half-life), yang paling lama
+// lebih dulu.
+func (s *LocationService) Stale(ctx context.Context, u models.User, opts models.ListOptions) ([]models.Location, models.PageMeta, error) {
+	p, err := listPage(repositories.StaleList, opts)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	now := time.Now().UTC()
+	locations, total, err := s.locations.Stale(ctx, now.Add(-s.freshnessHalfLife()), s.roles.Scope(ctx, u.Role), p)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	for i := range locations {
+		s.withFreshness(&locations[i], now)
+	}
+	meta := repositories.Meta(p, total, locations)
+	return locations, meta, nil
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultCounterBatch = 500
+	maxCounterBatch     = 2000
+)
+
+// ReconcileCounters menghitung ulang favorites_count dan checkins_count
+// satu batch lokasi setelah after (urut ID) dari collection favorites dan
+// checkins, lalu memperbaiki yang selisih. Counter dijaga lewat $inc
+// sehingga bisa meleset jika increment gagal atau lokasi sedang di arsip;
+// job ini juga mengisi counter dokumen lama. Dipanggil berkala (mis. cron
+// harian) dengan after=Next sampai Next kosong.
+func (s *LocationService) ReconcileCounters(ctx context.Context, after primitive.ObjectID, batch int) (models.CounterReconcileResult, error) {
+	var result models.CounterReconcileResult
+	if batch <= 0 {
+		batch = defaultCounterBatch
+	}
+	if batch > maxCounterBatch {
+		batch = maxCounterBatch
+	}
+	counters, err := s.locations.Counters(ctx, after, int64(batch))
+	if err != nil {
+		return result, err
+	}
+	var fixed []primitive.ObjectID
+	for _, c := range counters {
+		if !c.Drifted() {
+			continue
+		}
+		if err := s.locations.SetCounters(ctx, c); err != nil {
+			return result, err
+		}
+		fixed = append(fixed, c.ID)
+	}
+	s.opts.Events.Publish(ctx, "location.counters", fixed...)
+	result.Processed = len(counters)
+	result.Fixed = len(fixed)
+	if len(counters) == batch {
+		result.Next = counters[len(counters)-1].ID.Hex()
+	}
+	return result, nil
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"InfoCuy-Backend/internal/audit"
+	"InfoCuy-Backend/internal/database"
+	"InfoCuy-Backend/internal/models"
+)
+
+const (
+	// Write yang ditampung per instance selama mode baca saja
+	maxQueuedWrites = 10000
+	// Batas waktu menerapkan antrean setelah primary kembali
+	replayTimeout = 5 * time.Minute
+)
+
+type queuedWrite struct {
+	key      string
+	queuedAt time.Time
+	// Pelaku request asal, supaya audit saat replay tetap atas namanya
+	actor audit.Actor
+	apply func(ctx context.Context) error
+}
+
+// DatabaseMode menjalankan aplikasi dalam mode baca saja selama MongoDB
+// tidak punya primary. Write idempoten (favorit, preferensi) ditampung di
+// outbox lalu diterapkan setelah primary kembali; write lain ditolak
+// handler dengan 503. Outbox ada di memori instance: entry hilang jika
+// proses berhenti sebelum database pulih, jadi hanya write yang aman
+// hilang yang boleh diantrekan. Entry dengan key sama digabung dan yang
+// terakhir menang, sehingga urutan tambah/hapus favorit tetap benar.
+type DatabaseMode struct {
+	state *database.State
+	audit *AuditService
+
+	mu      sync.Mutex
+	outbox  []queuedWrite
+	applied int64
+	failed  int64
+}
+
+// NewDatabaseMode berlangganan perubahan state supaya outbox diterapkan
+// begitu database bisa ditulis lagi.
+func NewDatabaseMode(state *database.State, audit *AuditService) *DatabaseMode {
+	m := &DatabaseMode{state: state, audit: audit}
+	state.Subscribe(func(readOnly bool) {
+		if !readOnly {
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), replayTimeout)
+				defer cancel()
+				m.Replay(ctx)
+			}()
+		}
+	})
+	return m
+}
+
+// ReadOnly bernilai true selama write harus ditahan. Aman dipanggil pada
+// receiver nil.
+func (m *DatabaseMode) ReadOnly() bool {
+	return m != nil && m.state.ReadOnly()
+}
+
+// Queue menampung apply sampai database bisa ditulis. Entry lama dengan key
+// yang sama dibuang. database.ErrReadOnly jika outbox penuh.
+func (m *DatabaseMode) Queue(ctx context.Context, key string, apply func(ctx context.Context) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.drop(key)
+	if len(m.outbox) >= maxQueuedWrites {
+		return database.ErrReadOnly
+	}
+	m.outbox = append(m.outbox, queuedWrite{key: key, queuedAt: time.Now().UTC(), actor: audit.ActorFrom(ctx), apply: apply})
+	return nil
+}
+
+func (m *DatabaseMode) drop(key string) {
+	for i, w := range m.outbox {
+		if w.key == key {
+			m.outbox = append(m.outbox[:i], m.outbox[i+1:]...)
+			return
+		}
+	}
+}
+
+// Replay menerapkan outbox urut waktu masuk dan berhenti jika database
+// kembali read-only; entry sisanya tetap menunggu. Entry yang gagal karena
+// sebab lain dicatat ke log lalu dibuang.
+func (m *DatabaseMode) Replay(ctx context.Context) (applied int) {
+	for !m.ReadOnly() {
+		m.mu.Lock()
+		if len(m.outbox) == 0 {
+			m.mu.Unlock()
+			break
+		}
+		w := m.outbox[0]
+		m.outbox = m.outbox[1:]
+		m.mu.Unlock()
+
+		err := w.apply(audit.WithActor(ctx, w.actor))
+		m.mu.Lock()
+		switch {
+		case database.IsUnavailable(err):
+			// Kembalikan ke depan kecuali sudah diganti write yang lebih baru
+			if !m.queued(w.key) {
+				m.outbox = append([]queuedWrite{w}, m.outbox...)
+			}
+			pending := len(m.outbox)
+			m.mu.Unlock()
+			log.Printf("outbox database: berhenti, %d write masih menunggu: %v", pending, err)
+			return applied
+		case err != nil:
+			m.failed++
+			log.Printf("outbox database: write %s dibuang: %v", w.key, err)
+		default:
+			m.applied++
+			applied++
+		}
+		m.mu.Unlock()
+	}
+	return applied
+}
+
+func (m *DatabaseMode) queued(key string) bool {
+	for _, w := range m.outbox {
+		if w.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Pending mengembalikan jumlah write yang menunggu di outbox.
+func (m *DatabaseMode) Pending() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.outbox)
+}
+
+// Status mengembalikan mode database beserta isi outbox.
+func (m *DatabaseMode) Status() models.DatabaseStatus {
+	st := m.state.Status()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := models.DatabaseStatus{
+		ReadOnly: st.ReadOnly,
+		Forced:   st.Forced,
+		Reason:   st.Reason,
+		Since:    st.Since,
+		Outbox: models.OutboxStats{
+			Pending:  len(m.outbox),
+			Capacity: maxQueuedWrites,
+			Applied:  m.applied,
+			Failed:   m.failed,
+		},
+	}
+	if len(m.outbox) > 0 {
+		oldest := m.outbox[0].queuedAt
+		out.Outbox.Oldest = &oldest
+	}
+	return out
+}
+
+// Force memaksa mode baca saja, mis. untuk latihan failover atau sebelum
+// maintenance cluster. Audit dicatat selagi database masih bisa ditulis.
+func (m *DatabaseMode) Force(ctx context.Context, readOnly bool) models.DatabaseStatus {
+	before := m.state.Status()
+	if readOnly {
+		m.audit.Record(ctx, AuditEvent{Action: "database.read_only", ResourceType: AuditSettings, ResourceID: "database",
+			Before: before, After: map[string]interface{}{"forced": true}})
+		m.state.Force(true)
+		return m.Status()
+	}
+	m.state.Force(false)
+	m.audit.Record(ctx, AuditEvent{Action: "database.read_write", ResourceType: AuditSettings, ResourceID: "database",
+		Before: before, After: map[string]interface{}{"forced": false}})
+	return m.Status()
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Titik maksimum per sisi matriks jarak (origins dan destinations)
+const maxMatrixPoints = 25
+
+// DistanceMatrix menghitung jarak haversine setiap origin ke setiap
+// destination dalam satu panggilan, untuk tabel perbandingan di frontend.
+// Lokasi sensitif memakai koordinat yang sama dengan yang dilihat viewer,
+// jadi jarak tidak membocorkan koordinat asli.
+func (s *LocationService) DistanceMatrix(ctx context.Context, viewer models.User, in models.DistanceMatrixInput) (*models.DistanceMatrix, error) {
+	var v validator
+	sides := []struct {
+		name   string
+		points []models.MatrixPoint
+	}{{"origins", in.Origins}, {"destinations", in.Destinations}}
+	for _, side := range sides {
+		points := side.points
+		v.required(len(points) > 0, side.name)
+		v.check(len(points) <= maxMatrixPoints, side.name, RuleRange, Params{"min": 1, "max": maxMatrixPoints})
+		for i, p := range points {
+			field := fmt.Sprintf("%s.%d", side.name, i)
+			switch {
+			case (p.LocationID == nil) == (p.Coordinates == nil):
+				v.check(false, field, RuleRequireOne, Params{"fields": []string{"location_id", "coordinates"}})
+			case p.Coordinates != nil:
+				c := *p.Coordinates
+				v.check(c.Lat >= -90 && c.Lat <= 90, field+".coordinates.lat", RuleRange, Params{"min": -90, "max": 90})
+				v.check(c.Lng >= -180 && c.Lng <= 180, field+".coordinates.lng", RuleRange, Params{"min": -180, "max": 180})
+			}
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	conceal, err := s.concealer(ctx, viewer)
+	if err != nil {
+		return nil, err
+	}
+	// Lokasi yang sama di kedua sisi cukup dibaca sekali
+	found := map[primitive.ObjectID]*models.Location{}
+	resolve := func(side string, points []models.MatrixPoint) ([]models.MatrixPoint, error) {
+		resolved := make([]models.MatrixPoint, len(points))
+		for i, p := range points {
+			if p.LocationID == nil {
+				resolved[i] = models.MatrixPoint{Coordinates: p.Coordinates}
+				continue
+			}
+			loc, ok := found[*p.LocationID]
+			if !ok {
+				loc, err = s.locations.FindByID(ctx, *p.LocationID)
+				if errors.Is(err, repositories.ErrNotFound) {
+					loc = nil
+				} else if err != nil {
+					return nil, err
+				}
+				if loc != nil && !loc.Listed() && !canSeeUnlisted(ctx, s.roles, viewer, loc) {
+					loc = nil
+				}
+				if loc != nil {
+					conceal(loc)
+				}
+				found[*p.LocationID] = loc
+			}
+			if loc == nil {
+				v.check(false, fmt.Sprintf("%s.%d.location_id", side, i), RuleNotFound, nil)
+				continue
+			}
+			coords := loc.Coordinates
+			resolved[i] = models.MatrixPoint{LocationID: &loc.ID, Coordinates: &coords, Name: loc.Name, ApproximateCoordinates: loc.ApproximateCoordinates}
+		}
+		return resolved, nil
+	}
+	matrix := &models.DistanceMatrix{}
+	if matrix.Origins, err = resolve("origins", in.Origins); err != nil {
+		return nil, err
+	}
+	if matrix.Destinations, err = resolve("destinations", in.Destinations); err != nil {
+		return nil, err
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	matrix.DistancesM = make([][]float64, len(matrix.Origins))
+	for i, o := range matrix.Origins {
+		matrix.DistancesM[i] = make([]float64, len(matrix.Destinations))
+		for j, d := range matrix.Destinations {
+			matrix.DistancesM[i][j] = o.Coordinates.DistanceM(*d.Coordinates)
+		}
+	}
+	return matrix, nil
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+// Ukuran batch backfill ketinggian
+const (
+	defaultBackfillBatch = 100
+	maxBackfillBatch     = 500
+)
+
+// BackfillElevation mengisi elevation_m untuk satu batch lokasi yang belum
+// punya ketinggian. Dipanggil berulang sampai Remaining = 0.
+func (s *LocationService) BackfillElevation(ctx context.Context, batch int) (models.BackfillResult, error) {
+	var result models.BackfillResult
+	if s.opts.Elevation == nil {
+		return result, invalid("Provider elevation belum dikonfigurasi (ELEVATION_PROVIDER)")
+	}
+	if batch <= 0 {
+		batch = defaultBackfillBatch
+	}
+	if batch > maxBackfillBatch {
+		batch = maxBackfillBatch
+	}
+	locations, err := s.locations.WithoutElevation(ctx, int64(batch))
+	if err != nil {
+		return result, err
+	}
+	if len(locations) > 0 {
+		points := make([]models.Coordinates, len(locations))
+		for i, loc := range locations {
+			points[i] = loc.Coordinates
+		}
+		elevations, err := s.opts.Elevation.Lookup(ctx, points)
+		if err != nil {
+			return result, err
+		}
+		for i, loc := range locations {
+			if err := s.locations.Update(ctx, loc.ID, repositories.Fields{"elevation_m": elevations[i]}); err != nil {
+				return result, err
+			}
+			result.Updated++
+		}
+	}
+	result.Processed = len(locations)
+	result.Remaining, err = s.locations.CountWithoutElevation(ctx)
+	return result, err
+}
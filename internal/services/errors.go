@@ -0,0 +1,137 @@
+// Package services berisi logika bisnis. Service hanya bergantung pada
+// interface repository, sehingga bisa diuji tanpa MongoDB.
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+var (
+	ErrNotFound           = errors.New("data tidak ditemukan")
+	ErrForbidden          = errors.New("akses ditolak")
+	ErrEmailTaken         = errors.New("email sudah terdaftar")
+	ErrRegistrationClosed = errors.New("registrasi sedang ditutup")
+	ErrInvalidToken       = errors.New("token tidak valid")
+	ErrWrongPassword      = errors.New("password lama salah")
+	ErrAlreadyReviewed    = errors.New("lokasi sudah pernah diulas")
+	ErrAlreadyConfirmed   = errors.New("lokasi baru saja dikonfirmasi user ini")
+	ErrStreamFull         = errors.New("jumlah koneksi stream sudah maksimal")
+	ErrAlreadyModerated   = errors.New("lokasi sudah dimoderasi")
+	ErrReviewModerated    = errors.New("ulasan sudah dimoderasi")
+	ErrAlreadyCheckedIn   = errors.New("user sudah check-in di lokasi ini")
+	ErrInvalidCheckInCode = errors.New("kode check-in tidak valid")
+	ErrMailFailed         = errors.New("email gagal dikirim")
+	ErrSuggestionResolved = errors.New("usulan kategori sudah diputuskan")
+	ErrIssueRepaired      = errors.New("temuan integritas sudah diperbaiki")
+	// Lokasi di trash hasil merge; ID-nya sudah dialihkan ke lokasi lain
+	ErrLocationMerged = errors.New("lokasi sudah digabung ke lokasi lain")
+	// Login dilaporkan "bukan saya"; login ditolak sampai password direset
+	ErrPasswordResetRequired = errors.New("password harus direset")
+)
+
+// Versi ErrNotFound per resource, supaya response bisa memakai kode seperti
+// LOCATION_NOT_FOUND. errors.Is(err, ErrNotFound) tetap true.
+var (
+	ErrLocationNotFound = &NotFoundError{Resource: "location"}
+	ErrReviewNotFound   = &NotFoundError{Resource: "review"}
+	ErrPhotoNotFound    = &NotFoundError{Resource: "photo"}
+	ErrUserNotFound     = &NotFoundError{Resource: "user"}
+	ErrRoleNotFound     = &NotFoundError{Resource: "role"}
+	ErrCategoryNotFound = &NotFoundError{Resource: "category"}
+	ErrCampaignNotFound = &NotFoundError{Resource: "campaign"}
+	ErrRegionNotFound   = &NotFoundError{Resource: "region"}
+	ErrPostcodeNotFound = &NotFoundError{Resource: "postcode"}
+	ErrAddressNotFound  = &NotFoundError{Resource: "address"}
+	// Job pemindahan pemilik lokasi
+	ErrReassignmentNotFound = &NotFoundError{Resource: "reassignment"}
+	// Jadwal export berkala
+	ErrExportNotFound = &NotFoundError{Resource: "export"}
+	// Email yang gagal dikirim
+	ErrDeadLetterNotFound = &NotFoundError{Resource: "dead_letter"}
+	// Usulan kategori dari user
+	ErrSuggestionNotFound = &NotFoundError{Resource: "category_suggestion"}
+	// Penanda incident di halaman status
+	ErrIncidentNotFound = &NotFoundError{Resource: "status_incident"}
+	// Job geocoding import
+	ErrImportJobNotFound = &NotFoundError{Resource: "import_job"}
+	// Pemeriksaan integritas belum pernah dijalankan
+	ErrIntegrityReportNotFound = &NotFoundError{Resource: "integrity_report"}
+	// Temuan di laporan integritas terakhir
+	ErrIntegrityIssueNotFound = &NotFoundError{Resource: "integrity_issue"}
+	// Feed GTFS partner yang diambil berkala
+	ErrTransitSourceNotFound = &NotFoundError{Resource: "transit_source"}
+	// Consumer log event yang belum pernah menyimpan offset
+	ErrEventConsumerNotFound = &NotFoundError{Resource: "event_consumer"}
+)
+
+// NotFoundError berarti resource yang diminta tidak ada (atau tidak boleh
+// dilihat requestor).
+type NotFoundError struct {
+	Resource string
+}
+
+func (e *NotFoundError) Error() string { return e.Resource + " tidak ditemukan" }
+
+func (e *NotFoundError) Is(target error) bool { return target == ErrNotFound }
+
+// ValidationError berarti input dari client tidak valid (HTTP 400).
+type ValidationError struct {
+	Message string
+	// Terisi jika kesalahan bisa ditunjuk per field
+	Fields []FieldError
+}
+
+// FieldError adalah kesalahan pada satu field input. Field memakai nama
+// JSON, dengan titik untuk field bersarang (mis. coordinates.lat).
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	// Salah satu Rule*, beserta nilai yang disisipkan ke Message
+	Rule   string `json:"rule,omitempty"`
+	Params Params `json:"params,omitempty"`
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Fields) == 0 {
+		return e.Message
+	}
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = f.Field + ": " + f.Message
+	}
+	return strings.Join(parts, "; ")
+}
+
+func invalid(format string, args ...interface{}) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
+
+// CredentialError berarti login gagal. Reason dipakai untuk audit
+// (unknown_email | bad_password), jangan dikirim ke client.
+type CredentialError struct {
+	Reason string
+}
+
+func (e *CredentialError) Error() string { return "email atau password salah" }
+
+// AccountLockedError berarti login ditolak karena akun dikunci sementara
+// setelah terlalu banyak password salah.
+type AccountLockedError struct {
+	Until time.Time
+}
+
+func (e *AccountLockedError) Error() string { return "akun dikunci sementara" }
+
+// QuotaExceededError dikembalikan saat user sudah mencapai kuota lokasi.
+type QuotaExceededError struct {
+	Usage models.QuotaUsage
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("Kuota lokasi habis (%d dari %d)", e.Usage.Used, e.Usage.Limit)
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"sync"
+	"time"
+
+	"InfoCuy-Backend/internal/audit"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Percobaan menambah log saat seq direbut instance lain
+	maxEventAppendAttempts = 5
+	defaultEventLimit      = 100
+	maxEventLimit          = 1000
+)
+
+var eventConsumerPattern = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// EventLog menyimpan setiap LocationChanged ke collection events dengan
+// seq yang naik tanpa celah, supaya consumer internal bisa membangun ulang
+// proyeksi atau menyusul setelah mati tanpa backfill khusus. Seperti
+// subscriber lain, event ditulis setelah perubahan tersimpan; jika proses
+// mati di antaranya event itu tidak tercatat.
+type EventLog struct {
+	repo repositories.EventRepository
+	// Menyerialkan penambahan log di proses ini; antar instance dijaga
+	// index unik seq
+	mu sync.Mutex
+}
+
+func NewEventLog(repo repositories.EventRepository, events *LocationEvents) *EventLog {
+	l := &EventLog{repo: repo}
+	events.Subscribe(l.handle)
+	return l
+}
+
+func (l *EventLog) handle(ctx context.Context, ev LocationChanged) {
+	event := models.Event{
+		ID:          primitive.NewObjectID(),
+		Time:        time.Now().UTC().Truncate(time.Millisecond),
+		Type:        ev.Action,
+		Actor:       audit.ActorFrom(ctx).Email,
+		LocationIDs: ev.IDs,
+	}
+	ctx = context.WithoutCancel(ctx)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var err error
+	for attempt := 0; attempt < maxEventAppendAttempts; attempt++ {
+		if err = l.append(ctx, &event); !errors.Is(err, repositories.ErrDuplicate) {
+			break
+		}
+	}
+	if err != nil {
+		log.Printf("event log: gagal menyimpan %s: %v", ev.Action, err)
+	}
+}
+
+func (l *EventLog) append(ctx context.Context, ev *models.Event) error {
+	head, err := l.head(ctx)
+	if err != nil {
+		return err
+	}
+	ev.Seq = head + 1
+	return l.repo.Insert(ctx, ev)
+}
+
+// head mengembalikan seq terbesar, 0 jika log masih kosong.
+func (l *EventLog) head(ctx context.Context) (int64, error) {
+	last, err := l.repo.Last(ctx)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return last.Seq, nil
+}
+
+// EventParams adalah query GET /events. Jika AfterSeq nil dan Consumer
+// diisi, pembacaan dilanjutkan dari offset yang disimpan consumer itu.
+type EventParams struct {
+	AfterSeq *int64
+	Consumer string
+	Limit    int
+}
+
+// EventPage adalah satu halaman log event.
+type EventPage struct {
+	Events []models.Event
+	// Seq yang dipakai sebagai after_seq halaman berikutnya
+	NextSeq int64
+	More    bool
+}
+
+// List mengembalikan event setelah after_seq (atau offset consumer), urut
+// seq naik.
+func (l *EventLog) List(ctx context.Context, p EventParams) (*EventPage, error) {
+	var v validator
+	if p.AfterSeq != nil {
+		v.check(*p.AfterSeq >= 0, "after_seq", RuleNotNegative, nil)
+	}
+	if p.Consumer != "" {
+		v.check(eventConsumerPattern.MatchString(p.Consumer), "consumer", RuleType, Params{"type": "nama consumer (a-z, 0-9, - dan _, maks 32)"})
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	limit := p.Limit
+	if limit <= 0 {
+		limit = defaultEventLimit
+	}
+	limit = min(limit, maxEventLimit)
+	var after int64
+	switch {
+	case p.AfterSeq != nil:
+		after = *p.AfterSeq
+	case p.Consumer != "":
+		offset, err := l.repo.Offset(ctx, p.Consumer)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return nil, err
+		}
+		if offset != nil {
+			after = offset.Seq
+		}
+	}
+	events, err := l.repo.After(ctx, after, int64(limit)+1)
+	if err != nil {
+		return nil, err
+	}
+	page := &EventPage{Events: events, NextSeq: after}
+	if len(events) > limit {
+		page.Events, page.More = events[:limit], true
+	}
+	if n := len(page.Events); n > 0 {
+		page.NextSeq = page.Events[n-1].Seq
+	}
+	return page, nil
+}
+
+// Offset mengembalikan offset tersimpan consumer.
+func (l *EventLog) Offset(ctx context.Context, consumer string) (*models.EventOffset, error) {
+	offset, err := l.repo.Offset(ctx, consumer)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrEventConsumerNotFound
+	}
+	return offset, err
+}
+
+// SetOffset menyimpan seq terakhir yang sudah diproses consumer. Offset
+// boleh mundur (mis. untuk replay) tetapi tidak melewati event terakhir.
+func (l *EventLog) SetOffset(ctx context.Context, u models.User, consumer string, in models.EventOffsetInput) (*models.EventOffset, error) {
+	var v validator
+	v.check(eventConsumerPattern.MatchString(consumer), "consumer", RuleType, Params{"type": "nama consumer (a-z, 0-9, - dan _, maks 32)"})
+	v.required(in.Seq != nil, "seq")
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	head, err := l.head(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v.check(*in.Seq >= 0 && *in.Seq <= head, "seq", RuleRange, Params{"min": 0, "max": head})
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	offset := models.EventOffset{Consumer: consumer, Seq: *in.Seq, UpdatedAt: time.Now().UTC(), UpdatedBy: u.Email}
+	if err := l.repo.SetOffset(ctx, offset); err != nil {
+		return nil, err
+	}
+	return &offset, nil
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LocationChanged diterbitkan setelah data lokasi (atau rating-nya)
+// berubah di write model.
+type LocationChanged struct {
+	// Nama perubahan, sama dengan action di audit log (mis. location.update)
+	Action string
+	IDs    []primitive.ObjectID
+}
+
+// LocationEvents meneruskan LocationChanged ke subscriber secara sinkron,
+// setelah perubahan tersimpan. Subscriber didaftarkan saat aplikasi dirakit
+// dan tidak boleh menggagalkan operasi yang menerbitkan event. Aman
+// dipanggil pada receiver nil (tidak ada subscriber).
+type LocationEvents struct {
+	subscribers []func(ctx context.Context, ev LocationChanged)
+}
+
+func NewLocationEvents() *LocationEvents {
+	return &LocationEvents{}
+}
+
+// Subscribe mendaftarkan fn. Hanya dipanggil saat startup.
+func (e *LocationEvents) Subscribe(fn func(ctx context.Context, ev LocationChanged)) {
+	e.subscribers = append(e.subscribers, fn)
+}
+
+func (e *LocationEvents) Publish(ctx context.Context, action string, ids ...primitive.ObjectID) {
+	if e == nil || len(ids) == 0 {
+		return
+	}
+	ev := LocationChanged{Action: action, IDs: ids}
+	for _, fn := range e.subscribers {
+		fn(ctx, ev)
+	}
+}
@@ -0,0 +1,405 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/geoio"
+	"InfoCuy-Backend/internal/httpclient"
+	"InfoCuy-Backend/internal/jobs"
+	"InfoCuy-Backend/internal/mailer"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/objectstore"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxExportNameLen    = 200
+	maxExportPrefixLen  = 200
+	defaultExportBatch  = 5
+	maxExportBatch      = 20
+	defaultExportPrefix = "exports"
+	// File dibuat di memori sebelum dikirim; dataset yang lebih besar dari
+	// ini sebaiknya diambil langsung lewat GET /locations/export
+	maxExportBytes = 25 << 20
+)
+
+// Tanggal di nama file dan isi email memakai WIB
+var exportZone = time.FixedZone("WIB", 7*60*60)
+
+var exportIntervals = map[string]time.Duration{
+	models.ExportDaily:  24 * time.Hour,
+	models.ExportWeekly: 7 * 24 * time.Hour,
+}
+
+// ScheduledExportService mengelola export lokasi berkala. Seperti job lain
+// tidak ada worker terpisah: POST /admin/jobs/scheduled-exports dipanggil
+// cron, menjalankan jadwal yang jatuh tempo lalu mengirim file ke email,
+// webhook atau bucket S3.
+type ScheduledExportService struct {
+	exports    repositories.ScheduledExportRepository
+	locations  *LocationService
+	categories *CategoryService
+	mail       mailer.Mailer
+	// nil atau bukan S3 = tujuan s3 tidak tersedia
+	store   objectstore.Store
+	audit   *AuditService
+	webhook *httpclient.Client
+	// Slot pengiriman webhook; nil = tanpa antrean
+	queue *jobs.Queue
+}
+
+func NewScheduledExportService(exports repositories.ScheduledExportRepository, locations *LocationService, categories *CategoryService,
+	mail mailer.Mailer, store objectstore.Store, audit *AuditService, queue *jobs.Queue) *ScheduledExportService {
+	return &ScheduledExportService{
+		exports:    exports,
+		locations:  locations,
+		categories: categories,
+		mail:       mail,
+		store:      store,
+		audit:      audit,
+		queue:      queue,
+		// POST file tidak diulang otomatis supaya penerima tidak dapat ganda
+		webhook: httpclient.New("export_webhook", httpclient.Options{Timeout: 30 * time.Second}),
+	}
+}
+
+func (s *ScheduledExportService) s3Enabled() bool {
+	return s.store != nil && s.store.Name() == "s3"
+}
+
+// redact menyembunyikan secret webhook dari response.
+func redact(e *models.ScheduledExport) *models.ScheduledExport {
+	e.Delivery.HasSecret = e.Delivery.Secret != ""
+	e.Delivery.Secret = ""
+	return e
+}
+
+// build memvalidasi input menjadi jadwal. existing diisi saat update supaya
+// secret webhook lama tetap dipakai jika tidak dikirim ulang.
+func (s *ScheduledExportService) build(ctx context.Context, in models.ScheduledExportInput, existing *models.ScheduledExport) (models.ScheduledExport, error) {
+	e := models.ScheduledExport{
+		Name:     strings.TrimSpace(in.Name),
+		Format:   strings.ToLower(strings.TrimSpace(in.Format)),
+		Filter:   in.Filter,
+		Interval: strings.TrimSpace(in.Interval),
+		Delivery: models.ExportDelivery{Type: strings.TrimSpace(in.Delivery.Type)},
+		Enabled:  in.Enabled == nil || *in.Enabled,
+	}
+	if e.Format == "" {
+		e.Format = string(geoio.GeoJSON)
+	}
+	if e.Interval == "" {
+		e.Interval = models.ExportWeekly
+	}
+	var v validator
+	v.required(e.Name != "", "name")
+	v.maxLength("name", e.Name, maxExportNameLen)
+	v.oneOf("format", e.Format, []string{string(geoio.GeoJSON), string(geoio.CSV)})
+	v.oneOf("interval", e.Interval, models.ExportIntervals)
+	v.oneOf("delivery.type", e.Delivery.Type, models.ExportTargets)
+	switch d := in.Delivery; e.Delivery.Type {
+	case models.DeliverEmail:
+		e.Delivery.Email = strings.ToLower(strings.TrimSpace(d.Email))
+		v.email("delivery.email", e.Delivery.Email)
+	case models.DeliverWebhook:
+		e.Delivery.URL = strings.TrimSpace(d.URL)
+		e.Delivery.Secret = d.Secret
+		if e.Delivery.Secret == "" && existing != nil && existing.Delivery.Type == models.DeliverWebhook {
+			e.Delivery.Secret = existing.Delivery.Secret
+		}
+		u, err := url.Parse(e.Delivery.URL)
+		if e.Delivery.URL == "" {
+			v.required(false, "delivery.url")
+		} else {
+			v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", "delivery.url", RuleURL, nil)
+		}
+	case models.DeliverS3:
+		e.Delivery.Prefix = strings.Trim(strings.TrimSpace(d.Prefix), "/")
+		if e.Delivery.Prefix == "" {
+			e.Delivery.Prefix = defaultExportPrefix
+		}
+		v.maxLength("delivery.prefix", e.Delivery.Prefix, maxExportPrefixLen)
+		v.check(s.s3Enabled(), "delivery.type", RuleOneOf, Params{"allowed": []string{models.DeliverEmail, models.DeliverWebhook}})
+	}
+	if err := v.err(); err != nil {
+		return e, err
+	}
+	if e.Filter.Category != "" {
+		resolve, err := s.categories.Resolver(ctx)
+		if err != nil {
+			return e, err
+		}
+		if e.Filter.Category, err = resolve(e.Filter.Category); err != nil {
+			return e, err
+		}
+	}
+	e.NextRunAt = time.Now().UTC().Truncate(time.Millisecond)
+	if in.StartsAt != nil {
+		e.NextRunAt = in.StartsAt.UTC().Truncate(time.Millisecond)
+	} else if existing != nil {
+		e.NextRunAt = existing.NextRunAt
+	}
+	return e, nil
+}
+
+// Create menyimpan jadwal baru.
+func (s *ScheduledExportService) Create(ctx context.Context, u models.User, in models.ScheduledExportInput) (*models.ScheduledExport, error) {
+	e, err := s.build(ctx, in, nil)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	e.CreatedBy, e.CreatedAt, e.UpdatedAt = u.Email, now, now
+	if err := s.exports.Create(ctx, &e); err != nil {
+		return nil, err
+	}
+	redact(&e)
+	s.audit.Record(ctx, AuditEvent{Action: "export.create", ResourceType: AuditExport, ResourceID: e.ID.Hex(), After: e})
+	return &e, nil
+}
+
+func (s *ScheduledExportService) find(ctx context.Context, id primitive.ObjectID) (*models.ScheduledExport, error) {
+	e, err := s.exports.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrExportNotFound
+	}
+	return e, err
+}
+
+func (s *ScheduledExportService) Get(ctx context.Context, id primitive.ObjectID) (*models.ScheduledExport, error) {
+	e, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return redact(e), nil
+}
+
+func (s *ScheduledExportService) List(ctx context.Context, opts models.ListOptions) ([]models.ScheduledExport, models.PageMeta, error) {
+	p, err := listPage(repositories.ScheduledExportList, opts)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	exports, total, err := s.exports.List(ctx, p)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	for i := range exports {
+		redact(&exports[i])
+	}
+	meta := repositories.Meta(p, total, exports)
+	return exports, meta, nil
+}
+
+// Update mengganti jadwal. Tanpa starts_at, jadwal berikutnya tidak berubah.
+func (s *ScheduledExportService) Update(ctx context.Context, id primitive.ObjectID, in models.ScheduledExportInput) (*models.ScheduledExport, error) {
+	existing, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	e, err := s.build(ctx, in, existing)
+	if err != nil {
+		return nil, err
+	}
+	e.ID, e.LastRun, e.CreatedBy, e.CreatedAt = existing.ID, existing.LastRun, existing.CreatedBy, existing.CreatedAt
+	e.UpdatedAt = time.Now().UTC()
+	if err := s.exports.Replace(ctx, &e); errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrExportNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	redact(&e)
+	s.audit.Record(ctx, AuditEvent{Action: "export.update", ResourceType: AuditExport, ResourceID: id.Hex(), Before: redact(existing), After: e})
+	return &e, nil
+}
+
+func (s *ScheduledExportService) Delete(ctx context.Context, id primitive.ObjectID) error {
+	existing, err := s.find(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.exports.Delete(ctx, id); errors.Is(err, repositories.ErrNotFound) {
+		return ErrExportNotFound
+	} else if err != nil {
+		return err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "export.delete", ResourceType: AuditExport, ResourceID: id.Hex(), Before: redact(existing)})
+	return nil
+}
+
+// RunNow menjalankan jadwal sekarang tanpa menggeser jadwal berikutnya.
+func (s *ScheduledExportService) RunNow(ctx context.Context, id primitive.ObjectID) (*models.ExportRun, error) {
+	e, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	run := s.run(ctx, *e)
+	if err := s.exports.SetLastRun(ctx, id, run); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "export.run", ResourceType: AuditExport, ResourceID: id.Hex(), After: run})
+	return &run, nil
+}
+
+// RunDue menjalankan satu batch jadwal yang jatuh tempo. Jadwal diklaim
+// (next_run_at digeser) sebelum dijalankan, jadi kegagalan tidak diulang
+// sampai jadwal berikutnya; hasilnya tercatat di last_run.
+func (s *ScheduledExportService) RunDue(ctx context.Context, batch int) (models.ScheduledExportResult, error) {
+	var result models.ScheduledExportResult
+	if batch <= 0 {
+		batch = defaultExportBatch
+	}
+	if batch > maxExportBatch {
+		batch = maxExportBatch
+	}
+	now := time.Now().UTC()
+	due, err := s.exports.Due(ctx, now, int64(batch))
+	if err != nil {
+		return result, err
+	}
+	for _, e := range due {
+		if err := s.exports.Claim(ctx, e.ID, e.NextRunAt, nextRun(e, now)); errors.Is(err, repositories.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return result, err
+		}
+		run := s.run(ctx, e)
+		if err := s.exports.SetLastRun(context.WithoutCancel(ctx), e.ID, run); err != nil {
+			log.Println("simpan hasil export", e.ID.Hex()+":", err)
+		}
+		result.Ran++
+		if run.Status == models.ExportRunFailed {
+			result.Failed++
+		}
+	}
+	result.Remaining, err = s.exports.CountDue(ctx, now)
+	return result, err
+}
+
+// nextRun melompati jadwal yang terlewat (mis. cron sempat mati) supaya
+// tidak ada rentetan export yang sama sekaligus.
+func nextRun(e models.ScheduledExport, now time.Time) time.Time {
+	step := exportIntervals[e.Interval]
+	if step == 0 {
+		step = exportIntervals[models.ExportWeekly]
+	}
+	next := e.NextRunAt.Add(step)
+	for !next.After(now) {
+		next = next.Add(step)
+	}
+	return next.UTC().Truncate(time.Millisecond)
+}
+
+// countingWriter menghitung lokasi yang ditulis ke file export.
+type countingWriter struct {
+	geoio.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(loc models.Location) error {
+	w.n++
+	return w.Writer.Write(loc)
+}
+
+// limitedBuffer menolak tulisan setelah maxExportBytes.
+type limitedBuffer struct {
+	bytes.Buffer
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > maxExportBytes {
+		return 0, fmt.Errorf("file export melebihi %d MB", maxExportBytes>>20)
+	}
+	return b.Buffer.Write(p)
+}
+
+// run membuat file export lalu mengirimnya. Error dicatat di hasil, tidak
+// dikembalikan, supaya satu tujuan yang gagal tidak menghentikan batch.
+func (s *ScheduledExportService) run(ctx context.Context, e models.ScheduledExport) models.ExportRun {
+	run := models.ExportRun{At: time.Now().UTC().Truncate(time.Millisecond), Status: models.ExportRunOK}
+	format, _ := geoio.ParseFormat(e.Format)
+	var buf limitedBuffer
+	w := &countingWriter{Writer: geoio.NewWriter(format, &buf)}
+	// Viewer kosong: hanya lokasi yang tayang, dengan koordinat versi publik
+	err := s.locations.Export(ctx, ExportParams{Category: e.Filter.Category}, w)
+	if err == nil {
+		run.Locations, run.Bytes = w.n, buf.Len()
+		name := fmt.Sprintf("%s-%s.%s", e.ID.Hex(), run.At.In(exportZone).Format("2006-01-02"), format)
+		run.Target, err = s.deliver(ctx, e, format, name, buf.Bytes())
+	}
+	if err != nil {
+		run.Status, run.Error = models.ExportRunFailed, err.Error()
+		log.Println("export terjadwal", e.ID.Hex()+":", err)
+	}
+	return run
+}
+
+// deliver mengirim file ke tujuan jadwal dan mengembalikan tujuan akhirnya.
+func (s *ScheduledExportService) deliver(ctx context.Context, e models.ScheduledExport, format geoio.Format, name string, data []byte) (string, error) {
+	switch e.Delivery.Type {
+	case models.DeliverEmail:
+		err := s.mail.Send(ctx, mailer.Message{
+			To:      e.Delivery.Email,
+			Subject: "Export InfoCuy: " + e.Name,
+			Body: fmt.Sprintf("Terlampir export lokasi %q (%s) per %s.\n\nEmail ini dikirim otomatis oleh jadwal export InfoCuy.",
+				e.Name, format, time.Now().In(exportZone).Format("2 January 2006 15:04 WIB")),
+			Attachments: []mailer.Attachment{{Name: name, ContentType: format.ContentType(), Data: data}},
+			Bulk:        true,
+		})
+		return e.Delivery.Email, err
+	case models.DeliverWebhook:
+		return e.Delivery.URL, s.postWebhook(ctx, e, format, name, data)
+	case models.DeliverS3:
+		if !s.s3Enabled() {
+			return "", errors.New("object store S3 tidak dikonfigurasi")
+		}
+		obj, err := s.store.Put(ctx, path.Join(e.Delivery.Prefix, name), format.ContentType(), data)
+		return obj.Key, err
+	}
+	return "", fmt.Errorf("tujuan export %q tidak dikenal", e.Delivery.Type)
+}
+
+// postWebhook mengirim file sebagai body POST. Jika secret diset, header
+// X-InfoCuy-Signature berisi sha256=<HMAC-SHA256 body> untuk diverifikasi
+// penerima.
+func (s *ScheduledExportService) postWebhook(ctx context.Context, e models.ScheduledExport, format geoio.Format, name string, data []byte) error {
+	release, err := s.queue.Acquire(ctx, jobs.Webhook)
+	if err != nil {
+		return err
+	}
+	defer release()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.Delivery.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", format.ContentType())
+	req.Header.Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	req.Header.Set("X-InfoCuy-Export", e.ID.Hex())
+	if e.Delivery.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(e.Delivery.Secret))
+		mac.Write(data)
+		req.Header.Set("X-InfoCuy-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	resp, err := s.webhook.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook membalas %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FavoriteService mengelola lokasi yang disimpan user.
+type FavoriteService struct {
+	favorites  repositories.FavoriteRepository
+	locations  repositories.LocationRepository
+	categories *CategoryService
+	roles      *RoleService
+	// favorites_count ikut berubah di map_view
+	events *LocationEvents
+	// Selama database read-only favorit diantrekan
+	database *DatabaseMode
+}
+
+// Favorit lokasi yang di-purge ikut dihapus lewat events.
+func NewFavoriteService(favorites repositories.FavoriteRepository, locations repositories.LocationRepository,
+	categories *CategoryService, roles *RoleService, events *LocationEvents, database *DatabaseMode) *FavoriteService {
+	s := &FavoriteService{favorites: favorites, locations: locations, categories: categories, roles: roles, events: events, database: database}
+	events.Subscribe(s.handle)
+	return s
+}
+
+// Add menyimpan lokasi ke favorit u. created false jika lokasi sudah ada
+// di favorit sebelumnya. Lokasi yang belum tayang (pending/ditolak)
+// dianggap tidak ada, sama seperti di detail lokasi.
+func (s *FavoriteService) Add(ctx context.Context, u models.User, locationID primitive.ObjectID) (models.FavoriteStatus, bool, error) {
+	loc, err := s.locations.FindByID(ctx, locationID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return models.FavoriteStatus{}, false, ErrLocationNotFound
+	} else if err != nil {
+		return models.FavoriteStatus{}, false, err
+	}
+	if !loc.Listed() && !canSeeUnlisted(ctx, s.roles, u, loc) {
+		return models.FavoriteStatus{}, false, ErrLocationNotFound
+	}
+	if s.database.ReadOnly() {
+		status, err := s.queue(ctx, u, locationID, true)
+		return status, false, err
+	}
+	created, err := s.add(ctx, u, locationID)
+	if err != nil {
+		return models.FavoriteStatus{}, false, err
+	}
+	status, err := s.status(ctx, locationID, true)
+	return status, created, err
+}
+
+func (s *FavoriteService) add(ctx context.Context, u models.User, locationID primitive.ObjectID) (bool, error) {
+	err := s.favorites.Add(ctx, &models.Favorite{UserID: u.ID, LocationID: locationID, CreatedAt: time.Now().UTC()})
+	if errors.Is(err, repositories.ErrDuplicate) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	s.count(ctx, "favorite.add", locationID, 1)
+	return true, nil
+}
+
+// Remove menghapus lokasi dari favorit u. Lokasi yang tidak ada di favorit
+// tidak dianggap error supaya client bisa mengulang request.
+func (s *FavoriteService) Remove(ctx context.Context, u models.User, locationID primitive.ObjectID) (models.FavoriteStatus, error) {
+	if s.database.ReadOnly() {
+		return s.queue(ctx, u, locationID, false)
+	}
+	if err := s.remove(ctx, u, locationID); err != nil {
+		return models.FavoriteStatus{}, err
+	}
+	return s.status(ctx, locationID, false)
+}
+
+func (s *FavoriteService) remove(ctx context.Context, u models.User, locationID primitive.ObjectID) error {
+	err := s.favorites.Remove(ctx, u.ID, locationID)
+	if err == nil {
+		s.count(ctx, "favorite.remove", locationID, -1)
+	} else if !errors.Is(err, repositories.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// queue menyimpan tambah/hapus favorit di outbox selama database
+// read-only. Keduanya idempoten dan memakai key yang sama, jadi hanya
+// keputusan terakhir user yang diterapkan.
+func (s *FavoriteService) queue(ctx context.Context, u models.User, locationID primitive.ObjectID, favorited bool) (models.FavoriteStatus, error) {
+	err := s.database.Queue(ctx, "favorite:"+u.ID.Hex()+":"+locationID.Hex(), func(ctx context.Context) error {
+		if favorited {
+			_, err := s.add(ctx, u, locationID)
+			return err
+		}
+		return s.remove(ctx, u, locationID)
+	})
+	if err != nil {
+		return models.FavoriteStatus{}, err
+	}
+	status, err := s.status(ctx, locationID, favorited)
+	status.Queued = true
+	return status, err
+}
+
+// count mengubah favorites_count lokasi. Kegagalan hanya dicatat; favorit
+// tetap tersimpan dan counter diperbaiki job rekonsiliasi.
+func (s *FavoriteService) count(ctx context.Context, action string, locationID primitive.ObjectID, delta int64) {
+	if err := s.locations.IncrementCounter(ctx, locationID, repositories.CounterFavorites, delta); err != nil {
+		log.Println("favorites_count lokasi", locationID.Hex()+":", err)
+		return
+	}
+	s.events.Publish(ctx, action, locationID)
+}
+
+func (s *FavoriteService) status(ctx context.Context, locationID primitive.ObjectID, favorited bool) (models.FavoriteStatus, error) {
+	count, err := s.favorites.CountByLocation(ctx, locationID)
+	return models.FavoriteStatus{Favorited: favorited, FavoritesCount: count}, err
+}
+
+// List mengembalikan lokasi favorit u lengkap dengan rating, yang terakhir
+// disimpan lebih dulu. Lokasi di trash atau arsip tidak ikut tampil.
+func (s *FavoriteService) List(ctx context.Context, u models.User, opts models.ListOptions) ([]models.Location, models.PageMeta, error) {
+	p, err := listPage(repositories.FavoriteList, opts)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	locations, total, err := s.favorites.Locations(ctx, u.ID, p)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	conceal, err := concealer(ctx, s.categories, s.roles, u)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	for i := range locations {
+		conceal(&locations[i])
+	}
+	meta := repositories.Meta(p, total, locations)
+	return locations, meta, nil
+}
+
+func (s *FavoriteService) handle(ctx context.Context, ev LocationChanged) {
+	if ev.Action != "location.purge" {
+		return
+	}
+	for _, id := range ev.IDs {
+		if _, err := s.favorites.DeleteByLocation(context.WithoutCancel(ctx), id); err != nil {
+			log.Println("hapus favorit lokasi", id.Hex()+":", err)
+		}
+	}
+}
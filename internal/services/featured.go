@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	featuredSettingsKey = "featured"
+	maxFeaturedSlots    = 20
+	maxFeaturedCaption  = 80
+)
+
+// FeaturedTTL adalah umur cache carousel di server dan di client. Admin
+// yang mengubah carousel langsung membuang cache instance yang menerima
+// request; instance lain menyusul paling lambat setelah FeaturedTTL.
+const FeaturedTTL = time.Minute
+
+// FeaturedService mengelola carousel lokasi pilihan di beranda: admin
+// menyusun urutan dan jendela tayang tiap slot, publik membaca slot yang
+// sedang aktif dari cache.
+type FeaturedService struct {
+	settings   repositories.SettingsRepository
+	locations  repositories.LocationRepository
+	categories *CategoryService
+	audit      *AuditService
+
+	mu      sync.Mutex
+	cached  []models.FeaturedItem
+	ids     map[primitive.ObjectID]bool
+	expires time.Time
+}
+
+// NewFeaturedService membuat service dan berlangganan perubahan lokasi
+// supaya lokasi yang diubah, disembunyikan atau dihapus tidak tertahan di
+// cache.
+func NewFeaturedService(settings repositories.SettingsRepository, locations repositories.LocationRepository,
+	categories *CategoryService, audit *AuditService, events *LocationEvents) *FeaturedService {
+	s := &FeaturedService{settings: settings, locations: locations, categories: categories, audit: audit}
+	events.Subscribe(s.handle)
+	return s
+}
+
+func (s *FeaturedService) handle(_ context.Context, ev LocationChanged) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ev.IDs {
+		if s.ids[id] {
+			s.expires = time.Time{}
+			return
+		}
+	}
+}
+
+func (s *FeaturedService) load(ctx context.Context) ([]models.FeaturedSlot, error) {
+	var stored models.FeaturedSettings
+	if err := s.settings.Get(ctx, featuredSettingsKey, &stored); errors.Is(err, repositories.ErrNotFound) {
+		return []models.FeaturedSlot{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if stored.Slots == nil {
+		stored.Slots = []models.FeaturedSlot{}
+	}
+	return stored.Slots, nil
+}
+
+// find mengembalikan lokasi yang boleh tampil di carousel, atau nil jika
+// sudah dihapus, diarsip atau belum tayang.
+func (s *FeaturedService) find(ctx context.Context, id primitive.ObjectID) (*models.Location, error) {
+	loc, err := s.locations.FindWithRating(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if loc.DeletedAt != nil || !loc.Listed() {
+		return nil, nil
+	}
+	return loc, nil
+}
+
+// Admin mengembalikan semua slot sesuai urutan, termasuk yang terjadwal,
+// sudah lewat atau lokasinya tidak lagi tayang.
+func (s *FeaturedService) Admin(ctx context.Context) ([]models.FeaturedEntry, error) {
+	slots, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	entries := make([]models.FeaturedEntry, len(slots))
+	for i, slot := range slots {
+		entries[i] = models.FeaturedEntry{FeaturedSlot: slot, Position: i + 1}
+		loc, err := s.find(ctx, slot.LocationID)
+		if err != nil {
+			return nil, err
+		}
+		if loc != nil {
+			item := mapViewItem(*loc)
+			entries[i].Location = &item
+			entries[i].Active = slot.ActiveAt(now)
+		}
+	}
+	return entries, nil
+}
+
+// Update mengganti seluruh isi carousel. Urutan slots adalah urutan tampil,
+// jadi drag-order di UI admin cukup mengirim ulang daftar yang sudah
+// diurutkan.
+func (s *FeaturedService) Update(ctx context.Context, slots []models.FeaturedSlot, updatedBy string) ([]models.FeaturedEntry, error) {
+	var v validator
+	v.check(len(slots) <= maxFeaturedSlots, "slots", RuleRange, Params{"min": 0, "max": maxFeaturedSlots})
+	seen := map[primitive.ObjectID]bool{}
+	for i := range slots {
+		slot := &slots[i]
+		field := fmt.Sprintf("slots.%d.", i)
+		slot.Caption = strings.TrimSpace(slot.Caption)
+		v.maxLength(field+"caption", slot.Caption, maxFeaturedCaption)
+		if slot.StartsAt != nil {
+			t := slot.StartsAt.UTC().Truncate(time.Millisecond)
+			slot.StartsAt = &t
+		}
+		if slot.EndsAt != nil {
+			t := slot.EndsAt.UTC().Truncate(time.Millisecond)
+			slot.EndsAt = &t
+		}
+		if slot.StartsAt != nil && slot.EndsAt != nil {
+			v.check(slot.EndsAt.After(*slot.StartsAt), field+"ends_at", RuleAfter, Params{"field": "starts_at"})
+		}
+		if slot.LocationID.IsZero() {
+			v.required(false, field+"location_id")
+			continue
+		}
+		v.check(!seen[slot.LocationID], field+"location_id", RuleNotEqual, Params{"field": "slot lain"})
+		seen[slot.LocationID] = true
+		loc, err := s.find(ctx, slot.LocationID)
+		if err != nil {
+			return nil, err
+		}
+		v.check(loc != nil, field+"location_id", RuleNotFound, nil)
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	before, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if slots == nil {
+		slots = []models.FeaturedSlot{}
+	}
+	if err := s.settings.Put(ctx, featuredSettingsKey, models.FeaturedSettings{Slots: slots}, updatedBy); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.expires = time.Time{}
+	s.mu.Unlock()
+	s.audit.Record(ctx, AuditEvent{Action: "settings.update", ResourceType: AuditSettings, ResourceID: featuredSettingsKey,
+		Before: models.FeaturedSettings{Slots: before}, After: models.FeaturedSettings{Slots: slots}})
+	return s.Admin(ctx)
+}
+
+// Public mengembalikan slot yang sedang tayang sesuai urutan dari cache.
+// Cache dibangun ulang setelah FeaturedTTL, saat jendela salah satu slot
+// mulai atau berakhir, atau saat lokasinya berubah.
+func (s *FeaturedService) Public(ctx context.Context) ([]models.FeaturedItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if s.cached != nil && now.Before(s.expires) {
+		return s.cached, nil
+	}
+	slots, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	expires := now.Add(FeaturedTTL)
+	items := []models.FeaturedItem{}
+	ids := map[primitive.ObjectID]bool{}
+	for _, slot := range slots {
+		ids[slot.LocationID] = true
+		for _, edge := range []*time.Time{slot.StartsAt, slot.EndsAt} {
+			if edge != nil && edge.After(now) && edge.Before(expires) {
+				expires = *edge
+			}
+		}
+		if !slot.ActiveAt(now) {
+			continue
+		}
+		loc, err := s.find(ctx, slot.LocationID)
+		if err != nil {
+			return nil, err
+		}
+		if loc != nil {
+			items = append(items, models.FeaturedItem{Caption: slot.Caption, Location: mapViewItem(*loc)})
+		}
+	}
+	if err := concealFeatured(ctx, s.categories, items); err != nil {
+		return nil, err
+	}
+	s.cached, s.ids, s.expires = items, ids, expires
+	return items, nil
+}
+
+// concealFeatured menyamarkan koordinat seperti daftar publik lainnya;
+// carousel di-cache untuk semua pengunjung.
+func concealFeatured(ctx context.Context, categories *CategoryService, items []models.FeaturedItem) error {
+	locations := make([]models.MapViewItem, len(items))
+	for i := range items {
+		locations[i] = items[i].Location
+	}
+	if err := concealItems(ctx, categories, locations); err != nil {
+		return err
+	}
+	for i := range items {
+		items[i].Location = locations[i]
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"InfoCuy-Backend/internal/geocode"
+	"InfoCuy-Backend/internal/models"
+)
+
+const (
+	defaultGeocodeLimit = 5
+	maxGeocodeLimit     = 10
+	// Query lebih pendek dari ini terlalu umum untuk autocomplete
+	minGeocodeQuery = 3
+	maxGeocodeQuery = 200
+)
+
+// GeocodeService meneruskan reverse geocoding dan pencarian alamat ke
+// provider yang dikonfigurasi (lihat package geocode).
+type GeocodeService struct {
+	provider geocode.Provider
+}
+
+// NewGeocodeService menerima provider nil jika geocoding tidak dikonfigurasi.
+func NewGeocodeService(provider geocode.Provider) *GeocodeService {
+	return &GeocodeService{provider: provider}
+}
+
+// Reverse mengembalikan alamat terdekat dari koordinat.
+func (s *GeocodeService) Reverse(ctx context.Context, c models.Coordinates) (*geocode.Place, error) {
+	if s.provider == nil {
+		return nil, invalid("Provider geocoding belum dikonfigurasi (GEOCODE_PROVIDER)")
+	}
+	if c.Lat < -90 || c.Lat > 90 || c.Lng < -180 || c.Lng > 180 {
+		return nil, invalid("lat dan lng wajib diisi dengan koordinat yang valid")
+	}
+	place, err := s.provider.Reverse(ctx, c)
+	if errors.Is(err, geocode.ErrNoResult) {
+		return nil, ErrAddressNotFound
+	}
+	return place, err
+}
+
+// Search mencari tempat yang cocok dengan query untuk autocomplete.
+// limit 0 berarti default.
+func (s *GeocodeService) Search(ctx context.Context, query string, limit int) ([]geocode.Place, error) {
+	if s.provider == nil {
+		return nil, invalid("Provider geocoding belum dikonfigurasi (GEOCODE_PROVIDER)")
+	}
+	query = strings.TrimSpace(query)
+	if n := utf8.RuneCountInString(query); n < minGeocodeQuery || n > maxGeocodeQuery {
+		return nil, invalid("q wajib diisi, %d-%d karakter", minGeocodeQuery, maxGeocodeQuery)
+	}
+	if limit == 0 {
+		limit = defaultGeocodeLimit
+	}
+	if limit < 1 || limit > maxGeocodeLimit {
+		return nil, invalid("limit harus di antara 1 dan %d", maxGeocodeLimit)
+	}
+	return s.provider.Search(ctx, query, limit)
+}
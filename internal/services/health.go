@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// Batas waktu ping dependency pada /readyz
+const readinessTimeout = 2 * time.Second
+
+// Pinger mengecek satu dependency, mis. MongoDB.
+type Pinger func(ctx context.Context) error
+
+// DependencyCheck adalah hasil probe satu dependency.
+type DependencyCheck struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"` // ok | degraded | error | not_configured
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type HealthService struct {
+	startedAt time.Time
+	mongo     Pinger
+	database  *DatabaseMode
+}
+
+// NewHealthService menerima pinger MongoDB; nil berarti MONGO_URI tidak
+// diset sehingga service tidak pernah ready.
+func NewHealthService(mongo Pinger, database *DatabaseMode) *HealthService {
+	return &HealthService{startedAt: time.Now(), mongo: mongo, database: database}
+}
+
+func (s *HealthService) StartedAt() time.Time {
+	return s.startedAt.UTC()
+}
+
+func (s *HealthService) Uptime() time.Duration {
+	return time.Since(s.startedAt)
+}
+
+// Ready mem-probe semua dependency. ready false jika ada yang gagal.
+// Database tanpa primary tetap ready (mongodb_primary degraded) supaya
+// instance terus melayani read selama failover.
+func (s *HealthService) Ready(ctx context.Context) (bool, []DependencyCheck) {
+	check := DependencyCheck{Name: "mongodb", Status: "ok"}
+	if s.mongo == nil {
+		check.Status = "not_configured"
+		return false, []DependencyCheck{check}
+	}
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+	start := time.Now()
+	err := s.mongo(ctx)
+	check.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		check.Status, check.Error = "error", err.Error()
+		return false, []DependencyCheck{check}
+	}
+	primary := DependencyCheck{Name: "mongodb_primary", Status: "ok"}
+	if st := s.database.Status(); st.ReadOnly {
+		primary.Status, primary.Error = "degraded", st.Reason
+	}
+	return true, []DependencyCheck{check, primary}
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"InfoCuy-Backend/internal/geocode"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/rbac"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Setiap baris memakai satu panggilan provider; batas provider
+	// (GEOCODE_RATE_PER_MIN) tetap berlaku di atas ukuran batch
+	defaultGeocodeBatch = 20
+	maxGeocodeBatch     = 200
+)
+
+const unresolvedMessage = "Alamat tidak ditemukan, isi koordinat secara manual"
+
+// geocodesImports bernilai true jika baris import tanpa koordinat bisa
+// diantrekan untuk geocoding.
+func (s *LocationService) geocodesImports() bool {
+	return s.opts.Geocoder != nil && s.opts.ImportJobs != nil
+}
+
+func (s *LocationService) queueGeocoding(ctx context.Context, u models.User, rows []models.ImportJobRow) (*models.ImportJob, error) {
+	job := models.ImportJob{
+		Status:    models.ImportJobQueued,
+		CreatedBy: u.Email,
+		Role:      u.Role,
+		CreatedAt: time.Now().UTC(),
+		Total:     len(rows),
+		Rows:      rows,
+	}
+	if err := s.opts.ImportJobs.Create(ctx, &job); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "import_job.create", ResourceType: AuditImportJob, ResourceID: job.ID.Hex(),
+		After: map[string]interface{}{"total": job.Total}})
+	return &job, nil
+}
+
+// ImportJob mengembalikan progres job geocoding. Hanya pengimport dan user
+// yang boleh mengubah lokasi orang lain yang bisa melihatnya.
+func (s *LocationService) ImportJob(ctx context.Context, viewer models.User, id primitive.ObjectID) (*models.ImportJob, error) {
+	if s.opts.ImportJobs == nil {
+		return nil, ErrImportJobNotFound
+	}
+	job, err := s.opts.ImportJobs.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrImportJobNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if job.CreatedBy != viewer.Email && !s.roles.Can(ctx, viewer.Role, rbac.LocationsUpdateAny) {
+		return nil, ErrImportJobNotFound
+	}
+	job.Pending = job.Total - job.Cursor
+	return job, nil
+}
+
+// GeocodeImports mencari koordinat satu batch baris dari job queued
+// tertua lalu membuat lokasinya. Alamat yang tidak ditemukan ditandai
+// unresolved untuk ditempatkan manual. Sama seperti job reassign, tidak
+// ada worker terpisah: POST /admin/jobs/import-geocode dipanggil berkala
+// oleh cron sampai queued = 0. Batch berhenti lebih awal jika provider
+// menolak karena batas panggilan; sisanya diproses di panggilan berikutnya.
+func (s *LocationService) GeocodeImports(ctx context.Context, batch int) (models.GeocodeBatchResult, error) {
+	var result models.GeocodeBatchResult
+	if s.opts.ImportJobs == nil {
+		return result, nil
+	}
+	if batch <= 0 {
+		batch = defaultGeocodeBatch
+	}
+	if batch > maxGeocodeBatch {
+		batch = maxGeocodeBatch
+	}
+	job, err := s.opts.ImportJobs.NextQueued(ctx)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return result, nil
+	} else if err != nil {
+		return result, err
+	}
+	result.JobID = &job.ID
+	from := job.Cursor
+	end := min(from+batch, len(job.Rows))
+	processed := make([]models.ImportJobRow, 0, end-from)
+	var stop error
+	for _, row := range job.Rows[from:end] {
+		var places []geocode.Place
+		if s.opts.Geocoder != nil {
+			places, err = s.opts.Geocoder.Search(ctx, row.Address, 1)
+		}
+		if errors.Is(err, geocode.ErrRateLimited) {
+			result.RateLimited = true
+			break
+		} else if err != nil {
+			stop = err
+			break
+		}
+		if len(places) == 0 {
+			row.Status, row.Error = models.ImportRowUnresolved, unresolvedMessage
+			result.Unresolved++
+		} else {
+			var validationErr *ValidationError
+			created, err := s.createImportRow(ctx, job, row, places[0].Coordinates, models.ImportRowGeocoded)
+			switch {
+			case errors.As(err, &validationErr):
+				row.Status, row.Error, row.Coordinates = models.ImportRowFailed, err.Error(), &places[0].Coordinates
+			case err != nil:
+				stop = err
+			default:
+				row = created
+				result.Geocoded++
+			}
+			if stop != nil {
+				break
+			}
+		}
+		processed = append(processed, row)
+	}
+	result.Processed = len(processed)
+	if len(processed) > 0 {
+		// Lokasi memakai ID yang ditentukan saat import, jadi batch yang
+		// diproses dua kali tidak membuat lokasi ganda
+		err := s.opts.ImportJobs.Advance(ctx, job.ID, from, processed)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return result, err
+		}
+	}
+	if stop != nil {
+		return result, stop
+	}
+	if from+len(processed) == len(job.Rows) {
+		if err := s.opts.ImportJobs.Complete(ctx, job.ID, time.Now().UTC()); err == nil {
+			ev := AuditEvent{Action: "import_job.done", ResourceType: AuditImportJob, ResourceID: job.ID.Hex()}
+			if after, err := s.opts.ImportJobs.FindByID(ctx, job.ID); err == nil {
+				ev.After = map[string]interface{}{"geocoded": after.Geocoded, "unresolved": after.Unresolved, "failed": after.Failed}
+			}
+			s.audit.Record(ctx, ev)
+		} else if !errors.Is(err, repositories.ErrNotFound) {
+			return result, err
+		}
+	}
+	result.Queued, err = s.opts.ImportJobs.CountQueued(ctx)
+	return result, err
+}
+
+// createImportRow membuat lokasi baris di koordinat c dan mengembalikan
+// baris dengan status baru. ValidationError jika lokasi tidak lolos
+// validasi di titik itu (mis. kode pos tidak cocok dengan wilayahnya).
+func (s *LocationService) createImportRow(ctx context.Context, job *models.ImportJob, row models.ImportJobRow, c models.Coordinates, status string) (models.ImportJobRow, error) {
+	loc := row.Location
+	loc.ID, loc.Coordinates = row.LocationID, c
+	loc.AdminArea = s.regions.AdminArea(ctx, c)
+	loc.ModerationStatus = s.initialModeration(ctx, models.User{Email: job.CreatedBy, Role: job.Role}, &loc)
+	if err := s.postcodes.Apply(ctx, &loc); err != nil {
+		return row, err
+	}
+	if err := s.locations.Create(ctx, &loc); err != nil && !errors.Is(err, repositories.ErrDuplicate) {
+		return row, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "location.import", ResourceType: AuditLocation, ResourceID: loc.ID.Hex(), After: loc})
+	s.opts.Events.Publish(ctx, "location.import", loc.ID)
+	row.Status, row.Error, row.Coordinates = status, "", &c
+	return row, nil
+}
+
+// PlaceImportRow membuat lokasi untuk baris unresolved di koordinat yang
+// dipilih user, mis. dengan menggeser pin di peta.
+func (s *LocationService) PlaceImportRow(ctx context.Context, viewer models.User, id primitive.ObjectID, rowNumber int, c models.Coordinates) (*models.ImportJob, error) {
+	job, err := s.ImportJob(ctx, viewer, id)
+	if err != nil {
+		return nil, err
+	}
+	index := -1
+	for i, row := range job.Rows {
+		if row.Row == rowNumber {
+			index = i
+		}
+	}
+	if index < 0 {
+		return nil, invalid("Baris %d tidak ada di job ini", rowNumber)
+	}
+	if job.Rows[index].Status != models.ImportRowUnresolved {
+		return nil, invalid("Baris %d tidak menunggu penempatan manual", rowNumber)
+	}
+	var v validator
+	v.check(c.Lat >= -90 && c.Lat <= 90, "coordinates.lat", RuleRange, Params{"min": -90, "max": 90})
+	v.check(c.Lng >= -180 && c.Lng <= 180, "coordinates.lng", RuleRange, Params{"min": -180, "max": 180})
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	// Baris yang gagal validasi tetap unresolved supaya bisa dicoba dengan
+	// koordinat lain
+	row, err := s.createImportRow(ctx, job, job.Rows[index], c, models.ImportRowPlaced)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.opts.ImportJobs.Place(ctx, id, index, row); err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+	return s.ImportJob(ctx, viewer, id)
+}
@@ -0,0 +1,389 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/objectstore"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	integrityReportKey = "integrity_report"
+	// Batas temuan per jenis masalah dalam satu laporan
+	maxIntegrityIssues = 100
+	// Lokasi yang fotonya diperiksa ke storage per pemeriksaan (maks 10
+	// foto per lokasi, satu HEAD per foto)
+	integrityPhotoBatch = 50
+	// Email yang dicek ke collection user per query
+	integrityEmailChunk = 500
+)
+
+// IntegrityService mencari referensi yatim yang tidak dijaga database:
+// lokasi milik user yang sudah dihapus, ulasan/favorit/check-in milik
+// lokasi yang sudah di-purge (purge hanya mencatat kegagalan data turunan)
+// dan foto yang object-nya hilang dari storage. Konfirmasi tidak diperiksa
+// karena kedaluwarsa sendiri lewat TTL.
+//
+// Tidak ada scheduler di dalam proses: POST /admin/integrity-check
+// dipanggil berkala oleh cron, dan laporan terakhir disimpan di settings
+// supaya admin bisa memperbaiki temuannya satu per satu.
+type IntegrityService struct {
+	locations repositories.LocationRepository
+	users     repositories.UserRepository
+	reviews   repositories.ReviewRepository
+	favorites repositories.FavoriteRepository
+	checkins  repositories.CheckInRepository
+	settings  repositories.SettingsRepository
+	reassign  *ReassignService
+	audit     *AuditService
+	// Opsional; nil berarti foto tidak diperiksa
+	photos objectstore.Store
+
+	// Menjaga laporan dari pemeriksaan dan perbaikan yang bersamaan
+	mu sync.Mutex
+}
+
+func NewIntegrityService(locations repositories.LocationRepository, users repositories.UserRepository,
+	reviews repositories.ReviewRepository, favorites repositories.FavoriteRepository, checkins repositories.CheckInRepository,
+	settings repositories.SettingsRepository, reassign *ReassignService, audit *AuditService, photos objectstore.Store) *IntegrityService {
+	return &IntegrityService{locations: locations, users: users, reviews: reviews, favorites: favorites, checkins: checkins,
+		settings: settings, reassign: reassign, audit: audit, photos: photos}
+}
+
+// Report mengembalikan laporan pemeriksaan terakhir.
+func (s *IntegrityService) Report(ctx context.Context) (*models.IntegrityReport, error) {
+	var report models.IntegrityReport
+	if err := s.settings.Get(ctx, integrityReportKey, &report); errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrIntegrityReportNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if report.Issues == nil {
+		report.Issues = []models.IntegrityIssue{}
+	}
+	return &report, nil
+}
+
+// Check menjalankan pemeriksaan dan menyimpan hasilnya sebagai laporan
+// terbaru. Temuan yang sudah diperbaiki tidak muncul lagi, kecuali
+// perbaikannya belum selesai (mis. job reassign masih antre).
+func (s *IntegrityService) Check(ctx context.Context, u models.User) (*models.IntegrityReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev, err := s.Report(ctx)
+	if err != nil && !errors.Is(err, ErrIntegrityReportNotFound) {
+		return nil, err
+	}
+	report := &models.IntegrityReport{StartedAt: time.Now().UTC(), RunBy: u.Email, Issues: []models.IntegrityIssue{}}
+	creators, err := s.missingCreators(ctx)
+	if err != nil {
+		return nil, err
+	}
+	addIssues(report, models.IntegrityCreatorMissing, creators)
+	refs, err := s.locations.DanglingReferences(ctx, maxIntegrityIssues+1)
+	if err != nil {
+		return nil, err
+	}
+	orphans := map[string][]models.IntegrityIssue{}
+	for _, ref := range refs {
+		kind := orphanKinds[ref.Collection]
+		id := ref.LocationID
+		orphans[kind] = append(orphans[kind], models.IntegrityIssue{
+			ID:         kind + ":" + id.Hex(),
+			Kind:       kind,
+			Repair:     models.RepairDeleteRefs,
+			LocationID: &id,
+			Count:      ref.Count,
+			Detail:     fmt.Sprintf("%d %s menunjuk lokasi yang sudah dihapus permanen", ref.Count, ref.Collection),
+		})
+	}
+	for _, kind := range []string{models.IntegrityReviewOrphan, models.IntegrityFavoriteOrphan, models.IntegrityCheckInOrphan} {
+		addIssues(report, kind, orphans[kind])
+	}
+	addIssues(report, models.IntegrityPhotoMissing, s.checkPhotos(ctx, report, prev))
+	report.CompletedAt = time.Now().UTC()
+	if err := s.settings.Put(ctx, integrityReportKey, report, u.Email); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// orphanKinds memetakan collection DanglingReferences ke jenis temuan.
+var orphanKinds = map[string]string{
+	repositories.RefReviews:   models.IntegrityReviewOrphan,
+	repositories.RefFavorites: models.IntegrityFavoriteOrphan,
+	repositories.RefCheckIns:  models.IntegrityCheckInOrphan,
+}
+
+// addIssues menambahkan temuan satu jenis, dipotong di maxIntegrityIssues.
+func addIssues(report *models.IntegrityReport, kind string, issues []models.IntegrityIssue) {
+	if len(issues) > maxIntegrityIssues {
+		issues = issues[:maxIntegrityIssues]
+		report.Truncated = append(report.Truncated, kind)
+	}
+	report.Issues = append(report.Issues, issues...)
+}
+
+// missingCreators mencari pembuat lokasi yang tidak terdaftar lagi.
+// Lokasi tanpa created_by tidak dilaporkan karena tidak bisa di-reassign.
+func (s *IntegrityService) missingCreators(ctx context.Context) ([]models.IntegrityIssue, error) {
+	creators, err := s.locations.Creators(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(creators))
+	emails := make([]string, 0, len(creators))
+	for _, c := range creators {
+		if c.Email != "" {
+			counts[c.Email] = c.Count
+			emails = append(emails, c.Email)
+		}
+	}
+	issues := []models.IntegrityIssue{}
+	for chunk := range slices.Chunk(emails, integrityEmailChunk) {
+		missing, err := s.users.MissingEmails(ctx, chunk)
+		if err != nil {
+			return nil, err
+		}
+		for _, email := range missing {
+			issues = append(issues, models.IntegrityIssue{
+				ID:     models.IntegrityCreatorMissing + ":" + email,
+				Kind:   models.IntegrityCreatorMissing,
+				Repair: models.RepairReassign,
+				Email:  email,
+				Count:  counts[email],
+				Detail: fmt.Sprintf("%d lokasi dibuat oleh %s yang tidak terdaftar lagi", counts[email], email),
+			})
+		}
+	}
+	return issues, nil
+}
+
+// checkPhotos memeriksa satu batch lokasi setelah cursor laporan
+// sebelumnya. Temuan foto sebelumnya untuk lokasi yang tidak diperiksa
+// ulang tetap dibawa supaya laporan mencakup seluruh putaran.
+func (s *IntegrityService) checkPhotos(ctx context.Context, report, prev *models.IntegrityReport) []models.IntegrityIssue {
+	if s.photos == nil {
+		report.PhotosSkipped = "Object store tidak dikonfigurasi (OBJECT_STORE)"
+		return nil
+	}
+	heads, ok := s.photos.(objectstore.Presigner)
+	if !ok {
+		report.PhotosSkipped = fmt.Sprintf("Object store %s tidak mendukung pemeriksaan object", s.photos.Name())
+		return nil
+	}
+	var carried []models.IntegrityIssue
+	if prev != nil {
+		report.PhotoCursor = prev.PhotoCursor
+		for _, issue := range prev.Issues {
+			if issue.Kind == models.IntegrityPhotoMissing && issue.RepairedAt == nil {
+				carried = append(carried, issue)
+			}
+		}
+	}
+	locations, err := s.locations.WithPhotos(ctx, report.PhotoCursor, integrityPhotoBatch)
+	if err != nil {
+		report.PhotosSkipped = "Daftar foto gagal dibaca, dilanjutkan pada pemeriksaan berikutnya"
+		return carried
+	}
+	found := []models.IntegrityIssue{}
+	scanned := map[primitive.ObjectID]bool{}
+	for _, loc := range locations {
+		var missing []models.IntegrityIssue
+		for _, photo := range loc.Photos {
+			// Foto dari store yang sudah tidak aktif tidak bisa diperiksa
+			if photo.Store != s.photos.Name() {
+				continue
+			}
+			_, err := heads.Head(ctx, photo.Key)
+			if errors.Is(err, objectstore.ErrNotFound) {
+				missing = append(missing, photoIssue(loc.ID, photo))
+			} else if err != nil {
+				report.PhotosSkipped = "Storage gagal diperiksa, dilanjutkan pada pemeriksaan berikutnya"
+				return mergePhotoIssues(found, carried, scanned)
+			}
+			report.PhotosChecked++
+		}
+		found = append(found, missing...)
+		scanned[loc.ID] = true
+		report.PhotoCursor = loc.ID
+	}
+	if len(locations) < integrityPhotoBatch {
+		// Putaran selesai, pemeriksaan berikutnya mulai dari awal
+		report.PhotoCursor = primitive.NilObjectID
+	}
+	return mergePhotoIssues(found, carried, scanned)
+}
+
+func photoIssue(locationID primitive.ObjectID, photo models.Photo) models.IntegrityIssue {
+	photoID := photo.ID
+	return models.IntegrityIssue{
+		ID:         models.IntegrityPhotoMissing + ":" + locationID.Hex() + "/" + photo.ID.Hex(),
+		Kind:       models.IntegrityPhotoMissing,
+		Repair:     models.RepairRemovePhoto,
+		LocationID: &locationID,
+		PhotoID:    &photoID,
+		Count:      1,
+		Detail:     fmt.Sprintf("Object %s tidak ada di storage", photo.Key),
+	}
+}
+
+// mergePhotoIssues menggabungkan temuan baru dengan temuan lama untuk
+// lokasi yang belum diperiksa ulang, urut ID.
+func mergePhotoIssues(found, carried []models.IntegrityIssue, scanned map[primitive.ObjectID]bool) []models.IntegrityIssue {
+	for _, issue := range carried {
+		if !scanned[*issue.LocationID] {
+			found = append(found, issue)
+		}
+	}
+	slices.SortFunc(found, func(a, b models.IntegrityIssue) int { return strings.Compare(a.ID, b.ID) })
+	return found
+}
+
+// Repair menjalankan aksi perbaikan satu temuan di laporan terakhir.
+// Temuan diperiksa ulang lebih dulu; jika ternyata sudah tidak bermasalah,
+// tidak ada yang diubah dan temuan tetap ditandai selesai.
+func (s *IntegrityService) Repair(ctx context.Context, u models.User, in models.IntegrityRepairInput) (*models.IntegrityIssue, error) {
+	var v validator
+	v.required(in.Issue != "", "issue")
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	report, err := s.Report(ctx)
+	if errors.Is(err, ErrIntegrityReportNotFound) {
+		return nil, ErrIntegrityIssueNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(report.Issues, func(issue models.IntegrityIssue) bool { return issue.ID == in.Issue })
+	if i < 0 {
+		return nil, ErrIntegrityIssueNotFound
+	}
+	issue := &report.Issues[i]
+	if issue.RepairedAt != nil {
+		return nil, ErrIssueRepaired
+	}
+	before := *issue
+	var result string
+	switch issue.Kind {
+	case models.IntegrityCreatorMissing:
+		result, err = s.reassignCreator(ctx, u, issue.Email, in.Target)
+	case models.IntegrityReviewOrphan, models.IntegrityFavoriteOrphan, models.IntegrityCheckInOrphan:
+		result, err = s.deleteReferences(ctx, issue.Kind, *issue.LocationID)
+	case models.IntegrityPhotoMissing:
+		result, err = s.removePhoto(ctx, *issue.LocationID, *issue.PhotoID)
+	default:
+		return nil, invalid("Temuan %s tidak punya aksi perbaikan", issue.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	issue.RepairedAt, issue.RepairedBy, issue.Result = &now, u.Email, result
+	if err := s.settings.Put(ctx, integrityReportKey, report, u.Email); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "integrity.repair", ResourceType: AuditIntegrity, ResourceID: issue.ID, Before: before, After: *issue})
+	return issue, nil
+}
+
+// reassignCreator membuat job reassign untuk lokasi milik email yang tidak
+// terdaftar. Target kosong = admin yang memperbaiki.
+func (s *IntegrityService) reassignCreator(ctx context.Context, u models.User, email, target string) (string, error) {
+	missing, err := s.users.MissingEmails(ctx, []string{email})
+	if err != nil {
+		return "", err
+	}
+	if len(missing) == 0 {
+		return "User sudah terdaftar lagi, tidak ada yang diubah", nil
+	}
+	if target == "" {
+		target = u.Email
+	}
+	job, err := s.reassign.Start(ctx, u, models.ReassignInput{Filter: models.ReassignFilter{CreatedBy: email}, Target: target})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Job reassign %s: %d lokasi ke %s", job.ID.Hex(), job.Matched, job.Target), nil
+}
+
+// deleteReferences menghapus dokumen turunan lokasi yang memang sudah
+// tidak ada, termasuk di trash dan arsip.
+func (s *IntegrityService) deleteReferences(ctx context.Context, kind string, locationID primitive.ObjectID) (string, error) {
+	exists, err := s.locationExists(ctx, locationID)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return "Lokasi masih ada, tidak ada yang dihapus", nil
+	}
+	var n int64
+	switch kind {
+	case models.IntegrityReviewOrphan:
+		n, err = s.reviews.DeleteByLocation(ctx, locationID)
+	case models.IntegrityFavoriteOrphan:
+		n, err = s.favorites.DeleteByLocation(ctx, locationID)
+	case models.IntegrityCheckInOrphan:
+		n, err = s.checkins.DeleteByLocation(ctx, locationID)
+	}
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d dokumen dihapus", n), nil
+}
+
+func (s *IntegrityService) locationExists(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	lookups := []func(context.Context, primitive.ObjectID) (*models.Location, error){
+		s.locations.FindByID, s.locations.FindDeleted, s.locations.FindArchivedWithRating,
+	}
+	for _, find := range lookups {
+		_, err := find(ctx, id)
+		if err == nil {
+			return true, nil
+		}
+		if !errors.Is(err, repositories.ErrNotFound) {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// removePhoto menghapus foto dari lokasi jika object-nya masih tidak ada.
+func (s *IntegrityService) removePhoto(ctx context.Context, locationID, photoID primitive.ObjectID) (string, error) {
+	loc, err := s.locations.FindByID(ctx, locationID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return "Lokasi sudah tidak ada, tidak ada yang diubah", nil
+	} else if err != nil {
+		return "", err
+	}
+	i := slices.IndexFunc(loc.Photos, func(p models.Photo) bool { return p.ID == photoID })
+	if i < 0 {
+		return "Foto sudah dihapus dari lokasi", nil
+	}
+	photo := loc.Photos[i]
+	if heads, ok := s.photos.(objectstore.Presigner); ok && photo.Store == s.photos.Name() {
+		if _, err := heads.Head(ctx, photo.Key); err == nil {
+			return "Object ada di storage, foto tidak diubah", nil
+		} else if !errors.Is(err, objectstore.ErrNotFound) {
+			return "", err
+		}
+	}
+	if err := s.locations.RemovePhoto(ctx, locationID, photoID); errors.Is(err, repositories.ErrNotFound) {
+		return "Foto sudah dihapus dari lokasi", nil
+	} else if err != nil {
+		return "", err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "location.photo_delete", ResourceType: AuditLocation, ResourceID: locationID.Hex(), Before: photo})
+	return "Foto dihapus dari lokasi", nil
+}
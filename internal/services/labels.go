@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+const attributeLabelsKey = "attribute_labels"
+
+// Atribut yang labelnya bisa diatur beserta nilai yang dikenal. accessibility
+// berisi nama fitur di ?accessible=, access_level nilai field-nya.
+var labelledAttributes = map[string][]string{
+	"price_range":     models.PriceRanges,
+	"payment_methods": models.PaymentMethods,
+	"accessibility":   {"wheelchair", "toilet", "parking"},
+	"access_level":    {models.AccessYes, models.AccessLimited, models.AccessNo},
+}
+
+// Label bawaan; admin cukup menimpa yang perlu diubah
+var defaultAttributeLabels = map[string]map[string]models.Labels{
+	"price_range": {
+		"budget":   {"id": "Murah", "en": "Budget"},
+		"moderate": {"id": "Sedang", "en": "Moderate"},
+		"pricey":   {"id": "Mahal", "en": "Pricey"},
+		"luxury":   {"id": "Mewah", "en": "Luxury"},
+	},
+	"payment_methods": {
+		"cash":          {"id": "Tunai", "en": "Cash"},
+		"qris":          {"id": "QRIS", "en": "QRIS"},
+		"debit_card":    {"id": "Kartu debit", "en": "Debit card"},
+		"credit_card":   {"id": "Kartu kredit", "en": "Credit card"},
+		"ewallet":       {"id": "Dompet digital", "en": "E-wallet"},
+		"bank_transfer": {"id": "Transfer bank", "en": "Bank transfer"},
+	},
+	"accessibility": {
+		"wheelchair": {"id": "Akses kursi roda", "en": "Wheelchair access"},
+		"toilet":     {"id": "Toilet difabel", "en": "Accessible toilet"},
+		"parking":    {"id": "Parkir difabel", "en": "Accessible parking"},
+	},
+	"access_level": {
+		models.AccessYes:     {"id": "Ya", "en": "Yes"},
+		models.AccessLimited: {"id": "Terbatas", "en": "Limited"},
+		models.AccessNo:      {"id": "Tidak", "en": "No"},
+	},
+}
+
+// validateLabels memastikan hanya bahasa yang didukung dan teks tidak kosong.
+func validateLabels(labels models.Labels) error {
+	for lang, text := range labels {
+		if !slices.Contains(models.Languages, lang) {
+			return invalid("Bahasa label hanya boleh: %s", strings.Join(models.Languages, ", "))
+		}
+		if strings.TrimSpace(text) == "" {
+			return invalid("Label %s tidak boleh kosong", lang)
+		}
+	}
+	return nil
+}
+
+// AttributeLabels mengembalikan label bawaan yang sudah ditimpa pengaturan
+// admin, per bahasa.
+func (s *SettingsService) AttributeLabels(ctx context.Context) models.AttributeLabels {
+	labels := models.AttributeLabels{Attributes: map[string]map[string]models.Labels{}}
+	for attr, values := range defaultAttributeLabels {
+		labels.Attributes[attr] = map[string]models.Labels{}
+		for value, l := range values {
+			labels.Attributes[attr][value] = models.Labels{}
+			for lang, text := range l {
+				labels.Attributes[attr][value][lang] = text
+			}
+		}
+	}
+	var stored models.AttributeLabels
+	if err := s.repo.Get(ctx, attributeLabelsKey, &stored); err != nil {
+		return labels
+	}
+	for attr, values := range stored.Attributes {
+		for value, l := range values {
+			if _, ok := labels.Attributes[attr][value]; !ok {
+				continue
+			}
+			for lang, text := range l {
+				labels.Attributes[attr][value][lang] = text
+			}
+		}
+	}
+	return labels
+}
+
+// UpdateAttributeLabels menyimpan label yang ditimpa admin. Label yang tidak
+// dikirim kembali ke bawaan.
+func (s *SettingsService) UpdateAttributeLabels(ctx context.Context, in models.AttributeLabels, updatedBy string) (models.AttributeLabels, error) {
+	for attr, values := range in.Attributes {
+		known, ok := labelledAttributes[attr]
+		if !ok {
+			return models.AttributeLabels{}, invalid("Atribut %s tidak dikenal", attr)
+		}
+		for value, l := range values {
+			if !slices.Contains(known, value) {
+				return models.AttributeLabels{}, invalid("Nilai %s.%s tidak dikenal", attr, value)
+			}
+			if err := validateLabels(l); err != nil {
+				return models.AttributeLabels{}, err
+			}
+		}
+	}
+	before := s.AttributeLabels(ctx)
+	if err := s.repo.Put(ctx, attributeLabelsKey, in, updatedBy); err != nil {
+		return models.AttributeLabels{}, err
+	}
+	after := s.AttributeLabels(ctx)
+	s.audit.Record(ctx, AuditEvent{Action: "settings.update", ResourceType: AuditSettings, ResourceID: attributeLabelsKey, Before: before, After: after})
+	return after, nil
+}
+
+// LocalizeCategories mengisi Label tiap kategori untuk lang.
+func LocalizeCategories(categories []models.Category, lang string) {
+	for i := range categories {
+		categories[i].Label = categories[i].Labels.Resolve(lang)
+		if categories[i].Label == "" {
+			categories[i].Label = categories[i].Name
+		}
+	}
+}
+
+// LocalizeFacets mengisi Label tiap facet dari label kategori dan atribut.
+// Nilai tanpa label (mis. kategori yang sudah dihapus) dibiarkan kosong.
+func LocalizeFacets(facets *models.LocationFacets, categories []models.Category, attrs models.AttributeLabels, lang string) {
+	LocalizeCategories(categories, lang)
+	bySlug := map[string]string{}
+	for _, c := range categories {
+		bySlug[c.Slug] = c.Label
+	}
+	for i := range facets.Category {
+		facets.Category[i].Label = bySlug[facets.Category[i].Value]
+	}
+	for i := range facets.PriceRange {
+		facets.PriceRange[i].Label = attrs.Attributes["price_range"][facets.PriceRange[i].Value].Resolve(lang)
+	}
+	for i := range facets.PaymentMethods {
+		facets.PaymentMethods[i].Label = attrs.Attributes["payment_methods"][facets.PaymentMethods[i].Value].Resolve(lang)
+	}
+}
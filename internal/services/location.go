@@ -0,0 +1,549 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/elevation"
+	"InfoCuy-Backend/internal/fieldpolicy"
+	"InfoCuy-Backend/internal/geocode"
+	"InfoCuy-Backend/internal/mailer"
+	"InfoCuy-Backend/internal/mapmatch"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/objectstore"
+	"InfoCuy-Backend/internal/rbac"
+	"InfoCuy-Backend/internal/repositories"
+	"InfoCuy-Backend/internal/weather"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Field lokasi yang hanya boleh ditulis moderator
+var LocationPolicy = fieldpolicy.Policy{
+	Resource: "location",
+	Rules: []fieldpolicy.Rule{
+		{Field: "verified", Permission: rbac.LocationsModerate},
+		{Field: "status", Permission: rbac.LocationsModerate},
+		{Field: "created_by", Permission: rbac.LocationsModerate},
+		{Field: "visibility", Value: "pinned", Permission: rbac.LocationsModerate},
+	},
+}
+
+type LocationOptions struct {
+	PolicyMode fieldpolicy.Mode
+	// Ditampilkan ke user saat kuota habis
+	QuotaUpgradeURL string
+	// Opsional; nil berarti koordinat tidak pernah di-snap
+	MapMatcher mapmatch.Provider
+	// Opsional; nil berarti elevation_m tidak diisi
+	Elevation elevation.Provider
+	// Opsional; nil berarti GET /locations/:id/weather tidak tersedia
+	Weather weather.Provider
+	// Opsional; nil berarti upload foto tidak tersedia
+	Photos objectstore.Store
+	// Skor freshness turun separuh setiap durasi ini (0 = DefaultFreshnessHalfLife)
+	FreshnessHalfLife time.Duration
+	// Lama lokasi disimpan di trash sebelum di-purge (0 = DefaultTrashRetention)
+	TrashRetention time.Duration
+	// Lokasi yang tidak diubah/dikonfirmasi selama ini diarsipkan (0 = DefaultArchiveAfter)
+	ArchiveAfter time.Duration
+	// Opsional; nil berarti perubahan tidak diteruskan ke read model (map_view)
+	Events *LocationEvents
+	// Opsional; nil berarti alasan penolakan hanya terlihat di detail lokasi
+	Mail mailer.Mailer
+	// Opsional; kehadiran acara ikut dihapus saat lokasi di-purge jika diisi
+	CheckIns repositories.CheckInRepository
+	// Opsional; keduanya wajib diisi supaya baris import tanpa koordinat
+	// di-geocode dari alamatnya, jika tidak baris itu ditolak
+	Geocoder   geocode.Provider
+	ImportJobs repositories.ImportJobRepository
+	// Opsional; nil berarti merge tidak tersedia dan ID lama tidak dialihkan
+	Redirects repositories.RedirectRepository
+}
+
+type LocationService struct {
+	locations repositories.LocationRepository
+	reviews   repositories.ReviewRepository
+	// Konfirmasi "data masih akurat" per user
+	confirmations repositories.ConfirmationRepository
+	settings      *SettingsService
+	roles         *RoleService
+	categories    *CategoryService
+	regions       *RegionService
+	postcodes     *PostcodeService
+	audit         *AuditService
+	opts          LocationOptions
+}
+
+func NewLocationService(locations repositories.LocationRepository, reviews repositories.ReviewRepository,
+	confirmations repositories.ConfirmationRepository, settings *SettingsService, roles *RoleService,
+	categories *CategoryService, regions *RegionService, postcodes *PostcodeService, audit *AuditService,
+	opts LocationOptions) *LocationService {
+	return &LocationService{locations: locations, reviews: reviews, confirmations: confirmations, settings: settings,
+		roles: roles, categories: categories, regions: regions, postcodes: postcodes, audit: audit, opts: opts}
+}
+
+// Get mengembalikan detail lokasi beserta ringkasan rating dan freshness.
+// Lokasi di arsip hanya dicari jika includeArchived. Lokasi pending atau
+// rejected hanya terlihat oleh pembuatnya dan moderator (viewer boleh
+// zero value untuk request tanpa login).
+func (s *LocationService) Get(ctx context.Context, viewer models.User, id primitive.ObjectID, includeArchived bool) (*models.Location, error) {
+	loc, err := s.locations.FindWithRating(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) && includeArchived {
+		loc, err = s.locations.FindArchivedWithRating(ctx, id)
+	}
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrLocationNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if !loc.Listed() && !canSeeUnlisted(ctx, s.roles, viewer, loc) {
+		return nil, ErrLocationNotFound
+	}
+	conceal, err := s.concealer(ctx, viewer)
+	if err != nil {
+		return nil, err
+	}
+	conceal(loc)
+	s.withFreshness(loc, time.Now())
+	return loc, nil
+}
+
+// ListParams adalah query GET /locations
+type ListParams struct {
+	Category   string
+	CreatedBy  string
+	Q          string
+	Attributes AttributeParams
+	// Sort mis. -created_at
+	List models.ListOptions
+	// Ikut menampilkan lokasi di arsip
+	IncludeArchived bool
+	// Penentu koordinat asli atau perkiraan; zero value untuk tanpa login.
+	// Tidak dipakai MapViewService.List (selalu perkiraan)
+	Viewer models.User
+	// Titik referensi faktor jarak untuk sort relevance (?lat=&lng=)
+	Near *models.Coordinates
+	// Sertakan rincian skor relevansi per lokasi (?explain=true)
+	Explain bool
+	// Jangan catat impression promosi, mis. untuk request shadow traffic
+	SkipImpressions bool
+}
+
+func (s *LocationService) List(ctx context.Context, p ListParams) ([]models.Location, models.PageMeta, error) {
+	page, err := locationPage(s.settings.NearLimits(ctx, p.Category), p.List)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	attrs, err := p.Attributes.filter()
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+
+	locations, total, err := s.locations.List(ctx, repositories.LocationQuery{
+		Category:        p.Category,
+		CreatedBy:       p.CreatedBy,
+		Text:            strings.TrimSpace(p.Q),
+		Attributes:      attrs,
+		IncludeArchived: p.IncludeArchived,
+		ListPage:        page,
+	})
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	meta := repositories.Meta(page, total, locations)
+	conceal, err := s.concealer(ctx, p.Viewer)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	now := time.Now()
+	for i := range locations {
+		conceal(&locations[i])
+		s.withFreshness(&locations[i], now)
+	}
+	return locations, meta, nil
+}
+
+// locationPage memeriksa opsi daftar lokasi dengan batas limit pengaturan
+// pencarian kategorinya.
+func locationPage(limits models.NearLimits, o models.ListOptions) (repositories.ListPage, error) {
+	spec := repositories.LocationList
+	spec.DefaultLimit, spec.MaxLimit = limits.DefaultLimit, limits.MaxLimit
+	return listPage(spec, o)
+}
+
+// NearbyParams adalah query GET /locations/nearby (radius dalam meter, 0 = maksimum)
+type NearbyParams struct {
+	Lat, Lng   float64
+	RadiusM    float64
+	Category   string
+	Attributes AttributeParams
+	Limit      int
+	Sort       string
+	Viewer     models.User
+}
+
+// Nearby mengembalikan lokasi terdekat beserta radius efektif yang dipakai.
+func (s *LocationService) Nearby(ctx context.Context, p NearbyParams) ([]models.NearbyLocation, float64, error) {
+	if p.Lat < -90 || p.Lat > 90 || p.Lng < -180 || p.Lng > 180 {
+		return nil, 0, invalid("lat dan lng wajib diisi dengan koordinat yang valid")
+	}
+	attrs, err := p.Attributes.filter()
+	if err != nil {
+		return nil, 0, err
+	}
+	limits := s.settings.NearLimits(ctx, p.Category)
+	if p.RadiusM <= 0 {
+		p.RadiusM = limits.MaxRadiusM
+	}
+	if p.Sort == "" {
+		p.Sort = "distance"
+	}
+	limit, err := EnforceNearLimits(limits, p.RadiusM, p.Limit, p.Sort)
+	if err != nil {
+		return nil, 0, err
+	}
+	results, err := s.locations.Nearby(ctx, repositories.NearbyQuery{
+		Lat:        p.Lat,
+		Lng:        p.Lng,
+		RadiusM:    p.RadiusM,
+		Category:   p.Category,
+		Attributes: attrs,
+		Limit:      limit,
+		SortField:  strings.TrimPrefix(p.Sort, "-"),
+		SortDesc:   strings.HasPrefix(p.Sort, "-"),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	conceal, err := s.concealer(ctx, p.Viewer)
+	if err != nil {
+		return nil, 0, err
+	}
+	origin := models.Coordinates{Lat: p.Lat, Lng: p.Lng}
+	now := time.Now()
+	for i := range results {
+		// Jarak asli dari beberapa titik cukup untuk triangulasi, jadi
+		// dihitung ulang dari koordinat perkiraan
+		if conceal(&results[i].Location) {
+			results[i].DistanceM = origin.DistanceM(results[i].Coordinates)
+		}
+		s.withFreshness(&results[i].Location, now)
+	}
+	return results, p.RadiusM, nil
+}
+
+// decode menerapkan field policy lalu mengubah payload menjadi Location
+// yang sudah divalidasi, dengan kategori yang dinormalisasi ke slug.
+// Field moderator hanya boleh ditulis jika target berada di dalam scope
+// role u; target nil berarti cukup permission-nya. Mengembalikan field yang
+// dibuang karena policy.
+func (s *LocationService) decode(ctx context.Context, u models.User, payload map[string]interface{}, category CategoryResolver, target *models.Location) (models.Location, []string, error) {
+	var loc models.Location
+	can := func(perm string) bool { return s.roles.Can(ctx, u.Role, perm) }
+	if target != nil {
+		can = func(perm string) bool { return s.roles.CanModerate(ctx, u.Role, perm, target) }
+	}
+	ignored, err := LocationPolicy.Apply(can, payload, s.opts.PolicyMode)
+	if err != nil {
+		return loc, nil, err
+	}
+	raw, _ := json.Marshal(payload)
+	if err := json.Unmarshal(raw, &loc); err != nil {
+		return loc, nil, DecodeError(err)
+	}
+	// Atribut enum dinormalisasi dulu supaya "QRIS" tetap diterima
+	loc.PriceRange = strings.ToLower(strings.TrimSpace(loc.PriceRange))
+	loc.PaymentMethods = normalizeList(loc.PaymentMethods)
+	_, hasCoordinates := payload["coordinates"]
+	if err := validateLocation(loc, hasCoordinates); err != nil {
+		return loc, nil, err
+	}
+	loc.Name = strings.TrimSpace(loc.Name)
+	loc.Address = strings.TrimSpace(loc.Address)
+	// Info snap, ketinggian, wilayah, foto, rating, favorit, check-in, konfirmasi, moderasi & promosi hanya diisi server
+	loc.Snap = nil
+	loc.ElevationM = nil
+	loc.AdminArea = nil
+	loc.Photos = nil
+	loc.Rating = nil
+	loc.FavoritesCount = 0
+	loc.CheckInsCount = 0
+	loc.LastConfirmedAt = nil
+	loc.ConfirmationCount = 0
+	loc.Freshness = nil
+	loc.DeletedAt = nil
+	loc.DeletedBy = ""
+	loc.UpdatedAt = nil
+	loc.ArchivedAt = nil
+	loc.ModerationStatus = ""
+	loc.RejectionReason = ""
+	loc.ModeratedBy = ""
+	loc.ModeratedAt = nil
+	loc.Promotion = nil
+	if loc.Category, err = category(loc.Category); err != nil {
+		return loc, nil, err
+	}
+	return loc, ignored, nil
+}
+
+// newTarget membaca kategori dan wilayah lokasi baru dari payload supaya
+// field moderator bisa dicek terhadap scope role sebelum payload di-decode.
+// nil jika role u tidak dibatasi scope.
+func (s *LocationService) newTarget(ctx context.Context, u models.User, payload map[string]interface{}, category CategoryResolver) *models.Location {
+	if s.roles.Scope(ctx, u.Role) == nil {
+		return nil
+	}
+	var probe struct {
+		Category    string             `json:"category"`
+		Coordinates models.Coordinates `json:"coordinates"`
+	}
+	// Payload yang tidak valid ditolak oleh decode; di sini cukup nilai nol
+	raw, _ := json.Marshal(payload)
+	json.Unmarshal(raw, &probe)
+	target := &models.Location{Coordinates: probe.Coordinates}
+	target.Category, _ = category(probe.Category)
+	target.AdminArea = s.regions.AdminArea(ctx, target.Coordinates)
+	return target
+}
+
+// QuotaUsage menghitung pemakaian & batas kuota (override user > kuota role).
+func (s *LocationService) QuotaUsage(ctx context.Context, u models.User) (models.QuotaUsage, error) {
+	usage := models.QuotaUsage{Source: "role", UpgradeURL: s.opts.QuotaUpgradeURL}
+	if u.LocationQuota != nil {
+		usage.Limit, usage.Source = *u.LocationQuota, "user"
+	} else {
+		usage.Limit = s.settings.QuotaSettings(ctx).Roles[u.Role]
+	}
+	usage.Unlimited = usage.Limit <= 0
+	used, err := s.locations.CountByCreator(ctx, u.Email)
+	if err != nil {
+		return usage, err
+	}
+	usage.Used = used
+	return usage, nil
+}
+
+// snap menggeser koordinat ke jalan/bangunan terdekat. Kegagalan provider
+// tidak menggagalkan request: koordinat asli tetap dipakai.
+func (s *LocationService) snap(ctx context.Context, loc *models.Location) {
+	if s.opts.MapMatcher == nil {
+		return
+	}
+	res, err := s.opts.MapMatcher.Snap(ctx, loc.Coordinates)
+	if err != nil {
+		if !errors.Is(err, mapmatch.ErrTooFar) {
+			log.Println("map-matching:", err)
+		}
+		return
+	}
+	loc.Snap = &models.SnapInfo{
+		RawCoordinates: loc.Coordinates,
+		Provider:       s.opts.MapMatcher.Name(),
+		DistanceM:      res.DistanceM,
+	}
+	loc.Coordinates = res.Coordinates
+}
+
+// lookupElevation mengisi elevation_m. Kegagalan provider hanya dicatat;
+// lokasi tanpa ketinggian akan diisi oleh backfill.
+func (s *LocationService) lookupElevation(ctx context.Context, loc *models.Location) {
+	if s.opts.Elevation == nil {
+		return
+	}
+	elevations, err := s.opts.Elevation.Lookup(ctx, []models.Coordinates{loc.Coordinates})
+	if err != nil {
+		log.Println("elevation:", err)
+		return
+	}
+	loc.ElevationM = &elevations[0]
+}
+
+// Create menyimpan lokasi baru. Jika snap true dan map-matching aktif,
+// koordinat digeser ke jalan/bangunan terdekat.
+func (s *LocationService) Create(ctx context.Context, u models.User, payload map[string]interface{}, snap bool) (*models.Location, []string, error) {
+	resolve, err := s.categories.Resolver(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	loc, ignored, err := s.decode(ctx, u, payload, resolve, s.newTarget(ctx, u, payload, resolve))
+	if err != nil {
+		return nil, nil, err
+	}
+	usage, err := s.QuotaUsage(ctx, u)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !usage.Unlimited && usage.Used >= int64(usage.Limit) {
+		return nil, nil, &QuotaExceededError{Usage: usage}
+	}
+	loc.ID = primitive.NewObjectID()
+	if loc.CreatedBy == "" {
+		loc.CreatedBy = u.Email
+	}
+	if snap {
+		s.snap(ctx, &loc)
+	}
+	s.lookupElevation(ctx, &loc)
+	loc.AdminArea = s.regions.AdminArea(ctx, loc.Coordinates)
+	loc.ModerationStatus = s.initialModeration(ctx, u, &loc)
+	if err := s.postcodes.Apply(ctx, &loc); err != nil {
+		return nil, nil, err
+	}
+	if err := s.locations.Create(ctx, &loc); err != nil {
+		return nil, nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "location.create", ResourceType: AuditLocation, ResourceID: loc.ID.Hex(), After: loc})
+	s.opts.Events.Publish(ctx, "location.create", loc.ID)
+	return &loc, ignored, nil
+}
+
+// authorize memastikan requestor adalah pembuat lokasi atau memiliki
+// permission anyPerm (mis. locations:delete_any) dengan lokasi di dalam
+// scope role-nya.
+func (s *LocationService) authorize(ctx context.Context, u models.User, id primitive.ObjectID, anyPerm string) (*models.Location, error) {
+	existing, err := s.locations.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrLocationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if existing.CreatedBy != u.Email && !s.roles.CanModerate(ctx, u.Role, anyPerm, existing) {
+		return nil, ErrForbidden
+	}
+	return existing, nil
+}
+
+func (s *LocationService) Update(ctx context.Context, u models.User, id primitive.ObjectID, payload map[string]interface{}) ([]string, error) {
+	existing, err := s.authorize(ctx, u, id, rbac.LocationsUpdateAny)
+	if err != nil {
+		return nil, err
+	}
+	resolve, err := s.categories.Resolver(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data, ignored, err := s.decode(ctx, u, payload, resolve, existing)
+	if err != nil {
+		return nil, err
+	}
+	set := repositories.Fields{"name": data.Name, "category": data.Category, "coordinates": data.Coordinates, "updated_at": time.Now().UTC()}
+	// Koordinat dipindah manual: info snap, ketinggian & wilayah lama tidak berlaku lagi
+	data.AdminArea = existing.AdminArea
+	if data.Coordinates != existing.Coordinates {
+		set["snap"] = nil
+		s.lookupElevation(ctx, &data)
+		set["elevation_m"] = data.ElevationM
+		data.AdminArea = s.regions.AdminArea(ctx, data.Coordinates)
+		set["admin_area"] = data.AdminArea
+	}
+	// Moderator ber-scope tidak boleh memindahkan lokasi orang lain keluar scope-nya
+	if existing.CreatedBy != u.Email && !s.roles.CanModerate(ctx, u.Role, rbac.LocationsUpdateAny, &data) {
+		return nil, ErrForbidden
+	}
+	// Suntingan user tanpa locations:moderate harus direview ulang sebelum
+	// tampil lagi di daftar publik
+	if s.initialModeration(ctx, u, &data) == models.ModerationPending {
+		set["moderation_status"] = models.ModerationPending
+		set["rejection_reason"] = nil
+	}
+	if err := s.postcodes.Apply(ctx, &data); err != nil {
+		return nil, err
+	}
+	set["address"], set["postal_code"] = data.Address, data.PostalCode
+	// Field terproteksi hanya di-set jika dikirim (dan lolos field policy)
+	if _, sent := payload["verified"]; sent {
+		set["verified"] = data.Verified
+	}
+	if _, sent := payload["status"]; sent {
+		set["status"] = data.Status
+	}
+	if _, sent := payload["visibility"]; sent {
+		set["visibility"] = data.Visibility
+	}
+	if _, sent := payload["created_by"]; sent {
+		set["created_by"] = data.CreatedBy
+	}
+	// Client lama tidak mengirim atribut ini; jangan sampai terhapus
+	if _, sent := payload["accessibility"]; sent {
+		set["accessibility"] = data.Accessibility
+	}
+	if _, sent := payload["price_range"]; sent {
+		set["price_range"] = data.PriceRange
+	}
+	if _, sent := payload["payment_methods"]; sent {
+		set["payment_methods"] = data.PaymentMethods
+	}
+	if _, sent := payload["approximate_coordinates"]; sent {
+		set["approximate_coordinates"] = data.ApproximateCoordinates
+	}
+	if err := s.locations.Update(ctx, id, set); err != nil {
+		return nil, err
+	}
+	if updated, err := s.locations.FindByID(ctx, id); err == nil {
+		s.audit.Record(ctx, AuditEvent{Action: "location.update", ResourceType: AuditLocation, ResourceID: id.Hex(), Before: existing, After: updated})
+	}
+	s.opts.Events.Publish(ctx, "location.update", id)
+	return ignored, nil
+}
+
+// Delete memindahkan lokasi ke trash dan mengembalikan datanya (untuk
+// audit). Lokasi bisa dipulihkan sampai di-purge.
+func (s *LocationService) Delete(ctx context.Context, u models.User, id primitive.ObjectID) (*models.Location, error) {
+	existing, err := s.authorize(ctx, u, id, rbac.LocationsDeleteAny)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.locations.SoftDelete(ctx, id, u.Email, time.Now().UTC()); errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrLocationNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "location.delete", ResourceType: AuditLocation, ResourceID: id.Hex(), Before: existing})
+	s.opts.Events.Publish(ctx, "location.delete", id)
+	return existing, nil
+}
+
+// Embed mengembalikan data publik lokasi untuk kartu embed, dengan label
+// kategori dalam bahasa lang. Lokasi yang belum tayang, di trash atau di
+// arsip dianggap tidak ada.
+func (s *LocationService) Embed(ctx context.Context, id primitive.ObjectID, lang string) (*models.LocationEmbed, error) {
+	loc, err := s.Get(ctx, models.User{}, id, false)
+	if err != nil {
+		return nil, err
+	}
+	if loc.DeletedAt != nil {
+		return nil, ErrLocationNotFound
+	}
+	embed := &models.LocationEmbed{
+		Version:                models.LocationEmbedVersion,
+		ID:                     loc.ID,
+		Name:                   loc.Name,
+		Category:               models.EmbedCategory{Slug: loc.Category, Label: loc.Category},
+		Address:                loc.Address,
+		Coordinates:            loc.Coordinates,
+		ApproximateCoordinates: loc.ApproximateCoordinates,
+		Verified:               loc.Verified,
+		Status:                 loc.Status,
+		UpdatedAt:              loc.UpdatedAt,
+	}
+	if loc.Rating != nil {
+		embed.Rating = *loc.Rating
+	}
+	if len(loc.Photos) > 0 {
+		embed.PhotoURL = &loc.Photos[0].URL
+	}
+	if c, err := s.categories.Get(ctx, loc.Category); err == nil {
+		categories := []models.Category{*c}
+		LocalizeCategories(categories, lang)
+		embed.Category = models.EmbedCategory{Slug: c.Slug, Label: categories[0].Label, Icon: c.Icon, Color: c.Color}
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	return embed, nil
+}
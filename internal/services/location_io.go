@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"InfoCuy-Backend/internal/geoio"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Batas jumlah baris per file import
+const MaxImportRows = 5000
+
+// ExportParams adalah filter GET /locations/export (sama seperti list)
+type ExportParams struct {
+	Category   string
+	CreatedBy  string
+	Q          string
+	Attributes AttributeParams
+	// Ikut mengekspor lokasi di arsip (tidak berlaku untuk facets)
+	IncludeArchived bool
+	// Penentu koordinat asli atau perkiraan; zero value untuk tanpa login
+	Viewer models.User
+}
+
+// Import memvalidasi setiap baris lalu menyimpan yang lolos sekaligus.
+// Baris yang gagal (format, field policy, validasi, kuota) dilaporkan per
+// baris tanpa menggagalkan baris lain. Baris yang hanya berisi alamat
+// diantrekan ke job geocoding (lihat GeocodeImports) dan ikut memakai
+// kuota saat import.
+func (s *LocationService) Import(ctx context.Context, u models.User, rows []geoio.Row) (models.ImportResult, error) {
+	result := models.ImportResult{Total: len(rows), Errors: []models.ImportRowError{}}
+	if len(rows) == 0 {
+		return result, invalid("File tidak berisi data lokasi")
+	}
+	if len(rows) > MaxImportRows {
+		return result, invalid("Maksimal %d baris per import", MaxImportRows)
+	}
+	usage, err := s.QuotaUsage(ctx, u)
+	if err != nil {
+		return result, err
+	}
+	remaining := int64(usage.Limit) - usage.Used
+	resolve, err := s.categories.Resolver(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	fail := func(row int, msg string) {
+		result.Errors = append(result.Errors, models.ImportRowError{Row: row, Error: msg})
+	}
+	var valid []models.Location
+	var geocoding []models.ImportJobRow
+	for _, row := range rows {
+		if row.Err != nil {
+			fail(row.Row, row.Err.Error())
+			continue
+		}
+		_, hasCoordinates := row.Payload["coordinates"]
+		address, _ := row.Payload["address"].(string)
+		pending := !hasCoordinates && strings.TrimSpace(address) != "" && s.geocodesImports()
+		if pending {
+			// Koordinat sementara hanya untuk validasi, diganti hasil geocoding
+			row.Payload["coordinates"] = map[string]interface{}{"lat": 0.0, "lng": 0.0}
+		}
+		loc, _, err := s.decode(ctx, u, row.Payload, resolve, s.newTarget(ctx, u, row.Payload, resolve))
+		if err != nil {
+			fail(row.Row, err.Error())
+			continue
+		}
+		if !usage.Unlimited && int64(len(valid)+len(geocoding)) >= remaining {
+			fail(row.Row, "kuota lokasi habis")
+			continue
+		}
+		loc.ID = primitive.NewObjectID()
+		if loc.CreatedBy == "" {
+			loc.CreatedBy = u.Email
+		}
+		if pending {
+			loc.Coordinates = models.Coordinates{}
+			geocoding = append(geocoding, models.ImportJobRow{Row: row.Row, Name: loc.Name, Address: loc.Address,
+				Status: models.ImportRowPending, LocationID: loc.ID, Location: loc})
+			continue
+		}
+		loc.AdminArea = s.regions.AdminArea(ctx, loc.Coordinates)
+		loc.ModerationStatus = s.initialModeration(ctx, u, &loc)
+		if err := s.postcodes.Apply(ctx, &loc); err != nil {
+			fail(row.Row, err.Error())
+			continue
+		}
+		valid = append(valid, loc)
+	}
+	if err := s.locations.CreateMany(ctx, valid); err != nil {
+		return result, err
+	}
+	events := make([]AuditEvent, len(valid))
+	ids := make([]primitive.ObjectID, len(valid))
+	for i, loc := range valid {
+		events[i] = AuditEvent{Action: "location.import", ResourceType: AuditLocation, ResourceID: loc.ID.Hex(), After: loc}
+		ids[i] = loc.ID
+	}
+	s.audit.Record(ctx, events...)
+	s.opts.Events.Publish(ctx, "location.import", ids...)
+	result.Inserted = len(valid)
+	result.Failed = len(result.Errors)
+	if len(geocoding) > 0 {
+		job, err := s.queueGeocoding(ctx, u, geocoding)
+		if err != nil {
+			return result, err
+		}
+		result.Geocoding, result.JobID = len(geocoding), &job.ID
+	}
+	return result, nil
+}
+
+// Export menulis semua lokasi yang cocok dengan filter ke w.
+// Facets menghitung lokasi per kategori, kisaran harga dan metode
+// pembayaran dengan filter yang sama seperti export.
+func (s *LocationService) Facets(ctx context.Context, p ExportParams) (models.LocationFacets, error) {
+	attrs, err := p.Attributes.filter()
+	if err != nil {
+		return models.LocationFacets{}, err
+	}
+	return s.locations.Facets(ctx, repositories.LocationQuery{
+		Category:   p.Category,
+		CreatedBy:  p.CreatedBy,
+		Text:       strings.TrimSpace(p.Q),
+		Attributes: attrs,
+	})
+}
+
+func (s *LocationService) Export(ctx context.Context, p ExportParams, w geoio.Writer) error {
+	attrs, err := p.Attributes.filter()
+	if err != nil {
+		return err
+	}
+	conceal, err := s.concealer(ctx, p.Viewer)
+	if err != nil {
+		return err
+	}
+	err = s.locations.Each(ctx, repositories.LocationQuery{
+		Category:        p.Category,
+		CreatedBy:       p.CreatedBy,
+		Text:            strings.TrimSpace(p.Q),
+		Attributes:      attrs,
+		IncludeArchived: p.IncludeArchived,
+	}, func(loc models.Location) error {
+		conceal(&loc)
+		return w.Write(loc)
+	})
+	if err != nil {
+		return err
+	}
+	return w.Close()
+}
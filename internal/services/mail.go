@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"InfoCuy-Backend/internal/mailer"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Email gagal disimpan selama ini untuk dikirim ulang admin
+	deadLetterRetention = 7 * 24 * time.Hour
+)
+
+// MailOutbox adalah mailer.Mailer yang dipakai semua service. Email
+// transaksional yang gagal dikirim provider disimpan sebagai dead letter
+// supaya bisa dikirim ulang setelah gangguan provider selesai, tanpa user
+// harus mengulang permintaannya. Email massal (Message.Bulk) dan email
+// berlampiran tidak disimpan karena pengirimnya mencatat kegagalannya
+// sendiri (status penerima campaign, last_run export).
+type MailOutbox struct {
+	mailer.Mailer
+	deadLetters repositories.DeadLetterRepository
+	audit       *AuditService
+}
+
+func NewMailOutbox(m mailer.Mailer, deadLetters repositories.DeadLetterRepository, audit *AuditService) *MailOutbox {
+	return &MailOutbox{Mailer: m, deadLetters: deadLetters, audit: audit}
+}
+
+// Send mengirim msg lewat provider. Error tetap dikembalikan ke pemanggil
+// walaupun email sudah disimpan sebagai dead letter.
+func (o *MailOutbox) Send(ctx context.Context, msg mailer.Message) error {
+	err := o.Mailer.Send(ctx, msg)
+	if err == nil || msg.Bulk || len(msg.Attachments) > 0 {
+		return err
+	}
+	now := time.Now().UTC()
+	d := &models.DeadLetter{
+		Provider:      o.Name(),
+		To:            msg.To,
+		Subject:       msg.Subject,
+		Body:          msg.Body,
+		HTML:          msg.HTML,
+		Error:         err.Error(),
+		Attempts:      1,
+		Rejected:      mailer.Rejected(err),
+		CreatedAt:     now,
+		LastAttemptAt: now,
+		ExpiresAt:     now.Add(deadLetterRetention),
+	}
+	// Tetap disimpan walaupun request pemanggil sudah selesai
+	if saveErr := o.deadLetters.Create(context.WithoutCancel(ctx), d); saveErr != nil {
+		log.Println("mail dead letter:", saveErr)
+	}
+	return err
+}
+
+func (o *MailOutbox) find(ctx context.Context, id primitive.ObjectID) (*models.DeadLetter, error) {
+	d, err := o.deadLetters.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrDeadLetterNotFound
+	}
+	return d, err
+}
+
+// DeadLetters mengembalikan email yang gagal dikirim, terbaru lebih dulu.
+func (o *MailOutbox) DeadLetters(ctx context.Context, opts models.ListOptions) ([]models.DeadLetter, models.PageMeta, error) {
+	p, err := listPage(repositories.DeadLetterList, opts)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	letters, total, err := o.deadLetters.List(ctx, p)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	meta := repositories.Meta(p, total, letters)
+	return letters, meta, nil
+}
+
+// Resend mengirim ulang dead letter lewat provider yang sedang aktif. Jika
+// berhasil, dead letter dihapus; jika gagal lagi, percobaannya dicatat dan
+// error dibungkus ErrMailFailed.
+func (o *MailOutbox) Resend(ctx context.Context, id primitive.ObjectID) (*models.DeadLetter, error) {
+	d, err := o.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sendErr := o.Mailer.Send(ctx, mailer.Message{To: d.To, Subject: d.Subject, Body: d.Body, HTML: d.HTML})
+	if sendErr != nil {
+		d.Attempts++
+		d.LastAttemptAt = time.Now().UTC()
+		d.Error = sendErr.Error()
+		d.Rejected = mailer.Rejected(sendErr)
+		if err := o.deadLetters.RecordAttempt(ctx, id, d.LastAttemptAt, d.Error, d.Rejected); err != nil {
+			return nil, err
+		}
+		return d, fmt.Errorf("%w: %w", ErrMailFailed, sendErr)
+	}
+	if err := o.deadLetters.Delete(ctx, id); err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+	o.audit.Record(ctx, AuditEvent{Action: "mail.resend", ResourceType: AuditMail, ResourceID: id.Hex(), Before: d})
+	return d, nil
+}
+
+// Discard menghapus dead letter tanpa mengirimnya.
+func (o *MailOutbox) Discard(ctx context.Context, id primitive.ObjectID) error {
+	d, err := o.find(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := o.deadLetters.Delete(ctx, id); errors.Is(err, repositories.ErrNotFound) {
+		return ErrDeadLetterNotFound
+	} else if err != nil {
+		return err
+	}
+	o.audit.Record(ctx, AuditEvent{Action: "mail.discard", ResourceType: AuditMail, ResourceID: id.Hex(), Before: d})
+	return nil
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultMapViewBatch = 500
+	maxMapViewBatch     = 2000
+)
+
+// MapViewService menjaga read model map_view (id, nama, kategori,
+// koordinat, rating, status, counter favorit/check-in,
+// approximate_coordinates) tetap sama dengan geo_data dan melayani
+// daftar publik GET /locations darinya, sehingga daftar tidak perlu
+// menghitung rating per request dan tidak terpengaruh dokumen lokasi yang
+// besar.
+type MapViewService struct {
+	views      repositories.MapViewRepository
+	locations  repositories.LocationRepository
+	settings   *SettingsService
+	categories *CategoryService
+	promotions *PromotionService
+	// Half-life skor freshness untuk faktor relevansi
+	halfLife time.Duration
+}
+
+// NewMapViewService membuat service dan berlangganan perubahan lokasi.
+// halfLife 0 = DefaultFreshnessHalfLife.
+func NewMapViewService(views repositories.MapViewRepository, locations repositories.LocationRepository,
+	settings *SettingsService, categories *CategoryService, promotions *PromotionService, events *LocationEvents, halfLife time.Duration) *MapViewService {
+	if halfLife <= 0 {
+		halfLife = DefaultFreshnessHalfLife
+	}
+	s := &MapViewService{views: views, locations: locations, settings: settings, categories: categories, promotions: promotions, halfLife: halfLife}
+	events.Subscribe(s.handle)
+	return s
+}
+
+// handle memproyeksikan ulang lokasi yang berubah. Kegagalan hanya dicatat;
+// map_view diperbaiki oleh job rebuild.
+func (s *MapViewService) handle(ctx context.Context, ev LocationChanged) {
+	if err := s.views.Project(context.WithoutCancel(ctx), ev.IDs); err != nil {
+		log.Println("map_view", ev.Action+":", err)
+	}
+}
+
+// List melayani GET /locations. Filter yang tidak ada di map_view
+// (created_by, q, atribut, include_archived) dijawab dari geo_data dengan
+// bentuk response yang sama. Lokasi sensitif selalu tampil dengan koordinat
+// perkiraan. Halaman pertama diawali lokasi pinned/sponsored yang cocok
+// dengan filter (di luar limit dan total, ditandai field promotion).
+// Pencarian dengan ?q diurutkan menurut skor relevansi kecuali ?sort diisi.
+func (s *MapViewService) List(ctx context.Context, p ListParams) ([]models.MapViewItem, models.PageMeta, error) {
+	if p.List.Sort == SortRelevance || p.List.Sort == "" && strings.TrimSpace(p.Q) != "" {
+		return s.search(ctx, p)
+	}
+	page, err := locationPage(s.settings.NearLimits(ctx, p.Category), p.List)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	attrs, err := p.Attributes.filter()
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	q := strings.TrimSpace(p.Q)
+
+	query := repositories.LocationQuery{
+		Category:        p.Category,
+		CreatedBy:       p.CreatedBy,
+		Text:            q,
+		Attributes:      attrs,
+		IncludeArchived: p.IncludeArchived,
+		ListPage:        page,
+	}
+
+	var items []models.MapViewItem
+	var total int64
+	if p.CreatedBy == "" && q == "" && !p.IncludeArchived && len(attrs.Accessible)+len(attrs.PriceRanges)+len(attrs.PaymentMethods) == 0 {
+		items, total, err = s.views.List(ctx, repositories.MapViewQuery{
+			Category: p.Category,
+			ListPage: page,
+		})
+	} else {
+		var locations []models.Location
+		locations, total, err = s.locations.List(ctx, query)
+		items = make([]models.MapViewItem, len(locations))
+		for i, loc := range locations {
+			items[i] = mapViewItem(loc)
+		}
+	}
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	// Cursor dibuat dari hasil organik, sebelum lokasi promosi disisipkan
+	meta := repositories.Meta(page, total, items)
+	// Daftar "lokasi saya" tidak dipromosikan; halaman lewat cursor bukan
+	// halaman pertama (Page 0)
+	if page.Page == 1 && p.CreatedBy == "" {
+		if items, err = s.withPromoted(ctx, query, items, !p.SkipImpressions); err != nil {
+			return nil, models.PageMeta{}, err
+		}
+	}
+	if err := concealItems(ctx, s.categories, items); err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	return items, meta, nil
+}
+
+// search mengurutkan hasil pencarian menurut skor relevansi (lihat
+// rankFactors) dengan bobot dari pengaturan ranking. Skor dihitung di
+// aplikasi untuk maxRankCandidates lokasi terbaru yang cocok, jadi halaman
+// memakai ?page, bukan cursor.
+func (s *MapViewService) search(ctx context.Context, p ListParams) ([]models.MapViewItem, models.PageMeta, error) {
+	q := strings.TrimSpace(p.Q)
+	var v validator
+	v.required(q != "", "q")
+	v.check(p.List.Cursor == "", "cursor", RuleCursor, nil)
+	if p.Near != nil {
+		v.check(p.Near.Lat >= -90 && p.Near.Lat <= 90, "lat", RuleRange, Params{"min": -90, "max": 90})
+		v.check(p.Near.Lng >= -180 && p.Near.Lng <= 180, "lng", RuleRange, Params{"min": -180, "max": 180})
+	}
+	if err := v.err(); err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	o := p.List
+	o.Sort = ""
+	page, err := locationPage(s.settings.NearLimits(ctx, p.Category), o)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	attrs, err := p.Attributes.filter()
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	query := repositories.LocationQuery{
+		Category:        p.Category,
+		CreatedBy:       p.CreatedBy,
+		Text:            q,
+		Attributes:      attrs,
+		IncludeArchived: p.IncludeArchived,
+		ListPage:        repositories.ListPage{Desc: true, Limit: maxRankCandidates},
+	}
+	locations, total, err := s.locations.List(ctx, query)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	items := make([]models.MapViewItem, len(locations))
+	for i, loc := range locations {
+		items[i] = mapViewItem(loc)
+	}
+	// Disamarkan dulu supaya faktor jarak memakai koordinat yang dilihat client
+	if err := concealItems(ctx, s.categories, items); err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	weights, now := s.settings.RankingWeights(ctx), time.Now()
+	scores := make([]float64, len(items))
+	for i := range items {
+		explain := rank(weights, rankInput{loc: &locations[i], coords: items[i].Coordinates, query: q, ref: p.Near, now: now, halfLife: s.halfLife})
+		scores[i] = explain.Score
+		if p.Explain {
+			items[i].Ranking = &explain
+		}
+	}
+	// Skor sama tetap urut terbaru dulu seperti hasil repository
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return cmp.Compare(scores[b], scores[a]) })
+	ranked := make([]models.MapViewItem, len(items))
+	for i, idx := range order {
+		ranked[i] = items[idx]
+	}
+
+	total = min(total, int64(len(ranked)))
+	meta := models.PageMeta{Page: page.Page, Limit: int(page.Limit), Total: total}
+	if page.Limit > 0 {
+		meta.TotalPages = (total + page.Limit - 1) / page.Limit
+	}
+	from := min(page.Skip, total)
+	items = ranked[from:min(from+page.Limit, total)]
+	if page.Page == 1 && p.CreatedBy == "" {
+		query.ListPage = page
+		if items, err = s.withPromoted(ctx, query, items, !p.SkipImpressions); err != nil {
+			return nil, models.PageMeta{}, err
+		}
+		if err := concealItems(ctx, s.categories, items); err != nil {
+			return nil, models.PageMeta{}, err
+		}
+	}
+	return items, meta, nil
+}
+
+// withPromoted menaruh lokasi promosi di depan items dan membuang
+// duplikatnya dari hasil organik. record false melewatkan pencatatan
+// impression.
+func (s *MapViewService) withPromoted(ctx context.Context, q repositories.LocationQuery, items []models.MapViewItem, record bool) ([]models.MapViewItem, error) {
+	promoted, err := s.promotions.boost(ctx, q, record)
+	if err != nil || len(promoted) == 0 {
+		return items, err
+	}
+	ids := map[primitive.ObjectID]bool{}
+	for _, item := range promoted {
+		ids[item.ID] = true
+	}
+	for _, item := range items {
+		if !ids[item.ID] {
+			promoted = append(promoted, item)
+		}
+	}
+	return promoted, nil
+}
+
+func mapViewItem(loc models.Location) models.MapViewItem {
+	item := models.MapViewItem{
+		ID:                     loc.ID,
+		Name:                   loc.Name,
+		Category:               loc.Category,
+		Coordinates:            loc.Coordinates,
+		Status:                 loc.Status,
+		FavoritesCount:         loc.FavoritesCount,
+		CheckInsCount:          loc.CheckInsCount,
+		ApproximateCoordinates: loc.ApproximateCoordinates,
+	}
+	if loc.Rating != nil {
+		item.Rating = *loc.Rating
+	}
+	return item
+}
+
+// Rebuild memproyeksikan ulang satu batch lokasi setelah after (urut ID)
+// dan menghapus dokumen map_view yatim di rentang yang sama. Dipakai untuk
+// mengisi map_view pertama kali dan memperbaiki event yang gagal.
+func (s *MapViewService) Rebuild(ctx context.Context, after primitive.ObjectID, batch int) (models.MapViewRebuildResult, error) {
+	var result models.MapViewRebuildResult
+	if batch <= 0 {
+		batch = defaultMapViewBatch
+	}
+	if batch > maxMapViewBatch {
+		batch = maxMapViewBatch
+	}
+	ids, err := s.views.LocationIDsAfter(ctx, after, int64(batch))
+	if err != nil {
+		return result, err
+	}
+	if err := s.views.Project(ctx, ids); err != nil {
+		return result, err
+	}
+	result.Processed = len(ids)
+	// Batch terakhir juga membersihkan semua ID setelahnya
+	upTo := primitive.NilObjectID
+	if len(ids) == batch {
+		upTo = ids[len(ids)-1]
+		result.Next = upTo.Hex()
+	}
+	if result.Removed, err = s.views.Prune(ctx, after, upTo, ids); err != nil {
+		return result, err
+	}
+	if result.Next != "" {
+		result.Remaining, err = s.views.CountLocationsAfter(ctx, upTo)
+	}
+	return result, err
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/osm"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+const (
+	defaultMissingRadiusM = 1000
+	// Overpass melambat tajam di atas radius ini untuk pusat kota
+	maxMissingRadiusM    = 3000
+	defaultMissingLimit  = 20
+	maxMissingLimit      = 50
+	maxMissingCandidates = 1000
+	// POI dianggap sudah ada jika ada lokasi kita dengan nama yang sama
+	// dalam jarak ini (koordinat kontributor dan OSM jarang persis sama)...
+	missingNameMatchM = 150
+	// ...atau lokasi dengan kategori yang sama yang hampir berimpit
+	missingSameCategoryM = 30
+)
+
+// MissingPOIService membandingkan POI OpenStreetMap di sekitar sebuah
+// titik dengan data lokasi kita, untuk mengarahkan kontributor ke tempat
+// yang belum tercatat. Kategori dipetakan ke OSM lewat Category.OSMTags.
+type MissingPOIService struct {
+	locations  repositories.LocationRepository
+	categories *CategoryService
+	// nil jika POI_PROVIDER tidak diisi
+	provider osm.Provider
+}
+
+func NewMissingPOIService(locations repositories.LocationRepository, categories *CategoryService, provider osm.Provider) *MissingPOIService {
+	return &MissingPOIService{locations: locations, categories: categories, provider: provider}
+}
+
+// MissingParams adalah query GET /suggestions/missing (radius dalam meter).
+type MissingParams struct {
+	Lat, Lng float64
+	RadiusM  float64
+	// Kosong = semua kategori yang punya osm_tags
+	Category string
+	Limit    int
+}
+
+// Missing mengembalikan POI OSM dalam radius yang belum ada padanannya di
+// data kita, urut jarak terdekat.
+func (s *MissingPOIService) Missing(ctx context.Context, p MissingParams) ([]models.MissingPOI, float64, error) {
+	if s.provider == nil {
+		return nil, 0, invalid("Sumber POI belum dikonfigurasi (POI_PROVIDER)")
+	}
+	if p.RadiusM == 0 {
+		p.RadiusM = defaultMissingRadiusM
+	}
+	if p.Limit == 0 {
+		p.Limit = defaultMissingLimit
+	}
+	var v validator
+	v.check(p.Lat >= -90 && p.Lat <= 90, "lat", RuleRange, Params{"min": -90, "max": 90})
+	v.check(p.Lng >= -180 && p.Lng <= 180, "lng", RuleRange, Params{"min": -180, "max": 180})
+	v.check(p.RadiusM > 0 && p.RadiusM <= maxMissingRadiusM, "radius", RuleRange, Params{"min": 1, "max": maxMissingRadiusM})
+	v.check(p.Limit > 0 && p.Limit <= maxMissingLimit, "limit", RuleRange, Params{"min": 1, "max": maxMissingLimit})
+	if err := v.err(); err != nil {
+		return nil, 0, err
+	}
+	categoryByTag, tags, err := s.tags(ctx, p.Category)
+	if err != nil {
+		return nil, 0, err
+	}
+	missing := []models.MissingPOI{}
+	if len(tags) == 0 {
+		return missing, p.RadiusM, nil
+	}
+	center := models.Coordinates{Lat: p.Lat, Lng: p.Lng}
+	pois, err := s.provider.Around(ctx, center, p.RadiusM, tags)
+	if err != nil {
+		return nil, 0, err
+	}
+	// Lokasi di tepi radius tetap dibandingkan dengan POI di tepi radius
+	ours, err := s.locations.Nearby(ctx, repositories.NearbyQuery{
+		Lat: p.Lat, Lng: p.Lng, RadiusM: p.RadiusM + missingNameMatchM, Limit: maxMissingCandidates, SortField: "distance",
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	seen := map[string]bool{}
+	for _, poi := range pois {
+		tag, category := poiCategory(poi, tags, categoryByTag)
+		if category == "" || seen[poi.ID] || covered(poi, category, ours) {
+			continue
+		}
+		seen[poi.ID] = true
+		missing = append(missing, models.MissingPOI{
+			OSMID:       poi.ID,
+			Name:        poi.Name,
+			Category:    category,
+			OSMTag:      tag,
+			Coordinates: poi.Coordinates,
+			DistanceM:   center.DistanceM(poi.Coordinates),
+			Payload: models.MissingPOIPayload{
+				Name:        poi.Name,
+				Category:    category,
+				Address:     poi.Address(),
+				PostalCode:  poi.Tags["addr:postcode"],
+				Coordinates: poi.Coordinates,
+			},
+		})
+	}
+	slices.SortFunc(missing, func(a, b models.MissingPOI) int {
+		return cmp.Or(cmp.Compare(a.DistanceM, b.DistanceM), cmp.Compare(a.OSMID, b.OSMID))
+	})
+	if len(missing) > p.Limit {
+		missing = missing[:p.Limit]
+	}
+	return missing, p.RadiusM, nil
+}
+
+// tags memetakan tag OSM ke slug kategori. Jika dua kategori memakai tag
+// yang sama, kategori dengan slug terkecil yang dipakai.
+func (s *MissingPOIService) tags(ctx context.Context, only string) (map[string]string, []string, error) {
+	categories, err := s.categories.List(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if only != "" && !slices.ContainsFunc(categories, func(c models.Category) bool { return c.Slug == only }) {
+		return nil, nil, ErrCategoryNotFound
+	}
+	slices.SortFunc(categories, func(a, b models.Category) int { return cmp.Compare(a.Slug, b.Slug) })
+	categoryByTag := map[string]string{}
+	tags := []string{}
+	for _, c := range categories {
+		if only != "" && c.Slug != only {
+			continue
+		}
+		for _, tag := range c.OSMTags {
+			if _, ok := categoryByTag[tag]; !ok {
+				categoryByTag[tag] = c.Slug
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return categoryByTag, tags, nil
+}
+
+// poiCategory mengembalikan tag pertama (urutan tags) yang dimiliki POI
+// beserta kategorinya.
+func poiCategory(poi osm.POI, tags []string, categoryByTag map[string]string) (string, string) {
+	for _, tag := range tags {
+		key, value, _ := strings.Cut(tag, "=")
+		if poi.Tags[key] == value {
+			return tag, categoryByTag[tag]
+		}
+	}
+	return "", ""
+}
+
+// covered melaporkan apakah POI sudah punya padanan di data kita: nama
+// yang sama di dekatnya (dinormalisasi seperti teks review, sehingga beda
+// kapital dan tanda baca diabaikan), atau lokasi sekategori yang hampir
+// berimpit.
+func covered(poi osm.POI, category string, ours []models.NearbyLocation) bool {
+	name := normalizeReviewText(poi.Name)
+	for _, loc := range ours {
+		d := poi.Coordinates.DistanceM(loc.Coordinates)
+		if d <= missingNameMatchM && name != "" && normalizeReviewText(loc.Name) == name {
+			return true
+		}
+		if d <= missingSameCategoryM && loc.Category == category {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"InfoCuy-Backend/internal/mailer"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/rbac"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxRejectionReasonLen = 500
+)
+
+// initialModeration menentukan status moderasi lokasi baru atau yang baru
+// disunting: langsung approved untuk moderator yang scope-nya mencakup loc
+// (admin_area sudah terisi), pending untuk user lain.
+func (s *LocationService) initialModeration(ctx context.Context, u models.User, loc *models.Location) string {
+	if s.roles.CanModerate(ctx, u.Role, rbac.LocationsModerate, loc) {
+		return models.ModerationApproved
+	}
+	return models.ModerationPending
+}
+
+// canSeeUnlisted bernilai true jika viewer boleh melihat lokasi yang belum
+// atau tidak tayang: pembuatnya dan moderator yang scope-nya mencakup loc.
+func canSeeUnlisted(ctx context.Context, roles *RoleService, viewer models.User, loc *models.Location) bool {
+	if viewer.Email == "" {
+		return false
+	}
+	return loc.CreatedBy == viewer.Email || roles.CanModerate(ctx, viewer.Role, rbac.LocationsModerate, loc)
+}
+
+// Pending mengembalikan lokasi yang menunggu review di dalam scope u, yang
+// paling lama dikirim lebih dulu.
+func (s *LocationService) Pending(ctx context.Context, u models.User, opts models.ListOptions) ([]models.Location, models.PageMeta, error) {
+	p, err := listPage(repositories.PendingLocationList, opts)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	locations, total, err := s.locations.List(ctx, repositories.LocationQuery{
+		Moderation: models.ModerationPending,
+		Scope:      s.roles.Scope(ctx, u.Role),
+		ListPage:   p,
+	})
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	meta := repositories.Meta(p, total, locations)
+	return locations, meta, nil
+}
+
+// Approve menayangkan lokasi pending (atau yang pernah ditolak).
+func (s *LocationService) Approve(ctx context.Context, u models.User, id primitive.ObjectID) (*models.Location, error) {
+	loc, before, err := s.moderate(ctx, u, id, []string{models.ModerationPending, models.ModerationRejected}, repositories.Fields{
+		"moderation_status": models.ModerationApproved,
+		"rejection_reason":  nil,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "location.approve", ResourceType: AuditLocation, ResourceID: id.Hex(), Before: before, After: loc})
+	s.opts.Events.Publish(ctx, "location.approve", id)
+	return loc, nil
+}
+
+// Reject menolak lokasi pending. reason opsional; jika diisi ikut dikirim
+// ke pembuat lokasi.
+func (s *LocationService) Reject(ctx context.Context, u models.User, id primitive.ObjectID, reason string) (*models.Location, error) {
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) > maxRejectionReasonLen {
+		return nil, invalid("reason maksimal %d karakter", maxRejectionReasonLen)
+	}
+	set := repositories.Fields{"moderation_status": models.ModerationRejected, "rejection_reason": reason}
+	if reason == "" {
+		set["rejection_reason"] = nil
+	}
+	loc, before, err := s.moderate(ctx, u, id, []string{models.ModerationPending}, set)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "location.reject", ResourceType: AuditLocation, ResourceID: id.Hex(), Before: before, After: loc})
+	s.notifyRejected(ctx, loc)
+	return loc, nil
+}
+
+// moderate mengubah status moderasi jika status saat ini salah satu dari
+// from; ErrAlreadyModerated jika tidak, ErrForbidden jika lokasi di luar
+// scope u.
+func (s *LocationService) moderate(ctx context.Context, u models.User, id primitive.ObjectID, from []string, set repositories.Fields) (*models.Location, *models.Location, error) {
+	before, err := s.locations.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, nil, ErrLocationNotFound
+	} else if err != nil {
+		return nil, nil, err
+	}
+	if !s.roles.CanModerate(ctx, u.Role, rbac.LocationsModerate, before) {
+		return nil, nil, ErrForbidden
+	}
+	set["moderated_by"] = u.Email
+	set["moderated_at"] = time.Now().UTC()
+	loc, err := s.locations.Moderate(ctx, id, from, set)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, nil, ErrAlreadyModerated
+	} else if err != nil {
+		return nil, nil, err
+	}
+	return loc, before, nil
+}
+
+// notifyRejected memberi tahu pembuat lokasi lewat email. Kegagalan hanya
+// dicatat; alasan tetap terlihat di detail lokasi.
+func (s *LocationService) notifyRejected(ctx context.Context, loc *models.Location) {
+	if s.opts.Mail == nil {
+		return
+	}
+	body := fmt.Sprintf("Lokasi \"%s\" yang Anda kirim tidak disetujui moderator.", loc.Name)
+	if loc.RejectionReason != "" {
+		body += "\n\nAlasan: " + loc.RejectionReason
+	}
+	err := s.opts.Mail.Send(ctx, mailer.Message{To: loc.CreatedBy, Subject: "Lokasi Anda tidak disetujui", Body: body})
+	if err != nil {
+		log.Println("email penolakan lokasi", loc.ID.Hex()+":", err)
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"image"
+	_ "image/gif" // DecodeConfig untuk GIF
+	_ "image/jpeg"
+	_ "image/png"
+	"log"
+	"net/http"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/rbac"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// MaxPhotoBytes adalah ukuran maksimal satu foto.
+	MaxPhotoBytes = 5 << 20
+	// Jumlah foto maksimal per lokasi
+	maxPhotosPerLocation = 10
+)
+
+// Tipe gambar yang diterima (hasil sniffing, bukan header dari client)
+var photoExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
+
+// AddPhoto mengunggah foto ke object store lalu mencatatnya di lokasi.
+// Hanya pembuat lokasi atau pemilik permission locations:update_any.
+func (s *LocationService) AddPhoto(ctx context.Context, u models.User, id primitive.ObjectID, data []byte) (*models.Photo, error) {
+	store := s.opts.Photos
+	if store == nil {
+		return nil, invalid("Upload foto belum dikonfigurasi (OBJECT_STORE)")
+	}
+	existing, err := s.authorize(ctx, u, id, rbac.LocationsUpdateAny)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing.Photos) >= maxPhotosPerLocation {
+		return nil, invalid("Maksimal %d foto per lokasi", maxPhotosPerLocation)
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := photoExtensions[contentType]
+	if !ok {
+		return nil, &ValidationError{Message: "Data tidak valid", Fields: []FieldError{
+			fieldError("file", RuleImage, Params{"formats": []string{"JPEG", "PNG", "WebP", "GIF"}}),
+		}}
+	}
+
+	photo := models.Photo{
+		ID:          primitive.NewObjectID(),
+		Store:       store.Name(),
+		ContentType: contentType,
+		SizeBytes:   int64(len(data)),
+		UploadedBy:  u.Email,
+		UploadedAt:  time.Now().UTC(),
+	}
+	// WebP tidak didukung image/*, dimensi dibiarkan kosong
+	if cfg, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		photo.Width, photo.Height = cfg.Width, cfg.Height
+	}
+	obj, err := store.Put(ctx, "locations/"+id.Hex()+"/"+photo.ID.Hex()+ext, contentType, data)
+	if err != nil {
+		return nil, err
+	}
+	photo.Key, photo.URL = obj.Key, obj.URL
+
+	added, err := s.locations.AddPhoto(ctx, id, photo, maxPhotosPerLocation)
+	if err != nil || !added {
+		// Jangan tinggalkan file yatim di storage
+		if delErr := store.Delete(context.WithoutCancel(ctx), obj.Key); delErr != nil {
+			log.Println("hapus foto", obj.Key+":", delErr)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return nil, invalid("Maksimal %d foto per lokasi", maxPhotosPerLocation)
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "location.photo_add", ResourceType: AuditLocation, ResourceID: id.Hex(), After: photo})
+	return &photo, nil
+}
+
+// DeletePhoto menghapus foto dari lokasi dan dari object store.
+func (s *LocationService) DeletePhoto(ctx context.Context, u models.User, id, photoID primitive.ObjectID) error {
+	existing, err := s.authorize(ctx, u, id, rbac.LocationsUpdateAny)
+	if err != nil {
+		return err
+	}
+	var photo *models.Photo
+	for i := range existing.Photos {
+		if existing.Photos[i].ID == photoID {
+			photo = &existing.Photos[i]
+			break
+		}
+	}
+	if photo == nil {
+		return ErrPhotoNotFound
+	}
+	if err := s.locations.RemovePhoto(ctx, id, photoID); err != nil {
+		return err
+	}
+	s.deletePhotoFiles(ctx, *photo)
+	s.audit.Record(ctx, AuditEvent{Action: "location.photo_delete", ResourceType: AuditLocation, ResourceID: id.Hex(), Before: photo})
+	return nil
+}
+
+// deletePhotoFiles menghapus file foto dari storage. Kegagalan hanya
+// dicatat karena data di MongoDB sudah terhapus.
+func (s *LocationService) deletePhotoFiles(ctx context.Context, photos ...models.Photo) {
+	store := s.opts.Photos
+	for _, p := range photos {
+		if store == nil || store.Name() != p.Store {
+			log.Println("foto", p.Key, "tidak dihapus: object store", p.Store, "tidak aktif")
+			continue
+		}
+		if err := store.Delete(context.WithoutCancel(ctx), p.Key); err != nil {
+			log.Println("hapus foto", p.Key+":", err)
+		}
+	}
+}
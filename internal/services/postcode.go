@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"strings"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/postcode"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+type PostcodeService struct {
+	repo  repositories.PostcodeRepository
+	audit *AuditService
+}
+
+func NewPostcodeService(repo repositories.PostcodeRepository, audit *AuditService) *PostcodeService {
+	return &PostcodeService{repo: repo, audit: audit}
+}
+
+// Import mengganti seluruh dataset kode pos dengan isi CSV.
+func (s *PostcodeService) Import(ctx context.Context, r io.Reader) (models.PostcodeImportResult, error) {
+	var result models.PostcodeImportResult
+	postcodes, skipped, err := postcode.Read(r)
+	if errors.Is(err, postcode.ErrInvalidFile) {
+		return result, invalid("%s", err.Error())
+	}
+	if err != nil {
+		return result, err
+	}
+	if len(postcodes) == 0 {
+		return result, invalid("File tidak berisi kode pos yang valid")
+	}
+	if err := s.repo.ReplaceAll(ctx, postcodes); err != nil {
+		return result, err
+	}
+	result.Postcodes = len(postcodes)
+	result.Skipped = skipped
+	for _, pc := range postcodes {
+		result.Areas += len(pc.Areas)
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "postcode.import", ResourceType: AuditPostcode, ResourceID: "dataset", After: result})
+	return result, nil
+}
+
+func (s *PostcodeService) Get(ctx context.Context, code string) (*models.Postcode, error) {
+	if !postcode.Valid(code) {
+		return nil, invalid("Kode pos harus 5 digit angka")
+	}
+	pc, err := s.repo.Get(ctx, code)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrPostcodeNotFound
+	}
+	return pc, err
+}
+
+// Apply memvalidasi dan melengkapi kode pos lokasi sebelum disimpan:
+//   - kode pos diambil dari postal_code, atau dari angka 5 digit di address
+//   - jika dataset sudah di-import, kode pos harus terdaftar
+//   - jika tidak ada kode pos, diisi otomatis dari kecamatan (admin_area)
+//     selama kecamatan tersebut hanya punya satu kode pos
+//
+// Kode pos yang dipakai selalu ditambahkan ke akhir address jika belum ada.
+func (s *PostcodeService) Apply(ctx context.Context, loc *models.Location) error {
+	loaded, err := s.repo.Loaded(ctx)
+	if err != nil {
+		return err
+	}
+	code := strings.TrimSpace(loc.PostalCode)
+	inAddress := postcode.FromAddress(loc.Address)
+	if code == "" {
+		code = inAddress
+	}
+
+	if code == "" {
+		if loaded {
+			code = s.infer(ctx, loc.AdminArea)
+		}
+		if code == "" {
+			loc.PostalCode = ""
+			return nil
+		}
+	} else {
+		var v validator
+		v.check(postcode.Valid(code), "postal_code", RulePostalCode, nil)
+		v.check(inAddress == "" || inAddress == code, "postal_code", RulePostalMismatch, Params{"address": inAddress})
+		if err := v.err(); err != nil {
+			return err
+		}
+		if loaded {
+			if _, err := s.repo.Get(ctx, code); errors.Is(err, repositories.ErrNotFound) {
+				v.check(false, "postal_code", RulePostalUnknown, nil)
+				return v.err()
+			} else if err != nil {
+				return err
+			}
+		}
+	}
+
+	loc.PostalCode = code
+	if inAddress == "" {
+		loc.Address = strings.TrimRight(loc.Address, " ,") + ", " + code
+	}
+	return nil
+}
+
+// infer mencari kode pos unik untuk kecamatan lokasi ("" jika tidak bisa).
+func (s *PostcodeService) infer(ctx context.Context, area *models.AdminArea) string {
+	if area == nil || area.District == nil || area.Regency == nil {
+		return ""
+	}
+	matches, err := s.repo.ByDistrict(ctx, postcode.Key(area.District.Name), postcode.Key(area.Regency.Name))
+	if err != nil {
+		log.Println("postcode:", err)
+		return ""
+	}
+	if len(matches) != 1 {
+		return ""
+	}
+	return matches[0].Code
+}
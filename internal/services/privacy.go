@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/rbac"
+)
+
+// approximateCategories mengembalikan slug kategori yang mewajibkan
+// koordinat perkiraan.
+func (s *CategoryService) approximateCategories(ctx context.Context) (map[string]bool, error) {
+	categories, err := s.categories.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slugs := map[string]bool{}
+	for _, c := range categories {
+		if c.ApproximateCoordinates {
+			slugs[c.Slug] = true
+		}
+	}
+	return slugs, nil
+}
+
+// concealer mengembalikan fungsi yang menyamarkan koordinat lokasi yang
+// dipilih pembuatnya atau kategorinya untuk hanya tampil perkiraan, dan
+// bernilai true jika lokasi disamarkan. Pembuat lokasi dan moderator (di
+// dalam scope-nya) tetap melihat koordinat asli. Kategori dimuat sekali, jadi aman dipakai per
+// baris saat export.
+func concealer(ctx context.Context, categories *CategoryService, roles *RoleService, viewer models.User) (func(*models.Location) bool, error) {
+	approximate, err := categories.approximateCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	moderator := viewer.Email != "" && roles.Can(ctx, viewer.Role, rbac.LocationsModerate)
+	scope := roles.Scope(ctx, viewer.Role)
+	return func(loc *models.Location) bool {
+		if !loc.ApproximateCoordinates && !approximate[loc.Category] {
+			return false
+		}
+		if (moderator && scope.Contains(loc)) || (viewer.Email != "" && loc.CreatedBy == viewer.Email) {
+			// Tetap ditandai supaya pemilik tahu publik melihat perkiraan
+			loc.ApproximateCoordinates = true
+			return false
+		}
+		loc.Conceal()
+		return true
+	}, nil
+}
+
+// concealItems menyamarkan item map_view yang sensitif. map_view tidak
+// menyimpan pembuat lokasi, jadi daftar publik dan stream selalu memakai
+// koordinat perkiraan; koordinat asli dibaca lewat detail lokasi.
+func concealItems(ctx context.Context, categories *CategoryService, items []models.MapViewItem) error {
+	approximate, err := categories.approximateCategories(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		if items[i].ApproximateCoordinates || approximate[items[i].Category] {
+			items[i].Conceal()
+		}
+	}
+	return nil
+}
+
+func (s *LocationService) concealer(ctx context.Context, viewer models.User) (func(*models.Location) bool, error) {
+	return concealer(ctx, s.categories, s.roles, viewer)
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// maxPromoted membatasi slot promosi di atas halaman pertama supaya
+	// hasil organik tetap terlihat.
+	maxPromoted         = 3
+	maxPromotionSponsor = 200
+)
+
+// Impression dicatat per tanggal WIB, sama dengan kehadiran check-in
+var promotionZone = time.FixedZone("WIB", 7*60*60)
+
+// PromotionService mengelola lokasi pinned/sponsored: admin mengatur masa
+// promosi, daftar GET /locations menaikkannya ke halaman pertama, dan setiap
+// kali tampil di slot promosi dihitung sebagai impression untuk laporan ke
+// sponsor.
+type PromotionService struct {
+	locations   repositories.LocationRepository
+	impressions repositories.PromotionRepository
+	audit       *AuditService
+	events      *LocationEvents
+}
+
+// NewPromotionService membuat service dan berlangganan penghapusan
+// permanen lokasi untuk membersihkan impression-nya.
+func NewPromotionService(locations repositories.LocationRepository, impressions repositories.PromotionRepository,
+	audit *AuditService, events *LocationEvents) *PromotionService {
+	s := &PromotionService{locations: locations, impressions: impressions, audit: audit, events: events}
+	events.Subscribe(s.handle)
+	return s
+}
+
+func (s *PromotionService) handle(ctx context.Context, ev LocationChanged) {
+	if ev.Action != "location.purge" {
+		return
+	}
+	for _, id := range ev.IDs {
+		if _, err := s.impressions.DeleteByLocation(context.WithoutCancel(ctx), id); err != nil {
+			log.Println("hapus impression lokasi", id.Hex()+":", err)
+		}
+	}
+}
+
+func (s *PromotionService) find(ctx context.Context, id primitive.ObjectID) (*models.Location, error) {
+	loc, err := s.locations.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrLocationNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if loc.DeletedAt != nil {
+		return nil, ErrLocationNotFound
+	}
+	return loc, nil
+}
+
+// Set memasang atau mengganti promosi lokasi.
+func (s *PromotionService) Set(ctx context.Context, id primitive.ObjectID, in models.PromotionInput) (*models.Promotion, error) {
+	p := models.Promotion{
+		Type:     strings.TrimSpace(in.Type),
+		Sponsor:  strings.TrimSpace(in.Sponsor),
+		StartsAt: time.Now().UTC().Truncate(time.Millisecond),
+		EndsAt:   in.EndsAt.UTC().Truncate(time.Millisecond),
+	}
+	if in.StartsAt != nil {
+		p.StartsAt = in.StartsAt.UTC().Truncate(time.Millisecond)
+	}
+	var v validator
+	v.oneOf("type", p.Type, models.PromotionTypes)
+	if p.Type == models.PromotionSponsored {
+		v.required(p.Sponsor != "", "sponsor")
+	}
+	v.maxLength("sponsor", p.Sponsor, maxPromotionSponsor)
+	if in.EndsAt.IsZero() {
+		v.required(false, "ends_at")
+	} else {
+		v.check(p.EndsAt.After(p.StartsAt), "ends_at", RuleAfter, Params{"field": "starts_at"})
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	existing, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.locations.Update(ctx, id, repositories.Fields{"promotion": p, "updated_at": time.Now().UTC()}); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "location.promote", ResourceType: AuditLocation, ResourceID: id.Hex(), Before: existing.Promotion, After: p})
+	s.events.Publish(ctx, "location.update", id)
+	return &p, nil
+}
+
+// Clear menghentikan promosi lokasi. Impression yang sudah tercatat tetap
+// disimpan untuk laporan.
+func (s *PromotionService) Clear(ctx context.Context, id primitive.ObjectID) error {
+	existing, err := s.find(ctx, id)
+	if err != nil {
+		return err
+	}
+	if existing.Promotion == nil {
+		return nil
+	}
+	if err := s.locations.Update(ctx, id, repositories.Fields{"promotion": nil, "updated_at": time.Now().UTC()}); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "location.unpromote", ResourceType: AuditLocation, ResourceID: id.Hex(), Before: existing.Promotion})
+	s.events.Publish(ctx, "location.update", id)
+	return nil
+}
+
+// Report menjumlahkan impression lokasi pada rentang tanggal from..to
+// (YYYY-MM-DD, inklusif, boleh kosong).
+func (s *PromotionService) Report(ctx context.Context, id primitive.ObjectID, from, to string) (*models.PromotionReport, error) {
+	var v validator
+	for field, date := range map[string]string{"from": from, "to": to} {
+		if date != "" {
+			_, err := time.Parse("2006-01-02", date)
+			v.check(err == nil, field, RuleType, Params{"type": "date (YYYY-MM-DD)"})
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	loc, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	days, err := s.impressions.Impressions(ctx, id, from, to)
+	if err != nil {
+		return nil, err
+	}
+	report := &models.PromotionReport{LocationID: id, Promotion: loc.Promotion, ByDay: days}
+	for _, d := range days {
+		report.Impressions += d.Count
+	}
+	return report, nil
+}
+
+// boost mengembalikan lokasi yang sedang dipromosikan dan cocok dengan
+// filter q (pinned lebih dulu), lalu mencatat impression-nya bila record.
+// Kegagalan mencatat hanya di-log supaya daftar publik tetap tampil.
+func (s *PromotionService) boost(ctx context.Context, q repositories.LocationQuery, record bool) ([]models.MapViewItem, error) {
+	now := time.Now().UTC()
+	q.PromotedAt = now
+	q.ListPage = repositories.ListPage{Field: "name", Limit: maxPromoted}
+	locations, _, err := s.locations.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(locations, func(a, b models.Location) int {
+		return promotionRank(a.Promotion) - promotionRank(b.Promotion)
+	})
+	items := make([]models.MapViewItem, len(locations))
+	ids := make([]primitive.ObjectID, len(locations))
+	for i, loc := range locations {
+		items[i] = mapViewItem(loc)
+		items[i].Promotion = loc.Promotion
+		ids[i] = loc.ID
+	}
+	if !record {
+		return items, nil
+	}
+	if err := s.impressions.AddImpressions(context.WithoutCancel(ctx), now.In(promotionZone).Format("2006-01-02"), ids); err != nil {
+		log.Println("catat impression promosi:", err)
+	}
+	return items, nil
+}
+
+func promotionRank(p *models.Promotion) int {
+	if p != nil && p.Type == models.PromotionPinned {
+		return 0
+	}
+	return 1
+}
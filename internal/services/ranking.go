@@ -0,0 +1,125 @@
+package services
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+// Nama sort GET /locations untuk skor relevansi; default jika ?q diisi
+const SortRelevance = "relevance"
+
+// Kandidat terbaru yang diberi skor per pencarian. Hasil di luar batas ini
+// tidak ikut diurutkan, jadi total dibatasi ke jumlah kandidat.
+const maxRankCandidates = 500
+
+// Ulasan "semu" bernilai 0 yang meredam rating lokasi dengan sedikit
+// ulasan, supaya satu ulasan bintang 5 tidak mengalahkan ratusan bintang 4
+const ratingDamping = 2
+
+// rankInput adalah data satu lokasi yang dinilai. coords adalah koordinat
+// yang dilihat client (perkiraan untuk lokasi sensitif) supaya skor jarak
+// tidak membocorkan koordinat asli.
+type rankInput struct {
+	loc      *models.Location
+	coords   models.Coordinates
+	query    string
+	ref      *models.Coordinates
+	now      time.Time
+	halfLife time.Duration
+}
+
+// rankFactors adalah pipeline skor relevansi, urut seperti di explain.
+// Faktor baru cukup ditambahkan di sini beserta bobotnya di
+// models.RankingWeights. value mengembalikan nilai 0-1 dan false jika
+// faktor tidak berlaku untuk pencarian ini (mis. jarak tanpa ?lat=&lng=).
+var rankFactors = []struct {
+	name   string
+	weight func(models.RankingWeights) float64
+	value  func(models.RankingWeights, rankInput) (float64, bool)
+}{
+	{"text", func(w models.RankingWeights) float64 { return w.Text }, func(_ models.RankingWeights, in rankInput) (float64, bool) {
+		return textRelevance(in.query, in.loc.Name, in.loc.Address), in.query != ""
+	}},
+	{"distance", func(w models.RankingWeights) float64 { return w.Distance }, func(w models.RankingWeights, in rankInput) (float64, bool) {
+		if in.ref == nil {
+			return 0, false
+		}
+		scale := w.DistanceScaleM
+		if scale <= 0 {
+			scale = defaultRankingWeights.DistanceScaleM
+		}
+		return scale / (scale + in.ref.DistanceM(in.coords)), true
+	}},
+	{"rating", func(w models.RankingWeights) float64 { return w.Rating }, func(_ models.RankingWeights, in rankInput) (float64, bool) {
+		r := in.loc.Rating
+		if r == nil || r.Count == 0 {
+			return 0, true
+		}
+		n := float64(r.Count)
+		return r.Average / 5 * n / (n + ratingDamping), true
+	}},
+	{"freshness", func(w models.RankingWeights) float64 { return w.Freshness }, func(_ models.RankingWeights, in rankInput) (float64, bool) {
+		return in.loc.FreshnessAt(in.now, in.halfLife), true
+	}},
+	{"verified", func(w models.RankingWeights) float64 { return w.Verified }, func(_ models.RankingWeights, in rankInput) (float64, bool) {
+		if in.loc.Verified {
+			return 1, true
+		}
+		return 0, true
+	}},
+}
+
+// rank menghitung skor relevansi in beserta rinciannya.
+func rank(w models.RankingWeights, in rankInput) models.RankingExplain {
+	explain := models.RankingExplain{Factors: []models.RankingFactor{}}
+	for _, f := range rankFactors {
+		weight := f.weight(w)
+		value, ok := f.value(w, in)
+		if !ok || weight == 0 {
+			continue
+		}
+		factor := models.RankingFactor{Name: f.name, Value: round3(value), Weight: weight, Score: round3(weight * value)}
+		explain.Factors = append(explain.Factors, factor)
+		explain.Score += weight * value
+	}
+	explain.Score = round3(explain.Score)
+	return explain
+}
+
+// textRelevance menilai kecocokan q dengan nama lalu alamat: nama sama
+// persis 1, nama diawali q 0,8, nama memuat q 0,6, sebagian kata q ada di
+// nama sampai 0,5, alamat memuat q 0,3.
+func textRelevance(q, name, address string) float64 {
+	q, name, address = strings.ToLower(strings.TrimSpace(q)), strings.ToLower(name), strings.ToLower(address)
+	switch {
+	case q == "":
+		return 0
+	case name == q:
+		return 1
+	case strings.HasPrefix(name, q):
+		return 0.8
+	case strings.Contains(name, q):
+		return 0.6
+	}
+	var score float64
+	if words := strings.Fields(q); len(words) > 1 {
+		found := 0
+		for _, word := range words {
+			if strings.Contains(name, word) {
+				found++
+			}
+		}
+		score = 0.5 * float64(found) / float64(len(words))
+	}
+	if strings.Contains(address, q) {
+		score = max(score, 0.3)
+	}
+	return score
+}
+
+func round3(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
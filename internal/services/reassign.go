@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultReassignBatch = 200
+	maxReassignBatch     = 1000
+)
+
+// ReassignService memindahkan pemilik (created_by) banyak lokasi sekaligus,
+// mis. setelah reorganisasi dinas. Sama seperti campaign, tidak ada worker
+// terpisah: POST /admin/jobs/location-reassign memproses satu batch dari
+// job tertua dan dipanggil berkala oleh cron sampai queued = 0.
+type ReassignService struct {
+	jobs       repositories.ReassignmentRepository
+	locations  repositories.LocationRepository
+	users      repositories.UserRepository
+	regions    repositories.RegionRepository
+	categories *CategoryService
+	roles      *RoleService
+	audit      *AuditService
+	// Opsional; nil berarti perubahan tidak diteruskan ke map_view
+	events *LocationEvents
+}
+
+func NewReassignService(jobs repositories.ReassignmentRepository, locations repositories.LocationRepository,
+	users repositories.UserRepository, regions repositories.RegionRepository, categories *CategoryService,
+	roles *RoleService, audit *AuditService, events *LocationEvents) *ReassignService {
+	return &ReassignService{jobs: jobs, locations: locations, users: users, regions: regions,
+		categories: categories, roles: roles, audit: audit, events: events}
+}
+
+// resolveFilter memvalidasi filter dan mengisi level wilayah.
+func (s *ReassignService) resolveFilter(ctx context.Context, in *models.ReassignInput) error {
+	f := &in.Filter
+	f.CreatedBy = strings.ToLower(strings.TrimSpace(f.CreatedBy))
+	f.Region = strings.TrimSpace(f.Region)
+	f.RegionLevel = ""
+	in.Target = strings.ToLower(strings.TrimSpace(in.Target))
+	var v validator
+	v.email("target", in.Target)
+	v.check(f.CreatedBy != "" || f.Category != "" || f.Region != "", "filter", RuleRequireOne, Params{"fields": []string{"created_by", "category", "region"}})
+	v.check(f.CreatedBy == "" || f.CreatedBy != in.Target, "target", RuleNotEqual, Params{"field": "filter.created_by"})
+	if err := v.err(); err != nil {
+		return err
+	}
+	if _, err := s.users.FindByEmail(ctx, in.Target); errors.Is(err, repositories.ErrNotFound) {
+		return invalid("User target %s tidak terdaftar", in.Target)
+	} else if err != nil {
+		return err
+	}
+	if f.Category != "" {
+		c, err := s.categories.Get(ctx, f.Category)
+		if errors.Is(err, ErrCategoryNotFound) {
+			return invalid("Kategori %s tidak ada", f.Category)
+		} else if err != nil {
+			return err
+		}
+		f.Category = c.Slug
+	}
+	if f.Region != "" {
+		region, err := s.regions.Get(ctx, f.Region)
+		if errors.Is(err, repositories.ErrNotFound) {
+			return invalid("Wilayah %s tidak ada", f.Region)
+		} else if err != nil {
+			return err
+		}
+		f.RegionLevel = region.Level
+	}
+	return nil
+}
+
+// Start membuat job untuk semua lokasi yang cocok dengan filter saat ini
+// dan berada di dalam scope role u. Lokasi di arsip tidak ikut dipindah.
+func (s *ReassignService) Start(ctx context.Context, u models.User, in models.ReassignInput) (*models.Reassignment, error) {
+	if err := s.resolveFilter(ctx, &in); err != nil {
+		return nil, err
+	}
+	// Scope disimpan di job karena batch diproses belakangan tanpa user
+	in.Filter.Scope = s.roles.Scope(ctx, u.Role)
+	matched, err := s.locations.CountReassignCandidates(ctx, in.Filter, in.Target, primitive.NilObjectID)
+	if err != nil {
+		return nil, err
+	}
+	if matched == 0 {
+		return nil, invalid("Tidak ada lokasi yang cocok dengan filter")
+	}
+	job := models.Reassignment{
+		Filter:    in.Filter,
+		Target:    in.Target,
+		Status:    models.ReassignQueued,
+		Matched:   matched,
+		CreatedBy: u.Email,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.jobs.Create(ctx, &job); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "reassignment.create", ResourceType: AuditReassignment, ResourceID: job.ID.Hex(), After: job})
+	return &job, nil
+}
+
+func (s *ReassignService) Get(ctx context.Context, id primitive.ObjectID) (*models.Reassignment, error) {
+	job, err := s.jobs.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrReassignmentNotFound
+	}
+	return job, err
+}
+
+func (s *ReassignService) List(ctx context.Context, opts models.ListOptions) ([]models.Reassignment, models.PageMeta, error) {
+	p, err := listPage(repositories.ReassignmentList, opts)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	jobs, total, err := s.jobs.List(ctx, p)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	meta := repositories.Meta(p, total, jobs)
+	return jobs, meta, nil
+}
+
+// Process memindahkan satu batch lokasi dari job queued tertua. Setiap
+// lokasi dicatat di audit log sebagai location.reassign.
+func (s *ReassignService) Process(ctx context.Context, batch int) (models.ReassignResult, error) {
+	var result models.ReassignResult
+	if batch <= 0 {
+		batch = defaultReassignBatch
+	}
+	if batch > maxReassignBatch {
+		batch = maxReassignBatch
+	}
+	job, err := s.jobs.NextQueued(ctx)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return result, nil
+	} else if err != nil {
+		return result, err
+	}
+	result.JobID = &job.ID
+	locations, err := s.locations.ReassignCandidates(ctx, job.Filter, job.Target, job.Cursor, int64(batch))
+	if err != nil {
+		return result, err
+	}
+	now := time.Now().UTC()
+	events := make([]AuditEvent, 0, len(locations))
+	ids := make([]primitive.ObjectID, 0, len(locations))
+	for _, loc := range locations {
+		if err := s.locations.Update(ctx, loc.ID, repositories.Fields{"created_by": job.Target, "updated_at": now}); err != nil {
+			return result, err
+		}
+		events = append(events, AuditEvent{Action: "location.reassign", ResourceType: AuditLocation, ResourceID: loc.ID.Hex(),
+			Before: map[string]string{"created_by": loc.CreatedBy},
+			After:  map[string]string{"created_by": job.Target, "job_id": job.ID.Hex()}})
+		ids = append(ids, loc.ID)
+	}
+	s.audit.Record(ctx, events...)
+	s.events.Publish(ctx, "location.update", ids...)
+	result.Reassigned = len(locations)
+	if len(locations) > 0 {
+		// Jika dua panggilan memproses batch yang sama, nilai yang ditulis
+		// sama dan hanya satu yang memajukan cursor, jadi counter tidak dobel
+		err := s.jobs.Advance(ctx, job.ID, job.Cursor, locations[len(locations)-1].ID, int64(len(locations)))
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return result, err
+		}
+	}
+	if len(locations) < batch {
+		if err := s.jobs.Complete(ctx, job.ID, now); err == nil {
+			after, _ := s.jobs.FindByID(ctx, job.ID)
+			s.audit.Record(ctx, AuditEvent{Action: "reassignment.done", ResourceType: AuditReassignment, ResourceID: job.ID.Hex(), Before: job, After: after})
+		} else if !errors.Is(err, repositories.ErrNotFound) {
+			return result, err
+		}
+	}
+	result.Queued, err = s.jobs.CountQueued(ctx)
+	return result, err
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/rbac"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Batas rantai redirect yang diikuti Resolve
+const maxRedirectHops = 5
+
+// Merge menggabungkan lokasi duplikat id ke lokasi into: lokasi id masuk
+// trash dan ID-nya dialihkan permanen ke into, termasuk redirect lama yang
+// menuju id. Ulasan dan foto tetap pada lokasi lama (ikut terhapus saat
+// purge); redirect tetap berlaku setelah purge. Permission dicek di route;
+// kedua lokasi harus berada di dalam scope role u.
+func (s *LocationService) Merge(ctx context.Context, u models.User, id primitive.ObjectID, in models.MergeInput) (*models.LocationRedirect, error) {
+	if s.opts.Redirects == nil {
+		return nil, invalid("Redirect lokasi belum dikonfigurasi")
+	}
+	into, err := primitive.ObjectIDFromHex(in.Into)
+	if err != nil {
+		return nil, invalid("into harus berisi ID lokasi yang valid")
+	}
+	if into == id {
+		return nil, invalid("Lokasi tidak bisa digabung ke dirinya sendiri")
+	}
+	source, err := s.locations.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrLocationNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	target, err := s.locations.FindByID(ctx, into)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrLocationNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if !s.roles.CanModerate(ctx, u.Role, rbac.LocationsModerate, source) || !s.roles.CanModerate(ctx, u.Role, rbac.LocationsModerate, target) {
+		return nil, ErrForbidden
+	}
+	// Link lama akan dibuka publik; tujuan yang belum tayang berarti 404
+	if !target.Listed() {
+		return nil, invalid("Lokasi tujuan belum disetujui moderator")
+	}
+	// Redirect ditulis sebelum soft delete: selama lokasi lama masih ada,
+	// redirect-nya tidak dipakai, jadi merge yang gagal di tengah tidak
+	// pernah meninggalkan link lama yang 404
+	now := time.Now().UTC()
+	red := models.LocationRedirect{From: id, To: into, Reason: models.RedirectMerged, CreatedBy: u.Email, CreatedAt: now}
+	if err := s.opts.Redirects.Put(ctx, &red); err != nil {
+		return nil, err
+	}
+	if err := s.locations.SoftDelete(ctx, id, u.Email, now); err != nil {
+		if derr := s.opts.Redirects.Delete(ctx, id); derr != nil {
+			log.Println("batalkan redirect merge", id.Hex()+":", derr)
+		}
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrLocationNotFound
+		}
+		return nil, err
+	}
+	// Jika gagal, rantai redirect lama tetap diikuti Resolve
+	if _, err := s.opts.Redirects.Retarget(ctx, id, into); err != nil {
+		log.Println("arahkan ulang redirect ke", id.Hex()+":", err)
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "location.merge", ResourceType: AuditLocation, ResourceID: id.Hex(), Before: source, After: red})
+	s.opts.Events.Publish(ctx, "location.delete", id)
+	return &red, nil
+}
+
+// Resolve seperti Get, tetapi ID yang sudah dialihkan (mis. lokasi yang
+// digabung) diikuti ke lokasi tujuannya. RedirectInfo nil jika id masih
+// berlaku. Merge biasanya tidak meninggalkan rantai, tetapi rantai pendek
+// (Retarget yang gagal) tetap diikuti sampai maxRedirectHops.
+func (s *LocationService) Resolve(ctx context.Context, viewer models.User, id primitive.ObjectID, includeArchived bool) (*models.Location, *models.RedirectInfo, error) {
+	loc, err := s.Get(ctx, viewer, id, includeArchived)
+	if !errors.Is(err, ErrLocationNotFound) || s.opts.Redirects == nil {
+		return loc, nil, err
+	}
+	var info *models.RedirectInfo
+	to := id
+	for hop := 0; hop < maxRedirectHops && errors.Is(err, ErrLocationNotFound); hop++ {
+		red, rerr := s.opts.Redirects.Find(ctx, to)
+		if errors.Is(rerr, repositories.ErrNotFound) {
+			break
+		} else if rerr != nil {
+			return nil, nil, rerr
+		}
+		if info == nil {
+			info = &models.RedirectInfo{From: id, Reason: red.Reason, Status: http.StatusMovedPermanently}
+		}
+		to = red.To
+		loc, err = s.Get(ctx, viewer, to, includeArchived)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	info.To = to
+	return loc, info, nil
+}
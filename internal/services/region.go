@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+
+	"InfoCuy-Backend/internal/boundary"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+type RegionService struct {
+	regions   repositories.RegionRepository
+	locations repositories.LocationRepository
+	audit     *AuditService
+}
+
+func NewRegionService(regions repositories.RegionRepository, locations repositories.LocationRepository, audit *AuditService) *RegionService {
+	return &RegionService{regions: regions, locations: locations, audit: audit}
+}
+
+// Import menyimpan batas wilayah satu level dari GeoJSON lalu menandai
+// lokasi yang sudah ada di dalam setiap wilayah. Wilayah dengan kode yang
+// sama diganti, jadi import bisa diulang saat data batas diperbarui.
+func (s *RegionService) Import(ctx context.Context, level string, r io.Reader) (models.RegionImportResult, error) {
+	result := models.RegionImportResult{Level: level, Errors: []models.ImportRowError{}}
+	if !boundary.ValidLevel(level) {
+		return result, invalid("level harus provinsi, kabupaten atau kecamatan")
+	}
+	fail := func(row int, msg string) {
+		result.Failed++
+		result.Errors = append(result.Errors, models.ImportRowError{Row: row, Error: msg})
+	}
+	err := boundary.Read(r, level, func(row boundary.Row) error {
+		result.Total++
+		if row.Err != nil {
+			fail(row.Row, row.Err.Error())
+			return nil
+		}
+		// Geometry yang ditolak index 2dsphere (mis. self-intersecting)
+		// hanya menggagalkan baris tersebut
+		if err := s.regions.Save(ctx, row.Region); err != nil {
+			fail(row.Row, err.Error())
+			return ctx.Err()
+		}
+		result.Imported++
+		ref := models.RegionRef{Code: row.Region.Code, Name: row.Region.Name}
+		tagged, err := s.locations.TagAdminArea(ctx, level, ref, row.Region.Geometry)
+		if err != nil {
+			fail(row.Row, "gagal menandai lokasi: "+err.Error())
+			return ctx.Err()
+		}
+		result.Tagged += tagged
+		return nil
+	})
+	if errors.Is(err, boundary.ErrInvalidFile) {
+		return result, invalid("%s", err.Error())
+	}
+	if err != nil {
+		return result, err
+	}
+	if result.Total == 0 {
+		return result, invalid("File tidak berisi wilayah")
+	}
+	summary := result
+	summary.Errors = nil
+	s.audit.Record(ctx, AuditEvent{Action: "region.import", ResourceType: AuditRegion, ResourceID: level, After: summary})
+	return result, nil
+}
+
+func (s *RegionService) List(ctx context.Context, level, parentCode string) ([]models.Region, error) {
+	if level != "" && !boundary.ValidLevel(level) {
+		return nil, invalid("level harus provinsi, kabupaten atau kecamatan")
+	}
+	return s.regions.List(ctx, level, parentCode)
+}
+
+// Stats menghitung lokasi di wilayah code, per kategori dan per wilayah
+// satu level di bawahnya.
+func (s *RegionService) Stats(ctx context.Context, code string) (*models.RegionStats, error) {
+	region, err := s.regions.Get(ctx, code)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrRegionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var childLevel string
+	for i, level := range models.RegionLevels {
+		if level == region.Level && i+1 < len(models.RegionLevels) {
+			childLevel = models.RegionLevels[i+1]
+		}
+	}
+	stats, err := s.locations.RegionStats(ctx, region.Level, region.Code, childLevel)
+	if err != nil {
+		return nil, err
+	}
+	stats.Region = *region
+	return &stats, nil
+}
+
+// AdminArea mencari wilayah yang memuat titik c. Mengembalikan nil jika
+// belum ada batas wilayah yang di-import atau lookup gagal (hanya dicatat).
+func (s *RegionService) AdminArea(ctx context.Context, c models.Coordinates) *models.AdminArea {
+	regions, err := s.regions.Containing(ctx, c)
+	if err != nil {
+		log.Println("admin area:", err)
+		return nil
+	}
+	if len(regions) == 0 {
+		return nil
+	}
+	area := &models.AdminArea{}
+	for _, r := range regions {
+		area.Set(r.Level, models.RegionRef{Code: r.Code, Name: r.Name})
+	}
+	return area
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/audit"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/rbac"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	minRating           = 1
+	maxRating           = 5
+	maxReviewCommentLen = 2000
+)
+
+type ReviewService struct {
+	reviews   repositories.ReviewRepository
+	locations repositories.LocationRepository
+	roles     *RoleService
+	audit     *AuditService
+	// Rating lokasi ikut berubah; nil = tidak diteruskan
+	events *LocationEvents
+}
+
+func NewReviewService(reviews repositories.ReviewRepository, locations repositories.LocationRepository, roles *RoleService,
+	audit *AuditService, events *LocationEvents) *ReviewService {
+	return &ReviewService{reviews: reviews, locations: locations, roles: roles, audit: audit, events: events}
+}
+
+// Create menyimpan ulasan u untuk lokasi. Satu user hanya boleh mengulas
+// satu lokasi sekali. Ulasan yang memicu aturan spam (lihat spamFlags)
+// disimpan pending dengan SpamFlags dan baru tayang setelah disetujui
+// moderator.
+func (s *ReviewService) Create(ctx context.Context, u models.User, locationID primitive.ObjectID, in models.ReviewInput) (*models.Review, error) {
+	in.Comment = strings.TrimSpace(in.Comment)
+	var v validator
+	v.check(in.Rating >= minRating && in.Rating <= maxRating, "rating", RuleRange, Params{"min": minRating, "max": maxRating})
+	v.maxLength("comment", in.Comment, maxReviewCommentLen)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	loc, err := s.locations.FindByID(ctx, locationID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrLocationNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	rev := &models.Review{
+		ID:         primitive.NewObjectID(),
+		LocationID: locationID,
+		UserID:     u.ID,
+		CreatedBy:  u.Email,
+		Rating:     in.Rating,
+		Comment:    in.Comment,
+		CreatedAt:  time.Now().UTC(),
+		IP:         audit.ActorFrom(ctx).IP,
+	}
+	// Moderator tidak melewati aturan spam
+	if !s.roles.CanModerate(ctx, u.Role, rbac.ReviewsModerate, loc) {
+		flags, err := s.spamFlags(ctx, loc, rev)
+		if err != nil {
+			return nil, err
+		}
+		if len(flags) > 0 {
+			rev.ModerationStatus = models.ModerationPending
+			rev.SpamFlags = flags
+		}
+	}
+	if err := s.reviews.Create(ctx, rev); errors.Is(err, repositories.ErrDuplicate) {
+		return nil, ErrAlreadyReviewed
+	} else if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "review.create", ResourceType: AuditReview, ResourceID: rev.ID.Hex(), After: rev})
+	if rev.Listed() {
+		s.events.Publish(ctx, "review.create", locationID)
+	}
+	return rev, nil
+}
+
+// List mengembalikan satu halaman ulasan (terbaru dulu) beserta ringkasan
+// rating lokasi.
+func (s *ReviewService) List(ctx context.Context, locationID primitive.ObjectID, opts models.ListOptions) ([]models.Review, models.PageMeta, models.RatingSummary, error) {
+	var summary models.RatingSummary
+	if _, err := s.locations.FindByID(ctx, locationID); errors.Is(err, repositories.ErrNotFound) {
+		return nil, models.PageMeta{}, summary, ErrLocationNotFound
+	} else if err != nil {
+		return nil, models.PageMeta{}, summary, err
+	}
+	p, err := listPage(repositories.ReviewList, opts)
+	if err != nil {
+		return nil, models.PageMeta{}, summary, err
+	}
+	reviews, total, err := s.reviews.List(ctx, locationID, p)
+	if err != nil {
+		return nil, models.PageMeta{}, summary, err
+	}
+	if summary, err = s.reviews.Summary(ctx, locationID); err != nil {
+		return nil, models.PageMeta{}, summary, err
+	}
+	meta := repositories.Meta(p, total, reviews)
+	return reviews, meta, summary, nil
+}
+
+// Delete menghapus ulasan milik u, atau milik siapa saja jika u memiliki
+// permission reviews:moderate untuk lokasi ulasan.
+func (s *ReviewService) Delete(ctx context.Context, u models.User, locationID, reviewID primitive.ObjectID) (*models.Review, error) {
+	rev, err := s.reviews.FindByID(ctx, reviewID)
+	if errors.Is(err, repositories.ErrNotFound) || (err == nil && rev.LocationID != locationID) {
+		return nil, ErrReviewNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if rev.UserID != u.ID && !s.canModerate(ctx, u, rev) {
+		return nil, ErrForbidden
+	}
+	if err := s.reviews.Delete(ctx, reviewID); errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrReviewNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "review.delete", ResourceType: AuditReview, ResourceID: reviewID.Hex(), Before: rev})
+	s.events.Publish(ctx, "review.delete", locationID)
+	return rev, nil
+}
+
+// Pending mengembalikan antrean moderasi ulasan untuk lokasi di dalam
+// scope u, yang paling lama dulu, beserta SpamFlags setiap ulasan.
+func (s *ReviewService) Pending(ctx context.Context, u models.User, opts models.ListOptions) ([]models.Review, models.PageMeta, error) {
+	p, err := listPage(repositories.PendingReviewList, opts)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	var locationIDs []primitive.ObjectID
+	if scope := s.roles.Scope(ctx, u.Role); scope != nil {
+		locationIDs = []primitive.ObjectID{}
+		err := s.locations.Each(ctx, repositories.LocationQuery{Scope: scope}, func(loc models.Location) error {
+			locationIDs = append(locationIDs, loc.ID)
+			return nil
+		})
+		if err != nil {
+			return nil, models.PageMeta{}, err
+		}
+	}
+	reviews, total, err := s.reviews.Pending(ctx, locationIDs, p)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	meta := repositories.Meta(p, total, reviews)
+	return reviews, meta, nil
+}
+
+// Approve menayangkan ulasan yang ditahan (atau pernah ditolak) sehingga
+// ikut rata-rata rating lokasi.
+func (s *ReviewService) Approve(ctx context.Context, u models.User, id primitive.ObjectID) (*models.Review, error) {
+	rev, before, err := s.moderate(ctx, u, id, []string{models.ModerationPending, models.ModerationRejected}, models.ModerationApproved)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "review.approve", ResourceType: AuditReview, ResourceID: id.Hex(), Before: before, After: rev})
+	s.events.Publish(ctx, "review.approve", rev.LocationID)
+	return rev, nil
+}
+
+// Reject menolak ulasan yang ditahan. Ulasan tetap disimpan (tidak tayang)
+// sehingga user yang sama tidak bisa langsung mengirim ulang.
+func (s *ReviewService) Reject(ctx context.Context, u models.User, id primitive.ObjectID) (*models.Review, error) {
+	rev, before, err := s.moderate(ctx, u, id, []string{models.ModerationPending}, models.ModerationRejected)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "review.reject", ResourceType: AuditReview, ResourceID: id.Hex(), Before: before, After: rev})
+	return rev, nil
+}
+
+// canModerate bernilai true jika u boleh memoderasi rev: memiliki
+// permission reviews:moderate dan lokasi ulasan berada di dalam scope u.
+func (s *ReviewService) canModerate(ctx context.Context, u models.User, rev *models.Review) bool {
+	if !s.roles.Can(ctx, u.Role, rbac.ReviewsModerate) {
+		return false
+	}
+	if s.roles.Scope(ctx, u.Role) == nil {
+		return true
+	}
+	loc, err := s.locations.FindByID(ctx, rev.LocationID)
+	return err == nil && s.roles.CanModerate(ctx, u.Role, rbac.ReviewsModerate, loc)
+}
+
+// moderate mengubah status ulasan jika status saat ini salah satu dari
+// from; ErrReviewModerated jika tidak, ErrForbidden jika lokasinya di luar
+// scope u.
+func (s *ReviewService) moderate(ctx context.Context, u models.User, id primitive.ObjectID, from []string, status string) (*models.Review, *models.Review, error) {
+	before, err := s.reviews.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, nil, ErrReviewNotFound
+	} else if err != nil {
+		return nil, nil, err
+	}
+	if !s.canModerate(ctx, u, before) {
+		return nil, nil, ErrForbidden
+	}
+	rev, err := s.reviews.Moderate(ctx, id, from, repositories.Fields{
+		"moderation_status": status,
+		"moderated_by":      u.Email,
+		"moderated_at":      time.Now().UTC(),
+	})
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, nil, ErrReviewModerated
+	} else if err != nil {
+		return nil, nil, err
+	}
+	return rev, before, nil
+}
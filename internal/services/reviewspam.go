@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+// Aturan spam ulasan. Ulasan yang memicu salah satu aturan tidak ditolak,
+// hanya ditahan untuk moderator.
+const (
+	// Ulasan ke-(N+1) dari akun atau IP yang sama dalam jendela ini ditahan.
+	// Batas IP lebih longgar karena satu IP bisa dipakai banyak orang (NAT
+	// kampus, kantor, operator seluler).
+	reviewBurstWindow  = time.Hour
+	reviewAccountBurst = 5
+	reviewIPBurst      = 10
+	// Teks dibandingkan dengan ulasan lain dalam jendela ini
+	reviewDuplicateWindow = 7 * 24 * time.Hour
+	// Kemiripan trigram (Jaccard) minimal untuk dianggap duplikat; teks
+	// yang lebih pendek dari reviewDuplicateMinLen tidak diperiksa karena
+	// ulasan singkat seperti "mantap" wajar sama
+	reviewSimilarity      = 0.85
+	reviewDuplicateMinLen = 20
+	// Ulasan terbaru yang diperiksa per ulasan baru
+	reviewSpamScan = 1000
+)
+
+// spamFlags menjalankan aturan spam untuk rev yang akan dibuat di loc.
+func (s *ReviewService) spamFlags(ctx context.Context, loc *models.Location, rev *models.Review) ([]models.ReviewFlag, error) {
+	var flags []models.ReviewFlag
+	if loc.CreatedBy == rev.CreatedBy {
+		flags = append(flags, models.ReviewFlag{Code: models.ReviewFlagSelfReview, Detail: "Pembuat lokasi mengulas lokasinya sendiri"})
+	}
+	recent, err := s.reviews.Recent(ctx, rev.CreatedAt.Add(-reviewDuplicateWindow), reviewSpamScan)
+	if err != nil {
+		return nil, err
+	}
+	burstSince := rev.CreatedAt.Add(-reviewBurstWindow)
+	var byAccount, byIP int
+	for _, r := range recent {
+		if !r.CreatedAt.After(burstSince) {
+			continue
+		}
+		if r.UserID == rev.UserID {
+			byAccount++
+		}
+		if rev.IP != "" && r.IP == rev.IP {
+			byIP++
+		}
+	}
+	minutes := int(reviewBurstWindow.Minutes())
+	if byAccount >= reviewAccountBurst {
+		flags = append(flags, models.ReviewFlag{Code: models.ReviewFlagAccountBurst,
+			Detail: fmt.Sprintf("%d ulasan dari akun ini dalam %d menit terakhir", byAccount+1, minutes)})
+	}
+	if byIP >= reviewIPBurst {
+		flags = append(flags, models.ReviewFlag{Code: models.ReviewFlagIPBurst,
+			Detail: fmt.Sprintf("%d ulasan dari IP ini dalam %d menit terakhir", byIP+1, minutes)})
+	}
+	if text := normalizeReviewText(rev.Comment); len([]rune(text)) >= reviewDuplicateMinLen {
+		grams := trigrams(text)
+		for _, r := range recent {
+			other := normalizeReviewText(r.Comment)
+			if len([]rune(other)) < reviewDuplicateMinLen {
+				continue
+			}
+			if score := jaccard(grams, trigrams(other)); score >= reviewSimilarity {
+				id := r.ID
+				flags = append(flags, models.ReviewFlag{Code: models.ReviewFlagDuplicate,
+					Detail: fmt.Sprintf("Teks %.0f%% mirip dengan ulasan lain", score*100), SimilarTo: &id})
+				break
+			}
+		}
+	}
+	return flags, nil
+}
+
+// normalizeReviewText menyisakan huruf dan angka (huruf kecil) dipisah satu
+// spasi, supaya beda tanda baca, kapital atau spasi tidak mengelabui
+// pemeriksaan duplikat.
+func normalizeReviewText(text string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+		} else {
+			space = true
+		}
+	}
+	return b.String()
+}
+
+func trigrams(text string) map[string]struct{} {
+	runes := []rune(text)
+	grams := make(map[string]struct{}, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		grams[string(runes[i:i+3])] = struct{}{}
+	}
+	return grams
+}
+
+func jaccard(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for g := range a {
+		if _, ok := b[g]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/rbac"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+// Role di-cache sebentar supaya cek permission tidak query DB tiap request
+const roleCacheTTL = 30 * time.Second
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,31}$`)
+
+type RoleService struct {
+	roles repositories.RoleRepository
+	users repositories.UserRepository
+	// Untuk memvalidasi scope role
+	regions    repositories.RegionRepository
+	categories repositories.CategoryRepository
+	audit      *AuditService
+
+	mu       sync.Mutex
+	cache    map[string]models.Role
+	loadedAt time.Time
+}
+
+func NewRoleService(roles repositories.RoleRepository, users repositories.UserRepository, regions repositories.RegionRepository,
+	categories repositories.CategoryRepository, audit *AuditService) *RoleService {
+	return &RoleService{roles: roles, users: users, regions: regions, categories: categories, audit: audit}
+}
+
+// EnsureDefaults membuat role bawaan (admin, contributor, user) jika belum ada.
+func (s *RoleService) EnsureDefaults(ctx context.Context) error {
+	var defaults []models.Role
+	for name, perms := range rbac.DefaultRoles {
+		defaults = append(defaults, models.Role{Name: name, Permissions: perms})
+	}
+	return s.roles.EnsureDefaults(ctx, defaults)
+}
+
+// all mengembalikan peta nama -> role dari cache. Jika DB tidak bisa
+// dibaca, role bawaan dipakai supaya admin tidak terkunci.
+func (s *RoleService) all(ctx context.Context) map[string]models.Role {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cache != nil && time.Since(s.loadedAt) < roleCacheTTL {
+		return s.cache
+	}
+	roles, err := s.roles.List(ctx)
+	if err != nil {
+		log.Println("Warning: gagal membaca roles, memakai role bawaan:", err)
+		defaults := make(map[string]models.Role, len(rbac.DefaultRoles))
+		for name, perms := range rbac.DefaultRoles {
+			defaults[name] = models.Role{Name: name, Permissions: perms}
+		}
+		return defaults
+	}
+	s.cache = make(map[string]models.Role, len(roles))
+	for _, r := range roles {
+		s.cache[r.Name] = r
+	}
+	s.loadedAt = time.Now()
+	return s.cache
+}
+
+func (s *RoleService) invalidate() {
+	s.mu.Lock()
+	s.cache = nil
+	s.mu.Unlock()
+}
+
+// Can bernilai true jika role memiliki permission perm.
+func (s *RoleService) Can(ctx context.Context, role, perm string) bool {
+	return rbac.Allows(s.all(ctx)[role].Permissions, perm)
+}
+
+// Scope mengembalikan batas moderasi role; nil = tidak dibatasi.
+func (s *RoleService) Scope(ctx context.Context, role string) *models.RoleScope {
+	return s.all(ctx)[role].Scope
+}
+
+// CanModerate bernilai true jika role memiliki permission moderasi perm
+// dan loc berada di dalam scope role.
+func (s *RoleService) CanModerate(ctx context.Context, role, perm string, loc *models.Location) bool {
+	r := s.all(ctx)[role]
+	return rbac.Allows(r.Permissions, perm) && r.Scope.Contains(loc)
+}
+
+// Exists bernilai true jika role terdaftar.
+func (s *RoleService) Exists(ctx context.Context, role string) bool {
+	_, ok := s.all(ctx)[role]
+	return ok
+}
+
+func (s *RoleService) List(ctx context.Context) ([]models.Role, error) {
+	return s.roles.List(ctx)
+}
+
+func (s *RoleService) Create(ctx context.Context, in models.Role) (*models.Role, error) {
+	if !roleNamePattern.MatchString(in.Name) {
+		return nil, invalid("Nama role hanya huruf kecil, angka, - dan _ (2-32 karakter)")
+	}
+	if _, err := s.roles.Get(ctx, in.Name); err == nil {
+		return nil, invalid("Role %s sudah ada", in.Name)
+	} else if !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+	return s.save(ctx, "role.create", nil, in)
+}
+
+func (s *RoleService) Update(ctx context.Context, name string, in models.Role) (*models.Role, error) {
+	if name == rbac.AdminRole {
+		return nil, invalid("Role %s tidak bisa diubah", rbac.AdminRole)
+	}
+	before, err := s.roles.Get(ctx, name)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrRoleNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	in.Name = name
+	return s.save(ctx, "role.update", before, in)
+}
+
+func (s *RoleService) save(ctx context.Context, action string, before *models.Role, role models.Role) (*models.Role, error) {
+	seen := map[string]bool{}
+	perms := []string{}
+	for _, p := range role.Permissions {
+		if !rbac.Known(p) {
+			return nil, invalid("Permission tidak dikenal: %s", p)
+		}
+		if !seen[p] {
+			seen[p] = true
+			perms = append(perms, p)
+		}
+	}
+	sort.Strings(perms)
+	role.Permissions = perms
+	scope, err := s.scope(ctx, role.Scope)
+	if err != nil {
+		return nil, err
+	}
+	role.Scope = scope
+	if err := s.roles.Save(ctx, role); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	s.audit.Record(ctx, AuditEvent{Action: action, ResourceType: AuditRole, ResourceID: role.Name, Before: before, After: role})
+	return &role, nil
+}
+
+// scope memvalidasi wilayah dan kategori scope; scope yang kosong menjadi
+// nil (tidak dibatasi).
+func (s *RoleService) scope(ctx context.Context, in *models.RoleScope) (*models.RoleScope, error) {
+	if in == nil {
+		return nil, nil
+	}
+	out := &models.RoleScope{Regions: dedupe(in.Regions), Categories: dedupe(in.Categories)}
+	for _, code := range out.Regions {
+		if _, err := s.regions.Get(ctx, code); errors.Is(err, repositories.ErrNotFound) {
+			return nil, invalid("Wilayah tidak dikenal: %s", code)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	for _, slug := range out.Categories {
+		if _, err := s.categories.FindBySlug(ctx, slug); errors.Is(err, repositories.ErrNotFound) {
+			return nil, invalid("Kategori tidak dikenal: %s", slug)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	if len(out.Regions) == 0 && len(out.Categories) == 0 {
+		return nil, nil
+	}
+	return out, nil
+}
+
+func dedupe(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" && !slices.Contains(out, v) {
+			out = append(out, v)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Delete menolak role bawaan dan role yang masih dipakai user.
+func (s *RoleService) Delete(ctx context.Context, name string) error {
+	if name == rbac.AdminRole || name == rbac.DefaultRole {
+		return invalid("Role %s tidak bisa dihapus", name)
+	}
+	n, err := s.users.CountByRole(ctx, name)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return invalid("Role %s masih dipakai %d user", name, n)
+	}
+	before, err := s.roles.Get(ctx, name)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return ErrRoleNotFound
+	} else if err != nil {
+		return err
+	}
+	if err := s.roles.Delete(ctx, name); err != nil {
+		return err
+	}
+	s.invalidate()
+	s.audit.Record(ctx, AuditEvent{Action: "role.delete", ResourceType: AuditRole, ResourceID: name, Before: before})
+	return nil
+}
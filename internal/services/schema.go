@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+// Ukuran batch upgrade schema
+const (
+	defaultSchemaBatch = 200
+	maxSchemaBatch     = 1000
+)
+
+// UpgradeSchema menulis ulang satu batch lokasi yang tersimpan dengan
+// versi LocationSchema lama. Tanpa job ini lokasi tetap terbaca benar
+// karena di-upgrade saat dibaca; job hanya membuat upgrade itu permanen.
+// Dipanggil berulang sampai Remaining = 0.
+func (s *LocationService) UpgradeSchema(ctx context.Context, batch int) (models.BackfillResult, error) {
+	var result models.BackfillResult
+	if batch <= 0 {
+		batch = defaultSchemaBatch
+	}
+	if batch > maxSchemaBatch {
+		batch = maxSchemaBatch
+	}
+	version := models.LocationSchema.Current()
+	locations, err := s.locations.SchemaOutdated(ctx, version, int64(batch))
+	if err != nil {
+		return result, err
+	}
+	for _, loc := range locations {
+		if err := s.locations.SaveUpgraded(ctx, loc); err != nil {
+			return result, err
+		}
+		result.Updated++
+	}
+	result.Processed = len(locations)
+	result.Remaining, err = s.locations.CountSchemaOutdated(ctx, version)
+	return result, err
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+// SecurityOptions adalah konfigurasi runtime yang ikut diperiksa. Nilai yang
+// bisa berubah saat reload config dibaca lewat fungsi setiap kali cek jalan.
+type SecurityOptions struct {
+	AllowAllOrigins      func() bool
+	AdminAllowAllOrigins func() bool
+	RegistrationOpen     func() bool
+	LegacyEmailAuth      func() bool
+	// Alasan JWT_SECRET dianggap lemah (config.JWT.Weakness); kosong jika kuat
+	JWTSecretWeakness string
+	MetricsTokenSet   bool
+}
+
+type SecurityService struct {
+	users     repositories.UserRepository
+	locations repositories.LocationRepository
+	opts      SecurityOptions
+}
+
+func NewSecurityService(users repositories.UserRepository, locations repositories.LocationRepository, opts SecurityOptions) *SecurityService {
+	return &SecurityService{users: users, locations: locations, opts: opts}
+}
+
+// Index yang wajib ada supaya query & constraint aman
+type requiredIndex struct {
+	name   string
+	key    string
+	unique bool
+	check  func(ctx context.Context, key string, unique bool) (bool, error)
+}
+
+func (s *SecurityService) requiredIndexes() []requiredIndex {
+	return []requiredIndex{
+		{name: "user.email", key: "email", unique: true, check: s.users.HasIndex},
+		{name: "geo_data.coordinates", key: "coordinates", check: s.locations.HasIndex},
+	}
+}
+
+func (s *SecurityService) RunChecks(ctx context.Context) []models.SecurityCheck {
+	var checks []models.SecurityCheck
+
+	if s.opts.AllowAllOrigins() {
+		checks = append(checks, models.SecurityCheck{ID: "cors_allow_all_origins", Status: "warn", Severity: "medium",
+			Message: "CORS mengizinkan semua origin (AllowAllOrigins aktif)",
+			Action:  "Isi CORS_ORIGINS dengan domain frontend yang dipakai"})
+	} else {
+		checks = append(checks, models.SecurityCheck{ID: "cors_allow_all_origins", Status: "ok", Severity: "medium", Message: "CORS dibatasi ke origin tertentu"})
+	}
+
+	if s.opts.AdminAllowAllOrigins() {
+		checks = append(checks, models.SecurityCheck{ID: "cors_admin_allow_all_origins", Status: "warn", Severity: "high",
+			Message: "CORS endpoint /v1/admin mengizinkan semua origin",
+			Action:  "Isi ADMIN_CORS_ORIGINS (atau CORS_ORIGINS) dengan domain panel admin"})
+	} else {
+		checks = append(checks, models.SecurityCheck{ID: "cors_admin_allow_all_origins", Status: "ok", Severity: "high", Message: "CORS endpoint admin dibatasi ke origin tertentu"})
+	}
+
+	if s.opts.RegistrationOpen() {
+		checks = append(checks, models.SecurityCheck{ID: "registration_open", Status: "warn", Severity: "low",
+			Message: "Registrasi publik terbuka untuk siapa saja",
+			Action:  "Set ALLOW_REGISTRATION=false jika pendaftaran tidak dibutuhkan"})
+	} else {
+		checks = append(checks, models.SecurityCheck{ID: "registration_open", Status: "ok", Severity: "low", Message: "Registrasi publik ditutup"})
+	}
+
+	if s.opts.LegacyEmailAuth() {
+		checks = append(checks, models.SecurityCheck{ID: "legacy_email_auth", Status: "warn", Severity: "high",
+			Message: "Header X-User-Email masih diterima: siapa pun yang tahu email user bisa bertindak sebagai user itu",
+			Action:  "Pindahkan client ke bearer token (lihat GET /v1/admin/deprecations) lalu set LEGACY_EMAIL_AUTH=false"})
+	} else {
+		checks = append(checks, models.SecurityCheck{ID: "legacy_email_auth", Status: "ok", Severity: "high", Message: "Header X-User-Email tidak diterima"})
+	}
+
+	if s.opts.JWTSecretWeakness != "" {
+		checks = append(checks, models.SecurityCheck{ID: "jwt_secret_weak", Status: "warn", Severity: "high",
+			Message: s.opts.JWTSecretWeakness + "; token bisa dipalsukan siapa pun yang menebaknya",
+			Action:  "Ganti JWT_SECRET dengan nilai acak minimal 32 karakter (mis. openssl rand -base64 48)"})
+	} else {
+		checks = append(checks, models.SecurityCheck{ID: "jwt_secret_weak", Status: "ok", Severity: "high", Message: "JWT_SECRET cukup panjang dan acak"})
+	}
+
+	if !s.opts.MetricsTokenSet {
+		checks = append(checks, models.SecurityCheck{ID: "metrics_open", Status: "warn", Severity: "medium",
+			Message: "GET /metrics terbuka tanpa token; hitungan per route dan per consumer bisa dibaca siapa saja",
+			Action:  "Set METRICS_TOKEN dan kirim sebagai bearer token dari scraper, atau blokir /metrics di firewall"})
+	} else {
+		checks = append(checks, models.SecurityCheck{ID: "metrics_open", Status: "ok", Severity: "medium", Message: "GET /metrics butuh METRICS_TOKEN"})
+	}
+
+	checks = append(checks, s.checkPlaintextPasswords(ctx))
+	checks = append(checks, s.checkIndexes(ctx)...)
+	return checks
+}
+
+func (s *SecurityService) checkPlaintextPasswords(ctx context.Context) models.SecurityCheck {
+	chk := models.SecurityCheck{ID: "plaintext_passwords", Severity: "high"}
+	count, err := s.users.CountPlaintextPasswords(ctx)
+	if err != nil {
+		chk.Status, chk.Message = "error", "Gagal menghitung akun: "+err.Error()
+		return chk
+	}
+	if count > 0 {
+		chk.Status = "warn"
+		chk.Message = fmt.Sprintf("%d akun masih menyimpan password plaintext", count)
+		chk.Action = "Hash password dengan bcrypt lalu paksa reset untuk akun lama"
+		return chk
+	}
+	chk.Status, chk.Message = "ok", "Tidak ada password plaintext"
+	return chk
+}
+
+func (s *SecurityService) checkIndexes(ctx context.Context) []models.SecurityCheck {
+	var checks []models.SecurityCheck
+	for _, idx := range s.requiredIndexes() {
+		chk := models.SecurityCheck{ID: "index:" + idx.name, Severity: "medium"}
+		found, err := idx.check(ctx, idx.key, idx.unique)
+		switch {
+		case err != nil:
+			chk.Status, chk.Message = "error", "Gagal membaca index: "+err.Error()
+		case found:
+			chk.Status, chk.Message = "ok", "Index "+idx.name+" tersedia"
+		default:
+			chk.Status, chk.Message = "warn", "Index "+idx.name+" belum dibuat"
+			if idx.unique {
+				chk.Action = "Buat unique index pada field " + idx.key
+			} else {
+				chk.Action = "Buat index pada field " + idx.key
+			}
+		}
+		checks = append(checks, chk)
+	}
+	return checks
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/auth"
+	"InfoCuy-Backend/internal/mailer"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Riwayat sesi (dan sidik jari yang dianggap dikenal) disimpan selama ini
+	sessionRetention = 90 * 24 * time.Hour
+	// Masa berlaku link "bukan saya" di email peringatan login
+	sessionReportTTL  = 7 * 24 * time.Hour
+	maxSessionHistory = 20
+	maxUserAgentLen   = 256
+)
+
+// Waktu di email peringatan dalam WIB
+var loginAlertZone = time.FixedZone("WIB", 7*60*60)
+
+// startSession mencatat login dan mengirim email peringatan jika perangkat
+// atau lokasinya belum pernah dipakai akun ini. Login pertama tidak
+// diperingatkan karena belum ada pembanding. Mengembalikan ID sesi, kosong
+// jika sesi gagal disimpan (login tetap berhasil, tanpa bisa dicabut).
+func (s *AuthService) startSession(ctx context.Context, u *models.User, dev models.LoginDevice) string {
+	now := time.Now().UTC()
+	sess := models.Session{
+		ID:        primitive.NewObjectID(),
+		UserID:    u.ID,
+		Device:    describeDevice(dev.UserAgent),
+		UserAgent: truncateUserAgent(dev.UserAgent),
+		IP:        dev.IP,
+		Location:  coarseLocation(dev),
+		CreatedAt: now,
+		ExpiresAt: now.Add(sessionRetention),
+	}
+	var reportToken string
+	seen, err := s.sessions.Seen(ctx, u.ID, sess.Device, sess.Location)
+	if err != nil {
+		log.Println("riwayat sesi:", err)
+	} else if seen.Any && (!seen.Device || !seen.Location) {
+		token, hash, err := auth.NewResetToken()
+		if err != nil {
+			log.Println("token laporan sesi:", err)
+		} else {
+			expires := now.Add(sessionReportTTL)
+			reportToken, sess.ReportTokenHash, sess.ReportExpiresAt = token, hash, &expires
+		}
+		sess.NewDevice, sess.NewLocation = !seen.Device, !seen.Location
+	}
+	if err := s.sessions.Create(ctx, &sess); err != nil {
+		log.Println("simpan sesi:", err)
+		return ""
+	}
+	if sess.NewDevice || sess.NewLocation {
+		s.sendLoginAlert(ctx, u, sess, reportToken)
+	}
+	return sess.ID.Hex()
+}
+
+func (s *AuthService) sendLoginAlert(ctx context.Context, u *models.User, sess models.Session, reportToken string) {
+	var what string
+	switch {
+	case sess.NewDevice && sess.NewLocation:
+		what = "perangkat dan lokasi"
+	case sess.NewDevice:
+		what = "perangkat"
+	default:
+		what = "lokasi"
+	}
+	body := fmt.Sprintf("Ada login ke akun InfoCuy Anda dari %s yang belum pernah dipakai sebelumnya:\n\n"+
+		"Perangkat: %s\nLokasi: %s\nIP: %s\nWaktu: %s\n",
+		what, sess.Device, sess.Location, sess.IP, sess.CreatedAt.In(loginAlertZone).Format("2 Jan 2006 15:04 MST"))
+	if reportToken != "" {
+		link := "token: " + reportToken
+		if s.opts.LoginAlertURL != "" {
+			link = s.opts.LoginAlertURL + "?token=" + reportToken
+		}
+		body += "\nJika ini bukan Anda, gunakan link berikut untuk mengeluarkan semua sesi dan mengatur ulang password (berlaku 7 hari):\n\n" + link
+	}
+	err := s.mail.Send(ctx, mailer.Message{To: u.Email, Subject: "Login baru ke akun InfoCuy", Body: body})
+	if err != nil {
+		log.Println("peringatan login:", err)
+	}
+}
+
+// ReportSession memproses link "bukan saya": sesi yang dilaporkan dan
+// semua sesi lain user dicabut, login ditolak sampai password direset, dan
+// link reset password dikirim ke email user.
+func (s *AuthService) ReportSession(ctx context.Context, in models.SessionReportInput) (*models.Session, error) {
+	now := time.Now().UTC()
+	sess, err := s.sessions.ConsumeReport(ctx, auth.HashResetToken(strings.TrimSpace(in.Token)), now)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	u, err := s.users.FindByID(ctx, sess.UserID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.revokeSessions(ctx, u.ID, now, true); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "user.session_report", ResourceType: AuditUser, ResourceID: u.ID.Hex(),
+		After: map[string]interface{}{"session_id": sess.ID.Hex(), "device": sess.Device, "ip": sess.IP, "location": sess.Location}})
+	if err := s.RequestPasswordReset(ctx, u.Email); err != nil {
+		log.Println("reset password setelah laporan sesi:", err)
+	}
+	return sess, nil
+}
+
+// revokeSessions menolak semua token user yang diterbitkan sebelum at.
+// Instance lain ikut menolak setelah cache user-nya kedaluwarsa.
+func (s *AuthService) revokeSessions(ctx context.Context, userID primitive.ObjectID, at time.Time, resetRequired bool) error {
+	err := s.users.Update(ctx, userID, repositories.Fields{"sessions_valid_after": at, "password_reset_required": resetRequired})
+	s.opts.Cache.Invalidate(userID)
+	if err != nil {
+		return err
+	}
+	if err := s.sessions.RevokeUser(ctx, userID, at, models.SessionPasswordReset); err != nil {
+		log.Println("tandai sesi dicabut:", err)
+	}
+	return nil
+}
+
+// Sessions mengembalikan riwayat login user, terbaru dulu.
+func (s *AuthService) Sessions(ctx context.Context, u models.User) ([]models.Session, error) {
+	return s.sessions.ListByUser(ctx, u.ID, maxSessionHistory)
+}
+
+// sessionRevoked bernilai true jika token dari sesi yang sudah dicabut.
+// Waktu mulai sesi diambil dari ObjectID sid, atau iat untuk token lama
+// tanpa sid. Keduanya berpresisi detik, jadi sesi yang dimulai pada detik
+// yang sama dengan pencabutan (mis. login tepat setelah reset) tetap
+// berlaku.
+func sessionRevoked(u *models.User, claims *auth.Claims) bool {
+	if u.SessionsValidAfter == nil {
+		return false
+	}
+	var started time.Time
+	if sid, err := primitive.ObjectIDFromHex(claims.Session); err == nil {
+		started = sid.Timestamp()
+	} else if claims.IssuedAt != nil {
+		started = claims.IssuedAt.Time
+	}
+	return started.Before(u.SessionsValidAfter.Truncate(time.Second))
+}
+
+func truncateUserAgent(ua string) string {
+	if len(ua) <= maxUserAgentLen {
+		return ua
+	}
+	return strings.ToValidUTF8(ua[:maxUserAgentLen], "")
+}
+
+// describeDevice meringkas user agent menjadi "<browser> di <OS>". Versi
+// diabaikan supaya update browser tidak dianggap perangkat baru.
+func describeDevice(ua string) string {
+	if strings.TrimSpace(ua) == "" {
+		return "Tidak dikenal"
+	}
+	browser := ""
+	for _, b := range []struct{ token, name string }{
+		{"Edg/", "Edge"}, {"OPR/", "Opera"}, {"Firefox/", "Firefox"}, {"FxiOS/", "Firefox"},
+		{"CriOS/", "Chrome"}, {"Chrome/", "Chrome"}, {"Safari/", "Safari"},
+	} {
+		if strings.Contains(ua, b.token) {
+			browser = b.name
+			break
+		}
+	}
+	if browser == "" {
+		// Klien non-browser, mis. "okhttp/4.9" atau "curl/8.0"
+		browser, _, _ = strings.Cut(strings.Fields(ua)[0], "/")
+	}
+	for _, o := range []struct{ token, name string }{
+		{"Android", "Android"}, {"iPhone", "iOS"}, {"iPad", "iOS"}, {"Windows", "Windows"},
+		{"Mac OS X", "macOS"}, {"CrOS", "ChromeOS"}, {"Linux", "Linux"},
+	} {
+		if strings.Contains(ua, o.token) {
+			return browser + " di " + o.name
+		}
+	}
+	return browser
+}
+
+// coarseLocation mengembalikan kota/negara dari header platform, atau
+// prefix jaringan IP (/16 untuk IPv4, /32 untuk IPv6) jika tidak ada.
+func coarseLocation(dev models.LoginDevice) string {
+	if dev.Country != "" {
+		if dev.City != "" {
+			return dev.City + ", " + dev.Country
+		}
+		return dev.Country
+	}
+	ip := net.ParseIP(dev.IP)
+	switch {
+	case ip == nil:
+		return "Tidak dikenal"
+	case ip.To4() != nil:
+		return "Jaringan " + (&net.IPNet{IP: ip.Mask(net.CIDRMask(16, 32)), Mask: net.CIDRMask(16, 32)}).String()
+	default:
+		return "Jaringan " + (&net.IPNet{IP: ip.Mask(net.CIDRMask(32, 128)), Mask: net.CIDRMask(32, 128)}).String()
+	}
+}
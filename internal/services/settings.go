@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+// Key dokumen di collection settings
+const (
+	nearSettingsKey    = "near_limits"
+	quotaSettingsKey   = "quotas"
+	rankingSettingsKey = "ranking"
+)
+
+// Default bawaan jika admin belum mengatur apa pun
+var defaultNearLimits = models.NearLimits{
+	MaxRadiusM:   50000,
+	DefaultLimit: 20,
+	MaxLimit:     100,
+	AllowedSorts: []string{"distance", "name"},
+}
+
+// Bobot relevansi bawaan: kecocokan teks paling menentukan, faktor lain
+// memisahkan hasil yang sama-sama cocok
+var defaultRankingWeights = models.RankingWeights{
+	Text:           3,
+	Distance:       1,
+	Rating:         1,
+	Freshness:      0.5,
+	Verified:       0.5,
+	DistanceScaleM: 2000,
+}
+
+// Kuota bawaan: user biasa 50, contributor 500, admin tanpa batas
+var defaultQuotas = map[string]int{"user": 50, "contributor": 500}
+
+type SettingsService struct {
+	repo  repositories.SettingsRepository
+	audit *AuditService
+}
+
+func NewSettingsService(repo repositories.SettingsRepository, audit *AuditService) *SettingsService {
+	return &SettingsService{repo: repo, audit: audit}
+}
+
+func (s *SettingsService) NearSettings(ctx context.Context) models.NearSettings {
+	settings := models.NearSettings{Default: defaultNearLimits, Categories: map[string]models.NearLimits{}}
+	var stored models.NearSettings
+	if err := s.repo.Get(ctx, nearSettingsKey, &stored); err != nil {
+		return settings
+	}
+	settings.Default = mergeNearLimits(defaultNearLimits, stored.Default)
+	if stored.Categories != nil {
+		settings.Categories = stored.Categories
+	}
+	return settings
+}
+
+func (s *SettingsService) UpdateNearSettings(ctx context.Context, in models.NearSettings, updatedBy string) (models.NearSettings, error) {
+	// Kategori disimpan lowercase supaya cocok dengan filter
+	categories := map[string]models.NearLimits{}
+	for name, l := range in.Categories {
+		if l.MaxRadiusM < 0 || l.DefaultLimit < 0 || l.MaxLimit < 0 {
+			return models.NearSettings{}, invalid("Nilai batas tidak boleh negatif")
+		}
+		categories[strings.ToLower(name)] = l
+	}
+	in.Categories = categories
+	before := s.NearSettings(ctx)
+	if err := s.repo.Put(ctx, nearSettingsKey, in, updatedBy); err != nil {
+		return models.NearSettings{}, err
+	}
+	after := s.NearSettings(ctx)
+	s.audit.Record(ctx, AuditEvent{Action: "settings.update", ResourceType: AuditSettings, ResourceID: nearSettingsKey, Before: before, After: after})
+	return after, nil
+}
+
+// NearLimits mengembalikan batas efektif untuk satu kategori (kosong = default).
+func (s *SettingsService) NearLimits(ctx context.Context, category string) models.NearLimits {
+	settings := s.NearSettings(ctx)
+	if override, ok := settings.Categories[strings.ToLower(category)]; ok && category != "" {
+		return mergeNearLimits(settings.Default, override)
+	}
+	return settings.Default
+}
+
+// Field yang diisi di override menimpa base
+func mergeNearLimits(base, override models.NearLimits) models.NearLimits {
+	if override.MaxRadiusM > 0 {
+		base.MaxRadiusM = override.MaxRadiusM
+	}
+	if override.DefaultLimit > 0 {
+		base.DefaultLimit = override.DefaultLimit
+	}
+	if override.MaxLimit > 0 {
+		base.MaxLimit = override.MaxLimit
+	}
+	if len(override.AllowedSorts) > 0 {
+		base.AllowedSorts = override.AllowedSorts
+	}
+	return base
+}
+
+// EnforceNearLimits memvalidasi radius/sort dan mengembalikan limit yang sudah di-clamp.
+func EnforceNearLimits(limits models.NearLimits, radiusM float64, limit int, sort string) (int, error) {
+	if radiusM > limits.MaxRadiusM {
+		return 0, invalid("radius maksimal %.0f meter", limits.MaxRadiusM)
+	}
+	if sort != "" {
+		allowed := false
+		for _, s := range limits.AllowedSorts {
+			if strings.TrimPrefix(sort, "-") == s {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return 0, invalid("sort hanya boleh: %s", strings.Join(limits.AllowedSorts, ", "))
+		}
+	}
+	return clampLimit(limits, limit), nil
+}
+
+func clampLimit(limits models.NearLimits, limit int) int {
+	if limit <= 0 {
+		limit = limits.DefaultLimit
+	}
+	if limit > limits.MaxLimit {
+		limit = limits.MaxLimit
+	}
+	return limit
+}
+
+func (s *SettingsService) QuotaSettings(ctx context.Context) models.QuotaSettings {
+	settings := models.QuotaSettings{Roles: map[string]int{}}
+	for role, n := range defaultQuotas {
+		settings.Roles[role] = n
+	}
+	var stored models.QuotaSettings
+	if err := s.repo.Get(ctx, quotaSettingsKey, &stored); err == nil && stored.Roles != nil {
+		settings.Roles = stored.Roles
+	}
+	return settings
+}
+
+func (s *SettingsService) UpdateQuotaSettings(ctx context.Context, in models.QuotaSettings, updatedBy string) error {
+	for role, n := range in.Roles {
+		if n < 0 {
+			return invalid("Kuota role %s tidak boleh negatif", role)
+		}
+	}
+	before := s.QuotaSettings(ctx)
+	if err := s.repo.Put(ctx, quotaSettingsKey, in, updatedBy); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "settings.update", ResourceType: AuditSettings, ResourceID: quotaSettingsKey, Before: before, After: in})
+	return nil
+}
+
+// RankingWeights mengembalikan bobot relevansi pencarian; bawaan jika
+// admin belum mengaturnya.
+func (s *SettingsService) RankingWeights(ctx context.Context) models.RankingWeights {
+	var stored models.RankingWeights
+	if err := s.repo.Get(ctx, rankingSettingsKey, &stored); err != nil {
+		return defaultRankingWeights
+	}
+	return stored
+}
+
+// UpdateRankingWeights mengganti semua bobot sekaligus. Minimal satu bobot
+// harus lebih dari 0.
+func (s *SettingsService) UpdateRankingWeights(ctx context.Context, in models.RankingWeights, updatedBy string) (models.RankingWeights, error) {
+	var v validator
+	var fields []string
+	var sum float64
+	for _, f := range rankFactors {
+		w := f.weight(in)
+		v.check(w >= 0, f.name, RuleNotNegative, nil)
+		fields, sum = append(fields, f.name), sum+max(w, 0)
+	}
+	v.check(sum > 0, "weights", RuleRequireOne, Params{"fields": fields})
+	v.check(in.DistanceScaleM >= 0, "distance_scale_m", RuleNotNegative, nil)
+	if err := v.err(); err != nil {
+		return models.RankingWeights{}, err
+	}
+	if in.DistanceScaleM == 0 {
+		in.DistanceScaleM = defaultRankingWeights.DistanceScaleM
+	}
+	before := s.RankingWeights(ctx)
+	if err := s.repo.Put(ctx, rankingSettingsKey, in, updatedBy); err != nil {
+		return models.RankingWeights{}, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "settings.update", ResourceType: AuditSettings, ResourceID: rankingSettingsKey, Before: before, After: in})
+	return in, nil
+}
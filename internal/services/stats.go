@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+// PublicStatsTTL adalah umur cache GET /stats, di server maupun di client.
+// Halaman depan boleh sedikit tertinggal, tetapi tidak boleh memicu
+// agregasi seluruh geo_data di setiap kunjungan.
+const PublicStatsTTL = 5 * time.Minute
+
+// StatsService menghitung angka ringkas dataset untuk publik.
+type StatsService struct {
+	locations  repositories.LocationRepository
+	categories *CategoryService
+
+	mu     sync.Mutex
+	cached *models.PublicStats
+}
+
+func NewStatsService(locations repositories.LocationRepository, categories *CategoryService) *StatsService {
+	return &StatsService{locations: locations, categories: categories}
+}
+
+// Public mengembalikan angka dari cache, atau menghitung ulang jika cache
+// sudah lebih tua dari PublicStatsTTL. Request bersamaan saat cache habis
+// menunggu satu perhitungan yang sama.
+func (s *StatsService) Public(ctx context.Context) (models.PublicStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cached != nil && time.Since(s.cached.GeneratedAt) < PublicStatsTTL {
+		return *s.cached, nil
+	}
+	stats, err := s.locations.PublicStats(ctx)
+	if err != nil {
+		return stats, err
+	}
+	categories, err := s.categories.List(ctx)
+	if err != nil {
+		return stats, err
+	}
+	stats.Categories = len(categories)
+	stats.GeneratedAt = time.Now().UTC()
+	s.cached = &stats
+	return stats, nil
+}
@@ -0,0 +1,368 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"InfoCuy-Backend/internal/httpclient"
+	"InfoCuy-Backend/internal/mailer"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/objectstore"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	statusSettingsKey = "status_incidents"
+	// Incident yang disimpan; yang paling lama dibuang
+	maxStatusIncidents = 50
+	maxIncidentTitle   = 120
+	maxIncidentMessage = 2000
+	// Incident selesai masih tampil di halaman status selama ini
+	resolvedIncidentWindow = 7 * 24 * time.Hour
+)
+
+// StatusTTL adalah umur cache /status.json di server dan di client. Probe
+// dependency hanya berjalan sekali per StatusTTL, berapa pun jumlah
+// pengunjung halaman status.
+const StatusTTL = 30 * time.Second
+
+// Urutan keparahan status, untuk memilih status keseluruhan
+var statusRank = map[string]int{
+	models.StatusOperational: 0,
+	models.StatusMaintenance: 1,
+	models.StatusDegraded:    2,
+	models.StatusOutage:      3,
+}
+
+var incidentSeverities = []string{models.IncidentMinor, models.IncidentMajor, models.IncidentMaintenance}
+
+// uptime menghitung probe per komponen sejak proses start.
+type uptime struct {
+	up, total int64
+}
+
+func (u uptime) ratio() float64 {
+	if u.total == 0 {
+		return 1
+	}
+	return float64(u.up) / float64(u.total)
+}
+
+// StatusService menyusun halaman status publik: kesehatan MongoDB, cache,
+// email, object storage dan provider eksternal, penanda incident dari admin,
+// serta uptime. Cache aplikasi ada di memori proses (tanpa Redis) sehingga
+// selalu operational selama instance hidup.
+type StatusService struct {
+	health   *HealthService
+	mail     mailer.Mailer
+	store    objectstore.Store
+	settings repositories.SettingsRepository
+	audit    *AuditService
+
+	mu      sync.Mutex
+	cached  *models.StatusPage
+	expires time.Time
+	uptime  map[string]uptime
+}
+
+// NewStatusService membuat service. store nil berarti upload foto
+// nonaktif.
+func NewStatusService(health *HealthService, mail mailer.Mailer, store objectstore.Store,
+	settings repositories.SettingsRepository, audit *AuditService) *StatusService {
+	return &StatusService{health: health, mail: mail, store: store, settings: settings, audit: audit, uptime: map[string]uptime{}}
+}
+
+// Page mengembalikan halaman status dari cache, di-probe ulang setelah
+// StatusTTL. Halaman ini paling dibutuhkan saat database bermasalah, jadi
+// incident yang gagal dibaca dianggap tidak ada (dengan catatan) dan
+// hasilnya tetap di-cache supaya tidak setiap pengunjung membebani
+// database.
+func (s *StatusService) Page(ctx context.Context) models.StatusPage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if s.cached != nil && now.Before(s.expires) {
+		return *s.cached
+	}
+	var note string
+	incidents, err := s.load(ctx)
+	if err != nil {
+		log.Println("baca incident halaman status:", err)
+		note = "Penanda incident tidak bisa dibaca saat ini; status komponen tetap terbaru"
+	}
+	components := s.probe(ctx)
+	page := models.StatusPage{
+		Status:     models.StatusOperational,
+		UpdatedAt:  now.UTC(),
+		StartedAt:  s.health.StartedAt(),
+		UptimeS:    int64(s.health.Uptime().Seconds()),
+		Components: make([]models.StatusComponent, 0, len(components)),
+		Incidents:  []models.StatusIncident{},
+		Note:       note,
+	}
+	for _, comp := range components {
+		u := s.uptime[comp.Name]
+		u.total++
+		if comp.Status != models.StatusOutage {
+			u.up++
+		}
+		s.uptime[comp.Name] = u
+		comp.Uptime = u.ratio()
+		page.Components = append(page.Components, comp)
+		page.Status = worse(page.Status, comp.Status)
+	}
+	for _, inc := range incidents {
+		if !publicIncident(inc, now) {
+			continue
+		}
+		page.Incidents = append(page.Incidents, inc)
+		if inc.ActiveAt(now) {
+			page.Status = worse(page.Status, incidentStatus(inc.Severity))
+		}
+	}
+	s.cached, s.expires = &page, now.Add(StatusTTL)
+	return page
+}
+
+func worse(a, b string) string {
+	if statusRank[b] > statusRank[a] {
+		return b
+	}
+	return a
+}
+
+func incidentStatus(severity string) string {
+	switch severity {
+	case models.IncidentMajor:
+		return models.StatusOutage
+	case models.IncidentMaintenance:
+		return models.StatusMaintenance
+	}
+	return models.StatusDegraded
+}
+
+// publicIncident bernilai true untuk incident yang berlangsung, maintenance
+// terjadwal dan incident yang selesai dalam resolvedIncidentWindow.
+func publicIncident(inc models.StatusIncident, now time.Time) bool {
+	return inc.ResolvedAt == nil || now.Sub(*inc.ResolvedAt) < resolvedIncidentWindow
+}
+
+// probe memeriksa semua komponen secara paralel. Provider eksternal hanya
+// dibaca dari circuit breaker-nya supaya halaman status tidak ikut
+// memanggil layanan pihak ketiga. Hasilnya di-cache untuk semua
+// pengunjung, jadi tidak ikut batal jika request pemicunya terputus.
+func (s *StatusService) probe(ctx context.Context) []models.StatusComponent {
+	ctx = context.WithoutCancel(ctx)
+	components := make([]models.StatusComponent, 4)
+	var wg sync.WaitGroup
+	for i, fn := range []func(context.Context) models.StatusComponent{s.probeDatabase, s.probeCache, s.probeMail, s.probeStorage} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			components[i] = fn(ctx)
+		}()
+	}
+	wg.Wait()
+	for _, p := range httpclient.Providers() {
+		comp := models.StatusComponent{Name: "provider:" + p.Name, Status: models.StatusOperational}
+		switch {
+		case p.Open:
+			comp.Status, comp.Message = models.StatusOutage, "Provider sedang tidak tersedia"
+		case p.Failures > 0:
+			comp.Status, comp.Message = models.StatusDegraded, "Sebagian panggilan ke provider gagal"
+		}
+		components = append(components, comp)
+	}
+	return components
+}
+
+func (s *StatusService) probeDatabase(ctx context.Context) models.StatusComponent {
+	comp := models.StatusComponent{Name: "database", Status: models.StatusOperational}
+	_, checks := s.health.Ready(ctx)
+	for _, chk := range checks {
+		switch {
+		case chk.Name == "mongodb":
+			comp.LatencyMS = chk.LatencyMS
+			switch chk.Status {
+			case "not_configured":
+				comp.Status = models.StatusNotConfigured
+			case "error":
+				comp.Status, comp.Message = models.StatusOutage, "Database tidak bisa dijangkau"
+			}
+		case chk.Status == "degraded":
+			comp.Status, comp.Message = models.StatusDegraded, "Database dalam mode baca saja, perubahan data tertunda"
+		}
+	}
+	return comp
+}
+
+func (s *StatusService) probeCache(context.Context) models.StatusComponent {
+	return models.StatusComponent{Name: "cache", Status: models.StatusOperational}
+}
+
+func (s *StatusService) probeMail(ctx context.Context) models.StatusComponent {
+	comp := models.StatusComponent{Name: "mail", Status: models.StatusOperational}
+	if s.mail == nil || !s.mail.Configured() {
+		comp.Status = models.StatusNotConfigured
+		return comp
+	}
+	return s.ping(ctx, comp, s.mail.Ping, "Pengiriman email terganggu")
+}
+
+func (s *StatusService) probeStorage(ctx context.Context) models.StatusComponent {
+	comp := models.StatusComponent{Name: "storage", Status: models.StatusOperational}
+	if s.store == nil {
+		comp.Status = models.StatusNotConfigured
+		return comp
+	}
+	return s.ping(ctx, comp, s.store.Ping, "Upload foto terganggu")
+}
+
+// ping tidak menyertakan isi error di halaman publik; sebabnya bisa dilihat
+// admin di GET /admin/doctor.
+func (s *StatusService) ping(ctx context.Context, comp models.StatusComponent, fn func(context.Context) error, failed string) models.StatusComponent {
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+	start := time.Now()
+	err := fn(ctx)
+	comp.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		comp.Status, comp.Message = models.StatusOutage, failed
+	}
+	return comp
+}
+
+func (s *StatusService) load(ctx context.Context) ([]models.StatusIncident, error) {
+	var stored models.StatusIncidentSettings
+	if err := s.settings.Get(ctx, statusSettingsKey, &stored); errors.Is(err, repositories.ErrNotFound) {
+		return []models.StatusIncident{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if stored.Incidents == nil {
+		stored.Incidents = []models.StatusIncident{}
+	}
+	return stored.Incidents, nil
+}
+
+func (s *StatusService) save(ctx context.Context, incidents []models.StatusIncident, updatedBy string) error {
+	sort.SliceStable(incidents, func(i, j int) bool { return incidents[i].StartsAt.After(incidents[j].StartsAt) })
+	if len(incidents) > maxStatusIncidents {
+		incidents = incidents[:maxStatusIncidents]
+	}
+	if err := s.settings.Put(ctx, statusSettingsKey, models.StatusIncidentSettings{Incidents: incidents}, updatedBy); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.expires = time.Time{}
+	s.mu.Unlock()
+	return nil
+}
+
+// Incidents mengembalikan semua incident yang tersimpan, terbaru lebih
+// dulu.
+func (s *StatusService) Incidents(ctx context.Context) ([]models.StatusIncident, error) {
+	return s.load(ctx)
+}
+
+func validateIncident(inc *models.StatusIncident) error {
+	inc.Title = strings.TrimSpace(inc.Title)
+	inc.Message = strings.TrimSpace(inc.Message)
+	var v validator
+	v.required(inc.Title != "", "title")
+	v.maxLength("title", inc.Title, maxIncidentTitle)
+	v.maxLength("message", inc.Message, maxIncidentMessage)
+	v.oneOf("severity", inc.Severity, incidentSeverities)
+	if inc.ResolvedAt != nil {
+		t := inc.ResolvedAt.UTC().Truncate(time.Millisecond)
+		inc.ResolvedAt = &t
+		v.check(t.After(inc.StartsAt), "resolved_at", RuleAfter, Params{"field": "starts_at"})
+	}
+	return v.err()
+}
+
+// CreateIncident menambah penanda incident. StartsAt kosong berarti
+// sekarang.
+func (s *StatusService) CreateIncident(ctx context.Context, inc models.StatusIncident, createdBy string) (*models.StatusIncident, error) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	if inc.StartsAt.IsZero() {
+		inc.StartsAt = now
+	}
+	inc.StartsAt = inc.StartsAt.UTC().Truncate(time.Millisecond)
+	if err := validateIncident(&inc); err != nil {
+		return nil, err
+	}
+	incidents, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inc.ID = primitive.NewObjectID().Hex()
+	inc.CreatedBy, inc.UpdatedAt = createdBy, now
+	if err := s.save(ctx, append(incidents, inc), createdBy); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "status.incident.create", ResourceType: AuditSettings, ResourceID: statusSettingsKey, After: inc})
+	return &inc, nil
+}
+
+// UpdateIncident mengganti isi incident, mis. menambah kabar terbaru atau
+// menandainya selesai lewat resolved_at.
+func (s *StatusService) UpdateIncident(ctx context.Context, id string, inc models.StatusIncident, updatedBy string) (*models.StatusIncident, error) {
+	incidents, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	i := indexIncident(incidents, id)
+	if i < 0 {
+		return nil, ErrIncidentNotFound
+	}
+	before := incidents[i]
+	if inc.StartsAt.IsZero() {
+		inc.StartsAt = before.StartsAt
+	}
+	inc.StartsAt = inc.StartsAt.UTC().Truncate(time.Millisecond)
+	if err := validateIncident(&inc); err != nil {
+		return nil, err
+	}
+	inc.ID, inc.CreatedBy, inc.UpdatedAt = before.ID, before.CreatedBy, time.Now().UTC().Truncate(time.Millisecond)
+	incidents[i] = inc
+	if err := s.save(ctx, incidents, updatedBy); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "status.incident.update", ResourceType: AuditSettings, ResourceID: statusSettingsKey, Before: before, After: inc})
+	return &inc, nil
+}
+
+// DeleteIncident menghapus incident, mis. yang dibuat keliru.
+func (s *StatusService) DeleteIncident(ctx context.Context, id, deletedBy string) error {
+	incidents, err := s.load(ctx)
+	if err != nil {
+		return err
+	}
+	i := indexIncident(incidents, id)
+	if i < 0 {
+		return ErrIncidentNotFound
+	}
+	before := incidents[i]
+	if err := s.save(ctx, append(incidents[:i], incidents[i+1:]...), deletedBy); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "status.incident.delete", ResourceType: AuditSettings, ResourceID: statusSettingsKey, Before: before})
+	return nil
+}
+
+func indexIncident(incidents []models.StatusIncident, id string) int {
+	for i, inc := range incidents {
+		if inc.ID == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+const (
+	// DefaultStreamMaxClients dipakai jika maxClients <= 0
+	DefaultStreamMaxClients = 500
+	// Event yang menunggu dikirim per client; client yang lebih lambat diputus
+	streamClientBuffer = 64
+)
+
+// Jenis event stream untuk setiap action LocationChanged. Action yang tidak
+// ada di sini (mis. konfirmasi) tidak mengubah data di peta.
+var streamEventTypes = map[string]string{
+	"location.create":    models.StreamCreated,
+	"location.import":    models.StreamCreated,
+	"location.restore":   models.StreamCreated,
+	"location.unarchive": models.StreamCreated,
+	"location.approve":   models.StreamCreated,
+	"location.update":    models.StreamUpdated,
+	"review.create":      models.StreamUpdated,
+	"review.delete":      models.StreamUpdated,
+	"review.approve":     models.StreamUpdated,
+	"location.delete":    models.StreamDeleted,
+	"location.purge":     models.StreamDeleted,
+	"location.archive":   models.StreamDeleted,
+}
+
+// LocationStream menyiarkan perubahan lokasi ke client GET /locations/stream.
+// Event berasal dari LocationEvents di proses ini, jadi setiap instance
+// hanya menyiarkan perubahan yang terjadi di instance itu sendiri.
+type LocationStream struct {
+	views      repositories.MapViewRepository
+	categories *CategoryService
+	maxClients int
+
+	mu      sync.Mutex
+	clients map[*StreamClient]struct{}
+	seq     uint64
+	closed  bool
+}
+
+// StreamClient adalah satu koneksi stream. Events ditutup saat client
+// diputus (terlalu lambat atau server berhenti).
+type StreamClient struct {
+	Events   <-chan models.LocationEvent
+	events   chan models.LocationEvent
+	category string
+}
+
+// NewLocationStream harus dibuat setelah NewMapViewService supaya event
+// dibaca dari map_view yang sudah diperbarui.
+func NewLocationStream(views repositories.MapViewRepository, categories *CategoryService, events *LocationEvents, maxClients int) *LocationStream {
+	if maxClients <= 0 {
+		maxClients = DefaultStreamMaxClients
+	}
+	s := &LocationStream{views: views, categories: categories, maxClients: maxClients, clients: make(map[*StreamClient]struct{})}
+	events.Subscribe(s.handle)
+	return s
+}
+
+// Subscribe mendaftarkan client baru. category (slug) kosong berarti semua
+// kategori; event deleted selalu dikirim karena kategorinya tidak diketahui.
+func (s *LocationStream) Subscribe(category string) (*StreamClient, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || len(s.clients) >= s.maxClients {
+		return nil, ErrStreamFull
+	}
+	ch := make(chan models.LocationEvent, streamClientBuffer)
+	c := &StreamClient{Events: ch, events: ch, category: strings.ToLower(strings.TrimSpace(category))}
+	s.clients[c] = struct{}{}
+	return c, nil
+}
+
+// Unsubscribe melepas client; aman dipanggil lebih dari sekali.
+func (s *LocationStream) Unsubscribe(c *StreamClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.drop(c)
+}
+
+// Close memutus semua client dan menolak client baru. Dipanggil saat
+// server mulai shutdown supaya koneksi stream tidak menahannya.
+func (s *LocationStream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for c := range s.clients {
+		s.drop(c)
+	}
+}
+
+// drop dipanggil dengan s.mu terkunci.
+func (s *LocationStream) drop(c *StreamClient) {
+	if _, ok := s.clients[c]; ok {
+		delete(s.clients, c)
+		close(c.events)
+	}
+}
+
+func (s *LocationStream) handle(ctx context.Context, ev LocationChanged) {
+	typ, ok := streamEventTypes[ev.Action]
+	if !ok || s.idle() {
+		return
+	}
+	if typ == models.StreamDeleted {
+		for _, id := range ev.IDs {
+			s.broadcast(models.LocationEvent{Type: typ, ID: id.Hex()})
+		}
+		return
+	}
+	items, err := s.views.FindByIDs(context.WithoutCancel(ctx), ev.IDs)
+	if err == nil {
+		err = concealItems(context.WithoutCancel(ctx), s.categories, items)
+	}
+	if err != nil {
+		log.Println("location stream", ev.Action+":", err)
+		return
+	}
+	for i := range items {
+		s.broadcast(models.LocationEvent{Type: typ, ID: items[i].ID.Hex(), Location: &items[i]})
+	}
+}
+
+func (s *LocationStream) idle() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.clients) == 0
+}
+
+func (s *LocationStream) broadcast(ev models.LocationEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	ev.Seq = s.seq
+	for c := range s.clients {
+		if ev.Location != nil && c.category != "" && ev.Location.Category != c.category {
+			continue
+		}
+		select {
+		case c.events <- ev:
+		default:
+			// Client tidak sanggup mengikuti; EventSource akan reconnect
+			s.drop(c)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"InfoCuy-Backend/internal/mailer"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxSuggestionNameLen          = 100
+	minSuggestionJustificationLen = 20
+	maxSuggestionJustificationLen = 2000
+	maxSuggestionNoteLen          = 500
+	// Usulan pending per user, supaya antrean admin tidak dibanjiri satu akun
+	maxPendingSuggestions = 5
+)
+
+var suggestionStatuses = []string{models.SuggestionPending, models.SuggestionApproved, models.SuggestionMerged, models.SuggestionRejected}
+
+// CategorySuggestionService mengelola usulan kategori baru dari user: user
+// mengusulkan beserta alasannya, admin (categories:manage) menyetujui,
+// menggabungkan ke kategori yang sudah ada, atau menolak, lalu pengusul
+// diberi tahu lewat email.
+type CategorySuggestionService struct {
+	suggestions repositories.CategorySuggestionRepository
+	categories  *CategoryService
+	mail        mailer.Mailer
+	audit       *AuditService
+}
+
+func NewCategorySuggestionService(suggestions repositories.CategorySuggestionRepository, categories *CategoryService,
+	mail mailer.Mailer, audit *AuditService) *CategorySuggestionService {
+	return &CategorySuggestionService{suggestions: suggestions, categories: categories, mail: mail, audit: audit}
+}
+
+// Suggest mencatat usulan u. Nama yang sudah menjadi kategori ditolak
+// supaya user langsung memakai kategori itu.
+func (s *CategorySuggestionService) Suggest(ctx context.Context, u models.User, in models.CategorySuggestionInput) (*models.CategorySuggestion, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	in.Justification = strings.TrimSpace(in.Justification)
+	var v validator
+	v.required(in.Name != "", "name")
+	v.maxLength("name", in.Name, maxSuggestionNameLen)
+	v.required(in.Justification != "", "justification")
+	if in.Justification != "" {
+		v.check(utf8.RuneCountInString(in.Justification) >= minSuggestionJustificationLen, "justification", RuleMinLength, Params{"min": minSuggestionJustificationLen})
+	}
+	v.maxLength("justification", in.Justification, maxSuggestionJustificationLen)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	slug := Slugify(in.Name)
+	if slug == "" {
+		return nil, invalid("Nama kategori tidak valid")
+	}
+	if _, err := s.categories.Get(ctx, slug); err == nil {
+		return nil, invalid("Kategori %s sudah ada", slug)
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	// Nama yang sama dengan kategori lain yang slug-nya berbeda
+	resolve, err := s.categories.Resolver(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if existing, err := resolve(in.Name); err == nil && existing != in.Name {
+		return nil, invalid("Kategori %s sudah ada", existing)
+	}
+	pending, err := s.suggestions.CountPending(ctx, u.Email)
+	if err != nil {
+		return nil, err
+	}
+	if pending >= maxPendingSuggestions {
+		return nil, invalid("Anda masih punya %d usulan kategori yang menunggu keputusan admin", pending)
+	}
+	sug := &models.CategorySuggestion{
+		Name:          in.Name,
+		Slug:          slug,
+		Justification: in.Justification,
+		SuggestedBy:   u.Email,
+		Status:        models.SuggestionPending,
+		CreatedAt:     time.Now().UTC(),
+	}
+	if err := s.suggestions.Create(ctx, sug); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "category_suggestion.create", ResourceType: AuditCategorySuggestion, ResourceID: sug.ID.Hex(), After: sug})
+	return sug, nil
+}
+
+// List mengembalikan usulan berstatus status (kosong = semua), yang paling
+// lama diusulkan dulu.
+func (s *CategorySuggestionService) List(ctx context.Context, status string, opts models.ListOptions) ([]models.CategorySuggestion, models.PageMeta, error) {
+	if status != "" {
+		var v validator
+		v.oneOf("status", status, suggestionStatuses)
+		if err := v.err(); err != nil {
+			return nil, models.PageMeta{}, err
+		}
+	}
+	p, err := listPage(repositories.CategorySuggestionList, opts)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	suggestions, total, err := s.suggestions.List(ctx, status, p)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	meta := repositories.Meta(p, total, suggestions)
+	return suggestions, meta, nil
+}
+
+func (s *CategorySuggestionService) pending(ctx context.Context, id primitive.ObjectID) (*models.CategorySuggestion, error) {
+	sug, err := s.suggestions.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrSuggestionNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if sug.Status != models.SuggestionPending {
+		return nil, ErrSuggestionResolved
+	}
+	return sug, nil
+}
+
+// Approve membuat kategori dari usulan. Field in yang kosong diisi dari
+// usulan (name, slug), sisanya (icon, warna, label) mengikuti in.
+func (s *CategorySuggestionService) Approve(ctx context.Context, u models.User, id primitive.ObjectID, in models.Category) (*models.CategorySuggestion, error) {
+	before, err := s.pending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(in.Name) == "" {
+		in.Name = before.Name
+	}
+	if in.Slug == "" {
+		in.Slug = before.Slug
+	}
+	category, err := s.categories.Create(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolve(ctx, u, before, models.SuggestionApproved, category, "")
+}
+
+// Merge mengarahkan usulan ke kategori into yang sudah ada, mis. karena
+// usulan sama dengan kategori lain dengan nama berbeda.
+func (s *CategorySuggestionService) Merge(ctx context.Context, u models.User, id primitive.ObjectID, into, note string) (*models.CategorySuggestion, error) {
+	before, err := s.pending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var v validator
+	v.required(into != "", "category")
+	v.maxLength("note", note, maxSuggestionNoteLen)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	category, err := s.categories.Get(ctx, into)
+	if errors.Is(err, ErrCategoryNotFound) {
+		v.check(false, "category", RuleNotFound, nil)
+		return nil, v.err()
+	} else if err != nil {
+		return nil, err
+	}
+	return s.resolve(ctx, u, before, models.SuggestionMerged, category, strings.TrimSpace(note))
+}
+
+// Reject menolak usulan; reason opsional ikut dikirim ke pengusul.
+func (s *CategorySuggestionService) Reject(ctx context.Context, u models.User, id primitive.ObjectID, reason string) (*models.CategorySuggestion, error) {
+	before, err := s.pending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	reason = strings.TrimSpace(reason)
+	var v validator
+	v.maxLength("reason", reason, maxSuggestionNoteLen)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return s.resolve(ctx, u, before, models.SuggestionRejected, nil, reason)
+}
+
+func (s *CategorySuggestionService) resolve(ctx context.Context, u models.User, before *models.CategorySuggestion, status string,
+	category *models.Category, note string) (*models.CategorySuggestion, error) {
+	set := repositories.Fields{"status": status, "resolved_by": u.Email, "resolved_at": time.Now().UTC(), "note": note}
+	if category != nil {
+		set["category"] = category.Slug
+	}
+	sug, err := s.suggestions.Resolve(ctx, before.ID, set)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrSuggestionResolved
+	} else if err != nil {
+		return nil, err
+	}
+	action := map[string]string{
+		models.SuggestionApproved: "category_suggestion.approve",
+		models.SuggestionMerged:   "category_suggestion.merge",
+		models.SuggestionRejected: "category_suggestion.reject",
+	}[status]
+	s.audit.Record(ctx, AuditEvent{Action: action, ResourceType: AuditCategorySuggestion, ResourceID: sug.ID.Hex(), Before: before, After: sug})
+	s.notify(ctx, sug, category)
+	return sug, nil
+}
+
+// notify memberi tahu pengusul lewat email. Kegagalan hanya dicatat;
+// keputusan tetap berlaku.
+func (s *CategorySuggestionService) notify(ctx context.Context, sug *models.CategorySuggestion, category *models.Category) {
+	if s.mail == nil {
+		return
+	}
+	var subject, body string
+	switch sug.Status {
+	case models.SuggestionApproved:
+		subject = "Usulan kategori Anda disetujui"
+		body = fmt.Sprintf("Usulan kategori \"%s\" disetujui. Kategori %s (%s) sekarang bisa dipakai untuk lokasi.", sug.Name, category.Name, category.Slug)
+	case models.SuggestionMerged:
+		subject = "Usulan kategori Anda digabungkan"
+		body = fmt.Sprintf("Usulan kategori \"%s\" digabungkan ke kategori yang sudah ada: %s (%s). Gunakan kategori itu untuk lokasi Anda.", sug.Name, category.Name, category.Slug)
+	default:
+		subject = "Usulan kategori Anda tidak disetujui"
+		body = fmt.Sprintf("Usulan kategori \"%s\" tidak disetujui admin.", sug.Name)
+	}
+	if sug.Note != "" {
+		body += "\n\nCatatan admin: " + sug.Note
+	}
+	if err := s.mail.Send(ctx, mailer.Message{To: sug.SuggestedBy, Subject: subject, Body: body}); err != nil {
+		log.Println("email usulan kategori", sug.ID.Hex()+":", err)
+	}
+}
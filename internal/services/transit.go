@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"sync"
+
+	"InfoCuy-Backend/internal/gtfs"
+	"InfoCuy-Backend/internal/httpclient"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Radius & jumlah halte untuk panel "cara ke sini"
+const (
+	defaultTransitRadiusM = 500
+	maxTransitRadiusM     = 2000
+	defaultTransitLimit   = 10
+	maxTransitLimit       = 50
+)
+
+var feedNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+type TransitService struct {
+	transit    repositories.TransitRepository
+	locations  repositories.LocationRepository
+	categories *CategoryService
+	// Sumber feed yang diambil berkala (lihat Sync)
+	settings repositories.SettingsRepository
+	audit    *AuditService
+	client   *httpclient.Client
+
+	// Menjaga daftar sumber dari perubahan dan sync yang bersamaan
+	mu sync.Mutex
+}
+
+func NewTransitService(transit repositories.TransitRepository, locations repositories.LocationRepository,
+	categories *CategoryService, settings repositories.SettingsRepository, audit *AuditService) *TransitService {
+	return &TransitService{transit: transit, locations: locations, categories: categories, settings: settings, audit: audit,
+		client: httpclient.New("transit_feed", httpclient.Options{Timeout: feedFetchTimeout, Retries: 1})}
+}
+
+// Import mengganti seluruh halte feed dengan isi zip GTFS. Feed yang sama
+// bisa di-import ulang saat operator merilis data baru.
+func (s *TransitService) Import(ctx context.Context, feed string, r io.ReaderAt, size int64) (models.TransitImportResult, error) {
+	result := models.TransitImportResult{Feed: feed}
+	if !feedNamePattern.MatchString(feed) {
+		return result, invalid("Nama feed hanya huruf kecil, angka, - dan _ (maks 32 karakter)")
+	}
+	ds, err := gtfs.Parse(r, size)
+	if errors.Is(err, gtfs.ErrInvalidFeed) {
+		return result, invalid("%s", err.Error())
+	}
+	if err != nil {
+		return result, err
+	}
+	if len(ds.Stops) == 0 {
+		return result, invalid("Tidak ada halte yang dilayani rute di file GTFS")
+	}
+	for i := range ds.Stops {
+		ds.Stops[i].Feed = feed
+		ds.Stops[i].ID = feed + ":" + ds.Stops[i].StopID
+	}
+	if err := s.transit.ReplaceFeed(ctx, feed, ds.Stops); err != nil {
+		return result, err
+	}
+	result.Stops = len(ds.Stops)
+	result.Routes = ds.Routes
+	result.Skipped = ds.Skipped
+	s.audit.Record(ctx, AuditEvent{Action: "transit.import", ResourceType: AuditTransit, ResourceID: feed, After: result})
+	return result, nil
+}
+
+// TransitParams adalah query GET /locations/:id/transit (radius dalam meter)
+type TransitParams struct {
+	RadiusM float64
+	Limit   int
+}
+
+// NearLocation mengembalikan halte terdekat dari lokasi id beserta radius
+// efektif yang dipakai. Untuk lokasi dengan koordinat perkiraan, jarak
+// dihitung dari perkiraan itu supaya tidak bisa dipakai triangulasi.
+func (s *TransitService) NearLocation(ctx context.Context, id primitive.ObjectID, p TransitParams) ([]models.NearbyTransitStop, float64, error) {
+	if p.RadiusM <= 0 {
+		p.RadiusM = defaultTransitRadiusM
+	}
+	if p.RadiusM > maxTransitRadiusM {
+		return nil, 0, invalid("radius maksimum %d meter", maxTransitRadiusM)
+	}
+	if p.Limit <= 0 {
+		p.Limit = defaultTransitLimit
+	}
+	if p.Limit > maxTransitLimit {
+		p.Limit = maxTransitLimit
+	}
+	loc, err := s.locations.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, 0, ErrLocationNotFound
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	conceal, err := concealer(ctx, s.categories, nil, models.User{})
+	if err != nil {
+		return nil, 0, err
+	}
+	conceal(loc)
+	stops, err := s.transit.Nearby(ctx, repositories.TransitNearbyQuery{
+		Lat:     loc.Coordinates.Lat,
+		Lng:     loc.Coordinates.Lng,
+		RadiusM: p.RadiusM,
+		Limit:   p.Limit,
+	})
+	return stops, p.RadiusM, err
+}
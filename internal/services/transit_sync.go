@@ -0,0 +1,231 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+const (
+	transitSourcesKey = "transit_sources"
+	feedFetchTimeout  = 2 * time.Minute
+	// Sama dengan batas upload POST /admin/transit/import
+	maxTransitFeedBytes = 100 << 20
+	// Interval pengambilan dalam menit
+	defaultFeedInterval = 24 * 60
+	minFeedInterval     = 15
+	maxFeedInterval     = 7 * 24 * 60
+	// Setiap sumber bisa berarti download sampai 100MB
+	defaultTransitSyncBatch = 5
+	maxTransitSyncBatch     = 20
+)
+
+// Sources mengembalikan sumber feed transit yang diambil berkala, urut
+// nama feed.
+func (s *TransitService) Sources(ctx context.Context) ([]models.TransitSource, error) {
+	var stored models.TransitSourceSettings
+	if err := s.settings.Get(ctx, transitSourcesKey, &stored); errors.Is(err, repositories.ErrNotFound) {
+		return []models.TransitSource{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if stored.Sources == nil {
+		stored.Sources = []models.TransitSource{}
+	}
+	return stored.Sources, nil
+}
+
+func (s *TransitService) saveSources(ctx context.Context, sources []models.TransitSource, updatedBy string) error {
+	slices.SortFunc(sources, func(a, b models.TransitSource) int { return strings.Compare(a.Feed, b.Feed) })
+	return s.settings.Put(ctx, transitSourcesKey, models.TransitSourceSettings{Sources: sources}, updatedBy)
+}
+
+// PutSource membuat atau mengubah sumber feed. Status pengambilan direset
+// jika URL berubah, sehingga feed diambil penuh pada sync berikutnya.
+func (s *TransitService) PutSource(ctx context.Context, u models.User, feed string, in models.TransitSourceInput) (*models.TransitSource, error) {
+	if !feedNamePattern.MatchString(feed) {
+		return nil, invalid("Nama feed hanya huruf kecil, angka, - dan _ (maks 32 karakter)")
+	}
+	in.URL = strings.TrimSpace(in.URL)
+	if in.IntervalMinutes == 0 {
+		in.IntervalMinutes = defaultFeedInterval
+	}
+	var v validator
+	parsed, err := url.Parse(in.URL)
+	if in.URL == "" {
+		v.required(false, "url")
+	} else {
+		v.check(err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != "", "url", RuleURL, nil)
+	}
+	v.check(in.IntervalMinutes >= minFeedInterval && in.IntervalMinutes <= maxFeedInterval, "interval_minutes", RuleRange,
+		Params{"min": minFeedInterval, "max": maxFeedInterval})
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sources, err := s.Sources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	source := models.TransitSource{Feed: feed}
+	i := slices.IndexFunc(sources, func(src models.TransitSource) bool { return src.Feed == feed })
+	var before interface{}
+	if i >= 0 {
+		before = sources[i]
+		if sources[i].URL == in.URL {
+			source = sources[i]
+		}
+	}
+	source.URL, source.IntervalMinutes = in.URL, in.IntervalMinutes
+	if i >= 0 {
+		sources[i] = source
+	} else {
+		sources = append(sources, source)
+	}
+	if err := s.saveSources(ctx, sources, u.Email); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "transit.source_update", ResourceType: AuditTransit, ResourceID: feed, Before: before, After: source})
+	return &source, nil
+}
+
+// DeleteSource berhenti mengambil feed. Halte yang sudah di-import tetap
+// ada sampai feed di-import ulang.
+func (s *TransitService) DeleteSource(ctx context.Context, u models.User, feed string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sources, err := s.Sources(ctx)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(sources, func(src models.TransitSource) bool { return src.Feed == feed })
+	if i < 0 {
+		return ErrTransitSourceNotFound
+	}
+	before := sources[i]
+	if err := s.saveSources(ctx, slices.Delete(sources, i, i+1), u.Email); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "transit.source_delete", ResourceType: AuditTransit, ResourceID: feed, Before: before})
+	return nil
+}
+
+// Sync mengambil sumber feed yang sudah jatuh tempo (atau semua, jika
+// force) dengan conditional GET: server yang menjawab 304, atau isi yang
+// sama persis dengan import terakhir, tidak di-import ulang. Seperti job
+// lain, dipanggil berkala oleh cron (mis. tiap 15 menit); interval per
+// sumber yang menentukan kapan feed benar-benar diambil.
+func (s *TransitService) Sync(ctx context.Context, u models.User, batch int, force bool) (models.TransitSyncResult, error) {
+	result := models.TransitSyncResult{Sources: []models.TransitSource{}}
+	if batch <= 0 {
+		batch = defaultTransitSyncBatch
+	}
+	if batch > maxTransitSyncBatch {
+		batch = maxTransitSyncBatch
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sources, err := s.Sources(ctx)
+	if err != nil {
+		return result, err
+	}
+	now := time.Now().UTC()
+	for i := range sources {
+		src := &sources[i]
+		due := force || src.CheckedAt == nil || !now.Before(src.CheckedAt.Add(time.Duration(src.IntervalMinutes)*time.Minute))
+		if !due {
+			continue
+		}
+		if result.Checked == batch {
+			result.Remaining++
+			continue
+		}
+		s.fetch(ctx, src)
+		result.Checked++
+		switch src.Status {
+		case models.FeedImported:
+			result.Imported++
+		case models.FeedNotModified, models.FeedUnchanged:
+			result.Unchanged++
+		default:
+			result.Failed++
+		}
+		result.Sources = append(result.Sources, *src)
+	}
+	if result.Checked == 0 {
+		return result, nil
+	}
+	return result, s.saveSources(ctx, sources, u.Email)
+}
+
+// fetch mengambil satu sumber dan mencatat hasilnya di src. Validator
+// (ETag, Last-Modified) hanya disimpan setelah isinya berhasil diproses,
+// supaya feed yang gagal di-import diambil penuh lagi berikutnya.
+func (s *TransitService) fetch(ctx context.Context, src *models.TransitSource) {
+	now := time.Now().UTC()
+	src.CheckedAt, src.Error = &now, ""
+	status, err := s.download(ctx, src)
+	if err != nil {
+		src.Status, src.Error = models.FeedFailed, err.Error()
+		return
+	}
+	src.Status = status
+}
+
+func (s *TransitService) download(ctx context.Context, src *models.TransitSource) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src.URL, nil)
+	if err != nil {
+		return "", err
+	}
+	if src.ETag != "" {
+		req.Header.Set("If-None-Match", src.ETag)
+	}
+	if src.LastModified != "" {
+		req.Header.Set("If-Modified-Since", src.LastModified)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		return models.FeedNotModified, nil
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("server feed menjawab %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTransitFeedBytes+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxTransitFeedBytes {
+		return "", fmt.Errorf("feed lebih dari %dMB", maxTransitFeedBytes>>20)
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	status := models.FeedUnchanged
+	if hash != src.ContentHash {
+		result, err := s.Import(ctx, src.Feed, bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return "", err
+		}
+		now := time.Now().UTC()
+		src.ContentHash, src.Result, src.ImportedAt = hash, &result, &now
+		status = models.FeedImported
+	}
+	src.ETag, src.LastModified = resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	return status, nil
+}
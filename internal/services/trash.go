@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/rbac"
+	"InfoCuy-Backend/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// DefaultTrashRetention dipakai jika LocationOptions.TrashRetention kosong
+	DefaultTrashRetention = 30 * 24 * time.Hour
+	defaultPurgeBatch     = 100
+	maxPurgeBatch         = 500
+)
+
+// Trash mengembalikan lokasi di trash, yang terakhir dihapus lebih dulu.
+// Pemilik permission locations:delete_any melihat semua lokasi di dalam
+// scope role-nya, user lain hanya lokasi miliknya.
+func (s *LocationService) Trash(ctx context.Context, u models.User, opts models.ListOptions) ([]models.Location, models.PageMeta, error) {
+	p, err := listPage(repositories.TrashList, opts)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	q := repositories.LocationQuery{
+		Trashed:  true,
+		ListPage: p,
+	}
+	if s.roles.Can(ctx, u.Role, rbac.LocationsDeleteAny) {
+		q.Scope = s.roles.Scope(ctx, u.Role)
+	} else {
+		q.CreatedBy = u.Email
+	}
+	locations, total, err := s.locations.List(ctx, q)
+	if err != nil {
+		return nil, models.PageMeta{}, err
+	}
+	meta := repositories.Meta(p, total, locations)
+	return locations, meta, nil
+}
+
+// Restore mengeluarkan lokasi dari trash (pemilik atau locations:delete_any).
+// Lokasi hasil merge ditolak: ID-nya sudah dialihkan, jadi memulihkannya
+// berarti ada dua salinan tayang dan redirect yang tidak pernah dipakai.
+func (s *LocationService) Restore(ctx context.Context, u models.User, id primitive.ObjectID) (*models.Location, error) {
+	existing, err := s.locations.FindDeleted(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrLocationNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if existing.CreatedBy != u.Email && !s.roles.CanModerate(ctx, u.Role, rbac.LocationsDeleteAny, existing) {
+		return nil, ErrForbidden
+	}
+	if s.opts.Redirects != nil {
+		if _, err := s.opts.Redirects.Find(ctx, id); err == nil {
+			return nil, ErrLocationMerged
+		} else if !errors.Is(err, repositories.ErrNotFound) {
+			return nil, err
+		}
+	}
+	if err := s.locations.Restore(ctx, id); errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrLocationNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "location.restore", ResourceType: AuditLocation, ResourceID: id.Hex(), Before: existing})
+	s.opts.Events.Publish(ctx, "location.restore", id)
+	existing.DeletedAt, existing.DeletedBy = nil, ""
+	return existing, nil
+}
+
+// HardDelete menghapus lokasi (di trash maupun tidak) secara permanen
+// beserta ulasan, konfirmasi dan fotonya. Permission dicek di route; scope
+// role dicek di sini.
+func (s *LocationService) HardDelete(ctx context.Context, u models.User, id primitive.ObjectID) (*models.Location, error) {
+	existing, err := s.locations.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		existing, err = s.locations.FindDeleted(ctx, id)
+	}
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrLocationNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if !s.roles.CanModerate(ctx, u.Role, rbac.LocationsPurge, existing) {
+		return nil, ErrForbidden
+	}
+	if err := s.purge(ctx, *existing); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "location.purge", ResourceType: AuditLocation, ResourceID: id.Hex(), Before: existing})
+	return existing, nil
+}
+
+// PurgeTrash menghapus permanen satu batch lokasi yang sudah lebih lama
+// dari masa retensi di trash. Dipanggil berulang (mis. cron) sampai
+// Remaining = 0.
+func (s *LocationService) PurgeTrash(ctx context.Context, batch int) (models.PurgeResult, error) {
+	if batch <= 0 {
+		batch = defaultPurgeBatch
+	}
+	if batch > maxPurgeBatch {
+		batch = maxPurgeBatch
+	}
+	retention := s.opts.TrashRetention
+	if retention <= 0 {
+		retention = DefaultTrashRetention
+	}
+	result := models.PurgeResult{Before: time.Now().UTC().Add(-retention)}
+	locations, err := s.locations.DeletedBefore(ctx, result.Before, int64(batch))
+	if err != nil {
+		return result, err
+	}
+	for _, loc := range locations {
+		if err := s.purge(ctx, loc); err != nil {
+			return result, err
+		}
+		s.audit.Record(ctx, AuditEvent{Action: "location.purge", ResourceType: AuditLocation, ResourceID: loc.ID.Hex(), Before: loc})
+		result.Purged++
+	}
+	result.Remaining, err = s.locations.CountDeletedBefore(ctx, result.Before)
+	return result, err
+}
+
+// purge menghapus dokumen lokasi lalu data turunannya. Kegagalan pada data
+// turunan hanya dicatat supaya tidak membatalkan penghapusan lokasi.
+func (s *LocationService) purge(ctx context.Context, loc models.Location) error {
+	if err := s.locations.Delete(ctx, loc.ID); err != nil {
+		return err
+	}
+	s.opts.Events.Publish(ctx, "location.purge", loc.ID)
+	if _, err := s.reviews.DeleteByLocation(ctx, loc.ID); err != nil {
+		log.Println("hapus ulasan lokasi", loc.ID.Hex()+":", err)
+	}
+	if _, err := s.confirmations.DeleteByLocation(ctx, loc.ID); err != nil {
+		log.Println("hapus konfirmasi lokasi", loc.ID.Hex()+":", err)
+	}
+	if s.opts.CheckIns != nil {
+		if _, err := s.opts.CheckIns.DeleteByLocation(ctx, loc.ID); err != nil {
+			log.Println("hapus check-in lokasi", loc.ID.Hex()+":", err)
+		}
+	}
+	s.deletePhotoFiles(ctx, loc.Photos...)
+	return nil
+}
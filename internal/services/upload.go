@@ -0,0 +1,176 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"log"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/objectstore"
+	"InfoCuy-Backend/internal/rbac"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// MaxDirectPhotoBytes adalah ukuran maksimal foto yang diupload lewat
+	// URL presigned; foto sebesar ini tidak muat di body request serverless
+	MaxDirectPhotoBytes = 25 << 20
+	// Masa berlaku URL presigned
+	presignTTL = 15 * time.Minute
+	// Byte awal yang dibaca saat konfirmasi untuk sniffing tipe dan dimensi
+	photoHeaderBytes = 64 << 10
+)
+
+// presigner mengembalikan object store foto jika mendukung upload langsung.
+func (s *LocationService) presigner() (objectstore.Store, objectstore.Presigner, error) {
+	store := s.opts.Photos
+	if store == nil {
+		return nil, nil, invalid("Upload foto belum dikonfigurasi (OBJECT_STORE)")
+	}
+	p, ok := store.(objectstore.Presigner)
+	if !ok {
+		return nil, nil, invalid("Object store %s tidak mendukung upload langsung, gunakan POST /locations/:id/photos", store.Name())
+	}
+	return store, p, nil
+}
+
+// photoKey adalah key object foto; upload ID adalah nama file-nya.
+func photoKey(locationID primitive.ObjectID, uploadID string) string {
+	return "locations/" + locationID.Hex() + "/" + uploadID
+}
+
+// PresignPhoto menerbitkan URL upload langsung untuk foto besar. Tidak ada
+// yang disimpan di database: upload ID (ID foto + ekstensi) hanya berlaku
+// di bawah lokasinya dan dicek lagi saat konfirmasi. Ukuran size_bytes
+// ikut ditandatangani, jadi storage menolak upload dengan ukuran lain.
+// File yang tidak pernah dikonfirmasi tertinggal di storage; bersihkan
+// dengan lifecycle rule bucket.
+func (s *LocationService) PresignPhoto(ctx context.Context, u models.User, in models.PresignInput) (*models.PresignedUpload, error) {
+	_, p, err := s.presigner()
+	if err != nil {
+		return nil, err
+	}
+	contentType := strings.ToLower(strings.TrimSpace(in.ContentType))
+	ext, ok := photoExtensions[contentType]
+	var v validator
+	v.required(!in.LocationID.IsZero(), "location_id")
+	v.check(ok, "content_type", RuleOneOf, Params{"allowed": []string{"image/jpeg", "image/png", "image/webp", "image/gif"}})
+	v.check(in.SizeBytes > 0 && in.SizeBytes <= MaxDirectPhotoBytes, "size_bytes", RuleRange, Params{"min": 1, "max": MaxDirectPhotoBytes})
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	existing, err := s.authorize(ctx, u, in.LocationID, rbac.LocationsUpdateAny)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing.Photos) >= maxPhotosPerLocation {
+		return nil, invalid("Maksimal %d foto per lokasi", maxPhotosPerLocation)
+	}
+	uploadID := primitive.NewObjectID().Hex() + ext
+	url, err := p.PresignPut(photoKey(in.LocationID, uploadID), contentType, in.SizeBytes, presignTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &models.PresignedUpload{
+		UploadID:  uploadID,
+		Method:    http.MethodPut,
+		URL:       url,
+		Headers:   map[string]string{"Content-Type": contentType, "Content-Length": strconv.FormatInt(in.SizeBytes, 10)},
+		ExpiresAt: time.Now().UTC().Add(presignTTL),
+	}, nil
+}
+
+// ConfirmPhoto memeriksa file yang sudah diupload lewat URL presigned lalu
+// mencatatnya sebagai foto lokasi. File yang bukan gambar atau terlalu
+// besar dihapus dari storage.
+func (s *LocationService) ConfirmPhoto(ctx context.Context, u models.User, in models.ConfirmUploadInput) (*models.Photo, error) {
+	store, p, err := s.presigner()
+	if err != nil {
+		return nil, err
+	}
+	ext := path.Ext(in.UploadID)
+	photoID, idErr := primitive.ObjectIDFromHex(strings.TrimSuffix(in.UploadID, ext))
+	contentType := ""
+	for ct, e := range photoExtensions {
+		if e == ext {
+			contentType = ct
+		}
+	}
+	if idErr != nil || contentType == "" {
+		return nil, &ValidationError{Message: "Data tidak valid", Fields: []FieldError{fieldError("upload_id", RuleNotFound, nil)}}
+	}
+	existing, err := s.authorize(ctx, u, in.LocationID, rbac.LocationsUpdateAny)
+	if err != nil {
+		return nil, err
+	}
+	for _, photo := range existing.Photos {
+		if photo.ID == photoID {
+			return &photo, nil
+		}
+	}
+
+	key := photoKey(in.LocationID, in.UploadID)
+	info, err := p.Head(ctx, key)
+	if errors.Is(err, objectstore.ErrNotFound) {
+		return nil, invalid("File belum diupload atau URL upload sudah kedaluwarsa")
+	} else if err != nil {
+		return nil, err
+	}
+	// File yang ditolak tidak akan pernah dikonfirmasi, jadi langsung dihapus
+	discard := func() {
+		if err := store.Delete(context.WithoutCancel(ctx), key); err != nil {
+			log.Println("hapus upload", key+":", err)
+		}
+	}
+	if len(existing.Photos) >= maxPhotosPerLocation {
+		discard()
+		return nil, invalid("Maksimal %d foto per lokasi", maxPhotosPerLocation)
+	}
+	if info.Size > MaxDirectPhotoBytes {
+		discard()
+		return nil, invalid("Ukuran foto maksimal %d MB", MaxDirectPhotoBytes>>20)
+	}
+	header, err := p.ReadPrefix(ctx, key, photoHeaderBytes)
+	if err != nil {
+		return nil, err
+	}
+	// Tipe diambil dari isi file, bukan dari header yang dikirim client
+	if http.DetectContentType(header) != contentType {
+		discard()
+		return nil, &ValidationError{Message: "Data tidak valid", Fields: []FieldError{
+			fieldError("file", RuleImage, Params{"formats": []string{"JPEG", "PNG", "WebP", "GIF"}}),
+		}}
+	}
+
+	photo := models.Photo{
+		ID:          photoID,
+		Store:       store.Name(),
+		Key:         key,
+		URL:         p.URL(key),
+		ContentType: contentType,
+		SizeBytes:   info.Size,
+		UploadedBy:  u.Email,
+		UploadedAt:  time.Now().UTC(),
+	}
+	if cfg, _, err := image.DecodeConfig(bytes.NewReader(header)); err == nil {
+		photo.Width, photo.Height = cfg.Width, cfg.Height
+	}
+	added, err := s.locations.AddPhoto(ctx, in.LocationID, photo, maxPhotosPerLocation)
+	if err != nil {
+		return nil, err
+	}
+	if !added {
+		discard()
+		return nil, invalid("Maksimal %d foto per lokasi", maxPhotosPerLocation)
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "location.photo_add", ResourceType: AuditLocation, ResourceID: in.LocationID.Hex(), After: photo})
+	return &photo, nil
+}
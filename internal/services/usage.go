@@ -0,0 +1,309 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"io"
+	"log"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+const (
+	// Counter dikumpulkan di memori lalu ditulis sekaligus, supaya setiap
+	// request tidak menambah satu write ke MongoDB
+	usageFlushInterval = 30 * time.Second
+	// Flush lebih awal jika kombinasi consumer/endpoint sebanyak ini
+	usageFlushSize = 500
+	// Di atas batas ini (mis. MongoDB sedang down) kombinasi baru dibuang
+	maxPendingUsage   = 20000
+	usageWriteTimeout = 10 * time.Second
+	// Rentang laporan
+	defaultUsageDays = 7
+	maxUsageDays     = 92
+)
+
+// Tanggal counter dalam WIB, sama dengan impression promosi
+var usageZone = time.FixedZone("WIB", 7*60*60)
+
+// UsageHit adalah satu request yang sudah selesai.
+type UsageHit struct {
+	// Email user yang login; kosong = anonim, dikelompokkan per IP
+	Email    string
+	IP       string
+	Method   string
+	Route    string
+	Status   int
+	BytesIn  int64
+	BytesOut int64
+}
+
+type usageKey struct {
+	date, consumer, method, route string
+}
+
+// UsageService mencatat pemakaian API per consumer untuk GET /admin/usage.
+// Counter ditahan di memori instance dan ditulis per batch; sisa yang
+// belum ditulis hilang jika proses mati tanpa Close.
+type UsageService struct {
+	repo repositories.UsageRepository
+
+	mu      sync.Mutex
+	pending map[usageKey]*models.UsageCounter
+	dropped int64
+	// Satu flush pada satu waktu
+	flushMu sync.Mutex
+	kick    chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func NewUsageService(repo repositories.UsageRepository) *UsageService {
+	s := &UsageService{repo: repo, pending: map[usageKey]*models.UsageCounter{},
+		kick: make(chan struct{}, 1), stop: make(chan struct{}), done: make(chan struct{})}
+	go s.loop()
+	return s
+}
+
+func (s *UsageService) loop() {
+	defer close(s.done)
+	ticker := time.NewTicker(usageFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.kick:
+		case <-s.stop:
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), usageWriteTimeout)
+		if err := s.Flush(ctx); err != nil {
+			log.Println("simpan pemakaian API:", err)
+		}
+		cancel()
+	}
+}
+
+// Record menambah hit ke counter di memori. Tidak pernah memblokir
+// request untuk menulis ke database.
+func (s *UsageService) Record(hit UsageHit) {
+	key := usageKey{date: time.Now().In(usageZone).Format("2006-01-02"), method: hit.Method, route: hit.Route}
+	consumerType := models.ConsumerUser
+	if key.consumer = hit.Email; key.consumer == "" {
+		key.consumer, consumerType = hit.IP, models.ConsumerIP
+	}
+	s.mu.Lock()
+	c, ok := s.pending[key]
+	if !ok {
+		if len(s.pending) >= maxPendingUsage {
+			s.dropped++
+			s.mu.Unlock()
+			return
+		}
+		c = &models.UsageCounter{Date: key.date, Consumer: key.consumer, ConsumerType: consumerType, Method: key.method, Route: key.route}
+		s.pending[key] = c
+	}
+	c.Requests++
+	switch {
+	case hit.Status >= 500:
+		c.ServerErrors++
+	case hit.Status >= 400:
+		c.ClientErrors++
+	}
+	c.BytesIn += max(hit.BytesIn, 0)
+	c.BytesOut += max(hit.BytesOut, 0)
+	full := len(s.pending) >= usageFlushSize
+	s.mu.Unlock()
+	if full {
+		select {
+		case s.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Flush menulis semua counter yang tertahan. Jika gagal, counter
+// dikembalikan supaya ikut di flush berikutnya.
+func (s *UsageService) Flush(ctx context.Context) error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+	s.mu.Lock()
+	batch, dropped := s.pending, s.dropped
+	s.pending, s.dropped = map[usageKey]*models.UsageCounter{}, 0
+	s.mu.Unlock()
+	if dropped > 0 {
+		log.Printf("pemakaian API: %d request tidak dicatat karena buffer penuh", dropped)
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+	counters := make([]models.UsageCounter, 0, len(batch))
+	for _, c := range batch {
+		counters = append(counters, *c)
+	}
+	if err := s.repo.Add(ctx, counters); err != nil {
+		s.restore(batch)
+		return err
+	}
+	return nil
+}
+
+func (s *UsageService) restore(batch map[usageKey]*models.UsageCounter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, c := range batch {
+		cur, ok := s.pending[key]
+		if !ok {
+			if len(s.pending) >= maxPendingUsage {
+				s.dropped += c.Requests
+				continue
+			}
+			s.pending[key] = c
+			continue
+		}
+		cur.Requests += c.Requests
+		cur.ClientErrors += c.ClientErrors
+		cur.ServerErrors += c.ServerErrors
+		cur.BytesIn += c.BytesIn
+		cur.BytesOut += c.BytesOut
+	}
+}
+
+// Close menghentikan flush berkala lalu menulis sisa counter. Dipanggil
+// saat shutdown.
+func (s *UsageService) Close(ctx context.Context) error {
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+	<-s.done
+	return s.Flush(ctx)
+}
+
+// UsageParams adalah filter GET /admin/usage. From dan To berupa tanggal
+// WIB YYYY-MM-DD (inklusif); default 7 hari terakhir.
+type UsageParams struct {
+	From     string
+	To       string
+	Consumer string
+	// Jumlah consumer teratas; 0 = semua
+	Limit int
+}
+
+// Report menjumlahkan pemakaian per consumer pada rentang tanggal, urut
+// jumlah request terbanyak. Counter instance ini yang belum ditulis ikut
+// di-flush dulu.
+func (s *UsageService) Report(ctx context.Context, p UsageParams) (*models.UsageReport, error) {
+	from, to, err := p.dates()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Flush(ctx); err != nil {
+		log.Println("simpan pemakaian API:", err)
+	}
+	totals, err := s.repo.Totals(ctx, from, to, p.Consumer)
+	if err != nil {
+		return nil, err
+	}
+	byConsumer := map[string]*models.UsageConsumer{}
+	consumers := []*models.UsageConsumer{}
+	for _, t := range totals {
+		c, ok := byConsumer[t.Consumer]
+		if !ok {
+			c = &models.UsageConsumer{Consumer: t.Consumer, ConsumerType: t.ConsumerType, Endpoints: []models.UsageEndpoint{}}
+			byConsumer[t.Consumer] = c
+			consumers = append(consumers, c)
+		}
+		c.Requests += t.Requests
+		c.ClientErrors += t.ClientErrors
+		c.ServerErrors += t.ServerErrors
+		c.BytesIn += t.BytesIn
+		c.BytesOut += t.BytesOut
+		c.Endpoints = append(c.Endpoints, models.UsageEndpoint{Method: t.Method, Route: t.Route, Requests: t.Requests,
+			ClientErrors: t.ClientErrors, ServerErrors: t.ServerErrors, BytesIn: t.BytesIn, BytesOut: t.BytesOut})
+	}
+	report := &models.UsageReport{From: from, To: to, Consumers: make([]models.UsageConsumer, 0, len(consumers))}
+	for _, c := range consumers {
+		if c.Requests > 0 {
+			c.ErrorRate = float64(c.ClientErrors+c.ServerErrors) / float64(c.Requests)
+		}
+		slices.SortFunc(c.Endpoints, func(a, b models.UsageEndpoint) int {
+			return cmp.Or(cmp.Compare(b.Requests, a.Requests), cmp.Compare(a.Route, b.Route), cmp.Compare(a.Method, b.Method))
+		})
+		report.Consumers = append(report.Consumers, *c)
+	}
+	slices.SortFunc(report.Consumers, func(a, b models.UsageConsumer) int {
+		return cmp.Or(cmp.Compare(b.Requests, a.Requests), cmp.Compare(a.Consumer, b.Consumer))
+	})
+	if p.Limit > 0 && len(report.Consumers) > p.Limit {
+		report.Consumers = report.Consumers[:p.Limit]
+	}
+	return report, nil
+}
+
+var usageCSVHeader = []string{"consumer", "consumer_type", "method", "route", "requests", "client_errors", "server_errors",
+	"error_rate", "bytes_in", "bytes_out"}
+
+// ExportCSV menulis laporan yang sama dengan Report, satu baris per
+// consumer dan endpoint.
+func (s *UsageService) ExportCSV(ctx context.Context, p UsageParams, w io.Writer) error {
+	report, err := s.Report(ctx, p)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(usageCSVHeader); err != nil {
+		return err
+	}
+	for _, c := range report.Consumers {
+		for _, e := range c.Endpoints {
+			rate := 0.0
+			if e.Requests > 0 {
+				rate = float64(e.ClientErrors+e.ServerErrors) / float64(e.Requests)
+			}
+			record := []string{csvCell(c.Consumer), c.ConsumerType, e.Method, e.Route, strconv.FormatInt(e.Requests, 10),
+				strconv.FormatInt(e.ClientErrors, 10), strconv.FormatInt(e.ServerErrors, 10),
+				strconv.FormatFloat(rate, 'f', 4, 64), strconv.FormatInt(e.BytesIn, 10), strconv.FormatInt(e.BytesOut, 10)}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (p UsageParams) dates() (string, string, error) {
+	var v validator
+	today := time.Now().In(usageZone)
+	to, from := today, today.AddDate(0, 0, 1-defaultUsageDays)
+	if p.To != "" {
+		t, err := time.ParseInLocation("2006-01-02", p.To, usageZone)
+		v.check(err == nil, "to", RuleType, Params{"type": "date (YYYY-MM-DD)"})
+		to = t
+		if p.From == "" {
+			from = to.AddDate(0, 0, 1-defaultUsageDays)
+		}
+	}
+	if p.From != "" {
+		t, err := time.ParseInLocation("2006-01-02", p.From, usageZone)
+		v.check(err == nil, "from", RuleType, Params{"type": "date (YYYY-MM-DD)"})
+		from = t
+	}
+	if err := v.err(); err != nil {
+		return "", "", err
+	}
+	v.check(!to.Before(from), "to", RuleAfter, Params{"field": "from"})
+	v.check(to.Sub(from) < maxUsageDays*24*time.Hour, "to", RuleRange, Params{"max_days": maxUsageDays})
+	if err := v.err(); err != nil {
+		return "", "", err
+	}
+	return from.Format("2006-01-02"), to.Format("2006-01-02"), nil
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+	"InfoCuy-Backend/internal/units"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type UserService struct {
+	users repositories.UserRepository
+	roles *RoleService
+	audit *AuditService
+	cache *UserCache
+	// Selama database read-only preferensi diantrekan
+	database *DatabaseMode
+}
+
+// NewUserService membuat service; cache (boleh nil) adalah cache yang sama
+// dengan AuthService dan di-invalidate setiap kali user diubah.
+func NewUserService(users repositories.UserRepository, roles *RoleService, audit *AuditService, cache *UserCache, database *DatabaseMode) *UserService {
+	return &UserService{users: users, roles: roles, audit: audit, cache: cache, database: database}
+}
+
+func (s *UserService) List(ctx context.Context) ([]models.User, error) {
+	return s.users.List(ctx)
+}
+
+// SetRole mengembalikan data user sebelum diubah (untuk audit).
+func (s *UserService) SetRole(ctx context.Context, id primitive.ObjectID, role string) (*models.User, error) {
+	if !s.roles.Exists(ctx, role) {
+		return nil, invalid("Role %s tidak terdaftar", role)
+	}
+	before, err := s.users.SetRole(ctx, id, role)
+	s.cache.Invalidate(id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	after := *before
+	after.Role = role
+	s.audit.Record(ctx, AuditEvent{Action: "user.role_change", ResourceType: AuditUser, ResourceID: id.Hex(), Before: before, After: after})
+	return before, nil
+}
+
+// Delete mengembalikan data user yang dihapus (untuk audit).
+func (s *UserService) Delete(ctx context.Context, id primitive.ObjectID) (*models.User, error) {
+	deleted, err := s.users.Delete(ctx, id)
+	s.cache.Invalidate(id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "user.delete", ResourceType: AuditUser, ResourceID: id.Hex(), Before: deleted})
+	return deleted, nil
+}
+
+// UpdatePreferences mengganti preferensi user. alerts yang tidak dikirim
+// berarti berhenti berlangganan; units boleh kosong jika alerts dikirim
+// (nilai lama dipertahankan). queued true jika database sedang read-only
+// dan perubahan baru disimpan setelah database pulih.
+func (s *UserService) UpdatePreferences(ctx context.Context, u models.User, in models.Preferences) (prefs models.Preferences, queued bool, err error) {
+	if in.Units == "" && in.Alerts != nil {
+		in.Units = u.Preferences.Units
+		if in.Units == "" {
+			in.Units = string(units.Metric)
+		}
+	}
+	sys, ok := units.Parse(in.Units)
+	if !ok {
+		return in, false, invalid("units harus metric atau imperial")
+	}
+	in.Units = string(sys)
+	if a := in.Alerts; a != nil {
+		var v validator
+		v.check(a.FreshnessBelow >= 0 && a.FreshnessBelow < 1, "alerts.freshness_below", RuleRange, Params{"min": 0, "max": 1})
+		if err := v.err(); err != nil {
+			return in, false, err
+		}
+		if a.FreshnessBelow == 0 {
+			in.Alerts = nil
+		}
+	}
+	// Preferensi lengkap ditimpa, jadi aman diantrekan dan diterapkan nanti
+	if s.database.ReadOnly() {
+		err := s.database.Queue(ctx, "preferences:"+u.ID.Hex(), func(ctx context.Context) error {
+			return s.savePreferences(ctx, u, in)
+		})
+		return in, err == nil, err
+	}
+	return in, false, s.savePreferences(ctx, u, in)
+}
+
+func (s *UserService) savePreferences(ctx context.Context, u models.User, in models.Preferences) error {
+	err := s.users.Update(ctx, u.ID, repositories.Fields{"preferences.units": in.Units, "preferences.alerts": in.Alerts})
+	s.cache.Invalidate(u.ID)
+	if err != nil {
+		return err
+	}
+	s.audit.Record(ctx, AuditEvent{Action: "user.preferences", ResourceType: AuditUser, ResourceID: u.ID.Hex(),
+		Before: u.Preferences, After: in})
+	return nil
+}
+
+// SetLocationQuota mengatur override kuota; nil menghapus override.
+func (s *UserService) SetLocationQuota(ctx context.Context, id primitive.ObjectID, quota *int) error {
+	if quota != nil && *quota < 0 {
+		return invalid("Kuota tidak boleh negatif")
+	}
+	before, err := s.users.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	err = s.users.SetLocationQuota(ctx, id, quota)
+	s.cache.Invalidate(id)
+	if err != nil {
+		return err
+	}
+	after := *before
+	after.LocationQuota = quota
+	s.audit.Record(ctx, AuditEvent{Action: "user.quota", ResourceType: AuditUser, ResourceID: id.Hex(), Before: before, After: after})
+	return nil
+}
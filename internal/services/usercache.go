@@ -0,0 +1,106 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// User hasil autentikasi di-cache sebentar supaya setiap request
+// ter-autentikasi tidak perlu round trip ke Mongo. Perubahan lewat instance
+// ini langsung menghapus entry-nya; instance lain paling lama tertinggal
+// selama TTL.
+const (
+	userCacheTTL = 10 * time.Second
+	userCacheMax = 10000
+	// Entry kedaluwarsa masih dipakai selama ini jika MongoDB tidak bisa
+	// dijangkau (failover), supaya user yang baru aktif tetap bisa membaca
+	userCacheStale = 15 * time.Minute
+)
+
+type cachedUser struct {
+	user    models.User
+	expires time.Time
+}
+
+// UserCache adalah cache read-through user per ID untuk middleware auth.
+// Nilai nil berarti tanpa cache.
+type UserCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[primitive.ObjectID]cachedUser
+}
+
+func NewUserCache(ttl time.Duration) *UserCache {
+	if ttl <= 0 {
+		ttl = userCacheTTL
+	}
+	return &UserCache{ttl: ttl, entries: make(map[primitive.ObjectID]cachedUser)}
+}
+
+// get mengembalikan salinan user jika masih berlaku.
+func (c *UserCache) get(id primitive.ObjectID) (*models.User, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[id]
+	if !ok || time.Now().After(e.expires) {
+		return nil, false
+	}
+	u := e.user
+	return &u, true
+}
+
+// stale mengembalikan salinan user walau sudah kedaluwarsa, selama belum
+// lewat userCacheStale. Hanya untuk saat database tidak tersedia.
+func (c *UserCache) stale(id primitive.ObjectID) (*models.User, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[id]
+	if !ok || time.Now().After(e.expires.Add(userCacheStale)) {
+		return nil, false
+	}
+	u := e.user
+	return &u, true
+}
+
+func (c *UserCache) put(u *models.User) {
+	if c == nil || u == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if len(c.entries) >= userCacheMax {
+		for id, e := range c.entries {
+			if now.After(e.expires.Add(userCacheStale)) {
+				delete(c.entries, id)
+			}
+		}
+		// Masih penuh: mulai dari kosong daripada tumbuh tanpa batas
+		if len(c.entries) >= userCacheMax {
+			clear(c.entries)
+		}
+	}
+	c.entries[u.ID] = cachedUser{user: *u, expires: now.Add(c.ttl)}
+}
+
+// Invalidate menghapus user dari cache; dipanggil setiap kali data user
+// (role, password, lockout, preferensi, kuota) berubah atau user dihapus.
+func (c *UserCache) Invalidate(id primitive.ObjectID) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	delete(c.entries, id)
+	c.mu.Unlock()
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+
+	"InfoCuy-Backend/internal/auth"
+	"InfoCuy-Backend/internal/models"
+	"InfoCuy-Backend/internal/repositories"
+)
+
+// Batas panjang field teks lokasi
+const (
+	maxLocationNameLen    = 200
+	maxLocationAddressLen = 500
+	maxEmailLen           = 254
+)
+
+// Rule validasi pada FieldError.Rule. Client memakai rule dan params untuk
+// pesan sendiri; Message sudah diterjemahkan sesuai Accept-Language.
+const (
+	RuleRequired       = "required"
+	RuleMinLength      = "min_length"
+	RuleMaxLength      = "max_length"
+	RuleRange          = "range"
+	RuleNotNegative    = "not_negative"
+	RuleOneOf          = "one_of"
+	RuleEmail          = "email"
+	RuleType           = "type"
+	RuleAfter          = "after"
+	RuleNotEqual       = "not_equal"
+	RuleRequireOne     = "require_one"
+	RuleTemplate       = "template"
+	RuleImage          = "image"
+	RulePostalCode     = "postal_code"
+	RulePostalMismatch = "postal_code_mismatch"
+	RulePostalUnknown  = "postal_code_unknown"
+	RuleNotFound       = "not_found"
+	RuleURL            = "url"
+	RuleCursor         = "cursor"
+)
+
+// Pesan per rule dan bahasa; {nama} diganti dengan params[nama]
+var ruleMessages = map[string]models.Labels{
+	RuleRequired:       {"id": "wajib diisi", "en": "is required"},
+	RuleMinLength:      {"id": "minimal {min} karakter", "en": "must be at least {min} characters"},
+	RuleMaxLength:      {"id": "maksimal {max} karakter", "en": "must be at most {max} characters"},
+	RuleRange:          {"id": "harus di antara {min} dan {max}", "en": "must be between {min} and {max}"},
+	RuleNotNegative:    {"id": "tidak boleh negatif", "en": "must not be negative"},
+	RuleOneOf:          {"id": "hanya boleh: {allowed}", "en": "must be one of: {allowed}"},
+	RuleEmail:          {"id": "format email tidak valid", "en": "is not a valid email address"},
+	RuleType:           {"id": "harus bertipe {type}", "en": "must be of type {type}"},
+	RuleAfter:          {"id": "harus setelah {field}", "en": "must be after {field}"},
+	RuleNotEqual:       {"id": "sama dengan {field}", "en": "must differ from {field}"},
+	RuleRequireOne:     {"id": "isi minimal satu dari {fields}", "en": "fill in at least one of {fields}"},
+	RuleTemplate:       {"id": "template tidak valid: {error}", "en": "invalid template: {error}"},
+	RuleImage:          {"id": "harus berupa gambar {formats}", "en": "must be a {formats} image"},
+	RulePostalCode:     {"id": "harus 5 digit angka", "en": "must be 5 digits"},
+	RulePostalMismatch: {"id": "berbeda dengan kode pos di address ({address})", "en": "differs from the postal code in address ({address})"},
+	RulePostalUnknown:  {"id": "kode pos tidak terdaftar", "en": "is not a registered postal code"},
+	RuleNotFound:       {"id": "tidak ditemukan", "en": "was not found"},
+	RuleURL:            {"id": "harus URL http(s) yang valid", "en": "must be a valid http(s) URL"},
+	RuleCursor:         {"id": "tidak valid atau berasal dari urutan lain", "en": "is invalid or belongs to a different sort"},
+}
+
+// Params adalah nilai yang disisipkan ke pesan rule, mis. {"max": 200}.
+type Params map[string]interface{}
+
+// fieldError membuat FieldError dengan pesan bahasa default.
+func fieldError(field, rule string, params Params) FieldError {
+	return FieldError{Field: field, Rule: rule, Params: params}.Localize(models.DefaultLanguage)
+}
+
+// Localize mengisi Message sesuai lang dari Rule dan Params.
+func (e FieldError) Localize(lang string) FieldError {
+	msg, ok := ruleMessages[e.Rule]
+	if !ok {
+		return e
+	}
+	text := msg.Resolve(lang)
+	for k, v := range e.Params {
+		s := fmt.Sprint(v)
+		if list, ok := v.([]string); ok {
+			s = strings.Join(list, ", ")
+		}
+		text = strings.ReplaceAll(text, "{"+k+"}", s)
+	}
+	e.Message = text
+	return e
+}
+
+// validator mengumpulkan semua kesalahan field supaya client bisa
+// memperbaiki sekaligus, bukan satu per satu.
+type validator struct {
+	fields []FieldError
+}
+
+// check mencatat pelanggaran rule pada field jika ok false.
+func (v *validator) check(ok bool, field, rule string, params Params) {
+	if !ok {
+		v.fields = append(v.fields, fieldError(field, rule, params))
+	}
+}
+
+func (v *validator) required(ok bool, field string) {
+	v.check(ok, field, RuleRequired, nil)
+}
+
+func (v *validator) maxLength(field, s string, max int) {
+	v.check(utf8.RuneCountInString(s) <= max, field, RuleMaxLength, Params{"max": max})
+}
+
+func (v *validator) email(field, email string) {
+	switch {
+	case email == "":
+		v.required(false, field)
+	case len(email) > maxEmailLen || !validEmail(email):
+		v.check(false, field, RuleEmail, nil)
+	}
+}
+
+func (v *validator) password(field, password string) {
+	v.check(utf8.RuneCountInString(password) >= auth.MinPasswordLength, field, RuleMinLength, Params{"min": auth.MinPasswordLength})
+}
+
+// err mengembalikan *ValidationError jika ada kesalahan, nil jika tidak.
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Message: "Data tidak valid", Fields: v.fields}
+}
+
+// validEmail menerima alamat polos (tanpa nama tampilan) dengan domain
+// yang memiliki titik, mis. budi@example.com.
+func validEmail(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return false
+	}
+	at := strings.LastIndex(email, "@")
+	domain := email[at+1:]
+	return strings.Contains(domain, ".") && !strings.HasPrefix(domain, ".") && !strings.HasSuffix(domain, ".")
+}
+
+// validateLocation memeriksa field wajib & jangkauan koordinat.
+// hasCoordinates false berarti payload tidak mengirim coordinates sama sekali.
+func validateLocation(loc models.Location, hasCoordinates bool) error {
+	var v validator
+	name := strings.TrimSpace(loc.Name)
+	v.required(name != "", "name")
+	v.maxLength("name", name, maxLocationNameLen)
+	address := strings.TrimSpace(loc.Address)
+	v.required(address != "", "address")
+	v.maxLength("address", address, maxLocationAddressLen)
+	if !hasCoordinates {
+		v.required(false, "coordinates")
+	} else {
+		c := loc.Coordinates
+		v.check(c.Lat >= -90 && c.Lat <= 90, "coordinates.lat", RuleRange, Params{"min": -90, "max": 90})
+		v.check(c.Lng >= -180 && c.Lng <= 180, "coordinates.lng", RuleRange, Params{"min": -180, "max": 180})
+	}
+	if a := loc.Accessibility; a != nil {
+		v.access("accessibility.wheelchair", a.Wheelchair)
+		v.access("accessibility.accessible_toilet", a.AccessibleToilet)
+		v.access("accessibility.accessible_parking", a.AccessibleParking)
+	}
+	if loc.PriceRange != "" {
+		v.oneOf("price_range", loc.PriceRange, models.PriceRanges)
+	}
+	for i, m := range loc.PaymentMethods {
+		v.oneOf(fmt.Sprintf("payment_methods.%d", i), m, models.PaymentMethods)
+	}
+	return v.err()
+}
+
+func (v *validator) oneOf(field, value string, allowed []string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.check(false, field, RuleOneOf, Params{"allowed": allowed})
+}
+
+// normalizeList menyeragamkan daftar nilai enum: huruf kecil, tanpa spasi
+// di tepi, tanpa duplikat (urutan pertama dipertahankan).
+func normalizeList(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	seen := map[string]bool{}
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if v != "" && !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func (v *validator) access(field, value string) {
+	switch value {
+	case "", models.AccessYes, models.AccessLimited, models.AccessNo:
+	default:
+		v.check(false, field, RuleOneOf, Params{"allowed": []string{models.AccessYes, models.AccessLimited, models.AccessNo}})
+	}
+}
+
+// AttributeParams adalah filter atribut dari query string, masing-masing
+// berupa daftar dipisah koma.
+type AttributeParams struct {
+	Accessible string // mis. wheelchair,toilet
+	PriceRange string // mis. budget,moderate
+	Payment    string // mis. qris
+}
+
+// filter memvalidasi parameter lalu mengubahnya menjadi filter repository.
+func (p AttributeParams) filter() (repositories.AttributeFilter, error) {
+	var f repositories.AttributeFilter
+	var v validator
+	for _, name := range normalizeList(strings.Split(p.Accessible, ",")) {
+		field, ok := models.AccessibilityFeatures[name]
+		v.check(ok, "accessible", RuleOneOf, Params{"allowed": []string{"parking", "toilet", "wheelchair"}})
+		f.Accessible = append(f.Accessible, field)
+	}
+	f.PriceRanges = normalizeList(strings.Split(p.PriceRange, ","))
+	for _, r := range f.PriceRanges {
+		v.oneOf("price_range", r, models.PriceRanges)
+	}
+	f.PaymentMethods = normalizeList(strings.Split(p.Payment, ","))
+	for _, m := range f.PaymentMethods {
+		v.oneOf("payment", m, models.PaymentMethods)
+	}
+	return f, v.err()
+}
+
+// DecodeError mengubah error decode JSON menjadi *ValidationError dengan
+// format yang sama seperti validasi field.
+func DecodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	var sizeErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return &ValidationError{Message: "Data tidak valid", Fields: []FieldError{
+			fieldError(typeErr.Field, RuleType, Params{"type": jsonTypeName(typeErr.Type.Kind().String())}),
+		}}
+	case errors.As(err, &syntaxErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return &ValidationError{Message: "Body harus berupa JSON yang valid"}
+	case errors.As(err, &sizeErr):
+		return &ValidationError{Message: fmt.Sprintf("Body maksimal %d byte", sizeErr.Limit)}
+	}
+	return &ValidationError{Message: "Body tidak valid"}
+}
+
+// Nama tipe Go -> nama tipe JSON untuk pesan error
+func jsonTypeName(kind string) string {
+	switch kind {
+	case "string":
+		return "string"
+	case "bool":
+		return "boolean"
+	case "slice", "array":
+		return "array"
+	case "map", "struct", "ptr":
+		return "object"
+	}
+	return "number"
+}
+
+// listPage memeriksa ?page, ?limit, ?sort dan ?cursor terhadap spec. Sort
+// di luar whitelist atau cursor yang rusak menjadi error validasi.
+func listPage(spec repositories.ListSpec, o models.ListOptions) (repositories.ListPage, error) {
+	p, err := spec.Resolve(o)
+	var listErr *repositories.ListError
+	if !errors.As(err, &listErr) {
+		return p, err
+	}
+	var v validator
+	if listErr.Param == "sort" {
+		v.check(false, "sort", RuleOneOf, Params{"allowed": listErr.Allowed})
+	} else {
+		v.check(false, listErr.Param, RuleCursor, nil)
+	}
+	return p, v.err()
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"InfoCuy-Backend/internal/repositories"
+	"InfoCuy-Backend/internal/weather"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Weather mengembalikan cuaca terkini di koordinat lokasi id.
+func (s *LocationService) Weather(ctx context.Context, id primitive.ObjectID) (*weather.Conditions, error) {
+	if s.opts.Weather == nil {
+		return nil, invalid("Provider cuaca belum dikonfigurasi (WEATHER_PROVIDER)")
+	}
+	loc, err := s.locations.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrLocationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.opts.Weather.Current(ctx, loc.Coordinates)
+}
@@ -0,0 +1,286 @@
+// Package shadow mencerminkan sebagian traffic baca (GET) produksi ke
+// service kedua, mis. hasil refactor lapisan repository, lalu mencatat
+// perbedaan response-nya. User tetap hanya menerima response dari service
+// utama; request bayangan dikirim setelah response selesai, lewat antrian
+// dengan worker terbatas, sehingga shadow yang lambat atau mati tidak
+// memengaruhi latensi.
+//
+// Konfigurasi lewat environment:
+//
+//	SHADOW_TARGET          base URL service bayangan, mis. https://infocuy-next.onrender.com (kosong = nonaktif)
+//	SHADOW_SAMPLE_PERCENT  persentase request GET yang dicerminkan, 0-100 (1)
+//	SHADOW_IGNORE_FIELDS   field JSON yang tidak dibandingkan, dipisah koma (request_id)
+//
+// Karena request bayangan dikirim sedikit setelah request asli, data yang
+// baru berubah di antaranya bisa tercatat sebagai perbedaan. Timeout,
+// retry dan circuit breaker mengikuti OUTBOUND_SHADOW_* (lihat httpclient).
+package shadow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/httpclient"
+	"InfoCuy-Backend/internal/metrics"
+)
+
+// MaxBody adalah ukuran response maksimum yang dibandingkan; response
+// lebih besar hanya dibandingkan status-nya.
+const MaxBody = 1 << 20
+
+// Jumlah perbedaan yang dicatat per request
+const maxDiffs = 5
+
+// Header menandai request yang dikirim Mirror. Service yang menerimanya
+// tidak menambah penghitung (impression promosi, open campaign, statistik
+// pemakaian) supaya data yang dipakai bersama tidak terhitung dua kali.
+const Header = "X-Shadow-Request"
+
+var requestsTotal = metrics.NewCounterVec("shadow_requests_total",
+	"Request yang dicerminkan ke service bayangan per hasil (match, diff, error, dropped)", "outcome")
+
+// Header yang ikut diteruskan; sisanya (cookie, If-None-Match, dst.) tidak
+// dikirim supaya response bisa dibandingkan apa adanya
+var forwardHeaders = []string{"Authorization", "Accept", "Accept-Language", "X-Request-ID", "X-API-Version"}
+
+// Request adalah request GET yang sudah dilayani service utama beserta
+// response-nya.
+type Request struct {
+	Method    string
+	Path      string // path + query string
+	Header    http.Header
+	Status    int
+	Body      []byte
+	Truncated bool // Body dipotong di MaxBody
+}
+
+// Mirror mengirim Request ke service bayangan dan membandingkan hasilnya.
+type Mirror struct {
+	target  string
+	percent float64
+	ignore  map[string]bool
+	client  *httpclient.Client
+	queue   chan Request
+}
+
+// NewFromEnv membuat Mirror dari env. Jika SHADOW_TARGET kosong Mirror
+// tetap valid tetapi Sample selalu false.
+func NewFromEnv() *Mirror {
+	m := &Mirror{percent: 1, ignore: map[string]bool{"request_id": true}}
+	if v := os.Getenv("SHADOW_IGNORE_FIELDS"); v != "" {
+		m.ignore = map[string]bool{}
+		for _, f := range strings.Split(v, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				m.ignore[f] = true
+			}
+		}
+	}
+	if v := os.Getenv("SHADOW_SAMPLE_PERCENT"); v != "" {
+		p, err := strconv.ParseFloat(v, 64)
+		if err != nil || p < 0 || p > 100 {
+			log.Println("Warning: SHADOW_SAMPLE_PERCENT harus 0-100, shadow dinonaktifkan")
+			return m
+		}
+		m.percent = p
+	}
+	target := strings.TrimRight(os.Getenv("SHADOW_TARGET"), "/")
+	if target == "" {
+		return m
+	}
+	if u, err := url.Parse(target); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		log.Println("Warning: SHADOW_TARGET harus URL http(s), shadow dinonaktifkan")
+		return m
+	}
+	m.target = target
+	m.client = httpclient.New("shadow", httpclient.Options{Timeout: 10 * time.Second})
+	m.queue = make(chan Request, 64)
+	// Dua worker cukup untuk sampel kecil; sisanya dibuang (dropped)
+	for range 2 {
+		go m.loop()
+	}
+	return m
+}
+
+// Enabled bernilai true jika SHADOW_TARGET dikonfigurasi.
+func (m *Mirror) Enabled() bool {
+	return m != nil && m.target != "" && m.percent > 0
+}
+
+// FromMirror bernilai true jika r dikirim oleh Mirror (lihat Header).
+func FromMirror(r *http.Request) bool {
+	return r.Header.Get(Header) != ""
+}
+
+// Sample memutuskan apakah request ini ikut dicerminkan.
+func (m *Mirror) Sample() bool {
+	return m.Enabled() && rand.Float64()*100 < m.percent
+}
+
+// Submit mengantrikan request untuk dicerminkan. Jika antrian penuh
+// request dibuang daripada memblokir handler.
+func (m *Mirror) Submit(req Request) {
+	if !m.Enabled() {
+		return
+	}
+	select {
+	case m.queue <- req:
+	default:
+		requestsTotal.Inc("dropped")
+	}
+}
+
+func (m *Mirror) loop() {
+	for req := range m.queue {
+		m.replay(req)
+	}
+}
+
+func (m *Mirror) replay(req Request) {
+	httpReq, err := http.NewRequest(req.Method, m.target+req.Path, nil)
+	if err != nil {
+		requestsTotal.Inc("error")
+		return
+	}
+	for _, k := range forwardHeaders {
+		if v := req.Header.Get(k); v != "" {
+			httpReq.Header.Set(k, v)
+		}
+	}
+	// Penanda supaya service bayangan bisa membedakan traffic ini
+	httpReq.Header.Set(Header, "1")
+	start := time.Now()
+	resp, err := m.client.Do(httpReq)
+	if err != nil {
+		requestsTotal.Inc("error")
+		slog.Warn("shadow request gagal", "path", req.Path, "error", err)
+		return
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxBody+1))
+	if err != nil {
+		requestsTotal.Inc("error")
+		slog.Warn("shadow request gagal", "path", req.Path, "error", err)
+		return
+	}
+	diffs := m.Compare(req.Status, req.Body, resp.StatusCode, body, req.Truncated || len(body) > MaxBody)
+	if len(diffs) == 0 {
+		requestsTotal.Inc("match")
+		return
+	}
+	requestsTotal.Inc("diff")
+	slog.Warn("shadow diff",
+		"request_id", req.Header.Get("X-Request-ID"),
+		"method", req.Method,
+		"path", req.Path,
+		"status", req.Status,
+		"shadow_status", resp.StatusCode,
+		"shadow_latency_ms", float64(time.Since(start).Microseconds())/1000,
+		"diffs", diffs,
+	)
+}
+
+// Compare mengembalikan daftar perbedaan (maksimal 5) antara response utama
+// dan bayangan, mis. "status: 200 != 500" atau "data[3].name: "A" != "B"".
+// Body JSON dibandingkan per field tanpa memedulikan urutan key; body lain
+// dibandingkan byte per byte. Jika truncated hanya status yang dibandingkan.
+func (m *Mirror) Compare(status int, body []byte, shadowStatus int, shadowBody []byte, truncated bool) []string {
+	if status != shadowStatus {
+		return []string{fmt.Sprintf("status: %d != %d", status, shadowStatus)}
+	}
+	if truncated {
+		return nil
+	}
+	var a, b any
+	if json.Unmarshal(body, &a) != nil || json.Unmarshal(shadowBody, &b) != nil {
+		if !bytes.Equal(body, shadowBody) {
+			return []string{fmt.Sprintf("body: %d byte != %d byte", len(body), len(shadowBody))}
+		}
+		return nil
+	}
+	var diffs []string
+	m.diff("", a, b, &diffs)
+	return diffs
+}
+
+func (m *Mirror) diff(path string, a, b any, diffs *[]string) {
+	if len(*diffs) >= maxDiffs {
+		return
+	}
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(av)+len(bv))
+		for k := range av {
+			keys = append(keys, k)
+		}
+		for k := range bv {
+			if _, ok := av[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if m.ignore[k] {
+				continue
+			}
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			m.diff(p, av[k], bv[k], diffs)
+		}
+		return
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			break
+		}
+		if len(av) != len(bv) {
+			*diffs = append(*diffs, fmt.Sprintf("%s: %d item != %d item", orRoot(path), len(av), len(bv)))
+			return
+		}
+		for i := range av {
+			m.diff(fmt.Sprintf("%s[%d]", path, i), av[i], bv[i], diffs)
+		}
+		return
+	}
+	if !reflect.DeepEqual(a, b) {
+		*diffs = append(*diffs, fmt.Sprintf("%s: %s != %s", orRoot(path), short(a), short(b)))
+	}
+}
+
+func orRoot(path string) string {
+	if path == "" {
+		return "$"
+	}
+	return path
+}
+
+// short merender nilai JSON untuk log, dipotong supaya satu baris log
+// tidak membawa seluruh dokumen
+func short(v any) string {
+	if v == nil {
+		return "(tidak ada)"
+	}
+	b, _ := json.Marshal(v)
+	if len(b) > 80 {
+		return string(b[:77]) + "..."
+	}
+	return string(b)
+}
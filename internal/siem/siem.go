@@ -0,0 +1,236 @@
+// Package siem meneruskan event keamanan (login, perubahan role,
+// penghapusan data) ke SIEM kampus lewat syslog atau HTTP, dalam format
+// CEF atau JSON.
+//
+// Konfigurasi lewat environment:
+//
+//	SIEM_TARGET      syslog+udp://host:514, syslog+tcp://host:601, atau https://collector/path
+//	SIEM_FORMAT      cef (default) | json
+//	SIEM_HTTP_TOKEN  bearer token opsional untuk target HTTP
+package siem
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/httpclient"
+)
+
+// Event adalah satu kejadian yang relevan untuk keamanan.
+type Event struct {
+	Time     time.Time         `json:"time"`
+	Type     string            `json:"type"`     // mis. auth.login.failure
+	Severity int               `json:"severity"` // 0-10 (skala CEF)
+	Actor    string            `json:"actor,omitempty"`
+	Target   string            `json:"target,omitempty"`
+	SourceIP string            `json:"source_ip,omitempty"`
+	Outcome  string            `json:"outcome"` // success | failure
+	Details  map[string]string `json:"details,omitempty"`
+}
+
+type sender interface {
+	send(payload []byte, ev Event) error
+}
+
+// Exporter mengirim event secara asinkron lewat buffer channel supaya
+// request handler tidak menunggu SIEM.
+type Exporter struct {
+	format string
+	sender sender
+	events chan Event
+}
+
+// NewFromEnv membuat exporter dari env. Jika SIEM_TARGET kosong exporter
+// tetap valid tetapi Emit tidak melakukan apa pun.
+func NewFromEnv() *Exporter {
+	e := &Exporter{format: strings.ToLower(os.Getenv("SIEM_FORMAT"))}
+	if e.format != "json" {
+		e.format = "cef"
+	}
+	target := os.Getenv("SIEM_TARGET")
+	if target == "" {
+		return e
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		log.Println("Warning: SIEM_TARGET tidak valid:", err)
+		return e
+	}
+	switch u.Scheme {
+	case "syslog+udp", "syslog+tcp", "syslog":
+		network := strings.TrimPrefix(u.Scheme, "syslog+")
+		if network == "syslog" {
+			network = "udp"
+		}
+		e.sender = &syslogSender{network: network, addr: u.Host}
+	case "http", "https":
+		e.sender = &httpSender{url: target, token: os.Getenv("SIEM_HTTP_TOKEN"), format: e.format,
+			client: httpclient.New("siem", httpclient.Options{Timeout: 5 * time.Second})}
+	default:
+		log.Println("Warning: skema SIEM_TARGET tidak didukung:", u.Scheme)
+		return e
+	}
+	e.events = make(chan Event, 256)
+	go e.loop()
+	return e
+}
+
+// Enabled bernilai true jika SIEM_TARGET dikonfigurasi.
+func (e *Exporter) Enabled() bool {
+	return e != nil && e.sender != nil
+}
+
+// Emit mengantrikan event. Jika buffer penuh event dibuang (dengan log)
+// daripada memblokir request.
+func (e *Exporter) Emit(ev Event) {
+	if e.sender == nil {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now().UTC()
+	}
+	select {
+	case e.events <- ev:
+	default:
+		log.Println("siem: buffer penuh, event dibuang:", ev.Type)
+	}
+}
+
+func (e *Exporter) loop() {
+	for ev := range e.events {
+		var payload []byte
+		if e.format == "json" {
+			payload, _ = json.Marshal(ev)
+		} else {
+			payload = []byte(FormatCEF(ev))
+		}
+		if err := e.sender.send(payload, ev); err != nil {
+			log.Println("siem:", err)
+		}
+	}
+}
+
+// FormatCEF merender event dalam format ArcSight Common Event Format.
+func FormatCEF(ev Event) string {
+	ext := []string{
+		"rt=" + fmt.Sprint(ev.Time.UnixMilli()),
+		"outcome=" + cefExt(ev.Outcome),
+	}
+	if ev.Actor != "" {
+		ext = append(ext, "suser="+cefExt(ev.Actor))
+	}
+	if ev.Target != "" {
+		ext = append(ext, "duser="+cefExt(ev.Target))
+	}
+	if ev.SourceIP != "" {
+		ext = append(ext, "src="+cefExt(ev.SourceIP))
+	}
+	keys := make([]string, 0, len(ev.Details))
+	for k := range ev.Details {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		if i >= 6 { // CEF hanya menyediakan cs1..cs6
+			break
+		}
+		ext = append(ext, fmt.Sprintf("cs%dLabel=%s cs%d=%s", i+1, cefExt(k), i+1, cefExt(ev.Details[k])))
+	}
+	return fmt.Sprintf("CEF:0|InfoCuy|InfoCuy-Backend|1.0|%s|%s|%d|%s",
+		cefHeader(ev.Type), cefHeader(ev.Type), ev.Severity, strings.Join(ext, " "))
+}
+
+func cefHeader(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `|`, `\|`).Replace(s)
+}
+
+func cefExt(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`).Replace(s)
+}
+
+// --- SYSLOG (RFC 5424) ---
+
+type syslogSender struct {
+	network string
+	addr    string
+}
+
+func (s *syslogSender) send(payload []byte, ev Event) error {
+	host, _ := os.Hostname()
+	if host == "" {
+		host = "-"
+	}
+	// Facility authpriv (10); severity syslog diturunkan dari skala CEF
+	pri := 10*8 + syslogSeverity(ev.Severity)
+	msg := fmt.Sprintf("<%d>1 %s %s infocuy - %s - %s", pri, ev.Time.UTC().Format(time.RFC3339),
+		host, strings.ReplaceAll(ev.Type, " ", "_"), payload)
+	conn, err := net.DialTimeout(s.network, s.addr, 5*time.Second)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	if s.network == "tcp" {
+		// Octet counting framing (RFC 6587)
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+	_, err = conn.Write([]byte(msg))
+	return err
+}
+
+func syslogSeverity(cef int) int {
+	switch {
+	case cef >= 9:
+		return 2 // critical
+	case cef >= 7:
+		return 3 // error
+	case cef >= 5:
+		return 4 // warning
+	case cef >= 3:
+		return 5 // notice
+	default:
+		return 6 // informational
+	}
+}
+
+// --- HTTP ---
+
+type httpSender struct {
+	url    string
+	token  string
+	format string
+	client *httpclient.Client
+}
+
+func (h *httpSender) send(payload []byte, _ Event) error {
+	req, err := http.NewRequest(http.MethodPost, h.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	if h.format == "json" {
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req.Header.Set("Content-Type", "text/plain")
+	}
+	if h.token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.token)
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("collector membalas %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Package units mengonversi jarak (disimpan dalam meter) ke sistem satuan
+// yang diminta client: metric (m/km) atau imperial (ft/mi).
+package units
+
+import (
+	"math"
+	"strings"
+)
+
+type System string
+
+const (
+	Metric   System = "metric"
+	Imperial System = "imperial"
+)
+
+const (
+	metersPerFoot = 0.3048
+	metersPerMile = 1609.344
+)
+
+// Parse mengembalikan System yang valid; ok=false jika string kosong/tidak dikenal.
+func Parse(s string) (System, bool) {
+	switch System(strings.ToLower(strings.TrimSpace(s))) {
+	case Metric:
+		return Metric, true
+	case Imperial:
+		return Imperial, true
+	}
+	return "", false
+}
+
+// Distance adalah jarak yang sudah dikonversi untuk response API.
+type Distance struct {
+	Value  float64 `json:"value"`
+	Unit   string  `json:"unit"`
+	Meters float64 `json:"meters"`
+}
+
+// FromMeters mengonversi jarak ke unit yang paling terbaca di sistem tersebut.
+func FromMeters(meters float64, sys System) Distance {
+	d := Distance{Meters: round(meters, 1)}
+	if sys == Imperial {
+		if miles := meters / metersPerMile; miles >= 0.1 {
+			d.Value, d.Unit = round(miles, 2), "mi"
+		} else {
+			d.Value, d.Unit = round(meters/metersPerFoot, 0), "ft"
+		}
+		return d
+	}
+	if meters >= 1000 {
+		d.Value, d.Unit = round(meters/1000, 2), "km"
+	} else {
+		d.Value, d.Unit = round(meters, 0), "m"
+	}
+	return d
+}
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
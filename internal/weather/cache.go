@@ -0,0 +1,89 @@
+package weather
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+const (
+	// Batas jumlah sel di cache; entry kedaluwarsa dibuang saat batas tercapai
+	maxCacheEntries = 10000
+	// Jika provider gagal, data yang sudah kedaluwarsa masih dipakai selama
+	// umurnya (sejak FetchedAt) belum melewati batas ini
+	maxStaleAge = time.Hour
+)
+
+type cellKey struct {
+	lat, lng int64
+}
+
+type cacheEntry struct {
+	cond      Conditions
+	expiresAt time.Time
+}
+
+type cached struct {
+	Provider
+	gridDeg float64
+	ttl     time.Duration
+
+	mu      sync.Mutex
+	entries map[cellKey]cacheEntry
+}
+
+// WithCache membungkus provider sehingga koordinat dalam satu sel grid
+// (gridDeg x gridDeg derajat) memakai hasil yang sama selama ttl. Cuaca
+// diambil untuk titik tengah sel supaya hasilnya tidak bergantung pada
+// lokasi mana yang pertama diminta. Saat provider gagal (termasuk rate
+// limit atau circuit terbuka), entry kedaluwarsa masih dipakai sampai
+// maxStaleAge.
+func WithCache(p Provider, gridDeg float64, ttl time.Duration) Provider {
+	return &cached{Provider: p, gridDeg: gridDeg, ttl: ttl, entries: map[cellKey]cacheEntry{}}
+}
+
+func (c *cached) Current(ctx context.Context, coord models.Coordinates) (*Conditions, error) {
+	key := cellKey{
+		lat: int64(math.Floor(coord.Lat / c.gridDeg)),
+		lng: int64(math.Floor(coord.Lng / c.gridDeg)),
+	}
+	now := time.Now()
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		cond := entry.cond
+		return &cond, nil
+	}
+
+	center := models.Coordinates{
+		Lat: (float64(key.lat) + 0.5) * c.gridDeg,
+		Lng: (float64(key.lng) + 0.5) * c.gridDeg,
+	}
+	cond, err := c.Provider.Current(ctx, center)
+	if err != nil {
+		// Fallback: data lama (FetchedAt menunjukkan umurnya) lebih berguna daripada error
+		if ok && now.Sub(entry.cond.FetchedAt) < maxStaleAge {
+			stale := entry.cond
+			return &stale, nil
+		}
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if len(c.entries) >= maxCacheEntries {
+		for k, e := range c.entries {
+			if now.After(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	if len(c.entries) < maxCacheEntries {
+		c.entries[key] = cacheEntry{cond: *cond, expiresAt: now.Add(c.ttl)}
+	}
+	c.mu.Unlock()
+	return cond, nil
+}
@@ -0,0 +1,96 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/httpclient"
+	"InfoCuy-Backend/internal/models"
+)
+
+type openWeather struct {
+	baseURL string
+	apiKey  string
+	lang    string
+	client  *httpclient.Client
+}
+
+func NewOpenWeather(baseURL, apiKey, lang string) Provider {
+	if baseURL == "" {
+		baseURL = "https://api.openweathermap.org"
+	}
+	if lang == "" {
+		lang = "id"
+	}
+	return &openWeather{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		lang:    lang,
+		client:  httpclient.New("openweather", httpclient.Options{Timeout: 5 * time.Second, Retries: 1}),
+	}
+}
+
+// GET /data/2.5/weather?lat=..&lon=..&units=metric
+func (o *openWeather) Current(ctx context.Context, c models.Coordinates) (*Conditions, error) {
+	q := url.Values{}
+	q.Set("lat", strconv.FormatFloat(c.Lat, 'f', 6, 64))
+	q.Set("lon", strconv.FormatFloat(c.Lng, 'f', 6, 64))
+	q.Set("units", "metric")
+	q.Set("lang", o.lang)
+	q.Set("appid", o.apiKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.baseURL+"/data/2.5/weather?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, ErrRateLimited
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openweather: status %d", resp.StatusCode)
+	}
+	var out struct {
+		Weather []struct {
+			Main        string `json:"main"`
+			Description string `json:"description"`
+			Icon        string `json:"icon"`
+		} `json:"weather"`
+		Main struct {
+			Temp      float64 `json:"temp"`
+			FeelsLike float64 `json:"feels_like"`
+			Humidity  int     `json:"humidity"`
+		} `json:"main"`
+		Wind struct {
+			Speed float64 `json:"speed"`
+		} `json:"wind"`
+		Dt int64 `json:"dt"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	cond := &Conditions{
+		Provider:    "openweather",
+		TempC:       out.Main.Temp,
+		FeelsLikeC:  out.Main.FeelsLike,
+		Humidity:    out.Main.Humidity,
+		WindSpeedMS: out.Wind.Speed,
+		ObservedAt:  time.Unix(out.Dt, 0).UTC(),
+		FetchedAt:   time.Now().UTC(),
+	}
+	if len(out.Weather) > 0 {
+		cond.Summary = out.Weather[0].Main
+		cond.Description = out.Weather[0].Description
+		cond.Icon = out.Weather[0].Icon
+	}
+	return cond, nil
+}
@@ -0,0 +1,48 @@
+package weather
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+type rateLimited struct {
+	Provider
+	perMin int
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// WithRateLimit membungkus provider dengan token bucket: paling banyak
+// perMin panggilan per menit (burst sampai perMin). Jika token habis,
+// Current langsung mengembalikan ErrRateLimited tanpa menunggu.
+func WithRateLimit(p Provider, perMin int) Provider {
+	return &rateLimited{Provider: p, perMin: perMin, tokens: float64(perMin), last: time.Now()}
+}
+
+func (r *rateLimited) take() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	r.tokens += now.Sub(r.last).Minutes() * float64(r.perMin)
+	if r.tokens > float64(r.perMin) {
+		r.tokens = float64(r.perMin)
+	}
+	r.last = now
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+func (r *rateLimited) Current(ctx context.Context, c models.Coordinates) (*Conditions, error) {
+	if !r.take() {
+		return nil, ErrRateLimited
+	}
+	return r.Provider.Current(ctx, c)
+}
@@ -0,0 +1,91 @@
+// Package weather mengambil cuaca terkini di sebuah koordinat untuk
+// halaman detail lokasi. Provider bawaan memakai OpenWeather. Hasil di-cache
+// per sel grid (lokasi yang berdekatan berbagi satu panggilan) dan panggilan
+// ke provider dibatasi supaya kuota API tidak habis.
+//
+// Konfigurasi lewat environment:
+//
+//	WEATHER_PROVIDER        openweather (kosong = nonaktif)
+//	OPENWEATHER_API_KEY     wajib untuk openweather
+//	OPENWEATHER_URL         base URL, default https://api.openweathermap.org
+//	WEATHER_LANG            bahasa deskripsi, default id
+//	WEATHER_CACHE_TTL       lama cache, default 10m
+//	WEATHER_GRID_DEG        ukuran sel cache dalam derajat, default 0.05 (~5 km)
+//	WEATHER_RATE_PER_MIN    panggilan provider per menit, default 50
+package weather
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"InfoCuy-Backend/internal/models"
+)
+
+// Conditions adalah cuaca terkini di satu titik.
+type Conditions struct {
+	Provider    string    `json:"provider"`
+	Summary     string    `json:"summary"`
+	Description string    `json:"description"`
+	Icon        string    `json:"icon,omitempty"`
+	TempC       float64   `json:"temp_c"`
+	FeelsLikeC  float64   `json:"feels_like_c"`
+	Humidity    int       `json:"humidity"`
+	WindSpeedMS float64   `json:"wind_speed_ms"`
+	ObservedAt  time.Time `json:"observed_at"`
+	// Waktu data diambil dari provider (bisa lebih lama dari request karena cache)
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// Provider mengembalikan cuaca terkini untuk sebuah koordinat.
+type Provider interface {
+	Current(ctx context.Context, c models.Coordinates) (*Conditions, error)
+}
+
+// ErrRateLimited dikembalikan jika batas panggilan ke provider sudah
+// tercapai dan data belum ada di cache.
+var ErrRateLimited = errors.New("weather: batas panggilan provider tercapai")
+
+const (
+	defaultCacheTTL   = 10 * time.Minute
+	defaultGridDeg    = 0.05
+	defaultRatePerMin = 50
+)
+
+// NewFromEnv mengembalikan nil jika cuaca tidak dikonfigurasi.
+func NewFromEnv() Provider {
+	var p Provider
+	switch strings.ToLower(os.Getenv("WEATHER_PROVIDER")) {
+	case "":
+		return nil
+	case "openweather":
+		key := os.Getenv("OPENWEATHER_API_KEY")
+		if key == "" {
+			log.Println("Warning: OPENWEATHER_API_KEY kosong, cuaca dinonaktifkan")
+			return nil
+		}
+		p = NewOpenWeather(os.Getenv("OPENWEATHER_URL"), key, os.Getenv("WEATHER_LANG"))
+	default:
+		log.Println("Warning: WEATHER_PROVIDER tidak didukung:", os.Getenv("WEATHER_PROVIDER"))
+		return nil
+	}
+
+	ttl := defaultCacheTTL
+	if v, err := time.ParseDuration(os.Getenv("WEATHER_CACHE_TTL")); err == nil && v > 0 {
+		ttl = v
+	}
+	grid := defaultGridDeg
+	if v, err := strconv.ParseFloat(os.Getenv("WEATHER_GRID_DEG"), 64); err == nil && v > 0 {
+		grid = v
+	}
+	rate := defaultRatePerMin
+	if v, err := strconv.Atoi(os.Getenv("WEATHER_RATE_PER_MIN")); err == nil && v > 0 {
+		rate = v
+	}
+	// Urutan penting: cache di luar supaya cache hit tidak memakai kuota
+	return WithCache(WithRateLimit(p, rate), grid, ttl)
+}
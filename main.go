@@ -1,31 +1,77 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	// Import package dari folder api
 	// SESUAIKAN "InfoCuy-Backend" DENGAN NAMA MODULE DI go.mod KAMU
-	"InfoCuy-Backend/api" 
-
-	"github.com/joho/godotenv"
+	"InfoCuy-Backend/api"
+	"InfoCuy-Backend/internal/config"
 )
 
 func main() {
-	// Load .env di local
-	err := godotenv.Load()
+	// Environment + .env di local; berhenti jika ada nilai wajib yang kosong
+	cfg, err := config.Load()
 	if err != nil {
-		fmt.Println("Info: .env not found")
+		log.Fatal(err)
+	}
+	// Rakit aplikasi dari package api (handler)
+	app, err := handler.New(cfg)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	// Panggil Router dari package api (handler)
-	r := handler.SetupRouter()
-
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	srv := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           app.Router,
+		ReadHeaderTimeout: 10 * time.Second,
 	}
+	// Stream SSE tidak pernah selesai sendiri dan akan menahan Shutdown
+	srv.RegisterOnShutdown(app.StopStreams)
+
+	// SIGTERM dikirim Render/Docker saat restart, SIGINT saat Ctrl+C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	fmt.Println("🚀 Server running on port " + port)
-	r.Run(":" + port)
-}
\ No newline at end of file
+	// SIGHUP memuat ulang config runtime tanpa restart (lihat api.App.Reload)
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	go func() {
+		for range hup {
+			if _, _, err := app.Reload(); err != nil {
+				slog.Error("reload config gagal", "error", err)
+			}
+		}
+	}()
+
+	go func() {
+		slog.Info("server running", "port", cfg.Port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	slog.Info("menghentikan server, menunggu request yang berjalan")
+
+	// Render memberi 30 detik sebelum proses di-kill, default SHUTDOWN_TIMEOUT 25s
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Println("Warning: server tidak berhenti dengan bersih:", err)
+	}
+	if err := app.Shutdown(shutdownCtx); err != nil {
+		log.Println("Warning: gagal menutup koneksi MongoDB:", err)
+	}
+	slog.Info("server berhenti")
+}